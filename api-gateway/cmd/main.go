@@ -2,7 +2,8 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,40 +12,188 @@ import (
 
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/metrics"
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/middleware"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/ratelimit"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/router"
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
-	}
+	envErr := godotenv.Load()
 
 	conf := config.Load()
 
+	logging.Setup(logging.Config{
+		Service: "api-gateway",
+		Version: version,
+		Level:   conf.LogLevel,
+		Format:  conf.LogFormat,
+	})
+	if envErr != nil {
+		slog.Warn(".env file not found", slog.Any("error", envErr))
+	}
+
 	if conf.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.New()
+	if err := r.SetTrustedProxies(conf.TrustedProxies); err != nil {
+		fatal("Invalid TRUSTED_PROXIES", err)
+	}
+
+	routeTable, err := initializeRouteTable(conf)
+	if err != nil {
+		fatal("Failed to initialize route table", err)
+	}
+
+	upstreams := resilience.NewUpstreams()
+	metrics.RegisterUpstreams(upstreams)
+
+	authHandler, err := initializeAuthHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize auth handler", err)
+	}
+	defer authHandler.Close()
+
+	addressHandler, err := initializeAddressHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize address handler", err)
+	}
+	defer addressHandler.Close()
+
+	sellerHandler, err := initializeSellerHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize seller handler", err)
+	}
+	defer sellerHandler.Close()
+
+	productHandler, err := initializeProductHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize product handler", err)
+	}
+	defer productHandler.Close()
+
+	inventoryHandler, err := initializeInventoryHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize inventory handler", err)
+	}
+	defer inventoryHandler.Close()
+
+	cartHandler, err := initializeCartHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize cart handler", err)
+	}
+	defer cartHandler.Close()
+
+	orderHandler, err := initializeOrderHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize order handler", err)
+	}
+	defer orderHandler.Close()
+
+	checkoutHandler, err := initializeCheckoutHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize checkout handler", err)
+	}
+	defer checkoutHandler.Close()
+
+	paymentHandler, err := initializePaymentHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize payment handler", err)
+	}
+	defer paymentHandler.Close()
+
+	ledgerHandler, err := initializeLedgerHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize ledger handler", err)
+	}
+	defer ledgerHandler.Close()
+
+	promotionsHandler, err := initializePromotionsHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize promotions handler", err)
+	}
+	defer promotionsHandler.Close()
+
+	taxHandler, err := initializeTaxHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize tax handler", err)
+	}
+	defer taxHandler.Close()
+
+	shippingHandler, err := initializeShippingHandler(conf, upstreams)
+	if err != nil {
+		fatal("Failed to initialize shipping handler", err)
+	}
+	defer shippingHandler.Close()
+
+	proxyHandler, err := handler.NewProxyHandler(routeTable, upstreams)
+	if err != nil {
+		fatal("Failed to initialize proxy handler", err)
+	}
+	defer proxyHandler.Close()
+
+	limiter, err := initializeRateLimiter(conf)
+	if err != nil {
+		fatal("Failed to initialize rate limiter", err)
+	}
+	defer limiter.Close()
+
+	reloadRoutes := make(chan os.Signal, 1)
+	if conf.RoutesFile != "" {
+		signal.Notify(reloadRoutes, syscall.SIGHUP)
+
+		watchCtx, stopWatching := context.WithCancel(context.Background())
+		defer stopWatching()
+		go proxyHandler.Watch(watchCtx, conf.RoutesFile, conf.RoutesReloadInterval, reloadRoutes)
+	}
+
+	// Not reached yet: auth-service answers Login with Unimplemented, so no
+	// login succeeds and guest carts are only merged once it does.
+	authHandler.OnLogin(cartHandler.MergeGuestCart)
 
 	r.Use(
+		middleware.RequestID(),
 		middleware.Logger(),
+		middleware.Metrics(),
 		middleware.CORS(),
 		gin.Recovery(),
+		// Everything after this serves the request with one version of the
+		// route table: its rate limits, upstream policies and routes.
+		proxyHandler.Pin,
+		middleware.RateLimit(limiter, authHandler),
 	)
 
-	authHandler, err := initializeAuthHandler(conf)
-	if err != nil {
-		log.Fatalf("Fialed to initialize auth handler: %v", err)
-	}
-	defer authHandler.Close()
-
 	healthHandler := handler.NewHealthHandler()
 
-	router.SetupRoutes(r, authHandler, healthHandler)
+	err = router.SetupRoutes(r, &router.Handlers{
+		Auth:       authHandler,
+		Health:     healthHandler,
+		Address:    addressHandler,
+		Seller:     sellerHandler,
+		Product:    productHandler,
+		Inventory:  inventoryHandler,
+		Cart:       cartHandler,
+		Order:      orderHandler,
+		Checkout:   checkoutHandler,
+		Payment:    paymentHandler,
+		Ledger:     ledgerHandler,
+		Promotions: promotionsHandler,
+		Tax:        taxHandler,
+		Shipping:   shippingHandler,
+		Proxy:      proxyHandler,
+	})
+	if err != nil {
+		fatal("Failed to set up routes", err)
+	}
 
 	srv := &http.Server{
 		Addr:         getServerAddress(conf.Port),
@@ -54,10 +203,19 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	adminSrv := newAdminServer(conf.AdminPort)
+
 	go func() {
-		log.Printf("API Gatyeway starting on %s", srv.Addr)
+		slog.Info("API Gateway starting", slog.String("addr", srv.Addr))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server : %v", err)
+			fatal("Failed to start server", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("Admin server starting", slog.String("addr", adminSrv.Addr))
+		if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to start admin server", err)
 		}
 	}()
 
@@ -65,26 +223,217 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdonw:", err)
+		fatal("Server forced to shut down", err)
 	}
+	adminSrv.Shutdown(ctx)
 
-	log.Println("Server exited successfully")
+	slog.Info("Server exited successfully")
 }
 
-func initializeAuthHandler(conf *config.Config) (*handler.AuthHandler, error) {
+// newAdminServer serves the operator endpoints on their own port, which is
+// not published outside the container network, so that the metrics are not
+// served to API clients.
+func newAdminServer(port string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:        getServerAddress(port),
+		Handler:     mux,
+		ReadTimeout: 15 * time.Second,
+		IdleTimeout: 60 * time.Second,
+	}
+}
+
+// fatal logs err and exits, for failures the gateway cannot run with.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
+
+func initializeAuthHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.AuthHandler, error) {
 	authServiceURL := conf.AuthServiceURL
 
 	if authServiceURL == "" {
 		authServiceURL = "localhost:8081"
 	}
 
-	return handler.NewAuthHandler(authServiceURL)
+	return handler.NewAuthHandler(authServiceURL, metrics.DialOption("auth"), upstreams.DialOption("auth"))
+}
+
+func initializeAddressHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.AddressHandler, error) {
+	customerServiceURL := conf.CustomerServiceURL
+
+	if customerServiceURL == "" {
+		customerServiceURL = "localhost:8085"
+	}
+
+	return handler.NewAddressHandler(customerServiceURL, metrics.DialOption("customer"), upstreams.DialOption("customer"))
+}
+
+func initializeSellerHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.SellerHandler, error) {
+	sellerServiceURL := conf.SellerServiceURL
+
+	if sellerServiceURL == "" {
+		sellerServiceURL = "localhost:8086"
+	}
+
+	return handler.NewSellerHandler(sellerServiceURL, metrics.DialOption("seller"), upstreams.DialOption("seller"))
+}
+
+func initializeProductHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.ProductHandler, error) {
+	productServiceURL := conf.ProductServiceURL
+
+	if productServiceURL == "" {
+		productServiceURL = "localhost:8082"
+	}
+
+	return handler.NewProductHandler(productServiceURL, metrics.DialOption("product"), upstreams.DialOption("product"))
+}
+
+func initializeInventoryHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.InventoryHandler, error) {
+	inventoryServiceURL := conf.InventoryServiceURL
+
+	if inventoryServiceURL == "" {
+		inventoryServiceURL = "localhost:8087"
+	}
+
+	return handler.NewInventoryHandler(inventoryServiceURL, metrics.DialOption("inventory"), upstreams.DialOption("inventory"))
+}
+
+func initializeCartHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.CartHandler, error) {
+	cartServiceURL := conf.CartServiceURL
+
+	if cartServiceURL == "" {
+		cartServiceURL = "localhost:8088"
+	}
+
+	cookieSecret := conf.CartCookieSecret
+	if cookieSecret == "" {
+		cookieSecret = conf.JWTSecret
+	}
+	if cookieSecret == "" {
+		return nil, fmt.Errorf("CART_COOKIE_SECRET or JWT_SECRET must be set")
+	}
+
+	return handler.NewCartHandler(cartServiceURL, cookieSecret, conf.Environment == "production", metrics.DialOption("cart"), upstreams.DialOption("cart"))
+}
+
+func initializeOrderHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.OrderHandler, error) {
+	orderServiceURL := conf.OrderServiceURL
+
+	if orderServiceURL == "" {
+		orderServiceURL = "localhost:8083"
+	}
+
+	return handler.NewOrderHandler(orderServiceURL, metrics.DialOption("order"), upstreams.DialOption("order"))
+}
+
+func initializeCheckoutHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.CheckoutHandler, error) {
+	checkoutServiceURL := conf.CheckoutServiceURL
+
+	if checkoutServiceURL == "" {
+		checkoutServiceURL = "localhost:8089"
+	}
+
+	return handler.NewCheckoutHandler(checkoutServiceURL, metrics.DialOption("checkout"), upstreams.DialOption("checkout"))
+}
+
+func initializePaymentHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.PaymentHandler, error) {
+	paymentServiceURL := conf.PaymentServiceURL
+
+	if paymentServiceURL == "" {
+		paymentServiceURL = "localhost:8090"
+	}
+
+	return handler.NewPaymentHandler(paymentServiceURL, metrics.DialOption("payment"), upstreams.DialOption("payment"))
+}
+
+func initializeLedgerHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.LedgerHandler, error) {
+	ledgerServiceURL := conf.LedgerServiceURL
+
+	if ledgerServiceURL == "" {
+		ledgerServiceURL = "localhost:8091"
+	}
+
+	return handler.NewLedgerHandler(ledgerServiceURL, metrics.DialOption("ledger"), upstreams.DialOption("ledger"))
+}
+
+func initializePromotionsHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.PromotionsHandler, error) {
+	promotionsServiceURL := conf.PromotionsServiceURL
+
+	if promotionsServiceURL == "" {
+		promotionsServiceURL = "localhost:8092"
+	}
+
+	return handler.NewPromotionsHandler(promotionsServiceURL, metrics.DialOption("promotions"), upstreams.DialOption("promotions"))
+}
+
+func initializeTaxHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.TaxHandler, error) {
+	taxServiceURL := conf.TaxServiceURL
+
+	if taxServiceURL == "" {
+		taxServiceURL = "localhost:8093"
+	}
+
+	return handler.NewTaxHandler(taxServiceURL, metrics.DialOption("tax"), upstreams.DialOption("tax"))
+}
+
+func initializeShippingHandler(conf *config.Config, upstreams *resilience.Upstreams) (*handler.ShippingHandler, error) {
+	shippingServiceURL := conf.ShippingServiceURL
+
+	if shippingServiceURL == "" {
+		shippingServiceURL = "localhost:8094"
+	}
+
+	return handler.NewShippingHandler(shippingServiceURL, metrics.DialOption("shipping"), upstreams.DialOption("shipping"))
+}
+
+func initializeRouteTable(conf *config.Config) (*config.RouteTable, error) {
+	if conf.RoutesFile == "" {
+		return &config.RouteTable{}, nil
+	}
+
+	table, err := config.LoadRouteTable(conf.RoutesFile)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Loaded route table",
+		slog.String("file", conf.RoutesFile),
+		slog.Int("routes", len(table.Routes)),
+		slog.Int("rate_limits", len(table.RateLimits)),
+		slog.Int("policies", len(table.Policies)),
+		slog.String("routes_version", table.Version),
+	)
+
+	return table, nil
+}
+
+func initializeRateLimiter(conf *config.Config) (*ratelimit.Limiter, error) {
+	switch conf.RateLimitBackend {
+	case "memory":
+		return ratelimit.NewLimiter(ratelimit.NewMemoryStore()), nil
+	case "redis":
+		store := ratelimit.NewRedisStore(conf.RateLimitRedisAddr, conf.RateLimitRedisPassword)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Limits are not enforced while the server is down, so the gateway
+		// still starts.
+		if err := store.Ping(ctx); err != nil {
+			slog.Warn("Rate limit store unavailable", slog.String("addr", conf.RateLimitRedisAddr), slog.Any("error", err))
+		}
+
+		return ratelimit.NewLimiter(store), nil
+	default:
+		return nil, fmt.Errorf("RATE_LIMIT_BACKEND must be memory or redis, not %q", conf.RateLimitBackend)
+	}
 }
 
 func getServerAddress(port string) string {
@@ -4,17 +4,36 @@ go 1.24.1
 
 // api-gateway/go.mod
 require (
+	github.com/Dzaakk/micro-commerce/pkg v0.0.0
 	github.com/Dzaakk/micro-commerce/services/auth-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/cart-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/checkout-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/customer-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/inventory-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/ledger-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/order-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/payment-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/product-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/promotions-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/seller-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/shipping-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/tax-service v0.0.0
+	github.com/alicebob/miniredis/v2 v2.39.0
 	github.com/gin-gonic/gin v1.9.1
 	github.com/joho/godotenv v1.5.1
+	github.com/prometheus/client_golang v1.23.2
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7
 	google.golang.org/grpc v1.75.0
-	google.golang.org/protobuf v1.36.8 // indirect
+	google.golang.org/protobuf v1.36.8
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require go-micro.dev/v4 v4.11.0 // indirect
 
 require (
+	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/bytedance/sonic v1.9.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/chenzhuoyu/base64x v0.0.0-20221115062448-fe3a3abad311 // indirect
 	github.com/evanphx/json-patch/v5 v5.5.0 // indirect
 	github.com/felixge/httpsnoop v1.0.1 // indirect
@@ -39,21 +58,51 @@ require (
 	github.com/miekg/dns v1.1.43 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/oxtoacart/bpool v0.0.0-20190530202638-03653db5a59c // indirect
 	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
 	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
+	github.com/prometheus/client_model v0.6.2 // indirect
+	github.com/prometheus/common v0.66.1 // indirect
+	github.com/prometheus/procfs v0.16.1 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	github.com/ugorji/go/codec v1.2.11 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.yaml.in/yaml/v2 v2.4.2 // indirect
 	golang.org/x/arch v0.3.0 // indirect
 	golang.org/x/crypto v0.41.0 // indirect
-	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/net v0.43.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
+replace github.com/Dzaakk/micro-commerce/pkg => ../pkg
+
 replace github.com/Dzaakk/micro-commerce/services/auth-service => ../services/auth-service
+
+replace github.com/Dzaakk/micro-commerce/services/cart-service => ../services/cart-service
+
+replace github.com/Dzaakk/micro-commerce/services/checkout-service => ../services/checkout-service
+
+replace github.com/Dzaakk/micro-commerce/services/customer-service => ../services/customer-service
+
+replace github.com/Dzaakk/micro-commerce/services/inventory-service => ../services/inventory-service
+
+replace github.com/Dzaakk/micro-commerce/services/ledger-service => ../services/ledger-service
+
+replace github.com/Dzaakk/micro-commerce/services/order-service => ../services/order-service
+
+replace github.com/Dzaakk/micro-commerce/services/payment-service => ../services/payment-service
+
+replace github.com/Dzaakk/micro-commerce/services/product-service => ../services/product-service
+
+replace github.com/Dzaakk/micro-commerce/services/promotions-service => ../services/promotions-service
+
+replace github.com/Dzaakk/micro-commerce/services/seller-service => ../services/seller-service
+
+replace github.com/Dzaakk/micro-commerce/services/shipping-service => ../services/shipping-service
+
+replace github.com/Dzaakk/micro-commerce/services/tax-service => ../services/tax-service
@@ -2,32 +2,98 @@
 package config
 
 import (
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port              string
-	Environment       string
-	AuthServiceURL    string
-	UserServiceURL    string
-	ProductServiceURL string
-	OrderServiceURL   string
-	JWTSecret         string
+	Port                 string
+	Environment          string
+	AuthServiceURL       string
+	ProductServiceURL    string
+	OrderServiceURL      string
+	CustomerServiceURL   string
+	SellerServiceURL     string
+	InventoryServiceURL  string
+	CartServiceURL       string
+	CartCookieSecret     string
+	CheckoutServiceURL   string
+	PaymentServiceURL    string
+	LedgerServiceURL     string
+	PromotionsServiceURL string
+	TaxServiceURL        string
+	ShippingServiceURL   string
+	JWTSecret            string
+
+	// RoutesFile is the route table served for paths the gateway's own
+	// handlers do not know; empty serves none.
+	RoutesFile string
+	// RoutesReloadInterval is how often RoutesFile is checked for changes;
+	// 0 only reloads it on SIGHUP.
+	RoutesReloadInterval time.Duration
+
+	// RateLimitBackend is where the rate limits of the route table are
+	// counted: memory, per gateway instance, or redis, shared by all of
+	// them through the server at RateLimitRedisAddr.
+	RateLimitBackend       string
+	RateLimitRedisAddr     string
+	RateLimitRedisPassword string
+
+	// TrustedProxies are the addresses or CIDR ranges of the proxies in
+	// front of the gateway, whose X-Forwarded-For and X-Real-IP headers give
+	// the client IP that rate limits and logs use. With none, the default,
+	// the client IP is the address of the connection, so clients cannot
+	// choose their own.
+	TrustedProxies []string
+
+	// AdminPort serves the operator endpoints, such as the metrics, apart
+	// from the API, on a port that is not published.
+	AdminPort string
+
+	// LogLevel is the initial level, changeable at runtime through the admin
+	// API; LogFormat is json or text.
+	LogLevel  string
+	LogFormat string
 }
 
 func Load() *Config {
 	godotenv.Load()
 
 	return &Config{
-		Port:              os.Getenv("PORT"),
-		Environment:       os.Getenv("ENVIRONMENT"),
-		AuthServiceURL:    os.Getenv("AUTH_SERVICE_URL"),
-		UserServiceURL:    os.Getenv("USER_SERVICE_URL"),
-		ProductServiceURL: os.Getenv("PRODUCT_SERVICE_URL"),
-		OrderServiceURL:   os.Getenv("ORDER_SERVICE_URL"),
-		JWTSecret:         os.Getenv("JWT_SECRET"),
+		Port:                 os.Getenv("PORT"),
+		Environment:          os.Getenv("ENVIRONMENT"),
+		AuthServiceURL:       os.Getenv("AUTH_SERVICE_URL"),
+		ProductServiceURL:    os.Getenv("PRODUCT_SERVICE_URL"),
+		OrderServiceURL:      os.Getenv("ORDER_SERVICE_URL"),
+		CustomerServiceURL:   os.Getenv("CUSTOMER_SERVICE_URL"),
+		SellerServiceURL:     os.Getenv("SELLER_SERVICE_URL"),
+		InventoryServiceURL:  os.Getenv("INVENTORY_SERVICE_URL"),
+		CartServiceURL:       os.Getenv("CART_SERVICE_URL"),
+		CartCookieSecret:     os.Getenv("CART_COOKIE_SECRET"),
+		CheckoutServiceURL:   os.Getenv("CHECKOUT_SERVICE_URL"),
+		PaymentServiceURL:    os.Getenv("PAYMENT_SERVICE_URL"),
+		LedgerServiceURL:     os.Getenv("LEDGER_SERVICE_URL"),
+		PromotionsServiceURL: os.Getenv("PROMOTIONS_SERVICE_URL"),
+		TaxServiceURL:        os.Getenv("TAX_SERVICE_URL"),
+		ShippingServiceURL:   os.Getenv("SHIPPING_SERVICE_URL"),
+		JWTSecret:            os.Getenv("JWT_SECRET"),
+		RoutesFile:           os.Getenv("ROUTES_FILE"),
+		RoutesReloadInterval: getDuration("ROUTES_RELOAD_INTERVAL", 5*time.Second),
+
+		RateLimitBackend:       getEnv("RATE_LIMIT_BACKEND", "memory"),
+		RateLimitRedisAddr:     getEnv("RATE_LIMIT_REDIS_ADDR", "localhost:6379"),
+		RateLimitRedisPassword: os.Getenv("RATE_LIMIT_REDIS_PASSWORD"),
+
+		TrustedProxies: getList("TRUSTED_PROXIES"),
+
+		AdminPort: getEnv("ADMIN_PORT", "9080"),
+
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		LogFormat: getEnv("LOG_FORMAT", "json"),
 	}
 }
 
@@ -37,3 +103,27 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getList reads a comma-separated list, leaving out empty entries.
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		slog.Warn("Invalid duration, using the default", slog.String("key", key), slog.String("value", value), slog.Duration("default", defaultValue))
+		return defaultValue
+	}
+	return duration
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultPolicy names the policy of upstreams that have none of their own.
+const DefaultPolicy = "default"
+
+// defaultTimeout bounds calls to upstreams whose policy sets no timeout.
+const defaultTimeout = 5 * time.Second
+
+// ServiceUpstreams are the services the gateway's own handlers call, by the
+// name their policies are under.
+var ServiceUpstreams = []string{
+	"auth", "customer", "seller", "product", "inventory", "cart", "order",
+	"checkout", "payment", "ledger", "promotions", "tax", "shipping",
+}
+
+// UpstreamPolicy is how calls to an upstream are made. Policies are named
+// after a service in ServiceUpstreams or an upstream of the route table; a
+// route table upstream named like a service shares its policy and circuit
+// breaker.
+//
+// Timeout bounds each attempt of calls the gateway gave no deadline of their
+// own, such as a checkout allowed longer than usual. Retry retries idempotent calls that failed.
+// CircuitBreaker stops calling an upstream that keeps failing. MaxConcurrent
+// caps the calls in flight, so that a slow upstream cannot hold every
+// request; 0 leaves them uncapped.
+type UpstreamPolicy struct {
+	Timeout        Duration              `yaml:"timeout" json:"timeout"`
+	Retry          *RetryPolicy          `yaml:"retry" json:"retry"`
+	CircuitBreaker *CircuitBreakerPolicy `yaml:"circuit_breaker" json:"circuit_breaker"`
+	MaxConcurrent  int                   `yaml:"max_concurrent" json:"max_concurrent"`
+}
+
+// RetryPolicy retries a call up to Attempts more times, waiting a random
+// time up to Backoff doubled for every retry, capped at MaxBackoff.
+//
+// Only idempotent calls are retried: HTTP GET, HEAD and OPTIONS requests,
+// gRPC methods whose name starts with Get, List, Lookup, Validate, Quote,
+// Calculate or Evaluate, and the full gRPC method names in Methods.
+type RetryPolicy struct {
+	Attempts   int      `yaml:"attempts" json:"attempts"`
+	Backoff    Duration `yaml:"backoff" json:"backoff"`
+	MaxBackoff Duration `yaml:"max_backoff" json:"max_backoff"`
+	Methods    []string `yaml:"methods" json:"methods"`
+}
+
+// CircuitBreakerPolicy opens the breaker after Failures calls in a row
+// failed, failing calls at once for OpenFor. It then lets Probes calls
+// through, closing again when they all succeed and opening when one fails.
+type CircuitBreakerPolicy struct {
+	Failures int      `yaml:"failures" json:"failures"`
+	OpenFor  Duration `yaml:"open_for" json:"open_for"`
+	Probes   int      `yaml:"probes" json:"probes"`
+}
+
+// AttemptTimeout is how long each attempt of a call may take.
+func (p *UpstreamPolicy) AttemptTimeout() time.Duration {
+	if p.Timeout > 0 {
+		return time.Duration(p.Timeout)
+	}
+	return defaultTimeout
+}
+
+// Policy returns the policy of upstream, the default policy when it has
+// none, or an empty policy when there is no default either or no table.
+func (t *RouteTable) Policy(upstream string) *UpstreamPolicy {
+	if t == nil {
+		return &UpstreamPolicy{}
+	}
+	if policy, ok := t.Policies[upstream]; ok {
+		return policy
+	}
+	if policy, ok := t.Policies[DefaultPolicy]; ok {
+		return policy
+	}
+	return &UpstreamPolicy{}
+}
+
+func (t *RouteTable) validatePolicies() []string {
+	var problems []string
+
+	known := map[string]bool{DefaultPolicy: true}
+	for _, name := range ServiceUpstreams {
+		known[name] = true
+	}
+	for name := range t.Upstreams {
+		known[name] = true
+	}
+
+	names := make([]string, 0, len(t.Policies))
+	for name := range t.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy := t.Policies[name]
+		label := "policy " + name
+		if !known[name] {
+			problems = append(problems, label+": is not a service, an upstream or default")
+		}
+		if policy == nil {
+			t.Policies[name] = &UpstreamPolicy{}
+			continue
+		}
+
+		if policy.Timeout < 0 {
+			problems = append(problems, label+": timeout must not be negative")
+		}
+		if policy.MaxConcurrent < 0 {
+			problems = append(problems, label+": max_concurrent must not be negative")
+		}
+
+		if retry := policy.Retry; retry != nil {
+			if retry.Attempts < 1 || retry.Attempts > 10 {
+				problems = append(problems, label+": retry attempts must be between 1 and 10")
+			}
+			if retry.Backoff == 0 {
+				retry.Backoff = Duration(50 * time.Millisecond)
+			}
+			if retry.MaxBackoff == 0 {
+				retry.MaxBackoff = Duration(time.Second)
+			}
+			if retry.Backoff < 0 || retry.MaxBackoff < retry.Backoff {
+				problems = append(problems, label+": retry backoff must be positive and at most max_backoff")
+			}
+			for _, method := range retry.Methods {
+				if !grpcMethodPattern.MatchString(method) {
+					problems = append(problems, fmt.Sprintf("%s: retry method %q must be a full method name such as /package.Service/Method", label, method))
+				}
+			}
+		}
+
+		if breaker := policy.CircuitBreaker; breaker != nil {
+			if breaker.Failures == 0 {
+				breaker.Failures = 5
+			}
+			if breaker.OpenFor == 0 {
+				breaker.OpenFor = Duration(30 * time.Second)
+			}
+			if breaker.Probes == 0 {
+				breaker.Probes = 1
+			}
+			if breaker.Failures < 0 || breaker.OpenFor < 0 || breaker.Probes < 0 {
+				problems = append(problems, label+": circuit_breaker values must be positive")
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rate limit algorithms.
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
+// What requests are counted by for a rate limit.
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyAPIKey = "api_key"
+	RateLimitKeyRoute  = "route"
+)
+
+// RateLimit allows Limit requests every Period for requests whose path
+// starts with PathPrefix, and whose method is one of Methods, or any when it
+// is empty. Unlike routes, rate limits also apply to the gateway's own
+// handlers, and every matching one is enforced.
+//
+// Requests are counted separately per client IP, user, API key (the
+// X-API-Key header) or, for route, all together. User and API key limits
+// count requests without a user or key by client IP.
+//
+// The token bucket allows bursts of up to Burst requests, Limit by default,
+// refilling at Limit per Period. The sliding window weighs the count of the
+// previous period by how much of it is still inside the window.
+type RateLimit struct {
+	Name       string   `yaml:"name" json:"name"`
+	PathPrefix string   `yaml:"path_prefix" json:"path_prefix"`
+	Methods    []string `yaml:"methods" json:"methods"`
+	Algorithm  string   `yaml:"algorithm" json:"algorithm"`
+	Key        string   `yaml:"key" json:"key"`
+	Limit      int      `yaml:"limit" json:"limit"`
+	Period     Duration `yaml:"period" json:"period"`
+	Burst      int      `yaml:"burst" json:"burst"`
+}
+
+// RateLimitsFor returns the rate limits of the table that apply to a request
+// with method for path; a nil table has none.
+func (t *RouteTable) RateLimitsFor(method, path string) []*RateLimit {
+	if t == nil {
+		return nil
+	}
+
+	var applies []*RateLimit
+	for _, limit := range t.RateLimits {
+		if limit.Applies(method, path) {
+			applies = append(applies, limit)
+		}
+	}
+	return applies
+}
+
+// Applies reports whether the rate limit counts a request with method for
+// path.
+func (l *RateLimit) Applies(method, path string) bool {
+	return matchesPath(l.PathPrefix, CleanPath(path)) && allowsMethod(l.Methods, method)
+}
+
+// Window is Period as a time.Duration.
+func (l *RateLimit) Window() time.Duration {
+	return time.Duration(l.Period)
+}
+
+// Capacity is the most requests the rate limit allows at once.
+func (l *RateLimit) Capacity() int {
+	if l.Algorithm == AlgorithmTokenBucket && l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Limit
+}
+
+func (t *RouteTable) validateRateLimits() []string {
+	var problems []string
+
+	seen := map[string]bool{}
+	for i, limit := range t.RateLimits {
+		if limit == nil {
+			problems = append(problems, fmt.Sprintf("rate_limits[%d]: is empty", i))
+			continue
+		}
+
+		label := fmt.Sprintf("rate_limits[%d]", i)
+		if limit.Name == "" {
+			problems = append(problems, label+": name is required")
+		} else {
+			label = "rate limit " + limit.Name
+			if seen[limit.Name] {
+				problems = append(problems, label+": name is used twice")
+			}
+			seen[limit.Name] = true
+		}
+
+		problems = append(problems, validateMatch(label, limit.PathPrefix, limit.Methods)...)
+
+		if limit.Algorithm == "" {
+			limit.Algorithm = AlgorithmSlidingWindow
+		}
+		switch limit.Algorithm {
+		case AlgorithmTokenBucket:
+		case AlgorithmSlidingWindow:
+			if limit.Burst != 0 {
+				problems = append(problems, label+": burst is only for token_bucket")
+			}
+		default:
+			problems = append(problems, label+": algorithm must be token_bucket or sliding_window")
+		}
+
+		if limit.Key == "" {
+			limit.Key = RateLimitKeyIP
+		}
+		switch limit.Key {
+		case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyAPIKey, RateLimitKeyRoute:
+		default:
+			problems = append(problems, label+": key must be ip, user, api_key or route")
+		}
+
+		if limit.Limit <= 0 {
+			problems = append(problems, label+": limit must be positive")
+		}
+		if limit.Window() < time.Millisecond {
+			problems = append(problems, label+": period must be at least 1ms")
+		}
+		if limit.Burst < 0 {
+			problems = append(problems, label+": burst must not be negative")
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,415 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Upstream protocols.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Authentication a route requires.
+const (
+	AuthNone     = "none"
+	AuthOptional = "optional"
+	AuthRequired = "required"
+)
+
+// grpcMethodPattern matches a full gRPC method name, e.g.
+// /product.ProductService/ListExchangeRates.
+var grpcMethodPattern = regexp.MustCompile(`^/[A-Za-z_][A-Za-z0-9_.]*/[A-Za-z_][A-Za-z0-9_]*$`)
+
+var routeMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+	http.MethodHead:   true,
+}
+
+// RouteTable is the gateway's route and policy file, read from ROUTES_FILE.
+// Its routes serve paths the gateway's own handlers do not, and may not
+// overlap them (see CheckReserved); its rate limits and upstream policies
+// apply to all requests.
+type RouteTable struct {
+	Upstreams  map[string]*Upstream `yaml:"upstreams" json:"upstreams"`
+	Routes     []*Route             `yaml:"routes" json:"routes"`
+	RateLimits []*RateLimit         `yaml:"rate_limits" json:"rate_limits"`
+
+	// Policies are how upstreams are called, by upstream name.
+	Policies map[string]*UpstreamPolicy `yaml:"policies" json:"policies"`
+
+	// Version identifies the file content the table was loaded from.
+	Version string `yaml:"-" json:"-"`
+}
+
+type tableKey struct{}
+
+// WithTable returns a copy of ctx carrying the route table a request is
+// served with, so that everything serving it uses the same version.
+func WithTable(ctx context.Context, table *RouteTable) context.Context {
+	return context.WithValue(ctx, tableKey{}, table)
+}
+
+// TableFrom returns the route table ctx carries, or nil when it has none.
+func TableFrom(ctx context.Context) *RouteTable {
+	table, _ := ctx.Value(tableKey{}).(*RouteTable)
+	return table
+}
+
+// Upstream is a service routes are sent to. URL is a base URL such as
+// http://search:8080 for HTTP and a host:port for gRPC. Environment
+// variables in it are expanded, with ${NAME:-default} falling back to
+// default when NAME is not set.
+type Upstream struct {
+	Protocol string `yaml:"protocol" json:"protocol"`
+	URL      string `yaml:"url" json:"url"`
+}
+
+// Route sends requests whose path starts with PathPrefix, and whose method
+// is one of Methods, or any when it is empty, to Upstream.
+//
+// For HTTP upstreams the path is forwarded, without PathPrefix when
+// StripPrefix is set and with Rewrite put in front. For gRPC upstreams the
+// query string, or the JSON body for methods that carry one, is the request
+// message for GRPCMethod, and UserIDField names the message field set to the
+// caller's user id.
+type Route struct {
+	Name        string            `yaml:"name" json:"name"`
+	PathPrefix  string            `yaml:"path_prefix" json:"path_prefix"`
+	Methods     []string          `yaml:"methods" json:"methods"`
+	Upstream    string            `yaml:"upstream" json:"upstream"`
+	StripPrefix bool              `yaml:"strip_prefix" json:"strip_prefix"`
+	Rewrite     string            `yaml:"rewrite" json:"rewrite"`
+	GRPCMethod  string            `yaml:"grpc_method" json:"grpc_method"`
+	UserIDField string            `yaml:"user_id_field" json:"user_id_field"`
+	Auth        string            `yaml:"auth" json:"auth"`
+	Roles       []string          `yaml:"roles" json:"roles"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+}
+
+// AllowsMethod reports whether the route takes requests with method.
+func (r *Route) AllowsMethod(method string) bool {
+	return allowsMethod(r.Methods, method)
+}
+
+// MatchesPath reports whether path, once cleaned, is PathPrefix or below
+// it.
+func (r *Route) MatchesPath(path string) bool {
+	return matchesPath(r.PathPrefix, CleanPath(path))
+}
+
+// UpstreamPath is the path an HTTP upstream is sent for path, which is
+// cleaned first so that it cannot climb out of Rewrite.
+func (r *Route) UpstreamPath(path string) string {
+	path = CleanPath(path)
+	if r.StripPrefix {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(r.PathPrefix, "/"))
+	}
+	path = strings.TrimSuffix(r.Rewrite, "/") + path
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// CleanPath resolves the . and .. elements and repeated slashes of a
+// request path the way path.Clean does, keeping a trailing slash. Routes
+// are matched on the cleaned path, so /api/v1/search/../admin is never
+// taken for a path under /api/v1/search.
+func CleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// LoadRouteTable reads a route table from a .yaml, .yml or .json file and
+// validates it. Unknown fields are rejected so that typos do not go
+// unnoticed.
+func LoadRouteTable(path string) (*RouteTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRouteTable(path, data)
+}
+
+// ParseRouteTable decodes and validates the content of the route table file
+// at path, whose extension picks the format.
+func ParseRouteTable(path string, data []byte) (*RouteTable, error) {
+	var err error
+	var table RouteTable
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&table)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&table)
+	default:
+		return nil, fmt.Errorf("%s: route table must be a .yaml, .yml or .json file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := table.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	table.Version = RouteTableVersion(data)
+
+	return &table, nil
+}
+
+// RouteTableVersion is the version of a route table file with content data.
+func RouteTableVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Validate normalizes the table and reports every problem in it at once.
+func (t *RouteTable) Validate() error {
+	var problems []string
+
+	names := make([]string, 0, len(t.Upstreams))
+	for name := range t.Upstreams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		upstream := t.Upstreams[name]
+		if upstream == nil {
+			problems = append(problems, fmt.Sprintf("upstream %s: is empty", name))
+			continue
+		}
+		upstream.Protocol = strings.ToLower(strings.TrimSpace(upstream.Protocol))
+		upstream.URL = strings.TrimSpace(expandEnv(upstream.URL))
+
+		switch upstream.Protocol {
+		case ProtocolHTTP:
+			target, err := url.Parse(upstream.URL)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				problems = append(problems, fmt.Sprintf("upstream %s: url must be an http or https URL", name))
+			}
+		case ProtocolGRPC:
+			if upstream.URL == "" || strings.Contains(upstream.URL, "://") {
+				problems = append(problems, fmt.Sprintf("upstream %s: url must be a host:port", name))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("upstream %s: protocol must be http or grpc", name))
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, route := range t.Routes {
+		if route == nil {
+			problems = append(problems, fmt.Sprintf("routes[%d]: is empty", i))
+			continue
+		}
+
+		label := fmt.Sprintf("routes[%d]", i)
+		if route.Name == "" {
+			problems = append(problems, label+": name is required")
+		} else {
+			label = "route " + route.Name
+			if seen[route.Name] {
+				problems = append(problems, label+": name is used twice")
+			}
+			seen[route.Name] = true
+		}
+
+		problems = append(problems, validateMatch(label, route.PathPrefix, route.Methods)...)
+
+		route.Auth = strings.ToLower(strings.TrimSpace(route.Auth))
+		if route.Auth == "" {
+			route.Auth = AuthNone
+		}
+		switch route.Auth {
+		case AuthNone, AuthOptional, AuthRequired:
+		default:
+			problems = append(problems, label+": auth must be none, optional or required")
+		}
+		if len(route.Roles) > 0 && route.Auth != AuthRequired {
+			problems = append(problems, label+": roles need auth required")
+		}
+		if route.UserIDField != "" && route.Auth == AuthNone {
+			problems = append(problems, label+": user_id_field needs auth optional or required")
+		}
+
+		for name := range route.Headers {
+			if strings.EqualFold(name, "X-User-ID") || strings.EqualFold(name, "X-User-Role") {
+				problems = append(problems, fmt.Sprintf("%s: header %s is set by the gateway", label, name))
+			}
+		}
+
+		upstream, ok := t.Upstreams[route.Upstream]
+		if !ok || upstream == nil {
+			problems = append(problems, fmt.Sprintf("%s: upstream %q is not defined", label, route.Upstream))
+			continue
+		}
+
+		switch upstream.Protocol {
+		case ProtocolHTTP:
+			if route.GRPCMethod != "" || route.UserIDField != "" {
+				problems = append(problems, label+": grpc_method and user_id_field are only for grpc upstreams")
+			}
+			if route.Rewrite != "" && (!strings.HasPrefix(route.Rewrite, "/") || CleanPath(route.Rewrite) != route.Rewrite) {
+				problems = append(problems, label+": rewrite must be a clean path starting with /")
+			}
+		case ProtocolGRPC:
+			if !grpcMethodPattern.MatchString(route.GRPCMethod) {
+				problems = append(problems, label+": grpc_method must be a full method name such as /package.Service/Method")
+			}
+			if route.StripPrefix || route.Rewrite != "" {
+				problems = append(problems, label+": strip_prefix and rewrite are only for http upstreams")
+			}
+		}
+	}
+
+	problems = append(problems, t.validateRateLimits()...)
+	problems = append(problems, t.validatePolicies()...)
+
+	if len(problems) > 0 {
+		return errors.New("invalid route table: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CheckReserved reports the routes whose path prefix overlaps one of the
+// gateway's own paths. reserved are gin route paths, which may have :param
+// and *wildcard elements. The gateway's own routes are matched first, so an
+// overlapping route would only serve some of its paths, and could not
+// replace or protect the others.
+func (t *RouteTable) CheckReserved(reserved []string) error {
+	var problems []string
+	for _, route := range t.Routes {
+		for _, path := range reserved {
+			if overlaps(route.PathPrefix, path) {
+				problems = append(problems, fmt.Sprintf("route %s: path_prefix %s overlaps the gateway's own route %s", route.Name, route.PathPrefix, path))
+				break
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid route table: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// overlaps reports whether a request could match both pathPrefix and the
+// gin route path reserved.
+func overlaps(pathPrefix, reserved string) bool {
+	i := strings.IndexAny(reserved, ":*")
+	if i < 0 {
+		return matchesPath(pathPrefix, reserved)
+	}
+
+	// Everything below the static part of a path with parameters may be
+	// served by it.
+	static := reserved[:i]
+	return matchesPath(pathPrefix, static) || strings.HasPrefix(pathPrefix, static)
+}
+
+// validateMatch checks the path prefix and methods requests are selected by
+// and normalizes the methods.
+func validateMatch(label, pathPrefix string, methods []string) []string {
+	var problems []string
+
+	if !strings.HasPrefix(pathPrefix, "/") {
+		problems = append(problems, label+": path_prefix must start with /")
+	} else if CleanPath(pathPrefix) != pathPrefix {
+		problems = append(problems, label+": path_prefix must not have . or .. elements or repeated slashes")
+	}
+
+	for i, method := range methods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if !routeMethods[method] {
+			problems = append(problems, fmt.Sprintf("%s: method %q is not supported", label, methods[i]))
+		}
+		methods[i] = method
+	}
+
+	return problems
+}
+
+func allowsMethod(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, allowed := range methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(pathPrefix, path string) bool {
+	if !strings.HasPrefix(path, pathPrefix) {
+		return false
+	}
+	rest := path[len(pathPrefix):]
+	return rest == "" || rest[0] == '/' || strings.HasSuffix(pathPrefix, "/")
+}
+
+// Duration is a time.Duration written as a string such as 1m30s.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	return d.parse(value.Value)
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as 1m30s")
+	}
+	return d.parse(value)
+}
+
+func (d *Duration) parse(value string) error {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", value)
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func expandEnv(s string) string {
+	return os.Expand(s, func(name string) string {
+		name, defaultValue, _ := strings.Cut(name, ":-")
+		return getEnv(name, defaultValue)
+	})
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func testRouteTable(routes ...*Route) *RouteTable {
+	return &RouteTable{
+		Upstreams: map[string]*Upstream{
+			"search":  {Protocol: "HTTP", URL: "http://search:8080"},
+			"product": {Protocol: "grpc", URL: "product:8082"},
+		},
+		Routes: routes,
+	}
+}
+
+func TestRouteTableValidate(t *testing.T) {
+	httpRoute := func(edit func(r *Route)) *Route {
+		route := &Route{Name: "search", PathPrefix: "/api/v1/search", Methods: []string{"get"}, Upstream: "search"}
+		if edit != nil {
+			edit(route)
+		}
+		return route
+	}
+	grpcRoute := func(edit func(r *Route)) *Route {
+		route := &Route{Name: "rates", PathPrefix: "/api/v1/exchange-rates", Upstream: "product", GRPCMethod: "/product.ProductService/ListExchangeRates"}
+		if edit != nil {
+			edit(route)
+		}
+		return route
+	}
+
+	tests := []struct {
+		name    string
+		table   *RouteTable
+		wantErr string
+	}{
+		{name: "valid", table: testRouteTable(httpRoute(nil), grpcRoute(nil))},
+		{name: "name is required", table: testRouteTable(httpRoute(func(r *Route) { r.Name = "" })), wantErr: "routes[0]: name is required"},
+		{name: "name used twice", table: testRouteTable(httpRoute(nil), httpRoute(nil)), wantErr: "route search: name is used twice"},
+		{name: "relative path prefix", table: testRouteTable(httpRoute(func(r *Route) { r.PathPrefix = "api/v1/search" })), wantErr: "path_prefix must start with /"},
+		{name: "path prefix with dot dot", table: testRouteTable(httpRoute(func(r *Route) { r.PathPrefix = "/api/v1/search/../admin" })), wantErr: "path_prefix must not have"},
+		{name: "path prefix with repeated slashes", table: testRouteTable(httpRoute(func(r *Route) { r.PathPrefix = "/api//search" })), wantErr: "path_prefix must not have"},
+		{name: "unsupported method", table: testRouteTable(httpRoute(func(r *Route) { r.Methods = []string{"TRACE"} })), wantErr: `method "TRACE" is not supported`},
+		{name: "unknown auth", table: testRouteTable(httpRoute(func(r *Route) { r.Auth = "maybe" })), wantErr: "auth must be none, optional or required"},
+		{name: "roles without auth", table: testRouteTable(httpRoute(func(r *Route) { r.Roles = []string{"admin"} })), wantErr: "roles need auth required"},
+		{name: "identity header", table: testRouteTable(httpRoute(func(r *Route) { r.Headers = map[string]string{"x-user-id": "1"} })), wantErr: "header x-user-id is set by the gateway"},
+		{name: "unknown upstream", table: testRouteTable(httpRoute(func(r *Route) { r.Upstream = "nope" })), wantErr: `upstream "nope" is not defined`},
+		{name: "grpc method on http", table: testRouteTable(httpRoute(func(r *Route) { r.GRPCMethod = "/a.B/C" })), wantErr: "only for grpc upstreams"},
+		{name: "relative rewrite", table: testRouteTable(httpRoute(func(r *Route) { r.Rewrite = "v1" })), wantErr: "rewrite must be a clean path"},
+		{name: "rewrite with dot dot", table: testRouteTable(httpRoute(func(r *Route) { r.Rewrite = "/v1/../admin" })), wantErr: "rewrite must be a clean path"},
+		{name: "bad grpc method", table: testRouteTable(grpcRoute(func(r *Route) { r.GRPCMethod = "ListExchangeRates" })), wantErr: "grpc_method must be a full method name"},
+		{name: "strip prefix on grpc", table: testRouteTable(grpcRoute(func(r *Route) { r.StripPrefix = true })), wantErr: "only for http upstreams"},
+		{name: "user id field without auth", table: testRouteTable(grpcRoute(func(r *Route) { r.UserIDField = "user_id" })), wantErr: "user_id_field needs auth optional or required"},
+		{name: "bad http url", table: &RouteTable{Upstreams: map[string]*Upstream{"search": {Protocol: "http", URL: "search:8080"}}}, wantErr: "upstream search: url must be an http or https URL"},
+		{name: "grpc url with scheme", table: &RouteTable{Upstreams: map[string]*Upstream{"product": {Protocol: "grpc", URL: "http://product:8082"}}}, wantErr: "upstream product: url must be a host:port"},
+		{name: "unknown protocol", table: &RouteTable{Upstreams: map[string]*Upstream{"search": {Protocol: "ftp", URL: "ftp://search"}}}, wantErr: "protocol must be http or grpc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.table.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Validate() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRouteTableValidateNormalizes(t *testing.T) {
+	table := testRouteTable(&Route{Name: "search", PathPrefix: "/api/v1/search", Methods: []string{" get "}, Upstream: "search"})
+	if err := table.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	route := table.Routes[0]
+	if route.Methods[0] != "GET" || route.Auth != AuthNone || table.Upstreams["search"].Protocol != ProtocolHTTP {
+		t.Errorf("Validate() = methods %v, auth %q, protocol %q, want GET, none, http",
+			route.Methods, route.Auth, table.Upstreams["search"].Protocol)
+	}
+}
+
+func TestParseRouteTable(t *testing.T) {
+	table, err := LoadRouteTable("../../routes.yaml")
+	if err != nil {
+		t.Fatalf("LoadRouteTable() of the shipped file error = %v", err)
+	}
+	if table.Version == "" || len(table.Routes) == 0 {
+		t.Errorf("LoadRouteTable() = version %q with %d routes, want a version and routes", table.Version, len(table.Routes))
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		wantErr string
+	}{
+		{name: "json", path: "routes.json", data: `{"upstreams": {"search": {"protocol": "http", "url": "http://search"}}}`},
+		{name: "unknown yaml field", path: "routes.yaml", data: "upstream: {}\n", wantErr: "field upstream not found"},
+		{name: "unknown json field", path: "routes.json", data: `{"route": []}`, wantErr: `unknown field "route"`},
+		{name: "invalid table", path: "routes.yml", data: "routes:\n  - name: x\n", wantErr: "invalid route table"},
+		{name: "other extension", path: "routes.toml", data: "", wantErr: "must be a .yaml, .yml or .json file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRouteTable(tt.path, []byte(tt.data))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("ParseRouteTable() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("ParseRouteTable() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/api/v1/search", "/api/v1/search"},
+		{"/api/v1/search/", "/api/v1/search/"},
+		{"/api//v1/./search", "/api/v1/search"},
+		{"/api/v1/search/../admin", "/api/v1/admin"},
+		{"/../../etc/passwd", "/etc/passwd"},
+		{"api/v1", "/api/v1"},
+	}
+
+	for _, tt := range tests {
+		if got := CleanPath(tt.path); got != tt.want {
+			t.Errorf("CleanPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRouteMatchesPath(t *testing.T) {
+	tests := []struct {
+		prefix string
+		path   string
+		want   bool
+	}{
+		{"/api/v1/search", "/api/v1/search", true},
+		{"/api/v1/search", "/api/v1/search/books", true},
+		{"/api/v1/search", "/api/v1/searches", false},
+		{"/api/v1/search", "/api/v1", false},
+		{"/api/v1/search", "/api/v1/search/../admin", false},
+		{"/api/v1/search", "/api/v1/other/../search/books", true},
+		{"/files/", "/files/a", true},
+		{"/files/", "/files", false},
+	}
+
+	for _, tt := range tests {
+		route := &Route{PathPrefix: tt.prefix}
+		if got := route.MatchesPath(tt.path); got != tt.want {
+			t.Errorf("Route{PathPrefix: %q}.MatchesPath(%q) = %v, want %v", tt.prefix, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRouteUpstreamPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		route Route
+		path  string
+		want  string
+	}{
+		{name: "forwarded as is", route: Route{PathPrefix: "/api/v1/search"}, path: "/api/v1/search/books", want: "/api/v1/search/books"},
+		{name: "prefix stripped", route: Route{PathPrefix: "/api/v1/search", StripPrefix: true}, path: "/api/v1/search/books", want: "/books"},
+		{name: "prefix stripped to the root", route: Route{PathPrefix: "/api/v1/search", StripPrefix: true}, path: "/api/v1/search", want: "/"},
+		{name: "stripped and rewritten", route: Route{PathPrefix: "/api/v1/search", StripPrefix: true, Rewrite: "/v1/query"}, path: "/api/v1/search/books", want: "/v1/query/books"},
+		{name: "rewritten onto the prefix", route: Route{PathPrefix: "/api/v1/search", StripPrefix: true, Rewrite: "/v1/query/"}, path: "/api/v1/search", want: "/v1/query"},
+		{name: "rewrite in front", route: Route{PathPrefix: "/api/v1/search", Rewrite: "/internal"}, path: "/api/v1/search", want: "/internal/api/v1/search"},
+		{name: "prefix with a trailing slash", route: Route{PathPrefix: "/files/", StripPrefix: true}, path: "/files/a/b", want: "/a/b"},
+		{name: "cannot climb out of the rewrite", route: Route{PathPrefix: "/api/v1/search", StripPrefix: true, Rewrite: "/v1/query"}, path: "/api/v1/search/../../../admin", want: "/v1/query/admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.route.UpstreamPath(tt.path); got != tt.want {
+				t.Errorf("UpstreamPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteAllowsMethod(t *testing.T) {
+	anyMethod := &Route{}
+	if !anyMethod.AllowsMethod("DELETE") {
+		t.Error("AllowsMethod() of a route without methods = false, want true")
+	}
+
+	get := &Route{Methods: []string{"GET", "HEAD"}}
+	if !get.AllowsMethod("HEAD") || get.AllowsMethod("POST") {
+		t.Errorf("AllowsMethod() of a GET and HEAD route = %v for HEAD and %v for POST, want true and false",
+			get.AllowsMethod("HEAD"), get.AllowsMethod("POST"))
+	}
+}
+
+func TestCheckReserved(t *testing.T) {
+	reserved := []string{
+		"/",
+		"/health",
+		"/api/v1/me",
+		"/api/v1/orders/:id",
+		"/api/v1/admin/exchange-rates",
+		"/static/*filepath",
+	}
+
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"/api/v1/search", false},
+		{"/api/v1/exchange-rates", false},
+		{"/healthz", false},
+		{"/api/v1/ordersx", false},
+		{"/", true},
+		{"/health", true},
+		{"/api/v1/me", true},
+		{"/api/v1", true},
+		{"/api/v1/orders", true},
+		{"/api/v1/orders/42/invoice", true},
+		{"/api/v1/admin", true},
+		{"/static/css", true},
+	}
+
+	for _, tt := range tests {
+		table := testRouteTable(&Route{Name: "r", PathPrefix: tt.prefix, Upstream: "search"})
+		err := table.CheckReserved(reserved)
+		if (err != nil) != tt.want {
+			t.Errorf("CheckReserved() of path_prefix %s error = %v, want overlap %v", tt.prefix, err, tt.want)
+		}
+	}
+
+	if err := testRouteTable().CheckReserved(reserved); err != nil {
+		t.Errorf("CheckReserved() of an empty table error = %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/customer-service/proto"
+)
+
+type AddressHandler struct {
+	client pb.AddressServiceClient
+	conn   *grpc.ClientConn
+}
+
+type addressRequest struct {
+	Label             string `json:"label" binding:"max=50"`
+	RecipientName     string `json:"recipient_name" binding:"required,max=100"`
+	PhoneNumber       string `json:"phone_number" binding:"required,e164"`
+	Line1             string `json:"line1" binding:"required,max=255"`
+	Line2             string `json:"line2" binding:"max=255"`
+	City              string `json:"city" binding:"required,max=100"`
+	Region            string `json:"region" binding:"max=100"`
+	PostalCode        string `json:"postal_code" binding:"max=20"`
+	CountryCode       string `json:"country_code" binding:"required,len=2"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+}
+
+func (r *addressRequest) toProto() *pb.AddressInput {
+	return &pb.AddressInput{
+		Label:             r.Label,
+		RecipientName:     r.RecipientName,
+		PhoneNumber:       r.PhoneNumber,
+		Line1:             r.Line1,
+		Line2:             r.Line2,
+		City:              r.City,
+		Region:            r.Region,
+		PostalCode:        r.PostalCode,
+		CountryCode:       r.CountryCode,
+		IsDefaultShipping: r.IsDefaultShipping,
+		IsDefaultBilling:  r.IsDefaultBilling,
+	}
+}
+
+func NewAddressHandler(customerServiceURL string, opts ...grpc.DialOption) (*AddressHandler, error) {
+	conn, err := grpc.NewClient(customerServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressHandler{
+		client: pb.NewAddressServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *AddressHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListAddresses(ctx, &pb.ListAddressesRequest{
+		CustomerId: c.GetInt64("user_id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	addresses := resp.Addresses
+	if addresses == nil {
+		addresses = []*pb.Address{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"addresses": addresses,
+	})
+}
+
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetAddress(ctx, &pb.GetAddressRequest{
+		CustomerId: c.GetInt64("user_id"),
+		Id:         c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Address)
+}
+
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	var req addressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateAddress(ctx, &pb.CreateAddressRequest{
+		CustomerId: c.GetInt64("user_id"),
+		Address:    req.toProto(),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Address)
+}
+
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	var req addressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdateAddress(ctx, &pb.UpdateAddressRequest{
+		CustomerId: c.GetInt64("user_id"),
+		Id:         c.Param("id"),
+		Address:    req.toProto(),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Address)
+}
+
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	ctx := requestContext(c)
+
+	_, err := h.client.DeleteAddress(ctx, &pb.DeleteAddressRequest{
+		CustomerId: c.GetInt64("user_id"),
+		Id:         c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AddressHandler) SetDefaultAddress(c *gin.Context) {
+	var req struct {
+		Kind string `json:"kind" binding:"required,oneof=shipping billing"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.SetDefaultAddress(ctx, &pb.SetDefaultAddressRequest{
+		CustomerId: c.GetInt64("user_id"),
+		Id:         c.Param("id"),
+		Kind:       req.Kind,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Address)
+}
@@ -1,27 +1,39 @@
 package handler
 
 import (
-	"context"
+	"encoding/json"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/Dzaakk/micro-commerce/services/auth-service/proto"
 )
 
+const maxNameLength = 100
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,20}$`)
+
 type AuthHandler struct {
-	client pb.AuthServiceClient
-	conn   *grpc.ClientConn
+	client     pb.AuthServiceClient
+	userClient pb.UserServiceClient
+	conn       *grpc.ClientConn
+	loginHooks []LoginHook
 }
 
-func NewAuthHandler(authServiceURL string) (*AuthHandler, error) {
+// LoginHook runs after a successful login, before the response is written.
+type LoginHook func(c *gin.Context, userID int64)
+
+func NewAuthHandler(authServiceURL string, opts ...grpc.DialOption) (*AuthHandler, error) {
 	conn, err := grpc.NewClient(authServiceURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
 	)
 
 	if err != nil {
@@ -29,8 +41,9 @@ func NewAuthHandler(authServiceURL string) (*AuthHandler, error) {
 	}
 
 	return &AuthHandler{
-		client: pb.NewAuthServiceClient(conn),
-		conn:   conn,
+		client:     pb.NewAuthServiceClient(conn),
+		userClient: pb.NewUserServiceClient(conn),
+		conn:       conn,
 	}, nil
 }
 
@@ -40,6 +53,11 @@ func (h *AuthHandler) Close() {
 	}
 }
 
+// OnLogin registers hook to run after every successful login.
+func (h *AuthHandler) OnLogin(hook LoginHook) {
+	h.loginHooks = append(h.loginHooks, hook)
+}
+
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req struct {
 		Email     string `json:"email" binding:"required,email"`
@@ -50,14 +68,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		problem.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx := requestContext(c)
 
 	resp, err := h.client.Register(ctx, &pb.RegisterRequest{
 		Email:     req.Email,
@@ -68,10 +83,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	})
 
 	if err != nil {
-		st, _ := status.FromError(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": st.Message(),
-		})
+		problem.GRPC(c, err)
 		return
 	}
 
@@ -90,14 +102,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		problem.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := requestContext(c)
 
 	resp, err := h.client.Login(ctx, &pb.LoginRequest{
 		Email:    req.Email,
@@ -105,13 +114,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 
 	if err != nil {
-		st, _ := status.FromError(err)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": st.Message(),
-		})
+		problem.GRPC(c, err)
 		return
 	}
 
+	h.runLoginHooks(c, resp.AccessToken)
+
 	c.JSON(http.StatusOK, gin.H{
 		"access_token":  resp.AccessToken,
 		"refresh_token": resp.RefreshToken,
@@ -126,24 +134,18 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		problem.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := requestContext(c)
 
 	resp, err := h.client.RefreshToken(ctx, &pb.RefreshTokenRequest{
 		RefreshToken: req.RefreshToken,
 	})
 
 	if err != nil {
-		st, _ := status.FromError(err)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": st.Message(),
-		})
+		problem.GRPC(c, err)
 		return
 	}
 
@@ -157,23 +159,18 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	token := extractToken(c.GetHeader("Authorization"))
 	if token == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "token required",
-		})
+		problem.Error(c, http.StatusUnauthorized, "token required")
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := requestContext(c)
 
 	resp, err := h.client.ValidateToken(ctx, &pb.ValidateTokenRequest{
 		Token: token,
 	})
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "invalid token",
-		})
+		problem.GRPC(c, err)
 		return
 	}
 
@@ -185,9 +182,23 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	})
 }
 
-func (h *AuthHandler) ValidateTokenMiddleware(token string) (*pb.ValidateTokenResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (h *AuthHandler) runLoginHooks(c *gin.Context, accessToken string) {
+	if len(h.loginHooks) == 0 {
+		return
+	}
+
+	claims, err := h.ValidateTokenMiddleware(c, accessToken)
+	if err != nil || !claims.Valid {
+		return
+	}
+
+	for _, hook := range h.loginHooks {
+		hook(c, claims.UserId)
+	}
+}
+
+func (h *AuthHandler) ValidateTokenMiddleware(c *gin.Context, token string) (*pb.ValidateTokenResponse, error) {
+	ctx := requestContext(c)
 
 	return h.client.ValidateToken(ctx, &pb.ValidateTokenRequest{
 		Token: token,
@@ -195,30 +206,172 @@ func (h *AuthHandler) ValidateTokenMiddleware(token string) (*pb.ValidateTokenRe
 }
 
 func (h *AuthHandler) GetProfile(c *gin.Context) {
-	userID := c.GetInt64("user_id")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := requestContext(c)
 
-	resp, err := h.client.GetUser(ctx, &pb.GetUserRequest{
-		Id: userID,
+	resp, err := h.userClient.GetProfile(ctx, &pb.GetProfileRequest{
+		UserId: c.GetInt64("user_id"),
 	})
 
 	if err != nil {
-		st, _ := status.FromError(err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": st.Message(),
-		})
+		problem.GRPC(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, resp.User)
+	etag := profileETag(resp.Profile)
+	c.Header("ETag", etag)
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Profile)
 }
 
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"message": "update profile endpoint",
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		problem.Error(c, http.StatusPreconditionRequired, "If-Match header with the profile ETag is required")
+		return
+	}
+
+	expectedVersion, ok := parseProfileETag(ifMatch)
+	if !ok {
+		problem.Error(c, http.StatusPreconditionFailed, "invalid If-Match header")
+		return
+	}
+
+	var req struct {
+		Username  *string `json:"username"`
+		FirstName *string `json:"first_name"`
+		LastName  *string `json:"last_name"`
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid request body, only username, first_name and last_name can be updated here: "+err.Error())
+		return
+	}
+
+	var invalid []problem.InvalidParam
+	if req.Username != nil && !usernamePattern.MatchString(*req.Username) {
+		invalid = append(invalid, problem.InvalidParam{Name: "username", Reason: "must be 3-20 letters, digits, '.', '_' or '-'"})
+	}
+	if req.FirstName != nil && len(*req.FirstName) > maxNameLength {
+		invalid = append(invalid, problem.InvalidParam{Name: "first_name", Reason: "must be at most 100 characters"})
+	}
+	if req.LastName != nil && len(*req.LastName) > maxNameLength {
+		invalid = append(invalid, problem.InvalidParam{Name: "last_name", Reason: "must be at most 100 characters"})
+	}
+	if len(invalid) > 0 {
+		p := problem.New(http.StatusBadRequest, "validation failed")
+		p.InvalidParams = invalid
+		problem.Respond(c, p)
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.userClient.UpdateProfile(ctx, &pb.UpdateProfileRequest{
+		UserId:          c.GetInt64("user_id"),
+		Username:        req.Username,
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		ExpectedVersion: expectedVersion,
+	})
+
+	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			problem.Error(c, http.StatusPreconditionFailed, "profile was modified, fetch it again and retry")
+			return
+		}
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Header("ETag", profileETag(resp.Profile))
+	c.JSON(http.StatusOK, resp.Profile)
+}
+
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewEmail        string `json:"new_email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.userClient.ChangeEmail(ctx, &pb.ChangeEmailRequest{
+		UserId:          c.GetInt64("user_id"),
+		CurrentPassword: req.CurrentPassword,
+		NewEmail:        req.NewEmail,
 	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Header("ETag", profileETag(resp.Profile))
+	c.JSON(http.StatusOK, resp.Profile)
+}
+
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.userClient.ChangePassword(ctx, &pb.ChangePasswordRequest{
+		UserId:          c.GetInt64("user_id"),
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Header("ETag", profileETag(resp.Profile))
+	c.Status(http.StatusNoContent)
+}
+
+// profileETag derives a strong ETag from the profile version (updated_at).
+func profileETag(profile *pb.Profile) string {
+	return `"` + strconv.FormatInt(profile.GetVersion(), 10) + `"`
+}
+
+// parseProfileETag returns the version carried by an If-Match value. "*"
+// matches any version and is returned as 0, which skips the check.
+func parseProfileETag(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return 0, true
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+
+	return version, true
 }
 
 func extractToken(authHeader string) string {
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	pb "github.com/Dzaakk/micro-commerce/services/auth-service/proto"
+)
+
+// fakeAuthClient answers Login and ValidateToken; the other methods are not
+// used.
+type fakeAuthClient struct {
+	pb.AuthServiceClient
+}
+
+func (f *fakeAuthClient) Login(ctx context.Context, in *pb.LoginRequest, opts ...grpc.CallOption) (*pb.AuthResponse, error) {
+	return &pb.AuthResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil
+}
+
+func (f *fakeAuthClient) ValidateToken(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateTokenResponse, error) {
+	return &pb.ValidateTokenResponse{Valid: in.Token == "access-token", UserId: 42}, nil
+}
+
+func TestLoginRunsHooks(t *testing.T) {
+	h := &AuthHandler{client: &fakeAuthClient{}}
+
+	var got []int64
+	h.OnLogin(func(c *gin.Context, userID int64) { got = append(got, userID) })
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+		strings.NewReader(`{"email": "jane@example.com", "password": "secret"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.Login(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(got) != 1 || got[0] != 42 {
+		t.Errorf("login hooks ran for %v, want once for user 42", got)
+	}
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/cart-service/proto"
+)
+
+const (
+	guestCartCookie = "guest_cart"
+	guestCartMaxAge = 30 * 24 * 60 * 60
+)
+
+type CartHandler struct {
+	client       pb.CartServiceClient
+	conn         *grpc.ClientConn
+	cookieSecret []byte
+	secureCookie bool
+}
+
+// NewCartHandler creates the cart facade. Guest carts are identified by a
+// cookie signed with cookieSecret; secureCookie should be set in production
+// so the cookie is only sent over HTTPS.
+func NewCartHandler(cartServiceURL, cookieSecret string, secureCookie bool, opts ...grpc.DialOption) (*CartHandler, error) {
+	conn, err := grpc.NewClient(cartServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &CartHandler{
+		client:       pb.NewCartServiceClient(conn),
+		conn:         conn,
+		cookieSecret: []byte(cookieSecret),
+		secureCookie: secureCookie,
+	}, nil
+}
+
+func (h *CartHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+func (h *CartHandler) GetCart(c *gin.Context) {
+	userID, guestID := h.owner(c, false)
+	if userID == 0 && guestID == "" {
+		c.JSON(http.StatusOK, gin.H{
+			"items":          []*pb.CartItem{},
+			"item_count":     0,
+			"subtotal":       0,
+			"valid":          false,
+			"coupon_codes":   []string{},
+			"discount_total": 0,
+			"total":          0,
+		})
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetCart(ctx, &pb.CartRequest{
+		UserId:  userID,
+		GuestId: guestID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req struct {
+		SKU      string `json:"sku" binding:"required,max=64"`
+		Quantity int32  `json:"quantity" binding:"required,min=1,max=99"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.AddItem(ctx, &pb.AddItemRequest{
+		UserId:   userID,
+		GuestId:  guestID,
+		Sku:      req.SKU,
+		Quantity: req.Quantity,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	var req struct {
+		Quantity *int32 `json:"quantity" binding:"required,min=0,max=99"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdateItem(ctx, &pb.UpdateItemRequest{
+		UserId:   userID,
+		GuestId:  guestID,
+		Sku:      c.Param("sku"),
+		Quantity: *req.Quantity,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.RemoveItem(ctx, &pb.RemoveItemRequest{
+		UserId:  userID,
+		GuestId: guestID,
+		Sku:     c.Param("sku"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	userID, guestID := h.owner(c, false)
+	if userID == 0 && guestID == "" {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	ctx := requestContext(c)
+
+	_, err := h.client.ClearCart(ctx, &pb.CartRequest{
+		UserId:  userID,
+		GuestId: guestID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ApplyCoupon adds a coupon code to the cart. A code that does not apply
+// to the cart is refused with the reason.
+func (h *CartHandler) ApplyCoupon(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required,max=32"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ApplyCoupon(ctx, &pb.CouponRequest{
+		UserId:  userID,
+		GuestId: guestID,
+		Code:    req.Code,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+// SetCurrency prices the cart in another currency. An empty currency
+// switches back to the base currency.
+func (h *CartHandler) SetCurrency(c *gin.Context) {
+	var req struct {
+		Currency string `json:"currency" binding:"omitempty,len=3"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.SetCurrency(ctx, &pb.CurrencyRequest{
+		UserId:   userID,
+		GuestId:  guestID,
+		Currency: req.Currency,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+func (h *CartHandler) RemoveCoupon(c *gin.Context) {
+	userID, guestID := h.owner(c, true)
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.RemoveCoupon(ctx, &pb.CouponRequest{
+		UserId:  userID,
+		GuestId: guestID,
+		Code:    c.Param("code"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Cart)
+}
+
+// MergeGuestCart moves the guest cart named by the request cookie into the
+// cart of userID and drops the cookie. It is registered as a login hook and
+// never fails the login; problems are only logged.
+func (h *CartHandler) MergeGuestCart(c *gin.Context, userID int64) {
+	guestID, ok := h.guestID(c)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(c)
+
+	_, err := h.client.MergeCarts(ctx, &pb.MergeCartsRequest{
+		GuestId: guestID,
+		UserId:  userID,
+	})
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "Failed to merge guest cart", slog.Int64("cart_user_id", userID), slog.Any("error", err))
+		return
+	}
+
+	h.setGuestCookie(c, "", -1)
+}
+
+// owner returns who the cart belongs to: the signed in user if there is one,
+// otherwise the guest from the signed cookie. With create set, a guest
+// without a valid cookie is given a new one.
+func (h *CartHandler) owner(c *gin.Context, create bool) (int64, string) {
+	if userID := c.GetInt64("user_id"); userID != 0 {
+		return userID, ""
+	}
+
+	guestID, ok := h.guestID(c)
+	if !ok {
+		if !create {
+			return 0, ""
+		}
+		guestID = newGuestID()
+	}
+
+	// Refresh the cookie on every write so it lives as long as the cart.
+	if create {
+		h.setGuestCookie(c, h.sign(guestID), guestCartMaxAge)
+	}
+
+	return 0, guestID
+}
+
+func (h *CartHandler) guestID(c *gin.Context) (string, bool) {
+	value, err := c.Cookie(guestCartCookie)
+	if err != nil {
+		return "", false
+	}
+
+	guestID, _, found := strings.Cut(value, ".")
+	if !found || guestID == "" {
+		return "", false
+	}
+
+	if !hmac.Equal([]byte(value), []byte(h.sign(guestID))) {
+		return "", false
+	}
+
+	return guestID, true
+}
+
+func (h *CartHandler) sign(guestID string) string {
+	mac := hmac.New(sha256.New, h.cookieSecret)
+	mac.Write([]byte(guestID))
+	return guestID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (h *CartHandler) setGuestCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(guestCartCookie, value, maxAge, "/", "", h.secureCookie, true)
+}
+
+func newGuestID() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
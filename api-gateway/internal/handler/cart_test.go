@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	pb "github.com/Dzaakk/micro-commerce/services/cart-service/proto"
+)
+
+// fakeCartClient records MergeCarts calls; the other methods are not used.
+type fakeCartClient struct {
+	pb.CartServiceClient
+	merged []*pb.MergeCartsRequest
+	err    error
+}
+
+func (f *fakeCartClient) MergeCarts(ctx context.Context, in *pb.MergeCartsRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	f.merged = append(f.merged, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.CartResponse{}, nil
+}
+
+func TestCartGuestCookie(t *testing.T) {
+	h := &CartHandler{cookieSecret: []byte("test-secret")}
+	other := &CartHandler{cookieSecret: []byte("other-secret")}
+
+	signed := h.sign("guest-1")
+	tampered := strings.Replace(signed, "guest-1", "guest-2", 1)
+
+	tests := []struct {
+		name     string
+		cookie   string
+		wantID   string
+		wantOK   bool
+		noCookie bool
+	}{
+		{name: "valid", cookie: signed, wantID: "guest-1", wantOK: true},
+		{name: "no cookie", noCookie: true},
+		{name: "unsigned", cookie: "guest-1"},
+		{name: "empty id", cookie: "." + strings.SplitN(signed, ".", 2)[1]},
+		{name: "tampered id", cookie: tampered},
+		{name: "other secret", cookie: other.sign("guest-1")},
+		{name: "truncated signature", cookie: signed[:len(signed)-2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/cart", nil)
+			if !tt.noCookie {
+				c.Request.AddCookie(&http.Cookie{Name: guestCartCookie, Value: tt.cookie})
+			}
+
+			id, ok := h.guestID(c)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Fatalf("guestID() = %q, %v, want %q, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
+
+// The merge runs as a login hook. auth-service does not implement Login yet,
+// so nothing reaches it through the gateway; this calls it directly.
+func TestMergeGuestCart(t *testing.T) {
+	tests := []struct {
+		name        string
+		cookie      string
+		mergeErr    error
+		wantMerged  bool
+		wantCleared bool
+	}{
+		{name: "guest cart", cookie: "guest-1", wantMerged: true, wantCleared: true},
+		{name: "no cookie"},
+		{name: "unsigned cookie", cookie: "guest-1.forged"},
+		{name: "merge fails", cookie: "guest-1", mergeErr: errors.New("cart-service down"), wantMerged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeCartClient{err: tt.mergeErr}
+			h := &CartHandler{client: client, cookieSecret: []byte("test-secret")}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+			switch {
+			case tt.cookie == "guest-1":
+				c.Request.AddCookie(&http.Cookie{Name: guestCartCookie, Value: h.sign(tt.cookie)})
+			case tt.cookie != "":
+				c.Request.AddCookie(&http.Cookie{Name: guestCartCookie, Value: tt.cookie})
+			}
+
+			h.MergeGuestCart(c, 42)
+
+			if merged := len(client.merged) == 1; merged != tt.wantMerged {
+				t.Fatalf("MergeCarts calls = %v, want merged %v", client.merged, tt.wantMerged)
+			}
+			if tt.wantMerged && (client.merged[0].GuestId != "guest-1" || client.merged[0].UserId != 42) {
+				t.Errorf("MergeCarts() request = %v, want guest-1 into user 42", client.merged[0])
+			}
+
+			cleared := strings.Contains(w.Header().Get("Set-Cookie"), guestCartCookie+"=;")
+			if cleared != tt.wantCleared {
+				t.Errorf("Set-Cookie = %q, want cookie cleared %v", w.Header().Get("Set-Cookie"), tt.wantCleared)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/checkout-service/proto"
+)
+
+type CheckoutHandler struct {
+	client pb.CheckoutServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewCheckoutHandler(checkoutServiceURL string, opts ...grpc.DialOption) (*CheckoutHandler, error) {
+	conn, err := grpc.NewClient(checkoutServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckoutHandler{
+		client: pb.NewCheckoutServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *CheckoutHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// Checkout places the orders for the signed in customer's cart. Clients
+// should send an Idempotency-Key header so a retried request returns the
+// first checkout instead of charging twice.
+func (h *CheckoutHandler) Checkout(c *gin.Context) {
+	var req struct {
+		ShippingAddressID string `json:"shipping_address_id" binding:"required"`
+		PaymentMethod     string `json:"payment_method" binding:"required"`
+		ShippingOption    string `json:"shipping_option" binding:"max=61"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+
+	// The saga calls several services in turn, so allow more than the usual
+	// 5s while staying inside the server's write timeout. A checkout still
+	// running when this expires carries on and can be fetched by id.
+	ctx, cancel := context.WithTimeout(requestContext(c), 12*time.Second)
+	defer cancel()
+
+	resp, err := h.client.StartCheckout(ctx, &pb.StartCheckoutRequest{
+		CustomerId:        c.GetInt64("user_id"),
+		IdempotencyKey:    key,
+		ShippingAddressId: req.ShippingAddressID,
+		PaymentMethod:     req.PaymentMethod,
+		ShippingOption:    req.ShippingOption,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(checkoutHTTPStatus(resp.Checkout), resp.Checkout)
+}
+
+func (h *CheckoutHandler) GetCheckout(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetCheckout(ctx, &pb.GetCheckoutRequest{
+		Id:         c.Param("id"),
+		CustomerId: c.GetInt64("user_id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Checkout)
+}
+
+// checkoutHTTPStatus reports a checkout that is still being processed as
+// 202 so the client polls it, and a failed one as a client error carrying
+// the failure in the body.
+func checkoutHTTPStatus(checkout *pb.Checkout) int {
+	switch checkout.GetStatus() {
+	case "completed":
+		return http.StatusCreated
+	case "failed":
+		if checkout.GetFailure().GetCode() == "payment_declined" {
+			return http.StatusPaymentRequired
+		}
+		return http.StatusConflict
+	default:
+		return http.StatusAccepted
+	}
+}
+
+func newIdempotencyKey() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+)
+
+type InventoryHandler struct {
+	client pb.InventoryServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewInventoryHandler(inventoryServiceURL string, opts ...grpc.DialOption) (*InventoryHandler, error) {
+	conn, err := grpc.NewClient(inventoryServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &InventoryHandler{
+		client: pb.NewInventoryServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *InventoryHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+func (h *InventoryHandler) ListWarehouses(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListWarehouses(ctx, &pb.ListWarehousesRequest{})
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	warehouses := resp.Warehouses
+	if warehouses == nil {
+		warehouses = []*pb.Warehouse{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"warehouses": warehouses,
+	})
+}
+
+func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required,max=32"`
+		Name string `json:"name" binding:"required,max=100"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateWarehouse(ctx, &pb.CreateWarehouseRequest{
+		Code: req.Code,
+		Name: req.Name,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Warehouse)
+}
+
+// GetStock returns stock for one or more SKUs, e.g. ?sku=A-1&sku=A-2.
+func (h *InventoryHandler) GetStock(c *gin.Context) {
+	skus := c.QueryArray("sku")
+	if len(skus) == 0 {
+		problem.Error(c, http.StatusBadRequest, "at least one sku query parameter is required")
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetStock(ctx, &pb.GetStockRequest{
+		Skus: skus,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	items := resp.Items
+	if items == nil {
+		items = []*pb.Stock{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+func (h *InventoryHandler) SetStock(c *gin.Context) {
+	var req struct {
+		OnHand            *int32 `json:"on_hand" binding:"required,gte=0"`
+		LowStockThreshold int32  `json:"low_stock_threshold" binding:"gte=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.SetStock(ctx, &pb.SetStockRequest{
+		Sku:               c.Param("sku"),
+		WarehouseId:       c.Param("warehouse_id"),
+		OnHand:            *req.OnHand,
+		LowStockThreshold: req.LowStockThreshold,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Stock)
+}
+
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	var req struct {
+		Delta int32 `json:"delta" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.AdjustStock(ctx, &pb.AdjustStockRequest{
+		Sku:         c.Param("sku"),
+		WarehouseId: c.Param("warehouse_id"),
+		Delta:       req.Delta,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Stock)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/ledger-service/proto"
+)
+
+type LedgerHandler struct {
+	client pb.LedgerServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewLedgerHandler(ledgerServiceURL string, opts ...grpc.DialOption) (*LedgerHandler, error) {
+	conn, err := grpc.NewClient(ledgerServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &LedgerHandler{
+		client: pb.NewLedgerServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *LedgerHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// GetMyBalance shows what the platform owes the calling seller.
+func (h *LedgerHandler) GetMyBalance(c *gin.Context) {
+	h.getBalance(c, c.GetInt64("user_id"))
+}
+
+func (h *LedgerHandler) GetSellerBalance(c *gin.Context) {
+	sellerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid seller id")
+		return
+	}
+
+	h.getBalance(c, sellerID)
+}
+
+func (h *LedgerHandler) getBalance(c *gin.Context, sellerID int64) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetSellerBalance(ctx, &pb.GetSellerBalanceRequest{
+		SellerId: sellerID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Balance)
+}
+
+// ListMyEntries lists the calling seller's statement, newest first.
+func (h *LedgerHandler) ListMyEntries(c *gin.Context) {
+	h.listEntries(c, c.GetInt64("user_id"))
+}
+
+func (h *LedgerHandler) ListSellerEntries(c *gin.Context) {
+	sellerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid seller id")
+		return
+	}
+
+	h.listEntries(c, sellerID)
+}
+
+func (h *LedgerHandler) listEntries(c *gin.Context, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListSellerEntries(ctx, &pb.ListSellerEntriesRequest{
+		SellerId: sellerID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	entries := resp.Entries
+	if entries == nil {
+		entries = []*pb.SellerEntry{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   resp.Total,
+	})
+}
+
+// ListMyPayouts lists the payouts made to the calling seller.
+func (h *LedgerHandler) ListMyPayouts(c *gin.Context) {
+	h.listPayouts(c, c.GetInt64("user_id"))
+}
+
+func (h *LedgerHandler) ListPayouts(c *gin.Context) {
+	sellerID, _ := strconv.ParseInt(c.Query("seller_id"), 10, 64)
+
+	h.listPayouts(c, sellerID)
+}
+
+func (h *LedgerHandler) listPayouts(c *gin.Context, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListPayouts(ctx, &pb.ListPayoutsRequest{
+		SellerId: sellerID,
+		BatchId:  c.Query("batch_id"),
+		Status:   c.Query("status"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	payouts := resp.Payouts
+	if payouts == nil {
+		payouts = []*pb.Payout{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payouts": payouts,
+		"total":   resp.Total,
+	})
+}
+
+// CreatePayoutBatch runs a payout batch now instead of waiting for the
+// schedule.
+func (h *LedgerHandler) CreatePayoutBatch(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(requestContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.client.CreatePayoutBatch(ctx, &pb.CreatePayoutBatchRequest{})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Batch)
+}
+
+func (h *LedgerHandler) GetPayoutBatch(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetPayoutBatch(ctx, &pb.GetPayoutBatchRequest{
+		Id: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Batch)
+}
+
+// ChangePayoutStatus returns a handler marking a pending payout status,
+// either paid or failed. Failing needs a reason.
+func (h *LedgerHandler) ChangePayoutStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Reason string `json:"reason" binding:"max=500"`
+		}
+
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				problem.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		ctx := requestContext(c)
+
+		resp, err := h.client.UpdatePayoutStatus(ctx, &pb.UpdatePayoutStatusRequest{
+			Id:     c.Param("id"),
+			Status: status,
+			Reason: req.Reason,
+		})
+
+		if err != nil {
+			problem.GRPC(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp.Payout)
+	}
+}
+
+// GetReconciliationReport reports ledger totals between the RFC 3339 times
+// in the from and to query parameters, the last day by default.
+func (h *LedgerHandler) GetReconciliationReport(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(requestContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.client.GetReconciliationReport(ctx, &pb.GetReconciliationReportRequest{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Report)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// traceHeaders are the W3C trace context headers passed on to upstreams as
+// they came, so that upstream spans join the caller's trace.
+var traceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+// requestContext is the context for the upstream calls of a request. It is
+// canceled when the client goes away and carries the request ID, the
+// authenticated user and the trace headers as gRPC metadata.
+func requestContext(c *gin.Context) context.Context {
+	md := metadata.MD{}
+
+	if requestID := c.GetString("request_id"); requestID != "" {
+		md.Set("x-request-id", requestID)
+	}
+
+	if userID := c.GetInt64("user_id"); userID != 0 {
+		md.Set("x-user-id", strconv.FormatInt(userID, 10))
+		md.Set("x-user-role", c.GetString("role"))
+	}
+
+	for _, name := range traceHeaders {
+		if value := c.GetHeader(name); value != "" {
+			md.Set(name, value)
+		}
+	}
+
+	return metadata.NewOutgoingContext(c.Request.Context(), md)
+}
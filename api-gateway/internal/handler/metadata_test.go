@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		set    map[string]interface{}
+		header map[string]string
+		want   metadata.MD
+	}{
+		{
+			name:   "anonymous",
+			set:    map[string]interface{}{"request_id": "req-1"},
+			header: map[string]string{"X-User-ID": "1"},
+			want:   metadata.MD{"x-request-id": {"req-1"}},
+		},
+		{
+			name: "authenticated",
+			set:  map[string]interface{}{"request_id": "req-1", "user_id": int64(7), "role": "seller"},
+			want: metadata.MD{"x-request-id": {"req-1"}, "x-user-id": {"7"}, "x-user-role": {"seller"}},
+		},
+		{
+			name: "trace headers",
+			header: map[string]string{
+				"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"Tracestate":  "vendor=value",
+				"Baggage":     "tenant=1",
+				"Cookie":      "session=secret",
+			},
+			want: metadata.MD{
+				"traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"tracestate":  {"vendor=value"},
+				"baggage":     {"tenant=1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+			for name, value := range tt.header {
+				c.Request.Header.Set(name, value)
+			}
+			for key, value := range tt.set {
+				c.Set(key, value)
+			}
+
+			md, _ := metadata.FromOutgoingContext(requestContext(c))
+			if len(md) != len(tt.want) {
+				t.Errorf("requestContext() metadata = %v, want %v", md, tt.want)
+			}
+			for key, want := range tt.want {
+				if got := md.Get(key); len(got) != 1 || got[0] != want[0] {
+					t.Errorf("requestContext() metadata %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestContextCanceledWithRequest(t *testing.T) {
+	requestCtx, cancel := context.WithCancel(context.Background())
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products", nil).WithContext(requestCtx)
+
+	ctx := requestContext(c)
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("requestContext() has a deadline, want the upstream policy to set one")
+	}
+
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("requestContext() not canceled with the request")
+	}
+}
+
+// grpcCall is what a gRPC upstream saw of a call.
+type grpcCall struct {
+	md       metadata.MD
+	deadline time.Duration
+	canceled bool
+}
+
+// recordingUpstream is a gRPC server answering every method with an empty
+// JSON message and sending what it saw of each call to calls. Calls with a
+// "hold" field wait until they are canceled.
+func recordingUpstream(t *testing.T) (string, <-chan grpcCall) {
+	calls := make(chan grpcCall, 1)
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			ctx := stream.Context()
+			var req map[string]interface{}
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+
+			call := grpcCall{}
+			call.md, _ = metadata.FromIncomingContext(ctx)
+			if deadline, ok := ctx.Deadline(); ok {
+				call.deadline = time.Until(deadline)
+			}
+			if _, ok := req["hold"]; ok {
+				<-ctx.Done()
+				call.canceled = true
+			}
+			calls <- call
+
+			return stream.SendMsg(json.RawMessage(`{}`))
+		}),
+	)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String(), calls
+}
+
+func TestProxyGRPCMetadata(t *testing.T) {
+	addr, calls := recordingUpstream(t)
+	table := parseTestTable(t, `
+upstreams:
+  product:
+    protocol: grpc
+    url: `+addr+`
+routes:
+  - name: products
+    path_prefix: /api/v1/products
+    upstream: product
+    grpc_method: /product.ProductService/ListProducts
+    auth: optional
+    headers:
+      x-gateway: micro-commerce
+policies:
+  product:
+    timeout: 3s
+`)
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	r := newTestProxy(h)
+	r.Use(func(c *gin.Context) {
+		c.Set("request_id", c.GetHeader("X-Request-ID"))
+	})
+	gateway := httptest.NewServer(r)
+	defer gateway.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, gateway.URL+"/api/v1/products", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.Header.Set("Authorization", "Bearer 7 customer")
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	call := <-calls
+	for key, want := range map[string]string{
+		"x-request-id": "req-1",
+		"x-user-id":    "7",
+		"x-user-role":  "customer",
+		"traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"x-gateway":    "micro-commerce",
+	} {
+		if got := call.md.Get(key); len(got) != 1 || got[0] != want {
+			t.Errorf("upstream metadata %s = %v, want %q", key, got, want)
+		}
+	}
+	// The policy's timeout bounds the attempt.
+	if call.deadline <= 2*time.Second || call.deadline > 3*time.Second {
+		t.Errorf("upstream deadline in %v, want about 3s", call.deadline)
+	}
+
+	// A client that goes away cancels the upstream call.
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ = http.NewRequestWithContext(ctx, http.MethodPost, gateway.URL+"/api/v1/products", strings.NewReader(`{"hold":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case call := <-calls:
+		if !call.canceled {
+			t.Error("upstream call not canceled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream call still running after the client went away")
+	}
+	<-done
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+)
+
+type OrderHandler struct {
+	client pb.OrderServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewOrderHandler(orderServiceURL string, opts ...grpc.DialOption) (*OrderHandler, error) {
+	conn, err := grpc.NewClient(orderServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderHandler{
+		client: pb.NewOrderServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *OrderHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// ListMyOrders lists the orders placed by the calling customer.
+func (h *OrderHandler) ListMyOrders(c *gin.Context) {
+	h.listOrders(c, c.GetInt64("user_id"), 0)
+}
+
+// ListSellerOrders lists the orders the calling seller has to fulfil.
+func (h *OrderHandler) ListSellerOrders(c *gin.Context) {
+	h.listOrders(c, 0, c.GetInt64("user_id"))
+}
+
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	customerID, _ := strconv.ParseInt(c.Query("customer_id"), 10, 64)
+	sellerID, _ := strconv.ParseInt(c.Query("seller_id"), 10, 64)
+
+	h.listOrders(c, customerID, sellerID)
+}
+
+func (h *OrderHandler) listOrders(c *gin.Context, customerID, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListOrders(ctx, &pb.ListOrdersRequest{
+		CustomerId: customerID,
+		SellerId:   sellerID,
+		CheckoutId: c.Query("checkout_id"),
+		Status:     c.Query("status"),
+		Page:       int32(page),
+		PageSize:   int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	orders := resp.Orders
+	if orders == nil {
+		orders = []*pb.Order{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"total":  resp.Total,
+	})
+}
+
+func (h *OrderHandler) GetMyOrder(c *gin.Context) {
+	h.getOrder(c, c.GetInt64("user_id"), 0)
+}
+
+func (h *OrderHandler) GetSellerOrder(c *gin.Context) {
+	h.getOrder(c, 0, c.GetInt64("user_id"))
+}
+
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	h.getOrder(c, 0, 0)
+}
+
+func (h *OrderHandler) getOrder(c *gin.Context, customerID, sellerID int64) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetOrder(ctx, &pb.GetOrderRequest{
+		Id:         c.Param("id"),
+		CustomerId: customerID,
+		SellerId:   sellerID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) GetMyOrderHistory(c *gin.Context) {
+	h.getOrderHistory(c, c.GetInt64("user_id"), 0)
+}
+
+func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
+	h.getOrderHistory(c, 0, 0)
+}
+
+func (h *OrderHandler) getOrderHistory(c *gin.Context, customerID, sellerID int64) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetOrderHistory(ctx, &pb.GetOrderHistoryRequest{
+		Id:         c.Param("id"),
+		CustomerId: customerID,
+		SellerId:   sellerID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	changes := resp.Changes
+	if changes == nil {
+		changes = []*pb.OrderStatusChange{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+	})
+}
+
+// CancelMyOrder lets a customer cancel one of their orders before it is
+// fulfilled.
+func (h *OrderHandler) CancelMyOrder(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.changeStatus(c, "cancelled", "customer", req.Reason)
+}
+
+// ChangeSellerOrderStatus returns a handler moving one of the calling
+// seller's orders to status.
+func (h *OrderHandler) ChangeSellerOrderStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Reason string `json:"reason" binding:"max=500"`
+		}
+
+		// The reason is only needed for cancellations, so an empty body is fine.
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				problem.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		h.changeStatus(c, status, "seller", req.Reason)
+	}
+}
+
+func (h *OrderHandler) ChangeOrderStatus(c *gin.Context) {
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason" binding:"max=500"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.changeStatus(c, req.Status, "admin", req.Reason)
+}
+
+func (h *OrderHandler) changeStatus(c *gin.Context, status, role, reason string) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ChangeOrderStatus(ctx, &pb.ChangeOrderStatusRequest{
+		Id:        c.Param("id"),
+		Status:    status,
+		ActorId:   c.GetInt64("user_id"),
+		ActorRole: role,
+		Reason:    reason,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Order)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/payment-service/proto"
+)
+
+// maxWebhookBodySize bounds provider webhook payloads, which are small JSON
+// documents.
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	client pb.PaymentServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewPaymentHandler(paymentServiceURL string, opts ...grpc.DialOption) (*PaymentHandler, error) {
+	conn, err := grpc.NewClient(paymentServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentHandler{
+		client: pb.NewPaymentServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *PaymentHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// HandleWebhook passes a provider webhook through untouched; the payment
+// service checks the signature over the exact bytes received.
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		problem.Error(c, http.StatusRequestEntityTooLarge, "webhook payload too large")
+		return
+	}
+
+	headers := make(map[string]string, len(c.Request.Header))
+	for key := range c.Request.Header {
+		headers[key] = c.Request.Header.Get(key)
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.HandleWebhook(ctx, &pb.HandleWebhookRequest{
+		Provider: c.Param("provider"),
+		Payload:  payload,
+		Headers:  headers,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applied": resp.Applied,
+	})
+}
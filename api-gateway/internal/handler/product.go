@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+)
+
+type ProductHandler struct {
+	client pb.ProductServiceClient
+	conn   *grpc.ClientConn
+}
+
+// moneyRequest is an amount in minor units of an ISO 4217 currency.
+type moneyRequest struct {
+	Amount   int64  `json:"amount" binding:"gte=0"`
+	Currency string `json:"currency" binding:"required,len=3"`
+}
+
+type variantRequest struct {
+	SKU         string         `json:"sku" binding:"required,max=64"`
+	Size        string         `json:"size" binding:"max=50"`
+	Color       string         `json:"color" binding:"max=50"`
+	Price       int64          `json:"price" binding:"gt=0"`
+	WeightGrams int32          `json:"weight_grams" binding:"gte=0"`
+	Prices      []moneyRequest `json:"prices" binding:"dive"`
+}
+
+func (v variantRequest) toProto() *pb.VariantInput {
+	return &pb.VariantInput{
+		Sku:         v.SKU,
+		Size:        v.Size,
+		Color:       v.Color,
+		Price:       v.Price,
+		WeightGrams: v.WeightGrams,
+		Prices:      toProtoMoney(v.Prices),
+	}
+}
+
+func toProtoMoney(prices []moneyRequest) []*pb.Money {
+	result := make([]*pb.Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, &pb.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return result
+}
+
+func NewProductHandler(productServiceURL string, opts ...grpc.DialOption) (*ProductHandler, error) {
+	conn, err := grpc.NewClient(productServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductHandler{
+		client: pb.NewProductServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *ProductHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+func (h *ProductHandler) ListCategories(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	categories := resp.Categories
+	if categories == nil {
+		categories = []*pb.Category{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+	})
+}
+
+func (h *ProductHandler) CreateCategory(c *gin.Context) {
+	var req struct {
+		ParentID    string `json:"parent_id"`
+		Name        string `json:"name" binding:"required,max=100"`
+		TaxCategory string `json:"tax_category" binding:"max=30"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateCategory(ctx, &pb.CreateCategoryRequest{
+		ParentId:    req.ParentID,
+		Name:        req.Name,
+		TaxCategory: req.TaxCategory,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Category)
+}
+
+// ListProducts is the public catalog listing; only published products are
+// returned.
+func (h *ProductHandler) ListProducts(c *gin.Context) {
+	h.listProducts(c, 0)
+}
+
+// ListMyProducts lists the calling seller's products, drafts included.
+func (h *ProductHandler) ListMyProducts(c *gin.Context) {
+	h.listProducts(c, c.GetInt64("user_id"))
+}
+
+func (h *ProductHandler) listProducts(c *gin.Context, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListProducts(ctx, &pb.ListProductsRequest{
+		CategoryId: c.Query("category_id"),
+		StoreId:    c.Query("store_id"),
+		Query:      c.Query("q"),
+		SellerId:   sellerID,
+		Page:       int32(page),
+		PageSize:   int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	products := resp.Products
+	if products == nil {
+		products = []*pb.Product{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"total":    resp.Total,
+	})
+}
+
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetProduct(ctx, &pb.GetProductRequest{
+		Id: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+// GetMyProduct returns one of the calling seller's products, even a draft.
+func (h *ProductHandler) GetMyProduct(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetProduct(ctx, &pb.GetProductRequest{
+		Id:       c.Param("id"),
+		SellerId: c.GetInt64("user_id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var req struct {
+		CategoryID  string           `json:"category_id"`
+		Name        string           `json:"name" binding:"required,min=3,max=200"`
+		Description string           `json:"description" binding:"max=5000"`
+		Variants    []variantRequest `json:"variants" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	variants := make([]*pb.VariantInput, 0, len(req.Variants))
+	for _, v := range req.Variants {
+		variants = append(variants, v.toProto())
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateProduct(ctx, &pb.CreateProductRequest{
+		SellerId:    c.GetInt64("user_id"),
+		StoreId:     c.GetString("store_id"),
+		CategoryId:  req.CategoryID,
+		Name:        req.Name,
+		Description: req.Description,
+		Variants:    variants,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Product)
+}
+
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	var req struct {
+		CategoryID  *string `json:"category_id"`
+		Name        *string `json:"name" binding:"omitempty,min=3,max=200"`
+		Description *string `json:"description" binding:"omitempty,max=5000"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		SellerId:    c.GetInt64("user_id"),
+		Id:          c.Param("id"),
+		CategoryId:  req.CategoryID,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) PublishProduct(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.PublishProduct(ctx, &pb.ChangeProductStatusRequest{
+		SellerId: c.GetInt64("user_id"),
+		Id:       c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) UnpublishProduct(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.UnpublishProduct(ctx, &pb.ChangeProductStatusRequest{
+		SellerId: c.GetInt64("user_id"),
+		Id:       c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	ctx := requestContext(c)
+
+	_, err := h.client.DeleteProduct(ctx, &pb.DeleteProductRequest{
+		SellerId: c.GetInt64("user_id"),
+		Id:       c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductHandler) AddVariant(c *gin.Context) {
+	var req variantRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.AddVariant(ctx, &pb.AddVariantRequest{
+		SellerId:  c.GetInt64("user_id"),
+		ProductId: c.Param("id"),
+		Variant:   req.toProto(),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Product)
+}
+
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	var req struct {
+		Price       *int64 `json:"price" binding:"omitempty,gt=0"`
+		WeightGrams *int32 `json:"weight_grams" binding:"omitempty,gte=0"`
+		IsActive    *bool  `json:"is_active"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdateVariant(ctx, &pb.UpdateVariantRequest{
+		SellerId:    c.GetInt64("user_id"),
+		ProductId:   c.Param("id"),
+		VariantId:   c.Param("variant_id"),
+		Price:       req.Price,
+		WeightGrams: req.WeightGrams,
+		IsActive:    req.IsActive,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.DeleteVariant(ctx, &pb.DeleteVariantRequest{
+		SellerId:  c.GetInt64("user_id"),
+		ProductId: c.Param("id"),
+		VariantId: c.Param("variant_id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+// SetVariantPrices replaces the prices a seller sets for a variant in
+// currencies other than the base currency.
+func (h *ProductHandler) SetVariantPrices(c *gin.Context) {
+	var req struct {
+		Prices []moneyRequest `json:"prices" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.SetVariantPrices(ctx, &pb.SetVariantPricesRequest{
+		SellerId:  c.GetInt64("user_id"),
+		ProductId: c.Param("id"),
+		VariantId: c.Param("variant_id"),
+		Prices:    toProtoMoney(req.Prices),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+func (h *ProductHandler) ListExchangeRates(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListExchangeRates(ctx, &pb.ListExchangeRatesRequest{})
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	rates := resp.Rates
+	if rates == nil {
+		rates = []*pb.ExchangeRate{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rates": rates,
+	})
+}
+
+// SetExchangeRates records new rates from the base currency. Rates that
+// did not change are kept as they are.
+func (h *ProductHandler) SetExchangeRates(c *gin.Context) {
+	var req struct {
+		BaseCurrency string `json:"base_currency" binding:"required,len=3"`
+		Source       string `json:"source" binding:"max=50"`
+		Rates        []struct {
+			Currency string `json:"currency" binding:"required,len=3"`
+			Rate     string `json:"rate" binding:"required,max=25"`
+		} `json:"rates" binding:"required,min=1,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rates := make([]*pb.ExchangeRateInput, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		rates = append(rates, &pb.ExchangeRateInput{
+			Currency: rate.Currency,
+			Rate:     rate.Rate,
+		})
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.SetExchangeRates(ctx, &pb.SetExchangeRatesRequest{
+		BaseCurrency: req.BaseCurrency,
+		Rates:        rates,
+		Source:       req.Source,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rates": resp.Rates,
+	})
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/promotions-service/proto"
+)
+
+type PromotionsHandler struct {
+	client pb.PromotionsServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewPromotionsHandler(promotionsServiceURL string, opts ...grpc.DialOption) (*PromotionsHandler, error) {
+	conn, err := grpc.NewClient(promotionsServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PromotionsHandler{
+		client: pb.NewPromotionsServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *PromotionsHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// promotionTerms is the editable part of a promotion. Amounts are in minor
+// units; value is a percentage for percentage promotions.
+type promotionTerms struct {
+	Name             string   `json:"name" binding:"required,max=200"`
+	Value            int64    `json:"value" binding:"min=0"`
+	MaxDiscount      int64    `json:"max_discount" binding:"min=0"`
+	MinSubtotal      int64    `json:"min_subtotal" binding:"min=0"`
+	StartsAt         string   `json:"starts_at"`
+	EndsAt           string   `json:"ends_at"`
+	UsageLimit       int32    `json:"usage_limit" binding:"min=0"`
+	PerCustomerLimit int32    `json:"per_customer_limit" binding:"min=0"`
+	ProductIDs       []string `json:"product_ids" binding:"max=100"`
+	CategoryIDs      []string `json:"category_ids" binding:"max=100"`
+	SellerIDs        []int64  `json:"seller_ids" binding:"max=100"`
+	Stackable        bool     `json:"stackable"`
+	Priority         int32    `json:"priority"`
+}
+
+func (t *promotionTerms) toProto() *pb.PromotionInput {
+	return &pb.PromotionInput{
+		Name:             t.Name,
+		Value:            t.Value,
+		MaxDiscount:      t.MaxDiscount,
+		MinSubtotal:      t.MinSubtotal,
+		StartsAt:         t.StartsAt,
+		EndsAt:           t.EndsAt,
+		UsageLimit:       t.UsageLimit,
+		PerCustomerLimit: t.PerCustomerLimit,
+		ProductIds:       t.ProductIDs,
+		CategoryIds:      t.CategoryIDs,
+		SellerIds:        t.SellerIDs,
+		Stackable:        t.Stackable,
+		Priority:         t.Priority,
+	}
+}
+
+// CreatePromotion creates a coupon, or an automatic promotion when no code
+// is given.
+func (h *PromotionsHandler) CreatePromotion(c *gin.Context) {
+	var req struct {
+		promotionTerms
+		Code   string `json:"code" binding:"max=32"`
+		Type   string `json:"type" binding:"required,oneof=percentage fixed free_shipping"`
+		Active bool   `json:"active"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreatePromotion(ctx, &pb.CreatePromotionRequest{
+		Code:   req.Code,
+		Type:   req.Type,
+		Terms:  req.promotionTerms.toProto(),
+		Active: req.Active,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Promotion)
+}
+
+// UpdatePromotion replaces the terms of a promotion. Its code and type
+// cannot change.
+func (h *PromotionsHandler) UpdatePromotion(c *gin.Context) {
+	var req promotionTerms
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdatePromotion(ctx, &pb.UpdatePromotionRequest{
+		Id:    c.Param("id"),
+		Terms: req.toProto(),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Promotion)
+}
+
+// SetPromotionActive returns a handler switching a promotion on or off.
+func (h *PromotionsHandler) SetPromotionActive(active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := requestContext(c)
+
+		resp, err := h.client.SetPromotionActive(ctx, &pb.SetPromotionActiveRequest{
+			Id:     c.Param("id"),
+			Active: active,
+		})
+
+		if err != nil {
+			problem.GRPC(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp.Promotion)
+	}
+}
+
+func (h *PromotionsHandler) GetPromotion(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetPromotion(ctx, &pb.GetPromotionRequest{
+		Id: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Promotion)
+}
+
+func (h *PromotionsHandler) ListPromotions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListPromotions(ctx, &pb.ListPromotionsRequest{
+		Code:     c.Query("code"),
+		State:    c.Query("state"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	promotions := resp.Promotions
+	if promotions == nil {
+		promotions = []*pb.Promotion{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promotions": promotions,
+		"total":      resp.Total,
+	})
+}
@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/metrics"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// proxyRouteKey and proxyRoutesKey are where Match leaves the matched route,
+// and Pin the route table the request is served with, in the context.
+const (
+	proxyRouteKey  = "proxy_route"
+	proxyRoutesKey = "proxy_routes"
+)
+
+// maxProxyBody caps the JSON body turned into a gRPC request message.
+const maxProxyBody = 1 << 20
+
+// ProxyHandler serves the routes of a route table by forwarding them to
+// their upstreams, HTTP ones through a reverse proxy and gRPC ones with the
+// request message sent as JSON. The table can be replaced while requests are
+// being served; see Reload.
+type ProxyHandler struct {
+	active    atomic.Pointer[proxyRoutes]
+	upstreams *resilience.Upstreams
+
+	// mu serializes reloads and guards the reserved paths and reload
+	// status below.
+	mu          sync.Mutex
+	reserved    []string
+	loadedAt    time.Time
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// proxyRoutes is a route table ready to serve, with the clients of its
+// upstreams. Its routes, rate limits and policies are never changed once it
+// is built; a reload builds a new one. refs counts the requests pinning it,
+// and once it has been replaced the last of them closes its connections.
+type proxyRoutes struct {
+	table *config.RouteTable
+	http  map[string]*httputil.ReverseProxy
+	grpc  map[string]*grpc.ClientConn
+
+	refs      atomic.Int64
+	retired   atomic.Bool
+	closeOnce sync.Once
+}
+
+func NewProxyHandler(table *config.RouteTable, upstreams *resilience.Upstreams) (*ProxyHandler, error) {
+	routes, err := newProxyRoutes(table, upstreams)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &ProxyHandler{upstreams: upstreams, loadedAt: time.Now()}
+	h.active.Store(routes)
+	return h, nil
+}
+
+func newProxyRoutes(table *config.RouteTable, upstreams *resilience.Upstreams) (*proxyRoutes, error) {
+	p := &proxyRoutes{
+		table: table,
+		http:  map[string]*httputil.ReverseProxy{},
+		grpc:  map[string]*grpc.ClientConn{},
+	}
+
+	for _, route := range table.Routes {
+		upstream := table.Upstreams[route.Upstream]
+		switch upstream.Protocol {
+		case config.ProtocolHTTP:
+			target, err := url.Parse(upstream.URL)
+			if err != nil {
+				p.close()
+				return nil, err
+			}
+			p.http[route.Name] = newReverseProxy(route, target,
+				metrics.Transport(route.Upstream, upstreams.Transport(route.Upstream, http.DefaultTransport)))
+		case config.ProtocolGRPC:
+			if _, ok := p.grpc[route.Upstream]; ok {
+				continue
+			}
+			conn, err := grpc.NewClient(upstream.URL,
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+				metrics.DialOption(route.Upstream),
+				upstreams.DialOption(route.Upstream),
+			)
+			if err != nil {
+				p.close()
+				return nil, err
+			}
+			p.grpc[route.Upstream] = conn
+		}
+	}
+
+	return p, nil
+}
+
+func (p *proxyRoutes) close() {
+	p.closeOnce.Do(func() {
+		for _, conn := range p.grpc {
+			conn.Close()
+		}
+	})
+}
+
+func (h *ProxyHandler) Close() {
+	h.active.Load().retire()
+}
+
+// Match finds the route with the longest path prefix for the request and
+// puts it in the context, or answers 404 when there is none. The request
+// path is cleaned first, so the upstream is sent the path that matched. It
+// must run after Pin.
+func (h *ProxyHandler) Match(c *gin.Context) {
+	active := pinnedRoutes(c)
+	if active == nil {
+		problem.Error(c, http.StatusInternalServerError, "route table not pinned")
+		return
+	}
+
+	path := config.CleanPath(c.Request.URL.Path)
+	c.Request.URL.Path = path
+	c.Request.URL.RawPath = ""
+
+	var matched *config.Route
+	methodMismatch := false
+	for _, route := range active.table.Routes {
+		if !route.MatchesPath(path) {
+			continue
+		}
+		if !route.AllowsMethod(c.Request.Method) {
+			methodMismatch = true
+			continue
+		}
+		if matched == nil || len(route.PathPrefix) > len(matched.PathPrefix) {
+			matched = route
+		}
+	}
+
+	if matched == nil {
+		if methodMismatch {
+			problem.Error(c, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		problem.Error(c, http.StatusNotFound, "route not found")
+		return
+	}
+
+	c.Set(proxyRouteKey, matched)
+	c.Next()
+}
+
+// ProxyRoute returns the route Match found for the request.
+func ProxyRoute(c *gin.Context) *config.Route {
+	route, _ := c.Get(proxyRouteKey)
+	matched, _ := route.(*config.Route)
+	return matched
+}
+
+// Serve forwards the request to the upstream of its route. It must run
+// after Match and whatever authentication the route asks for.
+func (h *ProxyHandler) Serve(c *gin.Context) {
+	route := ProxyRoute(c)
+	active := pinnedRoutes(c)
+	if route == nil || active == nil {
+		problem.Error(c, http.StatusNotFound, "route not found")
+		return
+	}
+
+	if len(route.Roles) > 0 && !hasRole(route.Roles, c.GetString("role")) {
+		problem.Error(c, http.StatusForbidden, "insufficient permission")
+		return
+	}
+
+	if proxy, ok := active.http[route.Name]; ok {
+		h.serveHTTP(c, route, proxy)
+		return
+	}
+	h.serveGRPC(c, route, active.grpc[route.Upstream])
+}
+
+func (h *ProxyHandler) serveHTTP(c *gin.Context, route *config.Route, proxy *httputil.ReverseProxy) {
+	// Identity headers only ever come from the gateway.
+	header := c.Request.Header
+	header.Del("X-User-ID")
+	header.Del("X-User-Role")
+	if userID := c.GetInt64("user_id"); userID != 0 {
+		header.Set("X-User-ID", strconv.FormatInt(userID, 10))
+		header.Set("X-User-Role", c.GetString("role"))
+	}
+	for name, value := range route.Headers {
+		header.Set(name, value)
+	}
+
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *ProxyHandler) serveGRPC(c *gin.Context, route *config.Route, conn *grpc.ClientConn) {
+	message, err := requestMessage(c, route)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+	for name, value := range route.Headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, name, value)
+	}
+
+	var resp json.RawMessage
+	err = conn.Invoke(ctx, route.GRPCMethod, message, &resp, grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+	if len(resp) == 0 {
+		resp = json.RawMessage("{}")
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", resp)
+}
+
+// requestMessage builds the JSON request message of a gRPC route from the
+// query string, or from the body for methods that carry one.
+func requestMessage(c *gin.Context, route *config.Route) (json.RawMessage, error) {
+	fields := map[string]interface{}{}
+
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxProxyBody+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxProxyBody {
+			return nil, fmt.Errorf("request body must be at most %d bytes", maxProxyBody)
+		}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &fields); err != nil {
+				return nil, fmt.Errorf("request body must be a JSON object")
+			}
+		}
+	default:
+		for name, values := range c.Request.URL.Query() {
+			if len(values) == 1 {
+				fields[name] = values[0]
+			} else {
+				fields[name] = values
+			}
+		}
+	}
+
+	if route.UserIDField != "" {
+		if userID := c.GetInt64("user_id"); userID != 0 {
+			fields[route.UserIDField] = userID
+		} else {
+			delete(fields, route.UserIDField)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+func newReverseProxy(route *config.Route, target *url.URL, transport http.RoundTripper) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Transport: transport,
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Path = route.UpstreamPath(r.In.URL.Path)
+			r.Out.URL.RawPath = ""
+			r.SetURL(target)
+			r.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			slog.ErrorContext(r.Context(), "Proxy request failed", slog.String("route", route.Name), slog.Any("error", err))
+			code := http.StatusBadGateway
+			if errors.Is(err, resilience.ErrCircuitOpen) || errors.Is(err, resilience.ErrBulkheadFull) {
+				code = http.StatusServiceUnavailable
+			}
+			problem.Write(w, r, problem.New(code, "upstream unavailable"))
+		},
+	}
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, allowed := range roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonCodec sends gRPC messages as JSON, which go-micro servers accept as
+// application/grpc+json, so routes need no generated client code.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	switch message := v.(type) {
+	case json.RawMessage:
+		return message, nil
+	case *json.RawMessage:
+		return *message, nil
+	default:
+		return json.Marshal(v)
+	}
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if message, ok := v.(*json.RawMessage); ok {
+		*message = append((*message)[:0], data...)
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Pin holds the active route table for the rest of the request and puts it
+// in the request context, so that the request's rate limits, upstream
+// policies and route all come from one version of the table, and the
+// upstream connections of that version stay open until the request is done.
+func (h *ProxyHandler) Pin(c *gin.Context) {
+	active := h.acquire()
+	defer active.release()
+
+	c.Set(proxyRoutesKey, active)
+	c.Request = c.Request.WithContext(config.WithTable(c.Request.Context(), active.table))
+	c.Next()
+}
+
+func pinnedRoutes(c *gin.Context) *proxyRoutes {
+	value, _ := c.Get(proxyRoutesKey)
+	active, _ := value.(*proxyRoutes)
+	return active
+}
+
+// acquire pins the active route table. A table replaced between loading and
+// counting it is let go and the new one pinned instead, so a retired table
+// only ever loses references.
+func (h *ProxyHandler) acquire() *proxyRoutes {
+	for {
+		active := h.active.Load()
+		active.refs.Add(1)
+		if h.active.Load() == active {
+			return active
+		}
+		active.release()
+	}
+}
+
+func (p *proxyRoutes) release() {
+	if p.refs.Add(-1) == 0 && p.retired.Load() {
+		p.close()
+	}
+}
+
+// retire marks a replaced route table to be closed once no request pins it.
+func (p *proxyRoutes) retire() {
+	p.retired.Store(true)
+	if p.refs.Load() == 0 {
+		p.close()
+	}
+}
+
+// Reserve sets the paths of the gateway's own routes, which route tables
+// may not overlap, and checks the active table against them.
+func (h *ProxyHandler) Reserve(paths []string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.reserved = paths
+	return h.active.Load().table.CheckReserved(paths)
+}
+
+// Reload makes table, with its routes, rate limits and upstream policies,
+// the active route table for requests from then on. Requests already pinned
+// finish on the table they started with, whose upstream connections are
+// closed once the last of them is done. When the table overlaps the reserved
+// paths or its upstreams cannot be set up the active table is kept and the
+// error is returned.
+func (h *ProxyHandler) Reload(table *config.RouteTable) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := table.CheckReserved(h.reserved); err != nil {
+		h.failed(err)
+		return err
+	}
+
+	routes, err := newProxyRoutes(table, h.upstreams)
+	if err != nil {
+		h.failed(err)
+		return err
+	}
+
+	h.active.Swap(routes).retire()
+	h.loadedAt = time.Now()
+	h.lastError = ""
+	h.lastErrorAt = time.Time{}
+
+	return nil
+}
+
+// Watch reloads the route table at path whenever its content changes, checked
+// every interval, and whenever reload receives, until ctx is done. A table
+// that fails to load or validate leaves the active one in place; a changed
+// file is only retried once its content changes again, a reload signal
+// always retries. An interval of 0 only reloads on signals.
+func (h *ProxyHandler) Watch(ctx context.Context, path string, interval time.Duration, reload <-chan os.Signal) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	tried := h.Version()
+	for {
+		force := false
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-reload:
+			force = true
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if force {
+				h.reloadFailed(path, err)
+			}
+			continue
+		}
+
+		version := config.RouteTableVersion(data)
+		if !force && version == tried {
+			continue
+		}
+		tried = version
+
+		table, err := config.ParseRouteTable(path, data)
+		if err != nil {
+			h.reloadFailed(path, err)
+			continue
+		}
+		if err := h.Reload(table); err != nil {
+			logReloadFailed(path, h.Version(), err)
+			continue
+		}
+		slog.Info("Reloaded routes",
+			slog.String("file", path),
+			slog.Int("routes", len(table.Routes)),
+			slog.String("routes_version", table.Version),
+		)
+	}
+}
+
+func (h *ProxyHandler) reloadFailed(path string, err error) {
+	h.mu.Lock()
+	h.failed(err)
+	h.mu.Unlock()
+
+	logReloadFailed(path, h.Version(), err)
+}
+
+func logReloadFailed(path, keptVersion string, err error) {
+	slog.Error("Failed to reload routes",
+		slog.String("file", path),
+		slog.String("kept_version", keptVersion),
+		slog.Any("error", err),
+	)
+}
+
+// failed records a reload error; h.mu must be held.
+func (h *ProxyHandler) failed(err error) {
+	h.lastError = err.Error()
+	h.lastErrorAt = time.Now()
+}
+
+// Version is the version of the active route table.
+func (h *ProxyHandler) Version() string {
+	return h.active.Load().table.Version
+}
+
+// GetConfig shows the active route table and the outcome of the last reload.
+func (h *ProxyHandler) GetConfig(c *gin.Context) {
+	active := h.active.Load()
+
+	h.mu.Lock()
+	loadedAt, lastError, lastErrorAt := h.loadedAt, h.lastError, h.lastErrorAt
+	h.mu.Unlock()
+
+	routes := make([]gin.H, 0, len(active.table.Routes))
+	for _, route := range active.table.Routes {
+		routes = append(routes, gin.H{
+			"name":        route.Name,
+			"path_prefix": route.PathPrefix,
+			"methods":     route.Methods,
+			"upstream":    route.Upstream,
+			"auth":        route.Auth,
+		})
+	}
+
+	response := gin.H{
+		"version":   active.table.Version,
+		"loaded_at": loadedAt.UTC().Format(time.RFC3339),
+		"routes":    routes,
+	}
+	if lastError != "" {
+		response["last_reload_error"] = gin.H{
+			"error": lastError,
+			"at":    lastErrorAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUpstreams shows the circuit breaker state and call counts of every
+// upstream called so far.
+func (h *ProxyHandler) GetUpstreams(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"upstreams": h.upstreams.Stats(),
+	})
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/connectivity"
+)
+
+const grpcRoutesYAML = `
+upstreams:
+  product:
+    protocol: grpc
+    url: localhost:1
+routes:
+  - name: rates
+    path_prefix: /api/v1/exchange-rates
+    upstream: product
+    grpc_method: /product.ProductService/ListExchangeRates
+rate_limits:
+  - name: rates
+    path_prefix: /api/v1/exchange-rates
+    key: ip
+    limit: 10
+    period: 1m
+`
+
+func parseTestTable(t *testing.T, data string) *config.RouteTable {
+	t.Helper()
+	table, err := config.ParseRouteTable("routes.yaml", []byte(data))
+	if err != nil {
+		t.Fatalf("ParseRouteTable() error = %v", err)
+	}
+	return table
+}
+
+func TestReloadDrainsPinnedTable(t *testing.T) {
+	h, err := NewProxyHandler(parseTestTable(t, grpcRoutesYAML), resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	old := h.acquire()
+	conn := old.grpc["product"]
+
+	next := parseTestTable(t, strings.Replace(grpcRoutesYAML, "limit: 10", "limit: 20", 1))
+	if err := h.Reload(next); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if h.Version() != next.Version {
+		t.Errorf("Version() = %s, want %s", h.Version(), next.Version)
+	}
+
+	// The pinned table keeps its own limits and its connection.
+	if limits := old.table.RateLimitsFor(http.MethodGet, "/api/v1/exchange-rates"); len(limits) != 1 || limits[0].Limit != 10 {
+		t.Errorf("pinned table rate limits = %v, want the old limit of 10", limits)
+	}
+	if state := conn.GetState(); state == connectivity.Shutdown {
+		t.Fatal("old table's connection closed while a request still pins it")
+	}
+
+	old.release()
+	if state := conn.GetState(); state != connectivity.Shutdown {
+		t.Errorf("old table's connection state = %v after its last request, want %v", state, connectivity.Shutdown)
+	}
+
+	current := h.acquire()
+	defer current.release()
+	if current.grpc["product"].GetState() == connectivity.Shutdown {
+		t.Error("active table's connection is closed")
+	}
+}
+
+func TestReloadKeepsTableOnError(t *testing.T) {
+	table := parseTestTable(t, grpcRoutesYAML)
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+	if err := h.Reserve([]string{"/api/v1/me"}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	overlapping := parseTestTable(t, strings.Replace(grpcRoutesYAML, "path_prefix: /api/v1/exchange-rates\n    upstream", "path_prefix: /api/v1/me\n    upstream", 1))
+	if err := h.Reload(overlapping); err == nil {
+		t.Fatal("Reload() of a table overlapping a reserved path error = nil")
+	}
+	if h.Version() != table.Version {
+		t.Errorf("Version() = %s, want the kept %s", h.Version(), table.Version)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/gateway/config", nil)
+	h.GetConfig(c)
+	if !strings.Contains(w.Body.String(), "last_reload_error") || !strings.Contains(w.Body.String(), table.Version) {
+		t.Errorf("GetConfig() = %s, want the kept version and the reload error", w.Body)
+	}
+}
+
+func TestPinServesOneTable(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	table := testProxyTable(t, upstream.URL)
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	r := gin.New()
+	var seen []string
+	r.Use(h.Pin, func(c *gin.Context) {
+		seen = append(seen, config.TableFrom(c.Request.Context()).Version)
+		c.Next()
+	})
+	r.NoRoute(h.Match, h.Serve)
+	gateway := httptest.NewServer(r)
+	defer gateway.Close()
+
+	done := make(chan int)
+	go func() {
+		resp, err := http.Get(gateway.URL + "/api/v1/search/books")
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+
+	// Wait for the request to be pinned before reloading under it.
+	deadline := time.Now().Add(2 * time.Second)
+	for h.active.Load().refs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	empty := &config.RouteTable{Version: "empty"}
+	if err := h.Reload(empty); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	close(release)
+
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("request pinned before the reload status = %d, want %d", status, http.StatusOK)
+	}
+
+	resp, err := http.Get(gateway.URL + "/api/v1/search/books")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("request after the reload status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	if len(seen) != 2 || seen[0] != table.Version || seen[1] != "empty" {
+		t.Errorf("requests were served with tables %v, want %s then empty", seen, table.Version)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	lastError := func(h *ProxyHandler) string {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.lastError
+	}
+
+	write(grpcRoutesYAML)
+	table, err := config.LoadRouteTable(path)
+	if err != nil {
+		t.Fatalf("LoadRouteTable() error = %v", err)
+	}
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	go h.Watch(ctx, path, 10*time.Millisecond, signals)
+
+	changed := strings.Replace(grpcRoutesYAML, "limit: 10", "limit: 20", 1)
+	write(changed)
+	want := config.RouteTableVersion([]byte(changed))
+	waitFor("the changed file to load", func() bool { return h.Version() == want })
+
+	write("routes:\n  - name: broken\n")
+	waitFor("the broken file to be reported", func() bool { return lastError(h) != "" })
+	if h.Version() != want {
+		t.Errorf("Version() = %s after a broken file, want the kept %s", h.Version(), want)
+	}
+
+	// A signal retries the file even though it did not change.
+	h.mu.Lock()
+	h.lastError = ""
+	h.mu.Unlock()
+	signals <- os.Interrupt
+	waitFor("the signal to retry the broken file", func() bool { return lastError(h) != "" })
+
+	write(grpcRoutesYAML)
+	waitFor("the fixed file to load", func() bool { return h.Version() == table.Version })
+	if lastError(h) != "" {
+		t.Errorf("last reload error = %q after a good reload, want none", lastError(h))
+	}
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+)
+
+// echoUpstream answers every request with the path and headers it got.
+func echoUpstream(t *testing.T) *httptest.Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"path":    r.URL.Path,
+			"user":    r.Header.Get("X-User-ID"),
+			"role":    r.Header.Get("X-User-Role"),
+			"gateway": r.Header.Get("X-Gateway"),
+		})
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+// testProxyTable routes /api/v1/search to url, with /api/v1/search/admin
+// kept for admins.
+func testProxyTable(t *testing.T, url string) *config.RouteTable {
+	table := &config.RouteTable{
+		Upstreams: map[string]*config.Upstream{
+			"search": {Protocol: "http", URL: url},
+		},
+		Routes: []*config.Route{
+			{
+				Name:        "search",
+				PathPrefix:  "/api/v1/search",
+				Methods:     []string{"GET"},
+				Upstream:    "search",
+				StripPrefix: true,
+				Rewrite:     "/v1/query",
+				Auth:        config.AuthOptional,
+				Headers:     map[string]string{"X-Gateway": "micro-commerce"},
+			},
+			{
+				Name:       "search-admin",
+				PathPrefix: "/api/v1/search/admin",
+				Upstream:   "search",
+				Auth:       config.AuthRequired,
+				Roles:      []string{"admin"},
+			},
+		},
+	}
+	if err := table.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return table
+}
+
+// newTestProxy serves h behind an authentication stand-in that takes the
+// user from a "Bearer <id> <role>" header.
+func newTestProxy(h *ProxyHandler) *gin.Engine {
+	r := gin.New()
+	r.NoRoute(h.Pin, h.Match, func(c *gin.Context) {
+		fields := strings.Fields(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if len(fields) == 2 {
+			userID, _ := strconv.ParseInt(fields[0], 10, 64)
+			c.Set("user_id", userID)
+			c.Set("role", fields[1])
+		}
+		c.Next()
+	}, h.Serve)
+	return r
+}
+
+func TestProxy(t *testing.T) {
+	upstream := echoUpstream(t)
+	table := testProxyTable(t, upstream.URL)
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+	// The reverse proxy needs a real connection to write to.
+	gateway := httptest.NewServer(newTestProxy(h))
+	defer gateway.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+		want       map[string]string
+	}{
+		{
+			name:       "anonymous",
+			method:     http.MethodGet,
+			path:       "/api/v1/search/books",
+			wantStatus: http.StatusOK,
+			want:       map[string]string{"path": "/v1/query/books", "user": "", "role": "", "gateway": "micro-commerce"},
+		},
+		{
+			name:       "identity headers from the client are dropped",
+			method:     http.MethodGet,
+			path:       "/api/v1/search",
+			header:     map[string]string{"X-User-ID": "1", "X-User-Role": "admin"},
+			wantStatus: http.StatusOK,
+			want:       map[string]string{"path": "/v1/query", "user": "", "role": ""},
+		},
+		{
+			name:       "identity headers come from the token",
+			method:     http.MethodGet,
+			path:       "/api/v1/search/books",
+			header:     map[string]string{"Authorization": "Bearer 7 customer", "X-User-ID": "1"},
+			wantStatus: http.StatusOK,
+			want:       map[string]string{"user": "7", "role": "customer"},
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			path:       "/api/v1/search/books",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "no route",
+			method:     http.MethodGet,
+			path:       "/api/v1/nothing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "longest prefix wins",
+			method:     http.MethodGet,
+			path:       "/api/v1/search/admin/stats",
+			header:     map[string]string{"Authorization": "Bearer 7 customer"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "longest prefix wins for its role",
+			method:     http.MethodPost,
+			path:       "/api/v1/search/admin/stats",
+			header:     map[string]string{"Authorization": "Bearer 1 admin"},
+			wantStatus: http.StatusOK,
+			want:       map[string]string{"path": "/api/v1/search/admin/stats", "user": "1", "role": "admin"},
+		},
+		{
+			name:       "dot dot cannot leave the prefix",
+			method:     http.MethodGet,
+			path:       "/api/v1/search/../admin/users",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "dot dot cannot reach a stricter route",
+			method:     http.MethodGet,
+			path:       "/api/v1/search/books/../admin/stats",
+			header:     map[string]string{"Authorization": "Bearer 7 customer"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "cleaned path is forwarded",
+			method:     http.MethodGet,
+			path:       "/api/v1/search//books/./new",
+			wantStatus: http.StatusOK,
+			want:       map[string]string{"path": "/v1/query/books/new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, gateway.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s error = %v", tt.method, tt.path, err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("%s %s status = %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.want == nil {
+				return
+			}
+			var got map[string]string
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("upstream response %q: %v", body, err)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("upstream got %s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestProxyReserve(t *testing.T) {
+	table := testProxyTable(t, "http://search:8080")
+	h, err := NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.Reserve([]string{"/health", "/api/v1/me"}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := h.Reserve([]string{"/api/v1/search/:id"}); err == nil {
+		t.Error("Reserve() of a path the table serves error = nil")
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+
+	pb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+)
+
+// RequestReturn opens a return for lines of one of the calling customer's
+// delivered orders.
+func (h *OrderHandler) RequestReturn(c *gin.Context) {
+	var req struct {
+		Items []struct {
+			SKU      string `json:"sku" binding:"required"`
+			Quantity int32  `json:"quantity" binding:"required,gt=0"`
+		} `json:"items" binding:"required,min=1,dive"`
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lines := make([]*pb.ReturnLine, 0, len(req.Items))
+	for _, item := range req.Items {
+		lines = append(lines, &pb.ReturnLine{
+			Sku:      item.SKU,
+			Quantity: item.Quantity,
+		})
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateReturn(ctx, &pb.CreateReturnRequest{
+		OrderId:    c.Param("id"),
+		CustomerId: c.GetInt64("user_id"),
+		Lines:      lines,
+		Reason:     req.Reason,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Rma)
+}
+
+func (h *OrderHandler) ListMyReturns(c *gin.Context) {
+	h.listReturns(c, c.GetInt64("user_id"), 0)
+}
+
+func (h *OrderHandler) ListSellerReturns(c *gin.Context) {
+	h.listReturns(c, 0, c.GetInt64("user_id"))
+}
+
+func (h *OrderHandler) ListReturns(c *gin.Context) {
+	customerID, _ := strconv.ParseInt(c.Query("customer_id"), 10, 64)
+	sellerID, _ := strconv.ParseInt(c.Query("seller_id"), 10, 64)
+
+	h.listReturns(c, customerID, sellerID)
+}
+
+func (h *OrderHandler) listReturns(c *gin.Context, customerID, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListReturns(ctx, &pb.ListReturnsRequest{
+		OrderId:    c.Query("order_id"),
+		CustomerId: customerID,
+		SellerId:   sellerID,
+		Status:     c.Query("status"),
+		Page:       int32(page),
+		PageSize:   int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	returns := resp.Returns
+	if returns == nil {
+		returns = []*pb.Return{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"returns": returns,
+		"total":   resp.Total,
+	})
+}
+
+func (h *OrderHandler) GetMyReturn(c *gin.Context) {
+	h.getReturn(c, c.GetInt64("user_id"), 0)
+}
+
+func (h *OrderHandler) GetSellerReturn(c *gin.Context) {
+	h.getReturn(c, 0, c.GetInt64("user_id"))
+}
+
+func (h *OrderHandler) GetReturn(c *gin.Context) {
+	h.getReturn(c, 0, 0)
+}
+
+func (h *OrderHandler) getReturn(c *gin.Context, customerID, sellerID int64) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetReturn(ctx, &pb.GetReturnRequest{
+		Id:         c.Param("id"),
+		CustomerId: customerID,
+		SellerId:   sellerID,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Rma)
+}
+
+// CancelMyReturn withdraws a return the calling customer has not sent back
+// yet.
+func (h *OrderHandler) CancelMyReturn(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"max=500"`
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.changeReturnStatus(c, "cancelled", "customer", req.Reason, 0)
+}
+
+// ChangeSellerReturnStatus returns a handler moving a return on one of the
+// calling seller's orders to status. Refunds may name a partial amount.
+func (h *OrderHandler) ChangeSellerReturnStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Reason       string `json:"reason" binding:"max=500"`
+			RefundAmount int64  `json:"refund_amount" binding:"gte=0"`
+		}
+
+		// The body is optional: a reason is only needed for rejections.
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				problem.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		h.changeReturnStatus(c, status, "seller", req.Reason, req.RefundAmount)
+	}
+}
+
+func (h *OrderHandler) ChangeReturnStatus(c *gin.Context) {
+	var req struct {
+		Status       string `json:"status" binding:"required"`
+		Reason       string `json:"reason" binding:"max=500"`
+		RefundAmount int64  `json:"refund_amount" binding:"gte=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.changeReturnStatus(c, req.Status, "admin", req.Reason, req.RefundAmount)
+}
+
+func (h *OrderHandler) changeReturnStatus(c *gin.Context, status, role, reason string, refundAmount int64) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ChangeReturnStatus(ctx, &pb.ChangeReturnStatusRequest{
+		Id:           c.Param("id"),
+		Status:       status,
+		ActorId:      c.GetInt64("user_id"),
+		ActorRole:    role,
+		Reason:       reason,
+		RefundAmount: refundAmount,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Rma)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/seller-service/proto"
+)
+
+type SellerHandler struct {
+	client pb.SellerServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewSellerHandler(sellerServiceURL string, opts ...grpc.DialOption) (*SellerHandler, error) {
+	conn, err := grpc.NewClient(sellerServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SellerHandler{
+		client: pb.NewSellerServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *SellerHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+func (h *SellerHandler) ApplyStore(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name" binding:"required,min=3,max=100"`
+		PhoneNumber string `json:"phone_number" binding:"required,e164"`
+		Address     string `json:"address" binding:"required"`
+		Description string `json:"description" binding:"max=2000"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ApplyStore(ctx, &pb.ApplyStoreRequest{
+		OwnerId:     c.GetInt64("user_id"),
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Address:     req.Address,
+		Description: req.Description,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Store)
+}
+
+func (h *SellerHandler) GetMyStore(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetMyStore(ctx, &pb.GetMyStoreRequest{
+		OwnerId: c.GetInt64("user_id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Store)
+}
+
+func (h *SellerHandler) UpdateStore(c *gin.Context) {
+	var req struct {
+		Name        *string `json:"name" binding:"omitempty,min=3,max=100"`
+		PhoneNumber *string `json:"phone_number" binding:"omitempty,e164"`
+		Address     *string `json:"address" binding:"omitempty,min=1"`
+		Description *string `json:"description" binding:"omitempty,max=2000"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.UpdateStore(ctx, &pb.UpdateStoreRequest{
+		OwnerId:     c.GetInt64("user_id"),
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Address:     req.Address,
+		Description: req.Description,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Store)
+}
+
+// GetStore is the public storefront lookup. Stores that are not approved are
+// hidden from every caller; owners see theirs through GetMyStore and admins
+// through GetStoreByID.
+func (h *SellerHandler) GetStore(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetStore(ctx, &pb.GetStoreRequest{
+		Slug: c.Param("slug"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	if resp.Store.Status != "approved" {
+		problem.Error(c, http.StatusNotFound, "store not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          resp.Store.Id,
+		"name":        resp.Store.Name,
+		"slug":        resp.Store.Slug,
+		"description": resp.Store.Description,
+	})
+}
+
+func (h *SellerHandler) ListStores(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListStores(ctx, &pb.ListStoresRequest{
+		Status:   c.Query("status"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	stores := resp.Stores
+	if stores == nil {
+		stores = []*pb.Store{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stores": stores,
+		"total":  resp.Total,
+	})
+}
+
+func (h *SellerHandler) GetStoreByID(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetStore(ctx, &pb.GetStoreRequest{
+		Id: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Store)
+}
+
+func (h *SellerHandler) GetStoreHistory(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetStoreHistory(ctx, &pb.GetStoreHistoryRequest{
+		StoreId: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	changes := resp.Changes
+	if changes == nil {
+		changes = []*pb.StoreStatusChange{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+	})
+}
+
+// ChangeStoreStatus returns an admin action that moves a store to status,
+// e.g. POST /admin/stores/:id/approve.
+func (h *SellerHandler) ChangeStoreStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Reason string `json:"reason" binding:"max=500"`
+		}
+
+		// The reason is optional for approvals, so an empty body is fine.
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				problem.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		ctx := requestContext(c)
+
+		resp, err := h.client.ChangeStoreStatus(ctx, &pb.ChangeStoreStatusRequest{
+			StoreId: c.Param("id"),
+			Status:  status,
+			ActorId: c.GetInt64("user_id"),
+			Reason:  req.Reason,
+		})
+
+		if err != nil {
+			problem.GRPC(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp.Store)
+	}
+}
+
+func (h *SellerHandler) GetStoreForOwnerMiddleware(c *gin.Context, ownerID int64) (*pb.Store, error) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetMyStore(ctx, &pb.GetMyStoreRequest{
+		OwnerId: ownerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Store, nil
+}
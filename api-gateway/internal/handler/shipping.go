@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/shipping-service/proto"
+)
+
+type ShippingHandler struct {
+	client pb.ShippingServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewShippingHandler(shippingServiceURL string, opts ...grpc.DialOption) (*ShippingHandler, error) {
+	conn, err := grpc.NewClient(shippingServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShippingHandler{
+		client: pb.NewShippingServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *ShippingHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// QuoteRates prices delivery of some lines to an address, e.g. for a
+// shipping estimate on the cart page. Options are what checkout accepts as
+// shipping_option.
+func (h *ShippingHandler) QuoteRates(c *gin.Context) {
+	var req struct {
+		Country string `json:"country" binding:"required,len=2"`
+		Region  string `json:"region" binding:"max=100"`
+		Lines   []struct {
+			SKU      string `json:"sku" binding:"required"`
+			Quantity int32  `json:"quantity" binding:"required,gt=0"`
+		} `json:"lines" binding:"required,min=1,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quoteReq := &pb.QuoteRatesRequest{
+		Country: req.Country,
+		Region:  req.Region,
+	}
+	for _, line := range req.Lines {
+		quoteReq.Lines = append(quoteReq.Lines, &pb.QuoteLine{
+			Sku:      line.SKU,
+			Quantity: line.Quantity,
+		})
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.QuoteRates(ctx, quoteReq)
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	parcels := resp.Parcels
+	if parcels == nil {
+		parcels = []*pb.Parcel{}
+	}
+	options := resp.Options
+	if options == nil {
+		options = []*pb.Rate{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"parcels": parcels,
+		"options": options,
+	})
+}
+
+// CreateSellerShipment books the parcel of one of the seller's paid orders
+// with a carrier and marks the order fulfilled. The option chosen at
+// checkout is used unless the seller picks another.
+func (h *ShippingHandler) CreateSellerShipment(c *gin.Context) {
+	var req struct {
+		Option      string `json:"option" binding:"max=61"`
+		WeightGrams int32  `json:"weight_grams" binding:"gte=0"`
+	}
+
+	// Both fields are optional, so an empty body is fine.
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.CreateShipment(ctx, &pb.CreateShipmentRequest{
+		OrderId:     c.Param("id"),
+		SellerId:    c.GetInt64("user_id"),
+		Option:      req.Option,
+		WeightGrams: req.WeightGrams,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp.Shipment)
+}
+
+func (h *ShippingHandler) ListMyOrderShipments(c *gin.Context) {
+	h.listShipments(c, c.Param("id"), c.GetInt64("user_id"), 0)
+}
+
+func (h *ShippingHandler) ListSellerOrderShipments(c *gin.Context) {
+	h.listShipments(c, c.Param("id"), 0, c.GetInt64("user_id"))
+}
+
+func (h *ShippingHandler) ListShipments(c *gin.Context) {
+	customerID, _ := strconv.ParseInt(c.Query("customer_id"), 10, 64)
+	sellerID, _ := strconv.ParseInt(c.Query("seller_id"), 10, 64)
+
+	h.listShipments(c, c.Query("order_id"), customerID, sellerID)
+}
+
+func (h *ShippingHandler) listShipments(c *gin.Context, orderID string, customerID, sellerID int64) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListShipments(ctx, &pb.ListShipmentsRequest{
+		OrderId:    orderID,
+		CustomerId: customerID,
+		SellerId:   sellerID,
+		Status:     c.Query("status"),
+		Page:       int32(page),
+		PageSize:   int32(pageSize),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	shipments := resp.Shipments
+	if shipments == nil {
+		shipments = []*pb.Shipment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shipments": shipments,
+		"total":     resp.Total,
+	})
+}
+
+func (h *ShippingHandler) GetShipment(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.GetShipment(ctx, &pb.GetShipmentRequest{
+		Id: c.Param("id"),
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp.Shipment)
+}
+
+// HandleWebhook passes a carrier tracking webhook through untouched; the
+// shipping service checks the signature over the exact bytes received.
+func (h *ShippingHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		problem.Error(c, http.StatusRequestEntityTooLarge, "webhook payload too large")
+		return
+	}
+
+	headers := make(map[string]string, len(c.Request.Header))
+	for key := range c.Request.Header {
+		headers[key] = c.Request.Header.Get(key)
+	}
+
+	ctx := requestContext(c)
+
+	resp, err := h.client.HandleWebhook(ctx, &pb.HandleWebhookRequest{
+		Carrier: c.Param("carrier"),
+		Payload: payload,
+		Headers: headers,
+	})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applied": resp.Applied,
+	})
+}
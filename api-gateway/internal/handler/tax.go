@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/Dzaakk/micro-commerce/services/tax-service/proto"
+)
+
+type TaxHandler struct {
+	client pb.TaxServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewTaxHandler(taxServiceURL string, opts ...grpc.DialOption) (*TaxHandler, error) {
+	conn, err := grpc.NewClient(taxServiceURL,
+		append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaxHandler{
+		client: pb.NewTaxServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (h *TaxHandler) Close() {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+}
+
+// ListRuleSets lists the loaded versions of the tax rules and which one is
+// in effect.
+func (h *TaxHandler) ListRuleSets(c *gin.Context) {
+	ctx := requestContext(c)
+
+	resp, err := h.client.ListRuleSets(ctx, &pb.ListRuleSetsRequest{})
+
+	if err != nil {
+		problem.GRPC(c, err)
+		return
+	}
+
+	ruleSets := resp.RuleSets
+	if ruleSets == nil {
+		ruleSets = []*pb.RuleSet{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rule_sets": ruleSets,
+	})
+}
@@ -0,0 +1,83 @@
+// Package metrics defines the Prometheus metrics of the gateway: the rate,
+// errors and duration of the requests it serves, per route template, and of
+// the calls it makes to upstreams. They are registered with the default
+// registry, which also has the Go runtime and process metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gateway"
+
+// UnmatchedRoute is the route label of requests no route matched, so paths
+// probed by clients do not each make a series.
+const UnmatchedRoute = "unmatched"
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Requests served, by method, route template and status class.",
+	}, []string{"method", "route", "status_class"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time to serve a request, by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	requestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Requests being served.",
+	})
+)
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RequestStarted counts a request as in flight until the returned func is
+// called with its outcome.
+func RequestStarted() func(method, route string, status int) {
+	start := time.Now()
+	requestsInFlight.Inc()
+
+	return func(method, route string, status int) {
+		requestsInFlight.Dec()
+		method = methodLabel(method)
+		requestsTotal.WithLabelValues(method, route, StatusClass(status)).Inc()
+		requestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// StatusClass returns the class of an HTTP status, such as "2xx".
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// methodLabel keeps the methods clients can make up out of the labels.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusContinue, "1xx"},
+		{http.StatusOK, "2xx"},
+		{http.StatusNoContent, "2xx"},
+		{http.StatusFound, "3xx"},
+		{http.StatusTooManyRequests, "4xx"},
+		{http.StatusServiceUnavailable, "5xx"},
+		{599, "5xx"},
+		{0, "unknown"},
+		{600, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := StatusClass(tt.status); got != tt.want {
+			t.Errorf("StatusClass(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestMethodLabel(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{http.MethodGet, "GET"},
+		{http.MethodPatch, "PATCH"},
+		{http.MethodOptions, "OPTIONS"},
+		{"get", "OTHER"},
+		{"PROPFIND", "OTHER"},
+		{"", "OTHER"},
+	}
+
+	for _, tt := range tests {
+		if got := methodLabel(tt.method); got != tt.want {
+			t.Errorf("methodLabel(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "requests_total",
+		Help:      "Calls to upstreams, by upstream, method and outcome: the gRPC code, the HTTP status or \"error\".",
+	}, []string{"upstream", "method", "code"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "request_duration_seconds",
+		Help:      "Time of calls to upstreams, retries included, by upstream and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"upstream", "method"})
+)
+
+// DialOption records the unary calls of a gRPC client connection to
+// upstream. It goes before the resilience options, so a call is timed with
+// its retries and counts once, with the code its caller gets.
+func DialOption(upstream string) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		upstreamRequestsTotal.WithLabelValues(upstream, method, status.Code(err).String()).Inc()
+		upstreamRequestDuration.WithLabelValues(upstream, method).Observe(time.Since(start).Seconds())
+		return err
+	})
+}
+
+// Transport records the requests sent to upstream through base.
+func Transport(upstream string, base http.RoundTripper) http.RoundTripper {
+	return &transport{name: upstream, base: base}
+}
+
+type transport struct {
+	name string
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamRequestsTotal.WithLabelValues(t.name, req.Method, code).Inc()
+	upstreamRequestDuration.WithLabelValues(t.name, req.Method).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// RegisterUpstreams exports the circuit breaker states and call counts of
+// upstreams.
+func RegisterUpstreams(upstreams *resilience.Upstreams) {
+	prometheus.MustRegister(&upstreamCollector{upstreams: upstreams})
+}
+
+var (
+	circuitStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "upstream", "circuit_state"),
+		"Whether the circuit breaker of an upstream is in a state.",
+		[]string{"upstream", "state"}, nil,
+	)
+	circuitTransitionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "upstream", "circuit_transitions_total"),
+		"Times the circuit breaker of an upstream went into a state.",
+		[]string{"upstream", "state"}, nil,
+	)
+	upstreamInFlightDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "upstream", "in_flight"),
+		"Calls to an upstream in flight.",
+		[]string{"upstream"}, nil,
+	)
+	upstreamRetriesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "upstream", "retries_total"),
+		"Attempts of calls to an upstream after the first.",
+		[]string{"upstream"}, nil,
+	)
+	upstreamRejectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "upstream", "rejected_total"),
+		"Calls to an upstream failed at once, by reason.",
+		[]string{"upstream", "reason"}, nil,
+	)
+)
+
+var circuitStates = []resilience.State{resilience.Closed, resilience.Open, resilience.HalfOpen}
+
+// upstreamCollector reads the counts resilience keeps rather than counting
+// them again.
+type upstreamCollector struct {
+	upstreams *resilience.Upstreams
+}
+
+func (c *upstreamCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- circuitStateDesc
+	ch <- circuitTransitionsDesc
+	ch <- upstreamInFlightDesc
+	ch <- upstreamRetriesDesc
+	ch <- upstreamRejectedDesc
+}
+
+func (c *upstreamCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.upstreams.Stats() {
+		for _, state := range circuitStates {
+			value := 0.0
+			if s.State == state.String() {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(circuitStateDesc, prometheus.GaugeValue, value, s.Name, state.String())
+			ch <- prometheus.MustNewConstMetric(circuitTransitionsDesc, prometheus.CounterValue, float64(s.StateChanges[state.String()]), s.Name, state.String())
+		}
+
+		ch <- prometheus.MustNewConstMetric(upstreamInFlightDesc, prometheus.GaugeValue, float64(s.InFlight), s.Name)
+		ch <- prometheus.MustNewConstMetric(upstreamRetriesDesc, prometheus.CounterValue, float64(s.Retries), s.Name)
+		ch <- prometheus.MustNewConstMetric(upstreamRejectedDesc, prometheus.CounterValue, float64(s.RejectedOpen), s.Name, "circuit_open")
+		ch <- prometheus.MustNewConstMetric(upstreamRejectedDesc, prometheus.CounterValue, float64(s.RejectedFull), s.Name, "bulkhead_full")
+	}
+}
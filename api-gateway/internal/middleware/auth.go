@@ -1,41 +1,85 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func AuthMiddleware(authHandler *handler.AuthHandler) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			c.Abort()
+		// The user may already be known from an earlier middleware, such as
+		// a rate limit counted by user.
+		if _, ok := c.Get("user_id"); ok {
+			c.Next()
 			return
 		}
 
-		token := extractToken(authHeader)
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token format"})
-			c.Abort()
+		if err := authenticate(c, authHandler); err != nil {
+			problem.GRPC(c, err)
 			return
 		}
 
-		resp, err := authHandler.ValidateTokenMiddleware(token)
-		if err != nil || !resp.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-			c.Abort()
+		c.Next()
+	}
+}
+
+// authenticate validates the bearer token of the request and sets the user
+// in the context. It returns a gRPC status error when it cannot. Errors from
+// auth-service keep their code, so an expired token (Unauthenticated) or a
+// locked account (PermissionDenied) reach the client as such, and an
+// auth-service that cannot be reached does not tell the client to log in
+// again.
+func authenticate(c *gin.Context, authHandler *handler.AuthHandler) error {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return status.Error(codes.Unauthenticated, "authorization header required")
+	}
+
+	token := extractToken(authHeader)
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "invalid token format")
+	}
+
+	resp, err := authHandler.ValidateTokenMiddleware(c, token)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.InvalidArgument, codes.NotFound:
+		// A malformed token, or one of a user that no longer exists.
+		return status.Error(codes.Unauthenticated, "invalid or expired token")
+	default:
+		return err
+	}
+	if !resp.Valid {
+		return status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	c.Set("user_id", resp.UserId)
+	c.Set("email", resp.Email)
+	c.Set("role", resp.Role)
+	c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), resp.UserId))
+
+	return nil
+}
+
+// OptionalAuthMiddleware sets the user like AuthMiddleware when a bearer
+// token is sent, and lets anonymous requests through untouched. A token that
+// is sent but invalid is still rejected.
+func OptionalAuthMiddleware(authHandler *handler.AuthHandler) gin.HandlerFunc {
+	required := AuthMiddleware(authHandler)
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
 
-		c.Set("user_id", resp.UserId)
-		c.Set("email", resp.Email)
-		c.Set("role", resp.Role)
-
-		c.Next()
+		required(c)
 	}
 }
 
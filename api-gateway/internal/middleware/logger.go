@@ -1,12 +1,15 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Logger logs every request once it is answered: server errors at error
+// level, client errors at warn and the rest at info. The request context
+// adds the request and user IDs; secrets in the query are redacted.
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -16,17 +19,26 @@ func Logger() gin.HandlerFunc {
 		c.Next()
 
 		latency := time.Since(start)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
 		statusCode := c.Writer.Status()
 
 		if raw != "" {
 			path = path + "?" + raw
 		}
 
-		log.Printf(
-			"[%s] %s %s %d %v",
-			clientIP, method, path, statusCode, latency)
+		level := slog.LevelInfo
+		switch {
+		case statusCode >= 500:
+			level = slog.LevelError
+		case statusCode >= 400:
+			level = slog.LevelWarn
+		}
 
+		slog.LogAttrs(c.Request.Context(), level, "Request",
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", statusCode),
+			slog.Duration("latency", latency),
+		)
 	}
 }
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the rate, status class and duration of requests per route
+// template. Requests for the route table are labelled with the path prefix
+// of the route they matched.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done := metrics.RequestStarted()
+
+		c.Next()
+
+		done(c.Request.Method, routeLabel(c), c.Writer.Status())
+	}
+}
+
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	if route := handler.ProxyRoute(c); route != nil {
+		return route.PathPrefix
+	}
+	return metrics.UnmatchedRoute
+}
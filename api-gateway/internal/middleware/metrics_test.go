@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// requestCounts returns gateway_http_requests_total by its method, route and
+// status class labels.
+func requestCounts(t *testing.T) map[[3]string]float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	counts := map[[3]string]float64{}
+	for _, family := range families {
+		if family.GetName() != "gateway_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			counts[[3]string{labels["method"], labels["route"], labels["status_class"]}] = metric.GetCounter().GetValue()
+		}
+	}
+	return counts
+}
+
+func TestMetrics(t *testing.T) {
+	table := &config.RouteTable{
+		Upstreams: map[string]*config.Upstream{
+			"search": {Protocol: "http", URL: "http://search.invalid"},
+		},
+		Routes: []*config.Route{
+			{Name: "search", PathPrefix: "/api/v1/search", Upstream: "search"},
+		},
+	}
+	if err := table.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	proxy, err := handler.NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer proxy.Close()
+
+	r := gin.New()
+	r.Use(Metrics(), proxy.Pin)
+	r.GET("/api/v1/products/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/api/v1/orders", func(c *gin.Context) {
+		c.Status(http.StatusBadGateway)
+	})
+	r.NoRoute(proxy.Match, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		// Every product is one route template.
+		{http.MethodGet, "/api/v1/products/1"},
+		{http.MethodGet, "/api/v1/products/2"},
+		{http.MethodGet, "/api/v1/products/3?currency=USD"},
+		{http.MethodPost, "/api/v1/orders"},
+		// Route table paths are labelled with their route's prefix.
+		{http.MethodGet, "/api/v1/search/books"},
+		{http.MethodGet, "/api/v1/search/films/1"},
+		// Paths no route matches share one label, whatever they are.
+		{http.MethodGet, "/wp-login.php"},
+		{http.MethodGet, "/.env"},
+		{http.MethodGet, "/api/v1/products"},
+		// Made up methods too.
+		{"PROPFIND", "/api/v1/products/1"},
+		{"BREW", "/api/v1/search/coffee"},
+	}
+
+	before := requestCounts(t)
+	for _, req := range requests {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+	after := requestCounts(t)
+
+	want := map[[3]string]float64{
+		{"GET", "/api/v1/products/:id", "2xx"}: 3,
+		{"POST", "/api/v1/orders", "5xx"}:      1,
+		{"GET", "/api/v1/search", "2xx"}:       2,
+		{"GET", "unmatched", "4xx"}:            3,
+		{"OTHER", "unmatched", "4xx"}:          1,
+		{"OTHER", "/api/v1/search", "2xx"}:     1,
+	}
+	for labels, count := range want {
+		if got := after[labels] - before[labels]; got != count {
+			t.Errorf("requests_total%v increased by %v, want %v", labels, got, count)
+		}
+	}
+	for labels, count := range after {
+		if _, ok := want[labels]; !ok && count != before[labels] {
+			t.Errorf("requests_total%v increased by %v, want no such series", labels, count-before[labels])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit counts each request against every rate limit of its route
+// table that applies to it and answers 429 with Retry-After once one is used
+// up. The RateLimit-* headers describe the limit closest to running out.
+// Requests are let through when the counts cannot be reached. It must run
+// after the route table is pinned; see handler.ProxyHandler.Pin.
+func RateLimit(limiter *ratelimit.Limiter, authHandler *handler.AuthHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limits := config.TableFrom(c.Request.Context()).RateLimitsFor(c.Request.Method, c.Request.URL.Path)
+		if len(limits) == 0 {
+			c.Next()
+			return
+		}
+
+		var tightest *config.RateLimit
+		var result ratelimit.Result
+		for _, limit := range limits {
+			counted, err := limiter.Take(c.Request.Context(), limit, rateLimitKey(c, limit, authHandler))
+			if err != nil {
+				slog.WarnContext(c.Request.Context(), "Rate limit not enforced", slog.String("limit", limit.Name), slog.Any("error", err))
+				continue
+			}
+
+			if tightest == nil || !counted.Allowed || counted.Remaining < result.Remaining {
+				tightest, result = limit, counted
+			}
+			if !counted.Allowed {
+				break
+			}
+		}
+
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		header.Set("RateLimit-Policy", strconv.Itoa(tightest.Limit)+";w="+strconv.Itoa(seconds(tightest.Window())))
+
+		if !result.Allowed {
+			p := problem.New(http.StatusTooManyRequests, "too many requests")
+			p.RetryAfter = max(seconds(result.RetryAfter), 1)
+			problem.Respond(c, p)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey is what the request is counted by for limit. Requests
+// without the user or API key the limit counts by are counted by client IP.
+func rateLimitKey(c *gin.Context, limit *config.RateLimit, authHandler *handler.AuthHandler) string {
+	switch limit.Key {
+	case config.RateLimitKeyRoute:
+		return "route"
+	case config.RateLimitKeyUser:
+		if _, ok := c.Get("user_id"); ok || authenticate(c, authHandler) == nil {
+			return "user:" + strconv.FormatInt(c.GetInt64("user_id"), 10)
+		}
+	case config.RateLimitKeyAPIKey:
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			// Keys are stored hashed so the store never holds them.
+			sum := sha256.Sum256([]byte(apiKey))
+			return "api_key:" + hex.EncodeToString(sum[:16])
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
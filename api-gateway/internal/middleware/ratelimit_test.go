@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const ipLimitYAML = `
+rate_limits:
+  - name: login
+    path_prefix: /api/v1/auth/login
+    algorithm: sliding_window
+    key: ip
+    limit: 1
+    period: 1m
+`
+
+// newRateLimitedEngine serves /api/v1/auth/login under a limit of one
+// request a minute per client IP, believing X-Forwarded-For from proxies.
+func newRateLimitedEngine(t *testing.T, proxies []string) *gin.Engine {
+	table, err := config.ParseRouteTable("routes.yaml", []byte(ipLimitYAML))
+	if err != nil {
+		t.Fatalf("ParseRouteTable() error = %v", err)
+	}
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		t.Fatalf("SetTrustedProxies() error = %v", err)
+	}
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(config.WithTable(c.Request.Context(), table))
+		c.Next()
+	}, RateLimit(ratelimit.NewLimiter(ratelimit.NewMemoryStore()), nil))
+	r.POST("/api/v1/auth/login", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.POST("/api/v1/auth/register", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	type request struct {
+		remoteAddr    string
+		forwardedFor  string
+		wantStatus    int
+		wantRemaining string
+	}
+
+	tests := []struct {
+		name     string
+		proxies  []string
+		requests []request
+	}{
+		{
+			name: "spoofed X-Forwarded-For does not change the key",
+			requests: []request{
+				{remoteAddr: "203.0.113.7:1234", forwardedFor: "198.51.100.1", wantStatus: http.StatusNoContent, wantRemaining: "0"},
+				{remoteAddr: "203.0.113.7:1234", forwardedFor: "198.51.100.2", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "203.0.113.7:5678", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name: "other connections are counted apart",
+			requests: []request{
+				{remoteAddr: "203.0.113.7:1234", wantStatus: http.StatusNoContent},
+				{remoteAddr: "203.0.113.8:1234", wantStatus: http.StatusNoContent},
+			},
+		},
+		{
+			name:    "trusted proxy forwards the client IP",
+			proxies: []string{"192.0.2.0/24"},
+			requests: []request{
+				{remoteAddr: "192.0.2.10:1234", forwardedFor: "198.51.100.1", wantStatus: http.StatusNoContent},
+				{remoteAddr: "192.0.2.11:1234", forwardedFor: "198.51.100.2", wantStatus: http.StatusNoContent},
+				{remoteAddr: "192.0.2.10:1234", forwardedFor: "198.51.100.1", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:    "untrusted address cannot pose as a proxy",
+			proxies: []string{"192.0.2.0/24"},
+			requests: []request{
+				{remoteAddr: "203.0.113.7:1234", forwardedFor: "198.51.100.1", wantStatus: http.StatusNoContent},
+				{remoteAddr: "203.0.113.7:1234", forwardedFor: "198.51.100.2", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRateLimitedEngine(t, tt.proxies)
+
+			for i, req := range tt.requests {
+				httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+				httpReq.RemoteAddr = req.remoteAddr
+				if req.forwardedFor != "" {
+					httpReq.Header.Set("X-Forwarded-For", req.forwardedFor)
+				}
+				w := httptest.NewRecorder()
+
+				r.ServeHTTP(w, httpReq)
+
+				if w.Code != req.wantStatus {
+					t.Fatalf("request %d status = %d, want %d", i, w.Code, req.wantStatus)
+				}
+				if req.wantRemaining != "" && w.Header().Get("RateLimit-Remaining") != req.wantRemaining {
+					t.Errorf("request %d RateLimit-Remaining = %q, want %q", i, w.Header().Get("RateLimit-Remaining"), req.wantRemaining)
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d refused without Retry-After", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitOnlyAppliesUnderItsPrefix(t *testing.T) {
+	r := newRateLimitedEngine(t, nil)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", nil))
+		if w.Code != http.StatusNoContent || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("request %d outside the limit = %d with RateLimit-Limit %q, want %d without",
+				i, w.Code, w.Header().Get("RateLimit-Limit"), http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/requestid"
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID takes the request ID from the X-Request-ID header, or generates
+// one when it is missing or unusable. The ID is echoed in the response and
+// set in the gin context as "request_id", in the request context, where log
+// entries pick it up, and in the request header, which upstream calls and
+// proxied requests pass on.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set("request_id", id)
+		c.Request.Header.Set(requestid.Header, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/requestid"
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// captureLogs sends log entries to the returned buffer, as JSON lines, until
+// the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	var buf bytes.Buffer
+	logging.Setup(logging.Config{Service: "api-gateway", Output: &buf})
+	return &buf
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{name: "kept from the client", header: "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", wantKept: true},
+		{name: "generated when missing"},
+		{name: "replaced when it could forge entries", header: "req-1 level=ERROR"},
+		{name: "replaced when too long", header: strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+
+			var upstreamID string
+			r := gin.New()
+			r.Use(RequestID(), Logger())
+			r.GET("/api/v1/orders", func(c *gin.Context) {
+				upstreamID = c.Request.Header.Get(requestid.Header)
+				problem.Error(c, http.StatusNotFound, "order not found")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(requestid.Header)
+			if tt.wantKept && id != tt.header {
+				t.Errorf("response %s = %q, want %q", requestid.Header, id, tt.header)
+			}
+			if !tt.wantKept && (id == tt.header || !requestid.Valid(id)) {
+				t.Errorf("response %s = %q, want a new ID", requestid.Header, id)
+			}
+			if upstreamID != id {
+				t.Errorf("request %s passed on = %q, want %q", requestid.Header, upstreamID, id)
+			}
+
+			var body problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("problem body error = %v", err)
+			}
+			if body.RequestID != id {
+				t.Errorf("problem request_id = %q, want %q", body.RequestID, id)
+			}
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+				t.Fatalf("log entry %q error = %v", logs.String(), err)
+			}
+			if entry["msg"] != "Request" || entry["request_id"] != id {
+				t.Errorf("log entry = %v, want the request logged with request_id %q", entry, id)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,10 +12,7 @@ func RoleMiddleware(requiredRole string) gin.HandlerFunc {
 		role := c.GetString("role")
 
 		if role != requiredRole {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "insufficient permission",
-			})
-			c.Abort()
+			problem.Error(c, http.StatusForbidden, "insufficient permission")
 			return
 		}
 
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovedSeller only lets through sellers whose store has been approved by
+// an admin. It must run after AuthMiddleware and puts the store id in the
+// context as "store_id".
+func ApprovedSeller(sellerHandler *handler.SellerHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "seller" {
+			problem.Error(c, http.StatusForbidden, "insufficient permission")
+			return
+		}
+
+		store, err := sellerHandler.GetStoreForOwnerMiddleware(c, c.GetInt64("user_id"))
+		if err != nil || store.Status != "approved" {
+			problem.Error(c, http.StatusForbidden, "an approved store is required")
+			return
+		}
+
+		c.Set("store_id", store.Id)
+
+		c.Next()
+	}
+}
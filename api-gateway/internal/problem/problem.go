@@ -0,0 +1,210 @@
+// Package problem writes the error responses of the gateway as RFC 7807
+// problem details (application/problem+json). Errors from upstream gRPC calls
+// are translated from their status code, keeping the error details the
+// services attach, such as field violations and retry delays.
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContentType is the media type of every error response.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is used for calls canceled because the client
+// went away. It has no constant in net/http.
+const StatusClientClosedRequest = 499
+
+// Problem is an RFC 7807 problem details object. Type is always
+// "about:blank", so Title is the text of the HTTP status; the extension
+// members carry what clients act on.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the canonical name of the gRPC status of an upstream error,
+	// such as NOT_FOUND.
+	Code      string `json:"code,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Reason, Domain and Metadata come from a google.rpc.ErrorInfo.
+	Reason        string            `json:"reason,omitempty"`
+	Domain        string            `json:"domain,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	InvalidParams []InvalidParam    `json:"invalid_params,omitempty"`
+	Violations    []Violation       `json:"violations,omitempty"`
+	// RetryAfter is in seconds and also sent as the Retry-After header.
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// InvalidParam is a request field that failed validation.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Violation is a failed precondition or exhausted quota.
+type Violation struct {
+	Type        string `json:"type,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description"`
+}
+
+// New returns the problem for status with a human readable detail.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  statusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// FromError translates the status of a gRPC error. Messages of server side
+// failures are left out, as they may describe internals such as addresses or
+// queries; GRPC logs them instead.
+func FromError(err error) *Problem {
+	st, _ := status.FromError(err)
+	httpStatus := HTTPStatus(st.Code())
+
+	detail := st.Message()
+	if httpStatus >= http.StatusInternalServerError && st.Code() != codes.Unavailable {
+		detail = ""
+	}
+
+	p := New(httpStatus, detail)
+	p.Code = code.Code(st.Code()).String()
+
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: v.GetField(), Reason: v.GetDescription()})
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.GetViolations() {
+				p.Violations = append(p.Violations, Violation{Type: v.GetType(), Subject: v.GetSubject(), Description: v.GetDescription()})
+			}
+		case *errdetails.QuotaFailure:
+			for _, v := range d.GetViolations() {
+				p.Violations = append(p.Violations, Violation{Subject: v.GetSubject(), Description: v.GetDescription()})
+			}
+		case *errdetails.RetryInfo:
+			p.RetryAfter = max(int(math.Ceil(d.GetRetryDelay().AsDuration().Seconds())), 1)
+		case *errdetails.ErrorInfo:
+			p.Reason = d.GetReason()
+			p.Domain = d.GetDomain()
+			p.Metadata = d.GetMetadata()
+		case *errdetails.LocalizedMessage:
+			p.Detail = d.GetMessage()
+		}
+	}
+
+	return p
+}
+
+// HTTPStatus returns the HTTP status for a gRPC code. FailedPrecondition is
+// a conflict with the current state of a resource, as the services use it
+// for invalid state transitions.
+func HTTPStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return StatusClientClosedRequest
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		// Unknown, Internal and DataLoss.
+		return http.StatusInternalServerError
+	}
+}
+
+// Error responds with status and detail and aborts the request.
+func Error(c *gin.Context, status int, detail string) {
+	Respond(c, New(status, detail))
+}
+
+// GRPC responds with the translation of an error from an upstream gRPC call
+// and aborts the request.
+func GRPC(c *gin.Context, err error) {
+	p := FromError(err)
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "Upstream error",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Any("error", err),
+		)
+	}
+	Respond(c, p)
+}
+
+// Respond writes p and aborts the request.
+func Respond(c *gin.Context, p *Problem) {
+	if p.RequestID == "" {
+		p.RequestID = c.GetString("request_id")
+	}
+	Write(c.Writer, c.Request, p)
+	c.Abort()
+}
+
+// Write writes p to w, for code outside of gin handlers such as the error
+// handler of a reverse proxy.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = logging.RequestID(r.Context())
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	if p.RetryAfter > 0 && header.Get("Retry-After") == "" {
+		header.Set("Retry-After", strconv.Itoa(p.RetryAfter))
+	}
+	if p.Status == http.StatusUnauthorized && header.Get("WWW-Authenticate") == "" {
+		header.Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write problem", slog.Any("error", err))
+	}
+}
+
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
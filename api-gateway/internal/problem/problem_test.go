@@ -0,0 +1,155 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func withDetails(t *testing.T, c codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	t.Helper()
+
+	st, err := status.New(c, msg).WithDetails(details...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st.Err()
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Problem
+	}{
+		{
+			name: "not found",
+			err:  status.Error(codes.NotFound, "order not found"),
+			want: &Problem{Type: "about:blank", Title: "Not Found", Status: 404, Detail: "order not found", Code: "NOT_FOUND"},
+		},
+		{
+			name: "field violations",
+			err: withDetails(t, codes.InvalidArgument, "validation failed", &errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "email", Description: "is invalid"},
+					{Field: "password", Description: "is too short"},
+				},
+			}),
+			want: &Problem{
+				Type: "about:blank", Title: "Bad Request", Status: 400, Detail: "validation failed", Code: "INVALID_ARGUMENT",
+				InvalidParams: []InvalidParam{{Name: "email", Reason: "is invalid"}, {Name: "password", Reason: "is too short"}},
+			},
+		},
+		{
+			name: "failed precondition with error info",
+			err: withDetails(t, codes.FailedPrecondition, "cannot ship a pending order",
+				&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+					{Type: "STATUS", Subject: "order/o1", Description: "order is pending"},
+				}},
+				&errdetails.ErrorInfo{Reason: "INVALID_TRANSITION", Domain: "orders", Metadata: map[string]string{"from": "pending"}},
+			),
+			want: &Problem{
+				Type: "about:blank", Title: "Conflict", Status: 409, Detail: "cannot ship a pending order", Code: "FAILED_PRECONDITION",
+				Violations: []Violation{{Type: "STATUS", Subject: "order/o1", Description: "order is pending"}},
+				Reason:     "INVALID_TRANSITION", Domain: "orders", Metadata: map[string]string{"from": "pending"},
+			},
+		},
+		{
+			name: "quota with a retry delay rounded up",
+			err: withDetails(t, codes.ResourceExhausted, "too many attempts",
+				&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user/1", Description: "login attempts"}}},
+				&errdetails.RetryInfo{RetryDelay: durationpb.New(2500 * time.Millisecond)},
+			),
+			want: &Problem{
+				Type: "about:blank", Title: "Too Many Requests", Status: 429, Detail: "too many attempts", Code: "RESOURCE_EXHAUSTED",
+				Violations: []Violation{{Subject: "user/1", Description: "login attempts"}},
+				RetryAfter: 3,
+			},
+		},
+		{
+			name: "retry delay under a second",
+			err:  withDetails(t, codes.Unavailable, "draining", &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Millisecond)}),
+			want: &Problem{Type: "about:blank", Title: "Service Unavailable", Status: 503, Detail: "draining", Code: "UNAVAILABLE", RetryAfter: 1},
+		},
+		{
+			name: "localized message replaces the detail",
+			err:  withDetails(t, codes.PermissionDenied, "forbidden", &errdetails.LocalizedMessage{Locale: "en-US", Message: "You cannot do that."}),
+			want: &Problem{Type: "about:blank", Title: "Forbidden", Status: 403, Detail: "You cannot do that.", Code: "PERMISSION_DENIED"},
+		},
+		{
+			name: "internal message is hidden",
+			err:  status.Error(codes.Internal, "pq: connection refused to 10.0.0.5:5432"),
+			want: &Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500, Code: "INTERNAL"},
+		},
+		{
+			name: "plain error",
+			err:  errors.New("dial tcp 10.0.0.5:50051: connect: connection refused"),
+			want: &Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500, Code: "UNKNOWN"},
+		},
+		{
+			name: "canceled",
+			err:  status.Error(codes.Canceled, "context canceled"),
+			want: &Problem{Type: "about:blank", Title: "Client Closed Request", Status: 499, Detail: "context canceled", Code: "CANCELLED"},
+		},
+		{
+			name: "deadline",
+			err:  status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			want: &Problem{Type: "about:blank", Title: "Gateway Timeout", Status: 504, Code: "DEADLINE_EXCEEDED"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromError(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromError() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem *Problem
+		want    map[string]string
+	}{
+		{
+			name:    "retry after",
+			problem: &Problem{Status: http.StatusTooManyRequests, RetryAfter: 3},
+			want:    map[string]string{"Content-Type": ContentType, "Retry-After": "3", "WWW-Authenticate": ""},
+		},
+		{
+			name:    "unauthorized",
+			problem: &Problem{Status: http.StatusUnauthorized},
+			want:    map[string]string{"Content-Type": ContentType, "Retry-After": "", "WWW-Authenticate": "Bearer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Write(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil), tt.problem)
+
+			if w.Code != tt.problem.Status {
+				t.Errorf("status = %d, want %d", w.Code, tt.problem.Status)
+			}
+			for name, want := range tt.want {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if tt.problem.Instance != "/api/v1/orders" {
+				t.Errorf("instance = %q, want the request path", tt.problem.Instance)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+// memorySweepInterval is how often the memory store drops the counts of keys
+// no longer limited.
+const memorySweepInterval = time.Minute
+
+// MemoryStore counts requests in the gateway's own memory, so every instance
+// enforces the limits on its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	done    chan struct{}
+	once    sync.Once
+}
+
+type memoryEntry struct {
+	// Token bucket.
+	tokens float64
+	at     time.Time
+
+	// Sliding window.
+	period   int64
+	previous int64
+	current  int64
+
+	expires time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		entries: map[string]*memoryEntry{},
+		done:    make(chan struct{}),
+	}
+	go s.sweep()
+	return s
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit *config.RateLimit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(limit.Capacity()), at: now}
+		s.entries[key] = entry
+	}
+
+	if limit.Algorithm == config.AlgorithmTokenBucket {
+		return s.takeToken(entry, limit, now), nil
+	}
+	return s.takeWindow(entry, limit, now), nil
+}
+
+func (s *MemoryStore) takeToken(entry *memoryEntry, limit *config.RateLimit, now time.Time) Result {
+	rate := tokenRate(limit)
+	capacity := float64(limit.Capacity())
+
+	if elapsed := now.Sub(entry.at); elapsed > 0 {
+		entry.tokens = math.Min(capacity, entry.tokens+float64(elapsed)*rate)
+		entry.at = now
+	}
+
+	allowed := entry.tokens >= 1
+	if allowed {
+		entry.tokens--
+	}
+	entry.expires = now.Add(time.Duration((capacity - entry.tokens) / rate))
+
+	return tokenBucketResult(limit, entry.tokens, allowed)
+}
+
+func (s *MemoryStore) takeWindow(entry *memoryEntry, limit *config.RateLimit, now time.Time) Result {
+	window := limit.Window()
+	period := now.UnixNano() / int64(window)
+	elapsed := time.Duration(now.UnixNano() - period*int64(window))
+
+	switch entry.period {
+	case period:
+	case period - 1:
+		entry.previous, entry.current = entry.current, 0
+	default:
+		entry.previous, entry.current = 0, 0
+	}
+	entry.period = period
+
+	count := float64(entry.previous)*float64(window-elapsed)/float64(window) + float64(entry.current)
+	allowed := count+1 <= float64(limit.Limit)
+	if allowed {
+		entry.current++
+	}
+	entry.expires = time.Unix(0, (period+2)*int64(window))
+
+	return slidingWindowResult(limit, entry.previous, entry.current, elapsed, allowed)
+}
+
+func (s *MemoryStore) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if now.After(entry.expires) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+type take struct {
+	at            time.Duration
+	key           string
+	wantAllowed   bool
+	wantRemaining int
+	wantRetry     time.Duration
+}
+
+func runTakes(t *testing.T, store Store, limit *config.RateLimit, takes []take) {
+	t.Helper()
+
+	defer store.Close()
+
+	// A whole number of windows, so the sliding window starts a period.
+	start := time.Unix(1000, 0)
+	for i, tt := range takes {
+		key := tt.key
+		if key == "" {
+			key = "ip:10.0.0.1"
+		}
+
+		result, err := store.Take(context.Background(), key, limit, start.Add(tt.at))
+		if err != nil {
+			t.Fatalf("take %d: Take() error = %v", i, err)
+		}
+		if result.Allowed != tt.wantAllowed || result.Remaining != tt.wantRemaining {
+			t.Errorf("take %d at %v: allowed %v remaining %d, want %v %d", i, tt.at, result.Allowed, result.Remaining, tt.wantAllowed, tt.wantRemaining)
+		}
+		// Rates are floats; a nanosecond either way is rounding.
+		if got := result.RetryAfter.Round(time.Microsecond); got != tt.wantRetry {
+			t.Errorf("take %d at %v: retry after %v, want %v", i, tt.at, got, tt.wantRetry)
+		}
+	}
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	testTokenBucket(t, NewMemoryStore())
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	testSlidingWindow(t, NewMemoryStore())
+}
+
+// testTokenBucket and testSlidingWindow are run against every store, which
+// must count alike.
+func testTokenBucket(t *testing.T, store Store) {
+	// Ten a second with bursts of five: a token every 100ms.
+	limit := &config.RateLimit{
+		Name:      "api",
+		Algorithm: config.AlgorithmTokenBucket,
+		Limit:     10,
+		Period:    config.Duration(time.Second),
+		Burst:     5,
+	}
+
+	runTakes(t, store, limit, []take{
+		{at: 0, wantAllowed: true, wantRemaining: 4},
+		{at: 0, wantAllowed: true, wantRemaining: 3},
+		{at: 0, wantAllowed: true, wantRemaining: 2},
+		{at: 0, wantAllowed: true, wantRemaining: 1},
+		{at: 0, wantAllowed: true, wantRemaining: 0},
+		{at: 0, wantRetry: 100 * time.Millisecond},
+		{at: 0, key: "ip:10.0.0.2", wantAllowed: true, wantRemaining: 4},
+		{at: 50 * time.Millisecond, wantRetry: 50 * time.Millisecond},
+		{at: 100 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		{at: 250 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		// The bucket never holds more than the burst.
+		{at: time.Minute, wantAllowed: true, wantRemaining: 4},
+	})
+}
+
+func testSlidingWindow(t *testing.T, store Store) {
+	limit := &config.RateLimit{
+		Name:      "login",
+		Algorithm: config.AlgorithmSlidingWindow,
+		Limit:     4,
+		Period:    config.Duration(time.Second),
+	}
+
+	runTakes(t, store, limit, []take{
+		{at: 0, wantAllowed: true, wantRemaining: 3},
+		{at: 0, wantAllowed: true, wantRemaining: 2},
+		{at: 0, wantAllowed: true, wantRemaining: 1},
+		{at: 0, wantAllowed: true, wantRemaining: 0},
+		// Allowed once the four have faded to three, a quarter into the
+		// next period.
+		{at: 0, wantRetry: 1250 * time.Millisecond},
+		{at: 0, key: "ip:10.0.0.2", wantAllowed: true, wantRemaining: 3},
+		// Halfway through the next period the previous four count as two.
+		{at: 1500 * time.Millisecond, wantAllowed: true, wantRemaining: 1},
+		{at: 1500 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		{at: 1500 * time.Millisecond, wantRetry: 250 * time.Millisecond},
+		{at: 1750 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		// Two periods later nothing is counted any more.
+		{at: 3500 * time.Millisecond, wantAllowed: true, wantRemaining: 3},
+	})
+}
@@ -0,0 +1,121 @@
+// Package ratelimit counts requests against the rate limits of the route
+// table, in memory or in a Redis server shared by every gateway instance.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+// Result is the outcome of counting a request against a rate limit.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the full limit is available again.
+	Reset time.Duration
+	// RetryAfter is how long until a request is allowed again, when this
+	// one was not.
+	RetryAfter time.Duration
+}
+
+// Store keeps the request counts of rate limits.
+type Store interface {
+	// Take counts a request at now against limit for key, which already
+	// includes the limit's name.
+	Take(ctx context.Context, key string, limit *config.RateLimit, now time.Time) (Result, error)
+	Close() error
+}
+
+// Limiter counts requests against rate limits with a store. Which limits
+// apply comes from the route table the request is served with.
+type Limiter struct {
+	store Store
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Take counts a request against limit for key, such as ip:10.0.0.1.
+func (l *Limiter) Take(ctx context.Context, limit *config.RateLimit, key string) (Result, error) {
+	return l.store.Take(ctx, limit.Name+":"+key, limit, time.Now())
+}
+
+func (l *Limiter) Close() error {
+	return l.store.Close()
+}
+
+// tokenRate is how many tokens a token bucket gains per nanosecond.
+func tokenRate(limit *config.RateLimit) float64 {
+	return float64(limit.Limit) / float64(limit.Window())
+}
+
+// tokenBucketResult is the result of a token bucket left with tokens.
+func tokenBucketResult(limit *config.RateLimit, tokens float64, allowed bool) Result {
+	rate := tokenRate(limit)
+	capacity := limit.Capacity()
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     capacity,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((float64(capacity) - tokens) / rate)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1 - tokens) / rate))
+	}
+	return result
+}
+
+// slidingWindowResult is the result of a sliding window whose previous and
+// current periods counted previous and current requests, elapsed into the
+// current period.
+func slidingWindowResult(limit *config.RateLimit, previous, current int64, elapsed time.Duration, allowed bool) Result {
+	period := float64(limit.Window())
+	count := float64(previous)*(period-float64(elapsed))/period + float64(current)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Limit,
+		Remaining: int(math.Max(0, math.Floor(float64(limit.Limit)-count))),
+	}
+	switch {
+	case current > 0:
+		result.Reset = 2*limit.Window() - elapsed
+	case previous > 0:
+		result.Reset = limit.Window() - elapsed
+	}
+
+	if !allowed {
+		result.RetryAfter = slidingWindowRetry(limit, previous, current, elapsed)
+	}
+	return result
+}
+
+// slidingWindowRetry is how long until a sliding window that refused a
+// request allows one.
+func slidingWindowRetry(limit *config.RateLimit, previous, current int64, elapsed time.Duration) time.Duration {
+	period := float64(limit.Window())
+	allowed := float64(limit.Limit - 1)
+
+	// The previous period's requests fade out as the window moves through
+	// the current period.
+	if float64(current) <= allowed && previous > 0 {
+		wait := period*(1-(allowed-float64(current))/float64(previous)) - float64(elapsed)
+		if wait < period-float64(elapsed) {
+			return time.Duration(math.Ceil(math.Max(wait, 0)))
+		}
+	}
+
+	// Otherwise the current period's requests have to fade out from the
+	// next one.
+	wait := period - float64(elapsed)
+	if float64(current) > allowed {
+		wait += period * (1 - allowed/float64(current))
+	}
+	return time.Duration(math.Ceil(wait))
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+// tokenBucketScript refills and takes from a token bucket kept as a hash.
+// Times are in microseconds, the rate in tokens per microsecond.
+var tokenBucketScript = newRedisScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+if now > at then
+  tokens = math.min(capacity, tokens + (now - at) * rate)
+  at = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', string.format('%.17g', tokens), 'at', string.format('%.0f', at))
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate / 1000) + 1000)
+return {allowed, string.format('%.17g', tokens)}
+`)
+
+// slidingWindowScript counts a request in the current period when the
+// previous period's count, weighted by ARGV[2], and the current one leave
+// room for it.
+var slidingWindowScript = newRedisScript(`
+local limit = tonumber(ARGV[1])
+local weight = tonumber(ARGV[2])
+
+local previous = tonumber(redis.call('GET', KEYS[1])) or 0
+local current = tonumber(redis.call('GET', KEYS[2])) or 0
+
+local allowed = 0
+if previous * weight + current + 1 <= limit then
+  current = redis.call('INCR', KEYS[2])
+  redis.call('PEXPIRE', KEYS[2], ARGV[3])
+  allowed = 1
+end
+
+return {allowed, previous, current}
+`)
+
+// RedisStore counts requests in Redis, or a server speaking its protocol,
+// so that every gateway instance enforces the same limits. Each count is a
+// single script, which keeps it atomic across instances.
+type RedisStore struct {
+	client *redisClient
+}
+
+func NewRedisStore(addr, password string) *RedisStore {
+	return &RedisStore{client: newRedisClient(addr, password)}
+}
+
+// Ping checks that the server can be reached.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	_, err := s.client.do(ctx, "PING")
+	return err
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit *config.RateLimit, now time.Time) (Result, error) {
+	key = "ratelimit:{" + key + "}"
+
+	if limit.Algorithm == config.AlgorithmTokenBucket {
+		return s.takeToken(ctx, key, limit, now)
+	}
+	return s.takeWindow(ctx, key, limit, now)
+}
+
+func (s *RedisStore) takeToken(ctx context.Context, key string, limit *config.RateLimit, now time.Time) (Result, error) {
+	perMicrosecond := tokenRate(limit) * float64(time.Microsecond)
+
+	reply, err := s.client.eval(ctx, tokenBucketScript, []string{key},
+		strconv.Itoa(limit.Capacity()),
+		strconv.FormatFloat(perMicrosecond, 'f', -1, 64),
+		strconv.FormatInt(now.UnixMicro(), 10),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Result{}, fmt.Errorf("token bucket script: unexpected reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("token bucket script: %w", err)
+	}
+
+	return tokenBucketResult(limit, tokens, allowed == 1), nil
+}
+
+func (s *RedisStore) takeWindow(ctx context.Context, key string, limit *config.RateLimit, now time.Time) (Result, error) {
+	window := limit.Window()
+	period := now.UnixNano() / int64(window)
+	elapsed := time.Duration(now.UnixNano() - period*int64(window))
+	weight := float64(window-elapsed) / float64(window)
+
+	reply, err := s.client.eval(ctx, slidingWindowScript,
+		[]string{key + ":" + strconv.FormatInt(period-1, 10), key + ":" + strconv.FormatInt(period, 10)},
+		strconv.Itoa(limit.Limit),
+		strconv.FormatFloat(weight, 'f', -1, 64),
+		strconv.FormatInt((2*window).Milliseconds(), 10),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("sliding window script: unexpected reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	previous, _ := values[1].(int64)
+	current, _ := values[2].(int64)
+
+	return slidingWindowResult(limit, previous, current, elapsed, allowed == 1), nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.close()
+}
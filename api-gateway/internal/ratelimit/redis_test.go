@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	return NewRedisStore(server.Addr(), ""), server
+}
+
+func TestRedisStoreTokenBucket(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	testTokenBucket(t, store)
+}
+
+func TestRedisStoreSlidingWindow(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	testSlidingWindow(t, store)
+}
+
+var redisTestLimit = &config.RateLimit{
+	Name:      "login",
+	Algorithm: config.AlgorithmSlidingWindow,
+	Limit:     4,
+	Period:    config.Duration(time.Second),
+}
+
+func TestRedisStoreSharedBetweenInstances(t *testing.T) {
+	first, server := newTestRedisStore(t)
+	defer first.Close()
+	second := NewRedisStore(server.Addr(), "")
+	defer second.Close()
+
+	now := time.Unix(1000, 0)
+	for i, store := range []*RedisStore{first, second, first, second} {
+		if result, err := store.Take(context.Background(), "ip:10.0.0.1", redisTestLimit, now); err != nil || !result.Allowed {
+			t.Fatalf("take %d: Take() = %+v, %v, want allowed", i, result, err)
+		}
+	}
+
+	result, err := second.Take(context.Background(), "ip:10.0.0.1", redisTestLimit, now)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if result.Allowed || result.RetryAfter != 1250*time.Millisecond {
+		t.Errorf("Take() over the limit = allowed %v retry after %v, want refused for 1.25s", result.Allowed, result.RetryAfter)
+	}
+}
+
+func TestRedisStorePassword(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	store := NewRedisStore(server.Addr(), "secret")
+	defer store.Close()
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() with the password error = %v", err)
+	}
+
+	wrong := NewRedisStore(server.Addr(), "guess")
+	defer wrong.Close()
+	var replyErr redisError
+	if err := wrong.Ping(context.Background()); !errors.As(err, &replyErr) {
+		t.Errorf("Ping() with a wrong password error = %v, want an error reply", err)
+	}
+}
+
+func TestRedisStoreDroppedConnection(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	defer store.Close()
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+
+	if _, err := store.Take(ctx, "ip:10.0.0.1", redisTestLimit, now); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+
+	// A restart drops the idle connection and forgets the scripts; the
+	// next take dials again and loads them.
+	server.Restart()
+	result, err := store.Take(ctx, "ip:10.0.0.1", redisTestLimit, now)
+	if err != nil {
+		t.Fatalf("Take() after a restart error = %v", err)
+	}
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Take() after a restart = allowed %v remaining %d, want allowed 2", result.Allowed, result.Remaining)
+	}
+
+	// While the server is down takes fail, and the limit is left to the
+	// caller.
+	server.Close()
+	if _, err := store.Take(ctx, "ip:10.0.0.1", redisTestLimit, now); err == nil {
+		t.Fatal("Take() with the server down error = nil, want an error")
+	}
+	if err := store.Ping(ctx); err == nil {
+		t.Error("Ping() with the server down error = nil, want an error")
+	}
+
+	if err := server.Restart(); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	if _, err := store.Take(ctx, "ip:10.0.0.1", redisTestLimit, now); err != nil {
+		t.Errorf("Take() once the server is back error = %v", err)
+	}
+}
+
+func TestRedisStoreClosed(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	store.Close()
+
+	if _, err := store.Take(context.Background(), "ip:10.0.0.1", redisTestLimit, time.Unix(1000, 0)); err == nil {
+		t.Error("Take() on a closed store error = nil, want an error")
+	}
+}
@@ -0,0 +1,239 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// redisTimeout bounds a command when the context has no deadline.
+	redisTimeout = 500 * time.Millisecond
+	// redisIdleConns is how many connections are kept for reuse.
+	redisIdleConns = 16
+)
+
+// redisError is an error reply from the server. The connection stays usable
+// after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisScript is a Lua script run with EVALSHA, falling back to EVAL the
+// first time a server has not seen it.
+type redisScript struct {
+	source string
+	sha    string
+}
+
+func newRedisScript(source string) *redisScript {
+	sum := sha1.Sum([]byte(source))
+	return &redisScript{source: source, sha: hex.EncodeToString(sum[:])}
+}
+
+// redisClient is a small client for the Redis protocol (RESP2), covering
+// what the rate limit scripts need.
+type redisClient struct {
+	addr     string
+	password string
+
+	mu     sync.Mutex
+	idle   []*redisConn
+	closed bool
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRedisClient(addr, password string) *redisClient {
+	return &redisClient{addr: addr, password: password}
+}
+
+// eval runs script with keys and args.
+func (c *redisClient) eval(ctx context.Context, script *redisScript, keys []string, args ...string) (interface{}, error) {
+	command := append([]string{"EVALSHA", script.sha, strconv.Itoa(len(keys))}, keys...)
+	command = append(command, args...)
+
+	reply, err := c.do(ctx, command...)
+	var replyErr redisError
+	if errors.As(err, &replyErr) && strings.HasPrefix(string(replyErr), "NOSCRIPT") {
+		command[0], command[1] = "EVAL", script.source
+		return c.do(ctx, command...)
+	}
+	return reply, err
+}
+
+// do sends a command and reads its reply. A command that fails on an idle
+// connection is sent again on a new one, as the server may have closed the
+// idle one since, by timing it out or restarting.
+func (c *redisClient) do(ctx context.Context, args ...string) (interface{}, error) {
+	for {
+		conn, reused, err := c.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err := conn.do(ctx, args...)
+		var replyErr redisError
+		if err != nil && !errors.As(err, &replyErr) {
+			conn.conn.Close()
+			if reused && ctx.Err() == nil {
+				continue
+			}
+			return nil, err
+		}
+
+		c.put(conn)
+		return reply, err
+	}
+}
+
+// get takes an idle connection, reporting it as reused, or dials a new one.
+func (c *redisClient) get(ctx context.Context) (*redisConn, bool, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, false, errors.New("redis: client is closed")
+	}
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, true, nil
+	}
+	c.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: redisTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, false, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if c.password != "" {
+		if _, err := conn.do(ctx, "AUTH", c.password); err != nil {
+			netConn.Close()
+			return nil, false, err
+		}
+	}
+
+	return conn, false, nil
+}
+
+func (c *redisClient) put(conn *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(c.idle) >= redisIdleConns {
+		conn.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+func (c *redisClient) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, conn := range c.idle {
+		conn.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+func (c *redisConn) do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, command.String()); err != nil {
+		return nil, err
+	}
+
+	return c.readReply()
+}
+
+// readReply reads one reply: simple strings and bulk strings as string,
+// integers as int64, arrays as []interface{} and nil as nil. Error replies
+// are returned as a redisError, or as an element of an array.
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", payload)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", payload)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, count)
+		for i := range values {
+			value, err := c.readReply()
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				// Keep reading so the connection stays in step.
+				values[i] = replyErr
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,36 @@
+// Package requestid carries the ID that ties together the log entries a
+// request leaves in the gateway and in the services it calls.
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the header the ID is accepted from, echoed in and forwarded as.
+const Header = "X-Request-ID"
+
+// maxLength caps IDs sent by clients, as they end up in every log entry.
+const maxLength = 128
+
+// New returns a random ID.
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether an ID sent by a client can be used as is: it must be
+// non-empty and only hold printable ASCII other than spaces, so it cannot
+// forge log entries or headers.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", want: true},
+		{name: "printable ascii", id: "req_1:a/b=c", want: true},
+		{name: "longest", id: strings.Repeat("a", maxLength), want: true},
+		{name: "empty"},
+		{name: "too long", id: strings.Repeat("a", maxLength+1)},
+		{name: "space", id: "req 1"},
+		{name: "newline", id: "req-1\nlevel=ERROR"},
+		{name: "non ascii", id: "req-é"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.id); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	first, second := New(), New()
+	if !Valid(first) || len(first) != 32 {
+		t.Errorf("New() = %q, want 32 hex digits", first)
+	}
+	if first == second {
+		t.Errorf("New() returned %q twice", first)
+	}
+}
@@ -0,0 +1,119 @@
+package resilience
+
+import (
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets calls through.
+	Closed State = iota
+	// Open fails calls at once.
+	Open
+	// HalfOpen lets a few probe calls through to see whether the upstream
+	// has recovered.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker is a circuit breaker. Its caller serializes access to it.
+type breaker struct {
+	state     State
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+	changes   map[State]int64
+}
+
+// allow reports whether a call may go to the upstream at now, and whether it
+// is a half-open probe.
+func (b *breaker) allow(policy *config.CircuitBreakerPolicy, now time.Time) (allowed, probe bool) {
+	if policy == nil {
+		b.setState(Closed, now)
+		return true, false
+	}
+
+	switch b.state {
+	case Open:
+		if now.Sub(b.openedAt) < time.Duration(policy.OpenFor) {
+			return false, false
+		}
+		b.setState(HalfOpen, now)
+		fallthrough
+	case HalfOpen:
+		if b.probes >= policy.Probes {
+			return false, false
+		}
+		b.probes++
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// record counts the outcome of a call allow let through.
+func (b *breaker) record(policy *config.CircuitBreakerPolicy, probe, failed bool, now time.Time) {
+	if policy == nil {
+		return
+	}
+
+	if probe {
+		if b.state != HalfOpen {
+			return
+		}
+		b.probes--
+		if failed {
+			b.setState(Open, now)
+			return
+		}
+		b.successes++
+		if b.successes >= policy.Probes {
+			b.setState(Closed, now)
+		}
+		return
+	}
+
+	if b.state != Closed {
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= policy.Failures {
+		b.setState(Open, now)
+	}
+}
+
+func (b *breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	b.failures, b.probes, b.successes = 0, 0, 0
+	if state == Open {
+		b.openedAt = now
+	}
+
+	if b.changes == nil {
+		b.changes = map[State]int64{}
+	}
+	b.changes[state]++
+}
@@ -0,0 +1,69 @@
+package resilience
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idempotentPrefixes start the names of gRPC methods that only read.
+var idempotentPrefixes = []string{"Get", "List", "Lookup", "Validate", "Quote", "Calculate", "Evaluate"}
+
+// DialOption applies the policy of upstream to the unary calls of a gRPC
+// client connection.
+func (u *Upstreams) DialOption(upstream string) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(u.unaryInterceptor(upstream))
+}
+
+func (u *Upstreams) unaryInterceptor(name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		up, policy := u.get(ctx, name)
+
+		idempotent := isIdempotent(method)
+		if !idempotent && policy.Retry != nil {
+			for _, listed := range policy.Retry.Methods {
+				idempotent = idempotent || listed == method
+			}
+		}
+
+		var err error
+		rejected := up.call(ctx, policy, idempotent, func(ctx context.Context) bool {
+			attemptCtx, cancel := attemptContext(ctx, policy)
+			defer cancel()
+
+			err = invoker(attemptCtx, method, req, reply, cc, opts...)
+			return grpcFailed(ctx, err)
+		})
+		if rejected != nil {
+			return status.Errorf(codes.Unavailable, "%s service unavailable: %v", name, rejected)
+		}
+		return err
+	}
+}
+
+func isIdempotent(method string) bool {
+	name := path.Base(method)
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcFailed reports whether err means the upstream is unhealthy. A deadline
+// only counts when it is the attempt's own, not the caller's.
+func grpcFailed(ctx context.Context, err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	case codes.DeadlineExceeded:
+		return ctx.Err() == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Transport applies the policy of upstream to the requests sent through
+// base.
+func (u *Upstreams) Transport(upstream string, base http.RoundTripper) http.RoundTripper {
+	return &transport{upstreams: u, name: upstream, base: base}
+}
+
+type transport struct {
+	upstreams *Upstreams
+	name      string
+	base      http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	up, policy := t.upstreams.get(req.Context(), t.name)
+
+	idempotent := (req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions) &&
+		(req.Body == nil || req.Body == http.NoBody)
+
+	var resp *http.Response
+	var err error
+	rejected := up.call(req.Context(), policy, idempotent, func(ctx context.Context) bool {
+		if resp != nil {
+			// A retry replaces the failed response.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		attemptCtx, cancel := attemptContext(ctx, policy)
+		resp, err = t.base.RoundTrip(req.Clone(attemptCtx))
+		if err != nil {
+			cancel()
+			return ctx.Err() == nil
+		}
+
+		// The timeout also covers reading the body.
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	if rejected != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, rejected)
+	}
+	return resp, err
+}
+
+// cancelBody ends the attempt's context once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
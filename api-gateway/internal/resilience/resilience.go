@@ -0,0 +1,206 @@
+// Package resilience applies the upstream policies of the route table to the
+// calls the gateway makes: per attempt timeouts, retries of idempotent calls
+// with jittered backoff, circuit breakers and bulkheads capping the calls in
+// flight.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+var (
+	// ErrCircuitOpen is returned for calls to an upstream whose circuit
+	// breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrBulkheadFull is returned for calls to an upstream that already
+	// has as many calls in flight as its policy allows.
+	ErrBulkheadFull = errors.New("too many calls in flight")
+)
+
+// Upstreams keeps, per upstream, its circuit breaker and call counts. Calls
+// are made under the policies of the route table their context carries (see
+// config.WithTable), so a reloaded table applies from the next request on;
+// breakers keep their state. Calls without a table get the empty policy.
+type Upstreams struct {
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+}
+
+type upstream struct {
+	name     string
+	inFlight atomic.Int64
+
+	mu      sync.Mutex
+	breaker breaker
+	stats   Stats
+}
+
+// Stats are the counts of calls to an upstream since the gateway started.
+type Stats struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// StateChanges counts how often the breaker went into each state.
+	StateChanges map[string]int64 `json:"state_changes"`
+	InFlight     int64            `json:"in_flight"`
+	Calls        int64            `json:"calls"`
+	Failures     int64            `json:"failures"`
+	Retries      int64            `json:"retries"`
+	// Rejected counts calls failed at once by the breaker or bulkhead.
+	RejectedOpen int64 `json:"rejected_open"`
+	RejectedFull int64 `json:"rejected_full"`
+}
+
+func NewUpstreams() *Upstreams {
+	return &Upstreams{upstreams: map[string]*upstream{}}
+}
+
+// Stats returns the counts of every upstream called so far, by name.
+func (u *Upstreams) Stats() []Stats {
+	u.mu.Lock()
+	upstreams := make([]*upstream, 0, len(u.upstreams))
+	for _, up := range u.upstreams {
+		upstreams = append(upstreams, up)
+	}
+	u.mu.Unlock()
+
+	stats := make([]Stats, 0, len(upstreams))
+	for _, up := range upstreams {
+		up.mu.Lock()
+		s := up.stats
+		s.Name = up.name
+		s.State = up.breaker.state.String()
+		s.StateChanges = map[string]int64{}
+		for state, count := range up.breaker.changes {
+			s.StateChanges[state.String()] = count
+		}
+		up.mu.Unlock()
+
+		s.InFlight = up.inFlight.Load()
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+func (u *Upstreams) get(ctx context.Context, name string) (*upstream, *config.UpstreamPolicy) {
+	policy := config.TableFrom(ctx).Policy(name)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	up, ok := u.upstreams[name]
+	if !ok {
+		up = &upstream{name: name}
+		u.upstreams[name] = up
+	}
+	return up, policy
+}
+
+// attemptFunc makes one attempt of a call and reports whether it failed in
+// a way that counts against the upstream: it is down, timed out or broken,
+// not that it refused the request.
+type attemptFunc func(ctx context.Context) (failed bool)
+
+// call makes a call to the upstream under policy, retrying it when it is
+// idempotent. It returns ErrBulkheadFull or ErrCircuitOpen when no attempt
+// was made; the outcome of attempts is for attempt to keep.
+func (up *upstream) call(ctx context.Context, policy *config.UpstreamPolicy, idempotent bool, attempt attemptFunc) error {
+	if limit := int64(policy.MaxConcurrent); limit > 0 {
+		if up.inFlight.Add(1) > limit {
+			up.inFlight.Add(-1)
+			up.count(func(s *Stats) { s.RejectedFull++ })
+			return ErrBulkheadFull
+		}
+	} else {
+		up.inFlight.Add(1)
+	}
+	defer up.inFlight.Add(-1)
+
+	attempts := 1
+	if idempotent && policy.Retry != nil {
+		attempts += policy.Retry.Attempts
+	}
+
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if !sleep(ctx, backoff(policy.Retry, i)) {
+				return nil
+			}
+			up.count(func(s *Stats) { s.Retries++ })
+		}
+
+		up.mu.Lock()
+		allowed, probe := up.breaker.allow(policy.CircuitBreaker, time.Now())
+		up.mu.Unlock()
+		if !allowed {
+			up.count(func(s *Stats) { s.RejectedOpen++ })
+			if i == 0 {
+				return ErrCircuitOpen
+			}
+			return nil
+		}
+
+		failed := attempt(ctx)
+
+		up.mu.Lock()
+		up.breaker.record(policy.CircuitBreaker, probe, failed, time.Now())
+		up.stats.Calls++
+		if failed {
+			up.stats.Failures++
+		}
+		up.mu.Unlock()
+
+		if !failed || ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (up *upstream) count(update func(s *Stats)) {
+	up.mu.Lock()
+	update(&up.stats)
+	up.mu.Unlock()
+}
+
+// attemptContext bounds an attempt by the policy's timeout, unless the caller
+// gave the call a deadline of its own.
+func attemptContext(ctx context.Context, policy *config.UpstreamPolicy) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, policy.AttemptTimeout())
+}
+
+// backoff is a random wait up to the retry's backoff doubled for every
+// earlier retry, capped at its max backoff.
+func backoff(retry *config.RetryPolicy, retries int) time.Duration {
+	limit := time.Duration(retry.Backoff) << (retries - 1)
+	if limit <= 0 || limit > time.Duration(retry.MaxBackoff) {
+		limit = time.Duration(retry.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(limit) + 1))
+}
+
+// sleep waits for d and reports whether ctx was still live after it.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
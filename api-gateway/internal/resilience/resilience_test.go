@@ -0,0 +1,268 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+)
+
+func TestBackoff(t *testing.T) {
+	retry := &config.RetryPolicy{
+		Backoff:    config.Duration(100 * time.Millisecond),
+		MaxBackoff: config.Duration(time.Second),
+	}
+
+	tests := []struct {
+		retries int
+		limit   time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		// The doubling overflows long before this; it stays capped.
+		{80, time.Second},
+	}
+
+	for _, tt := range tests {
+		var longest time.Duration
+		for i := 0; i < 1000; i++ {
+			wait := backoff(retry, tt.retries)
+			if wait < 0 || wait > tt.limit {
+				t.Fatalf("backoff(%d) = %v, want between 0 and %v", tt.retries, wait, tt.limit)
+			}
+			if wait > longest {
+				longest = wait
+			}
+		}
+		// The waits are jittered over the whole range, not pinned low.
+		if longest < tt.limit/2 {
+			t.Errorf("backoff(%d) longest of 1000 = %v, want close to %v", tt.retries, longest, tt.limit)
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	policy := &config.CircuitBreakerPolicy{
+		Failures: 3,
+		OpenFor:  config.Duration(10 * time.Second),
+		Probes:   2,
+	}
+
+	type step struct {
+		at          time.Duration
+		wantAllowed bool
+		failed      bool
+		wantState   State
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "success resets the failure count",
+			steps: []step{
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Open},
+			},
+		},
+		{
+			name: "opens, probes and closes",
+			steps: []step{
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Open},
+				{at: 9 * time.Second, wantState: Open},
+				{at: 10 * time.Second, wantAllowed: true, wantState: HalfOpen},
+				{at: 10 * time.Second, wantAllowed: true, wantState: Closed},
+				{at: 10 * time.Second, wantAllowed: true, failed: true, wantState: Closed},
+			},
+		},
+		{
+			name: "a failed probe opens it again",
+			steps: []step{
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Closed},
+				{wantAllowed: true, failed: true, wantState: Open},
+				{at: 10 * time.Second, wantAllowed: true, failed: true, wantState: Open},
+				{at: 15 * time.Second, wantState: Open},
+				{at: 20 * time.Second, wantAllowed: true, wantState: HalfOpen},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b breaker
+			start := time.Unix(1000, 0)
+
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				allowed, probe := b.allow(policy, now)
+				if allowed != s.wantAllowed {
+					t.Fatalf("step %d: allow() = %v, want %v", i, allowed, s.wantAllowed)
+				}
+				if allowed {
+					b.record(policy, probe, s.failed, now)
+				}
+				if b.state != s.wantState {
+					t.Fatalf("step %d: state = %s, want %s", i, b.state, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerLimitsProbes(t *testing.T) {
+	policy := &config.CircuitBreakerPolicy{Failures: 1, OpenFor: config.Duration(time.Second), Probes: 2}
+	now := time.Unix(1000, 0)
+
+	var b breaker
+	b.allow(policy, now)
+	b.record(policy, false, true, now)
+
+	later := now.Add(time.Second)
+	for i := 0; i < policy.Probes; i++ {
+		if allowed, probe := b.allow(policy, later); !allowed || !probe {
+			t.Fatalf("probe %d: allow() = %v, %v, want a probe", i, allowed, probe)
+		}
+	}
+	if allowed, _ := b.allow(policy, later); allowed {
+		t.Fatalf("allow() with every probe in flight = true, want false")
+	}
+}
+
+func TestUpstreamCall(t *testing.T) {
+	policy := &config.UpstreamPolicy{
+		Retry: &config.RetryPolicy{
+			Attempts:   2,
+			Backoff:    config.Duration(time.Microsecond),
+			MaxBackoff: config.Duration(time.Microsecond),
+		},
+		CircuitBreaker: &config.CircuitBreakerPolicy{
+			Failures: 5,
+			OpenFor:  config.Duration(time.Minute),
+			Probes:   1,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		idempotent   bool
+		failures     int
+		wantAttempts int
+	}{
+		{name: "succeeds", idempotent: true, wantAttempts: 1},
+		{name: "idempotent is retried", idempotent: true, failures: 1, wantAttempts: 2},
+		{name: "idempotent gives up", idempotent: true, failures: 10, wantAttempts: 3},
+		{name: "not idempotent is not retried", failures: 10, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &upstream{name: "orders"}
+
+			attempts := 0
+			err := up.call(context.Background(), policy, tt.idempotent, func(ctx context.Context) bool {
+				attempts++
+				return attempts <= tt.failures
+			})
+			if err != nil {
+				t.Fatalf("call() error = %v", err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if up.stats.Retries != int64(tt.wantAttempts-1) {
+				t.Errorf("retries = %d, want %d", up.stats.Retries, tt.wantAttempts-1)
+			}
+		})
+	}
+
+	t.Run("open breaker fails at once", func(t *testing.T) {
+		up := &upstream{name: "orders"}
+		for i := 0; i < 2; i++ {
+			up.call(context.Background(), policy, true, func(ctx context.Context) bool { return true })
+		}
+
+		err := up.call(context.Background(), policy, true, func(ctx context.Context) bool {
+			t.Fatal("attempt made through an open breaker")
+			return false
+		})
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("call() error = %v, want %v", err, ErrCircuitOpen)
+		}
+	})
+
+	t.Run("bulkhead", func(t *testing.T) {
+		up := &upstream{name: "orders"}
+		full := &config.UpstreamPolicy{MaxConcurrent: 1}
+
+		err := up.call(context.Background(), full, false, func(ctx context.Context) bool {
+			inner := up.call(ctx, full, false, func(ctx context.Context) bool { return false })
+			if !errors.Is(inner, ErrBulkheadFull) {
+				t.Errorf("call() while full error = %v, want %v", inner, ErrBulkheadFull)
+			}
+			return false
+		})
+		if err != nil {
+			t.Fatalf("call() error = %v", err)
+		}
+	})
+}
+
+func TestAttemptContext(t *testing.T) {
+	policy := &config.UpstreamPolicy{Timeout: config.Duration(2 * time.Second)}
+
+	tests := []struct {
+		name         string
+		callerLimit  time.Duration
+		wantDeadline time.Duration
+	}{
+		{name: "policy timeout without a deadline", wantDeadline: 2 * time.Second},
+		{name: "caller deadline kept when longer", callerLimit: time.Minute, wantDeadline: time.Minute},
+		{name: "caller deadline kept when shorter", callerLimit: time.Second, wantDeadline: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.callerLimit > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerLimit)
+				defer cancel()
+			}
+
+			attemptCtx, cancel := attemptContext(ctx, policy)
+			defer cancel()
+
+			deadline, ok := attemptCtx.Deadline()
+			if !ok {
+				t.Fatal("attemptContext() has no deadline")
+			}
+			if left := time.Until(deadline); left > tt.wantDeadline || left < tt.wantDeadline-time.Second/2 {
+				t.Errorf("attemptContext() deadline in %v, want %v", left, tt.wantDeadline)
+			}
+		})
+	}
+
+	t.Run("canceled with the caller", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attemptCtx, attemptCancel := attemptContext(ctx, policy)
+		defer attemptCancel()
+
+		cancel()
+		if attemptCtx.Err() == nil {
+			t.Error("attemptContext() not canceled with the caller")
+		}
+	})
+}
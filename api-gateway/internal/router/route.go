@@ -1,25 +1,271 @@
 package router
 
 import (
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
 	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/middleware"
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, authHandler *handler.AuthHandler, healthHandler *handler.HealthHandler) {
+type Handlers struct {
+	Auth       *handler.AuthHandler
+	Health     *handler.HealthHandler
+	Address    *handler.AddressHandler
+	Seller     *handler.SellerHandler
+	Product    *handler.ProductHandler
+	Inventory  *handler.InventoryHandler
+	Cart       *handler.CartHandler
+	Order      *handler.OrderHandler
+	Checkout   *handler.CheckoutHandler
+	Payment    *handler.PaymentHandler
+	Ledger     *handler.LedgerHandler
+	Promotions *handler.PromotionsHandler
+	Tax        *handler.TaxHandler
+	Shipping   *handler.ShippingHandler
+	Proxy      *handler.ProxyHandler
+}
+
+// SetupRoutes registers the gateway's own routes and serves the route table
+// for every other path. It fails when the table overlaps the gateway's own
+// routes.
+func SetupRoutes(r *gin.Engine, h *Handlers) error {
 
-	r.GET("/", healthHandler.Index)
-	r.GET("/health", healthHandler.Health)
-	r.GET("/ready", healthHandler.Ready)
+	r.GET("/", h.Health.Index)
+	r.GET("/health", h.Health.Health)
+	r.GET("/ready", h.Health.Ready)
 
 	v1 := r.Group("/api/v1")
 	{
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/validate", authHandler.ValidateToken)
+			auth.POST("/register", h.Auth.Register)
+			auth.POST("/login", h.Auth.Login)
+			auth.POST("/refresh", h.Auth.RefreshToken)
+			auth.POST("/validate", h.Auth.ValidateToken)
+		}
+
+		me := v1.Group("/me", middleware.AuthMiddleware(h.Auth))
+		{
+			me.GET("", h.Auth.GetProfile)
+			me.PATCH("", h.Auth.UpdateProfile)
+			me.POST("/email", h.Auth.ChangeEmail)
+			me.POST("/password", h.Auth.ChangePassword)
+
+			addresses := me.Group("/addresses")
+			{
+				addresses.GET("", h.Address.ListAddresses)
+				addresses.POST("", h.Address.CreateAddress)
+				addresses.GET("/:id", h.Address.GetAddress)
+				addresses.PUT("/:id", h.Address.UpdateAddress)
+				addresses.DELETE("/:id", h.Address.DeleteAddress)
+				addresses.POST("/:id/default", h.Address.SetDefaultAddress)
+			}
+		}
+
+		cart := v1.Group("/cart", middleware.OptionalAuthMiddleware(h.Auth))
+		{
+			cart.GET("", h.Cart.GetCart)
+			cart.DELETE("", h.Cart.ClearCart)
+			cart.POST("/items", h.Cart.AddItem)
+			cart.PATCH("/items/:sku", h.Cart.UpdateItem)
+			cart.DELETE("/items/:sku", h.Cart.RemoveItem)
+			cart.POST("/coupons", h.Cart.ApplyCoupon)
+			cart.DELETE("/coupons/:code", h.Cart.RemoveCoupon)
+			cart.PUT("/currency", h.Cart.SetCurrency)
+		}
+
+		checkout := v1.Group("/checkout", middleware.AuthMiddleware(h.Auth))
+		{
+			checkout.POST("", h.Checkout.Checkout)
+			checkout.GET("/:id", h.Checkout.GetCheckout)
+		}
+
+		v1.POST("/shipping/rates", h.Shipping.QuoteRates)
+
+		// Providers and carriers authenticate webhooks by signature, not by
+		// token.
+		v1.POST("/payments/webhooks/:provider", h.Payment.HandleWebhook)
+		v1.POST("/shipping/webhooks/:carrier", h.Shipping.HandleWebhook)
+
+		orders := v1.Group("/orders", middleware.AuthMiddleware(h.Auth))
+		{
+			orders.GET("", h.Order.ListMyOrders)
+			orders.GET("/:id", h.Order.GetMyOrder)
+			orders.GET("/:id/history", h.Order.GetMyOrderHistory)
+			orders.GET("/:id/shipments", h.Shipping.ListMyOrderShipments)
+			orders.POST("/:id/cancel", h.Order.CancelMyOrder)
+			orders.POST("/:id/returns", h.Order.RequestReturn)
+		}
+
+		returns := v1.Group("/returns", middleware.AuthMiddleware(h.Auth))
+		{
+			returns.GET("", h.Order.ListMyReturns)
+			returns.GET("/:id", h.Order.GetMyReturn)
+			returns.POST("/:id/cancel", h.Order.CancelMyReturn)
+		}
+
+		v1.GET("/stores/:slug", h.Seller.GetStore)
+		v1.GET("/categories", h.Product.ListCategories)
+
+		products := v1.Group("/products")
+		{
+			products.GET("", h.Product.ListProducts)
+			products.GET("/:id", h.Product.GetProduct)
+		}
+
+		sellerProducts := v1.Group("/products", middleware.AuthMiddleware(h.Auth), middleware.ApprovedSeller(h.Seller))
+		{
+			sellerProducts.POST("", h.Product.CreateProduct)
+			sellerProducts.PATCH("/:id", h.Product.UpdateProduct)
+			sellerProducts.DELETE("/:id", h.Product.DeleteProduct)
+			sellerProducts.POST("/:id/publish", h.Product.PublishProduct)
+			sellerProducts.POST("/:id/unpublish", h.Product.UnpublishProduct)
+			sellerProducts.POST("/:id/variants", h.Product.AddVariant)
+			sellerProducts.PATCH("/:id/variants/:variant_id", h.Product.UpdateVariant)
+			sellerProducts.DELETE("/:id/variants/:variant_id", h.Product.DeleteVariant)
+			sellerProducts.PUT("/:id/variants/:variant_id/prices", h.Product.SetVariantPrices)
+		}
+
+		seller := v1.Group("/seller", middleware.AuthMiddleware(h.Auth), middleware.RoleMiddleware("seller"))
+		{
+			seller.POST("/store", h.Seller.ApplyStore)
+			seller.GET("/store", h.Seller.GetMyStore)
+			seller.PATCH("/store", h.Seller.UpdateStore)
+			seller.GET("/products", h.Product.ListMyProducts)
+			seller.GET("/products/:id", h.Product.GetMyProduct)
+			seller.GET("/orders", h.Order.ListSellerOrders)
+			seller.GET("/orders/:id", h.Order.GetSellerOrder)
+			seller.GET("/orders/:id/shipments", h.Shipping.ListSellerOrderShipments)
+			seller.POST("/orders/:id/shipments", h.Shipping.CreateSellerShipment)
+			seller.POST("/orders/:id/fulfill", h.Order.ChangeSellerOrderStatus("fulfilled"))
+			seller.POST("/orders/:id/ship", h.Order.ChangeSellerOrderStatus("shipped"))
+			seller.POST("/orders/:id/deliver", h.Order.ChangeSellerOrderStatus("delivered"))
+			seller.POST("/orders/:id/cancel", h.Order.ChangeSellerOrderStatus("cancelled"))
+			seller.GET("/returns", h.Order.ListSellerReturns)
+			seller.GET("/returns/:id", h.Order.GetSellerReturn)
+			seller.POST("/returns/:id/approve", h.Order.ChangeSellerReturnStatus("approved"))
+			seller.POST("/returns/:id/reject", h.Order.ChangeSellerReturnStatus("rejected"))
+			seller.POST("/returns/:id/receive", h.Order.ChangeSellerReturnStatus("received"))
+			seller.POST("/returns/:id/refund", h.Order.ChangeSellerReturnStatus("refunded"))
+			seller.GET("/balance", h.Ledger.GetMyBalance)
+			seller.GET("/ledger", h.Ledger.ListMyEntries)
+			seller.GET("/payouts", h.Ledger.ListMyPayouts)
+		}
+
+		admin := v1.Group("/admin", middleware.AuthMiddleware(h.Auth), middleware.RoleMiddleware("admin"))
+		{
+			admin.GET("/gateway/config", h.Proxy.GetConfig)
+			admin.GET("/gateway/upstreams", h.Proxy.GetUpstreams)
+			admin.GET("/gateway/log-level", gin.WrapH(logging.LevelHandler()))
+			admin.PUT("/gateway/log-level", gin.WrapH(logging.LevelHandler()))
+
+			admin.POST("/categories", h.Product.CreateCategory)
+
+			admin.GET("/exchange-rates", h.Product.ListExchangeRates)
+			admin.PUT("/exchange-rates", h.Product.SetExchangeRates)
+
+			admin.GET("/warehouses", h.Inventory.ListWarehouses)
+			admin.POST("/warehouses", h.Inventory.CreateWarehouse)
+
+			inventory := admin.Group("/inventory")
+			{
+				inventory.GET("", h.Inventory.GetStock)
+				inventory.PUT("/:sku/warehouses/:warehouse_id", h.Inventory.SetStock)
+				inventory.POST("/:sku/warehouses/:warehouse_id/adjust", h.Inventory.AdjustStock)
+			}
+
+			adminOrders := admin.Group("/orders")
+			{
+				adminOrders.GET("", h.Order.ListOrders)
+				adminOrders.GET("/:id", h.Order.GetOrder)
+				adminOrders.GET("/:id/history", h.Order.GetOrderHistory)
+				adminOrders.POST("/:id/status", h.Order.ChangeOrderStatus)
+			}
+
+			adminReturns := admin.Group("/returns")
+			{
+				adminReturns.GET("", h.Order.ListReturns)
+				adminReturns.GET("/:id", h.Order.GetReturn)
+				adminReturns.POST("/:id/status", h.Order.ChangeReturnStatus)
+			}
+
+			ledger := admin.Group("/ledger")
+			{
+				ledger.GET("/reconciliation", h.Ledger.GetReconciliationReport)
+				ledger.GET("/sellers/:id/balance", h.Ledger.GetSellerBalance)
+				ledger.GET("/sellers/:id/entries", h.Ledger.ListSellerEntries)
+			}
+
+			payouts := admin.Group("/payouts")
+			{
+				payouts.GET("", h.Ledger.ListPayouts)
+				payouts.POST("/batches", h.Ledger.CreatePayoutBatch)
+				payouts.GET("/batches/:id", h.Ledger.GetPayoutBatch)
+				payouts.POST("/:id/paid", h.Ledger.ChangePayoutStatus("paid"))
+				payouts.POST("/:id/failed", h.Ledger.ChangePayoutStatus("failed"))
+			}
+
+			promotions := admin.Group("/promotions")
+			{
+				promotions.GET("", h.Promotions.ListPromotions)
+				promotions.POST("", h.Promotions.CreatePromotion)
+				promotions.GET("/:id", h.Promotions.GetPromotion)
+				promotions.PUT("/:id", h.Promotions.UpdatePromotion)
+				promotions.POST("/:id/activate", h.Promotions.SetPromotionActive(true))
+				promotions.POST("/:id/deactivate", h.Promotions.SetPromotionActive(false))
+			}
+
+			admin.GET("/tax/rule-sets", h.Tax.ListRuleSets)
+
+			shipments := admin.Group("/shipments")
+			{
+				shipments.GET("", h.Shipping.ListShipments)
+				shipments.GET("/:id", h.Shipping.GetShipment)
+			}
+
+			stores := admin.Group("/stores")
+			{
+				stores.GET("", h.Seller.ListStores)
+				stores.GET("/:id", h.Seller.GetStoreByID)
+				stores.GET("/:id/history", h.Seller.GetStoreHistory)
+				stores.POST("/:id/review", h.Seller.ChangeStoreStatus("under_review"))
+				stores.POST("/:id/approve", h.Seller.ChangeStoreStatus("approved"))
+				stores.POST("/:id/reject", h.Seller.ChangeStoreStatus("rejected"))
+				stores.POST("/:id/suspend", h.Seller.ChangeStoreStatus("suspended"))
+				stores.POST("/:id/reinstate", h.Seller.ChangeStoreStatus("approved"))
+			}
 		}
 	}
 
+	// Paths none of the routes above serve are looked up in the route table,
+	// which may not overlap them.
+	var reserved []string
+	for _, route := range r.Routes() {
+		reserved = append(reserved, route.Path)
+	}
+	if err := h.Proxy.Reserve(reserved); err != nil {
+		return err
+	}
+	r.NoRoute(h.Proxy.Match, proxyAuth(h), h.Proxy.Serve)
+
+	return nil
+}
+
+// proxyAuth authenticates route table requests the way their route asks
+// for.
+func proxyAuth(h *Handlers) gin.HandlerFunc {
+	required := middleware.AuthMiddleware(h.Auth)
+	optional := middleware.OptionalAuthMiddleware(h.Auth)
+
+	return func(c *gin.Context) {
+		switch handler.ProxyRoute(c).Auth {
+		case config.AuthRequired:
+			required(c)
+		case config.AuthOptional:
+			optional(c)
+		default:
+			c.Next()
+		}
+	}
 }
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/config"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/handler"
+	"github.com/Dzaakk/micro-commerce/api-gateway/internal/resilience"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetupRoutesReservesGatewayPaths(t *testing.T) {
+	shipped, err := config.LoadRouteTable("../../routes.yaml")
+	if err != nil {
+		t.Fatalf("LoadRouteTable() error = %v", err)
+	}
+
+	overlapping := &config.RouteTable{
+		Upstreams: map[string]*config.Upstream{"search": {Protocol: "http", URL: "http://search:8080"}},
+		Routes:    []*config.Route{{Name: "cart", PathPrefix: "/api/v1/cart", Upstream: "search"}},
+	}
+	if err := overlapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		table   *config.RouteTable
+		wantErr string
+	}{
+		{name: "shipped route table", table: shipped},
+		{name: "table overlapping the cart", table: overlapping, wantErr: "overlaps the gateway's own route /api/v1/cart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, err := handler.NewProxyHandler(tt.table, resilience.NewUpstreams())
+			if err != nil {
+				t.Fatalf("NewProxyHandler() error = %v", err)
+			}
+			defer proxy.Close()
+
+			// The other handlers are only referenced while routes are set up.
+			err = SetupRoutes(gin.New(), &Handlers{Proxy: proxy})
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("SetupRoutes() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("SetupRoutes() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The metrics are served on the admin port, not to API clients.
+func TestSetupRoutesLeavesOutMetrics(t *testing.T) {
+	table := &config.RouteTable{}
+	if err := table.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	proxy, err := handler.NewProxyHandler(table, resilience.NewUpstreams())
+	if err != nil {
+		t.Fatalf("NewProxyHandler() error = %v", err)
+	}
+	defer proxy.Close()
+
+	r := gin.New()
+	if err := SetupRoutes(r, &Handlers{Proxy: proxy}); err != nil {
+		t.Fatalf("SetupRoutes() error = %v", err)
+	}
+	for _, route := range r.Routes() {
+		if route.Path == "/metrics" {
+			t.Errorf("SetupRoutes() registered %s %s", route.Method, route.Path)
+		}
+	}
+}
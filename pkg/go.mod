@@ -0,0 +1,3 @@
+module github.com/Dzaakk/micro-commerce/pkg
+
+go 1.23.0
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// LevelHandler serves the current level on GET and changes it on PUT with a
+// body such as {"level":"debug"}. It belongs behind admin authentication or
+// on a port only operators reach.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body struct {
+				Level string `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeProblem(w, r, http.StatusBadRequest, "body must be a JSON object with a level")
+				return
+			}
+
+			previous := Level()
+			if err := SetLevel(body.Level); err != nil {
+				writeProblem(w, r, http.StatusBadRequest, "level must be debug, info, warn or error")
+				return
+			}
+			// Logged at no less than the new level, so raising it to error
+			// still leaves a record of the change.
+			logLevel := max(slog.LevelWarn, level.Level())
+			slog.Log(r.Context(), logLevel, "Log level changed", slog.String("from", previous), slog.String("to", Level()))
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeProblem(w, r, http.StatusMethodNotAllowed, "use GET or PUT")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"level": Level()})
+	})
+}
+
+// writeProblem answers with RFC 7807 problem details, like the gateway does.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"type":     "about:blank",
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   detail,
+		"instance": r.URL.Path,
+	})
+}
@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetLevel(t *testing.T) {
+	logger, buf := setup(t)
+
+	logger.Debug("Hidden")
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	// Loggers made before the change follow it too.
+	logger.Debug("Shown")
+	slog.Debug("Default shown")
+
+	if err := SetLevel("WARN"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if Level() != "warn" {
+		t.Errorf("Level() = %q, want %q", Level(), "warn")
+	}
+	logger.Info("Hidden again")
+
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("SetLevel(verbose) error = nil, want an error")
+	}
+	if Level() != "warn" {
+		t.Errorf("Level() after an invalid level = %q, want %q kept", Level(), "warn")
+	}
+
+	var messages []string
+	for _, entry := range entries(t, buf) {
+		messages = append(messages, entry["msg"].(string))
+	}
+	if strings.Join(messages, ",") != "Shown,Default shown" {
+		t.Errorf("logged %v, want [Shown Default shown]", messages)
+	}
+}
+
+func TestSetupLevel(t *testing.T) {
+	setup(t)
+
+	Setup(Config{Level: "error", Output: new(strings.Builder)})
+	if Level() != "error" {
+		t.Errorf("Level() = %q, want %q", Level(), "error")
+	}
+
+	var buf strings.Builder
+	Setup(Config{Level: "loud", Output: &buf})
+	if Level() != "error" {
+		t.Errorf("Level() after an invalid LOG_LEVEL = %q, want %q kept", Level(), "error")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantLevel  string
+	}{
+		{name: "get", method: http.MethodGet, wantStatus: http.StatusOK, wantLevel: "info"},
+		{name: "put", method: http.MethodPut, body: `{"level":"debug"}`, wantStatus: http.StatusOK, wantLevel: "debug"},
+		{name: "unknown level", method: http.MethodPut, body: `{"level":"loud"}`, wantStatus: http.StatusBadRequest, wantLevel: "info"},
+		{name: "not json", method: http.MethodPut, body: `debug`, wantStatus: http.StatusBadRequest, wantLevel: "info"},
+		{name: "other method", method: http.MethodPost, body: `{"level":"debug"}`, wantStatus: http.StatusMethodNotAllowed, wantLevel: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, buf := setup(t)
+			SetLevel("info")
+
+			w := httptest.NewRecorder()
+			LevelHandler().ServeHTTP(w, httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if Level() != tt.wantLevel {
+				t.Errorf("Level() = %q, want %q", Level(), tt.wantLevel)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q error = %v", w.Body.String(), err)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if body["level"] != tt.wantLevel {
+					t.Errorf("body level = %v, want %q", body["level"], tt.wantLevel)
+				}
+				return
+			}
+			if w.Header().Get("Content-Type") != "application/problem+json" || body["status"] != float64(tt.wantStatus) {
+				t.Errorf("problem = %v with content type %q, want a problem with status %d", body, w.Header().Get("Content-Type"), tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, PUT" {
+				t.Errorf("Allow = %q, want %q", w.Header().Get("Allow"), "GET, PUT")
+			}
+			if buf.Len() != 0 {
+				t.Errorf("logged %q for a refused change", buf.String())
+			}
+		})
+	}
+
+	t.Run("change is logged", func(t *testing.T) {
+		_, buf := setup(t)
+		SetLevel("info")
+
+		LevelHandler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"error"}`)))
+
+		got := entries(t, buf)
+		if len(got) != 1 || got[0]["from"] != "info" || got[0]["to"] != "error" {
+			t.Errorf("entries = %v, want the change from info to error", got)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+// Package logging sets up log/slog the same way in the gateway and the
+// services: JSON or text entries, a level that can be changed while running,
+// the standard fields service, version, request_id and user_id, and secrets
+// redacted before they are written.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Config describes the logger of a process.
+type Config struct {
+	Service string
+	Version string
+	// Level is debug, info, warn or error, as in LOG_LEVEL. Empty or
+	// invalid is info.
+	Level string
+	// Format is json or text, as in LOG_FORMAT. Empty is json.
+	Format string
+	// Output defaults to stdout.
+	Output io.Writer
+}
+
+// level is shared by every logger Setup returns, so SetLevel applies to the
+// whole process.
+var level = new(slog.LevelVar)
+
+// Setup returns the logger for cfg and makes it the default of log/slog and
+// of log, so code still calling log.Printf writes the same entries.
+func Setup(cfg Config) *slog.Logger {
+	output := cfg.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		handler = slog.NewTextHandler(output, opts)
+	} else {
+		handler = slog.NewJSONHandler(output, opts)
+	}
+
+	logger := slog.New(contextHandler{handler}).With(
+		slog.String("service", cfg.Service),
+		slog.String("version", cfg.Version),
+	)
+	slog.SetDefault(logger)
+
+	if cfg.Level != "" {
+		if err := SetLevel(cfg.Level); err != nil {
+			logger.Warn("Invalid LOG_LEVEL, using info", slog.String("level", cfg.Level))
+		}
+	}
+
+	return logger
+}
+
+// SetLevel changes the level of every logger Setup returned.
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level returns the current level, such as "info".
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userIDKey
+)
+
+// WithRequestID returns ctx carrying the request ID entries logged with it
+// get.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID in ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithUserID returns ctx carrying the user ID entries logged with it get.
+func WithUserID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserID returns the user ID in ctx, or 0 when there is none.
+func UserID(ctx context.Context) int64 {
+	id, _ := ctx.Value(userIDKey).(int64)
+	return id
+}
+
+// contextHandler adds the request and user IDs of the context to entries
+// logged with one, such as by slog.InfoContext.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if id := UserID(ctx); id != 0 {
+		r.AddAttrs(slog.Int64("user_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
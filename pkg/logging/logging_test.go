@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// setup returns a logger writing JSON lines to the returned buffer, putting
+// the default logger and level back when the test ends.
+func setup(t *testing.T) (*slog.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	previous := slog.Default()
+	previousLevel := level.Level()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		level.Set(previousLevel)
+	})
+
+	var buf bytes.Buffer
+	return Setup(Config{Service: "auth-service", Version: "test", Output: &buf}), &buf
+}
+
+// entries decodes the JSON lines in buf.
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var decoded []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q error = %v", line, err)
+		}
+		decoded = append(decoded, entry)
+	}
+	return decoded
+}
+
+func TestSetupStandardFields(t *testing.T) {
+	logger, buf := setup(t)
+
+	ctx := WithUserID(WithRequestID(context.Background(), "req-1"), 7)
+	logger.InfoContext(ctx, "Login")
+	logger.Info("Started")
+
+	got := entries(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("entries = %v, want 2", got)
+	}
+	if got[0]["service"] != "auth-service" || got[0]["version"] != "test" ||
+		got[0]["request_id"] != "req-1" || got[0]["user_id"] != float64(7) {
+		t.Errorf("entry with context = %v, want service, version, request_id and user_id", got[0])
+	}
+	if _, ok := got[1]["request_id"]; ok {
+		t.Errorf("entry without context = %v, want no request_id", got[1])
+	}
+}
+
+func TestSetupText(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	var buf bytes.Buffer
+	Setup(Config{Service: "auth-service", Format: "TEXT", Output: &buf}).Info("Started", "password", "hunter2")
+
+	if got := buf.String(); !strings.Contains(got, "service=auth-service") || !strings.Contains(got, "password="+Redacted) {
+		t.Errorf("text entry = %q, want service and a redacted password", got)
+	}
+}
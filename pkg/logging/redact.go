@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of secrets in log entries.
+const Redacted = "[REDACTED]"
+
+// secretKeys are attribute, header and field names whose values are never
+// logged, in lower case. Names ending in password, secret or _token are
+// secrets too.
+var secretKeys = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"token":               true,
+	"api_key":             true,
+	"x-api-key":           true,
+}
+
+var (
+	// credentialPattern finds credentials inside values, such as an
+	// Authorization header quoted in an error.
+	credentialPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[^\s"',]+`)
+	// queryPattern finds secrets passed as query or form parameters.
+	queryPattern = regexp.MustCompile(`(?i)\b([a-z_]*(?:password|secret|token|api_key))=[^&\s"]+`)
+)
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	return secretKeys[key] ||
+		strings.HasSuffix(key, "password") ||
+		strings.HasSuffix(key, "secret") ||
+		strings.HasSuffix(key, "_token")
+}
+
+// redact is the ReplaceAttr of every handler.
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if isSecret(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactString(a.Value.String()))
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			return slog.String(a.Key, redactString(v.Error()))
+		case http.Header, map[string]string, map[string]any:
+			return slog.Any(a.Key, redactValue(v))
+		}
+	}
+
+	return a
+}
+
+// redactValue redacts headers and maps, and those nested in maps.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case http.Header:
+		return redactHeader(v)
+	case map[string]string:
+		return redactMap(v)
+	case map[string]any:
+		return redactMap(v)
+	case string:
+		return redactString(v)
+	}
+	return value
+}
+
+func redactString(s string) string {
+	s = credentialPattern.ReplaceAllString(s, "$1 "+Redacted)
+	return queryPattern.ReplaceAllString(s, "$1="+Redacted)
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if isSecret(name) {
+			values = []string{Redacted}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+func redactMap[V any](fields map[string]V) map[string]any {
+	redacted := make(map[string]any, len(fields))
+	for name, value := range fields {
+		if isSecret(name) {
+			redacted[name] = Redacted
+		} else {
+			redacted[name] = redactValue(value)
+		}
+	}
+	return redacted
+}
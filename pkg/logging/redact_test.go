@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want any
+	}{
+		{name: "secret key", attr: slog.String("password", "hunter2"), want: Redacted},
+		{name: "secret key in any case", attr: slog.String("Authorization", "Bearer abc"), want: Redacted},
+		{name: "key ending in password", attr: slog.String("new_password", "hunter2"), want: Redacted},
+		{name: "key ending in secret", attr: slog.String("client_secret", "s3cr3t"), want: Redacted},
+		{name: "key ending in _token", attr: slog.String("refresh_token", "abc"), want: Redacted},
+		{name: "secret key of any kind", attr: slog.Int("api_key", 1234), want: Redacted},
+		{name: "ordinary key", attr: slog.String("email", "a@example.com"), want: "a@example.com"},
+		{name: "tokens is not a secret", attr: slog.Int("tokens", 3), want: float64(3)},
+		{name: "bearer credential in a value", attr: slog.String("msg", "sent Bearer abc.def to upstream"), want: "sent Bearer " + Redacted + " to upstream"},
+		{name: "basic credential in a value", attr: slog.String("detail", "header basic dXNlcjpwYXNz, rejected"), want: "header basic " + Redacted + ", rejected"},
+		{name: "query secret in a value", attr: slog.String("path", "/reset?email=a&reset_token=abc&next=/"), want: "/reset?email=a&reset_token=" + Redacted + "&next=/"},
+		{name: "credential in an error", attr: slog.Any("error", errors.New(`dial: "Bearer abc" refused`)), want: `dial: "Bearer ` + Redacted + `" refused`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := setup(t)
+			logger.Info("Entry", tt.attr)
+
+			got := entries(t, buf)[0][tt.attr.Key]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %#v, want %#v", tt.attr.Key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	logger, buf := setup(t)
+
+	header := http.Header{
+		"Authorization": {"Bearer abc"},
+		"Cookie":        {"session=abc"},
+		"X-Api-Key":     {"key"},
+		"Accept":        {"application/json"},
+	}
+	logger.Info("Request", slog.Any("header", header))
+
+	got := entries(t, buf)[0]["header"]
+	want := map[string]any{
+		"Authorization": []any{Redacted},
+		"Cookie":        []any{Redacted},
+		"X-Api-Key":     []any{Redacted},
+		"Accept":        []any{"application/json"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("header = %v, want %v", got, want)
+	}
+	if header.Get("Authorization") != "Bearer abc" {
+		t.Error("redaction changed the logged header")
+	}
+}
+
+func TestRedactMaps(t *testing.T) {
+	logger, buf := setup(t)
+
+	logger.Info("Request",
+		slog.Any("fields", map[string]string{"email": "a@example.com", "password": "hunter2"}),
+		slog.Any("body", map[string]any{
+			"user": map[string]any{"name": "a", "password": "hunter2"},
+			"headers": http.Header{
+				"Set-Cookie": {"session=abc"},
+			},
+			"note": "token=abc",
+		}),
+	)
+
+	got := entries(t, buf)[0]
+	wantFields := map[string]any{"email": "a@example.com", "password": Redacted}
+	if !reflect.DeepEqual(got["fields"], wantFields) {
+		t.Errorf("fields = %v, want %v", got["fields"], wantFields)
+	}
+	wantBody := map[string]any{
+		"user":    map[string]any{"name": "a", "password": Redacted},
+		"headers": map[string]any{"Set-Cookie": []any{Redacted}},
+		"note":    "token=" + Redacted,
+	}
+	if !reflect.DeepEqual(got["body"], wantBody) {
+		t.Errorf("body = %v, want %v", got["body"], wantBody)
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	logger, buf := setup(t)
+
+	logger.Info("Login",
+		slog.Group("request",
+			slog.String("email", "a@example.com"),
+			slog.String("password", "hunter2"),
+			slog.Group("header", slog.String("authorization", "Bearer abc")),
+		),
+	)
+	logger.WithGroup("call").With(slog.String("token", "abc")).Info("Call", slog.String("client_secret", "s3cr3t"))
+
+	got := entries(t, buf)
+	wantRequest := map[string]any{
+		"email":    "a@example.com",
+		"password": Redacted,
+		"header":   map[string]any{"authorization": Redacted},
+	}
+	if !reflect.DeepEqual(got[0]["request"], wantRequest) {
+		t.Errorf("request = %v, want %v", got[0]["request"], wantRequest)
+	}
+	wantCall := map[string]any{"token": Redacted, "client_secret": Redacted}
+	if !reflect.DeepEqual(got[1]["call"], wantCall) {
+		t.Errorf("call = %v, want %v", got[1]["call"], wantCall)
+	}
+}
@@ -2,32 +2,52 @@ package main
 
 import (
 	"database/sql"
+	"log/slog"
+	"net/http"
+	"os"
 
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/config"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/metrics"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/microlog"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/repository/postgres"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/requestmeta"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/service"
 	pb "github.com/Dzaakk/micro-commerce/services/auth-service/proto"
 
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
 	_ "github.com/lib/pq"
 	"go-micro.dev/v4"
 	"go-micro.dev/v4/logger"
 )
 
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
 
 	cfg := config.Load()
 
+	logger.DefaultLogger = microlog.New(logging.Setup(logging.Config{
+		Service: "auth-service",
+		Version: version,
+		Level:   os.Getenv("LOG_LEVEL"),
+		Format:  os.Getenv("LOG_FORMAT"),
+	}))
+	logger.DefaultHelper = logger.NewHelper(logger.DefaultLogger)
+
 	db, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
 		logger.Fatal("Failed to connect to database: ", err)
 	}
 	defer db.Close()
+	metrics.RegisterDB(db, "auth")
 
 	srv := micro.NewService(
 		micro.Name("auth-service"),
-		micro.Version("latest"),
+		micro.Version(version),
 		micro.Address(":"+cfg.Port),
+		micro.WrapHandler(requestmeta.HandlerWrapper(), metrics.HandlerWrapper(), handler.ErrorWrapper()),
 	)
 
 	srv.Init()
@@ -43,8 +63,37 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	userService := service.NewUserService(userRepo)
+	userHandler := handler.NewUserHandler(userService)
+
+	if err := pb.RegisterUserServiceHandler(srv.Server(), userHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	go serveAdmin(getEnv("ADMIN_PORT", "9081"))
+
 	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
 	if err := srv.Run(); err != nil {
 		logger.Fatal(err)
 	}
 }
+
+// serveAdmin serves the operator endpoints on their own port, which is not
+// published outside the container network.
+func serveAdmin(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/admin/log-level", logging.LevelHandler())
+	mux.Handle("/metrics", metrics.Handler())
+
+	slog.Info("Admin server starting", slog.String("addr", ":"+port))
+	if err := http.ListenAndServe(":"+port, mux); err != nil {
+		slog.Error("Admin server stopped", slog.Any("error", err))
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,40 @@
+// Package apperror defines the kinds of domain errors auth-service returns,
+// so they can be told apart with errors.Is rather than by their message.
+package apperror
+
+import "errors"
+
+// Kinds of domain errors. Errors of a kind match it with errors.Is.
+var (
+	ErrInvalid            = errors.New("invalid")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPrecondition       = errors.New("precondition failed")
+	ErrExpired            = errors.New("expired")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrLocked             = errors.New("locked")
+	ErrUnimplemented      = errors.New("unimplemented")
+)
+
+// Error is a domain error of one of the kinds above. Reason identifies the
+// error itself in UPPER_SNAKE_CASE, such as USER_NOT_FOUND, and is what
+// callers should branch on when the kind is not enough.
+type Error struct {
+	Kind    error
+	Reason  string
+	Message string
+}
+
+// New returns an error of kind.
+func New(kind error, reason, message string) *Error {
+	return &Error{Kind: kind, Reason: reason, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/service"
+
+	"go-micro.dev/v4/server"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const serviceID = "auth-service"
+
+// ErrorWrapper translates the errors handlers return into gRPC statuses. The
+// code follows the kind of a domain error and the details carry its reason
+// and, for validation errors, the invalid fields, so callers never need to
+// match messages. Other errors are logged and sent as Internal without their
+// message.
+func ErrorWrapper() server.HandlerWrapper {
+	return func(next server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			err := next(ctx, req, rsp)
+			if err == nil {
+				return nil
+			}
+
+			st := toStatus(err)
+			if st.Code() == codes.Internal {
+				slog.ErrorContext(ctx, "Call failed", slog.String("endpoint", req.Endpoint()), slog.Any("error", err))
+			}
+			return st.Err()
+		}
+	}
+}
+
+func toStatus(err error) *status.Status {
+	var validationErr *service.ValidationError
+	var domainErr *apperror.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return withDetails(status.New(codes.InvalidArgument, validationErr.Error()),
+			errorInfo("VALIDATION_FAILED"),
+			badRequest(validationErr.Fields),
+		)
+	case errors.As(err, &domainErr):
+		st := status.New(codeFromKind(domainErr.Kind), domainErr.Message)
+		if domainErr.Kind == apperror.ErrPrecondition {
+			return withDetails(st, errorInfo(domainErr.Reason), &errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        domainErr.Reason,
+					Description: domainErr.Message,
+				}},
+			})
+		}
+		return withDetails(st, errorInfo(domainErr.Reason))
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	// Errors that already are statuses, such as from calls to other
+	// services, pass through.
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	return status.New(codes.Internal, "internal error")
+}
+
+func codeFromKind(kind error) codes.Code {
+	switch kind {
+	case apperror.ErrInvalid:
+		return codes.InvalidArgument
+	case apperror.ErrNotFound:
+		return codes.NotFound
+	case apperror.ErrConflict:
+		return codes.AlreadyExists
+	case apperror.ErrPrecondition:
+		return codes.FailedPrecondition
+	case apperror.ErrExpired, apperror.ErrInvalidCredentials:
+		return codes.Unauthenticated
+	case apperror.ErrLocked:
+		return codes.PermissionDenied
+	case apperror.ErrUnimplemented:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
+	}
+}
+
+func errorInfo(reason string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: serviceID}
+}
+
+func badRequest(fields map[string]string) *errdetails.BadRequest {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	detail := &errdetails.BadRequest{}
+	for _, name := range names {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       name,
+			Description: fields[name],
+		})
+	}
+	return detail
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
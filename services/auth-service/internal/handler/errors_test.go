@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name:       "validation error",
+			err:        &service.ValidationError{Fields: map[string]string{"email": "must be a valid email address"}},
+			wantCode:   codes.InvalidArgument,
+			wantReason: "VALIDATION_FAILED",
+		},
+		{
+			name:       "invalid credentials",
+			err:        service.ErrInvalidCredentials,
+			wantCode:   codes.Unauthenticated,
+			wantReason: "INVALID_CREDENTIALS",
+		},
+		{
+			name:       "locked account",
+			err:        service.ErrAccountLocked,
+			wantCode:   codes.PermissionDenied,
+			wantReason: "ACCOUNT_LOCKED",
+		},
+		{
+			name:       "not implemented",
+			err:        service.ErrNotImplemented,
+			wantCode:   codes.Unimplemented,
+			wantReason: "NOT_IMPLEMENTED",
+		},
+		{
+			name:       "wrapped domain error",
+			err:        fmt.Errorf("get user: %w", apperror.New(apperror.ErrNotFound, "USER_NOT_FOUND", "user not found")),
+			wantCode:   codes.NotFound,
+			wantReason: "USER_NOT_FOUND",
+		},
+		{
+			name:       "conflict",
+			err:        apperror.New(apperror.ErrConflict, "EMAIL_TAKEN", "email is taken"),
+			wantCode:   codes.AlreadyExists,
+			wantReason: "EMAIL_TAKEN",
+		},
+		{
+			name:       "precondition",
+			err:        apperror.New(apperror.ErrPrecondition, "VERSION_MISMATCH", "user was modified"),
+			wantCode:   codes.FailedPrecondition,
+			wantReason: "VERSION_MISMATCH",
+		},
+		{
+			name:     "canceled",
+			err:      context.Canceled,
+			wantCode: codes.Canceled,
+		},
+		{
+			name:     "deadline",
+			err:      context.DeadlineExceeded,
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:     "status from another service",
+			err:      status.Error(codes.Unavailable, "customer service is down"),
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("connection reset"),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := toStatus(tt.err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("toStatus() code = %s, want %s", st.Code(), tt.wantCode)
+			}
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("toStatus() reason = %q, want %q", reason, tt.wantReason)
+			}
+			if tt.wantCode == codes.Internal && st.Message() != "internal error" {
+				t.Errorf("toStatus() message = %q, want the error hidden", st.Message())
+			}
+		})
+	}
+}
+
+func TestToStatusFieldViolations(t *testing.T) {
+	st := toStatus(&service.ValidationError{Fields: map[string]string{
+		"username": "is taken",
+		"email":    "must be a valid email address",
+	}})
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if req, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range req.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+	if len(fields) != 2 || fields[0] != "email" || fields[1] != "username" {
+		t.Errorf("field violations = %v, want [email username]", fields)
+	}
+}
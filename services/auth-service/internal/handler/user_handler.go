@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/auth-service/proto"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+func (h *UserHandler) GetProfile(ctx context.Context, req *pb.GetProfileRequest, rsp *pb.ProfileResponse) error {
+	profile, err := h.userService.GetProfile(ctx, strconv.FormatInt(req.UserId, 10))
+	if err != nil {
+		return err
+	}
+
+	rsp.Profile = toProtoProfile(profile)
+	return nil
+}
+
+func (h *UserHandler) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest, rsp *pb.ProfileResponse) error {
+	updates := map[string]interface{}{}
+	if req.Username != nil {
+		updates["username"] = *req.Username
+	}
+	if req.FirstName != nil {
+		updates["first_name"] = *req.FirstName
+	}
+	if req.LastName != nil {
+		updates["last_name"] = *req.LastName
+	}
+
+	profile, err := h.userService.UpdateUser(ctx, strconv.FormatInt(req.UserId, 10), updates, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
+
+	rsp.Profile = toProtoProfile(profile)
+	return nil
+}
+
+func (h *UserHandler) ChangeEmail(ctx context.Context, req *pb.ChangeEmailRequest, rsp *pb.ProfileResponse) error {
+	profile, err := h.userService.ChangeEmail(ctx, strconv.FormatInt(req.UserId, 10), &dto.ChangeEmailRequest{
+		CurrentPassword: req.CurrentPassword,
+		NewEmail:        req.NewEmail,
+	})
+	if err != nil {
+		return err
+	}
+
+	rsp.Profile = toProtoProfile(profile)
+	return nil
+}
+
+func (h *UserHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest, rsp *pb.ProfileResponse) error {
+	profile, err := h.userService.ChangePassword(ctx, strconv.FormatInt(req.UserId, 10), &dto.ChangePasswordRequest{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	rsp.Profile = toProtoProfile(profile)
+	return nil
+}
+
+func toProtoProfile(profile *dto.UserProfile) *pb.Profile {
+	return &pb.Profile{
+		Id:        profile.ID,
+		Email:     profile.Email,
+		Username:  profile.Username,
+		FirstName: profile.FirstName,
+		LastName:  profile.LastName,
+		Role:      profile.Role,
+		IsActive:  profile.IsActive,
+		CreatedAt: profile.CreatedAt,
+		UpdatedAt: profile.UpdatedAt,
+		Version:   profile.Version,
+	}
+}
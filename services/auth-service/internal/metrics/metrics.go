@@ -0,0 +1,163 @@
+// Package metrics defines the Prometheus metrics of the auth service: the
+// rate, codes and duration of the calls it handles and counts of what they
+// did, such as logins and issued tokens. They are registered with the
+// default registry, which also has the Go runtime and process metrics.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go-micro.dev/v4/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serverStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_started_total",
+		Help: "Calls started, by service and method.",
+	}, []string{"grpc_service", "grpc_method"})
+
+	serverHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Calls completed, by service, method and code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	serverHandling = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time to handle a call, by service and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auth",
+		Name:      "logins_total",
+		Help:      "Login attempts, by result.",
+	}, []string{"result"})
+
+	registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auth",
+		Name:      "registrations_total",
+		Help:      "Registration attempts, by result.",
+	}, []string{"result"})
+
+	tokensIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auth",
+		Name:      "tokens_issued_total",
+		Help:      "Tokens issued, by type.",
+	}, []string{"type"})
+
+	tokensRevoked = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "auth",
+		Name:      "tokens_revoked_total",
+		Help:      "Tokens revoked.",
+	})
+)
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDB exports the connection pool stats of db.
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// HandlerWrapper records every call and what it did. It must wrap the
+// handler.ErrorWrapper, so calls are counted with the codes callers get.
+func HandlerWrapper() server.HandlerWrapper {
+	return func(next server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			service, method := splitEndpoint(req.Endpoint())
+			serverStarted.WithLabelValues(service, method).Inc()
+
+			start := time.Now()
+			err := next(ctx, req, rsp)
+			code := status.Code(err)
+
+			serverHandled.WithLabelValues(service, method, code.String()).Inc()
+			serverHandling.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+			recordOutcome(req.Endpoint(), code)
+
+			return err
+		}
+	}
+}
+
+// recordOutcome counts the domain events of a call from its endpoint and
+// code, as the handlers answer each with one call.
+func recordOutcome(endpoint string, code codes.Code) {
+	switch endpoint {
+	case "AuthService.Login":
+		logins.WithLabelValues(loginResult(code)).Inc()
+		if code == codes.OK {
+			issued()
+		}
+	case "AuthService.Register":
+		registrations.WithLabelValues(registrationResult(code)).Inc()
+		if code == codes.OK {
+			issued()
+		}
+	case "AuthService.RefreshToken":
+		if code == codes.OK {
+			issued()
+		}
+	case "AuthService.Logout", "AuthService.RevokeRefreshToken", "OAuthService.RevokeToken":
+		if code == codes.OK {
+			tokensRevoked.Inc()
+		}
+	}
+}
+
+// issued counts the access and refresh token pair of an auth response.
+func issued() {
+	tokensIssued.WithLabelValues("access").Inc()
+	tokensIssued.WithLabelValues("refresh").Inc()
+}
+
+func loginResult(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return "success"
+	case codes.InvalidArgument:
+		return "invalid"
+	case codes.Unauthenticated:
+		return "invalid_credentials"
+	case codes.PermissionDenied:
+		return "locked"
+	default:
+		return "error"
+	}
+}
+
+func registrationResult(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return "success"
+	case codes.InvalidArgument:
+		return "invalid"
+	case codes.AlreadyExists:
+		return "conflict"
+	default:
+		return "error"
+	}
+}
+
+// splitEndpoint splits an endpoint such as "UserService.GetProfile" into
+// its service and method.
+func splitEndpoint(endpoint string) (service, method string) {
+	service, method, ok := strings.Cut(endpoint, ".")
+	if !ok {
+		return "unknown", endpoint
+	}
+	return service, method
+}
@@ -0,0 +1,91 @@
+// Package microlog makes go-micro log through log/slog, so its entries get
+// the same format, level and standard fields as the rest of the service.
+package microlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go-micro.dev/v4/logger"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+	opts   logger.Options
+}
+
+// New returns a go-micro logger writing to l, to be set as
+// logger.DefaultLogger.
+func New(l *slog.Logger) logger.Logger {
+	return &slogLogger{logger: l}
+}
+
+func (l *slogLogger) Init(opts ...logger.Option) error {
+	for _, o := range opts {
+		o(&l.opts)
+	}
+	if len(l.opts.Fields) > 0 {
+		l.logger = l.logger.With(attrs(l.opts.Fields)...)
+	}
+	return nil
+}
+
+// Options reports the level slog currently logs at, which go-micro's helpers
+// check before formatting an entry.
+func (l *slogLogger) Options() logger.Options {
+	opts := l.opts
+	opts.Level = fromSlog(l.logger)
+	return opts
+}
+
+func (l *slogLogger) Fields(fields map[string]interface{}) logger.Logger {
+	return &slogLogger{logger: l.logger.With(attrs(fields)...), opts: l.opts}
+}
+
+func (l *slogLogger) Log(level logger.Level, v ...interface{}) {
+	l.logger.Log(context.Background(), toSlog(level), fmt.Sprint(v...))
+}
+
+func (l *slogLogger) Logf(level logger.Level, format string, v ...interface{}) {
+	l.logger.Log(context.Background(), toSlog(level), fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) String() string {
+	return "slog"
+}
+
+func attrs(fields map[string]interface{}) []any {
+	args := make([]any, 0, len(fields))
+	for key, value := range fields {
+		args = append(args, slog.Any(key, value))
+	}
+	return args
+}
+
+func toSlog(level logger.Level) slog.Level {
+	switch {
+	case level <= logger.DebugLevel:
+		return slog.LevelDebug
+	case level == logger.InfoLevel:
+		return slog.LevelInfo
+	case level == logger.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+func fromSlog(l *slog.Logger) logger.Level {
+	ctx := context.Background()
+	switch {
+	case l.Enabled(ctx, slog.LevelDebug):
+		return logger.DebugLevel
+	case l.Enabled(ctx, slog.LevelInfo):
+		return logger.InfoLevel
+	case l.Enabled(ctx, slog.LevelWarn):
+		return logger.WarnLevel
+	default:
+		return logger.ErrorLevel
+	}
+}
@@ -19,6 +19,8 @@ type User struct {
 	Email        string    `db:"email"`
 	Username     string    `db:"username"`
 	PasswordHash string    `db:"password_hash"`
+	FirstName    string    `db:"first_name"`
+	LastName     string    `db:"last_name"`
 	Role         UserRole  `db:"role"`
 	IsActive     bool      `db:"is_active"`
 	CreatedAt    time.Time `db:"created_at"`
@@ -3,9 +3,19 @@ package repository
 import (
 	"context"
 
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
 )
 
+var (
+	ErrClientNotFound           = apperror.New(apperror.ErrNotFound, "CLIENT_NOT_FOUND", "client not found")
+	ErrInvalidClientCredentials = apperror.New(apperror.ErrInvalidCredentials, "INVALID_CLIENT_CREDENTIALS", "invalid client credentials")
+	// Expired and unknown codes and tokens look the same to the queries.
+	ErrAuthorizationCodeExpired = apperror.New(apperror.ErrExpired, "AUTHORIZATION_CODE_EXPIRED", "authorization code not found or expired")
+	ErrAccessTokenExpired       = apperror.New(apperror.ErrExpired, "ACCESS_TOKEN_EXPIRED", "access token not found or expired")
+	ErrRefreshTokenExpired      = apperror.New(apperror.ErrExpired, "REFRESH_TOKEN_EXPIRED", "refresh token not found or expired")
+)
+
 type AuthRepository interface {
 	// OAuth Clients
 	GetClientByID(ctx context.Context, clientID string) (*model.OAuthClient, error)
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
 	"github.com/lib/pq"
@@ -38,7 +37,7 @@ func (r *authRepository) GetClientByID(ctx context.Context, clientID string) (*m
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("client not found")
+		return nil, ErrClientNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -54,7 +53,7 @@ func (r *authRepository) ValidateClientCredentials(ctx context.Context, clientID
 	}
 
 	if client.ClientSecret != clientSecret {
-		return nil, fmt.Errorf("invalid client credentials")
+		return nil, ErrInvalidClientCredentials
 	}
 
 	return client, nil
@@ -98,7 +97,7 @@ func (r *authRepository) GetAuthorizationCode(code string) (*model.Authorization
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("authorization code not found or expired")
+		return nil, ErrAuthorizationCodeExpired
 	}
 	if err != nil {
 		return nil, err
@@ -151,7 +150,7 @@ func (r *authRepository) GetAccessToken(token string) (*model.AccessToken, error
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("access token not found or expired")
+		return nil, ErrAccessTokenExpired
 	}
 	if err != nil {
 		return nil, err
@@ -210,7 +209,7 @@ func (r *authRepository) GetRefreshToken(token string) (*model.RefreshToken, err
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("refresh token not found or expired")
+		return nil, ErrRefreshTokenExpired
 	}
 	if err != nil {
 		return nil, err
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/repository"
+	"github.com/lib/pq"
+)
+
+const userColumns = `
+	id, email, username, password_hash, COALESCE(first_name, ''), COALESCE(last_name, ''),
+	role, is_active, created_at, updated_at
+`
+
+// updatableUserColumns guards UpdateFields against building SQL from
+// arbitrary map keys.
+var updatableUserColumns = map[string]bool{
+	"email":         true,
+	"username":      true,
+	"password_hash": true,
+	"first_name":    true,
+	"last_name":     true,
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func NewUserRepository(db *sql.DB) repository.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	query := `
+		INSERT INTO users (email, username, password_hash, first_name, last_name, role, is_active)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		user.Email,
+		user.Username,
+		user.PasswordHash,
+		user.FirstName,
+		user.LastName,
+		user.Role,
+		user.IsActive,
+	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if isUniqueViolation(err) {
+		return repository.ErrDuplicateUser
+	}
+	return err
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE LOWER(email) = LOWER($1)`
+	return r.getOne(ctx, query, email)
+}
+
+func (r *userRepository) UpdateFields(ctx context.Context, id string, fields map[string]interface{}, expectedUpdatedAt time.Time) (*model.User, error) {
+	return r.update(ctx, r.db, id, fields, expectedUpdatedAt)
+}
+
+func (r *userRepository) UpdateCredentials(ctx context.Context, id string, fields map[string]interface{}) (*model.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	user, err := r.update(ctx, tx, id, fields, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM authorization_codes WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM access_tokens WHERE user_id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) update(ctx context.Context, q queryRower, id string, fields map[string]interface{}, expectedUpdatedAt time.Time) (*model.User, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		if !updatableUserColumns[column] {
+			return nil, fmt.Errorf("column %q cannot be updated", column)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	args := []interface{}{id}
+	sets := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		args = append(args, fields[column])
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	sets = append(sets, "updated_at = NOW()")
+
+	query := `UPDATE users SET ` + strings.Join(sets, ", ") + ` WHERE id = $1`
+	if !expectedUpdatedAt.IsZero() {
+		args = append(args, expectedUpdatedAt)
+		query += fmt.Sprintf(" AND updated_at = $%d", len(args))
+	}
+	query += ` RETURNING ` + userColumns
+
+	user, err := scanUser(q.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		// Tell a stale version apart from a missing user.
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, repository.ErrVersionConflict
+	}
+	if isUniqueViolation(err) {
+		return nil, repository.ErrDuplicateUser
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) getOne(ctx context.Context, query string, args ...interface{}) (*model.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func scanUser(row *sql.Row) (*model.User, error) {
+	var user model.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Username,
+		&user.PasswordHash,
+		&user.FirstName,
+		&user.LastName,
+		&user.Role,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
+)
+
+// openTestDB migrates a fresh schema in the database named by
+// AUTH_TEST_DATABASE_URL, and skips the test when it is not set.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("AUTH_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("AUTH_TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../../migrations/*.sql")
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("apply %s: %v", path, err)
+		}
+	}
+
+	return db
+}
+
+func TestUpdateCredentialsRevokesSessions(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	users := make([]*model.User, 2)
+	for i := range users {
+		users[i] = &model.User{
+			Email:        fmt.Sprintf("user%d@example.com", i),
+			Username:     fmt.Sprintf("user%d", i),
+			PasswordHash: "hash",
+			Role:         model.RoleCustomer,
+			IsActive:     true,
+		}
+		if err := repo.Create(ctx, users[i]); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if _, err := db.Exec(`INSERT INTO oauth_clients (client_id, client_secret, name) VALUES ('web', 'secret', 'Web App')`); err != nil {
+		t.Fatalf("insert client: %v", err)
+	}
+	for i, user := range users {
+		var accessTokenID string
+		err := db.QueryRow(`
+			INSERT INTO access_tokens (token, client_id, user_id, expires_at)
+			VALUES ($1, 'web', $2, NOW() + INTERVAL '1 hour') RETURNING id
+		`, fmt.Sprintf("access-%d", i), user.ID).Scan(&accessTokenID)
+		if err != nil {
+			t.Fatalf("insert access token: %v", err)
+		}
+		if _, err := db.Exec(`
+			INSERT INTO refresh_tokens (token, client_id, user_id, access_token_id, expires_at)
+			VALUES ($1, 'web', $2, $3, NOW() + INTERVAL '7 days')
+		`, fmt.Sprintf("refresh-%d", i), user.ID, accessTokenID); err != nil {
+			t.Fatalf("insert refresh token: %v", err)
+		}
+		if _, err := db.Exec(`
+			INSERT INTO authorization_codes (code, client_id, user_id, expires_at)
+			VALUES ($1, 'web', $2, NOW() + INTERVAL '10 minutes')
+		`, fmt.Sprintf("code-%d", i), user.ID); err != nil {
+			t.Fatalf("insert authorization code: %v", err)
+		}
+	}
+
+	updated, err := repo.UpdateCredentials(ctx, users[0].ID, map[string]interface{}{"password_hash": "new-hash"})
+	if err != nil {
+		t.Fatalf("UpdateCredentials() error = %v", err)
+	}
+	if updated.PasswordHash != "new-hash" {
+		t.Errorf("password hash = %q, want new-hash", updated.PasswordHash)
+	}
+
+	for _, table := range []string{"access_tokens", "refresh_tokens", "authorization_codes"} {
+		for i, want := range []int{0, 1} {
+			var count int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE user_id = $1`, users[i].ID).Scan(&count); err != nil {
+				t.Fatalf("count %s: %v", table, err)
+			}
+			if count != want {
+				t.Errorf("%s of user %d = %d, want %d", table, i, count, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
+)
+
+var (
+	ErrUserNotFound    = apperror.New(apperror.ErrNotFound, "USER_NOT_FOUND", "user not found")
+	ErrVersionConflict = apperror.New(apperror.ErrPrecondition, "VERSION_MISMATCH", "user was modified by another request")
+	ErrDuplicateUser   = apperror.New(apperror.ErrConflict, "USER_ALREADY_EXISTS", "username or email already in use")
+)
+
+type UserRepository interface {
+	// Create inserts user and sets its ID and timestamps. It returns
+	// ErrDuplicateUser when the email or username is taken.
+	Create(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+
+	// UpdateFields sets the given columns and bumps updated_at. When
+	// expectedUpdatedAt is non-zero the update only applies if the row still
+	// carries that timestamp, otherwise ErrVersionConflict is returned.
+	UpdateFields(ctx context.Context, id string, fields map[string]interface{}, expectedUpdatedAt time.Time) (*model.User, error)
+
+	// UpdateCredentials sets the email or password hash like UpdateFields and,
+	// in the same transaction, deletes every authorization code, access token
+	// and refresh token of the user, so no session outlives the change.
+	UpdateCredentials(ctx context.Context, id string, fields map[string]interface{}) (*model.User, error)
+}
@@ -0,0 +1,109 @@
+// Package requestmeta reads what the gateway says about a request in its
+// metadata: the request ID, the authenticated user and the trace headers.
+package requestmeta
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
+	"go-micro.dev/v4/metadata"
+	"go-micro.dev/v4/server"
+	"google.golang.org/grpc/status"
+)
+
+// traceHeaders are the W3C trace context headers the gateway passes on.
+var traceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+// Meta is what the gateway said about a request. Fields are empty for calls
+// that did not come through the gateway or had no authenticated user.
+type Meta struct {
+	RequestID string
+	UserID    int64
+	UserRole  string
+	// Trace holds the trace headers that came with the request, by their
+	// lower case name.
+	Trace map[string]string
+}
+
+type contextKey struct{}
+
+// FromContext returns the Meta HandlerWrapper put in ctx.
+func FromContext(ctx context.Context) Meta {
+	meta, _ := ctx.Value(contextKey{}).(Meta)
+	return meta
+}
+
+// HandlerWrapper reads the metadata of every call into a Meta in its
+// context. The metadata itself stays in the context too, so calls made to
+// other services with it pass it on. Calls that did not come with a request
+// ID get a new one.
+//
+// The request and user IDs are also put in the context for logging, so
+// entries logged with it carry them, and every call is logged with its
+// outcome once it returns.
+func HandlerWrapper() server.HandlerWrapper {
+	return func(next server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			meta := read(ctx)
+			if meta.RequestID == "" {
+				meta.RequestID = newRequestID()
+				ctx = metadata.Set(ctx, "X-Request-Id", meta.RequestID)
+			}
+
+			ctx = context.WithValue(ctx, contextKey{}, meta)
+			ctx = logging.WithRequestID(ctx, meta.RequestID)
+			if meta.UserID != 0 {
+				ctx = logging.WithUserID(ctx, meta.UserID)
+			}
+
+			start := time.Now()
+			err := next(ctx, req, rsp)
+
+			level := slog.LevelInfo
+			if err != nil {
+				level = slog.LevelWarn
+			}
+			slog.LogAttrs(ctx, level, "Call",
+				slog.String("endpoint", req.Endpoint()),
+				slog.String("code", status.Code(err).String()),
+				slog.Duration("latency", time.Since(start)),
+			)
+
+			return err
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func read(ctx context.Context) Meta {
+	var meta Meta
+
+	meta.RequestID, _ = metadata.Get(ctx, "X-Request-Id")
+	if userID, ok := metadata.Get(ctx, "X-User-Id"); ok {
+		if id, err := strconv.ParseInt(userID, 10, 64); err == nil {
+			meta.UserID = id
+			meta.UserRole, _ = metadata.Get(ctx, "X-User-Role")
+		}
+	}
+
+	for _, name := range traceHeaders {
+		if value, ok := metadata.Get(ctx, name); ok {
+			if meta.Trace == nil {
+				meta.Trace = map[string]string{}
+			}
+			meta.Trace[name] = value
+		}
+	}
+
+	return meta
+}
@@ -0,0 +1,138 @@
+package requestmeta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/pkg/logging"
+	"go-micro.dev/v4/metadata"
+	"go-micro.dev/v4/server"
+)
+
+// fakeRequest stands in for a call; only Endpoint is used.
+type fakeRequest struct {
+	server.Request
+}
+
+func (fakeRequest) Endpoint() string {
+	return "AuthService.ValidateToken"
+}
+
+// serve runs HandlerWrapper over a call with md, returning the context the
+// handler got.
+func serve(t *testing.T, md metadata.Metadata) context.Context {
+	t.Helper()
+
+	var got context.Context
+	handler := HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		got = ctx
+		return nil
+	})
+	if err := handler(metadata.NewContext(context.Background(), md), fakeRequest{}, nil); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	return got
+}
+
+func TestHandlerWrapperReadsMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.Metadata
+		want Meta
+	}{
+		{
+			name: "authenticated",
+			md: metadata.Metadata{
+				"X-Request-Id": "req-1",
+				"X-User-Id":    "7",
+				"X-User-Role":  "seller",
+				"Traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"Baggage":      "tenant=1",
+			},
+			want: Meta{
+				RequestID: "req-1",
+				UserID:    7,
+				UserRole:  "seller",
+				Trace: map[string]string{
+					"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+					"baggage":     "tenant=1",
+				},
+			},
+		},
+		{
+			name: "anonymous",
+			md:   metadata.Metadata{"X-Request-Id": "req-1", "X-User-Role": "admin"},
+			want: Meta{RequestID: "req-1"},
+		},
+		{
+			name: "malformed user id",
+			md:   metadata.Metadata{"X-Request-Id": "req-1", "X-User-Id": "seven", "X-User-Role": "admin"},
+			want: Meta{RequestID: "req-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromContext(serve(t, tt.md))
+
+			if got.RequestID != tt.want.RequestID || got.UserID != tt.want.UserID || got.UserRole != tt.want.UserRole {
+				t.Errorf("FromContext() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.Trace) != len(tt.want.Trace) {
+				t.Errorf("FromContext() trace = %v, want %v", got.Trace, tt.want.Trace)
+			}
+			for name, value := range tt.want.Trace {
+				if got.Trace[name] != value {
+					t.Errorf("FromContext() trace %s = %q, want %q", name, got.Trace[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerWrapperKeepsMetadata(t *testing.T) {
+	ctx := serve(t, metadata.Metadata{"X-Request-Id": "req-1", "X-User-Id": "7"})
+
+	// Calls the handler makes to other services pass the metadata on.
+	if id, _ := metadata.Get(ctx, "X-Request-Id"); id != "req-1" {
+		t.Errorf("metadata X-Request-Id = %q, want %q", id, "req-1")
+	}
+	if id, _ := metadata.Get(ctx, "X-User-Id"); id != "7" {
+		t.Errorf("metadata X-User-Id = %q, want %q", id, "7")
+	}
+}
+
+func TestHandlerWrapperRequestID(t *testing.T) {
+	t.Run("from the gateway", func(t *testing.T) {
+		ctx := serve(t, metadata.Metadata{"X-Request-Id": "req-1", "X-User-Id": "7"})
+
+		if id := logging.RequestID(ctx); id != "req-1" {
+			t.Errorf("logging.RequestID() = %q, want %q", id, "req-1")
+		}
+		if id := logging.UserID(ctx); id != 7 {
+			t.Errorf("logging.UserID() = %d, want 7", id)
+		}
+	})
+
+	t.Run("generated when missing", func(t *testing.T) {
+		ctx := serve(t, metadata.Metadata{})
+
+		id := FromContext(ctx).RequestID
+		if len(id) != 32 {
+			t.Fatalf("FromContext() request ID = %q, want 32 hex digits", id)
+		}
+		if got := logging.RequestID(ctx); got != id {
+			t.Errorf("logging.RequestID() = %q, want %q", got, id)
+		}
+		if got, _ := metadata.Get(ctx, "X-Request-Id"); got != id {
+			t.Errorf("metadata X-Request-Id = %q, want %q passed on", got, id)
+		}
+		if logging.UserID(ctx) != 0 {
+			t.Errorf("logging.UserID() = %d, want 0", logging.UserID(ctx))
+		}
+
+		if other := FromContext(serve(t, metadata.Metadata{})).RequestID; other == id {
+			t.Errorf("two calls got request ID %q", id)
+		}
+	})
+}
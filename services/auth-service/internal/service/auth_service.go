@@ -47,7 +47,14 @@ type UserService interface {
 	CreateUser(ctx context.Context, req *dto.RegisterRequest) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (*dto.BasicUser, error)
 	GetUserByID(ctx context.Context, id string) (*dto.BasicUser, error)
-	UpdateUser(ctx context.Context, userID string, updates map[string]interface{}) error
+	GetProfile(ctx context.Context, userID string) (*dto.UserProfile, error)
+	// UpdateUser applies a partial profile update. A non-zero expectedVersion
+	// must match the profile's current version.
+	UpdateUser(ctx context.Context, userID string, updates map[string]interface{}, expectedVersion int64) (*dto.UserProfile, error)
+	// ChangeEmail and ChangePassword sign the user out everywhere: a session
+	// opened with the old credentials may be the reason they are changed.
+	ChangeEmail(ctx context.Context, userID string, req *dto.ChangeEmailRequest) (*dto.UserProfile, error)
+	ChangePassword(ctx context.Context, userID string, req *dto.ChangePasswordRequest) (*dto.UserProfile, error)
 	ValidatePassword(ctx context.Context, email, password string) (string, error)
 }
 
@@ -6,11 +6,16 @@ import (
 
 	// "github.com/Dzaakk/micro-commerce/proto/customer"
 
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/dto"
 	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrNotImplemented is returned by the parts of the service that do not
+// exist yet, so callers get an answer rather than a crashed handler.
+var ErrNotImplemented = apperror.New(apperror.ErrUnimplemented, "NOT_IMPLEMENTED", "not implemented")
+
 type authServiceImpl struct {
 	// customerClient customer.CustomerServiceClient
 	authRepo      repository.AuthRepository
@@ -32,35 +37,35 @@ func NewAuthService(authRepo repository.AuthRepository, tokenService TokenServic
 
 // GetUserByID implements AuthService.
 func (a *authServiceImpl) GetUserByID(ctx context.Context, userID string) (*dto.BasicUser, error) {
-	panic("unimplemented")
+	return nil, ErrNotImplemented
 }
 
 // Login implements AuthService.
 func (a *authServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
-	panic("unimplemented")
+	return nil, ErrNotImplemented
 }
 
 // Logout implements AuthService.
 func (a *authServiceImpl) Logout(ctx context.Context, accessToken string) error {
-	panic("unimplemented")
+	return ErrNotImplemented
 }
 
 // RefreshToken implements AuthService.
 func (a *authServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
-	panic("unimplemented")
+	return nil, ErrNotImplemented
 }
 
 // Register implements AuthService.
 func (a *authServiceImpl) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
-	panic("unimplemented")
+	return nil, ErrNotImplemented
 }
 
 // RevokeRefreshToken implements AuthService.
 func (a *authServiceImpl) RevokeRefreshToken(ctx context.Context, token string) error {
-	panic("unimplemented")
+	return ErrNotImplemented
 }
 
 // ValidateToken implements AuthService.
 func (a *authServiceImpl) ValidateToken(ctx context.Context, token string) (*jwt.MapClaims, error) {
-	panic("unimplemented")
+	return nil, ErrNotImplemented
 }
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = apperror.New(apperror.ErrInvalidCredentials, "INVALID_CREDENTIALS", "invalid credentials")
+	// ErrAccountLocked is only returned once the password checked out, so
+	// it does not tell whether an account exists.
+	ErrAccountLocked = apperror.New(apperror.ErrLocked, "ACCOUNT_LOCKED", "account is locked")
+
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,20}$`)
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignores everything past 72 bytes
+	maxPasswordLength = 72
+	maxNameLength     = 100
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Is makes validation errors match apperror.ErrInvalid.
+func (e *ValidationError) Is(target error) bool {
+	return target == apperror.ErrInvalid
+}
+
+type userServiceImpl struct {
+	userRepo repository.UserRepository
+}
+
+func NewUserService(userRepo repository.UserRepository) UserService {
+	return &userServiceImpl{
+		userRepo: userRepo,
+	}
+}
+
+// CreateUser implements UserService.
+func (s *userServiceImpl) CreateUser(ctx context.Context, req *dto.RegisterRequest) (string, error) {
+	user := &model.User{
+		Email:    strings.ToLower(strings.TrimSpace(req.Email)),
+		Username: strings.TrimSpace(req.Username),
+		Role:     model.UserRole(req.Role),
+		IsActive: true,
+	}
+
+	invalid := map[string]string{}
+	if addr, err := mail.ParseAddress(user.Email); err != nil || addr.Address != user.Email {
+		invalid["email"] = "must be a valid email address"
+	}
+	if !usernamePattern.MatchString(user.Username) {
+		invalid["username"] = "must be 3-20 letters, digits, '.', '_' or '-'"
+	}
+	if len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength {
+		invalid["password"] = fmt.Sprintf("must be between %d and %d characters", minPasswordLength, maxPasswordLength)
+	}
+	// Admins are not created through registration.
+	if user.Role != model.RoleCustomer && user.Role != model.RoleSeller {
+		invalid["role"] = "must be customer or seller"
+	}
+	if len(invalid) > 0 {
+		return "", &ValidationError{Fields: invalid}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	user.PasswordHash = string(hash)
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+// GetUserByEmail implements UserService.
+func (s *userServiceImpl) GetUserByEmail(ctx context.Context, email string) (*dto.BasicUser, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBasicUser(user), nil
+}
+
+// GetUserByID implements UserService.
+func (s *userServiceImpl) GetUserByID(ctx context.Context, id string) (*dto.BasicUser, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBasicUser(user), nil
+}
+
+// GetProfile implements UserService.
+func (s *userServiceImpl) GetProfile(ctx context.Context, userID string) (*dto.UserProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserProfile(user), nil
+}
+
+// UpdateUser implements UserService.
+func (s *userServiceImpl) UpdateUser(ctx context.Context, userID string, updates map[string]interface{}, expectedVersion int64) (*dto.UserProfile, error) {
+	fields := make(map[string]interface{}, len(updates))
+	invalid := map[string]string{}
+
+	for name, raw := range updates {
+		value, ok := raw.(string)
+		if !ok {
+			invalid[name] = "must be a string"
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "username":
+			if !usernamePattern.MatchString(value) {
+				invalid[name] = "must be 3-20 letters, digits, '.', '_' or '-'"
+			}
+		case "first_name", "last_name":
+			if len(value) > maxNameLength {
+				invalid[name] = fmt.Sprintf("must be at most %d characters", maxNameLength)
+			}
+		default:
+			invalid[name] = "cannot be updated"
+		}
+
+		fields[name] = value
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+	if len(fields) == 0 {
+		return s.GetProfile(ctx, userID)
+	}
+
+	var expectedUpdatedAt time.Time
+	if expectedVersion != 0 {
+		expectedUpdatedAt = time.UnixMicro(expectedVersion).UTC()
+	}
+
+	user, err := s.userRepo.UpdateFields(ctx, userID, fields, expectedUpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserProfile(user), nil
+}
+
+// ChangeEmail implements UserService.
+func (s *userServiceImpl) ChangeEmail(ctx context.Context, userID string, req *dto.ChangeEmailRequest) (*dto.UserProfile, error) {
+	user, err := s.verifyCurrentPassword(ctx, userID, req.CurrentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(req.NewEmail))
+	if addr, err := mail.ParseAddress(newEmail); err != nil || addr.Address != newEmail {
+		return nil, &ValidationError{Fields: map[string]string{"new_email": "must be a valid email address"}}
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, &ValidationError{Fields: map[string]string{"new_email": "must differ from the current email"}}
+	}
+
+	updated, err := s.userRepo.UpdateCredentials(ctx, userID, map[string]interface{}{"email": newEmail})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserProfile(updated), nil
+}
+
+// ChangePassword implements UserService.
+func (s *userServiceImpl) ChangePassword(ctx context.Context, userID string, req *dto.ChangePasswordRequest) (*dto.UserProfile, error) {
+	user, err := s.verifyCurrentPassword(ctx, userID, req.CurrentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.NewPassword) < minPasswordLength || len(req.NewPassword) > maxPasswordLength {
+		return nil, &ValidationError{Fields: map[string]string{
+			"new_password": fmt.Sprintf("must be between %d and %d characters", minPasswordLength, maxPasswordLength),
+		}}
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.NewPassword)) == nil {
+		return nil, &ValidationError{Fields: map[string]string{"new_password": "must differ from the current password"}}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.userRepo.UpdateCredentials(ctx, userID, map[string]interface{}{"password_hash": string(hash)})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserProfile(updated), nil
+}
+
+// ValidatePassword implements UserService.
+func (s *userServiceImpl) ValidatePassword(ctx context.Context, email, password string) (string, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return "", ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return "", ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return "", ErrAccountLocked
+	}
+
+	return user.ID, nil
+}
+
+func (s *userServiceImpl) verifyCurrentPassword(ctx context.Context, userID, password string) (*model.User, error) {
+	if password == "" {
+		return nil, &ValidationError{Fields: map[string]string{"current_password": "is required"}}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A wrong current password is a field error, not a failed login: the
+	// caller's token is fine, and 401 would make clients log the user out.
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, &ValidationError{Fields: map[string]string{"current_password": "is incorrect"}}
+	}
+	if !user.IsActive {
+		return nil, ErrAccountLocked
+	}
+
+	return user, nil
+}
+
+func toBasicUser(user *model.User) *dto.BasicUser {
+	id, _ := strconv.ParseInt(user.ID, 10, 64)
+
+	return &dto.BasicUser{
+		ID:       id,
+		Email:    user.Email,
+		Username: user.Username,
+		Role:     string(user.Role),
+	}
+}
+
+func toUserProfile(user *model.User) *dto.UserProfile {
+	id, _ := strconv.ParseInt(user.ID, 10, 64)
+
+	return &dto.UserProfile{
+		ID:        id,
+		Email:     user.Email,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      string(user.Role),
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		Version:   user.UpdatedAt.UnixMicro(),
+	}
+}
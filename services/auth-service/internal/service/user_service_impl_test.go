@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/apperror"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/auth-service/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeUserRepo struct {
+	users map[string]*model.User
+	// revoked counts the times each user's sessions were revoked.
+	revoked map[string]int
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *model.User) error {
+	for _, existing := range r.users {
+		if existing.Email == user.Email || existing.Username == user.Username {
+			return repository.ErrDuplicateUser
+		}
+	}
+	user.ID = "new"
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id string) (*model.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) UpdateFields(ctx context.Context, id string, fields map[string]interface{}, expectedUpdatedAt time.Time) (*model.User, error) {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if email, ok := fields["email"].(string); ok {
+		user.Email = email
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) UpdateCredentials(ctx context.Context, id string, fields map[string]interface{}) (*model.User, error) {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if email, ok := fields["email"].(string); ok {
+		user.Email = email
+	}
+	if hash, ok := fields["password_hash"].(string); ok {
+		user.PasswordHash = hash
+	}
+	r.revoked[id]++
+	return user, nil
+}
+
+func newTestUserService(t *testing.T, active bool) UserService {
+	t.Helper()
+
+	svc, _ := newTestUserServiceRepo(t, active)
+	return svc
+}
+
+func newTestUserServiceRepo(t *testing.T, active bool) (UserService, *fakeUserRepo) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeUserRepo{
+		users: map[string]*model.User{
+			"1": {ID: "1", Email: "jane@example.com", Username: "jane", PasswordHash: string(hash), IsActive: active},
+		},
+		revoked: map[string]int{},
+	}
+	return NewUserService(repo), repo
+}
+
+func TestChangeEmailCurrentPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   bool
+		password string
+		wantKind error
+		wantCode string
+	}{
+		{name: "missing", active: true, password: "", wantKind: apperror.ErrInvalid},
+		{name: "wrong", active: true, password: "wrong-password", wantKind: apperror.ErrInvalid},
+		{name: "locked", active: false, password: "correct-password", wantKind: apperror.ErrLocked},
+		{name: "correct", active: true, password: "correct-password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestUserService(t, tt.active)
+
+			_, err := svc.ChangeEmail(context.Background(), "1", &dto.ChangeEmailRequest{
+				CurrentPassword: tt.password,
+				NewEmail:        "jane.doe@example.com",
+			})
+			if tt.wantKind == nil {
+				if err != nil {
+					t.Fatalf("ChangeEmail() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantKind) {
+				t.Fatalf("ChangeEmail() error = %v, want kind %v", err, tt.wantKind)
+			}
+			// A wrong password must never look like a failed login.
+			if errors.Is(err, apperror.ErrInvalidCredentials) {
+				t.Fatalf("ChangeEmail() error = %v, is invalid credentials", err)
+			}
+		})
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       dto.RegisterRequest
+		wantField string
+		wantKind  error
+	}{
+		{
+			name: "valid",
+			req:  dto.RegisterRequest{Email: "New@Example.com", Username: "newbie", Password: "long-enough", Role: "customer"},
+		},
+		{
+			name:      "invalid email",
+			req:       dto.RegisterRequest{Email: "nope", Username: "newbie", Password: "long-enough", Role: "customer"},
+			wantField: "email",
+		},
+		{
+			name:      "short password",
+			req:       dto.RegisterRequest{Email: "new@example.com", Username: "newbie", Password: "short", Role: "seller"},
+			wantField: "password",
+		},
+		{
+			name:      "admin role",
+			req:       dto.RegisterRequest{Email: "new@example.com", Username: "newbie", Password: "long-enough", Role: "admin"},
+			wantField: "role",
+		},
+		{
+			name:     "duplicate email",
+			req:      dto.RegisterRequest{Email: "jane@example.com", Username: "other", Password: "long-enough", Role: "customer"},
+			wantKind: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestUserService(t, true)
+
+			id, err := svc.CreateUser(context.Background(), &tt.req)
+			switch {
+			case tt.wantField != "":
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Fields[tt.wantField] == "" {
+					t.Fatalf("CreateUser() error = %v, want field %q", err, tt.wantField)
+				}
+			case tt.wantKind != nil:
+				if !errors.Is(err, tt.wantKind) {
+					t.Fatalf("CreateUser() error = %v, want kind %v", err, tt.wantKind)
+				}
+			default:
+				if err != nil || id == "" {
+					t.Fatalf("CreateUser() = %q, %v", id, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCredentialChangesRevokeSessions(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(svc UserService) error
+		wantRevoked bool
+	}{
+		{
+			name: "change password",
+			change: func(svc UserService) error {
+				_, err := svc.ChangePassword(context.Background(), "1", &dto.ChangePasswordRequest{
+					CurrentPassword: "correct-password",
+					NewPassword:     "another-password",
+				})
+				return err
+			},
+			wantRevoked: true,
+		},
+		{
+			name: "change email",
+			change: func(svc UserService) error {
+				_, err := svc.ChangeEmail(context.Background(), "1", &dto.ChangeEmailRequest{
+					CurrentPassword: "correct-password",
+					NewEmail:        "jane.doe@example.com",
+				})
+				return err
+			},
+			wantRevoked: true,
+		},
+		{
+			name: "rejected password change",
+			change: func(svc UserService) error {
+				_, err := svc.ChangePassword(context.Background(), "1", &dto.ChangePasswordRequest{
+					CurrentPassword: "wrong-password",
+					NewPassword:     "another-password",
+				})
+				return err
+			},
+		},
+		{
+			name: "profile update",
+			change: func(svc UserService) error {
+				_, err := svc.UpdateUser(context.Background(), "1", map[string]interface{}{"first_name": "Jane"}, 0)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestUserServiceRepo(t, true)
+
+			err := tt.change(svc)
+			if tt.wantRevoked && err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+			if got := repo.revoked["1"] > 0; got != tt.wantRevoked {
+				t.Errorf("sessions revoked = %v, want %v", got, tt.wantRevoked)
+			}
+		})
+	}
+
+	svc, repo := newTestUserServiceRepo(t, true)
+	if _, err := svc.ChangePassword(context.Background(), "1", &dto.ChangePasswordRequest{
+		CurrentPassword: "correct-password",
+		NewPassword:     "another-password",
+	}); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(repo.users["1"].PasswordHash), []byte("another-password")) != nil {
+		t.Error("ChangePassword() did not store the new password")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/cart-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("cart-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	cartRepo := repository.NewCartRepository(db)
+
+	catalog := client.NewProductCatalog(cfg.ProductServiceName, srv.Client())
+	stock := client.NewStockChecker(cfg.InventoryServiceName, srv.Client())
+	promotions := client.NewPromotions(cfg.PromotionsServiceName, srv.Client())
+
+	cartService := service.NewCartService(
+		cartRepo,
+		catalog,
+		stock,
+		promotions,
+		cfg.GuestCartTTL,
+		cfg.UserCartTTL,
+		cfg.ExpirySweepInterval,
+	)
+
+	cartHandler := handler.NewCartHandler(cartService)
+
+	if err := pb.RegisterCartServiceHandler(srv.Server(), cartHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cartService.RunExpiry(ctx)
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
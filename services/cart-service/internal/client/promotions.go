@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/service"
+	promotionspb "github.com/Dzaakk/micro-commerce/services/promotions-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type promotions struct {
+	promotions promotionspb.PromotionsService
+}
+
+// NewPromotions prices carts with the promotions service registered under
+// name.
+func NewPromotions(name string, c client.Client) service.Promotions {
+	return &promotions{
+		promotions: promotionspb.NewPromotionsService(name, c),
+	}
+}
+
+func (p *promotions) Evaluate(ctx context.Context, customerID int64, codes []string, lines []model.PricingLine, rate *model.ExchangeRate) (*model.Pricing, error) {
+	req := &promotionspb.EvaluatePricingRequest{
+		CustomerId: customerID,
+		Codes:      codes,
+		ExchangeRate: &promotionspb.ExchangeRate{
+			BaseCurrency: rate.BaseCurrency,
+			Currency:     rate.Currency,
+			Rate:         rate.Rate,
+		},
+	}
+	for _, line := range lines {
+		req.Lines = append(req.Lines, &promotionspb.PricingLine{
+			Sku:       line.SKU,
+			Quantity:  int32(line.Quantity),
+			UnitPrice: line.UnitPrice,
+		})
+	}
+
+	resp, err := p.promotions.EvaluatePricing(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := &model.Pricing{LineDiscounts: map[string]int64{}}
+	if resp.Pricing == nil {
+		return pricing, nil
+	}
+
+	pricing.DiscountTotal = resp.Pricing.DiscountTotal
+	for _, line := range resp.Pricing.Lines {
+		pricing.LineDiscounts[line.Sku] += line.Discount
+	}
+	for _, applied := range resp.Pricing.Applied {
+		pricing.Applied = append(pricing.Applied, model.AppliedPromotion{
+			PromotionID: applied.PromotionId,
+			Code:        applied.Code,
+			Name:        applied.Name,
+			Type:        applied.Type,
+			Discount:    applied.Discount,
+		})
+	}
+	for _, rejected := range resp.Pricing.Rejected {
+		pricing.Rejected = append(pricing.Rejected, model.RejectedCoupon{
+			Code:   rejected.Code,
+			Reason: rejected.Reason,
+		})
+	}
+
+	return pricing, nil
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/service"
+	inventorypb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type stockChecker struct {
+	inventory inventorypb.InventoryService
+}
+
+// NewStockChecker reads availability from the inventory service registered
+// under name.
+func NewStockChecker(name string, c client.Client) service.StockChecker {
+	return &stockChecker{
+		inventory: inventorypb.NewInventoryService(name, c),
+	}
+}
+
+func (s *stockChecker) Available(ctx context.Context, skus []string) (map[string]int, error) {
+	resp, err := s.inventory.GetStock(ctx, &inventorypb.GetStockRequest{Skus: skus})
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]int, len(resp.Items))
+	for _, item := range resp.Items {
+		available[item.Sku] = int(item.Available)
+	}
+
+	return available, nil
+}
@@ -0,0 +1,55 @@
+// services/cart-service/internal/config/config.go
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Registry names of the services carts are revalidated against.
+	ProductServiceName    string
+	InventoryServiceName  string
+	PromotionsServiceName string
+
+	// Carts expire after this long without being touched.
+	GuestCartTTL        time.Duration
+	UserCartTTL         time.Duration
+	ExpirySweepInterval time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:                  getEnv("PORT", "8088"),
+		Environment:           os.Getenv("ENVIRONMENT"),
+		DatabaseURL:           os.Getenv("DATABASE_URL"),
+		ProductServiceName:    getEnv("PRODUCT_SERVICE_NAME", "product-service"),
+		InventoryServiceName:  getEnv("INVENTORY_SERVICE_NAME", "inventory-service"),
+		PromotionsServiceName: getEnv("PROMOTIONS_SERVICE_NAME", "promotions-service"),
+		GuestCartTTL:          getDurationEnv("GUEST_CART_TTL", 30*24*time.Hour),
+		UserCartTTL:           getDurationEnv("USER_CART_TTL", 90*24*time.Hour),
+		ExpirySweepInterval:   getDurationEnv("EXPIRY_SWEEP_INTERVAL", 10*time.Minute),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
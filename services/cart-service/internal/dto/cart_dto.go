@@ -0,0 +1,67 @@
+package dto
+
+type AddItemRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type UpdateItemRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type CartItemResponse struct {
+	SKU               string `json:"sku"`
+	ProductID         string `json:"product_id,omitempty"`
+	VariantID         string `json:"variant_id,omitempty"`
+	ProductName       string `json:"product_name,omitempty"`
+	Size              string `json:"size,omitempty"`
+	Color             string `json:"color,omitempty"`
+	Quantity          int    `json:"quantity"`
+	UnitPrice         int64  `json:"unit_price"`
+	AddedPrice        int64  `json:"added_price"`
+	LineTotal         int64  `json:"line_total"`
+	AvailableQuantity int    `json:"available_quantity"`
+	Issue             string `json:"issue,omitempty"`
+	Discount          int64  `json:"discount"`
+}
+
+type CartPromotionResponse struct {
+	PromotionID string `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Discount    int64  `json:"discount"`
+}
+
+type RejectedCouponResponse struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// ExchangeRateResponse is the rate base prices were converted with.
+type ExchangeRateResponse struct {
+	ID           int64  `json:"id"`
+	BaseCurrency string `json:"base_currency"`
+	Currency     string `json:"currency"`
+	Rate         string `json:"rate"`
+}
+
+type CartResponse struct {
+	ID              string                    `json:"id,omitempty"`
+	UserID          int64                     `json:"user_id,omitempty"`
+	GuestID         string                    `json:"guest_id,omitempty"`
+	Items           []*CartItemResponse       `json:"items"`
+	ItemCount       int                       `json:"item_count"`
+	Subtotal        int64                     `json:"subtotal"`
+	Valid           bool                      `json:"valid"`
+	CouponCodes     []string                  `json:"coupon_codes"`
+	Currency        string                    `json:"currency,omitempty"`
+	ExchangeRate    *ExchangeRateResponse     `json:"exchange_rate,omitempty"`
+	DiscountTotal   int64                     `json:"discount_total"`
+	Total           int64                     `json:"total"`
+	Promotions      []*CartPromotionResponse  `json:"promotions"`
+	RejectedCoupons []*RejectedCouponResponse `json:"rejected_coupons"`
+	ExpiresAt       string                    `json:"expires_at,omitempty"`
+	UpdatedAt       string                    `json:"updated_at,omitempty"`
+}
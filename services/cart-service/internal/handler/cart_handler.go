@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/cart-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "cart-service"
+
+type CartHandler struct {
+	cartService service.CartService
+}
+
+func NewCartHandler(cartService service.CartService) *CartHandler {
+	return &CartHandler{
+		cartService: cartService,
+	}
+}
+
+func (h *CartHandler) GetCart(ctx context.Context, req *pb.CartRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.GetCart(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) AddItem(ctx context.Context, req *pb.AddItemRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.AddItem(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, &dto.AddItemRequest{
+		SKU:      req.Sku,
+		Quantity: int(req.Quantity),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) UpdateItem(ctx context.Context, req *pb.UpdateItemRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.UpdateItem(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, &dto.UpdateItemRequest{
+		SKU:      req.Sku,
+		Quantity: int(req.Quantity),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) RemoveItem(ctx context.Context, req *pb.RemoveItemRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.RemoveItem(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, req.Sku)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) ClearCart(ctx context.Context, req *pb.CartRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.ClearCart(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) MergeCarts(ctx context.Context, req *pb.MergeCartsRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.MergeCarts(ctx, req.GuestId, req.UserId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) ApplyCoupon(ctx context.Context, req *pb.CouponRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.ApplyCoupon(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, req.Code)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) RemoveCoupon(ctx context.Context, req *pb.CouponRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.RemoveCoupon(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, req.Code)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func (h *CartHandler) SetCurrency(ctx context.Context, req *pb.CurrencyRequest, rsp *pb.CartResponse) error {
+	cart, err := h.cartService.SetCurrency(ctx, model.CartOwner{UserID: req.UserId, GuestID: req.GuestId}, req.Currency)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Cart = toProtoCart(cart)
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+	var upstreamErr *microerrors.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.As(err, &upstreamErr) && upstreamErr.Code == http.StatusBadRequest:
+		// e.g. a currency the catalog has no exchange rate for
+		return microerrors.BadRequest(serviceID, "%s", upstreamErr.Detail)
+	case errors.Is(err, repository.ErrItemNotFound),
+		errors.Is(err, service.ErrCouponNotApplied):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrItemUnavailable),
+		errors.Is(err, service.ErrInsufficientStock),
+		errors.Is(err, service.ErrCartFull),
+		errors.Is(err, service.ErrCartEmpty),
+		errors.Is(err, service.ErrCouponRejected):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoCart(cart *dto.CartResponse) *pb.Cart {
+	resp := &pb.Cart{
+		Id:            cart.ID,
+		UserId:        cart.UserID,
+		GuestId:       cart.GuestID,
+		ItemCount:     int32(cart.ItemCount),
+		Subtotal:      cart.Subtotal,
+		Valid:         cart.Valid,
+		ExpiresAt:     cart.ExpiresAt,
+		UpdatedAt:     cart.UpdatedAt,
+		CouponCodes:   cart.CouponCodes,
+		DiscountTotal: cart.DiscountTotal,
+		Total:         cart.Total,
+		Currency:      cart.Currency,
+	}
+
+	if cart.ExchangeRate != nil {
+		resp.ExchangeRate = &pb.ExchangeRate{
+			Id:           cart.ExchangeRate.ID,
+			BaseCurrency: cart.ExchangeRate.BaseCurrency,
+			Currency:     cart.ExchangeRate.Currency,
+			Rate:         cart.ExchangeRate.Rate,
+		}
+	}
+
+	for _, item := range cart.Items {
+		resp.Items = append(resp.Items, &pb.CartItem{
+			Sku:               item.SKU,
+			ProductId:         item.ProductID,
+			VariantId:         item.VariantID,
+			ProductName:       item.ProductName,
+			Size:              item.Size,
+			Color:             item.Color,
+			Quantity:          int32(item.Quantity),
+			UnitPrice:         item.UnitPrice,
+			AddedPrice:        item.AddedPrice,
+			LineTotal:         item.LineTotal,
+			AvailableQuantity: int32(item.AvailableQuantity),
+			Issue:             item.Issue,
+			Discount:          item.Discount,
+		})
+	}
+
+	for _, promotion := range cart.Promotions {
+		resp.Promotions = append(resp.Promotions, &pb.CartPromotion{
+			PromotionId: promotion.PromotionID,
+			Code:        promotion.Code,
+			Name:        promotion.Name,
+			Type:        promotion.Type,
+			Discount:    promotion.Discount,
+		})
+	}
+
+	for _, rejected := range cart.RejectedCoupons {
+		resp.RejectedCoupons = append(resp.RejectedCoupons, &pb.RejectedCoupon{
+			Code:   rejected.Code,
+			Reason: rejected.Reason,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,106 @@
+package model
+
+import "time"
+
+// CartOwner identifies a cart by exactly one of UserID or GuestID.
+type CartOwner struct {
+	UserID  int64
+	GuestID string
+}
+
+func (o CartOwner) IsGuest() bool {
+	return o.UserID == 0
+}
+
+// Cart is priced in Currency, an ISO 4217 code. Empty means the catalog's
+// base currency.
+type Cart struct {
+	ID          string   `db:"id"`
+	UserID      int64    `db:"user_id"`
+	GuestID     string   `db:"guest_id"`
+	CouponCodes []string `db:"coupon_codes"`
+	Currency    string   `db:"currency"`
+	Items       []*CartItem
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (c *Cart) Item(sku string) *CartItem {
+	for _, item := range c.Items {
+		if item.SKU == sku {
+			return item
+		}
+	}
+	return nil
+}
+
+// CartItem keeps the price the item was added at, in the cart's currency.
+type CartItem struct {
+	SKU       string    `db:"sku"`
+	Quantity  int       `db:"quantity"`
+	UnitPrice int64     `db:"unit_price"`
+	AddedAt   time.Time `db:"added_at"`
+}
+
+// IsCurrencyCode reports whether code has the shape of an ISO 4217 code.
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ExchangeRate is the rate the product service converted base prices with:
+// one unit of BaseCurrency is worth Rate units of Currency. ID is zero when
+// the cart is in the base currency.
+type ExchangeRate struct {
+	ID           int64
+	BaseCurrency string
+	Currency     string
+	Rate         string
+}
+
+// SKUDetails is what the product service currently says about a SKU.
+type SKUDetails struct {
+	SKU         string
+	ProductID   string
+	VariantID   string
+	ProductName string
+	Size        string
+	Color       string
+	Price       int64
+}
+
+// PricingLine is a cart line as sent to the promotions service.
+type PricingLine struct {
+	SKU       string
+	Quantity  int
+	UnitPrice int64
+}
+
+type AppliedPromotion struct {
+	PromotionID string
+	Code        string
+	Name        string
+	Type        string
+	Discount    int64
+}
+
+type RejectedCoupon struct {
+	Code   string
+	Reason string
+}
+
+// Pricing is what the promotions service takes off a cart.
+type Pricing struct {
+	DiscountTotal int64
+	LineDiscounts map[string]int64
+	Applied       []AppliedPromotion
+	Rejected      []RejectedCoupon
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+)
+
+var (
+	ErrCartNotFound = errors.New("cart not found")
+	ErrItemNotFound = errors.New("item not in cart")
+)
+
+// CartRepository stores carts and their items. Expired carts are treated as
+// missing and are emptied the next time they are written to.
+type CartRepository interface {
+	GetByOwner(ctx context.Context, owner model.CartOwner, now time.Time) (*model.Cart, error)
+
+	// SaveItem creates the cart if needed, sets the item to the given
+	// quantity and price, and pushes the cart expiry out to expiresAt.
+	SaveItem(ctx context.Context, owner model.CartOwner, item *model.CartItem, now, expiresAt time.Time) (*model.Cart, error)
+	RemoveItem(ctx context.Context, owner model.CartOwner, sku string, now, expiresAt time.Time) (*model.Cart, error)
+	// SetCouponCodes replaces the coupon codes of a live cart. It returns
+	// ErrCartNotFound rather than create a cart.
+	SetCouponCodes(ctx context.Context, owner model.CartOwner, codes []string, now, expiresAt time.Time) (*model.Cart, error)
+	// SetCurrency creates the cart if needed, switches it to currency and
+	// sets the items to the given prices in it, so they do not show as
+	// changed. Items without a price keep theirs.
+	SetCurrency(ctx context.Context, owner model.CartOwner, currency string, prices map[string]int64, now, expiresAt time.Time) (*model.Cart, error)
+	// Clear empties the cart and drops its coupon codes.
+	Clear(ctx context.Context, owner model.CartOwner) error
+
+	// Merge moves the items of the guest cart into the user's cart, adding
+	// quantities of SKUs present in both (capped at maxQuantity), adds the
+	// guest's coupon codes up to maxCoupons, and deletes the guest cart. An
+	// existing user cart keeps its currency. It returns ErrCartNotFound when
+	// there is no live guest cart.
+	Merge(ctx context.Context, guestID string, userID int64, maxQuantity, maxCoupons int, now, expiresAt time.Time) (*model.Cart, error)
+
+	// DeleteExpired removes up to limit carts that expired before now.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error)
+}
@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const cartColumns = `
+	id, COALESCE(user_id, 0), COALESCE(guest_id, ''), coupon_codes, COALESCE(currency, ''),
+	expires_at, created_at, updated_at
+`
+
+type cartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func scanCart(row rowScanner) (*model.Cart, error) {
+	var cart model.Cart
+	err := row.Scan(
+		&cart.ID,
+		&cart.UserID,
+		&cart.GuestID,
+		pq.Array(&cart.CouponCodes),
+		&cart.Currency,
+		&cart.ExpiresAt,
+		&cart.CreatedAt,
+		&cart.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+// ownerFilter returns the WHERE condition and argument selecting the cart of
+// owner.
+func ownerFilter(owner model.CartOwner) (string, interface{}) {
+	if owner.IsGuest() {
+		return "guest_id = $1", owner.GuestID
+	}
+	return "user_id = $1", owner.UserID
+}
+
+func (r *cartRepository) GetByOwner(ctx context.Context, owner model.CartOwner, now time.Time) (*model.Cart, error) {
+	filter, arg := ownerFilter(owner)
+
+	cart, err := scanCart(r.db.QueryRowContext(ctx,
+		`SELECT `+cartColumns+` FROM carts WHERE `+filter+` AND expires_at > $2`, arg, now,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.Items, err = listItems(ctx, r.db, cart.ID); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+func (r *cartRepository) SaveItem(ctx context.Context, owner model.CartOwner, item *model.CartItem, now, expiresAt time.Time) (*model.Cart, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cart, err := ensureCart(ctx, tx, owner, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items (cart_id, sku, quantity, unit_price)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cart_id, sku) DO UPDATE
+		SET quantity = EXCLUDED.quantity, unit_price = EXCLUDED.unit_price
+	`, cart.ID, item.SKU, item.Quantity, item.UnitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return touchAndCommit(ctx, tx, cart, expiresAt)
+}
+
+func (r *cartRepository) RemoveItem(ctx context.Context, owner model.CartOwner, sku string, now, expiresAt time.Time) (*model.Cart, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cart, err := lockCart(ctx, tx, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM cart_items WHERE cart_id = $1 AND sku = $2`, cart.ID, sku)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	return touchAndCommit(ctx, tx, cart, expiresAt)
+}
+
+func (r *cartRepository) SetCouponCodes(ctx context.Context, owner model.CartOwner, codes []string, now, expiresAt time.Time) (*model.Cart, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cart, err := lockCart(ctx, tx, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE carts SET coupon_codes = $2 WHERE id = $1`, cart.ID, pq.Array(codes),
+	); err != nil {
+		return nil, err
+	}
+	cart.CouponCodes = codes
+
+	return touchAndCommit(ctx, tx, cart, expiresAt)
+}
+
+func (r *cartRepository) SetCurrency(ctx context.Context, owner model.CartOwner, currency string, prices map[string]int64, now, expiresAt time.Time) (*model.Cart, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cart, err := ensureCart(ctx, tx, owner, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE carts SET currency = NULLIF($2, '') WHERE id = $1`, cart.ID, currency,
+	); err != nil {
+		return nil, err
+	}
+	cart.Currency = currency
+
+	for sku, price := range prices {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE cart_items SET unit_price = $3 WHERE cart_id = $1 AND sku = $2`, cart.ID, sku, price,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return touchAndCommit(ctx, tx, cart, expiresAt)
+}
+
+func (r *cartRepository) Clear(ctx context.Context, owner model.CartOwner) error {
+	filter, arg := ownerFilter(owner)
+
+	_, err := r.db.ExecContext(ctx, `
+		WITH cart AS (
+			UPDATE carts SET coupon_codes = '{}' WHERE `+filter+` RETURNING id
+		)
+		DELETE FROM cart_items WHERE cart_id = (SELECT id FROM cart)
+	`, arg)
+	return err
+}
+
+func (r *cartRepository) Merge(ctx context.Context, guestID string, userID int64, maxQuantity, maxCoupons int, now, expiresAt time.Time) (*model.Cart, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	guestCart, err := lockCart(ctx, tx, model.CartOwner{GuestID: guestID}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	userCart, err := lockCart(ctx, tx, model.CartOwner{UserID: userID}, now)
+	if err == ErrCartNotFound {
+		// No user cart yet: hand the guest cart over as it is.
+		_, err = tx.ExecContext(ctx,
+			`UPDATE carts SET user_id = $2, guest_id = NULL WHERE id = $1`, guestCart.ID, userID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		guestCart.UserID = userID
+		guestCart.GuestID = ""
+		return touchAndCommit(ctx, tx, guestCart, expiresAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items (cart_id, sku, quantity, unit_price, added_at)
+		SELECT $1, sku, LEAST(quantity, $3), unit_price, added_at
+		FROM cart_items
+		WHERE cart_id = $2
+		ON CONFLICT (cart_id, sku) DO UPDATE
+		SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $3)
+	`, userCart.ID, guestCart.ID, maxQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Guest codes the user does not have yet are added after the user's own.
+	err = tx.QueryRowContext(ctx, `
+		UPDATE carts SET coupon_codes = (
+			coupon_codes || ARRAY(
+				SELECT code FROM unnest($2::text[]) WITH ORDINALITY AS guest(code, position)
+				WHERE NOT code = ANY(coupon_codes)
+				ORDER BY position
+			)
+		)[1:$3]
+		WHERE id = $1
+		RETURNING coupon_codes
+	`, userCart.ID, pq.Array(guestCart.CouponCodes), maxCoupons).Scan(pq.Array(&userCart.CouponCodes))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM carts WHERE id = $1`, guestCart.ID); err != nil {
+		return nil, err
+	}
+
+	return touchAndCommit(ctx, tx, userCart, expiresAt)
+}
+
+func (r *cartRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM carts
+		WHERE id IN (
+			SELECT id FROM carts
+			WHERE expires_at <= $1
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// lockCart locks the cart of owner for the rest of tx. An expired user cart
+// is emptied and returned as if it were new; an expired guest cart is
+// reported as ErrCartNotFound.
+func lockCart(ctx context.Context, tx *sql.Tx, owner model.CartOwner, now time.Time) (*model.Cart, error) {
+	filter, arg := ownerFilter(owner)
+
+	cart, err := scanCart(tx.QueryRowContext(ctx,
+		`SELECT `+cartColumns+` FROM carts WHERE `+filter+` FOR UPDATE`, arg,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !cart.ExpiresAt.After(now) {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM cart_items WHERE cart_id = $1`, cart.ID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE carts SET coupon_codes = '{}' WHERE id = $1`, cart.ID); err != nil {
+			return nil, err
+		}
+		cart.CouponCodes = nil
+		if owner.IsGuest() {
+			return nil, ErrCartNotFound
+		}
+	}
+
+	return cart, nil
+}
+
+func ensureCart(ctx context.Context, tx *sql.Tx, owner model.CartOwner, now, expiresAt time.Time) (*model.Cart, error) {
+	cart, err := lockCart(ctx, tx, owner, now)
+	if err != ErrCartNotFound {
+		return cart, err
+	}
+
+	var userID sql.NullInt64
+	var guestID sql.NullString
+	if owner.IsGuest() {
+		guestID = sql.NullString{String: owner.GuestID, Valid: true}
+	} else {
+		userID = sql.NullInt64{Int64: owner.UserID, Valid: true}
+	}
+
+	// An expired guest cart still holds the guest_id until it is swept.
+	if owner.IsGuest() {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM carts WHERE guest_id = $1`, owner.GuestID); err != nil {
+			return nil, err
+		}
+	}
+
+	cart, err = scanCart(tx.QueryRowContext(ctx, `
+		INSERT INTO carts (user_id, guest_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING `+cartColumns,
+		userID, guestID, expiresAt,
+	))
+	if err == sql.ErrNoRows {
+		// Created concurrently by another request.
+		return lockCart(ctx, tx, owner, now)
+	}
+
+	return cart, err
+}
+
+func touchAndCommit(ctx context.Context, tx *sql.Tx, cart *model.Cart, expiresAt time.Time) (*model.Cart, error) {
+	err := tx.QueryRowContext(ctx,
+		`UPDATE carts SET expires_at = $2, updated_at = NOW() WHERE id = $1 RETURNING expires_at, updated_at`,
+		cart.ID, expiresAt,
+	).Scan(&cart.ExpiresAt, &cart.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.Items, err = listItems(ctx, tx, cart.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+func listItems(ctx context.Context, q queryer, cartID string) ([]*model.CartItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT sku, quantity, unit_price, added_at
+		FROM cart_items
+		WHERE cart_id = $1
+		ORDER BY added_at ASC, sku ASC
+	`, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.CartItem
+	for rows.Next() {
+		var item model.CartItem
+		if err := rows.Scan(&item.SKU, &item.Quantity, &item.UnitPrice, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
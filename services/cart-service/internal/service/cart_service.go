@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+)
+
+var (
+	ErrItemUnavailable   = errors.New("item is not available")
+	ErrInsufficientStock = errors.New("not enough stock for the requested quantity")
+	ErrCartFull          = errors.New("cart has too many items")
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrCouponRejected    = errors.New("coupon cannot be applied")
+	ErrCouponNotApplied  = errors.New("coupon is not applied to the cart")
+)
+
+// Issues reported on cart items that cannot be checked out as they are.
+const (
+	IssueUnavailable       = "unavailable"
+	IssueInsufficientStock = "insufficient_stock"
+	IssuePriceChanged      = "price_changed"
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ProductCatalog resolves SKUs against the product service, priced in
+// currency (empty for the base currency), and returns the exchange rate
+// used. SKUs that are unknown or not for sale are left out of the result.
+type ProductCatalog interface {
+	LookupSKUs(ctx context.Context, skus []string, currency string) (map[string]*model.SKUDetails, *model.ExchangeRate, error)
+}
+
+// StockChecker reports the available quantity of SKUs from the inventory
+// service. Unknown SKUs have zero available.
+type StockChecker interface {
+	Available(ctx context.Context, skus []string) (map[string]int, error)
+}
+
+// Promotions prices carts through the promotions service. Codes that do
+// not apply are reported in the pricing rather than failing the call. The
+// lines are in the currency of rate.
+type Promotions interface {
+	Evaluate(ctx context.Context, customerID int64, codes []string, lines []model.PricingLine, rate *model.ExchangeRate) (*model.Pricing, error)
+}
+
+// CartService manages guest and user carts
+type CartService interface {
+	// GetCart returns the cart revalidated against current prices and stock.
+	// A missing cart is returned as an empty one.
+	GetCart(ctx context.Context, owner model.CartOwner) (*dto.CartResponse, error)
+	AddItem(ctx context.Context, owner model.CartOwner, req *dto.AddItemRequest) (*dto.CartResponse, error)
+	UpdateItem(ctx context.Context, owner model.CartOwner, req *dto.UpdateItemRequest) (*dto.CartResponse, error)
+	RemoveItem(ctx context.Context, owner model.CartOwner, sku string) (*dto.CartResponse, error)
+	ClearCart(ctx context.Context, owner model.CartOwner) (*dto.CartResponse, error)
+
+	// MergeCarts moves a guest cart into the user's cart on login.
+	MergeCarts(ctx context.Context, guestID string, userID int64) (*dto.CartResponse, error)
+
+	// ApplyCoupon adds a code to a cart with items. It fails with
+	// ErrCouponRejected if the promotions service would not apply it.
+	ApplyCoupon(ctx context.Context, owner model.CartOwner, code string) (*dto.CartResponse, error)
+	RemoveCoupon(ctx context.Context, owner model.CartOwner, code string) (*dto.CartResponse, error)
+
+	// SetCurrency prices the cart in another currency; empty switches back
+	// to the base currency. Items are repriced at once.
+	SetCurrency(ctx context.Context, owner model.CartOwner, currency string) (*dto.CartResponse, error)
+
+	// RunExpiry deletes expired carts until ctx is cancelled.
+	RunExpiry(ctx context.Context)
+}
@@ -0,0 +1,582 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/cart-service/internal/repository"
+
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	maxItemsPerCart    = 50
+	maxQuantityPerItem = 99
+	maxSKULength       = 64
+	maxCouponsPerCart  = 5
+	expiryBatchSize    = 500
+)
+
+var (
+	guestIDPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]{16,64}$`)
+	couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+)
+
+type cartServiceImpl struct {
+	cartRepo      repository.CartRepository
+	catalog       ProductCatalog
+	stock         StockChecker
+	promotions    Promotions
+	guestTTL      time.Duration
+	userTTL       time.Duration
+	sweepInterval time.Duration
+}
+
+func NewCartService(
+	cartRepo repository.CartRepository,
+	catalog ProductCatalog,
+	stock StockChecker,
+	promotions Promotions,
+	guestTTL, userTTL, sweepInterval time.Duration,
+) CartService {
+	return &cartServiceImpl{
+		cartRepo:      cartRepo,
+		catalog:       catalog,
+		stock:         stock,
+		promotions:    promotions,
+		guestTTL:      guestTTL,
+		userTTL:       userTTL,
+		sweepInterval: sweepInterval,
+	}
+}
+
+// GetCart implements CartService.
+func (s *cartServiceImpl) GetCart(ctx context.Context, owner model.CartOwner) (*dto.CartResponse, error) {
+	if err := validateOwner(owner); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, time.Now().UTC())
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return emptyCart(owner), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// AddItem implements CartService.
+func (s *cartServiceImpl) AddItem(ctx context.Context, owner model.CartOwner, req *dto.AddItemRequest) (*dto.CartResponse, error) {
+	sku := strings.TrimSpace(req.SKU)
+	if err := validateItem(owner, sku, req.Quantity, 1); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	quantity := req.Quantity
+	currency := ""
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, now)
+	if err != nil && !errors.Is(err, repository.ErrCartNotFound) {
+		return nil, err
+	}
+	if cart != nil {
+		currency = cart.Currency
+		if existing := cart.Item(sku); existing != nil {
+			quantity += existing.Quantity
+		} else if len(cart.Items) >= maxItemsPerCart {
+			return nil, ErrCartFull
+		}
+	}
+
+	if quantity > maxQuantityPerItem {
+		return nil, &ValidationError{Fields: map[string]string{
+			"quantity": fmt.Sprintf("cannot exceed %d per item in total", maxQuantityPerItem),
+		}}
+	}
+
+	return s.saveItem(ctx, owner, currency, sku, quantity, now)
+}
+
+// UpdateItem implements CartService.
+func (s *cartServiceImpl) UpdateItem(ctx context.Context, owner model.CartOwner, req *dto.UpdateItemRequest) (*dto.CartResponse, error) {
+	sku := strings.TrimSpace(req.SKU)
+	if err := validateItem(owner, sku, req.Quantity, 0); err != nil {
+		return nil, err
+	}
+
+	if req.Quantity == 0 {
+		return s.RemoveItem(ctx, owner, sku)
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, now)
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, repository.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cart.Item(sku) == nil {
+		return nil, repository.ErrItemNotFound
+	}
+
+	return s.saveItem(ctx, owner, cart.Currency, sku, req.Quantity, now)
+}
+
+// RemoveItem implements CartService.
+func (s *cartServiceImpl) RemoveItem(ctx context.Context, owner model.CartOwner, sku string) (*dto.CartResponse, error) {
+	if err := validateOwner(owner); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.RemoveItem(ctx, owner, strings.TrimSpace(sku), now, now.Add(s.ttl(owner)))
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, repository.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// ClearCart implements CartService.
+func (s *cartServiceImpl) ClearCart(ctx context.Context, owner model.CartOwner) (*dto.CartResponse, error) {
+	if err := validateOwner(owner); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.Clear(ctx, owner); err != nil {
+		return nil, err
+	}
+
+	return emptyCart(owner), nil
+}
+
+// MergeCarts implements CartService.
+func (s *cartServiceImpl) MergeCarts(ctx context.Context, guestID string, userID int64) (*dto.CartResponse, error) {
+	if err := validateOwner(model.CartOwner{GuestID: guestID}); err != nil {
+		return nil, err
+	}
+	owner := model.CartOwner{UserID: userID}
+	if err := validateOwner(owner); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.Merge(ctx, guestID, userID, maxQuantityPerItem, maxCouponsPerCart, now, now.Add(s.userTTL))
+	if errors.Is(err, repository.ErrCartNotFound) {
+		// Nothing to merge; the user keeps their own cart.
+		return s.GetCart(ctx, owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// ApplyCoupon implements CartService.
+func (s *cartServiceImpl) ApplyCoupon(ctx context.Context, owner model.CartOwner, code string) (*dto.CartResponse, error) {
+	code, err := validateCoupon(owner, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, now)
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, ErrCartEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, ErrCartEmpty
+	}
+	if containsCode(cart.CouponCodes, code) {
+		return s.revalidate(ctx, cart)
+	}
+	if len(cart.CouponCodes) >= maxCouponsPerCart {
+		return nil, &ValidationError{Fields: map[string]string{
+			"code": fmt.Sprintf("a cart can hold at most %d coupons", maxCouponsPerCart),
+		}}
+	}
+
+	// Price the cart with the code before storing it so a code that does
+	// not apply is refused with the reason instead of sitting on the cart.
+	codes := append(append([]string{}, cart.CouponCodes...), code)
+	preview := *cart
+	preview.CouponCodes = codes
+
+	resp, err := s.revalidate(ctx, &preview)
+	if err != nil {
+		return nil, err
+	}
+	for _, rejected := range resp.RejectedCoupons {
+		if rejected.Code == code {
+			return nil, fmt.Errorf("%w: %s", ErrCouponRejected, rejected.Reason)
+		}
+	}
+
+	cart, err = s.cartRepo.SetCouponCodes(ctx, owner, codes, now, now.Add(s.ttl(owner)))
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, ErrCartEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// RemoveCoupon implements CartService.
+func (s *cartServiceImpl) RemoveCoupon(ctx context.Context, owner model.CartOwner, code string) (*dto.CartResponse, error) {
+	code, err := validateCoupon(owner, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, now)
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, ErrCouponNotApplied
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !containsCode(cart.CouponCodes, code) {
+		return nil, ErrCouponNotApplied
+	}
+
+	codes := make([]string, 0, len(cart.CouponCodes))
+	for _, applied := range cart.CouponCodes {
+		if applied != code {
+			codes = append(codes, applied)
+		}
+	}
+
+	cart, err = s.cartRepo.SetCouponCodes(ctx, owner, codes, now, now.Add(s.ttl(owner)))
+	if errors.Is(err, repository.ErrCartNotFound) {
+		return nil, ErrCouponNotApplied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// SetCurrency implements CartService.
+func (s *cartServiceImpl) SetCurrency(ctx context.Context, owner model.CartOwner, currency string) (*dto.CartResponse, error) {
+	if err := validateOwner(owner); err != nil {
+		return nil, err
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && !model.IsCurrencyCode(currency) {
+		return nil, &ValidationError{Fields: map[string]string{"currency": "must be an ISO 4217 code"}}
+	}
+
+	now := time.Now().UTC()
+
+	cart, err := s.cartRepo.GetByOwner(ctx, owner, now)
+	if err != nil && !errors.Is(err, repository.ErrCartNotFound) {
+		return nil, err
+	}
+
+	var skus []string
+	if cart != nil {
+		for _, item := range cart.Items {
+			skus = append(skus, item.SKU)
+		}
+	}
+
+	// The lookup also refuses currencies the catalog has no rate for.
+	details, _, err := s.catalog.LookupSKUs(ctx, skus, currency)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]int64, len(details))
+	for sku, product := range details {
+		prices[sku] = product.Price
+	}
+
+	cart, err = s.cartRepo.SetCurrency(ctx, owner, currency, prices, now, now.Add(s.ttl(owner)))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// RunExpiry implements CartService.
+func (s *cartServiceImpl) RunExpiry(ctx context.Context) {
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			deleted, err := s.cartRepo.DeleteExpired(ctx, time.Now().UTC(), expiryBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Errorf("Failed to delete expired carts: %v", err)
+				}
+				break
+			}
+			if deleted > 0 {
+				logger.Infof("Deleted %d expired carts", deleted)
+			}
+			if deleted < expiryBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// saveItem checks that sku is for sale with enough stock for quantity and
+// stores it at the current price in currency.
+func (s *cartServiceImpl) saveItem(ctx context.Context, owner model.CartOwner, currency, sku string, quantity int, now time.Time) (*dto.CartResponse, error) {
+	details, _, err := s.catalog.LookupSKUs(ctx, []string{sku}, currency)
+	if err != nil {
+		return nil, err
+	}
+	product, ok := details[sku]
+	if !ok {
+		return nil, ErrItemUnavailable
+	}
+
+	available, err := s.stock.Available(ctx, []string{sku})
+	if err != nil {
+		return nil, err
+	}
+	if available[sku] < quantity {
+		return nil, fmt.Errorf("%w: %d available", ErrInsufficientStock, available[sku])
+	}
+
+	cart, err := s.cartRepo.SaveItem(ctx, owner, &model.CartItem{
+		SKU:       sku,
+		Quantity:  quantity,
+		UnitPrice: product.Price,
+	}, now, now.Add(s.ttl(owner)))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revalidate(ctx, cart)
+}
+
+// revalidate prices every item at its current price, flags items that are
+// no longer for sale or short of stock, and applies the cart's coupons and
+// any automatic promotions to the items that can still be bought.
+func (s *cartServiceImpl) revalidate(ctx context.Context, cart *model.Cart) (*dto.CartResponse, error) {
+	resp := &dto.CartResponse{
+		ID:              cart.ID,
+		UserID:          cart.UserID,
+		GuestID:         cart.GuestID,
+		Items:           make([]*dto.CartItemResponse, 0, len(cart.Items)),
+		Valid:           len(cart.Items) > 0,
+		CouponCodes:     append([]string{}, cart.CouponCodes...),
+		Currency:        cart.Currency,
+		Promotions:      []*dto.CartPromotionResponse{},
+		RejectedCoupons: []*dto.RejectedCouponResponse{},
+		ExpiresAt:       cart.ExpiresAt.Format(time.RFC3339),
+		UpdatedAt:       cart.UpdatedAt.Format(time.RFC3339),
+	}
+	if len(cart.Items) == 0 {
+		return resp, nil
+	}
+
+	skus := make([]string, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		skus = append(skus, item.SKU)
+	}
+
+	details, rate, err := s.catalog.LookupSKUs(ctx, skus, cart.Currency)
+	if err != nil {
+		return nil, err
+	}
+	resp.Currency = rate.Currency
+	resp.ExchangeRate = &dto.ExchangeRateResponse{
+		ID:           rate.ID,
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+	}
+
+	available, err := s.stock.Available(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []model.PricingLine
+
+	for _, item := range cart.Items {
+		line := &dto.CartItemResponse{
+			SKU:               item.SKU,
+			Quantity:          item.Quantity,
+			UnitPrice:         item.UnitPrice,
+			AddedPrice:        item.UnitPrice,
+			AvailableQuantity: available[item.SKU],
+		}
+
+		product, ok := details[item.SKU]
+		switch {
+		case !ok:
+			line.Issue = IssueUnavailable
+		case line.AvailableQuantity < item.Quantity:
+			line.Issue = IssueInsufficientStock
+		case product.Price != item.UnitPrice:
+			line.Issue = IssuePriceChanged
+		}
+
+		if ok {
+			line.ProductID = product.ProductID
+			line.VariantID = product.VariantID
+			line.ProductName = product.ProductName
+			line.Size = product.Size
+			line.Color = product.Color
+			line.UnitPrice = product.Price
+		}
+
+		switch line.Issue {
+		case IssueUnavailable, IssueInsufficientStock:
+			resp.Valid = false
+		default:
+			line.LineTotal = line.UnitPrice * int64(line.Quantity)
+			resp.Subtotal += line.LineTotal
+			lines = append(lines, model.PricingLine{
+				SKU:       line.SKU,
+				Quantity:  line.Quantity,
+				UnitPrice: line.UnitPrice,
+			})
+		}
+
+		resp.ItemCount += item.Quantity
+		resp.Items = append(resp.Items, line)
+	}
+
+	resp.Total = resp.Subtotal
+	if len(lines) == 0 {
+		return resp, nil
+	}
+
+	pricing, err := s.promotions.Evaluate(ctx, cart.UserID, cart.CouponCodes, lines, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range resp.Items {
+		line.Discount = pricing.LineDiscounts[line.SKU]
+	}
+	for _, applied := range pricing.Applied {
+		resp.Promotions = append(resp.Promotions, &dto.CartPromotionResponse{
+			PromotionID: applied.PromotionID,
+			Code:        applied.Code,
+			Name:        applied.Name,
+			Type:        applied.Type,
+			Discount:    applied.Discount,
+		})
+	}
+	for _, rejected := range pricing.Rejected {
+		resp.RejectedCoupons = append(resp.RejectedCoupons, &dto.RejectedCouponResponse{
+			Code:   rejected.Code,
+			Reason: rejected.Reason,
+		})
+	}
+	resp.DiscountTotal = pricing.DiscountTotal
+	resp.Total = resp.Subtotal - resp.DiscountTotal
+
+	return resp, nil
+}
+
+func (s *cartServiceImpl) ttl(owner model.CartOwner) time.Duration {
+	if owner.IsGuest() {
+		return s.guestTTL
+	}
+	return s.userTTL
+}
+
+func validateOwner(owner model.CartOwner) error {
+	switch {
+	case owner.UserID < 0:
+		return &ValidationError{Fields: map[string]string{"user_id": "must be positive"}}
+	case owner.UserID > 0 && owner.GuestID != "":
+		return &ValidationError{Fields: map[string]string{"owner": "use either user_id or guest_id, not both"}}
+	case owner.UserID == 0 && !guestIDPattern.MatchString(owner.GuestID):
+		return &ValidationError{Fields: map[string]string{"guest_id": "is missing or malformed"}}
+	}
+	return nil
+}
+
+func validateItem(owner model.CartOwner, sku string, quantity, minQuantity int) error {
+	if err := validateOwner(owner); err != nil {
+		return err
+	}
+
+	invalid := map[string]string{}
+	if sku == "" || len(sku) > maxSKULength {
+		invalid["sku"] = fmt.Sprintf("must be between 1 and %d characters", maxSKULength)
+	}
+	if quantity < minQuantity || quantity > maxQuantityPerItem {
+		invalid["quantity"] = fmt.Sprintf("must be between %d and %d", minQuantity, maxQuantityPerItem)
+	}
+	if len(invalid) > 0 {
+		return &ValidationError{Fields: invalid}
+	}
+	return nil
+}
+
+// validateCoupon checks the owner and returns code in its canonical
+// upper-case form.
+func validateCoupon(owner model.CartOwner, code string) (string, error) {
+	if err := validateOwner(owner); err != nil {
+		return "", err
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !couponCodePattern.MatchString(code) {
+		return "", &ValidationError{Fields: map[string]string{"code": "is missing or malformed"}}
+	}
+	return code, nil
+}
+
+func containsCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func emptyCart(owner model.CartOwner) *dto.CartResponse {
+	return &dto.CartResponse{
+		UserID:          owner.UserID,
+		GuestID:         owner.GuestID,
+		Items:           []*dto.CartItemResponse{},
+		CouponCodes:     []string{},
+		Promotions:      []*dto.CartPromotionResponse{},
+		RejectedCoupons: []*dto.RejectedCouponResponse{},
+	}
+}
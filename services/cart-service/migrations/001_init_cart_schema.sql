@@ -0,0 +1,22 @@
+-- A cart belongs to either a signed in user or a guest, never both.
+CREATE TABLE carts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT UNIQUE,
+    guest_id VARCHAR(64) UNIQUE, -- random id carried in the gateway's signed cookie
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT carts_single_owner CHECK ((user_id IS NULL) <> (guest_id IS NULL))
+);
+
+CREATE TABLE cart_items (
+    cart_id UUID NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price BIGINT NOT NULL, -- price when the item was added, in minor units
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cart_id, sku)
+);
+
+-- Indexes
+CREATE INDEX idx_carts_expires_at ON carts(expires_at);
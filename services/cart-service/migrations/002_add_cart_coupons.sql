@@ -0,0 +1,3 @@
+-- Coupon codes the customer entered, priced by the promotions service every
+-- time the cart is read.
+ALTER TABLE carts ADD COLUMN coupon_codes TEXT[] NOT NULL DEFAULT '{}';
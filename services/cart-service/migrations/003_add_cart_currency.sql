@@ -0,0 +1,3 @@
+-- Currency the cart is priced in. NULL prices it in the catalog's base
+-- currency.
+ALTER TABLE carts ADD COLUMN currency CHAR(3);
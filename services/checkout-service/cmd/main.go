@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/checkout-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("checkout-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	checkoutRepo := repository.NewCheckoutRepository(db)
+
+	checkoutService := service.NewCheckoutService(
+		checkoutRepo,
+		client.NewCarts(cfg.CartServiceName, srv.Client()),
+		client.NewAddresses(cfg.CustomerServiceName, srv.Client()),
+		client.NewPromotions(cfg.PromotionsServiceName, srv.Client()),
+		client.NewTaxes(cfg.TaxServiceName, srv.Client()),
+		client.NewShipping(cfg.ShippingServiceName, srv.Client()),
+		client.NewInventory(cfg.InventoryServiceName, srv.Client()),
+		client.NewPayments(cfg.PaymentServiceName, srv.Client()),
+		client.NewOrders(cfg.OrderServiceName, srv.Client()),
+		cfg.Currency,
+		cfg.LeaseDuration,
+		cfg.MaxStepAttempts,
+		cfg.RecoveryInterval,
+	)
+
+	checkoutHandler := handler.NewCheckoutHandler(checkoutService)
+
+	if err := pb.RegisterCheckoutServiceHandler(srv.Server(), checkoutHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go checkoutService.RunRecovery(ctx)
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+module github.com/Dzaakk/micro-commerce/services/checkout-service
+
+go 1.23.0
+
+toolchain go1.24.7
+
+require (
+	github.com/Dzaakk/micro-commerce/services/cart-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/customer-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/inventory-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/order-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/payment-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/promotions-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/shipping-service v0.0.0
+	github.com/Dzaakk/micro-commerce/services/tax-service v0.0.0
+	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
+)
+
+require (
+	github.com/Microsoft/go-winio v0.6.0 // indirect
+	github.com/ProtonMail/go-crypto v0.0.0-20210428141323-04723f9f07d7 // indirect
+	github.com/acomagu/bufpipe v1.0.3 // indirect
+	github.com/bitly/go-simplejson v0.5.0 // indirect
+	github.com/bmizerany/assert v0.0.0-20160611221934-b7ed37b82869 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
+	github.com/emirpasic/gods v1.12.0 // indirect
+	github.com/evanphx/json-patch/v5 v5.5.0 // indirect
+	github.com/felixge/httpsnoop v1.0.1 // indirect
+	github.com/fsnotify/fsnotify v1.6.0 // indirect
+	github.com/go-acme/lego/v4 v4.4.0 // indirect
+	github.com/go-git/gcfg v1.5.0 // indirect
+	github.com/go-git/go-billy/v5 v5.3.1 // indirect
+	github.com/go-git/go-git/v5 v5.4.2 // indirect
+	github.com/gobwas/httphead v0.1.0 // indirect
+	github.com/gobwas/pool v0.2.1 // indirect
+	github.com/gobwas/ws v1.0.4 // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/handlers v1.5.1 // indirect
+	github.com/imdario/mergo v0.3.12 // indirect
+	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
+	github.com/kevinburke/ssh_config v0.0.0-20201106050909-4977a11b4351 // indirect
+	github.com/miekg/dns v1.1.43 // indirect
+	github.com/mitchellh/go-homedir v1.1.0 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	github.com/oxtoacart/bpool v0.0.0-20190530202638-03653db5a59c // indirect
+	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/rogpeppe/go-internal v1.14.1 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	github.com/sergi/go-diff v1.1.0 // indirect
+	github.com/urfave/cli/v2 v2.25.7 // indirect
+	github.com/xanzy/ssh-agent v0.3.0 // indirect
+	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/mod v0.26.0 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	golang.org/x/tools v0.35.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/warnings.v0 v0.1.2 // indirect
+)
+
+require (
+	go-micro.dev/v4 v4.11.0
+	google.golang.org/grpc v1.75.0
+	google.golang.org/protobuf v1.36.8
+)
+
+replace github.com/Dzaakk/micro-commerce/services/cart-service => ../cart-service
+
+replace github.com/Dzaakk/micro-commerce/services/customer-service => ../customer-service
+
+replace github.com/Dzaakk/micro-commerce/services/inventory-service => ../inventory-service
+
+replace github.com/Dzaakk/micro-commerce/services/order-service => ../order-service
+
+replace github.com/Dzaakk/micro-commerce/services/payment-service => ../payment-service
+
+replace github.com/Dzaakk/micro-commerce/services/promotions-service => ../promotions-service
+
+replace github.com/Dzaakk/micro-commerce/services/shipping-service => ../shipping-service
+
+replace github.com/Dzaakk/micro-commerce/services/tax-service => ../tax-service
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	customerpb "github.com/Dzaakk/micro-commerce/services/customer-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type addresses struct {
+	addresses customerpb.AddressService
+}
+
+// NewAddresses looks up shipping addresses through the customer service
+// registered under name.
+func NewAddresses(name string, c client.Client) service.Addresses {
+	return &addresses{
+		addresses: customerpb.NewAddressService(name, c),
+	}
+}
+
+func (a *addresses) GetAddress(ctx context.Context, customerID int64, id string) (*model.ShippingAddress, error) {
+	resp, err := a.addresses.GetAddress(ctx, &customerpb.GetAddressRequest{
+		CustomerId: customerID,
+		Id:         id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	address := resp.Address
+	return &model.ShippingAddress{
+		RecipientName: address.GetRecipientName(),
+		PhoneNumber:   address.GetPhoneNumber(),
+		Line1:         address.GetLine1(),
+		Line2:         address.GetLine2(),
+		City:          address.GetCity(),
+		Region:        address.GetRegion(),
+		PostalCode:    address.GetPostalCode(),
+		Country:       address.GetCountryCode(),
+	}, nil
+}
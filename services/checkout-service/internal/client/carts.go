@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+
+	cartpb "github.com/Dzaakk/micro-commerce/services/cart-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+type carts struct {
+	carts cartpb.CartService
+}
+
+// NewCarts reads and clears carts through the cart service registered under
+// name.
+func NewCarts(name string, c client.Client) service.Carts {
+	return &carts{
+		carts: cartpb.NewCartService(name, c),
+	}
+}
+
+func (c *carts) GetCart(ctx context.Context, customerID int64) (*service.CartSnapshot, error) {
+	resp, err := c.carts.GetCart(ctx, &cartpb.CartRequest{UserId: customerID})
+	if err != nil {
+		return nil, err
+	}
+
+	cart := resp.Cart
+	if cart == nil {
+		return &service.CartSnapshot{}, nil
+	}
+
+	snapshot := &service.CartSnapshot{
+		Lines:       make([]model.CheckoutLine, 0, len(cart.Items)),
+		Subtotal:    cart.Subtotal,
+		Valid:       cart.Valid,
+		CouponCodes: cart.CouponCodes,
+	}
+	if rate := cart.ExchangeRate; rate != nil {
+		snapshot.ExchangeRate = &model.ExchangeRate{
+			ID:           rate.Id,
+			BaseCurrency: rate.BaseCurrency,
+			Currency:     rate.Currency,
+			Rate:         rate.Rate,
+		}
+	}
+	for _, item := range cart.Items {
+		snapshot.Lines = append(snapshot.Lines, model.CheckoutLine{
+			SKU:       item.Sku,
+			Quantity:  int(item.Quantity),
+			UnitPrice: item.UnitPrice,
+		})
+	}
+
+	return snapshot, nil
+}
+
+func (c *carts) ClearCart(ctx context.Context, customerID int64) error {
+	_, err := c.carts.ClearCart(ctx, &cartpb.CartRequest{UserId: customerID})
+	return err
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	inventorypb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type inventory struct {
+	inventory inventorypb.InventoryService
+}
+
+// NewInventory reserves stock through the inventory service registered
+// under name.
+func NewInventory(name string, c client.Client) service.Inventory {
+	return &inventory{
+		inventory: inventorypb.NewInventoryService(name, c),
+	}
+}
+
+func (i *inventory) Reserve(ctx context.Context, reference string, lines []model.CheckoutLine) (string, error) {
+	req := &inventorypb.ReserveRequest{Reference: reference}
+	for _, line := range lines {
+		req.Lines = append(req.Lines, &inventorypb.ReserveLine{
+			Sku:      line.SKU,
+			Quantity: int32(line.Quantity),
+		})
+	}
+
+	resp, err := i.inventory.Reserve(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Reservation.GetId(), nil
+}
+
+func (i *inventory) CommitReservation(ctx context.Context, reservationID string) error {
+	_, err := i.inventory.CommitReservation(ctx, &inventorypb.ReservationRequest{Id: reservationID})
+	return err
+}
+
+func (i *inventory) ReleaseReservation(ctx context.Context, reservationID string) error {
+	resp, err := i.inventory.GetReservation(ctx, &inventorypb.ReservationRequest{Id: reservationID})
+	if err != nil {
+		return err
+	}
+	if resp.Reservation.GetStatus() == "committed" {
+		return fmt.Errorf("%w: %s", service.ErrReservationCommitted, reservationID)
+	}
+
+	_, err = i.inventory.ReleaseReservation(ctx, &inventorypb.ReservationRequest{Id: reservationID})
+	return err
+}
+
+func (i *inventory) Restock(ctx context.Context, reference, reservationReference string, lines []model.CheckoutLine) error {
+	req := &inventorypb.RestockRequest{
+		Reference:            reference,
+		ReservationReference: reservationReference,
+	}
+	for _, line := range lines {
+		req.Lines = append(req.Lines, &inventorypb.ReserveLine{
+			Sku:      line.SKU,
+			Quantity: int32(line.Quantity),
+		})
+	}
+
+	_, err := i.inventory.Restock(ctx, req)
+	return err
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	orderpb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+// systemActor is how the checkout identifies itself in order history.
+const systemActor = "system"
+
+type orders struct {
+	orders orderpb.OrderService
+}
+
+// NewOrders creates and cancels orders through the order service registered
+// under name.
+func NewOrders(name string, c client.Client) service.Orders {
+	return &orders{
+		orders: orderpb.NewOrderService(name, c),
+	}
+}
+
+func (o *orders) CreateOrders(ctx context.Context, checkout *model.Checkout) ([]model.CheckoutOrder, error) {
+	address := checkout.ShippingAddress
+	req := &orderpb.CreateOrdersRequest{
+		CheckoutId:      checkout.ID,
+		CustomerId:      checkout.CustomerID,
+		PaymentId:       checkout.PaymentID,
+		TaxRulesVersion: checkout.TaxRulesVersion,
+		Currency:        checkout.Currency,
+		ExchangeRateId:  checkout.ExchangeRateID,
+		ShippingAddress: &orderpb.ShippingAddress{
+			RecipientName: address.RecipientName,
+			PhoneNumber:   address.PhoneNumber,
+			Line1:         address.Line1,
+			Line2:         address.Line2,
+			City:          address.City,
+			Region:        address.Region,
+			PostalCode:    address.PostalCode,
+			Country:       address.Country,
+		},
+	}
+	for _, line := range checkout.Lines {
+		req.Lines = append(req.Lines, &orderpb.OrderLine{
+			Sku:      line.SKU,
+			Quantity: int32(line.Quantity),
+			Discount: line.Discount,
+		})
+	}
+	for _, charge := range checkout.Shipping {
+		req.Shipping = append(req.Shipping, &orderpb.SellerShipping{
+			SellerId: charge.SellerID,
+			Option:   charge.Option,
+			Amount:   charge.Amount,
+		})
+	}
+
+	resp, err := o.orders.CreateOrders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]model.CheckoutOrder, 0, len(resp.Orders))
+	for _, order := range resp.Orders {
+		created = append(created, model.CheckoutOrder{
+			ID:          order.Id,
+			OrderNumber: order.OrderNumber,
+			SellerID:    order.SellerId,
+			Total:       order.Total,
+		})
+	}
+
+	return created, nil
+}
+
+func (o *orders) MarkPaid(ctx context.Context, orderID string) error {
+	_, err := o.orders.ChangeOrderStatus(ctx, &orderpb.ChangeOrderStatusRequest{
+		Id:        orderID,
+		Status:    "paid",
+		ActorRole: systemActor,
+	})
+	return err
+}
+
+func (o *orders) CancelCheckoutOrders(ctx context.Context, checkoutID, reason string) error {
+	resp, err := o.orders.ListOrders(ctx, &orderpb.ListOrdersRequest{
+		CheckoutId: checkoutID,
+		PageSize:   100,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, order := range resp.Orders {
+		_, err := o.orders.ChangeOrderStatus(ctx, &orderpb.ChangeOrderStatusRequest{
+			Id:        order.Id,
+			Status:    "cancelled",
+			ActorRole: systemActor,
+			Reason:    reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	paymentpb "github.com/Dzaakk/micro-commerce/services/payment-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type payments struct {
+	payments paymentpb.PaymentService
+}
+
+// NewPayments authorizes payments through the payment service registered
+// under name.
+func NewPayments(name string, c client.Client) service.PaymentGateway {
+	return &payments{
+		payments: paymentpb.NewPaymentService(name, c),
+	}
+}
+
+func (p *payments) Authorize(ctx context.Context, req *service.PaymentRequest) (string, error) {
+	resp, err := p.payments.AuthorizePayment(ctx, &paymentpb.AuthorizePaymentRequest{
+		IdempotencyKey: req.Reference,
+		CustomerId:     req.CustomerID,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		PaymentMethod:  req.PaymentMethod,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payment := resp.Payment
+	switch payment.GetStatus() {
+	case "authorized":
+		return payment.Id, nil
+	case "declined":
+		return payment.Id, fmt.Errorf("%w: %s", service.ErrPaymentDeclined, payment.DeclineCode)
+	case "requires_action", "created":
+		return payment.Id, service.ErrPaymentPending
+	default:
+		return payment.GetId(), fmt.Errorf("payment %s is unexpectedly %s", payment.GetId(), payment.GetStatus())
+	}
+}
+
+// compensationRefund is the refund reference used when a checkout is undone
+// after its payment was captured.
+const compensationRefund = "checkout_compensation"
+
+func (p *payments) Capture(ctx context.Context, paymentID string) error {
+	_, err := p.payments.CapturePayment(ctx, &paymentpb.CapturePaymentRequest{Id: paymentID})
+	return err
+}
+
+func (p *payments) Void(ctx context.Context, paymentID string) error {
+	resp, err := p.payments.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: paymentID})
+	if err != nil {
+		return err
+	}
+
+	switch resp.Payment.GetStatus() {
+	case "declined", "voided", "refunded":
+		return nil
+	case "captured", "capture_pending":
+		return fmt.Errorf("%w: %s", service.ErrPaymentCaptured, paymentID)
+	default:
+		_, err = p.payments.VoidPayment(ctx, &paymentpb.VoidPaymentRequest{Id: paymentID})
+		return err
+	}
+}
+
+func (p *payments) Refund(ctx context.Context, paymentID string) error {
+	resp, err := p.payments.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: paymentID})
+	if err != nil {
+		return err
+	}
+
+	switch resp.Payment.GetStatus() {
+	case "captured":
+		_, err = p.payments.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{
+			Id:        paymentID,
+			Reference: compensationRefund,
+		})
+		return err
+	case "capture_pending":
+		// Refundable once settled; the compensation is retried until then.
+		return fmt.Errorf("payment %s is still settling", paymentID)
+	default:
+		// Never captured, or already given back.
+		return nil
+	}
+}
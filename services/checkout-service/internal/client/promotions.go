@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	promotionspb "github.com/Dzaakk/micro-commerce/services/promotions-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type promotions struct {
+	promotions promotionspb.PromotionsService
+}
+
+// NewPromotions prices and redeems checkouts through the promotions service
+// registered under name.
+func NewPromotions(name string, c client.Client) service.Promotions {
+	return &promotions{
+		promotions: promotionspb.NewPromotionsService(name, c),
+	}
+}
+
+func (p *promotions) Evaluate(ctx context.Context, customerID int64, codes []string, lines []model.CheckoutLine, rate *model.ExchangeRate) (*service.Pricing, error) {
+	resp, err := p.promotions.EvaluatePricing(ctx, &promotionspb.EvaluatePricingRequest{
+		CustomerId:   customerID,
+		Codes:        codes,
+		Lines:        toPricingLines(lines),
+		ExchangeRate: toProtoExchangeRate(rate),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := &service.Pricing{LineDiscounts: map[string]int64{}}
+	if resp.Pricing == nil {
+		return pricing, nil
+	}
+
+	pricing.DiscountTotal = resp.Pricing.DiscountTotal
+	for _, line := range resp.Pricing.Lines {
+		pricing.LineDiscounts[line.Sku] += line.Discount
+	}
+	for _, applied := range resp.Pricing.Applied {
+		pricing.Promotions = append(pricing.Promotions, model.CheckoutPromotion{
+			PromotionID: applied.PromotionId,
+			Code:        applied.Code,
+			Discount:    applied.Discount,
+		})
+	}
+
+	return pricing, nil
+}
+
+func (p *promotions) Redeem(ctx context.Context, checkout *model.Checkout) error {
+	req := &promotionspb.RedeemPromotionsRequest{
+		Reference:     checkout.ID,
+		CustomerId:    checkout.CustomerID,
+		Lines:         toPricingLines(checkout.Lines),
+		DiscountTotal: checkout.DiscountTotal,
+		ExchangeRate:  toProtoExchangeRate(checkout.Rate()),
+	}
+	for _, promotion := range checkout.Promotions {
+		req.PromotionIds = append(req.PromotionIds, promotion.PromotionID)
+	}
+
+	_, err := p.promotions.RedeemPromotions(ctx, req)
+	return err
+}
+
+func (p *promotions) Release(ctx context.Context, checkoutID string) error {
+	_, err := p.promotions.ReleasePromotions(ctx, &promotionspb.ReleasePromotionsRequest{Reference: checkoutID})
+	return err
+}
+
+func toPricingLines(lines []model.CheckoutLine) []*promotionspb.PricingLine {
+	result := make([]*promotionspb.PricingLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, &promotionspb.PricingLine{
+			Sku:       line.SKU,
+			Quantity:  int32(line.Quantity),
+			UnitPrice: line.UnitPrice,
+		})
+	}
+	return result
+}
+
+func toProtoExchangeRate(rate *model.ExchangeRate) *promotionspb.ExchangeRate {
+	return &promotionspb.ExchangeRate{
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	shippingpb "github.com/Dzaakk/micro-commerce/services/shipping-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type shipping struct {
+	shipping shippingpb.ShippingService
+}
+
+// NewShipping quotes delivery through the shipping service registered under
+// name.
+func NewShipping(name string, c client.Client) service.Shipping {
+	return &shipping{
+		shipping: shippingpb.NewShippingService(name, c),
+	}
+}
+
+func (s *shipping) Quote(ctx context.Context, address model.ShippingAddress, lines []model.CheckoutLine) ([]service.ShippingQuote, error) {
+	req := &shippingpb.QuoteRatesRequest{
+		Country: address.Country,
+		Region:  address.Region,
+	}
+	for _, line := range lines {
+		req.Lines = append(req.Lines, &shippingpb.QuoteLine{
+			Sku:      line.SKU,
+			Quantity: int32(line.Quantity),
+		})
+	}
+
+	resp, err := s.shipping.QuoteRates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Options are only offered when every parcel has a rate for them.
+	quotes := make([]service.ShippingQuote, 0, len(resp.Options))
+	for _, option := range resp.Options {
+		quote := service.ShippingQuote{
+			Option:   option.Option,
+			Amount:   option.Amount,
+			Currency: option.Currency,
+		}
+		for _, parcel := range resp.Parcels {
+			for _, rate := range parcel.Rates {
+				if rate.Option == option.Option {
+					quote.Charges = append(quote.Charges, model.ShippingCharge{
+						SellerID: parcel.SellerId,
+						Option:   rate.Option,
+						Amount:   rate.Amount,
+					})
+				}
+			}
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	taxpb "github.com/Dzaakk/micro-commerce/services/tax-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type taxes struct {
+	taxes taxpb.TaxService
+}
+
+// NewTaxes calculates checkout taxes through the tax service registered
+// under name.
+func NewTaxes(name string, c client.Client) service.Taxes {
+	return &taxes{
+		taxes: taxpb.NewTaxService(name, c),
+	}
+}
+
+func (t *taxes) Calculate(ctx context.Context, address model.ShippingAddress, lines []model.CheckoutLine) (*service.TaxQuote, error) {
+	req := &taxpb.CalculateTaxRequest{
+		Country: address.Country,
+		Region:  address.Region,
+	}
+	for _, line := range lines {
+		req.Lines = append(req.Lines, &taxpb.TaxLine{
+			Sku:       line.SKU,
+			Quantity:  int32(line.Quantity),
+			UnitPrice: line.UnitPrice,
+			Discount:  line.Discount,
+		})
+	}
+
+	resp, err := t.taxes.CalculateTax(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	calculation := resp.Calculation
+	quote := &service.TaxQuote{
+		RulesVersion:     calculation.RulesVersion,
+		PricesIncludeTax: calculation.PricesIncludeTax,
+		TaxTotal:         calculation.TaxTotal,
+		LineTaxes:        make(map[string]int64, len(calculation.Lines)),
+	}
+	for _, line := range calculation.Lines {
+		quote.LineTaxes[line.Sku] += line.Tax
+	}
+
+	return quote, nil
+}
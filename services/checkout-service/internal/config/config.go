@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Registry names of the services taking part in the checkout saga.
+	CartServiceName       string
+	CustomerServiceName   string
+	InventoryServiceName  string
+	OrderServiceName      string
+	PaymentServiceName    string
+	PromotionsServiceName string
+	ShippingServiceName   string
+	TaxServiceName        string
+
+	// Currency charged for checkouts, as an ISO 4217 code.
+	Currency string
+
+	// A worker owns a checkout for LeaseDuration; if it dies, recovery takes
+	// the checkout over once the lease has run out.
+	LeaseDuration    time.Duration
+	MaxStepAttempts  int
+	RecoveryInterval time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:                  getEnv("PORT", "8089"),
+		Environment:           os.Getenv("ENVIRONMENT"),
+		DatabaseURL:           os.Getenv("DATABASE_URL"),
+		CartServiceName:       getEnv("CART_SERVICE_NAME", "cart-service"),
+		CustomerServiceName:   getEnv("CUSTOMER_SERVICE_NAME", "customer-service"),
+		InventoryServiceName:  getEnv("INVENTORY_SERVICE_NAME", "inventory-service"),
+		OrderServiceName:      getEnv("ORDER_SERVICE_NAME", "order-service"),
+		PaymentServiceName:    getEnv("PAYMENT_SERVICE_NAME", "payment-service"),
+		PromotionsServiceName: getEnv("PROMOTIONS_SERVICE_NAME", "promotions-service"),
+		ShippingServiceName:   getEnv("SHIPPING_SERVICE_NAME", "shipping-service"),
+		TaxServiceName:        getEnv("TAX_SERVICE_NAME", "tax-service"),
+		Currency:              getEnv("CURRENCY", "IDR"),
+		LeaseDuration:         getDurationEnv("CHECKOUT_LEASE_DURATION", time.Minute),
+		MaxStepAttempts:       getIntEnv("CHECKOUT_MAX_STEP_ATTEMPTS", 5),
+		RecoveryInterval:      getDurationEnv("CHECKOUT_RECOVERY_INTERVAL", 30*time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,50 @@
+package dto
+
+type StartCheckoutRequest struct {
+	CustomerID        int64  `json:"customer_id"`
+	IdempotencyKey    string `json:"idempotency_key"`
+	ShippingAddressID string `json:"shipping_address_id"`
+	PaymentMethod     string `json:"payment_method"`
+	ShippingOption    string `json:"shipping_option"`
+}
+
+type CheckoutOrderResponse struct {
+	ID          string `json:"id"`
+	OrderNumber string `json:"order_number"`
+	SellerID    int64  `json:"seller_id"`
+	Total       int64  `json:"total"`
+}
+
+type CheckoutFailureResponse struct {
+	Step    string `json:"step,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type CheckoutStepResponse struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+}
+
+type CheckoutResponse struct {
+	ID               string                   `json:"id"`
+	CustomerID       int64                    `json:"customer_id"`
+	Status           string                   `json:"status"`
+	Subtotal         int64                    `json:"subtotal"`
+	Discount         int64                    `json:"discount"`
+	Tax              int64                    `json:"tax"`
+	PricesIncludeTax bool                     `json:"prices_include_tax"`
+	Shipping         int64                    `json:"shipping"`
+	ShippingOption   string                   `json:"shipping_option"`
+	Amount           int64                    `json:"amount"`
+	Currency         string                   `json:"currency"`
+	BaseCurrency     string                   `json:"base_currency"`
+	ExchangeRate     string                   `json:"exchange_rate"`
+	ExchangeRateID   int64                    `json:"exchange_rate_id,omitempty"`
+	Orders           []*CheckoutOrderResponse `json:"orders"`
+	Failure          *CheckoutFailureResponse `json:"failure,omitempty"`
+	Steps            []*CheckoutStepResponse  `json:"steps"`
+	CreatedAt        string                   `json:"created_at"`
+	UpdatedAt        string                   `json:"updated_at"`
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/checkout-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "checkout-service"
+
+type CheckoutHandler struct {
+	checkoutService service.CheckoutService
+}
+
+func NewCheckoutHandler(checkoutService service.CheckoutService) *CheckoutHandler {
+	return &CheckoutHandler{
+		checkoutService: checkoutService,
+	}
+}
+
+func (h *CheckoutHandler) StartCheckout(ctx context.Context, req *pb.StartCheckoutRequest, rsp *pb.CheckoutResponse) error {
+	checkout, err := h.checkoutService.StartCheckout(ctx, &dto.StartCheckoutRequest{
+		CustomerID:        req.CustomerId,
+		IdempotencyKey:    req.IdempotencyKey,
+		ShippingAddressID: req.ShippingAddressId,
+		PaymentMethod:     req.PaymentMethod,
+		ShippingOption:    req.ShippingOption,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Checkout = toProtoCheckout(checkout)
+	return nil
+}
+
+func (h *CheckoutHandler) GetCheckout(ctx context.Context, req *pb.GetCheckoutRequest, rsp *pb.CheckoutResponse) error {
+	checkout, err := h.checkoutService.GetCheckout(ctx, req.Id, req.CustomerId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Checkout = toProtoCheckout(checkout)
+	return nil
+}
+
+// toMicroError maps errors raised before the saga starts. Failures of saga
+// steps are not errors; they are reported in the checkout itself.
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+	var upstreamErr *microerrors.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrCheckoutNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrCartEmpty),
+		errors.Is(err, service.ErrCartInvalid):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	case errors.As(err, &upstreamErr) && upstreamErr.Code == http.StatusNotFound:
+		// e.g. an unknown shipping address
+		return microerrors.NotFound(serviceID, "%s", upstreamErr.Detail)
+	case errors.As(err, &upstreamErr) && upstreamErr.Code == http.StatusBadRequest:
+		// e.g. a shipping address in a country there are no tax rules for
+		return microerrors.BadRequest(serviceID, "%s", upstreamErr.Detail)
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoCheckout(checkout *dto.CheckoutResponse) *pb.Checkout {
+	resp := &pb.Checkout{
+		Id:               checkout.ID,
+		CustomerId:       checkout.CustomerID,
+		Status:           checkout.Status,
+		Subtotal:         checkout.Subtotal,
+		Discount:         checkout.Discount,
+		Tax:              checkout.Tax,
+		PricesIncludeTax: checkout.PricesIncludeTax,
+		Shipping:         checkout.Shipping,
+		ShippingOption:   checkout.ShippingOption,
+		Amount:           checkout.Amount,
+		Currency:         checkout.Currency,
+		BaseCurrency:     checkout.BaseCurrency,
+		ExchangeRate:     checkout.ExchangeRate,
+		ExchangeRateId:   checkout.ExchangeRateID,
+		CreatedAt:        checkout.CreatedAt,
+		UpdatedAt:        checkout.UpdatedAt,
+	}
+
+	for _, order := range checkout.Orders {
+		resp.Orders = append(resp.Orders, &pb.CheckoutOrder{
+			Id:          order.ID,
+			OrderNumber: order.OrderNumber,
+			SellerId:    order.SellerID,
+			Total:       order.Total,
+		})
+	}
+
+	for _, step := range checkout.Steps {
+		resp.Steps = append(resp.Steps, &pb.CheckoutStep{
+			Name:     step.Name,
+			Status:   step.Status,
+			Attempts: int32(step.Attempts),
+		})
+	}
+
+	if checkout.Failure != nil {
+		resp.Failure = &pb.CheckoutFailure{
+			Step:    checkout.Failure.Step,
+			Code:    checkout.Failure.Code,
+			Message: checkout.Failure.Message,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,183 @@
+package model
+
+import "time"
+
+type CheckoutStatus string
+
+const (
+	// CheckoutRunning means the saga is moving forward through its steps.
+	CheckoutRunning CheckoutStatus = "running"
+	// CheckoutCompensating means a step failed and completed steps are being
+	// undone in reverse order.
+	CheckoutCompensating CheckoutStatus = "compensating"
+	CheckoutCompleted    CheckoutStatus = "completed"
+	CheckoutFailed       CheckoutStatus = "failed"
+)
+
+func (s CheckoutStatus) IsFinal() bool {
+	return s == CheckoutCompleted || s == CheckoutFailed
+}
+
+type StepName string
+
+const (
+	StepRedeemPromotions  StepName = "redeem_promotions"
+	StepReserveStock      StepName = "reserve_stock"
+	StepAuthorizePayment  StepName = "authorize_payment"
+	StepCreateOrder       StepName = "create_order"
+	StepCapturePayment    StepName = "capture_payment"
+	StepMarkPaid          StepName = "mark_paid"
+	StepCommitReservation StepName = "commit_reservation"
+	StepClearCart         StepName = "clear_cart"
+)
+
+// Steps is the order the saga runs in. The reservation is committed last, as
+// the stock it holds is the one thing that can still run out once the
+// payment is captured. Once commit_reservation has succeeded the checkout
+// can no longer fail; clear_cart is only retried.
+var Steps = []StepName{
+	StepRedeemPromotions,
+	StepReserveStock,
+	StepAuthorizePayment,
+	StepCreateOrder,
+	StepCapturePayment,
+	StepMarkPaid,
+	StepCommitReservation,
+	StepClearCart,
+}
+
+type StepStatus string
+
+const (
+	StepPending     StepStatus = "pending"
+	StepSucceeded   StepStatus = "succeeded"
+	StepFailed      StepStatus = "failed"
+	StepCompensated StepStatus = "compensated"
+)
+
+type CheckoutStep struct {
+	Name      StepName   `db:"name"`
+	Status    StepStatus `db:"status"`
+	Attempts  int        `db:"attempts"`
+	LastError string     `db:"last_error"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+type CheckoutLine struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+	// Discount is the part of the line total taken off by promotions.
+	Discount int64 `json:"discount,omitempty"`
+	// Tax is the tax on the line after discount.
+	Tax int64 `json:"tax,omitempty"`
+}
+
+// CheckoutPromotion is a promotion applied to the checkout, redeemed by
+// the redeem_promotions step.
+type CheckoutPromotion struct {
+	PromotionID string `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	Discount    int64  `json:"discount"`
+}
+
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	PhoneNumber   string `json:"phone_number"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+// ShippingCharge is what delivering the parcel of one seller costs.
+type ShippingCharge struct {
+	SellerID int64  `json:"seller_id"`
+	Option   string `json:"option"`
+	Amount   int64  `json:"amount"`
+}
+
+type CheckoutOrder struct {
+	ID          string `json:"id"`
+	OrderNumber string `json:"order_number"`
+	SellerID    int64  `json:"seller_id"`
+	Total       int64  `json:"total"`
+}
+
+type Checkout struct {
+	ID              string              `db:"id"`
+	CustomerID      int64               `db:"customer_id"`
+	IdempotencyKey  string              `db:"idempotency_key"`
+	Status          CheckoutStatus      `db:"status"`
+	Lines           []CheckoutLine      `db:"lines"`
+	ShippingAddress ShippingAddress     `db:"shipping_address"`
+	DiscountTotal   int64               `db:"discount_total"`
+	Promotions      []CheckoutPromotion `db:"promotions"`
+	// TaxTotal is added to Amount unless PricesIncludeTax, in which case it
+	// is already part of the line prices.
+	TaxTotal         int64  `db:"tax_total"`
+	TaxRulesVersion  string `db:"tax_rules_version"`
+	PricesIncludeTax bool   `db:"prices_include_tax"`
+	// ShippingTotal is the sum of the Shipping charges, added to Amount.
+	ShippingTotal  int64            `db:"shipping_total"`
+	ShippingOption string           `db:"shipping_option"`
+	Shipping       []ShippingCharge `db:"shipping"`
+	Amount         int64            `db:"amount"`
+	// Currency is what every amount is in. Prices are set in BaseCurrency
+	// and were converted with ExchangeRate, which ExchangeRateID names.
+	Currency       string          `db:"currency"`
+	BaseCurrency   string          `db:"base_currency"`
+	ExchangeRateID int64           `db:"exchange_rate_id"`
+	ExchangeRate   string          `db:"exchange_rate"`
+	PaymentMethod  string          `db:"payment_method"`
+	ReservationID  string          `db:"reservation_id"`
+	PaymentID      string          `db:"payment_id"`
+	Orders         []CheckoutOrder `db:"orders"`
+	FailedStep     StepName        `db:"failed_step"`
+	FailureCode    string          `db:"failure_code"`
+	FailureMessage string          `db:"failure_message"`
+	Attempts       int             `db:"attempts"`
+	Steps          []*CheckoutStep
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// Subtotal is the total of the lines before discount, shipping and any tax
+// added on top.
+func (c *Checkout) Subtotal() int64 {
+	subtotal := c.Amount + c.DiscountTotal - c.ShippingTotal
+	if !c.PricesIncludeTax {
+		subtotal -= c.TaxTotal
+	}
+	return subtotal
+}
+
+// Rate is the exchange rate the checkout was priced with.
+func (c *Checkout) Rate() *ExchangeRate {
+	return &ExchangeRate{
+		ID:           c.ExchangeRateID,
+		BaseCurrency: c.BaseCurrency,
+		Currency:     c.Currency,
+		Rate:         c.ExchangeRate,
+	}
+}
+
+// Step returns the recorded state of the named step, or nil if the saga has
+// not reached it yet.
+func (c *Checkout) Step(name StepName) *CheckoutStep {
+	for _, step := range c.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// Succeeded reports whether the named step has completed and not been
+// compensated.
+func (c *Checkout) Succeeded(name StepName) bool {
+	step := c.Step(name)
+	return step != nil && step.Status == StepSucceeded
+}
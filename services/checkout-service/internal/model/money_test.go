@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    ExchangeRate
+		amount  int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "identity ignores the rate", rate: ExchangeRate{BaseCurrency: "USD", Currency: "USD"}, amount: 1234, want: 1234},
+		{name: "same exponent", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "USD", Rate: "0.000061"}, amount: 100000000, want: 6100},
+		{name: "to a currency without decimals", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "JPY", Rate: "0.0093"}, amount: 100000000, want: 9300},
+		{name: "to a currency with three decimals", rate: ExchangeRate{BaseCurrency: "USD", Currency: "KWD", Rate: "0.307"}, amount: 100, want: 307},
+		{name: "half rounds up", rate: ExchangeRate{BaseCurrency: "USD", Currency: "JPY", Rate: "150"}, amount: 333, want: 500},
+		{name: "negative half rounds away from zero", rate: ExchangeRate{BaseCurrency: "USD", Currency: "JPY", Rate: "150"}, amount: -333, want: -500},
+		{name: "invalid rate", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "-0.9"}, amount: 100, wantErr: true},
+		{name: "overflow", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "2"}, amount: math.MaxInt64, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.Convert(tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert(%d) error = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "abc"}).Convert(1); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Convert() error = %v, want %v", err, ErrInvalidRate)
+	}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+)
+
+var (
+	ErrCheckoutNotFound  = errors.New("checkout not found")
+	ErrDuplicateCheckout = errors.New("checkout with this idempotency key already exists")
+	ErrCheckoutLocked    = errors.New("checkout is being processed by another worker")
+)
+
+// CheckoutRepository persists checkouts and the state of their saga steps.
+// A worker must hold the lease on a checkout (see Claim) before changing it.
+type CheckoutRepository interface {
+	// Create stores a new checkout with its lease held until lockedUntil. It
+	// returns ErrDuplicateCheckout if the customer already used the
+	// idempotency key.
+	Create(ctx context.Context, checkout *model.Checkout, lockedUntil time.Time) error
+	GetByID(ctx context.Context, id string) (*model.Checkout, error)
+	GetByIdempotencyKey(ctx context.Context, customerID int64, key string) (*model.Checkout, error)
+
+	// Claim takes the lease on an unfinished checkout until lockedUntil and
+	// counts the attempt. It returns ErrCheckoutLocked if another worker
+	// holds a lease that has not run out, or the checkout is already final.
+	Claim(ctx context.Context, id string, now, lockedUntil time.Time) (*model.Checkout, error)
+	Release(ctx context.Context, id string) error
+
+	// Save writes the saga progress of checkout: status, the ids returned by
+	// other services and the failure, if any.
+	Save(ctx context.Context, checkout *model.Checkout) error
+	SaveStep(ctx context.Context, checkoutID string, step *model.CheckoutStep) error
+
+	// ListUnfinished returns the ids of up to limit running or compensating
+	// checkouts whose lease has run out, oldest first.
+	ListUnfinished(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const checkoutColumns = `
+	id, customer_id, idempotency_key, status, lines, shipping_address, discount_total, promotions,
+	tax_total, tax_rules_version, prices_include_tax, shipping_total, shipping_option, shipping,
+	amount, currency, base_currency, exchange_rate_id, exchange_rate, payment_method, COALESCE(reservation_id, ''), COALESCE(payment_id, ''), orders,
+	COALESCE(failed_step, ''), COALESCE(failure_code, ''), COALESCE(failure_message, ''),
+	attempts, created_at, updated_at
+`
+
+type checkoutRepository struct {
+	db *sql.DB
+}
+
+func NewCheckoutRepository(db *sql.DB) CheckoutRepository {
+	return &checkoutRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCheckout(row rowScanner) (*model.Checkout, error) {
+	var checkout model.Checkout
+	var lines, address, promotions, shipping, orders []byte
+	err := row.Scan(
+		&checkout.ID,
+		&checkout.CustomerID,
+		&checkout.IdempotencyKey,
+		&checkout.Status,
+		&lines,
+		&address,
+		&checkout.DiscountTotal,
+		&promotions,
+		&checkout.TaxTotal,
+		&checkout.TaxRulesVersion,
+		&checkout.PricesIncludeTax,
+		&checkout.ShippingTotal,
+		&checkout.ShippingOption,
+		&shipping,
+		&checkout.Amount,
+		&checkout.Currency,
+		&checkout.BaseCurrency,
+		&checkout.ExchangeRateID,
+		&checkout.ExchangeRate,
+		&checkout.PaymentMethod,
+		&checkout.ReservationID,
+		&checkout.PaymentID,
+		&orders,
+		&checkout.FailedStep,
+		&checkout.FailureCode,
+		&checkout.FailureMessage,
+		&checkout.Attempts,
+		&checkout.CreatedAt,
+		&checkout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(lines, &checkout.Lines); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(address, &checkout.ShippingAddress); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(promotions, &checkout.Promotions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(shipping, &checkout.Shipping); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(orders, &checkout.Orders); err != nil {
+		return nil, err
+	}
+
+	return &checkout, nil
+}
+
+func (r *checkoutRepository) Create(ctx context.Context, checkout *model.Checkout, lockedUntil time.Time) error {
+	lines, err := json.Marshal(checkout.Lines)
+	if err != nil {
+		return err
+	}
+	address, err := json.Marshal(checkout.ShippingAddress)
+	if err != nil {
+		return err
+	}
+	promotions, err := json.Marshal(checkout.Promotions)
+	if err != nil {
+		return err
+	}
+	shipping, err := json.Marshal(checkout.Shipping)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO checkouts (
+			customer_id, idempotency_key, status, lines, shipping_address, discount_total, promotions,
+			tax_total, tax_rules_version, prices_include_tax, shipping_total, shipping_option, shipping,
+			amount, currency, base_currency, exchange_rate_id, exchange_rate, payment_method, attempts, locked_until
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, 1, $20)
+		RETURNING id, attempts, created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		checkout.CustomerID,
+		checkout.IdempotencyKey,
+		checkout.Status,
+		lines,
+		address,
+		checkout.DiscountTotal,
+		promotions,
+		checkout.TaxTotal,
+		checkout.TaxRulesVersion,
+		checkout.PricesIncludeTax,
+		checkout.ShippingTotal,
+		checkout.ShippingOption,
+		shipping,
+		checkout.Amount,
+		checkout.Currency,
+		checkout.BaseCurrency,
+		checkout.ExchangeRateID,
+		checkout.ExchangeRate,
+		checkout.PaymentMethod,
+		lockedUntil,
+	).Scan(&checkout.ID, &checkout.Attempts, &checkout.CreatedAt, &checkout.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateCheckout
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *checkoutRepository) GetByID(ctx context.Context, id string) (*model.Checkout, error) {
+	return r.get(ctx, `SELECT `+checkoutColumns+` FROM checkouts WHERE id::text = $1`, id)
+}
+
+func (r *checkoutRepository) GetByIdempotencyKey(ctx context.Context, customerID int64, key string) (*model.Checkout, error) {
+	return r.get(ctx,
+		`SELECT `+checkoutColumns+` FROM checkouts WHERE customer_id = $1 AND idempotency_key = $2`,
+		customerID, key,
+	)
+}
+
+func (r *checkoutRepository) get(ctx context.Context, query string, args ...interface{}) (*model.Checkout, error) {
+	checkout, err := scanCheckout(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrCheckoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checkout.Steps, err = r.listSteps(ctx, checkout.ID); err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+func (r *checkoutRepository) Claim(ctx context.Context, id string, now, lockedUntil time.Time) (*model.Checkout, error) {
+	query := `
+		UPDATE checkouts
+		SET locked_until = $3, attempts = attempts + 1
+		WHERE id::text = $1
+			AND status IN ('running', 'compensating')
+			AND (locked_until IS NULL OR locked_until <= $2)
+		RETURNING ` + checkoutColumns
+
+	checkout, err := scanCheckout(r.db.QueryRowContext(ctx, query, id, now, lockedUntil))
+	if err == sql.ErrNoRows {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrCheckoutLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checkout.Steps, err = r.listSteps(ctx, checkout.ID); err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+func (r *checkoutRepository) Release(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE checkouts SET locked_until = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (r *checkoutRepository) Save(ctx context.Context, checkout *model.Checkout) error {
+	orders, err := json.Marshal(checkout.Orders)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE checkouts
+		SET status = $2,
+			reservation_id = NULLIF($3, ''),
+			payment_id = NULLIF($4, ''),
+			orders = $5,
+			failed_step = NULLIF($6, ''),
+			failure_code = NULLIF($7, ''),
+			failure_message = NULLIF($8, ''),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		checkout.ID,
+		checkout.Status,
+		checkout.ReservationID,
+		checkout.PaymentID,
+		orders,
+		checkout.FailedStep,
+		checkout.FailureCode,
+		checkout.FailureMessage,
+	).Scan(&checkout.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrCheckoutNotFound
+	}
+
+	return err
+}
+
+func (r *checkoutRepository) SaveStep(ctx context.Context, checkoutID string, step *model.CheckoutStep) error {
+	query := `
+		INSERT INTO checkout_steps (checkout_id, name, status, attempts, last_error, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())
+		ON CONFLICT (checkout_id, name) DO UPDATE
+		SET status = EXCLUDED.status,
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		checkoutID,
+		step.Name,
+		step.Status,
+		step.Attempts,
+		step.LastError,
+	).Scan(&step.UpdatedAt)
+}
+
+func (r *checkoutRepository) ListUnfinished(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM checkouts
+		WHERE status IN ('running', 'compensating')
+			AND (locked_until IS NULL OR locked_until <= $1)
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *checkoutRepository) listSteps(ctx context.Context, checkoutID string) ([]*model.CheckoutStep, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT name, status, attempts, COALESCE(last_error, ''), updated_at
+		FROM checkout_steps
+		WHERE checkout_id = $1
+	`, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := map[model.StepName]*model.CheckoutStep{}
+	for rows.Next() {
+		var step model.CheckoutStep
+		if err := rows.Scan(&step.Name, &step.Status, &step.Attempts, &step.LastError, &step.UpdatedAt); err != nil {
+			return nil, err
+		}
+		steps[step.Name] = &step
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return steps in saga order rather than by name.
+	var ordered []*model.CheckoutStep
+	for _, name := range model.Steps {
+		if step, ok := steps[name]; ok {
+			ordered = append(ordered, step)
+		}
+	}
+
+	return ordered, nil
+}
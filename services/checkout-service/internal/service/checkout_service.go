@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+)
+
+var (
+	ErrCartEmpty   = errors.New("cart is empty")
+	ErrCartInvalid = errors.New("cart has items that are unavailable or short of stock")
+
+	// ErrPaymentDeclined and ErrPaymentPending are returned by
+	// PaymentGateway.Authorize. A pending payment waits on the customer,
+	// e.g. a 3-D Secure challenge, and is asked about again later.
+	ErrPaymentDeclined = errors.New("payment declined")
+	ErrPaymentPending  = errors.New("payment awaiting customer action")
+
+	// ErrPaymentCaptured and ErrReservationCommitted are returned when a
+	// payment or reservation can no longer be released, as the money or
+	// stock has already changed hands and has to be given back instead.
+	ErrPaymentCaptured      = errors.New("payment was captured")
+	ErrReservationCommitted = errors.New("reservation was committed")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// CartSnapshot is the customer's cart as priced by the cart service when the
+// checkout starts, in the currency of ExchangeRate.
+type CartSnapshot struct {
+	Lines        []model.CheckoutLine
+	Subtotal     int64
+	Valid        bool
+	CouponCodes  []string
+	ExchangeRate *model.ExchangeRate
+}
+
+// Carts reads and empties customer carts.
+type Carts interface {
+	GetCart(ctx context.Context, customerID int64) (*CartSnapshot, error)
+	ClearCart(ctx context.Context, customerID int64) error
+}
+
+// Addresses resolves the customer's saved shipping addresses.
+type Addresses interface {
+	GetAddress(ctx context.Context, customerID int64, id string) (*model.ShippingAddress, error)
+}
+
+// Pricing is the discount the promotions service grants on the checkout
+// lines: per line in LineDiscounts and per promotion in Promotions. Fixed
+// discounts are converted with the rate the lines were priced with.
+type Pricing struct {
+	DiscountTotal int64
+	LineDiscounts map[string]int64
+	Promotions    []model.CheckoutPromotion
+}
+
+// Promotions prices checkouts and counts the promotions used against their
+// limits. Redeem and Release are idempotent on the checkout id; Redeem
+// fails with a conflict when the discount is no longer available.
+type Promotions interface {
+	Evaluate(ctx context.Context, customerID int64, codes []string, lines []model.CheckoutLine, rate *model.ExchangeRate) (*Pricing, error)
+	Redeem(ctx context.Context, checkout *model.Checkout) error
+	Release(ctx context.Context, checkoutID string) error
+}
+
+// TaxQuote is the tax on the checkout lines: per line in LineTaxes, keyed
+// by SKU, and the version of the rules it was calculated with.
+type TaxQuote struct {
+	RulesVersion     string
+	PricesIncludeTax bool
+	TaxTotal         int64
+	LineTaxes        map[string]int64
+}
+
+// Taxes calculates the tax on checkout lines for the shipping address.
+type Taxes interface {
+	Calculate(ctx context.Context, address model.ShippingAddress, lines []model.CheckoutLine) (*TaxQuote, error)
+}
+
+// ShippingQuote is one way to deliver the checkout: a carrier service every
+// seller's parcel can go with, and what each parcel costs in Currency.
+type ShippingQuote struct {
+	Option   string
+	Amount   int64
+	Currency string
+	Charges  []model.ShippingCharge
+}
+
+// Shipping quotes delivery of checkout lines to the shipping address,
+// cheapest option first.
+type Shipping interface {
+	Quote(ctx context.Context, address model.ShippingAddress, lines []model.CheckoutLine) ([]ShippingQuote, error)
+}
+
+// Inventory holds stock for the checkout. Reserve is idempotent on
+// reference. ReleaseReservation fails with ErrReservationCommitted once the
+// stock has gone to the orders; Restock puts it back on hand instead, and
+// is idempotent on reference.
+type Inventory interface {
+	Reserve(ctx context.Context, reference string, lines []model.CheckoutLine) (string, error)
+	CommitReservation(ctx context.Context, reservationID string) error
+	ReleaseReservation(ctx context.Context, reservationID string) error
+	Restock(ctx context.Context, reference, reservationReference string, lines []model.CheckoutLine) error
+}
+
+// PaymentRequest asks for the checkout amount to be held on the customer's
+// payment method. Reference is the idempotency key.
+type PaymentRequest struct {
+	Reference     string
+	CustomerID    int64
+	Amount        int64
+	Currency      string
+	PaymentMethod string
+}
+
+// PaymentGateway authorizes, captures, voids and refunds payments.
+// Authorize is idempotent on the request reference and returns the payment
+// id whenever one exists, even along with ErrPaymentDeclined or
+// ErrPaymentPending. Void releases a payment that was not captured and
+// fails with ErrPaymentCaptured otherwise; Refund gives back all of a
+// captured payment and does nothing to one that never was.
+type PaymentGateway interface {
+	Authorize(ctx context.Context, req *PaymentRequest) (string, error)
+	Capture(ctx context.Context, paymentID string) error
+	Void(ctx context.Context, paymentID string) error
+	Refund(ctx context.Context, paymentID string) error
+}
+
+// Orders creates and cancels the orders of a checkout. CreateOrders is
+// idempotent on the checkout id.
+type Orders interface {
+	CreateOrders(ctx context.Context, checkout *model.Checkout) ([]model.CheckoutOrder, error)
+	MarkPaid(ctx context.Context, orderID string) error
+
+	// CancelCheckoutOrders cancels every order created for checkoutID,
+	// including ones whose creation was never confirmed.
+	CancelCheckoutOrders(ctx context.Context, checkoutID, reason string) error
+}
+
+// CheckoutService runs checkouts as sagas: redeem promotions, reserve stock,
+// authorize payment, create the orders, capture the payment, mark the orders
+// paid, commit the reservation and clear the cart. When a step fails for good
+// the completed steps are compensated in reverse order.
+type CheckoutService interface {
+	// StartCheckout runs the saga for the customer's cart and returns the
+	// checkout as it stands when the run ends. A checkout still running
+	// after a transient failure is finished in the background by RunRecovery.
+	StartCheckout(ctx context.Context, req *dto.StartCheckoutRequest) (*dto.CheckoutResponse, error)
+	GetCheckout(ctx context.Context, id string, customerID int64) (*dto.CheckoutResponse, error)
+
+	// RunRecovery resumes unfinished checkouts whose worker went away until
+	// ctx is cancelled.
+	RunRecovery(ctx context.Context)
+}
@@ -0,0 +1,678 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/repository"
+
+	microerrors "go-micro.dev/v4/errors"
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	maxIdempotencyKeyLength = 100
+	maxShippingOptionLength = 61
+	recoveryBatchSize       = 50
+	cancelReason            = "checkout failed"
+)
+
+type checkoutServiceImpl struct {
+	checkoutRepo     repository.CheckoutRepository
+	carts            Carts
+	addresses        Addresses
+	promotions       Promotions
+	taxes            Taxes
+	shipping         Shipping
+	inventory        Inventory
+	payments         PaymentGateway
+	orders           Orders
+	currency         string
+	leaseDuration    time.Duration
+	maxAttempts      int
+	recoveryInterval time.Duration
+}
+
+func NewCheckoutService(
+	checkoutRepo repository.CheckoutRepository,
+	carts Carts,
+	addresses Addresses,
+	promotions Promotions,
+	taxes Taxes,
+	shipping Shipping,
+	inventory Inventory,
+	payments PaymentGateway,
+	orders Orders,
+	currency string,
+	leaseDuration time.Duration,
+	maxAttempts int,
+	recoveryInterval time.Duration,
+) CheckoutService {
+	return &checkoutServiceImpl{
+		checkoutRepo:     checkoutRepo,
+		carts:            carts,
+		addresses:        addresses,
+		promotions:       promotions,
+		taxes:            taxes,
+		shipping:         shipping,
+		inventory:        inventory,
+		payments:         payments,
+		orders:           orders,
+		currency:         currency,
+		leaseDuration:    leaseDuration,
+		maxAttempts:      maxAttempts,
+		recoveryInterval: recoveryInterval,
+	}
+}
+
+// StartCheckout implements CheckoutService.
+func (s *checkoutServiceImpl) StartCheckout(ctx context.Context, req *dto.StartCheckoutRequest) (*dto.CheckoutResponse, error) {
+	key := strings.TrimSpace(req.IdempotencyKey)
+	addressID := strings.TrimSpace(req.ShippingAddressID)
+	paymentMethod := strings.TrimSpace(req.PaymentMethod)
+	shippingOption := strings.TrimSpace(req.ShippingOption)
+
+	invalid := map[string]string{}
+	if req.CustomerID <= 0 {
+		invalid["customer_id"] = "is required"
+	}
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		invalid["idempotency_key"] = fmt.Sprintf("must be between 1 and %d characters", maxIdempotencyKeyLength)
+	}
+	if addressID == "" {
+		invalid["shipping_address_id"] = "is required"
+	}
+	if paymentMethod == "" {
+		invalid["payment_method"] = "is required"
+	}
+	if len(shippingOption) > maxShippingOptionLength {
+		invalid["shipping_option"] = fmt.Sprintf("must be at most %d characters", maxShippingOptionLength)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	existing, err := s.checkoutRepo.GetByIdempotencyKey(ctx, req.CustomerID, key)
+	if err == nil {
+		return s.resume(ctx, existing.ID)
+	}
+	if !errors.Is(err, repository.ErrCheckoutNotFound) {
+		return nil, err
+	}
+
+	cart, err := s.carts.GetCart(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Lines) == 0 {
+		return nil, ErrCartEmpty
+	}
+	if !cart.Valid {
+		return nil, ErrCartInvalid
+	}
+	rate := cart.ExchangeRate
+	if rate == nil {
+		rate = &model.ExchangeRate{BaseCurrency: s.currency, Currency: s.currency, Rate: "1"}
+	}
+	if rate.BaseCurrency != s.currency {
+		return nil, fmt.Errorf("cart is priced from %s, checkout expects %s", rate.BaseCurrency, s.currency)
+	}
+
+	address, err := s.addresses.GetAddress(ctx, req.CustomerID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Coupons that no longer apply are dropped rather than failing the
+	// checkout; the customer pays for what the pricing grants.
+	pricing, err := s.promotions.Evaluate(ctx, req.CustomerID, cart.CouponCodes, cart.Lines, rate)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cart.Lines {
+		cart.Lines[i].Discount = pricing.LineDiscounts[cart.Lines[i].SKU]
+	}
+
+	// Tax is charged on the discounted lines. The rules version is kept so
+	// the orders are taxed the same way when they are created.
+	tax, err := s.taxes.Calculate(ctx, *address, cart.Lines)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cart.Lines {
+		cart.Lines[i].Tax = tax.LineTaxes[cart.Lines[i].SKU]
+	}
+
+	quotes, err := s.shipping.Quote(ctx, *address, cart.Lines)
+	if err != nil {
+		return nil, err
+	}
+	shipping, err := chooseShipping(quotes, shippingOption)
+	if err != nil {
+		return nil, err
+	}
+	shipping, err = convertShipping(shipping, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := cart.Subtotal - pricing.DiscountTotal + shipping.Amount
+	if !tax.PricesIncludeTax {
+		amount += tax.TaxTotal
+	}
+
+	checkout := &model.Checkout{
+		CustomerID:       req.CustomerID,
+		IdempotencyKey:   key,
+		Status:           model.CheckoutRunning,
+		Lines:            cart.Lines,
+		ShippingAddress:  *address,
+		DiscountTotal:    pricing.DiscountTotal,
+		Promotions:       pricing.Promotions,
+		TaxTotal:         tax.TaxTotal,
+		TaxRulesVersion:  tax.RulesVersion,
+		PricesIncludeTax: tax.PricesIncludeTax,
+		ShippingTotal:    shipping.Amount,
+		ShippingOption:   shipping.Option,
+		Shipping:         shipping.Charges,
+		Amount:           amount,
+		Currency:         rate.Currency,
+		BaseCurrency:     rate.BaseCurrency,
+		ExchangeRateID:   rate.ID,
+		ExchangeRate:     rate.Rate,
+		PaymentMethod:    paymentMethod,
+	}
+
+	err = s.checkoutRepo.Create(ctx, checkout, time.Now().UTC().Add(s.leaseDuration))
+	if errors.Is(err, repository.ErrDuplicateCheckout) {
+		// A concurrent request with the same key won the race.
+		existing, err := s.checkoutRepo.GetByIdempotencyKey(ctx, req.CustomerID, key)
+		if err != nil {
+			return nil, err
+		}
+		return toCheckoutResponse(existing), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.drive(checkout)
+
+	return toCheckoutResponse(checkout), nil
+}
+
+// chooseShipping picks the quote for option, or the cheapest one when no
+// option is given.
+func chooseShipping(quotes []ShippingQuote, option string) (*ShippingQuote, error) {
+	if len(quotes) == 0 {
+		return nil, &ValidationError{Fields: map[string]string{
+			"shipping_address_id": "no carrier delivers this cart to the address",
+		}}
+	}
+	if option == "" {
+		return &quotes[0], nil
+	}
+
+	for i := range quotes {
+		if quotes[i].Option == option {
+			return &quotes[i], nil
+		}
+	}
+	return nil, &ValidationError{Fields: map[string]string{
+		"shipping_option": "is not available for this cart and address",
+	}}
+}
+
+// convertShipping prices a quote, which carriers rate in the base currency,
+// in the currency of the cart.
+func convertShipping(quote *ShippingQuote, rate *model.ExchangeRate) (*ShippingQuote, error) {
+	if quote.Currency != "" && quote.Currency != rate.BaseCurrency {
+		return nil, fmt.Errorf("shipping is quoted in %s, expected %s", quote.Currency, rate.BaseCurrency)
+	}
+	if rate.IsIdentity() {
+		return quote, nil
+	}
+
+	converted := &ShippingQuote{
+		Option:   quote.Option,
+		Currency: rate.Currency,
+		Charges:  make([]model.ShippingCharge, 0, len(quote.Charges)),
+	}
+
+	// Charges are converted one by one and the total made up from them, so
+	// the orders add up to what the checkout charges.
+	for _, charge := range quote.Charges {
+		var err error
+		charge.Amount, err = rate.Convert(charge.Amount)
+		if err != nil {
+			return nil, err
+		}
+		converted.Amount += charge.Amount
+		converted.Charges = append(converted.Charges, charge)
+	}
+
+	return converted, nil
+}
+
+// GetCheckout implements CheckoutService.
+func (s *checkoutServiceImpl) GetCheckout(ctx context.Context, id string, customerID int64) (*dto.CheckoutResponse, error) {
+	checkout, err := s.checkoutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if customerID != 0 && checkout.CustomerID != customerID {
+		return nil, repository.ErrCheckoutNotFound
+	}
+
+	return toCheckoutResponse(checkout), nil
+}
+
+// RunRecovery implements CheckoutService.
+func (s *checkoutServiceImpl) RunRecovery(ctx context.Context) {
+	ticker := time.NewTicker(s.recoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ids, err := s.checkoutRepo.ListUnfinished(ctx, time.Now().UTC(), recoveryBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to list unfinished checkouts: %v", err)
+			}
+			continue
+		}
+
+		for _, id := range ids {
+			if _, err := s.resume(ctx, id); err != nil && ctx.Err() == nil {
+				logger.Errorf("Failed to resume checkout %s: %v", id, err)
+			}
+		}
+	}
+}
+
+// resume takes over checkout id if no other worker holds it and drives it
+// as far as it will go. A final or busy checkout is returned as it is.
+func (s *checkoutServiceImpl) resume(ctx context.Context, id string) (*dto.CheckoutResponse, error) {
+	now := time.Now().UTC()
+
+	checkout, err := s.checkoutRepo.Claim(ctx, id, now, now.Add(s.leaseDuration))
+	if errors.Is(err, repository.ErrCheckoutLocked) {
+		return s.GetCheckout(ctx, id, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.drive(checkout)
+
+	return toCheckoutResponse(checkout), nil
+}
+
+// drive runs the saga of a checkout whose lease is held by the caller and
+// releases the lease when it stops. It uses its own context bounded by the
+// lease so an abandoned client request cannot stop the saga halfway.
+func (s *checkoutServiceImpl) drive(checkout *model.Checkout) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.leaseDuration)
+	defer cancel()
+
+	if checkout.Status == model.CheckoutRunning {
+		s.runForward(ctx, checkout)
+	}
+	if checkout.Status == model.CheckoutCompensating {
+		s.compensate(ctx, checkout)
+	}
+
+	if err := s.checkoutRepo.Release(context.Background(), checkout.ID); err != nil {
+		logger.Errorf("Failed to release checkout %s: %v", checkout.ID, err)
+	}
+}
+
+// runForward executes the remaining steps in order. A transient error leaves
+// the checkout running so recovery retries it; a rejection, or a step that
+// keeps failing, switches it to compensating.
+func (s *checkoutServiceImpl) runForward(ctx context.Context, checkout *model.Checkout) {
+	for _, name := range model.Steps {
+		if checkout.Succeeded(name) {
+			continue
+		}
+
+		step := s.startStep(ctx, checkout, name)
+		if step == nil {
+			return
+		}
+
+		err := s.execute(ctx, checkout, name)
+		if err == nil {
+			step.Status = model.StepSucceeded
+			step.LastError = ""
+			if !s.save(ctx, checkout, step) {
+				return
+			}
+			continue
+		}
+
+		step.LastError = err.Error()
+		exhausted := step.Attempts >= s.maxAttempts
+
+		if name == model.StepClearCart {
+			// Past the point of no return: the orders stand even if the cart
+			// cannot be emptied.
+			if !exhausted && !isPermanent(err) {
+				s.save(ctx, checkout, step)
+				return
+			}
+			logger.Warnf("Giving up clearing cart of customer %d after checkout %s: %v", checkout.CustomerID, checkout.ID, err)
+			step.Status = model.StepFailed
+			if !s.save(ctx, checkout, step) {
+				return
+			}
+			continue
+		}
+
+		if !exhausted && !isPermanent(err) {
+			logger.Warnf("Checkout %s step %s failed, will retry: %v", checkout.ID, name, err)
+			s.save(ctx, checkout, step)
+			return
+		}
+
+		step.Status = model.StepFailed
+		checkout.Status = model.CheckoutCompensating
+		checkout.FailedStep = name
+		checkout.FailureCode = failureCode(name, err, exhausted)
+		checkout.FailureMessage = microerrors.FromError(err).Detail
+		s.save(ctx, checkout, step)
+		return
+	}
+
+	checkout.Status = model.CheckoutCompleted
+	s.save(ctx, checkout, nil)
+}
+
+// execute performs one forward step. Every step is safe to repeat: the
+// checkout id is the idempotency key for all calls that create something.
+func (s *checkoutServiceImpl) execute(ctx context.Context, checkout *model.Checkout, name model.StepName) error {
+	switch name {
+	case model.StepRedeemPromotions:
+		if len(checkout.Promotions) == 0 {
+			return nil
+		}
+		return s.promotions.Redeem(ctx, checkout)
+
+	case model.StepReserveStock:
+		reservationID, err := s.inventory.Reserve(ctx, checkout.ID, checkout.Lines)
+		if err != nil {
+			return err
+		}
+		checkout.ReservationID = reservationID
+
+	case model.StepAuthorizePayment:
+		paymentID, err := s.payments.Authorize(ctx, &PaymentRequest{
+			Reference:     checkout.ID,
+			CustomerID:    checkout.CustomerID,
+			Amount:        checkout.Amount,
+			Currency:      checkout.Currency,
+			PaymentMethod: checkout.PaymentMethod,
+		})
+		if paymentID != "" {
+			checkout.PaymentID = paymentID
+		}
+		if err != nil {
+			return err
+		}
+
+	case model.StepCreateOrder:
+		orders, err := s.orders.CreateOrders(ctx, checkout)
+		if err != nil {
+			return err
+		}
+		checkout.Orders = orders
+
+	case model.StepCapturePayment:
+		return s.payments.Capture(ctx, checkout.PaymentID)
+
+	case model.StepMarkPaid:
+		for _, order := range checkout.Orders {
+			if err := s.orders.MarkPaid(ctx, order.ID); err != nil {
+				return err
+			}
+		}
+
+	case model.StepCommitReservation:
+		// The stock now belongs to the orders; this fails if the
+		// reservation ran out while payment was pending.
+		return s.inventory.CommitReservation(ctx, checkout.ReservationID)
+
+	case model.StepClearCart:
+		return s.carts.ClearCart(ctx, checkout.CustomerID)
+	}
+
+	return nil
+}
+
+// compensate undoes the steps that took effect, newest first. Compensations
+// are retried until they succeed; only then is the checkout marked failed.
+func (s *checkoutServiceImpl) compensate(ctx context.Context, checkout *model.Checkout) {
+	for i := len(model.Steps) - 1; i >= 0; i-- {
+		name := model.Steps[i]
+		step := checkout.Step(name)
+		if step == nil || step.Status == model.StepPending || step.Status == model.StepCompensated {
+			continue
+		}
+
+		var err error
+		switch name {
+		case model.StepCommitReservation:
+			// Committed stock is put back on hand. A commit that failed
+			// may still have gone through, which releasing finds out.
+			committed := step.Status == model.StepSucceeded
+			if !committed {
+				err = s.inventory.ReleaseReservation(ctx, checkout.ReservationID)
+				committed = errors.Is(err, ErrReservationCommitted)
+			}
+			if committed {
+				err = s.inventory.Restock(ctx, checkout.ID, checkout.ID, checkout.Lines)
+			}
+		case model.StepCapturePayment:
+			// A capture that failed may still have gone through; Refund
+			// does nothing to a payment that was never captured.
+			err = s.payments.Refund(ctx, checkout.PaymentID)
+		case model.StepMarkPaid:
+			// Undone by cancelling the orders under create_order.
+		case model.StepCreateOrder:
+			// Orders may exist even if the step failed.
+			err = s.orders.CancelCheckoutOrders(ctx, checkout.ID, cancelReason)
+		case model.StepAuthorizePayment:
+			// A payment still waiting on a challenge is voided as well, so a
+			// late approval cannot leave money held.
+			if checkout.PaymentID != "" {
+				err = s.payments.Void(ctx, checkout.PaymentID)
+			}
+		case model.StepReserveStock:
+			// Once commit_reservation has run, it has dealt with the
+			// reservation.
+			if step.Status == model.StepSucceeded && checkout.Step(model.StepCommitReservation) == nil {
+				err = s.inventory.ReleaseReservation(ctx, checkout.ReservationID)
+			}
+		case model.StepRedeemPromotions:
+			// Redemptions never lapse, so an uncertain one is released too.
+			if len(checkout.Promotions) > 0 {
+				err = s.promotions.Release(ctx, checkout.ID)
+			}
+		default:
+			continue
+		}
+
+		// A step that failed without taking effect needs no undoing; an
+		// uncertain authorization or reservation lapses on its own.
+		if err != nil {
+			logger.Errorf("Failed to compensate checkout %s step %s: %v", checkout.ID, name, err)
+			step.LastError = err.Error()
+			s.save(ctx, checkout, step)
+			return
+		}
+
+		step.Status = model.StepCompensated
+		if !s.save(ctx, checkout, step) {
+			return
+		}
+	}
+
+	checkout.Status = model.CheckoutFailed
+	s.save(ctx, checkout, nil)
+}
+
+// startStep records an attempt at the named step before it runs, so a crash
+// mid-step is visible to recovery. It returns nil if the state could not be
+// saved.
+func (s *checkoutServiceImpl) startStep(ctx context.Context, checkout *model.Checkout, name model.StepName) *model.CheckoutStep {
+	step := checkout.Step(name)
+	if step == nil {
+		step = &model.CheckoutStep{Name: name, Status: model.StepPending}
+		checkout.Steps = append(checkout.Steps, step)
+	}
+	step.Attempts++
+
+	if err := s.checkoutRepo.SaveStep(ctx, checkout.ID, step); err != nil {
+		logger.Errorf("Failed to save checkout %s step %s: %v", checkout.ID, name, err)
+		return nil
+	}
+
+	return step
+}
+
+// save persists the checkout and, if given, one of its steps. Failures are
+// logged and reported as false; the lease then runs out and recovery picks
+// the checkout up from the last saved state.
+func (s *checkoutServiceImpl) save(ctx context.Context, checkout *model.Checkout, step *model.CheckoutStep) bool {
+	if step != nil {
+		if err := s.checkoutRepo.SaveStep(ctx, checkout.ID, step); err != nil {
+			logger.Errorf("Failed to save checkout %s step %s: %v", checkout.ID, step.Name, err)
+			return false
+		}
+	}
+
+	if err := s.checkoutRepo.Save(ctx, checkout); err != nil {
+		logger.Errorf("Failed to save checkout %s: %v", checkout.ID, err)
+		return false
+	}
+
+	return true
+}
+
+// isPermanent reports whether err is a rejection that retrying cannot fix,
+// as opposed to a timeout or an unreachable service.
+func isPermanent(err error) bool {
+	switch {
+	case errors.Is(err, ErrPaymentDeclined):
+		return true
+	case errors.Is(err, ErrPaymentPending):
+		return false
+	}
+
+	code := microerrors.FromError(err).Code
+	if code == http.StatusRequestTimeout || code == http.StatusTooManyRequests {
+		return false
+	}
+	return code >= 400 && code < 500
+}
+
+func failureCode(name model.StepName, err error, exhausted bool) string {
+	if errors.Is(err, ErrPaymentPending) {
+		return "payment_not_completed"
+	}
+	if exhausted && !isPermanent(err) {
+		return "service_unavailable"
+	}
+
+	code := microerrors.FromError(err).Code
+	switch name {
+	case model.StepRedeemPromotions:
+		if code == http.StatusConflict {
+			return "promotion_unavailable"
+		}
+		return "promotion_rejected"
+	case model.StepReserveStock:
+		if code == http.StatusConflict {
+			return "insufficient_stock"
+		}
+		return "stock_rejected"
+	case model.StepAuthorizePayment:
+		if errors.Is(err, ErrPaymentDeclined) {
+			return "payment_declined"
+		}
+		return "payment_rejected"
+	case model.StepCreateOrder, model.StepMarkPaid:
+		return "order_rejected"
+	case model.StepCapturePayment:
+		return "payment_rejected"
+	case model.StepCommitReservation:
+		if code == http.StatusPreconditionFailed {
+			return "reservation_expired"
+		}
+		return "stock_rejected"
+	default:
+		return "checkout_failed"
+	}
+}
+
+func toCheckoutResponse(checkout *model.Checkout) *dto.CheckoutResponse {
+	resp := &dto.CheckoutResponse{
+		ID:               checkout.ID,
+		CustomerID:       checkout.CustomerID,
+		Status:           string(checkout.Status),
+		Subtotal:         checkout.Subtotal(),
+		Discount:         checkout.DiscountTotal,
+		Tax:              checkout.TaxTotal,
+		PricesIncludeTax: checkout.PricesIncludeTax,
+		Shipping:         checkout.ShippingTotal,
+		ShippingOption:   checkout.ShippingOption,
+		Amount:           checkout.Amount,
+		Currency:         checkout.Currency,
+		BaseCurrency:     checkout.BaseCurrency,
+		ExchangeRate:     checkout.ExchangeRate,
+		ExchangeRateID:   checkout.ExchangeRateID,
+		Orders:           make([]*dto.CheckoutOrderResponse, 0, len(checkout.Orders)),
+		Steps:            make([]*dto.CheckoutStepResponse, 0, len(checkout.Steps)),
+		CreatedAt:        checkout.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        checkout.UpdatedAt.Format(time.RFC3339),
+	}
+
+	for _, order := range checkout.Orders {
+		resp.Orders = append(resp.Orders, &dto.CheckoutOrderResponse{
+			ID:          order.ID,
+			OrderNumber: order.OrderNumber,
+			SellerID:    order.SellerID,
+			Total:       order.Total,
+		})
+	}
+
+	for _, step := range checkout.Steps {
+		resp.Steps = append(resp.Steps, &dto.CheckoutStepResponse{
+			Name:     string(step.Name),
+			Status:   string(step.Status),
+			Attempts: step.Attempts,
+		})
+	}
+
+	if checkout.FailureCode != "" {
+		resp.Failure = &dto.CheckoutFailureResponse{
+			Step:    string(checkout.FailedStep),
+			Code:    checkout.FailureCode,
+			Message: checkout.FailureMessage,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/checkout-service/internal/model"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+type fakeCheckoutRepo struct{}
+
+func (fakeCheckoutRepo) Create(ctx context.Context, checkout *model.Checkout, lockedUntil time.Time) error {
+	return nil
+}
+
+func (fakeCheckoutRepo) GetByID(ctx context.Context, id string) (*model.Checkout, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (fakeCheckoutRepo) GetByIdempotencyKey(ctx context.Context, customerID int64, key string) (*model.Checkout, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (fakeCheckoutRepo) Claim(ctx context.Context, id string, now, lockedUntil time.Time) (*model.Checkout, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (fakeCheckoutRepo) Release(ctx context.Context, id string) error { return nil }
+
+func (fakeCheckoutRepo) Save(ctx context.Context, checkout *model.Checkout) error { return nil }
+
+func (fakeCheckoutRepo) SaveStep(ctx context.Context, checkoutID string, step *model.CheckoutStep) error {
+	return nil
+}
+
+func (fakeCheckoutRepo) ListUnfinished(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	return nil, nil
+}
+
+// fakeSaga stands in for every service the saga calls and keeps the state
+// they would be left in. Calls named in fail return that error; those also
+// named in lost take effect first, as when the reply is lost.
+type fakeSaga struct {
+	fail map[string]error
+	lost map[string]bool
+
+	redeemed    bool
+	reservation string
+	restocked   bool
+	payment     string
+	orders      map[string]string
+	cartCleared bool
+}
+
+func (f *fakeSaga) outcome(call string) (bool, error) {
+	err := f.fail[call]
+	return err == nil || f.lost[call], err
+}
+
+func (f *fakeSaga) GetCart(ctx context.Context, customerID int64) (*CartSnapshot, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeSaga) ClearCart(ctx context.Context, customerID int64) error {
+	apply, err := f.outcome("ClearCart")
+	if apply {
+		f.cartCleared = true
+	}
+	return err
+}
+
+func (f *fakeSaga) Evaluate(ctx context.Context, customerID int64, codes []string, lines []model.CheckoutLine, rate *model.ExchangeRate) (*Pricing, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeSaga) Redeem(ctx context.Context, checkout *model.Checkout) error {
+	apply, err := f.outcome("Redeem")
+	if apply {
+		f.redeemed = true
+	}
+	return err
+}
+
+func (f *fakeSaga) Release(ctx context.Context, checkoutID string) error {
+	f.redeemed = false
+	return nil
+}
+
+func (f *fakeSaga) Reserve(ctx context.Context, reference string, lines []model.CheckoutLine) (string, error) {
+	apply, err := f.outcome("Reserve")
+	if apply {
+		f.reservation = "active"
+	}
+	if err != nil {
+		return "", err
+	}
+	return "r1", nil
+}
+
+func (f *fakeSaga) CommitReservation(ctx context.Context, reservationID string) error {
+	apply, err := f.outcome("CommitReservation")
+	if apply {
+		f.reservation = "committed"
+	}
+	return err
+}
+
+func (f *fakeSaga) ReleaseReservation(ctx context.Context, reservationID string) error {
+	if f.reservation == "committed" {
+		return ErrReservationCommitted
+	}
+	f.reservation = "released"
+	return nil
+}
+
+func (f *fakeSaga) Restock(ctx context.Context, reference, reservationReference string, lines []model.CheckoutLine) error {
+	if f.reservation != "committed" {
+		return microerrors.New("inventory", "reservation was not used", http.StatusPreconditionFailed)
+	}
+	f.restocked = true
+	return nil
+}
+
+func (f *fakeSaga) Authorize(ctx context.Context, req *PaymentRequest) (string, error) {
+	apply, err := f.outcome("Authorize")
+	if apply {
+		f.payment = "authorized"
+	}
+	return "p1", err
+}
+
+func (f *fakeSaga) Capture(ctx context.Context, paymentID string) error {
+	apply, err := f.outcome("Capture")
+	if apply {
+		f.payment = "captured"
+	}
+	return err
+}
+
+func (f *fakeSaga) Void(ctx context.Context, paymentID string) error {
+	switch f.payment {
+	case "captured":
+		return ErrPaymentCaptured
+	case "authorized":
+		f.payment = "voided"
+	}
+	return nil
+}
+
+func (f *fakeSaga) Refund(ctx context.Context, paymentID string) error {
+	if f.payment == "captured" {
+		f.payment = "refunded"
+	}
+	return nil
+}
+
+func (f *fakeSaga) CreateOrders(ctx context.Context, checkout *model.Checkout) ([]model.CheckoutOrder, error) {
+	apply, err := f.outcome("CreateOrders")
+	if apply {
+		f.orders = map[string]string{"o1": "pending", "o2": "pending"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []model.CheckoutOrder{{ID: "o1", SellerID: 1}, {ID: "o2", SellerID: 2}}, nil
+}
+
+func (f *fakeSaga) MarkPaid(ctx context.Context, orderID string) error {
+	apply, err := f.outcome("MarkPaid")
+	if apply {
+		f.orders[orderID] = "paid"
+	}
+	return err
+}
+
+func (f *fakeSaga) CancelCheckoutOrders(ctx context.Context, checkoutID, reason string) error {
+	for id := range f.orders {
+		f.orders[id] = "cancelled"
+	}
+	return nil
+}
+
+func TestCheckoutSaga(t *testing.T) {
+	rejected := microerrors.Conflict("test", "rejected")
+	expired := microerrors.New("inventory", "reservation has expired", http.StatusPreconditionFailed)
+
+	tests := []struct {
+		name        string
+		fail        map[string]error
+		lost        map[string]bool
+		wantStatus  model.CheckoutStatus
+		wantStep    model.StepName
+		wantCode    string
+		wantPayment string
+		wantStock   string
+		wantRestock bool
+		wantOrders  string
+	}{
+		{
+			name:        "completes",
+			wantStatus:  model.CheckoutCompleted,
+			wantPayment: "captured",
+			wantStock:   "committed",
+			wantOrders:  "paid",
+		},
+		{
+			name:        "clear cart fails after the point of no return",
+			fail:        map[string]error{"ClearCart": rejected},
+			wantStatus:  model.CheckoutCompleted,
+			wantPayment: "captured",
+			wantStock:   "committed",
+			wantOrders:  "paid",
+		},
+		{
+			name:       "redeem promotions fails",
+			fail:       map[string]error{"Redeem": rejected},
+			wantStatus: model.CheckoutFailed,
+			wantStep:   model.StepRedeemPromotions,
+			wantCode:   "promotion_unavailable",
+		},
+		{
+			name:       "reserve stock fails",
+			fail:       map[string]error{"Reserve": rejected},
+			wantStatus: model.CheckoutFailed,
+			wantStep:   model.StepReserveStock,
+			wantCode:   "insufficient_stock",
+		},
+		{
+			name:       "authorize payment is declined",
+			fail:       map[string]error{"Authorize": fmt.Errorf("%w: insufficient_funds", ErrPaymentDeclined)},
+			wantStatus: model.CheckoutFailed,
+			wantStep:   model.StepAuthorizePayment,
+			wantCode:   "payment_declined",
+			wantStock:  "released",
+		},
+		{
+			name:        "create order fails",
+			fail:        map[string]error{"CreateOrders": rejected},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepCreateOrder,
+			wantCode:    "order_rejected",
+			wantPayment: "voided",
+			wantStock:   "released",
+		},
+		{
+			name:        "capture payment fails",
+			fail:        map[string]error{"Capture": rejected},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepCapturePayment,
+			wantCode:    "payment_rejected",
+			wantPayment: "voided",
+			wantStock:   "released",
+			wantOrders:  "cancelled",
+		},
+		{
+			name:        "capture payment goes through but its reply is lost",
+			fail:        map[string]error{"Capture": rejected},
+			lost:        map[string]bool{"Capture": true},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepCapturePayment,
+			wantCode:    "payment_rejected",
+			wantPayment: "refunded",
+			wantStock:   "released",
+			wantOrders:  "cancelled",
+		},
+		{
+			name:        "mark paid fails",
+			fail:        map[string]error{"MarkPaid": rejected},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepMarkPaid,
+			wantCode:    "order_rejected",
+			wantPayment: "refunded",
+			wantStock:   "released",
+			wantOrders:  "cancelled",
+		},
+		{
+			name:        "commit reservation finds it expired",
+			fail:        map[string]error{"CommitReservation": expired},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepCommitReservation,
+			wantCode:    "reservation_expired",
+			wantPayment: "refunded",
+			wantStock:   "released",
+			wantOrders:  "cancelled",
+		},
+		{
+			name:        "commit reservation goes through but its reply is lost",
+			fail:        map[string]error{"CommitReservation": rejected},
+			lost:        map[string]bool{"CommitReservation": true},
+			wantStatus:  model.CheckoutFailed,
+			wantStep:    model.StepCommitReservation,
+			wantCode:    "stock_rejected",
+			wantPayment: "refunded",
+			wantStock:   "committed",
+			wantRestock: true,
+			wantOrders:  "cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga := &fakeSaga{fail: tt.fail, lost: tt.lost}
+			svc := &checkoutServiceImpl{
+				checkoutRepo:  fakeCheckoutRepo{},
+				carts:         saga,
+				promotions:    saga,
+				inventory:     saga,
+				payments:      saga,
+				orders:        saga,
+				leaseDuration: time.Minute,
+				maxAttempts:   3,
+			}
+			checkout := &model.Checkout{
+				ID:         "c1",
+				CustomerID: 10,
+				Status:     model.CheckoutRunning,
+				Lines:      []model.CheckoutLine{{SKU: "SKU-1", Quantity: 2, UnitPrice: 500}},
+				Promotions: []model.CheckoutPromotion{{PromotionID: "promo", Discount: 100}},
+				Amount:     900,
+			}
+
+			svc.drive(checkout)
+
+			if checkout.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", checkout.Status, tt.wantStatus)
+			}
+			if checkout.FailedStep != tt.wantStep || checkout.FailureCode != tt.wantCode {
+				t.Errorf("failure = %s %s, want %s %s", checkout.FailedStep, checkout.FailureCode, tt.wantStep, tt.wantCode)
+			}
+			if want := tt.wantStatus == model.CheckoutCompleted; saga.redeemed != want {
+				t.Errorf("redeemed = %v, want %v", saga.redeemed, want)
+			}
+			if saga.payment != tt.wantPayment {
+				t.Errorf("payment = %q, want %q", saga.payment, tt.wantPayment)
+			}
+			if saga.reservation != tt.wantStock || saga.restocked != tt.wantRestock {
+				t.Errorf("reservation = %q restocked %v, want %q restocked %v", saga.reservation, saga.restocked, tt.wantStock, tt.wantRestock)
+			}
+			for id, status := range saga.orders {
+				if status != tt.wantOrders {
+					t.Errorf("order %s = %q, want %q", id, status, tt.wantOrders)
+				}
+			}
+			if tt.wantStatus == model.CheckoutFailed {
+				for _, step := range checkout.Steps {
+					if step.Status == model.StepSucceeded {
+						t.Errorf("step %s was not compensated", step.Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConvertShipping(t *testing.T) {
+	quote := &ShippingQuote{
+		Option:   "standard",
+		Amount:   666,
+		Currency: "USD",
+		Charges: []model.ShippingCharge{
+			{SellerID: 1, Option: "standard", Amount: 333},
+			{SellerID: 2, Option: "standard", Amount: 333},
+		},
+	}
+
+	converted, err := convertShipping(quote, &model.ExchangeRate{ID: 4, BaseCurrency: "USD", Currency: "JPY", Rate: "150"})
+	if err != nil {
+		t.Fatalf("convertShipping() error = %v", err)
+	}
+	// Each charge rounds 499.5 up, and the total is their sum rather than
+	// 666 converted on its own.
+	if converted.Amount != 1000 || converted.Currency != "JPY" || converted.Charges[0].Amount != 500 || converted.Charges[1].Amount != 500 {
+		t.Errorf("convertShipping() = %+v, want two charges of 500 JPY adding up to 1000", converted)
+	}
+	if quote.Charges[0].Amount != 333 {
+		t.Errorf("convertShipping() changed the quote it was given")
+	}
+
+	same, err := convertShipping(quote, &model.ExchangeRate{BaseCurrency: "USD", Currency: "USD", Rate: "1"})
+	if err != nil || same != quote {
+		t.Errorf("convertShipping() in the base currency = %+v, %v, want the quote unchanged", same, err)
+	}
+
+	if _, err := convertShipping(quote, &model.ExchangeRate{BaseCurrency: "IDR", Currency: "JPY", Rate: "0.0093"}); err == nil {
+		t.Error("convertShipping() of a quote in another base currency error = nil")
+	}
+}
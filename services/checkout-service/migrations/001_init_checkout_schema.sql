@@ -0,0 +1,38 @@
+-- A checkout is a saga over the cart, inventory, payment and order services.
+-- Every step is recorded so a crashed run can be resumed or compensated.
+CREATE TABLE checkouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id BIGINT NOT NULL,
+    idempotency_key VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- running, compensating, completed, failed
+    lines JSONB NOT NULL,                          -- cart snapshot taken when the checkout started
+    shipping_address JSONB NOT NULL,
+    amount BIGINT NOT NULL,                        -- in minor units
+    currency CHAR(3) NOT NULL,
+    reservation_id VARCHAR(100),
+    payment_id VARCHAR(100),
+    orders JSONB NOT NULL DEFAULT '[]',
+    failed_step VARCHAR(30),
+    failure_code VARCHAR(50),
+    failure_message TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,                        -- lease held by the worker driving the saga
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT checkouts_customer_idempotency_key UNIQUE (customer_id, idempotency_key),
+    CONSTRAINT checkouts_status_check CHECK (status IN ('running', 'compensating', 'completed', 'failed'))
+);
+
+CREATE TABLE checkout_steps (
+    checkout_id UUID NOT NULL REFERENCES checkouts(id) ON DELETE CASCADE,
+    name VARCHAR(30) NOT NULL,     -- reserve_stock, authorize_payment, create_order, clear_cart
+    status VARCHAR(20) NOT NULL,   -- pending, succeeded, failed, compensated
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (checkout_id, name)
+);
+
+-- Indexes
+CREATE INDEX idx_checkouts_customer_id ON checkouts(customer_id);
+CREATE INDEX idx_checkouts_unfinished ON checkouts(locked_until) WHERE status IN ('running', 'compensating');
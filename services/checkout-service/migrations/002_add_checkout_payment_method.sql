@@ -0,0 +1,4 @@
+-- Checkouts are now paid through the payment service, which needs the
+-- customer's payment method token.
+ALTER TABLE checkouts ADD COLUMN payment_method VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE checkouts ALTER COLUMN payment_method DROP DEFAULT;
@@ -0,0 +1,4 @@
+-- Promotions are priced when the checkout starts and redeemed as the first
+-- step of the saga; the amount charged is the subtotal less discount_total.
+ALTER TABLE checkouts ADD COLUMN discount_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE checkouts ADD COLUMN promotions JSONB NOT NULL DEFAULT '[]';
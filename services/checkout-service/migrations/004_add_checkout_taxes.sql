@@ -0,0 +1,6 @@
+-- Tax is calculated when the checkout starts. It is added to the amount
+-- charged unless prices_include_tax, and the rules version is passed on so
+-- the orders are taxed with the same rules.
+ALTER TABLE checkouts ADD COLUMN tax_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE checkouts ADD COLUMN tax_rules_version VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE checkouts ADD COLUMN prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
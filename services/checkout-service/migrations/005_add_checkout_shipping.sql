@@ -0,0 +1,5 @@
+-- Shipping is quoted when the checkout starts and added to the amount. The
+-- charge of every seller's parcel is kept so each order carries its own.
+ALTER TABLE checkouts ADD COLUMN shipping_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE checkouts ADD COLUMN shipping_option VARCHAR(61) NOT NULL DEFAULT '';
+ALTER TABLE checkouts ADD COLUMN shipping JSONB NOT NULL DEFAULT '[]';
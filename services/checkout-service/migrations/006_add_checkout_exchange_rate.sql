@@ -0,0 +1,8 @@
+-- A checkout is charged in the cart's currency. Prices are set in the base
+-- currency and converted with the rate the cart was priced with, which is
+-- kept so the orders are created with the same one.
+ALTER TABLE checkouts ADD COLUMN base_currency CHAR(3);
+UPDATE checkouts SET base_currency = currency;
+ALTER TABLE checkouts ALTER COLUMN base_currency SET NOT NULL;
+ALTER TABLE checkouts ADD COLUMN exchange_rate_id BIGINT NOT NULL DEFAULT 0; -- 0 when currency is base_currency
+ALTER TABLE checkouts ADD COLUMN exchange_rate VARCHAR(25) NOT NULL DEFAULT '1';
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/postal"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/customer-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	postalRules, err := postal.LoadRules(cfg.PostalRulesPath)
+	if err != nil {
+		logger.Fatal("Failed to load postal code rules: ", err)
+	}
+
+	srv := micro.NewService(
+		micro.Name("customer-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	addressRepo := repository.NewAddressRepository(db)
+	addressService := service.NewAddressService(addressRepo, postalRules)
+
+	addressHandler := handler.NewAddressHandler(addressService)
+
+	if err := pb.RegisterAddressServiceHandler(srv.Server(), addressHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,33 @@
+// services/customer-service/internal/config/config.go
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port            string
+	Environment     string
+	DatabaseURL     string
+	PostalRulesPath string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:            getEnv("PORT", "8085"),
+		Environment:     os.Getenv("ENVIRONMENT"),
+		DatabaseURL:     os.Getenv("DATABASE_URL"),
+		PostalRulesPath: getEnv("POSTAL_RULES_PATH", "data/postal_codes.json"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,33 @@
+package dto
+
+type AddressRequest struct {
+	Label             string `json:"label"`
+	RecipientName     string `json:"recipient_name"`
+	PhoneNumber       string `json:"phone_number"`
+	Line1             string `json:"line1"`
+	Line2             string `json:"line2"`
+	City              string `json:"city"`
+	Region            string `json:"region"`
+	PostalCode        string `json:"postal_code"`
+	CountryCode       string `json:"country_code"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+}
+
+type AddressResponse struct {
+	ID                string `json:"id"`
+	CustomerID        int64  `json:"customer_id"`
+	Label             string `json:"label"`
+	RecipientName     string `json:"recipient_name"`
+	PhoneNumber       string `json:"phone_number"`
+	Line1             string `json:"line1"`
+	Line2             string `json:"line2,omitempty"`
+	City              string `json:"city"`
+	Region            string `json:"region,omitempty"`
+	PostalCode        string `json:"postal_code,omitempty"`
+	CountryCode       string `json:"country_code"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
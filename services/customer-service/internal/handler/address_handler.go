@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/customer-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "customer-service"
+
+type AddressHandler struct {
+	addressService service.AddressService
+}
+
+func NewAddressHandler(addressService service.AddressService) *AddressHandler {
+	return &AddressHandler{addressService: addressService}
+}
+
+func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddressesRequest, rsp *pb.ListAddressesResponse) error {
+	addresses, err := h.addressService.ListAddresses(ctx, req.CustomerId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, address := range addresses {
+		rsp.Addresses = append(rsp.Addresses, toProtoAddress(address))
+	}
+
+	return nil
+}
+
+func (h *AddressHandler) GetAddress(ctx context.Context, req *pb.GetAddressRequest, rsp *pb.AddressResponse) error {
+	address, err := h.addressService.GetAddress(ctx, req.CustomerId, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Address = toProtoAddress(address)
+	return nil
+}
+
+func (h *AddressHandler) CreateAddress(ctx context.Context, req *pb.CreateAddressRequest, rsp *pb.AddressResponse) error {
+	address, err := h.addressService.CreateAddress(ctx, req.CustomerId, fromProtoInput(req.Address))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Address = toProtoAddress(address)
+	return nil
+}
+
+func (h *AddressHandler) UpdateAddress(ctx context.Context, req *pb.UpdateAddressRequest, rsp *pb.AddressResponse) error {
+	address, err := h.addressService.UpdateAddress(ctx, req.CustomerId, req.Id, fromProtoInput(req.Address))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Address = toProtoAddress(address)
+	return nil
+}
+
+func (h *AddressHandler) DeleteAddress(ctx context.Context, req *pb.DeleteAddressRequest, rsp *pb.DeleteAddressResponse) error {
+	if err := h.addressService.DeleteAddress(ctx, req.CustomerId, req.Id); err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Deleted = true
+	return nil
+}
+
+func (h *AddressHandler) SetDefaultAddress(ctx context.Context, req *pb.SetDefaultAddressRequest, rsp *pb.AddressResponse) error {
+	address, err := h.addressService.SetDefaultAddress(ctx, req.CustomerId, req.Id, model.AddressKind(req.Kind))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Address = toProtoAddress(address)
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrAddressNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrAddressLimitReached):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func fromProtoInput(in *pb.AddressInput) *dto.AddressRequest {
+	if in == nil {
+		return &dto.AddressRequest{}
+	}
+
+	return &dto.AddressRequest{
+		Label:             in.Label,
+		RecipientName:     in.RecipientName,
+		PhoneNumber:       in.PhoneNumber,
+		Line1:             in.Line1,
+		Line2:             in.Line2,
+		City:              in.City,
+		Region:            in.Region,
+		PostalCode:        in.PostalCode,
+		CountryCode:       in.CountryCode,
+		IsDefaultShipping: in.IsDefaultShipping,
+		IsDefaultBilling:  in.IsDefaultBilling,
+	}
+}
+
+func toProtoAddress(address *dto.AddressResponse) *pb.Address {
+	return &pb.Address{
+		Id:                address.ID,
+		CustomerId:        address.CustomerID,
+		Label:             address.Label,
+		RecipientName:     address.RecipientName,
+		PhoneNumber:       address.PhoneNumber,
+		Line1:             address.Line1,
+		Line2:             address.Line2,
+		City:              address.City,
+		Region:            address.Region,
+		PostalCode:        address.PostalCode,
+		CountryCode:       address.CountryCode,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt,
+		UpdatedAt:         address.UpdatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type AddressKind string
+
+const (
+	AddressKindShipping AddressKind = "shipping"
+	AddressKindBilling  AddressKind = "billing"
+)
+
+type Address struct {
+	ID                string    `db:"id"`
+	CustomerID        int64     `db:"customer_id"`
+	Label             string    `db:"label"`
+	RecipientName     string    `db:"recipient_name"`
+	PhoneNumber       string    `db:"phone_number"`
+	Line1             string    `db:"line1"`
+	Line2             string    `db:"line2"`
+	City              string    `db:"city"`
+	Region            string    `db:"region"`
+	PostalCode        string    `db:"postal_code"`
+	CountryCode       string    `db:"country_code"`
+	IsDefaultShipping bool      `db:"is_default_shipping"`
+	IsDefaultBilling  bool      `db:"is_default_billing"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
@@ -0,0 +1,86 @@
+package postal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule describes the postal code format of a single country.
+type Rule struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"`
+	Example  string `json:"example"`
+	Required bool   `json:"required"`
+
+	re *regexp.Regexp
+}
+
+// Rules maps ISO 3166-1 alpha-2 country codes to their postal code rule.
+// A country missing from the table is not supported for delivery.
+type Rules map[string]*Rule
+
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read postal rules: %w", err)
+	}
+
+	return ParseRules(data)
+}
+
+func ParseRules(data []byte) (Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse postal rules: %w", err)
+	}
+
+	for code, rule := range rules {
+		if len(code) != 2 || strings.ToUpper(code) != code {
+			return nil, fmt.Errorf("postal rules: invalid country code %q", code)
+		}
+		if rule.Pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("postal rules: invalid pattern for %s: %w", code, err)
+		}
+		rule.re = re
+	}
+
+	return rules, nil
+}
+
+// Normalize upper-cases and trims a postal code so that user input such as
+// "sw1a 1aa " matches the rule table.
+func Normalize(postalCode string) string {
+	return strings.ToUpper(strings.TrimSpace(postalCode))
+}
+
+// Validate checks a normalized postal code against the rule of countryCode.
+func (r Rules) Validate(countryCode, postalCode string) error {
+	rule, ok := r[countryCode]
+	if !ok {
+		return fmt.Errorf("country %q is not supported", countryCode)
+	}
+
+	if postalCode == "" {
+		if rule.Required {
+			return fmt.Errorf("postal code is required for %s", rule.Name)
+		}
+		return nil
+	}
+
+	if rule.re != nil && !rule.re.MatchString(postalCode) {
+		if rule.Example != "" {
+			return fmt.Errorf("invalid postal code for %s, expected format like %s", rule.Name, rule.Example)
+		}
+		return fmt.Errorf("invalid postal code for %s", rule.Name)
+	}
+
+	return nil
+}
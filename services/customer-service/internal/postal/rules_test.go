@@ -0,0 +1,89 @@
+package postal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRulesDataFile(t *testing.T) {
+	rules, err := LoadRules("../../data/postal_codes.json")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	for code, rule := range rules {
+		if rule.Name == "" {
+			t.Errorf("%s has no name", code)
+		}
+		if rule.Required && rule.Pattern == "" {
+			t.Errorf("%s requires a postal code but has no pattern", code)
+		}
+		if rule.Example == "" {
+			continue
+		}
+		if err := rules.Validate(code, Normalize(rule.Example)); err != nil {
+			t.Errorf("%s rejects its own example %q: %v", code, rule.Example, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rules, err := LoadRules("../../data/postal_codes.json")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	tests := []struct {
+		country    string
+		postalCode string
+		wantErr    string
+	}{
+		{country: "US", postalCode: "94105"},
+		{country: "US", postalCode: "94105-1234"},
+		{country: "US", postalCode: "9410", wantErr: "expected format like 94105"},
+		{country: "US", postalCode: "", wantErr: "required"},
+		{country: "GB", postalCode: Normalize(" sw1a 1aa ")},
+		{country: "GB", postalCode: "12345", wantErr: "invalid postal code"},
+		{country: "CA", postalCode: "K1A0B1"},
+		{country: "JP", postalCode: "100-0001"},
+		{country: "NL", postalCode: "1012AB"},
+		{country: "HK", postalCode: ""},
+		{country: "HK", postalCode: "ANYTHING"},
+		{country: "VN", postalCode: ""},
+		{country: "VN", postalCode: "1000", wantErr: "invalid postal code"},
+		{country: "XX", postalCode: "12345", wantErr: "not supported"},
+		{country: "us", postalCode: "94105", wantErr: "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+"/"+tt.postalCode, func(t *testing.T) {
+			err := rules.Validate(tt.country, tt.postalCode)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRulesRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "malformed json", data: `{"US": `},
+		{name: "lower-case country", data: `{"us": {"name": "United States"}}`},
+		{name: "three-letter country", data: `{"USA": {"name": "United States"}}`},
+		{name: "bad pattern", data: `{"US": {"name": "United States", "pattern": "^(\\d{5}$"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRules([]byte(tt.data)); err == nil {
+				t.Error("ParseRules() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+)
+
+var (
+	ErrAddressNotFound     = errors.New("address not found")
+	ErrAddressLimitReached = errors.New("address book is full")
+)
+
+type AddressRepository interface {
+	ListByCustomer(ctx context.Context, customerID int64) ([]*model.Address, error)
+	GetByID(ctx context.Context, customerID int64, id string) (*model.Address, error)
+
+	// Create and Update clear the default flags of the customer's other
+	// addresses when the given address is marked as a default.
+	//
+	// Create fails with ErrAddressLimitReached when the customer already has
+	// limit addresses, and makes the first address the default for both
+	// purposes.
+	Create(ctx context.Context, address *model.Address, limit int) error
+	Update(ctx context.Context, address *model.Address) error
+	SetDefault(ctx context.Context, customerID int64, id string, kind model.AddressKind) error
+
+	// Delete removes the address and hands its default flags over to the
+	// customer's most recently updated remaining address.
+	Delete(ctx context.Context, customerID int64, id string) error
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+)
+
+const addressColumns = `
+	id, customer_id, label, recipient_name, phone_number, line1, COALESCE(line2, ''), city,
+	COALESCE(region, ''), COALESCE(postal_code, ''), country_code,
+	is_default_shipping, is_default_billing, created_at, updated_at
+`
+
+type addressRepository struct {
+	db *sql.DB
+}
+
+func NewAddressRepository(db *sql.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAddress(row rowScanner) (*model.Address, error) {
+	var address model.Address
+	err := row.Scan(
+		&address.ID,
+		&address.CustomerID,
+		&address.Label,
+		&address.RecipientName,
+		&address.PhoneNumber,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.CountryCode,
+		&address.IsDefaultShipping,
+		&address.IsDefaultBilling,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (r *addressRepository) ListByCustomer(ctx context.Context, customerID int64) ([]*model.Address, error) {
+	query := `SELECT ` + addressColumns + `
+		FROM addresses
+		WHERE customer_id = $1
+		ORDER BY is_default_shipping DESC, is_default_billing DESC, updated_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []*model.Address
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, rows.Err()
+}
+
+func (r *addressRepository) GetByID(ctx context.Context, customerID int64, id string) (*model.Address, error) {
+	query := `SELECT ` + addressColumns + `
+		FROM addresses
+		WHERE id = $1 AND customer_id = $2
+	`
+
+	address, err := scanAddress(r.db.QueryRowContext(ctx, query, id, customerID))
+	if err == sql.ErrNoRows {
+		return nil, ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (r *addressRepository) Create(ctx context.Context, address *model.Address, limit int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Creates for the same customer take turns until commit, so concurrent
+	// ones cannot both pass the limit check. There is no customer row to lock;
+	// the database only holds addresses, so the customer ID is the key.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, address.CustomerID); err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM addresses WHERE customer_id = $1`, address.CustomerID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return ErrAddressLimitReached
+	}
+
+	// The first address always becomes the default for both purposes so that
+	// checkout never has to ask for an address the customer already gave us.
+	if count == 0 {
+		address.IsDefaultShipping = true
+		address.IsDefaultBilling = true
+	}
+
+	if err := clearDefaults(ctx, tx, address.CustomerID, "", address.IsDefaultShipping, address.IsDefaultBilling); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO addresses (customer_id, label, recipient_name, phone_number, line1, line2, city,
+			region, postal_code, country_code, is_default_shipping, is_default_billing)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		address.CustomerID,
+		address.Label,
+		address.RecipientName,
+		address.PhoneNumber,
+		address.Line1,
+		address.Line2,
+		address.City,
+		address.Region,
+		address.PostalCode,
+		address.CountryCode,
+		address.IsDefaultShipping,
+		address.IsDefaultBilling,
+	).Scan(&address.ID, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *addressRepository) Update(ctx context.Context, address *model.Address) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearDefaults(ctx, tx, address.CustomerID, address.ID, address.IsDefaultShipping, address.IsDefaultBilling); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE addresses
+		SET label = $3, recipient_name = $4, phone_number = $5, line1 = $6, line2 = $7, city = $8,
+			region = $9, postal_code = $10, country_code = $11,
+			is_default_shipping = $12, is_default_billing = $13, updated_at = NOW()
+		WHERE id = $1 AND customer_id = $2
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		address.ID,
+		address.CustomerID,
+		address.Label,
+		address.RecipientName,
+		address.PhoneNumber,
+		address.Line1,
+		address.Line2,
+		address.City,
+		address.Region,
+		address.PostalCode,
+		address.CountryCode,
+		address.IsDefaultShipping,
+		address.IsDefaultBilling,
+	).Scan(&address.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrAddressNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *addressRepository) SetDefault(ctx context.Context, customerID int64, id string, kind model.AddressKind) error {
+	column, err := defaultColumn(kind)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearDefaults(ctx, tx, customerID, id, kind == model.AddressKindShipping, kind == model.AddressKindBilling); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE addresses SET %s = true, updated_at = NOW() WHERE id = $1 AND customer_id = $2`, column)
+	result, err := tx.ExecContext(ctx, query, id, customerID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrAddressNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *addressRepository) Delete(ctx context.Context, customerID int64, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasShipping, wasBilling bool
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM addresses WHERE id = $1 AND customer_id = $2 RETURNING is_default_shipping, is_default_billing`,
+		id, customerID,
+	).Scan(&wasShipping, &wasBilling)
+	if err == sql.ErrNoRows {
+		return ErrAddressNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if wasShipping || wasBilling {
+		query := `
+			UPDATE addresses
+			SET is_default_shipping = is_default_shipping OR $2,
+				is_default_billing = is_default_billing OR $3
+			WHERE id = (
+				SELECT id FROM addresses WHERE customer_id = $1 ORDER BY updated_at DESC LIMIT 1
+			)
+		`
+		if _, err := tx.ExecContext(ctx, query, customerID, wasShipping, wasBilling); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// clearDefaults unsets the requested default flags on every address of the
+// customer except exceptID, keeping the partial unique indexes satisfied.
+func clearDefaults(ctx context.Context, tx *sql.Tx, customerID int64, exceptID string, shipping, billing bool) error {
+	if shipping {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE addresses SET is_default_shipping = false WHERE customer_id = $1 AND id::text <> $2 AND is_default_shipping`,
+			customerID, exceptID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if billing {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE addresses SET is_default_billing = false WHERE customer_id = $1 AND id::text <> $2 AND is_default_billing`,
+			customerID, exceptID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func defaultColumn(kind model.AddressKind) (string, error) {
+	switch kind {
+	case model.AddressKindShipping:
+		return "is_default_shipping", nil
+	case model.AddressKindBilling:
+		return "is_default_billing", nil
+	default:
+		return "", fmt.Errorf("unknown address kind %q", kind)
+	}
+}
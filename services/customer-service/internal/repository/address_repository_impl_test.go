@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+
+	_ "github.com/lib/pq"
+)
+
+// openTestDB migrates a fresh schema in the database named by
+// CUSTOMER_TEST_DATABASE_URL, and skips the test when it is not set.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("CUSTOMER_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("CUSTOMER_TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("apply %s: %v", path, err)
+		}
+	}
+
+	return db
+}
+
+func testAddress(customerID int64) *model.Address {
+	return &model.Address{
+		CustomerID:    customerID,
+		Label:         "Home",
+		RecipientName: "Jane Doe",
+		PhoneNumber:   "+14155550100",
+		Line1:         "1 Market St",
+		City:          "San Francisco",
+		PostalCode:    "94105",
+		CountryCode:   "US",
+	}
+}
+
+func TestCreateLimitUnderConcurrency(t *testing.T) {
+	repo := NewAddressRepository(openTestDB(t))
+	ctx := context.Background()
+
+	const limit = 5
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+		full    int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.Create(ctx, testAddress(1), limit)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, ErrAddressLimitReached):
+				full++
+			default:
+				t.Errorf("Create() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != limit || full != 20-limit {
+		t.Errorf("created %d and refused %d, want %d and %d", created, full, limit, 20-limit)
+	}
+
+	addresses, err := repo.ListByCustomer(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListByCustomer() error = %v", err)
+	}
+	if len(addresses) != limit {
+		t.Errorf("customer has %d addresses, want %d", len(addresses), limit)
+	}
+}
+
+func TestDefaultSwitching(t *testing.T) {
+	repo := NewAddressRepository(openTestDB(t))
+	ctx := context.Background()
+
+	defaults := func() (shipping, billing string) {
+		t.Helper()
+		addresses, err := repo.ListByCustomer(ctx, 1)
+		if err != nil {
+			t.Fatalf("ListByCustomer() error = %v", err)
+		}
+		for _, address := range addresses {
+			if address.IsDefaultShipping {
+				if shipping != "" {
+					t.Fatalf("more than one default shipping address")
+				}
+				shipping = address.ID
+			}
+			if address.IsDefaultBilling {
+				if billing != "" {
+					t.Fatalf("more than one default billing address")
+				}
+				billing = address.ID
+			}
+		}
+		return shipping, billing
+	}
+
+	first := testAddress(1)
+	if err := repo.Create(ctx, first, 20); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if shipping, billing := defaults(); shipping != first.ID || billing != first.ID {
+		t.Fatalf("defaults = %s/%s, want the first address for both", shipping, billing)
+	}
+
+	second := testAddress(1)
+	second.IsDefaultShipping = true
+	if err := repo.Create(ctx, second, 20); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if shipping, billing := defaults(); shipping != second.ID || billing != first.ID {
+		t.Fatalf("defaults = %s/%s, want shipping %s and billing %s", shipping, billing, second.ID, first.ID)
+	}
+
+	if err := repo.SetDefault(ctx, 1, second.ID, model.AddressKindBilling); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if shipping, billing := defaults(); shipping != second.ID || billing != second.ID {
+		t.Fatalf("defaults = %s/%s, want the second address for both", shipping, billing)
+	}
+
+	if err := repo.Delete(ctx, 1, second.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if shipping, billing := defaults(); shipping != first.ID || billing != first.ID {
+		t.Errorf("defaults after delete = %s/%s, want them handed to %s", shipping, billing, first.ID)
+	}
+
+	if err := repo.SetDefault(ctx, 2, first.ID, model.AddressKindShipping); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("SetDefault() for another customer error = %v, want %v", err, ErrAddressNotFound)
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+)
+
+// ValidationError lists the invalid fields of an address request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for field, msg := range e.Fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, msg))
+	}
+	return "invalid address: " + strings.Join(parts, "; ")
+}
+
+// AddressService manages a customer's address book
+type AddressService interface {
+	ListAddresses(ctx context.Context, customerID int64) ([]*dto.AddressResponse, error)
+	GetAddress(ctx context.Context, customerID int64, id string) (*dto.AddressResponse, error)
+	CreateAddress(ctx context.Context, customerID int64, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	UpdateAddress(ctx context.Context, customerID int64, id string, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	DeleteAddress(ctx context.Context, customerID int64, id string) error
+	SetDefaultAddress(ctx context.Context, customerID int64, id string, kind model.AddressKind) (*dto.AddressResponse, error)
+}
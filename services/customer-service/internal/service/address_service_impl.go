@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/postal"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/repository"
+)
+
+const (
+	maxAddressesPerCustomer = 20
+	defaultAddressLabel     = "Home"
+)
+
+var (
+	phoneNumberPattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	// addressIDPattern matches the UUIDs the database assigns. Anything else
+	// cannot name an address, and would fail the query as bad input.
+	addressIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+type addressServiceImpl struct {
+	addressRepo repository.AddressRepository
+	postalRules postal.Rules
+}
+
+func NewAddressService(addressRepo repository.AddressRepository, postalRules postal.Rules) AddressService {
+	return &addressServiceImpl{
+		addressRepo: addressRepo,
+		postalRules: postalRules,
+	}
+}
+
+// ListAddresses implements AddressService.
+func (s *addressServiceImpl) ListAddresses(ctx context.Context, customerID int64) ([]*dto.AddressResponse, error) {
+	addresses, err := s.addressRepo.ListByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		resp = append(resp, toAddressResponse(address))
+	}
+
+	return resp, nil
+}
+
+// GetAddress implements AddressService.
+func (s *addressServiceImpl) GetAddress(ctx context.Context, customerID int64, id string) (*dto.AddressResponse, error) {
+	if !addressIDPattern.MatchString(id) {
+		return nil, repository.ErrAddressNotFound
+	}
+
+	address, err := s.addressRepo.GetByID(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(address), nil
+}
+
+// CreateAddress implements AddressService.
+func (s *addressServiceImpl) CreateAddress(ctx context.Context, customerID int64, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	address, err := s.buildAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	address.CustomerID = customerID
+
+	if err := s.addressRepo.Create(ctx, address, maxAddressesPerCustomer); err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(address), nil
+}
+
+// UpdateAddress implements AddressService.
+func (s *addressServiceImpl) UpdateAddress(ctx context.Context, customerID int64, id string, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	if !addressIDPattern.MatchString(id) {
+		return nil, repository.ErrAddressNotFound
+	}
+
+	existing, err := s.addressRepo.GetByID(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := s.buildAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = existing.ID
+	address.CustomerID = existing.CustomerID
+	address.CreatedAt = existing.CreatedAt
+
+	// Defaults are only moved away by picking another default, never by
+	// unticking the current one.
+	address.IsDefaultShipping = address.IsDefaultShipping || existing.IsDefaultShipping
+	address.IsDefaultBilling = address.IsDefaultBilling || existing.IsDefaultBilling
+
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(address), nil
+}
+
+// DeleteAddress implements AddressService.
+func (s *addressServiceImpl) DeleteAddress(ctx context.Context, customerID int64, id string) error {
+	if !addressIDPattern.MatchString(id) {
+		return repository.ErrAddressNotFound
+	}
+
+	return s.addressRepo.Delete(ctx, customerID, id)
+}
+
+// SetDefaultAddress implements AddressService.
+func (s *addressServiceImpl) SetDefaultAddress(ctx context.Context, customerID int64, id string, kind model.AddressKind) (*dto.AddressResponse, error) {
+	if kind != model.AddressKindShipping && kind != model.AddressKindBilling {
+		return nil, &ValidationError{Fields: map[string]string{"kind": "must be shipping or billing"}}
+	}
+	if !addressIDPattern.MatchString(id) {
+		return nil, repository.ErrAddressNotFound
+	}
+
+	if err := s.addressRepo.SetDefault(ctx, customerID, id, kind); err != nil {
+		return nil, err
+	}
+
+	return s.GetAddress(ctx, customerID, id)
+}
+
+func (s *addressServiceImpl) buildAddress(req *dto.AddressRequest) (*model.Address, error) {
+	address := &model.Address{
+		Label:             strings.TrimSpace(req.Label),
+		RecipientName:     strings.TrimSpace(req.RecipientName),
+		PhoneNumber:       strings.TrimSpace(req.PhoneNumber),
+		Line1:             strings.TrimSpace(req.Line1),
+		Line2:             strings.TrimSpace(req.Line2),
+		City:              strings.TrimSpace(req.City),
+		Region:            strings.TrimSpace(req.Region),
+		PostalCode:        postal.Normalize(req.PostalCode),
+		CountryCode:       strings.ToUpper(strings.TrimSpace(req.CountryCode)),
+		IsDefaultShipping: req.IsDefaultShipping,
+		IsDefaultBilling:  req.IsDefaultBilling,
+	}
+
+	if address.Label == "" {
+		address.Label = defaultAddressLabel
+	}
+
+	fields := map[string]string{}
+	checkLength(fields, "label", address.Label, 50, false)
+	checkLength(fields, "recipient_name", address.RecipientName, 100, true)
+	checkLength(fields, "line1", address.Line1, 255, true)
+	checkLength(fields, "line2", address.Line2, 255, false)
+	checkLength(fields, "city", address.City, 100, true)
+	checkLength(fields, "region", address.Region, 100, false)
+
+	if !phoneNumberPattern.MatchString(address.PhoneNumber) {
+		fields["phone_number"] = "must be in E.164 format, e.g. +6281234567890"
+	}
+
+	if len(address.CountryCode) != 2 {
+		fields["country_code"] = "must be an ISO 3166-1 alpha-2 code"
+	} else if err := s.postalRules.Validate(address.CountryCode, address.PostalCode); err != nil {
+		if _, supported := s.postalRules[address.CountryCode]; !supported {
+			fields["country_code"] = err.Error()
+		} else {
+			fields["postal_code"] = err.Error()
+		}
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	return address, nil
+}
+
+func checkLength(fields map[string]string, name, value string, max int, required bool) {
+	switch {
+	case required && value == "":
+		fields[name] = "is required"
+	case len(value) > max:
+		fields[name] = "is too long"
+	}
+}
+
+func toAddressResponse(address *model.Address) *dto.AddressResponse {
+	return &dto.AddressResponse{
+		ID:                address.ID,
+		CustomerID:        address.CustomerID,
+		Label:             address.Label,
+		RecipientName:     address.RecipientName,
+		PhoneNumber:       address.PhoneNumber,
+		Line1:             address.Line1,
+		Line2:             address.Line2,
+		City:              address.City,
+		Region:            address.Region,
+		PostalCode:        address.PostalCode,
+		CountryCode:       address.CountryCode,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         address.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/postal"
+	"github.com/Dzaakk/micro-commerce/services/customer-service/internal/repository"
+)
+
+// fakeAddressRepo keeps addresses in memory and follows the default and
+// limit rules of the AddressRepository contract.
+type fakeAddressRepo struct {
+	addresses []*model.Address
+	calls     int
+}
+
+func (r *fakeAddressRepo) ListByCustomer(ctx context.Context, customerID int64) ([]*model.Address, error) {
+	r.calls++
+	var addresses []*model.Address
+	for _, address := range r.addresses {
+		if address.CustomerID == customerID {
+			copied := *address
+			addresses = append(addresses, &copied)
+		}
+	}
+	return addresses, nil
+}
+
+func (r *fakeAddressRepo) GetByID(ctx context.Context, customerID int64, id string) (*model.Address, error) {
+	r.calls++
+	if address := r.find(customerID, id); address != nil {
+		copied := *address
+		return &copied, nil
+	}
+	return nil, repository.ErrAddressNotFound
+}
+
+func (r *fakeAddressRepo) Create(ctx context.Context, address *model.Address, limit int) error {
+	r.calls++
+	count := 0
+	for _, existing := range r.addresses {
+		if existing.CustomerID == address.CustomerID {
+			count++
+		}
+	}
+	if count >= limit {
+		return repository.ErrAddressLimitReached
+	}
+	if count == 0 {
+		address.IsDefaultShipping = true
+		address.IsDefaultBilling = true
+	}
+	r.clearDefaults(address)
+
+	address.ID = fmt.Sprintf("00000000-0000-4000-8000-%012d", len(r.addresses)+1)
+	copied := *address
+	r.addresses = append(r.addresses, &copied)
+	return nil
+}
+
+func (r *fakeAddressRepo) Update(ctx context.Context, address *model.Address) error {
+	r.calls++
+	existing := r.find(address.CustomerID, address.ID)
+	if existing == nil {
+		return repository.ErrAddressNotFound
+	}
+	r.clearDefaults(address)
+	*existing = *address
+	return nil
+}
+
+func (r *fakeAddressRepo) SetDefault(ctx context.Context, customerID int64, id string, kind model.AddressKind) error {
+	r.calls++
+	address := r.find(customerID, id)
+	if address == nil {
+		return repository.ErrAddressNotFound
+	}
+	update := *address
+	update.IsDefaultShipping = kind == model.AddressKindShipping
+	update.IsDefaultBilling = kind == model.AddressKindBilling
+	r.clearDefaults(&update)
+	address.IsDefaultShipping = address.IsDefaultShipping || update.IsDefaultShipping
+	address.IsDefaultBilling = address.IsDefaultBilling || update.IsDefaultBilling
+	return nil
+}
+
+func (r *fakeAddressRepo) Delete(ctx context.Context, customerID int64, id string) error {
+	r.calls++
+	for i, address := range r.addresses {
+		if address.CustomerID == customerID && address.ID == id {
+			r.addresses = append(r.addresses[:i], r.addresses[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrAddressNotFound
+}
+
+func (r *fakeAddressRepo) find(customerID int64, id string) *model.Address {
+	for _, address := range r.addresses {
+		if address.CustomerID == customerID && address.ID == id {
+			return address
+		}
+	}
+	return nil
+}
+
+func (r *fakeAddressRepo) clearDefaults(except *model.Address) {
+	for _, address := range r.addresses {
+		if address.CustomerID != except.CustomerID || address.ID == except.ID {
+			continue
+		}
+		if except.IsDefaultShipping {
+			address.IsDefaultShipping = false
+		}
+		if except.IsDefaultBilling {
+			address.IsDefaultBilling = false
+		}
+	}
+}
+
+func newTestAddressService(t *testing.T) (AddressService, *fakeAddressRepo) {
+	t.Helper()
+
+	rules, err := postal.LoadRules("../../data/postal_codes.json")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	repo := &fakeAddressRepo{}
+	return NewAddressService(repo, rules), repo
+}
+
+func validAddressRequest() *dto.AddressRequest {
+	return &dto.AddressRequest{
+		RecipientName: "Jane Doe",
+		PhoneNumber:   "+14155550100",
+		Line1:         "1 Market St",
+		City:          "San Francisco",
+		Region:        "CA",
+		PostalCode:    "94105",
+		CountryCode:   "us",
+	}
+}
+
+func TestCreateAddressValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(req *dto.AddressRequest)
+		wantField string
+	}{
+		{name: "valid", edit: func(req *dto.AddressRequest) {}},
+		{name: "postal code is normalized", edit: func(req *dto.AddressRequest) {
+			req.CountryCode, req.PostalCode = "GB", " sw1a 1aa "
+		}},
+		{name: "postal code optional where the country has none", edit: func(req *dto.AddressRequest) {
+			req.CountryCode, req.PostalCode = "HK", ""
+		}},
+		{name: "postal code in the wrong format", edit: func(req *dto.AddressRequest) {
+			req.PostalCode = "941"
+		}, wantField: "postal_code"},
+		{name: "postal code missing where required", edit: func(req *dto.AddressRequest) {
+			req.PostalCode = ""
+		}, wantField: "postal_code"},
+		{name: "unsupported country", edit: func(req *dto.AddressRequest) {
+			req.CountryCode = "XX"
+		}, wantField: "country_code"},
+		{name: "country code is not alpha-2", edit: func(req *dto.AddressRequest) {
+			req.CountryCode = "USA"
+		}, wantField: "country_code"},
+		{name: "phone number is not E.164", edit: func(req *dto.AddressRequest) {
+			req.PhoneNumber = "415-555-0100"
+		}, wantField: "phone_number"},
+		{name: "recipient is required", edit: func(req *dto.AddressRequest) {
+			req.RecipientName = "  "
+		}, wantField: "recipient_name"},
+		{name: "label too long", edit: func(req *dto.AddressRequest) {
+			req.Label = string(make([]byte, 51))
+		}, wantField: "label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestAddressService(t)
+			req := validAddressRequest()
+			tt.edit(req)
+
+			_, err := svc.CreateAddress(context.Background(), 1, req)
+
+			var validationErr *ValidationError
+			switch {
+			case tt.wantField == "" && err != nil:
+				t.Fatalf("CreateAddress() error = %v, want nil", err)
+			case tt.wantField != "" && !errors.As(err, &validationErr):
+				t.Fatalf("CreateAddress() error = %v, want validation error", err)
+			case tt.wantField != "" && validationErr.Fields[tt.wantField] == "":
+				t.Errorf("CreateAddress() invalid fields = %v, want %s", validationErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCreateAddressLimit(t *testing.T) {
+	svc, _ := newTestAddressService(t)
+	ctx := context.Background()
+
+	for i := 0; i < maxAddressesPerCustomer; i++ {
+		if _, err := svc.CreateAddress(ctx, 1, validAddressRequest()); err != nil {
+			t.Fatalf("CreateAddress() #%d error = %v", i+1, err)
+		}
+	}
+
+	if _, err := svc.CreateAddress(ctx, 1, validAddressRequest()); !errors.Is(err, repository.ErrAddressLimitReached) {
+		t.Errorf("CreateAddress() past the limit error = %v, want %v", err, repository.ErrAddressLimitReached)
+	}
+	if _, err := svc.CreateAddress(ctx, 2, validAddressRequest()); err != nil {
+		t.Errorf("CreateAddress() for another customer error = %v", err)
+	}
+}
+
+func TestAddressDefaults(t *testing.T) {
+	svc, _ := newTestAddressService(t)
+	ctx := context.Background()
+
+	first, err := svc.CreateAddress(ctx, 1, validAddressRequest())
+	if err != nil {
+		t.Fatalf("CreateAddress() error = %v", err)
+	}
+	if !first.IsDefaultShipping || !first.IsDefaultBilling {
+		t.Fatalf("first address defaults = %v/%v, want both", first.IsDefaultShipping, first.IsDefaultBilling)
+	}
+
+	req := validAddressRequest()
+	req.IsDefaultShipping = true
+	second, err := svc.CreateAddress(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("CreateAddress() error = %v", err)
+	}
+
+	defaults := func() (shipping, billing []string) {
+		t.Helper()
+		addresses, err := svc.ListAddresses(ctx, 1)
+		if err != nil {
+			t.Fatalf("ListAddresses() error = %v", err)
+		}
+		for _, address := range addresses {
+			if address.IsDefaultShipping {
+				shipping = append(shipping, address.ID)
+			}
+			if address.IsDefaultBilling {
+				billing = append(billing, address.ID)
+			}
+		}
+		return shipping, billing
+	}
+
+	shipping, billing := defaults()
+	if len(shipping) != 1 || shipping[0] != second.ID || len(billing) != 1 || billing[0] != first.ID {
+		t.Fatalf("defaults = %v/%v, want shipping %s and billing %s", shipping, billing, second.ID, first.ID)
+	}
+
+	// Unticking the current default does not leave the customer without one.
+	if _, err := svc.UpdateAddress(ctx, 1, second.ID, validAddressRequest()); err != nil {
+		t.Fatalf("UpdateAddress() error = %v", err)
+	}
+	if shipping, _ := defaults(); len(shipping) != 1 || shipping[0] != second.ID {
+		t.Errorf("shipping default after update = %v, want %s", shipping, second.ID)
+	}
+
+	if _, err := svc.SetDefaultAddress(ctx, 1, second.ID, model.AddressKindBilling); err != nil {
+		t.Fatalf("SetDefaultAddress() error = %v", err)
+	}
+	if shipping, billing := defaults(); len(shipping) != 1 || shipping[0] != second.ID || len(billing) != 1 || billing[0] != second.ID {
+		t.Errorf("defaults after SetDefaultAddress() = %v/%v, want both %s", shipping, billing, second.ID)
+	}
+
+	var validationErr *ValidationError
+	if _, err := svc.SetDefaultAddress(ctx, 1, second.ID, "home"); !errors.As(err, &validationErr) {
+		t.Errorf("SetDefaultAddress() with an unknown kind error = %v, want validation error", err)
+	}
+}
+
+func TestAddressIDMustBeUUID(t *testing.T) {
+	svc, repo := newTestAddressService(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"", "1", "not-a-uuid", "00000000-0000-4000-8000-00000000000g", "00000000-0000-4000-8000-000000000001'"} {
+		if _, err := svc.GetAddress(ctx, 1, id); !errors.Is(err, repository.ErrAddressNotFound) {
+			t.Errorf("GetAddress(%q) error = %v, want %v", id, err, repository.ErrAddressNotFound)
+		}
+		if _, err := svc.UpdateAddress(ctx, 1, id, validAddressRequest()); !errors.Is(err, repository.ErrAddressNotFound) {
+			t.Errorf("UpdateAddress(%q) error = %v, want %v", id, err, repository.ErrAddressNotFound)
+		}
+		if _, err := svc.SetDefaultAddress(ctx, 1, id, model.AddressKindShipping); !errors.Is(err, repository.ErrAddressNotFound) {
+			t.Errorf("SetDefaultAddress(%q) error = %v, want %v", id, err, repository.ErrAddressNotFound)
+		}
+		if err := svc.DeleteAddress(ctx, 1, id); !errors.Is(err, repository.ErrAddressNotFound) {
+			t.Errorf("DeleteAddress(%q) error = %v, want %v", id, err, repository.ErrAddressNotFound)
+		}
+	}
+
+	if repo.calls != 0 {
+		t.Errorf("repository was called %d times, want malformed IDs rejected before querying", repo.calls)
+	}
+}
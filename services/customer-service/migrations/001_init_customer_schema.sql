@@ -0,0 +1,24 @@
+CREATE TABLE addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id BIGINT NOT NULL,
+    label VARCHAR(50) NOT NULL DEFAULT 'Home', -- 'Home', 'Office', ...
+    recipient_name VARCHAR(100) NOT NULL,
+    phone_number VARCHAR(20) NOT NULL,
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100),
+    postal_code VARCHAR(20),
+    country_code CHAR(2) NOT NULL, -- ISO 3166-1 alpha-2
+    is_default_shipping BOOLEAN DEFAULT false,
+    is_default_billing BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX idx_addresses_customer ON addresses(customer_id);
+
+-- At most one default shipping and one default billing address per customer
+CREATE UNIQUE INDEX idx_addresses_default_shipping ON addresses(customer_id) WHERE is_default_shipping;
+CREATE UNIQUE INDEX idx_addresses_default_billing ON addresses(customer_id) WHERE is_default_billing;
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/event"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("inventory-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+
+	publisher := event.NewBrokerPublisher(srv.Server().Options().Broker)
+
+	warehouseService := service.NewWarehouseService(warehouseRepo)
+	inventoryService := service.NewInventoryService(
+		inventoryRepo,
+		warehouseRepo,
+		publisher,
+		cfg.ReservationTTL,
+		cfg.ExpirySweepInterval,
+	)
+
+	inventoryHandler := handler.NewInventoryHandler(warehouseService, inventoryService)
+
+	if err := pb.RegisterInventoryServiceHandler(srv.Server(), inventoryHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go inventoryService.RunExpiry(ctx)
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
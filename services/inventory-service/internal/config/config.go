@@ -0,0 +1,46 @@
+// services/inventory-service/internal/config/config.go
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// ReservationTTL is used when a Reserve call does not ask for its own TTL.
+	ReservationTTL time.Duration
+	// ExpirySweepInterval controls how often expired reservations are released.
+	ExpirySweepInterval time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:                getEnv("PORT", "8087"),
+		Environment:         os.Getenv("ENVIRONMENT"),
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+		ReservationTTL:      getDurationEnv("RESERVATION_TTL", 15*time.Minute),
+		ExpirySweepInterval: getDurationEnv("EXPIRY_SWEEP_INTERVAL", 30*time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
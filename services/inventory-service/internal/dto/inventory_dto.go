@@ -0,0 +1,77 @@
+package dto
+
+type CreateWarehouseRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type WarehouseResponse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at"`
+}
+
+type SetStockRequest struct {
+	SKU               string `json:"sku"`
+	WarehouseID       string `json:"warehouse_id"`
+	OnHand            int    `json:"on_hand"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+}
+
+type AdjustStockRequest struct {
+	SKU         string `json:"sku"`
+	WarehouseID string `json:"warehouse_id"`
+	Delta       int    `json:"delta"`
+}
+
+type WarehouseStockResponse struct {
+	WarehouseID       string `json:"warehouse_id"`
+	WarehouseCode     string `json:"warehouse_code"`
+	OnHand            int    `json:"on_hand"`
+	Reserved          int    `json:"reserved"`
+	Available         int    `json:"available"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+}
+
+type StockResponse struct {
+	SKU        string                    `json:"sku"`
+	OnHand     int                       `json:"on_hand"`
+	Reserved   int                       `json:"reserved"`
+	Available  int                       `json:"available"`
+	Warehouses []*WarehouseStockResponse `json:"warehouses"`
+}
+
+type ReserveLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type ReserveRequest struct {
+	Reference  string        `json:"reference"`
+	Lines      []ReserveLine `json:"lines"`
+	TTLSeconds int           `json:"ttl_seconds"`
+}
+
+type RestockRequest struct {
+	Reference            string        `json:"reference"`
+	ReservationReference string        `json:"reservation_reference"`
+	Lines                []ReserveLine `json:"lines"`
+}
+
+type ReservationItemResponse struct {
+	SKU         string `json:"sku"`
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type ReservationResponse struct {
+	ID        string                     `json:"id"`
+	Reference string                     `json:"reference"`
+	Status    string                     `json:"status"`
+	Items     []*ReservationItemResponse `json:"items"`
+	ExpiresAt string                     `json:"expires_at"`
+	CreatedAt string                     `json:"created_at"`
+	UpdatedAt string                     `json:"updated_at"`
+}
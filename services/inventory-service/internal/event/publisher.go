@@ -0,0 +1,48 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-micro.dev/v4/broker"
+)
+
+// TopicLowStock is published when the available quantity of a SKU in a
+// warehouse drops to or below its low stock threshold.
+const TopicLowStock = "inventory.stock.low"
+
+type LowStockEvent struct {
+	SKU         string    `json:"sku"`
+	WarehouseID string    `json:"warehouse_id"`
+	Available   int       `json:"available"`
+	Threshold   int       `json:"threshold"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// Publisher sends inventory events to other services.
+type Publisher interface {
+	PublishLowStock(evt *LowStockEvent) error
+}
+
+type brokerPublisher struct {
+	broker broker.Broker
+}
+
+func NewBrokerPublisher(b broker.Broker) Publisher {
+	return &brokerPublisher{broker: b}
+}
+
+func (p *brokerPublisher) PublishLowStock(evt *LowStockEvent) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	return p.broker.Publish(TopicLowStock, &broker.Message{
+		Header: map[string]string{
+			"Content-Type": "application/json",
+			"Event-Type":   TopicLowStock,
+		},
+		Body: body,
+	})
+}
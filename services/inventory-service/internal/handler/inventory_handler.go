@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "inventory-service"
+
+type InventoryHandler struct {
+	warehouseService service.WarehouseService
+	inventoryService service.InventoryService
+}
+
+func NewInventoryHandler(warehouseService service.WarehouseService, inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		warehouseService: warehouseService,
+		inventoryService: inventoryService,
+	}
+}
+
+func (h *InventoryHandler) CreateWarehouse(ctx context.Context, req *pb.CreateWarehouseRequest, rsp *pb.WarehouseResponse) error {
+	warehouse, err := h.warehouseService.CreateWarehouse(ctx, &dto.CreateWarehouseRequest{
+		Code: req.Code,
+		Name: req.Name,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Warehouse = toProtoWarehouse(warehouse)
+	return nil
+}
+
+func (h *InventoryHandler) ListWarehouses(ctx context.Context, req *pb.ListWarehousesRequest, rsp *pb.ListWarehousesResponse) error {
+	warehouses, err := h.warehouseService.ListWarehouses(ctx)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, warehouse := range warehouses {
+		rsp.Warehouses = append(rsp.Warehouses, toProtoWarehouse(warehouse))
+	}
+
+	return nil
+}
+
+func (h *InventoryHandler) SetStock(ctx context.Context, req *pb.SetStockRequest, rsp *pb.StockResponse) error {
+	stock, err := h.inventoryService.SetStock(ctx, &dto.SetStockRequest{
+		SKU:               req.Sku,
+		WarehouseID:       req.WarehouseId,
+		OnHand:            int(req.OnHand),
+		LowStockThreshold: int(req.LowStockThreshold),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Stock = toProtoStock(stock)
+	return nil
+}
+
+func (h *InventoryHandler) AdjustStock(ctx context.Context, req *pb.AdjustStockRequest, rsp *pb.StockResponse) error {
+	stock, err := h.inventoryService.AdjustStock(ctx, &dto.AdjustStockRequest{
+		SKU:         req.Sku,
+		WarehouseID: req.WarehouseId,
+		Delta:       int(req.Delta),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Stock = toProtoStock(stock)
+	return nil
+}
+
+func (h *InventoryHandler) GetStock(ctx context.Context, req *pb.GetStockRequest, rsp *pb.GetStockResponse) error {
+	items, err := h.inventoryService.GetStock(ctx, req.Skus)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, item := range items {
+		rsp.Items = append(rsp.Items, toProtoStock(item))
+	}
+
+	return nil
+}
+
+func (h *InventoryHandler) Reserve(ctx context.Context, req *pb.ReserveRequest, rsp *pb.ReservationResponse) error {
+	reserveReq := &dto.ReserveRequest{
+		Reference:  req.Reference,
+		TTLSeconds: int(req.TtlSeconds),
+	}
+	for _, line := range req.Lines {
+		reserveReq.Lines = append(reserveReq.Lines, dto.ReserveLine{
+			SKU:      line.Sku,
+			Quantity: int(line.Quantity),
+		})
+	}
+
+	reservation, err := h.inventoryService.Reserve(ctx, reserveReq)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reservation = toProtoReservation(reservation)
+	return nil
+}
+
+func (h *InventoryHandler) CommitReservation(ctx context.Context, req *pb.ReservationRequest, rsp *pb.ReservationResponse) error {
+	reservation, err := h.inventoryService.CommitReservation(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reservation = toProtoReservation(reservation)
+	return nil
+}
+
+func (h *InventoryHandler) ReleaseReservation(ctx context.Context, req *pb.ReservationRequest, rsp *pb.ReservationResponse) error {
+	reservation, err := h.inventoryService.ReleaseReservation(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reservation = toProtoReservation(reservation)
+	return nil
+}
+
+func (h *InventoryHandler) GetReservation(ctx context.Context, req *pb.ReservationRequest, rsp *pb.ReservationResponse) error {
+	reservation, err := h.inventoryService.GetReservation(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reservation = toProtoReservation(reservation)
+	return nil
+}
+
+func (h *InventoryHandler) Restock(ctx context.Context, req *pb.RestockRequest, rsp *pb.RestockResponse) error {
+	restockReq := &dto.RestockRequest{
+		Reference:            req.Reference,
+		ReservationReference: req.ReservationReference,
+	}
+	for _, line := range req.Lines {
+		restockReq.Lines = append(restockReq.Lines, dto.ReserveLine{
+			SKU:      line.Sku,
+			Quantity: int(line.Quantity),
+		})
+	}
+
+	applied, err := h.inventoryService.Restock(ctx, restockReq)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Applied = applied
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrWarehouseNotFound),
+		errors.Is(err, repository.ErrStockNotFound),
+		errors.Is(err, repository.ErrReservationNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrWarehouseExists),
+		errors.Is(err, repository.ErrInsufficientStock),
+		errors.Is(err, repository.ErrStockBelowReserved),
+		errors.Is(err, repository.ErrNotReserved):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrReservationNotActive),
+		errors.Is(err, repository.ErrReservationExpired),
+		errors.Is(err, repository.ErrReservationNotUsed):
+		return microerrors.New(serviceID, err.Error(), http.StatusPreconditionFailed)
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoWarehouse(warehouse *dto.WarehouseResponse) *pb.Warehouse {
+	return &pb.Warehouse{
+		Id:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		IsActive:  warehouse.IsActive,
+		CreatedAt: warehouse.CreatedAt,
+	}
+}
+
+func toProtoStock(stock *dto.StockResponse) *pb.Stock {
+	resp := &pb.Stock{
+		Sku:       stock.SKU,
+		OnHand:    int32(stock.OnHand),
+		Reserved:  int32(stock.Reserved),
+		Available: int32(stock.Available),
+	}
+
+	for _, w := range stock.Warehouses {
+		resp.Warehouses = append(resp.Warehouses, &pb.WarehouseStock{
+			WarehouseId:       w.WarehouseID,
+			WarehouseCode:     w.WarehouseCode,
+			OnHand:            int32(w.OnHand),
+			Reserved:          int32(w.Reserved),
+			Available:         int32(w.Available),
+			LowStockThreshold: int32(w.LowStockThreshold),
+		})
+	}
+
+	return resp
+}
+
+func toProtoReservation(reservation *dto.ReservationResponse) *pb.Reservation {
+	resp := &pb.Reservation{
+		Id:        reservation.ID,
+		Reference: reservation.Reference,
+		Status:    reservation.Status,
+		ExpiresAt: reservation.ExpiresAt,
+		CreatedAt: reservation.CreatedAt,
+		UpdatedAt: reservation.UpdatedAt,
+	}
+
+	for _, item := range reservation.Items {
+		resp.Items = append(resp.Items, &pb.ReservationItem{
+			Sku:         item.SKU,
+			WarehouseId: item.WarehouseID,
+			Quantity:    int32(item.Quantity),
+		})
+	}
+
+	return resp
+}
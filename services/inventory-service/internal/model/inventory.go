@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+type Warehouse struct {
+	ID        string    `db:"id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type StockLevel struct {
+	SKU               string    `db:"sku"`
+	WarehouseID       string    `db:"warehouse_id"`
+	WarehouseCode     string    `db:"warehouse_code"`
+	OnHand            int       `db:"on_hand"`
+	Reserved          int       `db:"reserved"`
+	LowStockThreshold int       `db:"low_stock_threshold"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+func (s *StockLevel) Available() int {
+	return s.OnHand - s.Reserved
+}
+
+type Reservation struct {
+	ID        string            `db:"id"`
+	Reference string            `db:"reference"`
+	Status    ReservationStatus `db:"status"`
+	Items     []*ReservationItem
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type ReservationItem struct {
+	SKU         string `db:"sku"`
+	WarehouseID string `db:"warehouse_id"`
+	Quantity    int    `db:"quantity"`
+}
+
+// ReserveLine is one SKU and quantity requested by a caller; the repository
+// decides which warehouses it is taken from.
+type ReserveLine struct {
+	SKU      string
+	Quantity int
+}
+
+// StockChange records the availability of a stock row before and after a
+// write, so callers can tell when it crossed its low stock threshold.
+type StockChange struct {
+	SKU               string
+	WarehouseID       string
+	AvailableBefore   int
+	AvailableAfter    int
+	LowStockThreshold int
+}
+
+func (c *StockChange) CrossedLowStock() bool {
+	return c.AvailableBefore > c.LowStockThreshold && c.AvailableAfter <= c.LowStockThreshold
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+)
+
+var (
+	ErrStockNotFound        = errors.New("stock not found")
+	ErrStockBelowReserved   = errors.New("on hand stock cannot drop below reserved stock")
+	ErrInsufficientStock    = errors.New("insufficient stock")
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrReservationExpired   = errors.New("reservation has expired")
+	ErrDuplicateReservation = errors.New("reservation reference already used")
+	ErrReservationNotUsed   = errors.New("reservation was never committed")
+	ErrNotReserved          = errors.New("more stock returned than the reservation held")
+)
+
+// InventoryRepository owns stock levels and reservations. Every write that
+// touches stock runs in a single transaction with the affected rows locked,
+// so concurrent checkouts can never reserve more than is on hand.
+type InventoryRepository interface {
+	// SetStock overwrites the on hand quantity and low stock threshold of a
+	// SKU in a warehouse, creating the row if needed.
+	SetStock(ctx context.Context, level *model.StockLevel) (*model.StockChange, error)
+	// AdjustStock adds delta to the on hand quantity.
+	AdjustStock(ctx context.Context, sku, warehouseID string, delta int) (*model.StockChange, error)
+	ListStock(ctx context.Context, skus []string) ([]*model.StockLevel, error)
+
+	// Reserve inserts the reservation and holds stock for every line, spread
+	// over as few active warehouses as possible. It fails with
+	// ErrInsufficientStock without reserving anything if any line cannot be
+	// met, and with ErrDuplicateReservation if the reference was used before.
+	Reserve(ctx context.Context, reservation *model.Reservation, lines []model.ReserveLine) ([]*model.StockChange, error)
+	// CommitReservation turns held stock into a permanent deduction. An
+	// active reservation that is past its expiry is released instead and
+	// ErrReservationExpired is returned.
+	CommitReservation(ctx context.Context, id string, now time.Time) (*model.Reservation, error)
+	// ReleaseReservation gives held stock back.
+	ReleaseReservation(ctx context.Context, id string) (*model.Reservation, error)
+	// ExpireReservations releases up to limit active reservations that
+	// expired before now and returns how many were released.
+	ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error)
+	GetReservation(ctx context.Context, id string) (*model.Reservation, error)
+	GetReservationByReference(ctx context.Context, reference string) (*model.Reservation, error)
+
+	// Restock puts returned goods back on hand in the warehouses the
+	// committed reservation took them from. reference makes it idempotent:
+	// a repeated call changes nothing and reports false. It fails with
+	// ErrNotReserved if a line exceeds what the reservation held less what
+	// earlier restocks of it returned.
+	Restock(ctx context.Context, reference, reservationReference string, lines []model.ReserveLine) ([]*model.StockChange, bool, error)
+}
@@ -0,0 +1,685 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const reservationColumns = `id, reference, status, expires_at, created_at, updated_at`
+
+type inventoryRepository struct {
+	db *sql.DB
+}
+
+func NewInventoryRepository(db *sql.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *inventoryRepository) SetStock(ctx context.Context, level *model.StockLevel) (*model.StockChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	change := &model.StockChange{
+		SKU:               level.SKU,
+		WarehouseID:       level.WarehouseID,
+		LowStockThreshold: level.LowStockThreshold,
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT on_hand - reserved FROM stock_levels
+		WHERE sku = $1 AND warehouse_id = $2
+		FOR UPDATE
+	`, level.SKU, level.WarehouseID).Scan(&change.AvailableBefore)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO stock_levels (sku, warehouse_id, on_hand, low_stock_threshold)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sku, warehouse_id) DO UPDATE
+		SET on_hand = EXCLUDED.on_hand,
+			low_stock_threshold = EXCLUDED.low_stock_threshold,
+			updated_at = NOW()
+		RETURNING reserved, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		level.SKU,
+		level.WarehouseID,
+		level.OnHand,
+		level.LowStockThreshold,
+	).Scan(&level.Reserved, &level.UpdatedAt)
+	if err != nil {
+		return nil, translateCheckViolation(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	change.AvailableAfter = level.Available()
+	return change, nil
+}
+
+func (r *inventoryRepository) AdjustStock(ctx context.Context, sku, warehouseID string, delta int) (*model.StockChange, error) {
+	return addOnHand(ctx, r.db, sku, warehouseID, delta)
+}
+
+func (r *inventoryRepository) ListStock(ctx context.Context, skus []string) ([]*model.StockLevel, error) {
+	query := `
+		SELECT sl.sku, sl.warehouse_id, w.code, sl.on_hand, sl.reserved, sl.low_stock_threshold, sl.updated_at
+		FROM stock_levels sl
+		JOIN warehouses w ON w.id = sl.warehouse_id
+		WHERE sl.sku = ANY($1)
+		ORDER BY sl.sku ASC, w.code ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(skus))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*model.StockLevel
+	for rows.Next() {
+		var level model.StockLevel
+		err := rows.Scan(
+			&level.SKU,
+			&level.WarehouseID,
+			&level.WarehouseCode,
+			&level.OnHand,
+			&level.Reserved,
+			&level.LowStockThreshold,
+			&level.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, &level)
+	}
+
+	return levels, rows.Err()
+}
+
+func (r *inventoryRepository) Reserve(ctx context.Context, reservation *model.Reservation, lines []model.ReserveLine) ([]*model.StockChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO reservations (reference, status, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (reference) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`, reservation.Reference, model.ReservationStatusActive, reservation.ExpiresAt,
+	).Scan(&reservation.ID, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrDuplicateReservation
+	}
+	if err != nil {
+		return nil, err
+	}
+	reservation.Status = model.ReservationStatusActive
+	reservation.Items = nil
+
+	// Lock rows in SKU order so two checkouts sharing SKUs cannot deadlock.
+	sorted := make([]model.ReserveLine, len(lines))
+	copy(sorted, lines)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].SKU < sorted[j].SKU })
+
+	var changes []*model.StockChange
+	for _, line := range sorted {
+		levels, err := lockStockForSKU(ctx, tx, line.SKU)
+		if err != nil {
+			return nil, err
+		}
+
+		available := 0
+		for _, level := range levels {
+			available += level.Available()
+		}
+		if available < line.Quantity {
+			return nil, fmt.Errorf("%w: sku %s has %d available, %d requested",
+				ErrInsufficientStock, line.SKU, available, line.Quantity)
+		}
+
+		// Take from the fullest warehouse first to keep shipments together.
+		sort.SliceStable(levels, func(i, j int) bool {
+			return levels[i].Available() > levels[j].Available()
+		})
+
+		remaining := line.Quantity
+		for _, level := range levels {
+			if remaining == 0 {
+				break
+			}
+
+			take := level.Available()
+			if take <= 0 {
+				continue
+			}
+			if take > remaining {
+				take = remaining
+			}
+
+			_, err := tx.ExecContext(ctx, `
+				UPDATE stock_levels SET reserved = reserved + $3, updated_at = NOW()
+				WHERE sku = $1 AND warehouse_id = $2
+			`, level.SKU, level.WarehouseID, take)
+			if err != nil {
+				return nil, translateCheckViolation(err)
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO reservation_items (reservation_id, sku, warehouse_id, quantity)
+				VALUES ($1, $2, $3, $4)
+			`, reservation.ID, level.SKU, level.WarehouseID, take)
+			if err != nil {
+				return nil, err
+			}
+
+			reservation.Items = append(reservation.Items, &model.ReservationItem{
+				SKU:         level.SKU,
+				WarehouseID: level.WarehouseID,
+				Quantity:    take,
+			})
+			changes = append(changes, &model.StockChange{
+				SKU:               level.SKU,
+				WarehouseID:       level.WarehouseID,
+				AvailableBefore:   level.Available(),
+				AvailableAfter:    level.Available() - take,
+				LowStockThreshold: level.LowStockThreshold,
+			})
+
+			remaining -= take
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *inventoryRepository) CommitReservation(ctx context.Context, id string, now time.Time) (*model.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := lockReservation(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case model.ReservationStatusCommitted:
+		return reservation, nil
+	case model.ReservationStatusActive:
+	default:
+		return nil, ErrReservationNotActive
+	}
+
+	if !reservation.ExpiresAt.After(now) {
+		if err := releaseItems(ctx, tx, reservation, model.ReservationStatusExpired); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrReservationExpired
+	}
+
+	for _, item := range reservation.Items {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE stock_levels
+			SET on_hand = on_hand - $3, reserved = reserved - $3, updated_at = NOW()
+			WHERE sku = $1 AND warehouse_id = $2
+		`, item.SKU, item.WarehouseID, item.Quantity)
+		if err != nil {
+			return nil, translateCheckViolation(err)
+		}
+	}
+
+	if err := setReservationStatus(ctx, tx, reservation, model.ReservationStatusCommitted); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (r *inventoryRepository) ReleaseReservation(ctx context.Context, id string) (*model.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := lockReservation(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case model.ReservationStatusReleased, model.ReservationStatusExpired:
+		return reservation, nil
+	case model.ReservationStatusCommitted:
+		return nil, ErrReservationNotActive
+	}
+
+	if err := releaseItems(ctx, tx, reservation, model.ReservationStatusReleased); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (r *inventoryRepository) ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED lets several replicas sweep at once, and keeps the sweeper
+	// from waiting on a reservation that is being committed right now.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+reservationColumns+`
+		FROM reservations
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at ASC
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`, model.ReservationStatusActive, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired []*model.Reservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, reservation)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Release the items of the whole batch in (sku, warehouse) order, the
+	// lock order of Reserve. Going reservation by reservation would lock
+	// rows out of that order and could deadlock against a checkout.
+	var items []*model.ReservationItem
+	for _, reservation := range expired {
+		if reservation.Items, err = listItems(ctx, tx, reservation.ID); err != nil {
+			return 0, err
+		}
+		items = append(items, reservation.Items...)
+	}
+	sortItems(items)
+
+	if err := releaseStock(ctx, tx, items); err != nil {
+		return 0, err
+	}
+	for _, reservation := range expired {
+		if err := setReservationStatus(ctx, tx, reservation, model.ReservationStatusExpired); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(expired), nil
+}
+
+func (r *inventoryRepository) GetReservation(ctx context.Context, id string) (*model.Reservation, error) {
+	return r.getReservation(ctx, `SELECT `+reservationColumns+` FROM reservations WHERE id::text = $1`, id)
+}
+
+func (r *inventoryRepository) GetReservationByReference(ctx context.Context, reference string) (*model.Reservation, error) {
+	return r.getReservation(ctx, `SELECT `+reservationColumns+` FROM reservations WHERE reference = $1`, reference)
+}
+
+func (r *inventoryRepository) getReservation(ctx context.Context, query string, arg interface{}) (*model.Reservation, error) {
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Items, err = listItems(ctx, r.db, reservation.ID); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (r *inventoryRepository) Restock(ctx context.Context, reference, reservationReference string, lines []model.ReserveLine) ([]*model.StockChange, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := scanReservation(tx.QueryRowContext(ctx,
+		`SELECT `+reservationColumns+` FROM reservations WHERE reference = $1`, reservationReference,
+	))
+	if err == sql.ErrNoRows {
+		return nil, false, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if reservation.Status != model.ReservationStatusCommitted {
+		return nil, false, ErrReservationNotUsed
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO restocks (reference, reservation_id)
+		VALUES ($1, $2)
+		ON CONFLICT (reference) DO NOTHING
+	`, reference, reservation.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, false, nil
+	}
+
+	items, err := lockRestockableItems(ctx, tx, reservation.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Lock rows in SKU order, as Reserve does.
+	sorted := make([]model.ReserveLine, len(lines))
+	copy(sorted, lines)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].SKU < sorted[j].SKU })
+
+	var changes []*model.StockChange
+	for _, line := range sorted {
+		remaining := line.Quantity
+		for _, item := range items {
+			if item.SKU != line.SKU || item.Quantity == 0 || remaining == 0 {
+				continue
+			}
+
+			quantity := item.Quantity
+			if quantity > remaining {
+				quantity = remaining
+			}
+
+			_, err := tx.ExecContext(ctx, `
+				UPDATE reservation_items SET restocked = restocked + $2 WHERE id = $1
+			`, item.id, quantity)
+			if err != nil {
+				return nil, false, err
+			}
+			item.Quantity -= quantity
+
+			change, err := addOnHand(ctx, tx, item.SKU, item.WarehouseID, quantity)
+			if err != nil {
+				return nil, false, err
+			}
+			changes = append(changes, change)
+			remaining -= quantity
+		}
+
+		if remaining > 0 {
+			return nil, false, fmt.Errorf("%w: %s", ErrNotReserved, line.SKU)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return changes, true, nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// addOnHand adds delta to the on hand quantity of a stock row, creating it
+// if needed.
+func addOnHand(ctx context.Context, q rowQueryer, sku, warehouseID string, delta int) (*model.StockChange, error) {
+	query := `
+		INSERT INTO stock_levels (sku, warehouse_id, on_hand)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (sku, warehouse_id) DO UPDATE
+		SET on_hand = stock_levels.on_hand + EXCLUDED.on_hand, updated_at = NOW()
+		RETURNING on_hand - reserved, low_stock_threshold
+	`
+
+	change := &model.StockChange{SKU: sku, WarehouseID: warehouseID}
+
+	err := q.QueryRowContext(ctx, query, sku, warehouseID, delta).
+		Scan(&change.AvailableAfter, &change.LowStockThreshold)
+	if err != nil {
+		return nil, translateCheckViolation(err)
+	}
+
+	change.AvailableBefore = change.AvailableAfter - delta
+	return change, nil
+}
+
+func lockStockForSKU(ctx context.Context, tx *sql.Tx, sku string) ([]*model.StockLevel, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT sl.sku, sl.warehouse_id, sl.on_hand, sl.reserved, sl.low_stock_threshold
+		FROM stock_levels sl
+		JOIN warehouses w ON w.id = sl.warehouse_id
+		WHERE sl.sku = $1 AND w.is_active = true
+		ORDER BY sl.warehouse_id ASC
+		FOR UPDATE OF sl
+	`, sku)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*model.StockLevel
+	for rows.Next() {
+		var level model.StockLevel
+		err := rows.Scan(&level.SKU, &level.WarehouseID, &level.OnHand, &level.Reserved, &level.LowStockThreshold)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, &level)
+	}
+
+	return levels, rows.Err()
+}
+
+func lockReservation(ctx context.Context, tx *sql.Tx, id string) (*model.Reservation, error) {
+	reservation, err := scanReservation(tx.QueryRowContext(ctx,
+		`SELECT `+reservationColumns+` FROM reservations WHERE id::text = $1 FOR UPDATE`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Items, err = listItems(ctx, tx, reservation.ID); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// releaseItems returns the held stock of an active reservation and moves it
+// to status. Items are listed in SKU order, matching the lock order of Reserve.
+func releaseItems(ctx context.Context, tx *sql.Tx, reservation *model.Reservation, status model.ReservationStatus) error {
+	if err := releaseStock(ctx, tx, reservation.Items); err != nil {
+		return err
+	}
+
+	return setReservationStatus(ctx, tx, reservation, status)
+}
+
+// releaseStock gives the held quantity of items back, in the order given.
+func releaseStock(ctx context.Context, tx *sql.Tx, items []*model.ReservationItem) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE stock_levels SET reserved = reserved - $3, updated_at = NOW()
+			WHERE sku = $1 AND warehouse_id = $2
+		`, item.SKU, item.WarehouseID, item.Quantity)
+		if err != nil {
+			return translateCheckViolation(err)
+		}
+	}
+
+	return nil
+}
+
+// sortItems orders items by SKU, then warehouse, the order Reserve locks
+// stock rows in.
+func sortItems(items []*model.ReservationItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].SKU != items[j].SKU {
+			return items[i].SKU < items[j].SKU
+		}
+		return items[i].WarehouseID < items[j].WarehouseID
+	})
+}
+
+func setReservationStatus(ctx context.Context, tx *sql.Tx, reservation *model.Reservation, status model.ReservationStatus) error {
+	err := tx.QueryRowContext(ctx,
+		`UPDATE reservations SET status = $2, updated_at = NOW() WHERE id = $1 RETURNING updated_at`,
+		reservation.ID, status,
+	).Scan(&reservation.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	reservation.Status = status
+	return nil
+}
+
+// restockableItem is a reservation item with Quantity set to what has not
+// been restocked yet.
+type restockableItem struct {
+	model.ReservationItem
+	id int64
+}
+
+// lockRestockableItems locks the items of a reservation, so concurrent
+// restocks of it cannot together return more than it held.
+func lockRestockableItems(ctx context.Context, q queryer, reservationID string) ([]*restockableItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, sku, warehouse_id, quantity - restocked
+		FROM reservation_items
+		WHERE reservation_id = $1
+		ORDER BY sku ASC, warehouse_id ASC
+		FOR UPDATE
+	`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*restockableItem
+	for rows.Next() {
+		var item restockableItem
+		if err := rows.Scan(&item.id, &item.SKU, &item.WarehouseID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func listItems(ctx context.Context, q queryer, reservationID string) ([]*model.ReservationItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT sku, warehouse_id, quantity
+		FROM reservation_items
+		WHERE reservation_id = $1
+		ORDER BY sku ASC, warehouse_id ASC
+	`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.ReservationItem
+	for rows.Next() {
+		var item model.ReservationItem
+		if err := rows.Scan(&item.SKU, &item.WarehouseID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func scanReservation(row rowScanner) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.Reference,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+func translateCheckViolation(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23514" {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "stock_levels_non_negative", "stock_levels_no_oversell":
+		return ErrStockBelowReserved
+	default:
+		return err
+	}
+}
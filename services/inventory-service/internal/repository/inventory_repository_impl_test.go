@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+
+	_ "github.com/lib/pq"
+)
+
+// openTestDB migrates a fresh schema in the database named by
+// INVENTORY_TEST_DATABASE_URL, and skips the test when it is not set.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("INVENTORY_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("INVENTORY_TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("apply %s: %v", path, err)
+		}
+	}
+
+	return db
+}
+
+// stockFixture creates warehouses with the given codes and puts onHand of
+// each SKU in every one of them.
+func stockFixture(t *testing.T, db *sql.DB, codes []string, skus []string, onHand int) []*model.Warehouse {
+	t.Helper()
+	ctx := context.Background()
+
+	warehouses := NewWarehouseRepository(db)
+	inventory := NewInventoryRepository(db)
+
+	var created []*model.Warehouse
+	for _, code := range codes {
+		warehouse := &model.Warehouse{Code: code, Name: code}
+		if err := warehouses.Create(ctx, warehouse); err != nil {
+			t.Fatalf("Create() warehouse error = %v", err)
+		}
+		created = append(created, warehouse)
+
+		for _, sku := range skus {
+			if _, err := inventory.SetStock(ctx, &model.StockLevel{SKU: sku, WarehouseID: warehouse.ID, OnHand: onHand}); err != nil {
+				t.Fatalf("SetStock() error = %v", err)
+			}
+		}
+	}
+
+	return created
+}
+
+func stockOf(t *testing.T, repo InventoryRepository, sku string) (onHand, reserved int) {
+	t.Helper()
+
+	levels, err := repo.ListStock(context.Background(), []string{sku})
+	if err != nil {
+		t.Fatalf("ListStock() error = %v", err)
+	}
+	for _, level := range levels {
+		onHand += level.OnHand
+		reserved += level.Reserved
+	}
+	return onHand, reserved
+}
+
+func TestReserveCommitRelease(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+	stockFixture(t, db, []string{"WH-A", "WH-B"}, []string{"SKU-1"}, 5)
+
+	held := &model.Reservation{Reference: "checkout-1", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := repo.Reserve(ctx, held, []model.ReserveLine{{SKU: "SKU-1", Quantity: 7}}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if len(held.Items) != 2 || held.Items[0].Quantity != 5 || held.Items[1].Quantity != 2 {
+		t.Errorf("reservation items = %+v, want 5 from the fullest warehouse and 2 from the other", held.Items)
+	}
+	if onHand, reserved := stockOf(t, repo, "SKU-1"); onHand != 10 || reserved != 7 {
+		t.Fatalf("stock = %d on hand, %d reserved, want 10 and 7", onHand, reserved)
+	}
+
+	duplicate := &model.Reservation{Reference: "checkout-1", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := repo.Reserve(ctx, duplicate, []model.ReserveLine{{SKU: "SKU-1", Quantity: 1}}); !errors.Is(err, ErrDuplicateReservation) {
+		t.Errorf("Reserve() with a used reference error = %v, want %v", err, ErrDuplicateReservation)
+	}
+
+	tooMuch := &model.Reservation{Reference: "checkout-2", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := repo.Reserve(ctx, tooMuch, []model.ReserveLine{{SKU: "SKU-1", Quantity: 4}}); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Reserve() past availability error = %v, want %v", err, ErrInsufficientStock)
+	}
+	if _, err := repo.GetReservationByReference(ctx, "checkout-2"); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("failed Reserve() left a reservation behind: %v", err)
+	}
+
+	committed, err := repo.CommitReservation(ctx, held.ID, time.Now())
+	if err != nil {
+		t.Fatalf("CommitReservation() error = %v", err)
+	}
+	if committed.Status != model.ReservationStatusCommitted {
+		t.Errorf("status = %s, want committed", committed.Status)
+	}
+	if onHand, reserved := stockOf(t, repo, "SKU-1"); onHand != 3 || reserved != 0 {
+		t.Errorf("stock after commit = %d on hand, %d reserved, want 3 and 0", onHand, reserved)
+	}
+	if _, err := repo.ReleaseReservation(ctx, held.ID); !errors.Is(err, ErrReservationNotActive) {
+		t.Errorf("ReleaseReservation() of a committed reservation error = %v, want %v", err, ErrReservationNotActive)
+	}
+
+	released := &model.Reservation{Reference: "checkout-3", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := repo.Reserve(ctx, released, []model.ReserveLine{{SKU: "SKU-1", Quantity: 3}}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if _, err := repo.ReleaseReservation(ctx, released.ID); err != nil {
+		t.Fatalf("ReleaseReservation() error = %v", err)
+	}
+	if _, err := repo.ReleaseReservation(ctx, released.ID); err != nil {
+		t.Errorf("second ReleaseReservation() error = %v, want it to be a no-op", err)
+	}
+	if onHand, reserved := stockOf(t, repo, "SKU-1"); onHand != 3 || reserved != 0 {
+		t.Errorf("stock after release = %d on hand, %d reserved, want 3 and 0", onHand, reserved)
+	}
+}
+
+func TestCommitExpiredReservation(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+	stockFixture(t, db, []string{"WH-A"}, []string{"SKU-1"}, 5)
+
+	reservation := &model.Reservation{Reference: "checkout-1", ExpiresAt: time.Now().Add(time.Minute)}
+	if _, err := repo.Reserve(ctx, reservation, []model.ReserveLine{{SKU: "SKU-1", Quantity: 2}}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	if _, err := repo.CommitReservation(ctx, reservation.ID, time.Now().Add(time.Hour)); !errors.Is(err, ErrReservationExpired) {
+		t.Fatalf("CommitReservation() error = %v, want %v", err, ErrReservationExpired)
+	}
+	if onHand, reserved := stockOf(t, repo, "SKU-1"); onHand != 5 || reserved != 0 {
+		t.Errorf("stock = %d on hand, %d reserved, want the hold released", onHand, reserved)
+	}
+}
+
+func TestExpireReservations(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+	stockFixture(t, db, []string{"WH-A", "WH-B"}, []string{"SKU-1", "SKU-2"}, 5)
+
+	now := time.Now()
+	lines := [][]model.ReserveLine{
+		{{SKU: "SKU-2", Quantity: 6}, {SKU: "SKU-1", Quantity: 1}},
+		{{SKU: "SKU-1", Quantity: 3}},
+		{{SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-2", Quantity: 2}},
+	}
+	expiries := []time.Time{now.Add(-2 * time.Minute), now.Add(-time.Minute), now.Add(time.Hour)}
+	for i := range lines {
+		reservation := &model.Reservation{Reference: fmt.Sprintf("checkout-%d", i), ExpiresAt: expiries[i]}
+		if _, err := repo.Reserve(ctx, reservation, lines[i]); err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+	}
+
+	released, err := repo.ExpireReservations(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("ExpireReservations() error = %v", err)
+	}
+	if released != 2 {
+		t.Errorf("ExpireReservations() = %d, want 2", released)
+	}
+
+	for i, want := range []model.ReservationStatus{model.ReservationStatusExpired, model.ReservationStatusExpired, model.ReservationStatusActive} {
+		reservation, err := repo.GetReservationByReference(ctx, fmt.Sprintf("checkout-%d", i))
+		if err != nil {
+			t.Fatalf("GetReservationByReference() error = %v", err)
+		}
+		if reservation.Status != want {
+			t.Errorf("checkout-%d status = %s, want %s", i, reservation.Status, want)
+		}
+	}
+	if _, reserved := stockOf(t, repo, "SKU-1"); reserved != 2 {
+		t.Errorf("SKU-1 reserved = %d, want only the live reservation's 2", reserved)
+	}
+	if _, reserved := stockOf(t, repo, "SKU-2"); reserved != 2 {
+		t.Errorf("SKU-2 reserved = %d, want only the live reservation's 2", reserved)
+	}
+
+	if released, err := repo.ExpireReservations(ctx, now, 10); err != nil || released != 0 {
+		t.Errorf("second ExpireReservations() = %d, %v, want 0, nil", released, err)
+	}
+}
+
+// TestExpireWhileReserving sweeps expired reservations while checkouts reserve
+// the same SKUs in the opposite order the reservations were made in. Both sides
+// must lock stock rows in the same order, or Postgres aborts one of them with
+// a deadlock.
+func TestExpireWhileReserving(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+
+	skus := []string{"SKU-1", "SKU-2", "SKU-3", "SKU-4"}
+	stockFixture(t, db, []string{"WH-A", "WH-B"}, skus, 1000)
+
+	past := time.Now().Add(-time.Minute)
+	for i := 0; i < 50; i++ {
+		reservation := &model.Reservation{Reference: fmt.Sprintf("old-%d", i), ExpiresAt: past.Add(time.Duration(i) * time.Millisecond)}
+		line := []model.ReserveLine{{SKU: skus[len(skus)-1-i%len(skus)], Quantity: 1}}
+		if _, err := repo.Reserve(ctx, reservation, line); err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 21)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := repo.ExpireReservations(ctx, time.Now(), 100); err != nil {
+			errs <- fmt.Errorf("ExpireReservations() error = %w", err)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var lines []model.ReserveLine
+			for _, sku := range skus {
+				lines = append(lines, model.ReserveLine{SKU: sku, Quantity: 1})
+			}
+			reservation := &model.Reservation{Reference: fmt.Sprintf("new-%d", i), ExpiresAt: time.Now().Add(time.Hour)}
+			if _, err := repo.Reserve(ctx, reservation, lines); err != nil {
+				errs <- fmt.Errorf("Reserve() error = %w", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentReservesDoNotOversell(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+	stockFixture(t, db, []string{"WH-A", "WH-B"}, []string{"SKU-1", "SKU-2"}, 5)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+		refused  int
+	)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Alternate the line order; Reserve must lock rows in one order anyway.
+			lines := []model.ReserveLine{{SKU: "SKU-1", Quantity: 1}, {SKU: "SKU-2", Quantity: 1}}
+			if i%2 == 1 {
+				lines[0], lines[1] = lines[1], lines[0]
+			}
+			reservation := &model.Reservation{Reference: fmt.Sprintf("checkout-%d", i), ExpiresAt: time.Now().Add(time.Hour)}
+			_, err := repo.Reserve(ctx, reservation, lines)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				reserved++
+			case errors.Is(err, ErrInsufficientStock):
+				refused++
+			default:
+				t.Errorf("Reserve() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if reserved != 10 || refused != 20 {
+		t.Errorf("reserved %d and refused %d, want 10 and 20", reserved, refused)
+	}
+	for _, sku := range []string{"SKU-1", "SKU-2"} {
+		if onHand, held := stockOf(t, repo, sku); held != onHand {
+			t.Errorf("%s reserved = %d of %d on hand, want all of it and no more", sku, held, onHand)
+		}
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	items := []*model.ReservationItem{
+		{SKU: "SKU-2", WarehouseID: "b", Quantity: 1},
+		{SKU: "SKU-1", WarehouseID: "b", Quantity: 2},
+		{SKU: "SKU-2", WarehouseID: "a", Quantity: 3},
+		{SKU: "SKU-1", WarehouseID: "a", Quantity: 4},
+		{SKU: "SKU-1", WarehouseID: "a", Quantity: 5},
+	}
+
+	sortItems(items)
+
+	var got []string
+	for _, item := range items {
+		got = append(got, fmt.Sprintf("%s/%s/%d", item.SKU, item.WarehouseID, item.Quantity))
+	}
+	want := "SKU-1/a/4 SKU-1/a/5 SKU-1/b/2 SKU-2/a/3 SKU-2/b/1"
+	if strings.Join(got, " ") != want {
+		t.Errorf("sortItems() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+)
+
+var (
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrWarehouseExists   = errors.New("warehouse code already exists")
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *model.Warehouse) error
+	GetByID(ctx context.Context, id string) (*model.Warehouse, error)
+	List(ctx context.Context) ([]*model.Warehouse, error)
+}
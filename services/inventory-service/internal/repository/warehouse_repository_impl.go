@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+	"github.com/lib/pq"
+)
+
+type warehouseRepository struct {
+	db *sql.DB
+}
+
+func NewWarehouseRepository(db *sql.DB) WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	query := `
+		INSERT INTO warehouses (code, name)
+		VALUES ($1, $2)
+		RETURNING id, is_active, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, warehouse.Code, warehouse.Name).
+		Scan(&warehouse.ID, &warehouse.IsActive, &warehouse.CreatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrWarehouseExists
+	}
+
+	return err
+}
+
+func (r *warehouseRepository) GetByID(ctx context.Context, id string) (*model.Warehouse, error) {
+	query := `SELECT id, code, name, is_active, created_at FROM warehouses WHERE id::text = $1`
+
+	var warehouse model.Warehouse
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&warehouse.ID,
+		&warehouse.Code,
+		&warehouse.Name,
+		&warehouse.IsActive,
+		&warehouse.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrWarehouseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) List(ctx context.Context) ([]*model.Warehouse, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, code, name, is_active, created_at FROM warehouses ORDER BY code ASC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warehouses []*model.Warehouse
+	for rows.Next() {
+		var warehouse model.Warehouse
+		err := rows.Scan(
+			&warehouse.ID,
+			&warehouse.Code,
+			&warehouse.Name,
+			&warehouse.IsActive,
+			&warehouse.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, &warehouse)
+	}
+
+	return warehouses, rows.Err()
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/dto"
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// WarehouseService manages the warehouses stock is kept in
+type WarehouseService interface {
+	CreateWarehouse(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error)
+	ListWarehouses(ctx context.Context) ([]*dto.WarehouseResponse, error)
+}
+
+// InventoryService tracks stock per SKU and holds it for checkouts
+type InventoryService interface {
+	SetStock(ctx context.Context, req *dto.SetStockRequest) (*dto.StockResponse, error)
+	AdjustStock(ctx context.Context, req *dto.AdjustStockRequest) (*dto.StockResponse, error)
+	GetStock(ctx context.Context, skus []string) ([]*dto.StockResponse, error)
+
+	// Reserve holds stock for all lines or none of them. Calling it again
+	// with the same reference returns the existing reservation.
+	Reserve(ctx context.Context, req *dto.ReserveRequest) (*dto.ReservationResponse, error)
+	CommitReservation(ctx context.Context, id string) (*dto.ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, id string) (*dto.ReservationResponse, error)
+	GetReservation(ctx context.Context, id string) (*dto.ReservationResponse, error)
+
+	// Restock returns goods sold under the reservation with the given
+	// reference to stock. Calling it again with the same reference has no
+	// further effect and reports false.
+	Restock(ctx context.Context, req *dto.RestockRequest) (bool, error)
+
+	// RunExpiry releases expired reservations until ctx is cancelled.
+	RunExpiry(ctx context.Context)
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/event"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/repository"
+
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	maxSKULength       = 64
+	maxSKUsPerRequest  = 100
+	maxReserveLines    = 100
+	maxReservationTTL  = 24 * time.Hour
+	expiryBatchSize    = 100
+	maxReferenceLength = 100
+)
+
+type inventoryServiceImpl struct {
+	inventoryRepo  repository.InventoryRepository
+	warehouseRepo  repository.WarehouseRepository
+	publisher      event.Publisher
+	reservationTTL time.Duration
+	sweepInterval  time.Duration
+}
+
+func NewInventoryService(
+	inventoryRepo repository.InventoryRepository,
+	warehouseRepo repository.WarehouseRepository,
+	publisher event.Publisher,
+	reservationTTL time.Duration,
+	sweepInterval time.Duration,
+) InventoryService {
+	return &inventoryServiceImpl{
+		inventoryRepo:  inventoryRepo,
+		warehouseRepo:  warehouseRepo,
+		publisher:      publisher,
+		reservationTTL: reservationTTL,
+		sweepInterval:  sweepInterval,
+	}
+}
+
+// SetStock implements InventoryService.
+func (s *inventoryServiceImpl) SetStock(ctx context.Context, req *dto.SetStockRequest) (*dto.StockResponse, error) {
+	sku := strings.TrimSpace(req.SKU)
+
+	invalid := map[string]string{}
+	validateSKU(invalid, "sku", sku)
+	if req.OnHand < 0 {
+		invalid["on_hand"] = "must not be negative"
+	}
+	if req.LowStockThreshold < 0 {
+		invalid["low_stock_threshold"] = "must not be negative"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	warehouse, err := s.warehouseRepo.GetByID(ctx, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.inventoryRepo.SetStock(ctx, &model.StockLevel{
+		SKU:               sku,
+		WarehouseID:       warehouse.ID,
+		OnHand:            req.OnHand,
+		LowStockThreshold: req.LowStockThreshold,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishLowStock(change)
+
+	return s.getOne(ctx, sku)
+}
+
+// AdjustStock implements InventoryService.
+func (s *inventoryServiceImpl) AdjustStock(ctx context.Context, req *dto.AdjustStockRequest) (*dto.StockResponse, error) {
+	sku := strings.TrimSpace(req.SKU)
+
+	invalid := map[string]string{}
+	validateSKU(invalid, "sku", sku)
+	if req.Delta == 0 {
+		invalid["delta"] = "must not be zero"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	warehouse, err := s.warehouseRepo.GetByID(ctx, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.inventoryRepo.AdjustStock(ctx, sku, warehouse.ID, req.Delta)
+	if err != nil {
+		return nil, err
+	}
+	s.publishLowStock(change)
+
+	return s.getOne(ctx, sku)
+}
+
+// GetStock implements InventoryService.
+func (s *inventoryServiceImpl) GetStock(ctx context.Context, skus []string) ([]*dto.StockResponse, error) {
+	if len(skus) == 0 || len(skus) > maxSKUsPerRequest {
+		return nil, &ValidationError{Fields: map[string]string{
+			"skus": fmt.Sprintf("must contain between 1 and %d SKUs", maxSKUsPerRequest),
+		}}
+	}
+
+	cleaned := make([]string, 0, len(skus))
+	seen := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		sku = strings.TrimSpace(sku)
+		if sku == "" || seen[sku] {
+			continue
+		}
+		seen[sku] = true
+		cleaned = append(cleaned, sku)
+	}
+
+	levels, err := s.inventoryRepo.ListStock(ctx, cleaned)
+	if err != nil {
+		return nil, err
+	}
+
+	bySKU := make(map[string]*dto.StockResponse, len(cleaned))
+	resp := make([]*dto.StockResponse, 0, len(cleaned))
+	for _, sku := range cleaned {
+		stock := &dto.StockResponse{SKU: sku, Warehouses: []*dto.WarehouseStockResponse{}}
+		bySKU[sku] = stock
+		resp = append(resp, stock)
+	}
+
+	for _, level := range levels {
+		stock := bySKU[level.SKU]
+		if stock == nil {
+			continue
+		}
+
+		stock.OnHand += level.OnHand
+		stock.Reserved += level.Reserved
+		stock.Available += level.Available()
+		stock.Warehouses = append(stock.Warehouses, &dto.WarehouseStockResponse{
+			WarehouseID:       level.WarehouseID,
+			WarehouseCode:     level.WarehouseCode,
+			OnHand:            level.OnHand,
+			Reserved:          level.Reserved,
+			Available:         level.Available(),
+			LowStockThreshold: level.LowStockThreshold,
+		})
+	}
+
+	return resp, nil
+}
+
+// Reserve implements InventoryService.
+func (s *inventoryServiceImpl) Reserve(ctx context.Context, req *dto.ReserveRequest) (*dto.ReservationResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+
+	invalid := map[string]string{}
+	if reference == "" || len(reference) > maxReferenceLength {
+		invalid["reference"] = fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength)
+	}
+	if len(req.Lines) == 0 || len(req.Lines) > maxReserveLines {
+		invalid["lines"] = fmt.Sprintf("must contain between 1 and %d lines", maxReserveLines)
+	}
+	if req.TTLSeconds < 0 {
+		invalid["ttl_seconds"] = "must not be negative"
+	}
+
+	// Merge repeated SKUs so each one is checked against stock only once.
+	var lines []model.ReserveLine
+	index := map[string]int{}
+	for i, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		validateSKU(invalid, fmt.Sprintf("lines[%d].sku", i), sku)
+		if line.Quantity <= 0 {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = "must be greater than zero"
+			continue
+		}
+
+		if at, ok := index[sku]; ok {
+			lines[at].Quantity += line.Quantity
+			continue
+		}
+		index[sku] = len(lines)
+		lines = append(lines, model.ReserveLine{SKU: sku, Quantity: line.Quantity})
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	ttl := s.reservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+
+	reservation := &model.Reservation{
+		Reference: reference,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+
+	changes, err := s.inventoryRepo.Reserve(ctx, reservation, lines)
+	if errors.Is(err, repository.ErrDuplicateReservation) {
+		existing, err := s.inventoryRepo.GetReservationByReference(ctx, reference)
+		if err != nil {
+			return nil, err
+		}
+		return toReservationResponse(existing), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		s.publishLowStock(change)
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// CommitReservation implements InventoryService.
+func (s *inventoryServiceImpl) CommitReservation(ctx context.Context, id string) (*dto.ReservationResponse, error) {
+	reservation, err := s.inventoryRepo.CommitReservation(ctx, id, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ReleaseReservation implements InventoryService.
+func (s *inventoryServiceImpl) ReleaseReservation(ctx context.Context, id string) (*dto.ReservationResponse, error) {
+	reservation, err := s.inventoryRepo.ReleaseReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// GetReservation implements InventoryService.
+func (s *inventoryServiceImpl) GetReservation(ctx context.Context, id string) (*dto.ReservationResponse, error) {
+	reservation, err := s.inventoryRepo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// Restock implements InventoryService.
+func (s *inventoryServiceImpl) Restock(ctx context.Context, req *dto.RestockRequest) (bool, error) {
+	reference := strings.TrimSpace(req.Reference)
+	reservationReference := strings.TrimSpace(req.ReservationReference)
+
+	invalid := map[string]string{}
+	if reference == "" || len(reference) > maxReferenceLength {
+		invalid["reference"] = fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength)
+	}
+	if reservationReference == "" || len(reservationReference) > maxReferenceLength {
+		invalid["reservation_reference"] = fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength)
+	}
+	if len(req.Lines) == 0 || len(req.Lines) > maxReserveLines {
+		invalid["lines"] = fmt.Sprintf("must contain between 1 and %d lines", maxReserveLines)
+	}
+
+	var lines []model.ReserveLine
+	index := map[string]int{}
+	for i, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		validateSKU(invalid, fmt.Sprintf("lines[%d].sku", i), sku)
+		if line.Quantity <= 0 {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = "must be greater than zero"
+			continue
+		}
+
+		if at, ok := index[sku]; ok {
+			lines[at].Quantity += line.Quantity
+			continue
+		}
+		index[sku] = len(lines)
+		lines = append(lines, model.ReserveLine{SKU: sku, Quantity: line.Quantity})
+	}
+
+	if len(invalid) > 0 {
+		return false, &ValidationError{Fields: invalid}
+	}
+
+	changes, applied, err := s.inventoryRepo.Restock(ctx, reference, reservationReference, lines)
+	if err != nil {
+		return false, err
+	}
+
+	for _, change := range changes {
+		s.publishLowStock(change)
+	}
+
+	return applied, nil
+}
+
+// RunExpiry implements InventoryService.
+func (s *inventoryServiceImpl) RunExpiry(ctx context.Context) {
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep sweeping while full batches come back so a backlog drains
+		// within one tick.
+		for {
+			released, err := s.inventoryRepo.ExpireReservations(ctx, time.Now().UTC(), expiryBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Errorf("Failed to expire reservations: %v", err)
+				}
+				break
+			}
+			if released > 0 {
+				logger.Infof("Released %d expired reservations", released)
+			}
+			if released < expiryBatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (s *inventoryServiceImpl) getOne(ctx context.Context, sku string) (*dto.StockResponse, error) {
+	stock, err := s.GetStock(ctx, []string{sku})
+	if err != nil {
+		return nil, err
+	}
+
+	return stock[0], nil
+}
+
+// publishLowStock is best effort: the stock write has already been committed,
+// so a broker outage is logged rather than failing the request.
+func (s *inventoryServiceImpl) publishLowStock(change *model.StockChange) {
+	if change == nil || !change.CrossedLowStock() {
+		return
+	}
+
+	err := s.publisher.PublishLowStock(&event.LowStockEvent{
+		SKU:         change.SKU,
+		WarehouseID: change.WarehouseID,
+		Available:   change.AvailableAfter,
+		Threshold:   change.LowStockThreshold,
+		OccurredAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		logger.Warnf("Failed to publish low stock event for %s: %v", change.SKU, err)
+	}
+}
+
+func validateSKU(invalid map[string]string, field, sku string) {
+	if sku == "" || len(sku) > maxSKULength {
+		invalid[field] = fmt.Sprintf("must be between 1 and %d characters", maxSKULength)
+	}
+}
+
+func toReservationResponse(reservation *model.Reservation) *dto.ReservationResponse {
+	resp := &dto.ReservationResponse{
+		ID:        reservation.ID,
+		Reference: reservation.Reference,
+		Status:    string(reservation.Status),
+		Items:     make([]*dto.ReservationItemResponse, 0, len(reservation.Items)),
+		ExpiresAt: reservation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: reservation.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: reservation.UpdatedAt.Format(time.RFC3339),
+	}
+
+	for _, item := range reservation.Items {
+		resp.Items = append(resp.Items, &dto.ReservationItemResponse{
+			SKU:         item.SKU,
+			WarehouseID: item.WarehouseID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	return resp
+}
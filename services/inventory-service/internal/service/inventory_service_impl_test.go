@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/event"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/repository"
+)
+
+// fakeInventoryRepo records what the service asks of the repository and
+// answers with the changes and errors it is given.
+type fakeInventoryRepo struct {
+	reserved     []model.ReserveLine
+	reservation  *model.Reservation
+	changes      []*model.StockChange
+	reserveErr   error
+	byReference  map[string]*model.Reservation
+	reserveCalls int
+}
+
+func (r *fakeInventoryRepo) SetStock(ctx context.Context, level *model.StockLevel) (*model.StockChange, error) {
+	return &model.StockChange{SKU: level.SKU, WarehouseID: level.WarehouseID}, nil
+}
+
+func (r *fakeInventoryRepo) AdjustStock(ctx context.Context, sku, warehouseID string, delta int) (*model.StockChange, error) {
+	return &model.StockChange{SKU: sku, WarehouseID: warehouseID}, nil
+}
+
+func (r *fakeInventoryRepo) ListStock(ctx context.Context, skus []string) ([]*model.StockLevel, error) {
+	return nil, nil
+}
+
+func (r *fakeInventoryRepo) Reserve(ctx context.Context, reservation *model.Reservation, lines []model.ReserveLine) ([]*model.StockChange, error) {
+	r.reserveCalls++
+	if r.reserveErr != nil {
+		return nil, r.reserveErr
+	}
+	r.reserved = lines
+	r.reservation = reservation
+	reservation.ID = "00000000-0000-4000-8000-000000000001"
+	reservation.Status = model.ReservationStatusActive
+	return r.changes, nil
+}
+
+func (r *fakeInventoryRepo) CommitReservation(ctx context.Context, id string, now time.Time) (*model.Reservation, error) {
+	return nil, repository.ErrReservationNotFound
+}
+
+func (r *fakeInventoryRepo) ReleaseReservation(ctx context.Context, id string) (*model.Reservation, error) {
+	return nil, repository.ErrReservationNotFound
+}
+
+func (r *fakeInventoryRepo) ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeInventoryRepo) GetReservation(ctx context.Context, id string) (*model.Reservation, error) {
+	return nil, repository.ErrReservationNotFound
+}
+
+func (r *fakeInventoryRepo) GetReservationByReference(ctx context.Context, reference string) (*model.Reservation, error) {
+	if reservation, ok := r.byReference[reference]; ok {
+		return reservation, nil
+	}
+	return nil, repository.ErrReservationNotFound
+}
+
+func (r *fakeInventoryRepo) Restock(ctx context.Context, reference, reservationReference string, lines []model.ReserveLine) ([]*model.StockChange, bool, error) {
+	return nil, true, nil
+}
+
+type fakeWarehouseRepo struct{}
+
+func (r *fakeWarehouseRepo) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	return nil
+}
+
+func (r *fakeWarehouseRepo) GetByID(ctx context.Context, id string) (*model.Warehouse, error) {
+	return &model.Warehouse{ID: id, IsActive: true}, nil
+}
+
+func (r *fakeWarehouseRepo) List(ctx context.Context) ([]*model.Warehouse, error) {
+	return nil, nil
+}
+
+type fakePublisher struct {
+	events []*event.LowStockEvent
+	err    error
+}
+
+func (p *fakePublisher) PublishLowStock(evt *event.LowStockEvent) error {
+	p.events = append(p.events, evt)
+	return p.err
+}
+
+func newTestInventoryService() (InventoryService, *fakeInventoryRepo, *fakePublisher) {
+	repo := &fakeInventoryRepo{}
+	publisher := &fakePublisher{}
+	return NewInventoryService(repo, &fakeWarehouseRepo{}, publisher, 15*time.Minute, time.Minute), repo, publisher
+}
+
+func TestReserveValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *dto.ReserveRequest
+		wantField string
+	}{
+		{name: "valid", req: &dto.ReserveRequest{Reference: "checkout-1", Lines: []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}}}},
+		{name: "reference is required", req: &dto.ReserveRequest{Reference: " ", Lines: []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}}}, wantField: "reference"},
+		{name: "reference too long", req: &dto.ReserveRequest{Reference: strings.Repeat("r", maxReferenceLength+1), Lines: []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}}}, wantField: "reference"},
+		{name: "no lines", req: &dto.ReserveRequest{Reference: "checkout-1"}, wantField: "lines"},
+		{name: "sku too long", req: &dto.ReserveRequest{Reference: "checkout-1", Lines: []dto.ReserveLine{{SKU: strings.Repeat("s", maxSKULength+1), Quantity: 1}}}, wantField: "lines[0].sku"},
+		{name: "quantity must be positive", req: &dto.ReserveRequest{Reference: "checkout-1", Lines: []dto.ReserveLine{{SKU: "SKU-1", Quantity: 0}}}, wantField: "lines[0].quantity"},
+		{name: "negative ttl", req: &dto.ReserveRequest{Reference: "checkout-1", Lines: []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}}, TTLSeconds: -1}, wantField: "ttl_seconds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := newTestInventoryService()
+
+			_, err := svc.Reserve(context.Background(), tt.req)
+
+			var validationErr *ValidationError
+			switch {
+			case tt.wantField == "" && err != nil:
+				t.Fatalf("Reserve() error = %v, want nil", err)
+			case tt.wantField != "" && !errors.As(err, &validationErr):
+				t.Fatalf("Reserve() error = %v, want validation error", err)
+			case tt.wantField != "" && validationErr.Fields[tt.wantField] == "":
+				t.Errorf("Reserve() invalid fields = %v, want %s", validationErr.Fields, tt.wantField)
+			case tt.wantField != "" && repo.reserveCalls != 0:
+				t.Errorf("Reserve() reached the repository with an invalid request")
+			}
+		})
+	}
+}
+
+func TestReserveMergesLinesAndCapsTTL(t *testing.T) {
+	svc, repo, _ := newTestInventoryService()
+
+	before := time.Now().UTC()
+	_, err := svc.Reserve(context.Background(), &dto.ReserveRequest{
+		Reference: " checkout-1 ",
+		Lines: []dto.ReserveLine{
+			{SKU: "SKU-1", Quantity: 2},
+			{SKU: "SKU-2", Quantity: 1},
+			{SKU: " SKU-1", Quantity: 3},
+		},
+		TTLSeconds: int(48 * time.Hour / time.Second),
+	})
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	want := []model.ReserveLine{{SKU: "SKU-1", Quantity: 5}, {SKU: "SKU-2", Quantity: 1}}
+	if len(repo.reserved) != len(want) || repo.reserved[0] != want[0] || repo.reserved[1] != want[1] {
+		t.Errorf("reserved lines = %v, want %v", repo.reserved, want)
+	}
+	if repo.reservation.Reference != "checkout-1" {
+		t.Errorf("reference = %q, want checkout-1", repo.reservation.Reference)
+	}
+	if ttl := repo.reservation.ExpiresAt.Sub(before); ttl > maxReservationTTL+time.Minute {
+		t.Errorf("reservation held for %v, want at most %v", ttl, maxReservationTTL)
+	}
+}
+
+func TestReserveDuplicateReturnsExisting(t *testing.T) {
+	svc, repo, _ := newTestInventoryService()
+	repo.reserveErr = repository.ErrDuplicateReservation
+	repo.byReference = map[string]*model.Reservation{
+		"checkout-1": {
+			ID:        "00000000-0000-4000-8000-000000000009",
+			Reference: "checkout-1",
+			Status:    model.ReservationStatusCommitted,
+		},
+	}
+
+	resp, err := svc.Reserve(context.Background(), &dto.ReserveRequest{
+		Reference: "checkout-1",
+		Lines:     []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if resp.ID != "00000000-0000-4000-8000-000000000009" || resp.Status != "committed" {
+		t.Errorf("Reserve() = %s/%s, want the existing committed reservation", resp.ID, resp.Status)
+	}
+}
+
+func TestReserveInsufficientStock(t *testing.T) {
+	svc, repo, _ := newTestInventoryService()
+	repo.reserveErr = repository.ErrInsufficientStock
+
+	_, err := svc.Reserve(context.Background(), &dto.ReserveRequest{
+		Reference: "checkout-1",
+		Lines:     []dto.ReserveLine{{SKU: "SKU-1", Quantity: 1}},
+	})
+	if !errors.Is(err, repository.ErrInsufficientStock) {
+		t.Errorf("Reserve() error = %v, want %v", err, repository.ErrInsufficientStock)
+	}
+}
+
+func TestReservePublishesLowStock(t *testing.T) {
+	svc, repo, publisher := newTestInventoryService()
+	publisher.err = errors.New("broker down")
+	repo.changes = []*model.StockChange{
+		{SKU: "SKU-1", WarehouseID: "w1", AvailableBefore: 10, AvailableAfter: 4, LowStockThreshold: 5},
+		{SKU: "SKU-2", WarehouseID: "w1", AvailableBefore: 10, AvailableAfter: 6, LowStockThreshold: 5},
+		{SKU: "SKU-3", WarehouseID: "w1", AvailableBefore: 3, AvailableAfter: 2, LowStockThreshold: 5},
+	}
+
+	_, err := svc.Reserve(context.Background(), &dto.ReserveRequest{
+		Reference: "checkout-1",
+		Lines: []dto.ReserveLine{
+			{SKU: "SKU-1", Quantity: 6},
+			{SKU: "SKU-2", Quantity: 4},
+			{SKU: "SKU-3", Quantity: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Reserve() error = %v, want a publish failure to be logged only", err)
+	}
+
+	if len(publisher.events) != 1 || publisher.events[0].SKU != "SKU-1" || publisher.events[0].Available != 4 {
+		t.Errorf("low stock events = %+v, want one for SKU-1 at 4", publisher.events)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/inventory-service/internal/repository"
+)
+
+var warehouseCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{1,31}$`)
+
+type warehouseServiceImpl struct {
+	warehouseRepo repository.WarehouseRepository
+}
+
+func NewWarehouseService(warehouseRepo repository.WarehouseRepository) WarehouseService {
+	return &warehouseServiceImpl{
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+// CreateWarehouse implements WarehouseService.
+func (s *warehouseServiceImpl) CreateWarehouse(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error) {
+	warehouse := &model.Warehouse{
+		Code: strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name: strings.TrimSpace(req.Name),
+	}
+
+	invalid := map[string]string{}
+	if !warehouseCodePattern.MatchString(warehouse.Code) {
+		invalid["code"] = "must be 2-32 letters, digits or '-'"
+	}
+	if warehouse.Name == "" || len(warehouse.Name) > 100 {
+		invalid["name"] = "must be between 1 and 100 characters"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	if err := s.warehouseRepo.Create(ctx, warehouse); err != nil {
+		return nil, err
+	}
+
+	return toWarehouseResponse(warehouse), nil
+}
+
+// ListWarehouses implements WarehouseService.
+func (s *warehouseServiceImpl) ListWarehouses(ctx context.Context) ([]*dto.WarehouseResponse, error) {
+	warehouses, err := s.warehouseRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.WarehouseResponse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		resp = append(resp, toWarehouseResponse(warehouse))
+	}
+
+	return resp, nil
+}
+
+func toWarehouseResponse(warehouse *model.Warehouse) *dto.WarehouseResponse {
+	return &dto.WarehouseResponse{
+		ID:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		IsActive:  warehouse.IsActive,
+		CreatedAt: warehouse.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,42 @@
+CREATE TABLE warehouses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per SKU per warehouse. available = on_hand - reserved.
+CREATE TABLE stock_levels (
+    sku VARCHAR(64) NOT NULL,
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    on_hand INTEGER NOT NULL DEFAULT 0,
+    reserved INTEGER NOT NULL DEFAULT 0,
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sku, warehouse_id),
+    CONSTRAINT stock_levels_non_negative CHECK (on_hand >= 0 AND reserved >= 0),
+    CONSTRAINT stock_levels_no_oversell CHECK (reserved <= on_hand)
+);
+
+CREATE TABLE reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference VARCHAR(100) UNIQUE NOT NULL, -- caller supplied, e.g. the checkout id; makes Reserve idempotent
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- 'active', 'committed', 'released', 'expired'
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE reservation_items (
+    id BIGSERIAL PRIMARY KEY,
+    reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+-- Indexes
+CREATE INDEX idx_stock_levels_warehouse ON stock_levels(warehouse_id);
+CREATE INDEX idx_reservations_active_expiry ON reservations(expires_at) WHERE status = 'active';
+CREATE INDEX idx_reservation_items_reservation ON reservation_items(reservation_id);
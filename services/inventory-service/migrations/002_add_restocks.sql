@@ -0,0 +1,7 @@
+-- Returned goods put back on hand. The caller supplied reference, e.g. a
+-- return id, makes restocking idempotent.
+CREATE TABLE restocks (
+    reference VARCHAR(100) PRIMARY KEY,
+    reservation_id UUID NOT NULL REFERENCES reservations(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- How much of each reservation item has been restocked, so returns under
+-- different references cannot together put back more than was sold.
+-- Restocks made before this column existed are not counted.
+ALTER TABLE reservation_items ADD COLUMN restocked INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reservation_items ADD CONSTRAINT reservation_items_restocked_check
+    CHECK (restocked >= 0 AND restocked <= quantity);
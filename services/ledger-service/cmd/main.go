@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/ledger-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	if cfg.CommissionRate >= 10000 {
+		logger.Fatal("COMMISSION_RATE_BPS must be below 10000")
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("ledger-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	ledgerRepo := repository.NewLedgerRepository(db)
+	payoutRepo := repository.NewPayoutRepository(db)
+
+	ledgerService := service.NewLedgerService(
+		ledgerRepo,
+		cfg.Currency,
+		int64(cfg.CommissionRate),
+		cfg.PayoutHold,
+	)
+	payoutService := service.NewPayoutService(
+		ledgerRepo,
+		payoutRepo,
+		cfg.Currency,
+		cfg.PayoutHold,
+		int64(cfg.PayoutMinimum),
+		cfg.PayoutInterval,
+	)
+
+	ledgerHandler := handler.NewLedgerHandler(ledgerService, payoutService)
+
+	if err := pb.RegisterLedgerServiceHandler(srv.Server(), ledgerHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go payoutService.RunPayouts(ctx)
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Currency the ledger is kept in, as an ISO 4217 code. It has to match
+	// the currency checkouts are charged in.
+	Currency string
+
+	// Platform share of every order in basis points; 1000 is 10%.
+	CommissionRate int
+
+	// How often payout batches run, the smallest balance worth paying out
+	// and how long new earnings are held back so refunds can be taken from
+	// them.
+	PayoutInterval time.Duration
+	PayoutMinimum  int
+	PayoutHold     time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:           getEnv("PORT", "8091"),
+		Environment:    os.Getenv("ENVIRONMENT"),
+		DatabaseURL:    os.Getenv("DATABASE_URL"),
+		Currency:       getEnv("CURRENCY", "IDR"),
+		CommissionRate: getIntEnv("COMMISSION_RATE_BPS", 1000),
+		PayoutInterval: getDurationEnv("PAYOUT_INTERVAL", 24*time.Hour),
+		PayoutMinimum:  getIntEnv("PAYOUT_MINIMUM", 10000),
+		PayoutHold:     getDurationEnv("PAYOUT_HOLD", 14*24*time.Hour),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,123 @@
+package dto
+
+type RecordOrderCaptureRequest struct {
+	OrderID   string `json:"order_id"`
+	SellerID  int64  `json:"seller_id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+}
+
+type RecordOrderRefundRequest struct {
+	OrderID   string `json:"order_id"`
+	Amount    int64  `json:"amount"`
+	Reference string `json:"reference"`
+}
+
+type PostingResponse struct {
+	Account string `json:"account"`
+	Debit   int64  `json:"debit"`
+	Credit  int64  `json:"credit"`
+}
+
+type TransactionResponse struct {
+	ID          string             `json:"id"`
+	Kind        string             `json:"kind"`
+	Reference   string             `json:"reference"`
+	OrderID     string             `json:"order_id,omitempty"`
+	SellerID    int64              `json:"seller_id,omitempty"`
+	PayoutID    string             `json:"payout_id,omitempty"`
+	Description string             `json:"description"`
+	Currency    string             `json:"currency"`
+	Postings    []*PostingResponse `json:"postings"`
+	CreatedAt   string             `json:"created_at"`
+}
+
+type SellerBalanceResponse struct {
+	SellerID  int64  `json:"seller_id"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	Held      int64  `json:"held"`
+	Available int64  `json:"available"`
+	InTransit int64  `json:"in_transit"`
+}
+
+type ListSellerEntriesRequest struct {
+	SellerID int64 `json:"seller_id"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+type SellerEntryResponse struct {
+	TransactionID string `json:"transaction_id"`
+	Kind          string `json:"kind"`
+	OrderID       string `json:"order_id,omitempty"`
+	PayoutID      string `json:"payout_id,omitempty"`
+	Description   string `json:"description"`
+	Amount        int64  `json:"amount"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type PayoutResponse struct {
+	ID            string `json:"id"`
+	BatchID       string `json:"batch_id"`
+	SellerID      int64  `json:"seller_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type PayoutBatchResponse struct {
+	ID            string            `json:"id"`
+	HeldAfter     string            `json:"held_after"`
+	MinimumAmount int64             `json:"minimum_amount"`
+	PayoutCount   int               `json:"payout_count"`
+	Total         int64             `json:"total"`
+	Currency      string            `json:"currency"`
+	Payouts       []*PayoutResponse `json:"payouts"`
+	CreatedAt     string            `json:"created_at"`
+}
+
+type ListPayoutsRequest struct {
+	SellerID int64  `json:"seller_id"`
+	BatchID  string `json:"batch_id"`
+	Status   string `json:"status"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type UpdatePayoutStatusRequest struct {
+	PayoutID string `json:"payout_id"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+}
+
+type KindTotalResponse struct {
+	Kind   string `json:"kind"`
+	Count  int    `json:"count"`
+	Amount int64  `json:"amount"`
+}
+
+type AccountActivityResponse struct {
+	Account        string `json:"account"`
+	Type           string `json:"type"`
+	OpeningBalance int64  `json:"opening_balance"`
+	Debits         int64  `json:"debits"`
+	Credits        int64  `json:"credits"`
+	ClosingBalance int64  `json:"closing_balance"`
+}
+
+type ReconciliationReportResponse struct {
+	From          string                     `json:"from"`
+	To            string                     `json:"to"`
+	Currency      string                     `json:"currency"`
+	Kinds         []*KindTotalResponse       `json:"kinds"`
+	Accounts      []*AccountActivityResponse `json:"accounts"`
+	TotalDebits   int64                      `json:"total_debits"`
+	TotalCredits  int64                      `json:"total_credits"`
+	Balanced      bool                       `json:"balanced"`
+	Discrepancies []string                   `json:"discrepancies"`
+	GeneratedAt   string                     `json:"generated_at"`
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/ledger-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "ledger-service"
+
+type LedgerHandler struct {
+	ledgerService service.LedgerService
+	payoutService service.PayoutService
+}
+
+func NewLedgerHandler(ledgerService service.LedgerService, payoutService service.PayoutService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+		payoutService: payoutService,
+	}
+}
+
+func (h *LedgerHandler) RecordOrderCapture(ctx context.Context, req *pb.RecordOrderCaptureRequest, rsp *pb.TransactionResponse) error {
+	txn, err := h.ledgerService.RecordOrderCapture(ctx, &dto.RecordOrderCaptureRequest{
+		OrderID:   req.OrderId,
+		SellerID:  req.SellerId,
+		PaymentID: req.PaymentId,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Transaction = toProtoTransaction(txn)
+	return nil
+}
+
+func (h *LedgerHandler) RecordOrderRefund(ctx context.Context, req *pb.RecordOrderRefundRequest, rsp *pb.TransactionResponse) error {
+	txn, err := h.ledgerService.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{
+		OrderID:   req.OrderId,
+		Amount:    req.Amount,
+		Reference: req.Reference,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Transaction = toProtoTransaction(txn)
+	return nil
+}
+
+func (h *LedgerHandler) GetSellerBalance(ctx context.Context, req *pb.GetSellerBalanceRequest, rsp *pb.SellerBalanceResponse) error {
+	balance, err := h.ledgerService.GetSellerBalance(ctx, req.SellerId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Balance = &pb.SellerBalance{
+		SellerId:  balance.SellerID,
+		Currency:  balance.Currency,
+		Balance:   balance.Balance,
+		Held:      balance.Held,
+		Available: balance.Available,
+		InTransit: balance.InTransit,
+	}
+	return nil
+}
+
+func (h *LedgerHandler) ListSellerEntries(ctx context.Context, req *pb.ListSellerEntriesRequest, rsp *pb.ListSellerEntriesResponse) error {
+	entries, total, err := h.ledgerService.ListSellerEntries(ctx, &dto.ListSellerEntriesRequest{
+		SellerID: req.SellerId,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, entry := range entries {
+		rsp.Entries = append(rsp.Entries, &pb.SellerEntry{
+			TransactionId: entry.TransactionID,
+			Kind:          entry.Kind,
+			OrderId:       entry.OrderID,
+			PayoutId:      entry.PayoutID,
+			Description:   entry.Description,
+			Amount:        entry.Amount,
+			CreatedAt:     entry.CreatedAt,
+		})
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *LedgerHandler) CreatePayoutBatch(ctx context.Context, req *pb.CreatePayoutBatchRequest, rsp *pb.PayoutBatchResponse) error {
+	batch, err := h.payoutService.CreatePayoutBatch(ctx)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Batch = toProtoPayoutBatch(batch)
+	return nil
+}
+
+func (h *LedgerHandler) GetPayoutBatch(ctx context.Context, req *pb.GetPayoutBatchRequest, rsp *pb.PayoutBatchResponse) error {
+	batch, err := h.payoutService.GetPayoutBatch(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Batch = toProtoPayoutBatch(batch)
+	return nil
+}
+
+func (h *LedgerHandler) ListPayouts(ctx context.Context, req *pb.ListPayoutsRequest, rsp *pb.ListPayoutsResponse) error {
+	payouts, total, err := h.payoutService.ListPayouts(ctx, &dto.ListPayoutsRequest{
+		SellerID: req.SellerId,
+		BatchID:  req.BatchId,
+		Status:   req.Status,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, payout := range payouts {
+		rsp.Payouts = append(rsp.Payouts, toProtoPayout(payout))
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *LedgerHandler) UpdatePayoutStatus(ctx context.Context, req *pb.UpdatePayoutStatusRequest, rsp *pb.PayoutResponse) error {
+	payout, err := h.payoutService.UpdatePayoutStatus(ctx, &dto.UpdatePayoutStatusRequest{
+		PayoutID: req.Id,
+		Status:   req.Status,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payout = toProtoPayout(payout)
+	return nil
+}
+
+func (h *LedgerHandler) GetReconciliationReport(ctx context.Context, req *pb.GetReconciliationReportRequest, rsp *pb.ReconciliationReportResponse) error {
+	report, err := h.ledgerService.GetReconciliationReport(ctx, req.From, req.To)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Report = &pb.ReconciliationReport{
+		From:          report.From,
+		To:            report.To,
+		Currency:      report.Currency,
+		TotalDebits:   report.TotalDebits,
+		TotalCredits:  report.TotalCredits,
+		Balanced:      report.Balanced,
+		Discrepancies: report.Discrepancies,
+		GeneratedAt:   report.GeneratedAt,
+	}
+	for _, kind := range report.Kinds {
+		rsp.Report.Kinds = append(rsp.Report.Kinds, &pb.KindTotal{
+			Kind:   kind.Kind,
+			Count:  int32(kind.Count),
+			Amount: kind.Amount,
+		})
+	}
+	for _, account := range report.Accounts {
+		rsp.Report.Accounts = append(rsp.Report.Accounts, &pb.AccountActivity{
+			Account:        account.Account,
+			Type:           account.Type,
+			OpeningBalance: account.OpeningBalance,
+			Debits:         account.Debits,
+			Credits:        account.Credits,
+			ClosingBalance: account.ClosingBalance,
+		})
+	}
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrBatchNotFound),
+		errors.Is(err, repository.ErrPayoutNotFound),
+		errors.Is(err, repository.ErrTransactionNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrNotCaptured):
+		return microerrors.New(serviceID, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, service.ErrIdempotencyMismatch),
+		errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, repository.ErrStatusChanged),
+		errors.Is(err, repository.ErrOrderChanged),
+		errors.Is(err, repository.ErrBalanceChanged):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoTransaction(txn *dto.TransactionResponse) *pb.Transaction {
+	postings := make([]*pb.Posting, 0, len(txn.Postings))
+	for _, p := range txn.Postings {
+		postings = append(postings, &pb.Posting{
+			Account: p.Account,
+			Debit:   p.Debit,
+			Credit:  p.Credit,
+		})
+	}
+
+	return &pb.Transaction{
+		Id:          txn.ID,
+		Kind:        txn.Kind,
+		Reference:   txn.Reference,
+		OrderId:     txn.OrderID,
+		SellerId:    txn.SellerID,
+		PayoutId:    txn.PayoutID,
+		Description: txn.Description,
+		Currency:    txn.Currency,
+		Postings:    postings,
+		CreatedAt:   txn.CreatedAt,
+	}
+}
+
+func toProtoPayoutBatch(batch *dto.PayoutBatchResponse) *pb.PayoutBatch {
+	payouts := make([]*pb.Payout, 0, len(batch.Payouts))
+	for _, payout := range batch.Payouts {
+		payouts = append(payouts, toProtoPayout(payout))
+	}
+
+	return &pb.PayoutBatch{
+		Id:            batch.ID,
+		HeldAfter:     batch.HeldAfter,
+		MinimumAmount: batch.MinimumAmount,
+		PayoutCount:   int32(batch.PayoutCount),
+		Total:         batch.Total,
+		Currency:      batch.Currency,
+		Payouts:       payouts,
+		CreatedAt:     batch.CreatedAt,
+	}
+}
+
+func toProtoPayout(payout *dto.PayoutResponse) *pb.Payout {
+	return &pb.Payout{
+		Id:            payout.ID,
+		BatchId:       payout.BatchID,
+		SellerId:      payout.SellerID,
+		Amount:        payout.Amount,
+		Currency:      payout.Currency,
+		Status:        payout.Status,
+		FailureReason: payout.FailureReason,
+		CreatedAt:     payout.CreatedAt,
+		UpdatedAt:     payout.UpdatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type AccountType string
+
+const (
+	AccountAsset     AccountType = "asset"
+	AccountLiability AccountType = "liability"
+	AccountRevenue   AccountType = "revenue"
+)
+
+// NormalBalance turns a debit-positive sum of postings into a balance on the
+// account's normal side, so a liability the platform owes is positive.
+func (t AccountType) NormalBalance(sum int64) int64 {
+	if t == AccountAsset {
+		return sum
+	}
+	return -sum
+}
+
+type Account struct {
+	Code     string      `db:"code"`
+	Type     AccountType `db:"type"`
+	SellerID int64       `db:"seller_id"`
+}
+
+var (
+	// PlatformCash is money captured from customers and held by the
+	// platform until it is refunded or paid out.
+	PlatformCash = Account{Code: "platform:cash", Type: AccountAsset}
+	// PlatformCommission is the platform's cut of each order.
+	PlatformCommission = Account{Code: "platform:commission", Type: AccountRevenue}
+	// PayoutsInTransit holds payouts sent to sellers' banks and not yet
+	// confirmed.
+	PayoutsInTransit = Account{Code: "platform:payouts_in_transit", Type: AccountLiability}
+)
+
+// SellerPayable is what the platform owes a seller.
+func SellerPayable(sellerID int64) Account {
+	return Account{
+		Code:     fmt.Sprintf("seller:%d:payable", sellerID),
+		Type:     AccountLiability,
+		SellerID: sellerID,
+	}
+}
+
+type TransactionKind string
+
+const (
+	KindOrderCapture  TransactionKind = "order_capture"
+	KindOrderRefund   TransactionKind = "order_refund"
+	KindPayout        TransactionKind = "payout"
+	KindPayoutSettled TransactionKind = "payout_settled"
+	KindPayoutFailed  TransactionKind = "payout_failed"
+)
+
+// Posting moves Amount into or out of an account. Positive amounts are
+// debits and negative amounts credits.
+type Posting struct {
+	Account Account `db:"account_code"`
+	Amount  int64   `db:"amount"`
+}
+
+func Debit(account Account, amount int64) *Posting {
+	return &Posting{Account: account, Amount: amount}
+}
+
+func Credit(account Account, amount int64) *Posting {
+	return &Posting{Account: account, Amount: -amount}
+}
+
+// Transaction is one balanced journal entry. Reference is unique per kind,
+// which makes recording a transaction idempotent.
+type Transaction struct {
+	ID          string          `db:"id"`
+	Kind        TransactionKind `db:"kind"`
+	Reference   string          `db:"reference"`
+	OrderID     string          `db:"order_id"`
+	SellerID    int64           `db:"seller_id"`
+	PayoutID    string          `db:"payout_id"`
+	Description string          `db:"description"`
+	Currency    string          `db:"currency"`
+	Postings    []*Posting
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// IsBalanced reports whether the postings sum to zero without any of them
+// being empty.
+func (t *Transaction) IsBalanced() bool {
+	if len(t.Postings) < 2 {
+		return false
+	}
+
+	var sum int64
+	for _, p := range t.Postings {
+		if p.Amount == 0 {
+			return false
+		}
+		sum += p.Amount
+	}
+	return sum == 0
+}
+
+// Total is the sum of the debits, which equals the sum of the credits.
+func (t *Transaction) Total() int64 {
+	var total int64
+	for _, p := range t.Postings {
+		if p.Amount > 0 {
+			total += p.Amount
+		}
+	}
+	return total
+}
+
+// OrderTotals sums what has been recorded for one order so far.
+type OrderTotals struct {
+	Captured           int64
+	Commission         int64
+	Refunded           int64
+	CommissionReversed int64
+}
+
+type SellerBalance struct {
+	SellerID  int64
+	Currency  string
+	Balance   int64
+	Held      int64
+	InTransit int64
+}
+
+// Available is the part of the balance that can be paid out now.
+func (b *SellerBalance) Available() int64 {
+	if available := b.Balance - b.Held; available > 0 {
+		return available
+	}
+	return 0
+}
+
+// SellerEntry is one line of a seller's statement.
+type SellerEntry struct {
+	TransactionID string          `db:"transaction_id"`
+	Kind          TransactionKind `db:"kind"`
+	OrderID       string          `db:"order_id"`
+	PayoutID      string          `db:"payout_id"`
+	Description   string          `db:"description"`
+	Amount        int64           `db:"amount"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
+
+type KindTotal struct {
+	Kind   TransactionKind
+	Count  int
+	Amount int64
+}
+
+// AccountActivity holds debit-positive sums for one account over a period.
+type AccountActivity struct {
+	Account Account
+	Opening int64
+	Debits  int64
+	Credits int64
+}
+
+func (a *AccountActivity) Closing() int64 {
+	return a.Opening + a.Debits - a.Credits
+}
+
+// Reconciliation is the raw material of a reconciliation report.
+type Reconciliation struct {
+	From     time.Time
+	To       time.Time
+	Kinds    []*KindTotal
+	Accounts []*AccountActivity
+	// UnbalancedTransactions lists transactions whose postings do not sum
+	// to zero. The database refuses to commit them, so any entry here
+	// means the ledger was tampered with.
+	UnbalancedTransactions []string
+	// PendingPayouts is the sum of payouts still pending now, which should
+	// match the current balance of PayoutsInTransit.
+	PendingPayouts   int64
+	PayoutsInTransit int64
+}
@@ -0,0 +1,75 @@
+package model
+
+import "testing"
+
+func TestTransactionIsBalanced(t *testing.T) {
+	seller := SellerPayable(7)
+
+	tests := []struct {
+		name     string
+		postings []*Posting
+		want     bool
+		total    int64
+	}{
+		{
+			name:     "capture",
+			postings: []*Posting{Debit(PlatformCash, 1000), Credit(seller, 900), Credit(PlatformCommission, 100)},
+			want:     true,
+			total:    1000,
+		},
+		{
+			name:     "refund",
+			postings: []*Posting{Debit(seller, 450), Debit(PlatformCommission, 50), Credit(PlatformCash, 500)},
+			want:     true,
+			total:    500,
+		},
+		{
+			name:     "debits exceed credits",
+			postings: []*Posting{Debit(PlatformCash, 1000), Credit(seller, 899), Credit(PlatformCommission, 100)},
+			total:    1000,
+		},
+		{
+			name:     "empty posting",
+			postings: []*Posting{Debit(PlatformCash, 1000), Credit(seller, 1000), Credit(PlatformCommission, 0)},
+			total:    1000,
+		},
+		{
+			name:     "single posting",
+			postings: []*Posting{Debit(PlatformCash, 0)},
+		},
+		{
+			name: "no postings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := &Transaction{Postings: tt.postings}
+			if got := txn.IsBalanced(); got != tt.want {
+				t.Errorf("IsBalanced() = %v, want %v", got, tt.want)
+			}
+			if got := txn.Total(); got != tt.total {
+				t.Errorf("Total() = %d, want %d", got, tt.total)
+			}
+		})
+	}
+}
+
+func TestNormalBalance(t *testing.T) {
+	tests := []struct {
+		account AccountType
+		sum     int64
+		want    int64
+	}{
+		{AccountAsset, 500, 500},
+		{AccountLiability, -500, 500},
+		{AccountRevenue, -100, 100},
+		{AccountLiability, 200, -200},
+	}
+
+	for _, tt := range tests {
+		if got := tt.account.NormalBalance(tt.sum); got != tt.want {
+			t.Errorf("%s.NormalBalance(%d) = %d, want %d", tt.account, tt.sum, got, tt.want)
+		}
+	}
+}
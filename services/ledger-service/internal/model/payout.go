@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+type PayoutStatus string
+
+const (
+	PayoutPending PayoutStatus = "pending"
+	PayoutPaid    PayoutStatus = "paid"
+	// PayoutFailed means the transfer bounced; the amount goes back to the
+	// seller's balance and is picked up by the next batch.
+	PayoutFailed PayoutStatus = "failed"
+)
+
+var payoutTransitions = map[PayoutStatus][]PayoutStatus{
+	PayoutPending: {PayoutPaid, PayoutFailed},
+	PayoutPaid:    {},
+	PayoutFailed:  {},
+}
+
+func (s PayoutStatus) IsValid() bool {
+	_, ok := payoutTransitions[s]
+	return ok
+}
+
+func (s PayoutStatus) CanTransitionTo(next PayoutStatus) bool {
+	for _, allowed := range payoutTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Payout struct {
+	ID            string       `db:"id"`
+	BatchID       string       `db:"batch_id"`
+	SellerID      int64        `db:"seller_id"`
+	Amount        int64        `db:"amount"`
+	Currency      string       `db:"currency"`
+	Status        PayoutStatus `db:"status"`
+	FailureReason string       `db:"failure_reason"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+}
+
+// PayoutBatch is one run of the payout job. Earnings recorded after
+// HeldAfter were left for a later batch.
+type PayoutBatch struct {
+	ID            string    `db:"id"`
+	HeldAfter     time.Time `db:"held_after"`
+	MinimumAmount int64     `db:"minimum_amount"`
+	PayoutCount   int       `db:"payout_count"`
+	Total         int64     `db:"total"`
+	Currency      string    `db:"currency"`
+	Payouts       []*Payout
+	CreatedAt     time.Time `db:"created_at"`
+}
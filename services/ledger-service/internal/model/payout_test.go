@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestPayoutStatusCanTransitionTo(t *testing.T) {
+	statuses := []PayoutStatus{PayoutPending, PayoutPaid, PayoutFailed}
+	allowed := map[[2]PayoutStatus]bool{
+		{PayoutPending, PayoutPaid}:   true,
+		{PayoutPending, PayoutFailed}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]PayoutStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+)
+
+var (
+	ErrTransactionNotFound  = errors.New("ledger transaction not found")
+	ErrDuplicateTransaction = errors.New("ledger transaction with this reference already recorded")
+	ErrUnbalanced           = errors.New("ledger transaction does not balance")
+	ErrOrderChanged         = errors.New("order ledger changed concurrently")
+)
+
+// LedgerRepository is the append-only journal. It has no way to change or
+// remove a recorded transaction; a mistake is corrected by recording another
+// transaction that reverses it. The database rejects updates and deletes of
+// ledger rows as well.
+type LedgerRepository interface {
+	// Append records txn and its postings, creating accounts on first use.
+	// It fails with ErrUnbalanced if the postings do not sum to zero and
+	// with ErrDuplicateTransaction if the kind and reference were used
+	// before. For a transaction about an order, seen must hold the order
+	// totals its postings were worked out from; Append fails with
+	// ErrOrderChanged if another transaction for the order got in first.
+	Append(ctx context.Context, txn *model.Transaction, seen *model.OrderTotals) error
+	GetTransaction(ctx context.Context, kind model.TransactionKind, reference string) (*model.Transaction, error)
+	GetOrderTotals(ctx context.Context, orderID string) (*model.OrderTotals, error)
+
+	// GetSellerBalance holds back what the seller earned from captures
+	// recorded after heldAfter. A seller without entries has a zero balance.
+	GetSellerBalance(ctx context.Context, sellerID int64, heldAfter time.Time) (*model.SellerBalance, error)
+	// ListSellerBalances returns every seller that has ever had an entry.
+	ListSellerBalances(ctx context.Context, heldAfter time.Time) ([]*model.SellerBalance, error)
+	ListSellerEntries(ctx context.Context, sellerID int64, limit, offset int) ([]*model.SellerEntry, int, error)
+
+	// Reconcile sums the ledger over [from, to) and checks it against the
+	// payouts.
+	Reconcile(ctx context.Context, from, to time.Time) (*model.Reconciliation, error)
+}
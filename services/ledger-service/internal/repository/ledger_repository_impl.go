@@ -0,0 +1,408 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const transactionColumns = `
+	id, kind, reference, COALESCE(order_id::text, ''), COALESCE(seller_id, 0), COALESCE(payout_id::text, ''),
+	description, currency, created_at
+`
+
+type ledgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func scanTransaction(row rowScanner) (*model.Transaction, error) {
+	var txn model.Transaction
+	err := row.Scan(
+		&txn.ID,
+		&txn.Kind,
+		&txn.Reference,
+		&txn.OrderID,
+		&txn.SellerID,
+		&txn.PayoutID,
+		&txn.Description,
+		&txn.Currency,
+		&txn.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txn, nil
+}
+
+func (r *ledgerRepository) Append(ctx context.Context, txn *model.Transaction, seen *model.OrderTotals) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if txn.OrderID != "" && seen != nil {
+		// Transactions of one order are serialized so seen can be trusted
+		// until commit.
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, txn.OrderID); err != nil {
+			return err
+		}
+
+		current, err := orderTotals(ctx, tx, txn.OrderID)
+		if err != nil {
+			return err
+		}
+		if *current != *seen {
+			return ErrOrderChanged
+		}
+	}
+
+	if err := insertTransaction(ctx, tx, txn); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ledgerRepository) GetTransaction(ctx context.Context, kind model.TransactionKind, reference string) (*model.Transaction, error) {
+	txn, err := scanTransaction(r.db.QueryRowContext(ctx,
+		`SELECT `+transactionColumns+` FROM ledger_transactions WHERE kind = $1 AND reference = $2`,
+		kind, reference,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.code, a.type, COALESCE(a.seller_id, 0), p.amount
+		FROM ledger_postings p
+		JOIN ledger_accounts a ON a.code = p.account_code
+		WHERE p.transaction_id = $1
+		ORDER BY p.id ASC
+	`, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var posting model.Posting
+		err := rows.Scan(
+			&posting.Account.Code,
+			&posting.Account.Type,
+			&posting.Account.SellerID,
+			&posting.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		txn.Postings = append(txn.Postings, &posting)
+	}
+
+	return txn, rows.Err()
+}
+
+func (r *ledgerRepository) GetOrderTotals(ctx context.Context, orderID string) (*model.OrderTotals, error) {
+	return orderTotals(ctx, r.db, orderID)
+}
+
+func (r *ledgerRepository) GetSellerBalance(ctx context.Context, sellerID int64, heldAfter time.Time) (*model.SellerBalance, error) {
+	balances, err := sellerBalances(ctx, r.db, heldAfter, []int64{sellerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return &model.SellerBalance{SellerID: sellerID}, nil
+	}
+
+	return balances[0], nil
+}
+
+func (r *ledgerRepository) ListSellerBalances(ctx context.Context, heldAfter time.Time) ([]*model.SellerBalance, error) {
+	return sellerBalances(ctx, r.db, heldAfter, nil)
+}
+
+func (r *ledgerRepository) ListSellerEntries(ctx context.Context, sellerID int64, limit, offset int) ([]*model.SellerEntry, int, error) {
+	account := model.SellerPayable(sellerID).Code
+
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM ledger_postings WHERE account_code = $1`, account,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Payables are credit accounts, so a credit adds to what the seller is
+	// owed.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.kind, COALESCE(t.order_id::text, ''), COALESCE(t.payout_id::text, ''), t.description,
+			-p.amount, t.created_at
+		FROM ledger_postings p
+		JOIN ledger_transactions t ON t.id = p.transaction_id
+		WHERE p.account_code = $1
+		ORDER BY p.id DESC
+		LIMIT $2 OFFSET $3
+	`, account, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []*model.SellerEntry
+	for rows.Next() {
+		var entry model.SellerEntry
+		err := rows.Scan(
+			&entry.TransactionID,
+			&entry.Kind,
+			&entry.OrderID,
+			&entry.PayoutID,
+			&entry.Description,
+			&entry.Amount,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *ledgerRepository) Reconcile(ctx context.Context, from, to time.Time) (*model.Reconciliation, error) {
+	// One snapshot, so the sums agree with each other even while new
+	// transactions come in.
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	report := &model.Reconciliation{From: from, To: to}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.kind, COUNT(DISTINCT t.id), COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0)
+		FROM ledger_transactions t
+		JOIN ledger_postings p ON p.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY t.kind
+		ORDER BY t.kind ASC
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var kind model.KindTotal
+		if err := rows.Scan(&kind.Kind, &kind.Count, &kind.Amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.Kinds = append(report.Kinds, &kind)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT a.code, a.type, COALESCE(a.seller_id, 0),
+			COALESCE(SUM(p.amount) FILTER (WHERE p.created_at < $1), 0),
+			COALESCE(SUM(p.amount) FILTER (WHERE p.created_at >= $1 AND p.amount > 0), 0),
+			COALESCE(-SUM(p.amount) FILTER (WHERE p.created_at >= $1 AND p.amount < 0), 0)
+		FROM ledger_accounts a
+		JOIN ledger_postings p ON p.account_code = a.code
+		WHERE p.created_at < $2
+		GROUP BY a.code, a.type, a.seller_id
+		ORDER BY a.code ASC
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var activity model.AccountActivity
+		err := rows.Scan(
+			&activity.Account.Code,
+			&activity.Account.Type,
+			&activity.Account.SellerID,
+			&activity.Opening,
+			&activity.Debits,
+			&activity.Credits,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.Accounts = append(report.Accounts, &activity)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT transaction_id
+		FROM ledger_postings
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY transaction_id
+		HAVING SUM(amount) <> 0 OR COUNT(*) < 2
+		ORDER BY transaction_id ASC
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.UnbalancedTransactions = append(report.UnbalancedTransactions, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COALESCE(SUM(amount), 0) FROM payouts WHERE status = $1),
+			(SELECT COALESCE(-SUM(amount), 0) FROM ledger_postings WHERE account_code = $2)
+	`, model.PayoutPending, model.PayoutsInTransit.Code).Scan(&report.PendingPayouts, &report.PayoutsInTransit)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, tx.Commit()
+}
+
+// insertTransaction writes txn and its postings inside tx. The balance is
+// checked here and again by the database at commit.
+func insertTransaction(ctx context.Context, tx *sql.Tx, txn *model.Transaction) error {
+	if !txn.IsBalanced() {
+		return ErrUnbalanced
+	}
+
+	for _, posting := range txn.Postings {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ledger_accounts (code, type, seller_id, currency)
+			VALUES ($1, $2, NULLIF($3, 0), $4)
+			ON CONFLICT (code) DO NOTHING
+		`, posting.Account.Code, posting.Account.Type, posting.Account.SellerID, txn.Currency)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO ledger_transactions (kind, reference, order_id, seller_id, payout_id, description, currency)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, 0), NULLIF($5, '')::uuid, $6, $7)
+		RETURNING id, created_at
+	`,
+		txn.Kind,
+		txn.Reference,
+		txn.OrderID,
+		txn.SellerID,
+		txn.PayoutID,
+		txn.Description,
+		txn.Currency,
+	).Scan(&txn.ID, &txn.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateTransaction
+		}
+		return err
+	}
+
+	for _, posting := range txn.Postings {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ledger_postings (transaction_id, account_code, amount)
+			VALUES ($1, $2, $3)
+		`, txn.ID, posting.Account.Code, posting.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func orderTotals(ctx context.Context, q queryer, orderID string) (*model.OrderTotals, error) {
+	var totals model.OrderTotals
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(p.amount) FILTER (WHERE t.kind = $2 AND p.account_code = $4), 0),
+			COALESCE(-SUM(p.amount) FILTER (WHERE t.kind = $2 AND p.account_code = $5), 0),
+			COALESCE(-SUM(p.amount) FILTER (WHERE t.kind = $3 AND p.account_code = $4), 0),
+			COALESCE(SUM(p.amount) FILTER (WHERE t.kind = $3 AND p.account_code = $5), 0)
+		FROM ledger_transactions t
+		JOIN ledger_postings p ON p.transaction_id = t.id
+		WHERE t.order_id::text = $1
+	`,
+		orderID,
+		model.KindOrderCapture,
+		model.KindOrderRefund,
+		model.PlatformCash.Code,
+		model.PlatformCommission.Code,
+	).Scan(&totals.Captured, &totals.Commission, &totals.Refunded, &totals.CommissionReversed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// sellerBalances sums the payable accounts of sellerIDs, or of every seller
+// if sellerIDs is nil. Captures recorded after heldAfter are held.
+func sellerBalances(ctx context.Context, q queryer, heldAfter time.Time, sellerIDs []int64) ([]*model.SellerBalance, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT a.seller_id,
+			COALESCE(-SUM(p.amount), 0),
+			COALESCE(-SUM(p.amount) FILTER (WHERE t.kind = $2 AND t.created_at > $1), 0),
+			(SELECT COALESCE(SUM(amount), 0) FROM payouts WHERE seller_id = a.seller_id AND status = $3)
+		FROM ledger_accounts a
+		JOIN ledger_postings p ON p.account_code = a.code
+		JOIN ledger_transactions t ON t.id = p.transaction_id
+		WHERE a.seller_id IS NOT NULL AND ($4::bigint[] IS NULL OR a.seller_id = ANY($4))
+		GROUP BY a.seller_id
+		ORDER BY a.seller_id ASC
+	`, heldAfter, model.KindOrderCapture, model.PayoutPending, pq.Array(sellerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*model.SellerBalance
+	for rows.Next() {
+		var balance model.SellerBalance
+		if err := rows.Scan(&balance.SellerID, &balance.Balance, &balance.Held, &balance.InTransit); err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	return balances, rows.Err()
+}
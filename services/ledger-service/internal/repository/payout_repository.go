@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+)
+
+var (
+	ErrBatchNotFound  = errors.New("payout batch not found")
+	ErrPayoutNotFound = errors.New("payout not found")
+	ErrBalanceChanged = errors.New("seller balance changed concurrently")
+	ErrStatusChanged  = errors.New("payout status changed concurrently")
+)
+
+type PayoutFilter struct {
+	SellerID int64
+	BatchID  string
+	Status   model.PayoutStatus
+	Limit    int
+	Offset   int
+}
+
+// PayoutRepository stores payout batches and payouts, recording the ledger
+// transaction of every step in the same database transaction.
+type PayoutRepository interface {
+	// CreateBatch stores batch with its payouts, and entries[i] as the
+	// ledger transaction of batch.Payouts[i] with the payout's id as its
+	// reference. Only one batch is created at a time. It fails with
+	// ErrBalanceChanged if a seller no longer has the amount available as
+	// of batch.HeldAfter.
+	CreateBatch(ctx context.Context, batch *model.PayoutBatch, entries []*model.Transaction) error
+	GetBatch(ctx context.Context, id string) (*model.PayoutBatch, error)
+
+	GetPayout(ctx context.Context, id string) (*model.Payout, error)
+	List(ctx context.Context, filter PayoutFilter) ([]*model.Payout, int, error)
+
+	// UpdateStatus moves payout to its new status and records txn if the
+	// payout is still fromStatus, and returns ErrStatusChanged otherwise.
+	UpdateStatus(ctx context.Context, payout *model.Payout, fromStatus model.PayoutStatus, txn *model.Transaction) error
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+)
+
+const payoutColumns = `
+	id, batch_id, seller_id, amount, currency, status, COALESCE(failure_reason, ''), created_at, updated_at
+`
+
+// payoutBatchLock is the advisory lock key that keeps payout batches from
+// running concurrently.
+const payoutBatchLock = 7300001
+
+type payoutRepository struct {
+	db *sql.DB
+}
+
+func NewPayoutRepository(db *sql.DB) PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+func scanPayout(row rowScanner) (*model.Payout, error) {
+	var payout model.Payout
+	err := row.Scan(
+		&payout.ID,
+		&payout.BatchID,
+		&payout.SellerID,
+		&payout.Amount,
+		&payout.Currency,
+		&payout.Status,
+		&payout.FailureReason,
+		&payout.CreatedAt,
+		&payout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
+
+func (r *payoutRepository) CreateBatch(ctx context.Context, batch *model.PayoutBatch, entries []*model.Transaction) error {
+	if len(entries) != len(batch.Payouts) {
+		return fmt.Errorf("got %d ledger entries for %d payouts", len(entries), len(batch.Payouts))
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, payoutBatchLock); err != nil {
+		return err
+	}
+
+	sellerIDs := make([]int64, 0, len(batch.Payouts))
+	for _, payout := range batch.Payouts {
+		sellerIDs = append(sellerIDs, payout.SellerID)
+	}
+
+	balances, err := sellerBalances(ctx, tx, batch.HeldAfter, sellerIDs)
+	if err != nil {
+		return err
+	}
+	available := make(map[int64]int64, len(balances))
+	for _, balance := range balances {
+		available[balance.SellerID] = balance.Available()
+	}
+	for _, payout := range batch.Payouts {
+		if payout.Amount > available[payout.SellerID] {
+			return fmt.Errorf("%w: seller %d", ErrBalanceChanged, payout.SellerID)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO payout_batches (held_after, minimum_amount, payout_count, total, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`,
+		batch.HeldAfter,
+		batch.MinimumAmount,
+		batch.PayoutCount,
+		batch.Total,
+		batch.Currency,
+	).Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i, payout := range batch.Payouts {
+		payout.BatchID = batch.ID
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO payouts (batch_id, seller_id, amount, currency, status)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at
+		`,
+			payout.BatchID,
+			payout.SellerID,
+			payout.Amount,
+			payout.Currency,
+			payout.Status,
+		).Scan(&payout.ID, &payout.CreatedAt, &payout.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		entry := entries[i]
+		entry.PayoutID = payout.ID
+		entry.Reference = payout.ID
+		if err := insertTransaction(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *payoutRepository) GetBatch(ctx context.Context, id string) (*model.PayoutBatch, error) {
+	var batch model.PayoutBatch
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, held_after, minimum_amount, payout_count, total, currency, created_at
+		FROM payout_batches
+		WHERE id::text = $1
+	`, id).Scan(
+		&batch.ID,
+		&batch.HeldAfter,
+		&batch.MinimumAmount,
+		&batch.PayoutCount,
+		&batch.Total,
+		&batch.Currency,
+		&batch.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+payoutColumns+` FROM payouts WHERE batch_id = $1 ORDER BY seller_id ASC`, batch.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		batch.Payouts = append(batch.Payouts, payout)
+	}
+
+	return &batch, rows.Err()
+}
+
+func (r *payoutRepository) GetPayout(ctx context.Context, id string) (*model.Payout, error) {
+	payout, err := scanPayout(r.db.QueryRowContext(ctx,
+		`SELECT `+payoutColumns+` FROM payouts WHERE id::text = $1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrPayoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+func (r *payoutRepository) List(ctx context.Context, filter PayoutFilter) ([]*model.Payout, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if filter.SellerID != 0 {
+		addCondition("seller_id", filter.SellerID)
+	}
+	if filter.BatchID != "" {
+		addCondition("batch_id::text", filter.BatchID)
+	}
+	if filter.Status != "" {
+		addCondition("status", filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM payouts `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM payouts %s ORDER BY created_at DESC, id ASC LIMIT $%d OFFSET $%d`,
+		payoutColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var payouts []*model.Payout
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		payouts = append(payouts, payout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return payouts, total, nil
+}
+
+func (r *payoutRepository) UpdateStatus(ctx context.Context, payout *model.Payout, fromStatus model.PayoutStatus, txn *model.Transaction) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE payouts SET status = $3, failure_reason = NULLIF($4, ''), updated_at = NOW()
+		WHERE id = $1 AND status = $2
+		RETURNING updated_at
+	`, payout.ID, fromStatus, payout.Status, payout.FailureReason).Scan(&payout.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrStatusChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertTransaction(ctx, tx, txn); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/dto"
+)
+
+var (
+	ErrIdempotencyMismatch = errors.New("reference was already used for a different amount")
+	ErrNotCaptured         = errors.New("no capture recorded for this order")
+	ErrInvalidTransition   = errors.New("payout is not in a state that allows this")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// LedgerService books order money into the ledger and reports on it.
+type LedgerService interface {
+	// RecordOrderCapture credits the seller with the order amount less
+	// commission. Recording an order again returns the first transaction,
+	// or ErrIdempotencyMismatch if the amount or seller differ.
+	RecordOrderCapture(ctx context.Context, req *dto.RecordOrderCaptureRequest) (*dto.TransactionResponse, error)
+	// RecordOrderRefund debits the seller for a refund and gives back the
+	// commission taken on it. It fails with ErrNotCaptured for an order
+	// that was never captured.
+	RecordOrderRefund(ctx context.Context, req *dto.RecordOrderRefundRequest) (*dto.TransactionResponse, error)
+
+	GetSellerBalance(ctx context.Context, sellerID int64) (*dto.SellerBalanceResponse, error)
+	ListSellerEntries(ctx context.Context, req *dto.ListSellerEntriesRequest) ([]*dto.SellerEntryResponse, int, error)
+
+	// GetReconciliationReport sums the ledger between two RFC 3339 times
+	// and lists anything that does not add up.
+	GetReconciliationReport(ctx context.Context, from, to string) (*dto.ReconciliationReportResponse, error)
+}
+
+// PayoutService pays sellers what the ledger says they are owed.
+type PayoutService interface {
+	// CreatePayoutBatch creates a pending payout for every seller whose
+	// available balance reaches the minimum.
+	CreatePayoutBatch(ctx context.Context) (*dto.PayoutBatchResponse, error)
+	GetPayoutBatch(ctx context.Context, id string) (*dto.PayoutBatchResponse, error)
+	ListPayouts(ctx context.Context, req *dto.ListPayoutsRequest) ([]*dto.PayoutResponse, int, error)
+	UpdatePayoutStatus(ctx context.Context, req *dto.UpdatePayoutStatusRequest) (*dto.PayoutResponse, error)
+
+	// RunPayouts creates a payout batch every interval until ctx is done.
+	RunPayouts(ctx context.Context)
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/repository"
+)
+
+const (
+	// basisPoints is 100%, the unit commission rates are given in.
+	basisPoints = 10000
+
+	maxReferenceLength = 100
+	maxAppendAttempts  = 3
+	defaultPageSize    = 20
+	maxPageSize        = 100
+
+	defaultReportPeriod = 24 * time.Hour
+)
+
+type ledgerServiceImpl struct {
+	ledgerRepo     repository.LedgerRepository
+	currency       string
+	commissionRate int64
+	payoutHold     time.Duration
+}
+
+// NewLedgerService creates a ledger service in currency that keeps
+// commissionRate basis points of every order for the platform. Earnings
+// younger than payoutHold count as held in seller balances.
+func NewLedgerService(
+	ledgerRepo repository.LedgerRepository,
+	currency string,
+	commissionRate int64,
+	payoutHold time.Duration,
+) LedgerService {
+	return &ledgerServiceImpl{
+		ledgerRepo:     ledgerRepo,
+		currency:       currency,
+		commissionRate: commissionRate,
+		payoutHold:     payoutHold,
+	}
+}
+
+// RecordOrderCapture implements LedgerService.
+func (s *ledgerServiceImpl) RecordOrderCapture(ctx context.Context, req *dto.RecordOrderCaptureRequest) (*dto.TransactionResponse, error) {
+	orderID := strings.TrimSpace(req.OrderID)
+
+	invalid := map[string]string{}
+	if orderID == "" {
+		invalid["order_id"] = "is required"
+	}
+	if req.SellerID <= 0 {
+		invalid["seller_id"] = "is required"
+	}
+	if req.Amount <= 0 {
+		invalid["amount"] = "must be positive"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	commission := req.Amount * s.commissionRate / basisPoints
+	seller := model.SellerPayable(req.SellerID)
+
+	description := fmt.Sprintf("Order %s captured", orderID)
+	if req.PaymentID != "" {
+		description = fmt.Sprintf("Order %s captured by payment %s", orderID, req.PaymentID)
+	}
+
+	txn := &model.Transaction{
+		Kind:        model.KindOrderCapture,
+		Reference:   orderID,
+		OrderID:     orderID,
+		SellerID:    req.SellerID,
+		Description: description,
+		Currency:    s.currency,
+		Postings: nonZero(
+			model.Debit(model.PlatformCash, req.Amount),
+			model.Credit(seller, req.Amount-commission),
+			model.Credit(model.PlatformCommission, commission),
+		),
+	}
+
+	err := s.ledgerRepo.Append(ctx, txn, nil)
+	if errors.Is(err, repository.ErrDuplicateTransaction) {
+		existing, err := s.ledgerRepo.GetTransaction(ctx, model.KindOrderCapture, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if existing.SellerID != req.SellerID || existing.Total() != req.Amount {
+			return nil, ErrIdempotencyMismatch
+		}
+		return toTransactionResponse(existing), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(txn), nil
+}
+
+// RecordOrderRefund implements LedgerService.
+func (s *ledgerServiceImpl) RecordOrderRefund(ctx context.Context, req *dto.RecordOrderRefundRequest) (*dto.TransactionResponse, error) {
+	orderID := strings.TrimSpace(req.OrderID)
+	reference := strings.TrimSpace(req.Reference)
+
+	invalid := map[string]string{}
+	if orderID == "" {
+		invalid["order_id"] = "is required"
+	}
+	if req.Amount <= 0 {
+		invalid["amount"] = "must be positive"
+	}
+	if reference == "" || len(reference) > maxReferenceLength {
+		invalid["reference"] = fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	// References only have to be unique within an order.
+	key := orderID + "/" + reference
+
+	existing, err := s.ledgerRepo.GetTransaction(ctx, model.KindOrderRefund, key)
+	switch {
+	case err == nil:
+		if existing.Total() != req.Amount {
+			return nil, ErrIdempotencyMismatch
+		}
+		return toTransactionResponse(existing), nil
+	case !errors.Is(err, repository.ErrTransactionNotFound):
+		return nil, err
+	}
+
+	capture, err := s.ledgerRepo.GetTransaction(ctx, model.KindOrderCapture, orderID)
+	if errors.Is(err, repository.ErrTransactionNotFound) {
+		return nil, ErrNotCaptured
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		totals, err := s.ledgerRepo.GetOrderTotals(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+
+		if remaining := totals.Captured - totals.Refunded; req.Amount > remaining {
+			return nil, &ValidationError{Fields: map[string]string{
+				"amount": fmt.Sprintf("must be at most the %d left to refund on the order", remaining),
+			}}
+		}
+
+		// Commission is given back in proportion to what has been refunded
+		// in total, so rounding evens out and a full refund returns all of it.
+		commission := totals.Commission*(totals.Refunded+req.Amount)/totals.Captured - totals.CommissionReversed
+
+		txn := &model.Transaction{
+			Kind:        model.KindOrderRefund,
+			Reference:   key,
+			OrderID:     orderID,
+			SellerID:    capture.SellerID,
+			Description: fmt.Sprintf("Order %s refunded (%s)", orderID, reference),
+			Currency:    capture.Currency,
+			Postings: nonZero(
+				model.Debit(model.SellerPayable(capture.SellerID), req.Amount-commission),
+				model.Debit(model.PlatformCommission, commission),
+				model.Credit(model.PlatformCash, req.Amount),
+			),
+		}
+
+		err = s.ledgerRepo.Append(ctx, txn, totals)
+		switch {
+		case err == nil:
+			return toTransactionResponse(txn), nil
+		case errors.Is(err, repository.ErrDuplicateTransaction):
+			// A retry of the same refund won the race.
+			return s.RecordOrderRefund(ctx, req)
+		case errors.Is(err, repository.ErrOrderChanged) && attempt < maxAppendAttempts:
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+// GetSellerBalance implements LedgerService.
+func (s *ledgerServiceImpl) GetSellerBalance(ctx context.Context, sellerID int64) (*dto.SellerBalanceResponse, error) {
+	if sellerID <= 0 {
+		return nil, &ValidationError{Fields: map[string]string{"seller_id": "is required"}}
+	}
+
+	balance, err := s.ledgerRepo.GetSellerBalance(ctx, sellerID, time.Now().UTC().Add(-s.payoutHold))
+	if err != nil {
+		return nil, err
+	}
+	balance.Currency = s.currency
+
+	return toSellerBalanceResponse(balance), nil
+}
+
+// ListSellerEntries implements LedgerService.
+func (s *ledgerServiceImpl) ListSellerEntries(ctx context.Context, req *dto.ListSellerEntriesRequest) ([]*dto.SellerEntryResponse, int, error) {
+	if req.SellerID <= 0 {
+		return nil, 0, &ValidationError{Fields: map[string]string{"seller_id": "is required"}}
+	}
+
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	entries, total, err := s.ledgerRepo.ListSellerEntries(ctx, req.SellerID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.SellerEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, &dto.SellerEntryResponse{
+			TransactionID: entry.TransactionID,
+			Kind:          string(entry.Kind),
+			OrderID:       entry.OrderID,
+			PayoutID:      entry.PayoutID,
+			Description:   entry.Description,
+			Amount:        entry.Amount,
+			CreatedAt:     entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, total, nil
+}
+
+// GetReconciliationReport implements LedgerService.
+func (s *ledgerServiceImpl) GetReconciliationReport(ctx context.Context, fromValue, toValue string) (*dto.ReconciliationReportResponse, error) {
+	now := time.Now().UTC()
+	to, from := now, now.Add(-defaultReportPeriod)
+
+	invalid := map[string]string{}
+	if toValue != "" {
+		t, err := time.Parse(time.RFC3339, toValue)
+		if err != nil {
+			invalid["to"] = "must be an RFC 3339 time"
+		}
+		to, from = t.UTC(), t.UTC().Add(-defaultReportPeriod)
+	}
+	if fromValue != "" {
+		t, err := time.Parse(time.RFC3339, fromValue)
+		if err != nil {
+			invalid["from"] = "must be an RFC 3339 time"
+		}
+		from = t.UTC()
+	}
+	if len(invalid) == 0 && !from.Before(to) {
+		invalid["from"] = "must be before to"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	recon, err := s.ledgerRepo.Reconcile(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ReconciliationReportResponse{
+		From:          from.Format(time.RFC3339),
+		To:            to.Format(time.RFC3339),
+		Currency:      s.currency,
+		Kinds:         make([]*dto.KindTotalResponse, 0, len(recon.Kinds)),
+		Accounts:      make([]*dto.AccountActivityResponse, 0, len(recon.Accounts)),
+		Discrepancies: []string{},
+		GeneratedAt:   now.Format(time.RFC3339),
+	}
+
+	for _, kind := range recon.Kinds {
+		report.Kinds = append(report.Kinds, &dto.KindTotalResponse{
+			Kind:   string(kind.Kind),
+			Count:  kind.Count,
+			Amount: kind.Amount,
+		})
+	}
+
+	// Closing balances must satisfy assets = liabilities + revenue, which
+	// holds exactly when all postings ever made sum to zero.
+	var equation int64
+	for _, activity := range recon.Accounts {
+		report.TotalDebits += activity.Debits
+		report.TotalCredits += activity.Credits
+		equation += activity.Closing()
+
+		accountType := activity.Account.Type
+		report.Accounts = append(report.Accounts, &dto.AccountActivityResponse{
+			Account:        activity.Account.Code,
+			Type:           string(accountType),
+			OpeningBalance: accountType.NormalBalance(activity.Opening),
+			Debits:         activity.Debits,
+			Credits:        activity.Credits,
+			ClosingBalance: accountType.NormalBalance(activity.Closing()),
+		})
+	}
+
+	if report.TotalDebits != report.TotalCredits {
+		report.Discrepancies = append(report.Discrepancies, fmt.Sprintf(
+			"debits of %d do not equal credits of %d", report.TotalDebits, report.TotalCredits))
+	}
+	if equation != 0 {
+		report.Discrepancies = append(report.Discrepancies, fmt.Sprintf(
+			"closing balances are off assets = liabilities + revenue by %d", equation))
+	}
+	for _, id := range recon.UnbalancedTransactions {
+		report.Discrepancies = append(report.Discrepancies, fmt.Sprintf("transaction %s does not balance", id))
+	}
+	if recon.PendingPayouts != recon.PayoutsInTransit {
+		report.Discrepancies = append(report.Discrepancies, fmt.Sprintf(
+			"pending payouts of %d do not match %d in transit", recon.PendingPayouts, recon.PayoutsInTransit))
+	}
+
+	report.Balanced = len(report.Discrepancies) == 0
+
+	return report, nil
+}
+
+// nonZero drops empty postings, such as the commission line of an order
+// sold at a zero rate.
+func nonZero(postings ...*model.Posting) []*model.Posting {
+	kept := postings[:0]
+	for _, p := range postings {
+		if p.Amount != 0 {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func pagination(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+func toTransactionResponse(txn *model.Transaction) *dto.TransactionResponse {
+	resp := &dto.TransactionResponse{
+		ID:          txn.ID,
+		Kind:        string(txn.Kind),
+		Reference:   txn.Reference,
+		OrderID:     txn.OrderID,
+		SellerID:    txn.SellerID,
+		PayoutID:    txn.PayoutID,
+		Description: txn.Description,
+		Currency:    txn.Currency,
+		Postings:    make([]*dto.PostingResponse, 0, len(txn.Postings)),
+		CreatedAt:   txn.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, p := range txn.Postings {
+		posting := &dto.PostingResponse{Account: p.Account.Code}
+		if p.Amount > 0 {
+			posting.Debit = p.Amount
+		} else {
+			posting.Credit = -p.Amount
+		}
+		resp.Postings = append(resp.Postings, posting)
+	}
+
+	return resp
+}
+
+func toSellerBalanceResponse(balance *model.SellerBalance) *dto.SellerBalanceResponse {
+	return &dto.SellerBalanceResponse{
+		SellerID:  balance.SellerID,
+		Currency:  balance.Currency,
+		Balance:   balance.Balance,
+		Held:      balance.Held,
+		Available: balance.Available(),
+		InTransit: balance.InTransit,
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/repository"
+)
+
+// fakeLedgerRepo keeps the journal in memory and refuses unbalanced
+// transactions, as the database does.
+type fakeLedgerRepo struct {
+	journal []*model.Transaction
+}
+
+func (r *fakeLedgerRepo) Append(ctx context.Context, txn *model.Transaction, seen *model.OrderTotals) error {
+	if !txn.IsBalanced() {
+		return repository.ErrUnbalanced
+	}
+	if _, err := r.GetTransaction(ctx, txn.Kind, txn.Reference); err == nil {
+		return repository.ErrDuplicateTransaction
+	}
+	r.journal = append(r.journal, txn)
+	return nil
+}
+
+func (r *fakeLedgerRepo) GetTransaction(ctx context.Context, kind model.TransactionKind, reference string) (*model.Transaction, error) {
+	for _, txn := range r.journal {
+		if txn.Kind == kind && txn.Reference == reference {
+			return txn, nil
+		}
+	}
+	return nil, repository.ErrTransactionNotFound
+}
+
+func (r *fakeLedgerRepo) GetOrderTotals(ctx context.Context, orderID string) (*model.OrderTotals, error) {
+	var totals model.OrderTotals
+	for _, txn := range r.journal {
+		if txn.OrderID != orderID {
+			continue
+		}
+		commission := -r.sum(txn, model.PlatformCommission)
+		switch txn.Kind {
+		case model.KindOrderCapture:
+			totals.Captured += txn.Total()
+			totals.Commission += commission
+		case model.KindOrderRefund:
+			totals.Refunded += txn.Total()
+			totals.CommissionReversed -= commission
+		}
+	}
+	return &totals, nil
+}
+
+func (r *fakeLedgerRepo) GetSellerBalance(ctx context.Context, sellerID int64, heldAfter time.Time) (*model.SellerBalance, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeLedgerRepo) ListSellerBalances(ctx context.Context, heldAfter time.Time) ([]*model.SellerBalance, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeLedgerRepo) ListSellerEntries(ctx context.Context, sellerID int64, limit, offset int) ([]*model.SellerEntry, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *fakeLedgerRepo) Reconcile(ctx context.Context, from, to time.Time) (*model.Reconciliation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeLedgerRepo) sum(txn *model.Transaction, account model.Account) int64 {
+	var sum int64
+	for _, posting := range txn.Postings {
+		if posting.Account.Code == account.Code {
+			sum += posting.Amount
+		}
+	}
+	return sum
+}
+
+// balance is the debit-positive balance of account over the whole journal.
+func (r *fakeLedgerRepo) balance(account model.Account) int64 {
+	var balance int64
+	for _, txn := range r.journal {
+		balance += r.sum(txn, account)
+	}
+	return balance
+}
+
+func TestRecordOrderRefundBalances(t *testing.T) {
+	tests := []struct {
+		name    string
+		capture int64
+		refunds []int64
+	}{
+		{name: "full refund", capture: 1000, refunds: []int64{1000}},
+		{name: "partial refunds that round", capture: 1001, refunds: []int64{333, 333, 335}},
+		{name: "refunds smaller than a commission unit", capture: 999, refunds: []int64{1, 1, 997}},
+	}
+
+	seller := model.SellerPayable(7)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLedgerRepo{}
+			svc := NewLedgerService(repo, "USD", 1000, 0)
+			ctx := context.Background()
+
+			if _, err := svc.RecordOrderCapture(ctx, &dto.RecordOrderCaptureRequest{OrderID: "o1", SellerID: 7, Amount: tt.capture}); err != nil {
+				t.Fatalf("RecordOrderCapture() error = %v", err)
+			}
+			for i, amount := range tt.refunds {
+				reference := string(rune('a' + i))
+				if _, err := svc.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{OrderID: "o1", Amount: amount, Reference: reference}); err != nil {
+					t.Fatalf("RecordOrderRefund(%d) error = %v", amount, err)
+				}
+			}
+
+			// Every transaction balanced on its own, so once the order is
+			// refunded in full nothing is left on any account.
+			for _, account := range []model.Account{model.PlatformCash, model.PlatformCommission, seller} {
+				if got := repo.balance(account); got != 0 {
+					t.Errorf("balance of %s = %d, want 0", account.Code, got)
+				}
+			}
+
+			var invalid *ValidationError
+			_, err := svc.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{OrderID: "o1", Amount: 1, Reference: "over"})
+			if !errors.As(err, &invalid) {
+				t.Errorf("RecordOrderRefund() past the capture error = %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestRecordOrderRefundIdempotent(t *testing.T) {
+	repo := &fakeLedgerRepo{}
+	svc := NewLedgerService(repo, "USD", 1000, 0)
+	ctx := context.Background()
+
+	if _, err := svc.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{OrderID: "o1", Amount: 100, Reference: "r1"}); !errors.Is(err, ErrNotCaptured) {
+		t.Fatalf("RecordOrderRefund() before capture error = %v, want %v", err, ErrNotCaptured)
+	}
+
+	if _, err := svc.RecordOrderCapture(ctx, &dto.RecordOrderCaptureRequest{OrderID: "o1", SellerID: 7, Amount: 1000}); err != nil {
+		t.Fatalf("RecordOrderCapture() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{OrderID: "o1", Amount: 100, Reference: "r1"}); err != nil {
+			t.Fatalf("RecordOrderRefund() attempt %d error = %v", i+1, err)
+		}
+	}
+	if got := len(repo.journal); got != 2 {
+		t.Errorf("journal has %d transactions, want 2", got)
+	}
+
+	_, err := svc.RecordOrderRefund(ctx, &dto.RecordOrderRefundRequest{OrderID: "o1", Amount: 200, Reference: "r1"})
+	if !errors.Is(err, ErrIdempotencyMismatch) {
+		t.Errorf("RecordOrderRefund() with another amount error = %v, want %v", err, ErrIdempotencyMismatch)
+	}
+}
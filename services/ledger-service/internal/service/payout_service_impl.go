@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/ledger-service/internal/repository"
+
+	"go-micro.dev/v4/logger"
+)
+
+const maxReasonLength = 500
+
+type payoutServiceImpl struct {
+	ledgerRepo     repository.LedgerRepository
+	payoutRepo     repository.PayoutRepository
+	currency       string
+	payoutHold     time.Duration
+	payoutMinimum  int64
+	payoutInterval time.Duration
+}
+
+// NewPayoutService creates a payout service that pays out balances of at
+// least payoutMinimum every payoutInterval, leaving earnings younger than
+// payoutHold for a later batch so refunds can still be taken from them.
+func NewPayoutService(
+	ledgerRepo repository.LedgerRepository,
+	payoutRepo repository.PayoutRepository,
+	currency string,
+	payoutHold time.Duration,
+	payoutMinimum int64,
+	payoutInterval time.Duration,
+) PayoutService {
+	return &payoutServiceImpl{
+		ledgerRepo:     ledgerRepo,
+		payoutRepo:     payoutRepo,
+		currency:       currency,
+		payoutHold:     payoutHold,
+		payoutMinimum:  payoutMinimum,
+		payoutInterval: payoutInterval,
+	}
+}
+
+// CreatePayoutBatch implements PayoutService.
+func (s *payoutServiceImpl) CreatePayoutBatch(ctx context.Context) (*dto.PayoutBatchResponse, error) {
+	for attempt := 1; ; attempt++ {
+		heldAfter := time.Now().UTC().Add(-s.payoutHold)
+
+		balances, err := s.ledgerRepo.ListSellerBalances(ctx, heldAfter)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := &model.PayoutBatch{
+			HeldAfter:     heldAfter,
+			MinimumAmount: s.payoutMinimum,
+			Currency:      s.currency,
+		}
+		var entries []*model.Transaction
+
+		for _, balance := range balances {
+			amount := balance.Available()
+			if amount < s.payoutMinimum {
+				continue
+			}
+
+			batch.Payouts = append(batch.Payouts, &model.Payout{
+				SellerID: balance.SellerID,
+				Amount:   amount,
+				Currency: s.currency,
+				Status:   model.PayoutPending,
+			})
+			batch.PayoutCount++
+			batch.Total += amount
+
+			entries = append(entries, &model.Transaction{
+				Kind:        model.KindPayout,
+				SellerID:    balance.SellerID,
+				Description: fmt.Sprintf("Payout to seller %d", balance.SellerID),
+				Currency:    s.currency,
+				Postings: []*model.Posting{
+					model.Debit(model.SellerPayable(balance.SellerID), amount),
+					model.Credit(model.PayoutsInTransit, amount),
+				},
+			})
+		}
+
+		err = s.payoutRepo.CreateBatch(ctx, batch, entries)
+		switch {
+		case err == nil:
+			return toPayoutBatchResponse(batch), nil
+		case errors.Is(err, repository.ErrBalanceChanged) && attempt < maxAppendAttempts:
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+// GetPayoutBatch implements PayoutService.
+func (s *payoutServiceImpl) GetPayoutBatch(ctx context.Context, id string) (*dto.PayoutBatchResponse, error) {
+	batch, err := s.payoutRepo.GetBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPayoutBatchResponse(batch), nil
+}
+
+// ListPayouts implements PayoutService.
+func (s *payoutServiceImpl) ListPayouts(ctx context.Context, req *dto.ListPayoutsRequest) ([]*dto.PayoutResponse, int, error) {
+	status := model.PayoutStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return nil, 0, &ValidationError{Fields: map[string]string{"status": "is not a known payout status"}}
+	}
+
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	payouts, total, err := s.payoutRepo.List(ctx, repository.PayoutFilter{
+		SellerID: req.SellerID,
+		BatchID:  strings.TrimSpace(req.BatchID),
+		Status:   status,
+		Limit:    pageSize,
+		Offset:   (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.PayoutResponse, 0, len(payouts))
+	for _, payout := range payouts {
+		resp = append(resp, toPayoutResponse(payout))
+	}
+
+	return resp, total, nil
+}
+
+// UpdatePayoutStatus implements PayoutService.
+func (s *payoutServiceImpl) UpdatePayoutStatus(ctx context.Context, req *dto.UpdatePayoutStatusRequest) (*dto.PayoutResponse, error) {
+	next := model.PayoutStatus(req.Status)
+	reason := strings.TrimSpace(req.Reason)
+
+	invalid := map[string]string{}
+	if next != model.PayoutPaid && next != model.PayoutFailed {
+		invalid["status"] = "must be paid or failed"
+	}
+	if len(reason) > maxReasonLength {
+		invalid["reason"] = fmt.Sprintf("must be at most %d characters", maxReasonLength)
+	}
+	if reason == "" && next == model.PayoutFailed {
+		invalid["reason"] = "is required when failing"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	payout, err := s.payoutRepo.GetPayout(ctx, req.PayoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeating the last transition is a no-op so callers can retry safely.
+	if payout.Status == next {
+		return toPayoutResponse(payout), nil
+	}
+	if !payout.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: payout %s to %s", ErrInvalidTransition, payout.Status, next)
+	}
+
+	txn := &model.Transaction{
+		Reference: payout.ID,
+		SellerID:  payout.SellerID,
+		PayoutID:  payout.ID,
+		Currency:  payout.Currency,
+	}
+	if next == model.PayoutPaid {
+		txn.Kind = model.KindPayoutSettled
+		txn.Description = fmt.Sprintf("Payout to seller %d settled", payout.SellerID)
+		txn.Postings = []*model.Posting{
+			model.Debit(model.PayoutsInTransit, payout.Amount),
+			model.Credit(model.PlatformCash, payout.Amount),
+		}
+	} else {
+		// The money never left, so it goes back to the seller's balance.
+		txn.Kind = model.KindPayoutFailed
+		txn.Description = fmt.Sprintf("Payout to seller %d failed: %s", payout.SellerID, reason)
+		txn.Postings = []*model.Posting{
+			model.Debit(model.PayoutsInTransit, payout.Amount),
+			model.Credit(model.SellerPayable(payout.SellerID), payout.Amount),
+		}
+	}
+
+	from := payout.Status
+	payout.Status = next
+	payout.FailureReason = ""
+	if next == model.PayoutFailed {
+		payout.FailureReason = reason
+	}
+
+	if err := s.payoutRepo.UpdateStatus(ctx, payout, from, txn); err != nil {
+		return nil, err
+	}
+
+	return toPayoutResponse(payout), nil
+}
+
+// RunPayouts implements PayoutService.
+func (s *payoutServiceImpl) RunPayouts(ctx context.Context) {
+	ticker := time.NewTicker(s.payoutInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		batch, err := s.CreatePayoutBatch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to create payout batch: %v", err)
+			}
+			continue
+		}
+
+		logger.Infof("Created payout batch %s with %d payouts totalling %d %s",
+			batch.ID, batch.PayoutCount, batch.Total, batch.Currency)
+	}
+}
+
+func toPayoutBatchResponse(batch *model.PayoutBatch) *dto.PayoutBatchResponse {
+	resp := &dto.PayoutBatchResponse{
+		ID:            batch.ID,
+		HeldAfter:     batch.HeldAfter.Format(time.RFC3339),
+		MinimumAmount: batch.MinimumAmount,
+		PayoutCount:   batch.PayoutCount,
+		Total:         batch.Total,
+		Currency:      batch.Currency,
+		Payouts:       make([]*dto.PayoutResponse, 0, len(batch.Payouts)),
+		CreatedAt:     batch.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, payout := range batch.Payouts {
+		resp.Payouts = append(resp.Payouts, toPayoutResponse(payout))
+	}
+
+	return resp
+}
+
+func toPayoutResponse(payout *model.Payout) *dto.PayoutResponse {
+	return &dto.PayoutResponse{
+		ID:            payout.ID,
+		BatchID:       payout.BatchID,
+		SellerID:      payout.SellerID,
+		Amount:        payout.Amount,
+		Currency:      payout.Currency,
+		Status:        string(payout.Status),
+		FailureReason: payout.FailureReason,
+		CreatedAt:     payout.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     payout.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,111 @@
+-- Double-entry ledger. Every transaction has postings that sum to zero:
+-- positive amounts are debits, negative amounts credits, in minor units.
+CREATE TABLE ledger_accounts (
+    code VARCHAR(100) PRIMARY KEY,          -- e.g. platform:cash or seller:42:payable
+    type VARCHAR(20) NOT NULL,
+    seller_id BIGINT,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT ledger_accounts_type_check CHECK (type IN ('asset', 'liability', 'revenue'))
+);
+
+CREATE TABLE ledger_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(30) NOT NULL,
+    reference VARCHAR(200) NOT NULL,        -- what the transaction is idempotent on
+    order_id UUID,
+    seller_id BIGINT,
+    payout_id UUID,
+    description TEXT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, reference),
+    CONSTRAINT ledger_transactions_kind_check CHECK (kind IN (
+        'order_capture', 'order_refund', 'payout', 'payout_settled', 'payout_failed'
+    ))
+);
+
+CREATE TABLE ledger_postings (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(id),
+    account_code VARCHAR(100) NOT NULL REFERENCES ledger_accounts(code),
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recorded entries are never changed; mistakes are corrected by new
+-- transactions. The repository only ever inserts, and these triggers stop
+-- anything else from rewriting history.
+CREATE FUNCTION ledger_reject_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are immutable: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_accounts_immutable
+    BEFORE UPDATE OR DELETE ON ledger_accounts
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER ledger_transactions_immutable
+    BEFORE UPDATE OR DELETE ON ledger_transactions
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER ledger_postings_immutable
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER ledger_accounts_no_truncate
+    BEFORE TRUNCATE ON ledger_accounts
+    FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER ledger_transactions_no_truncate
+    BEFORE TRUNCATE ON ledger_transactions
+    FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER ledger_postings_no_truncate
+    BEFORE TRUNCATE ON ledger_postings
+    FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_change();
+
+-- Checked at commit, once all postings of a transaction are in.
+CREATE FUNCTION ledger_check_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_postings WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % does not balance', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- A payout batch is one run of the payout job. Payouts move through
+-- pending -> paid | failed; the ledger records each step.
+CREATE TABLE payout_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    held_after TIMESTAMP NOT NULL,          -- earnings recorded later were held back
+    minimum_amount BIGINT NOT NULL,
+    payout_count INT NOT NULL,
+    total BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id UUID NOT NULL REFERENCES payout_batches(id),
+    seller_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    failure_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT payouts_status_check CHECK (status IN ('pending', 'paid', 'failed'))
+);
+
+-- Indexes
+CREATE INDEX idx_ledger_transactions_order_id ON ledger_transactions(order_id);
+CREATE INDEX idx_ledger_transactions_created_at ON ledger_transactions(created_at);
+CREATE INDEX idx_ledger_postings_transaction_id ON ledger_postings(transaction_id);
+CREATE INDEX idx_ledger_postings_account_code ON ledger_postings(account_code, created_at);
+CREATE INDEX idx_payouts_batch_id ON payouts(batch_id);
+CREATE INDEX idx_payouts_seller_id ON payouts(seller_id);
+CREATE INDEX idx_payouts_status ON payouts(status);
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("order-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	orderRepo := repository.NewOrderRepository(db)
+	returnRepo := repository.NewReturnRepository(db)
+
+	catalog := client.NewProductCatalog(cfg.ProductServiceName, srv.Client())
+	inventory := client.NewInventory(cfg.InventoryServiceName, srv.Client())
+	payments := client.NewPayments(cfg.PaymentServiceName, srv.Client())
+	ledger := client.NewLedger(cfg.LedgerServiceName, srv.Client())
+	taxes := client.NewTaxes(cfg.TaxServiceName, srv.Client())
+
+	orderService := service.NewOrderService(orderRepo, catalog, taxes, inventory, payments, ledger)
+	returnService := service.NewReturnService(returnRepo, orderRepo, inventory, payments, ledger, cfg.ReturnWindow)
+
+	orderHandler := handler.NewOrderHandler(orderService, returnService)
+
+	if err := pb.RegisterOrderServiceHandler(srv.Server(), orderHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	inventorypb "github.com/Dzaakk/micro-commerce/services/inventory-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+type inventory struct {
+	inventory inventorypb.InventoryService
+}
+
+// NewInventory restocks returned goods through the inventory service
+// registered under name.
+func NewInventory(name string, c client.Client) service.Inventory {
+	return &inventory{
+		inventory: inventorypb.NewInventoryService(name, c),
+	}
+}
+
+// Restock relies on the checkout id being the reference of the stock
+// reservation the order was fulfilled from.
+func (i *inventory) Restock(ctx context.Context, reference, checkoutID string, items []*model.ReturnItem) error {
+	req := &inventorypb.RestockRequest{
+		Reference:            reference,
+		ReservationReference: checkoutID,
+	}
+	for _, item := range items {
+		req.Lines = append(req.Lines, &inventorypb.ReserveLine{
+			Sku:      item.SKU,
+			Quantity: int32(item.Quantity),
+		})
+	}
+
+	_, err := i.inventory.Restock(ctx, req)
+	return err
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+
+	ledgerpb "github.com/Dzaakk/micro-commerce/services/ledger-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+type ledger struct {
+	ledger ledgerpb.LedgerService
+}
+
+// NewLedger books order money through the ledger service registered under
+// name.
+func NewLedger(name string, c client.Client) service.Ledger {
+	return &ledger{
+		ledger: ledgerpb.NewLedgerService(name, c),
+	}
+}
+
+func (l *ledger) RecordCapture(ctx context.Context, order *model.Order) error {
+	amount, err := order.Rate().ToBase(order.Total)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.ledger.RecordOrderCapture(ctx, &ledgerpb.RecordOrderCaptureRequest{
+		OrderId:   order.ID,
+		SellerId:  order.SellerID,
+		PaymentId: order.PaymentID,
+		Amount:    amount,
+	})
+	return err
+}
+
+func (l *ledger) RecordRefund(ctx context.Context, order *model.Order, amount int64, reference string) error {
+	// The refunded total is converted rather than each refund on its own,
+	// so rounding never takes more out of the ledger than was captured.
+	rate := order.Rate()
+	before, err := rate.ToBase(order.RefundedTotal)
+	if err != nil {
+		return err
+	}
+	after, err := rate.ToBase(order.RefundedTotal + amount)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.ledger.RecordOrderRefund(ctx, &ledgerpb.RecordOrderRefundRequest{
+		OrderId:   order.ID,
+		Amount:    after - before,
+		Reference: reference,
+	})
+	return err
+}
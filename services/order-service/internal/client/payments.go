@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	paymentpb "github.com/Dzaakk/micro-commerce/services/payment-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type payments struct {
+	payments paymentpb.PaymentService
+}
+
+// NewPayments refunds payments through the payment service registered under
+// name.
+func NewPayments(name string, c client.Client) service.Payments {
+	return &payments{
+		payments: paymentpb.NewPaymentService(name, c),
+	}
+}
+
+func (p *payments) Refund(ctx context.Context, paymentID string, amount int64, reference string) error {
+	_, err := p.payments.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{
+		Id:        paymentID,
+		Amount:    amount,
+		Reference: reference,
+	})
+	return err
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	productpb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type productCatalog struct {
+	products productpb.ProductService
+}
+
+// NewProductCatalog looks SKUs up through the product service registered
+// under name.
+func NewProductCatalog(name string, c client.Client) service.ProductCatalog {
+	return &productCatalog{
+		products: productpb.NewProductService(name, c),
+	}
+}
+
+func (p *productCatalog) LookupSKUs(ctx context.Context, skus []string, currency string, exchangeRateID int64) (map[string]*model.SKUDetails, *model.ExchangeRate, error) {
+	resp, err := p.products.LookupSKUs(ctx, &productpb.LookupSKUsRequest{
+		Skus:           skus,
+		Currency:       currency,
+		ExchangeRateId: exchangeRateID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	details := make(map[string]*model.SKUDetails, len(resp.Items))
+	for _, item := range resp.Items {
+		details[item.Sku] = &model.SKUDetails{
+			SKU:         item.Sku,
+			ProductID:   item.ProductId,
+			VariantID:   item.VariantId,
+			ProductName: item.ProductName,
+			Size:        item.Size,
+			Color:       item.Color,
+			Price:       item.Price,
+			SellerID:    item.SellerId,
+			StoreID:     item.StoreId,
+		}
+	}
+
+	rate := &model.ExchangeRate{
+		ID:           resp.ExchangeRate.GetId(),
+		BaseCurrency: resp.ExchangeRate.GetBaseCurrency(),
+		Currency:     resp.ExchangeRate.GetCurrency(),
+		Rate:         resp.ExchangeRate.GetRate(),
+	}
+
+	return details, rate, nil
+}
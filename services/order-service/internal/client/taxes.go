@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	taxpb "github.com/Dzaakk/micro-commerce/services/tax-service/proto"
+
+	"go-micro.dev/v4/client"
+)
+
+type taxes struct {
+	taxes taxpb.TaxService
+}
+
+// NewTaxes calculates order taxes through the tax service registered under
+// name.
+func NewTaxes(name string, c client.Client) service.Taxes {
+	return &taxes{
+		taxes: taxpb.NewTaxService(name, c),
+	}
+}
+
+func (t *taxes) Calculate(ctx context.Context, address model.ShippingAddress, rulesVersion string, items []*model.OrderItem) (*model.TaxCalculation, error) {
+	lines := make([]*taxpb.TaxLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, &taxpb.TaxLine{
+			Sku:       item.SKU,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+		})
+	}
+
+	resp, err := t.taxes.CalculateTax(ctx, &taxpb.CalculateTaxRequest{
+		Country:      address.Country,
+		Region:       address.Region,
+		RulesVersion: rulesVersion,
+		Lines:        lines,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calculation := resp.Calculation
+	result := &model.TaxCalculation{
+		RulesVersion:     calculation.RulesVersion,
+		PricesIncludeTax: calculation.PricesIncludeTax,
+		Lines:            make(map[string]*model.LineTax, len(calculation.Lines)),
+	}
+	for _, line := range calculation.Lines {
+		taxed := &model.LineTax{
+			SKU:         line.Sku,
+			TaxCategory: line.TaxCategory,
+			Tax:         line.Tax,
+			Components:  make([]model.TaxComponent, 0, len(line.Components)),
+		}
+		for _, component := range line.Components {
+			taxed.Components = append(taxed.Components, model.TaxComponent{
+				Jurisdiction: component.Jurisdiction,
+				Name:         component.Name,
+				Type:         component.Type,
+				RateBPS:      int(component.RateBps),
+				Amount:       component.Amount,
+			})
+		}
+		result.Lines[line.Sku] = taxed
+	}
+
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Registry name of the service order lines are priced against.
+	ProductServiceName string
+
+	// Registry names of the services returns restock and refund through.
+	InventoryServiceName string
+	PaymentServiceName   string
+
+	// Registry name of the service seller earnings are booked in.
+	LedgerServiceName string
+
+	// Registry name of the service orders are taxed through.
+	TaxServiceName string
+
+	// How long after delivery customers may request a return.
+	ReturnWindow time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:                 getEnv("PORT", "8083"),
+		Environment:          os.Getenv("ENVIRONMENT"),
+		DatabaseURL:          os.Getenv("DATABASE_URL"),
+		ProductServiceName:   getEnv("PRODUCT_SERVICE_NAME", "product-service"),
+		InventoryServiceName: getEnv("INVENTORY_SERVICE_NAME", "inventory-service"),
+		PaymentServiceName:   getEnv("PAYMENT_SERVICE_NAME", "payment-service"),
+		LedgerServiceName:    getEnv("LEDGER_SERVICE_NAME", "ledger-service"),
+		TaxServiceName:       getEnv("TAX_SERVICE_NAME", "tax-service"),
+		ReturnWindow:         getDurationEnv("RETURN_WINDOW", 14*24*time.Hour),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
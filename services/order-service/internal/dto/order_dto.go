@@ -0,0 +1,165 @@
+package dto
+
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	PhoneNumber   string `json:"phone_number"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+type OrderLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+	Discount int64  `json:"discount"`
+}
+
+type SellerShipping struct {
+	SellerID int64  `json:"seller_id"`
+	Option   string `json:"option"`
+	Amount   int64  `json:"amount"`
+}
+
+type CreateOrdersRequest struct {
+	CheckoutID      string           `json:"checkout_id"`
+	CustomerID      int64            `json:"customer_id"`
+	Lines           []OrderLine      `json:"lines"`
+	ShippingAddress ShippingAddress  `json:"shipping_address"`
+	PaymentID       string           `json:"payment_id"`
+	TaxRulesVersion string           `json:"tax_rules_version"`
+	Shipping        []SellerShipping `json:"shipping"`
+	Currency        string           `json:"currency"`
+	ExchangeRateID  int64            `json:"exchange_rate_id"`
+}
+
+type ListOrdersRequest struct {
+	CustomerID int64  `json:"customer_id"`
+	SellerID   int64  `json:"seller_id"`
+	CheckoutID string `json:"checkout_id"`
+	Status     string `json:"status"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+}
+
+type ChangeOrderStatusRequest struct {
+	OrderID   string `json:"order_id"`
+	Status    string `json:"status"`
+	ActorID   int64  `json:"actor_id"`
+	ActorRole string `json:"actor_role"`
+	Reason    string `json:"reason"`
+}
+
+type OrderItemResponse struct {
+	ProductID    string                  `json:"product_id"`
+	VariantID    string                  `json:"variant_id"`
+	SKU          string                  `json:"sku"`
+	ProductName  string                  `json:"product_name"`
+	Size         string                  `json:"size,omitempty"`
+	Color        string                  `json:"color,omitempty"`
+	UnitPrice    int64                   `json:"unit_price"`
+	Quantity     int                     `json:"quantity"`
+	LineTotal    int64                   `json:"line_total"`
+	Discount     int64                   `json:"discount"`
+	Tax          int64                   `json:"tax"`
+	TaxCategory  string                  `json:"tax_category"`
+	TaxBreakdown []*TaxComponentResponse `json:"tax_breakdown"`
+}
+
+type TaxComponentResponse struct {
+	Jurisdiction string `json:"jurisdiction"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	RateBPS      int    `json:"rate_bps"`
+	Amount       int64  `json:"amount"`
+}
+
+type OrderResponse struct {
+	ID               string               `json:"id"`
+	OrderNumber      string               `json:"order_number"`
+	CheckoutID       string               `json:"checkout_id"`
+	CustomerID       int64                `json:"customer_id"`
+	SellerID         int64                `json:"seller_id"`
+	StoreID          string               `json:"store_id"`
+	PaymentID        string               `json:"payment_id,omitempty"`
+	Status           string               `json:"status"`
+	Items            []*OrderItemResponse `json:"items"`
+	Subtotal         int64                `json:"subtotal"`
+	ShippingTotal    int64                `json:"shipping_total"`
+	ShippingOption   string               `json:"shipping_option,omitempty"`
+	TaxTotal         int64                `json:"tax_total"`
+	DiscountTotal    int64                `json:"discount_total"`
+	Total            int64                `json:"total"`
+	RefundedTotal    int64                `json:"refunded_total"`
+	ShippingAddress  ShippingAddress      `json:"shipping_address"`
+	TaxRulesVersion  string               `json:"tax_rules_version,omitempty"`
+	PricesIncludeTax bool                 `json:"prices_include_tax"`
+	Currency         string               `json:"currency,omitempty"`
+	BaseCurrency     string               `json:"base_currency,omitempty"`
+	ExchangeRate     string               `json:"exchange_rate,omitempty"`
+	ExchangeRateID   int64                `json:"exchange_rate_id,omitempty"`
+	CreatedAt        string               `json:"created_at"`
+	UpdatedAt        string               `json:"updated_at"`
+}
+
+type OrderStatusChangeResponse struct {
+	ReturnID   string `json:"return_id,omitempty"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ActorID    int64  `json:"actor_id"`
+	ActorRole  string `json:"actor_role"`
+	Reason     string `json:"reason,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type ReturnLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type CreateReturnRequest struct {
+	OrderID    string       `json:"order_id"`
+	CustomerID int64        `json:"customer_id"`
+	Lines      []ReturnLine `json:"lines"`
+	Reason     string       `json:"reason"`
+}
+
+type ListReturnsRequest struct {
+	OrderID    string `json:"order_id"`
+	CustomerID int64  `json:"customer_id"`
+	SellerID   int64  `json:"seller_id"`
+	Status     string `json:"status"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+}
+
+type ChangeReturnStatusRequest struct {
+	ReturnID     string `json:"return_id"`
+	Status       string `json:"status"`
+	ActorID      int64  `json:"actor_id"`
+	ActorRole    string `json:"actor_role"`
+	Reason       string `json:"reason"`
+	RefundAmount int64  `json:"refund_amount"`
+}
+
+type ReturnItemResponse struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+}
+
+type ReturnResponse struct {
+	ID           string                `json:"id"`
+	RMANumber    string                `json:"rma_number"`
+	OrderID      string                `json:"order_id"`
+	CustomerID   int64                 `json:"customer_id"`
+	SellerID     int64                 `json:"seller_id"`
+	Status       string                `json:"status"`
+	Reason       string                `json:"reason"`
+	Items        []*ReturnItemResponse `json:"items"`
+	RefundAmount int64                 `json:"refund_amount"`
+	CreatedAt    string                `json:"created_at"`
+	UpdatedAt    string                `json:"updated_at"`
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "order-service"
+
+type OrderHandler struct {
+	orderService  service.OrderService
+	returnService service.ReturnService
+}
+
+func NewOrderHandler(orderService service.OrderService, returnService service.ReturnService) *OrderHandler {
+	return &OrderHandler{
+		orderService:  orderService,
+		returnService: returnService,
+	}
+}
+
+func (h *OrderHandler) CreateOrders(ctx context.Context, req *pb.CreateOrdersRequest, rsp *pb.OrdersResponse) error {
+	lines := make([]dto.OrderLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, dto.OrderLine{
+			SKU:      line.Sku,
+			Quantity: int(line.Quantity),
+			Discount: line.Discount,
+		})
+	}
+
+	shipping := make([]dto.SellerShipping, 0, len(req.Shipping))
+	for _, charge := range req.Shipping {
+		shipping = append(shipping, dto.SellerShipping{
+			SellerID: charge.SellerId,
+			Option:   charge.Option,
+			Amount:   charge.Amount,
+		})
+	}
+
+	orders, err := h.orderService.CreateOrders(ctx, &dto.CreateOrdersRequest{
+		CheckoutID:      req.CheckoutId,
+		CustomerID:      req.CustomerId,
+		Lines:           lines,
+		ShippingAddress: fromProtoAddress(req.ShippingAddress),
+		PaymentID:       req.PaymentId,
+		TaxRulesVersion: req.TaxRulesVersion,
+		Shipping:        shipping,
+		Currency:        req.Currency,
+		ExchangeRateID:  req.ExchangeRateId,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, order := range orders {
+		rsp.Orders = append(rsp.Orders, toProtoOrder(order))
+	}
+	return nil
+}
+
+func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest, rsp *pb.OrderResponse) error {
+	order, err := h.orderService.GetOrder(ctx, req.Id, service.OrderScope{
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Order = toProtoOrder(order)
+	return nil
+}
+
+func (h *OrderHandler) ListOrders(ctx context.Context, req *pb.ListOrdersRequest, rsp *pb.ListOrdersResponse) error {
+	orders, total, err := h.orderService.ListOrders(ctx, &dto.ListOrdersRequest{
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+		CheckoutID: req.CheckoutId,
+		Status:     req.Status,
+		Page:       int(req.Page),
+		PageSize:   int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, order := range orders {
+		rsp.Orders = append(rsp.Orders, toProtoOrder(order))
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *OrderHandler) ChangeOrderStatus(ctx context.Context, req *pb.ChangeOrderStatusRequest, rsp *pb.OrderResponse) error {
+	order, err := h.orderService.ChangeOrderStatus(ctx, &dto.ChangeOrderStatusRequest{
+		OrderID:   req.Id,
+		Status:    req.Status,
+		ActorID:   req.ActorId,
+		ActorRole: req.ActorRole,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Order = toProtoOrder(order)
+	return nil
+}
+
+func (h *OrderHandler) GetOrderHistory(ctx context.Context, req *pb.GetOrderHistoryRequest, rsp *pb.GetOrderHistoryResponse) error {
+	changes, err := h.orderService.GetOrderHistory(ctx, req.Id, service.OrderScope{
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, change := range changes {
+		rsp.Changes = append(rsp.Changes, &pb.OrderStatusChange{
+			ReturnId:   change.ReturnID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			ActorId:    change.ActorID,
+			ActorRole:  change.ActorRole,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+	var upstreamErr *microerrors.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrOrderNotFound),
+		errors.Is(err, repository.ErrReturnNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return microerrors.Forbidden(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, service.ErrItemUnavailable),
+		errors.Is(err, repository.ErrStatusChanged),
+		errors.Is(err, service.ErrNotReturnable),
+		errors.Is(err, service.ErrReturnWindowClosed),
+		errors.Is(err, repository.ErrReturnQuantityExceeded),
+		errors.Is(err, repository.ErrRefundExceedsTotal):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	case errors.As(err, &upstreamErr) && upstreamErr.Code >= 400 && upstreamErr.Code < 500:
+		// Inventory or payments refused a restock or refund.
+		return microerrors.Conflict(serviceID, "%s: %s", upstreamErr.Id, upstreamErr.Detail)
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func fromProtoAddress(address *pb.ShippingAddress) dto.ShippingAddress {
+	if address == nil {
+		return dto.ShippingAddress{}
+	}
+
+	return dto.ShippingAddress{
+		RecipientName: address.RecipientName,
+		PhoneNumber:   address.PhoneNumber,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+	}
+}
+
+func toProtoOrder(order *dto.OrderResponse) *pb.Order {
+	resp := &pb.Order{
+		Id:             order.ID,
+		OrderNumber:    order.OrderNumber,
+		PaymentId:      order.PaymentID,
+		RefundedTotal:  order.RefundedTotal,
+		CheckoutId:     order.CheckoutID,
+		CustomerId:     order.CustomerID,
+		SellerId:       order.SellerID,
+		StoreId:        order.StoreID,
+		Status:         order.Status,
+		Subtotal:       order.Subtotal,
+		ShippingTotal:  order.ShippingTotal,
+		TaxTotal:       order.TaxTotal,
+		DiscountTotal:  order.DiscountTotal,
+		Total:          order.Total,
+		ShippingOption: order.ShippingOption,
+		ShippingAddress: &pb.ShippingAddress{
+			RecipientName: order.ShippingAddress.RecipientName,
+			PhoneNumber:   order.ShippingAddress.PhoneNumber,
+			Line1:         order.ShippingAddress.Line1,
+			Line2:         order.ShippingAddress.Line2,
+			City:          order.ShippingAddress.City,
+			Region:        order.ShippingAddress.Region,
+			PostalCode:    order.ShippingAddress.PostalCode,
+			Country:       order.ShippingAddress.Country,
+		},
+		TaxRulesVersion:  order.TaxRulesVersion,
+		PricesIncludeTax: order.PricesIncludeTax,
+		Currency:         order.Currency,
+		BaseCurrency:     order.BaseCurrency,
+		ExchangeRate:     order.ExchangeRate,
+		ExchangeRateId:   order.ExchangeRateID,
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
+	}
+
+	for _, item := range order.Items {
+		protoItem := &pb.OrderItem{
+			ProductId:   item.ProductID,
+			VariantId:   item.VariantID,
+			Sku:         item.SKU,
+			ProductName: item.ProductName,
+			Size:        item.Size,
+			Color:       item.Color,
+			UnitPrice:   item.UnitPrice,
+			Quantity:    int32(item.Quantity),
+			LineTotal:   item.LineTotal,
+			Discount:    item.Discount,
+			Tax:         item.Tax,
+			TaxCategory: item.TaxCategory,
+		}
+		for _, component := range item.TaxBreakdown {
+			protoItem.TaxBreakdown = append(protoItem.TaxBreakdown, &pb.TaxComponent{
+				Jurisdiction: component.Jurisdiction,
+				Name:         component.Name,
+				Type:         component.Type,
+				RateBps:      int32(component.RateBPS),
+				Amount:       component.Amount,
+			})
+		}
+		resp.Items = append(resp.Items, protoItem)
+	}
+
+	return resp
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+)
+
+func (h *OrderHandler) CreateReturn(ctx context.Context, req *pb.CreateReturnRequest, rsp *pb.ReturnResponse) error {
+	lines := make([]dto.ReturnLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, dto.ReturnLine{
+			SKU:      line.Sku,
+			Quantity: int(line.Quantity),
+		})
+	}
+
+	ret, err := h.returnService.CreateReturn(ctx, &dto.CreateReturnRequest{
+		OrderID:    req.OrderId,
+		CustomerID: req.CustomerId,
+		Lines:      lines,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Rma = toProtoReturn(ret)
+	return nil
+}
+
+func (h *OrderHandler) GetReturn(ctx context.Context, req *pb.GetReturnRequest, rsp *pb.ReturnResponse) error {
+	ret, err := h.returnService.GetReturn(ctx, req.Id, service.OrderScope{
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Rma = toProtoReturn(ret)
+	return nil
+}
+
+func (h *OrderHandler) ListReturns(ctx context.Context, req *pb.ListReturnsRequest, rsp *pb.ListReturnsResponse) error {
+	returns, total, err := h.returnService.ListReturns(ctx, &dto.ListReturnsRequest{
+		OrderID:    req.OrderId,
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+		Status:     req.Status,
+		Page:       int(req.Page),
+		PageSize:   int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, ret := range returns {
+		rsp.Returns = append(rsp.Returns, toProtoReturn(ret))
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *OrderHandler) ChangeReturnStatus(ctx context.Context, req *pb.ChangeReturnStatusRequest, rsp *pb.ReturnResponse) error {
+	ret, err := h.returnService.ChangeReturnStatus(ctx, &dto.ChangeReturnStatusRequest{
+		ReturnID:     req.Id,
+		Status:       req.Status,
+		ActorID:      req.ActorId,
+		ActorRole:    req.ActorRole,
+		Reason:       req.Reason,
+		RefundAmount: req.RefundAmount,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Rma = toProtoReturn(ret)
+	return nil
+}
+
+func toProtoReturn(ret *dto.ReturnResponse) *pb.Return {
+	resp := &pb.Return{
+		Id:           ret.ID,
+		RmaNumber:    ret.RMANumber,
+		OrderId:      ret.OrderID,
+		CustomerId:   ret.CustomerID,
+		SellerId:     ret.SellerID,
+		Status:       ret.Status,
+		Reason:       ret.Reason,
+		RefundAmount: ret.RefundAmount,
+		CreatedAt:    ret.CreatedAt,
+		UpdatedAt:    ret.UpdatedAt,
+	}
+
+	for _, item := range ret.Items {
+		resp.Items = append(resp.Items, &pb.ReturnItem{
+			Sku:       item.SKU,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: item.UnitPrice,
+		})
+	}
+
+	return resp
+}
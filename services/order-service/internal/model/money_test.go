@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToBase(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    ExchangeRate
+		amount  int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "identity ignores the rate", rate: ExchangeRate{BaseCurrency: "USD", Currency: "USD"}, amount: 1234, want: 1234},
+		{name: "same exponent", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "USD", Rate: "0.000061"}, amount: 6100, want: 100000000},
+		{name: "from a currency without decimals", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "JPY", Rate: "0.0093"}, amount: 9300, want: 100000000},
+		{name: "from a currency with three decimals", rate: ExchangeRate{BaseCurrency: "USD", Currency: "KWD", Rate: "0.307"}, amount: 307, want: 100},
+		{name: "half rounds up", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "2"}, amount: 1, want: 1},
+		{name: "negative half rounds away from zero", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "2"}, amount: -1, want: -1},
+		{name: "below half rounds down", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0.9"}, amount: 1000, want: 1111},
+		{name: "invalid rate", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0"}, amount: 100, wantErr: true},
+		{name: "overflow", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0.000000000001"}, amount: 1 << 62, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.ToBase(tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToBase(%d) error = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToBase(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "abc"}).ToBase(1); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("ToBase() error = %v, want %v", err, ErrInvalidRate)
+	}
+}
+
+func TestIsCurrencyCode(t *testing.T) {
+	tests := map[string]bool{"USD": true, "usd": false, "US": false, "USDT": false, "U5D": false, "": false}
+
+	for code, want := range tests {
+		if got := IsCurrencyCode(code); got != want {
+			t.Errorf("IsCurrencyCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
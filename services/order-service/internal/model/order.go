@@ -0,0 +1,223 @@
+package model
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions is the order lifecycle. Cancelling or refunding a paid
+// order gives the money back, and the stock too while the goods are still
+// with the seller; once goods are handed over only a refund can end the
+// order early.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+}
+
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type ActorRole string
+
+const (
+	ActorCustomer ActorRole = "customer"
+	ActorSeller   ActorRole = "seller"
+	ActorAdmin    ActorRole = "admin"
+	// ActorSystem is used by other services, e.g. payments marking an order paid.
+	ActorSystem ActorRole = "system"
+)
+
+// roleTargets limits which statuses each role may move an order to. Admins
+// and the system may make any transition the state machine allows.
+var roleTargets = map[ActorRole][]OrderStatus{
+	ActorCustomer: {OrderStatusCancelled},
+	ActorSeller:   {OrderStatusFulfilled, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled},
+	ActorAdmin:    nil,
+	ActorSystem:   nil,
+}
+
+func (r ActorRole) IsValid() bool {
+	_, ok := roleTargets[r]
+	return ok
+}
+
+func (r ActorRole) CanSet(status OrderStatus) bool {
+	targets, ok := roleTargets[r]
+	if !ok {
+		return false
+	}
+	if targets == nil {
+		return true
+	}
+	for _, allowed := range targets {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	PhoneNumber   string `json:"phone_number"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+type Order struct {
+	ID              string      `db:"id"`
+	OrderNumber     string      `db:"order_number"`
+	CheckoutID      string      `db:"checkout_id"`
+	CustomerID      int64       `db:"customer_id"`
+	SellerID        int64       `db:"seller_id"`
+	StoreID         string      `db:"store_id"`
+	PaymentID       string      `db:"payment_id"`
+	Status          OrderStatus `db:"status"`
+	Items           []*OrderItem
+	Subtotal        int64           `db:"subtotal"`
+	ShippingTotal   int64           `db:"shipping_total"`
+	TaxTotal        int64           `db:"tax_total"`
+	DiscountTotal   int64           `db:"discount_total"`
+	Total           int64           `db:"total"`
+	RefundedTotal   int64           `db:"refunded_total"`
+	ShippingAddress ShippingAddress `db:"shipping_address"`
+	// Version of the tax rules the order was taxed with, and whether its
+	// prices already include the tax or it was charged on top.
+	TaxRulesVersion  string `db:"tax_rules_version"`
+	PricesIncludeTax bool   `db:"prices_include_tax"`
+	// ShippingOption is the carrier:service the customer paid ShippingTotal
+	// for.
+	ShippingOption string `db:"shipping_option"`
+	// Every amount is in Currency. Prices are set in BaseCurrency and were
+	// converted at ExchangeRate, the product service rate ExchangeRateID,
+	// which is kept so the order's totals read the same after rates move.
+	Currency       string    `db:"currency"`
+	BaseCurrency   string    `db:"base_currency"`
+	ExchangeRateID int64     `db:"exchange_rate_id"`
+	ExchangeRate   string    `db:"exchange_rate"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// Rate is the exchange rate the order was priced with.
+func (o *Order) Rate() *ExchangeRate {
+	return &ExchangeRate{
+		ID:           o.ExchangeRateID,
+		BaseCurrency: o.BaseCurrency,
+		Currency:     o.Currency,
+		Rate:         o.ExchangeRate,
+	}
+}
+
+// PaidUnitPrice is what one unit of item cost the customer after promotions
+// and with any tax charged on top, rounded down so refunds never exceed what
+// was paid.
+func (o *Order) PaidUnitPrice(item *OrderItem) int64 {
+	paid := item.LineTotal - item.Discount
+	if !o.PricesIncludeTax {
+		paid += item.Tax
+	}
+	return paid / int64(item.Quantity)
+}
+
+type OrderItem struct {
+	ProductID   string `db:"product_id"`
+	VariantID   string `db:"variant_id"`
+	SKU         string `db:"sku"`
+	ProductName string `db:"product_name"`
+	Size        string `db:"size"`
+	Color       string `db:"color"`
+	UnitPrice   int64  `db:"unit_price"`
+	Quantity    int    `db:"quantity"`
+	LineTotal   int64  `db:"line_total"`
+	Discount    int64  `db:"discount"`
+	Tax         int64  `db:"tax"`
+	TaxCategory string `db:"tax_category"`
+	// TaxBreakdown is the tax per jurisdiction as calculated at purchase,
+	// kept so the order reads the same after the rules change.
+	TaxBreakdown []TaxComponent `db:"tax_breakdown"`
+}
+
+// TaxComponent is what one tax, such as a VAT or a state sales tax, added to
+// an order line.
+type TaxComponent struct {
+	Jurisdiction string `json:"jurisdiction"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	RateBPS      int    `json:"rate_bps"`
+	Amount       int64  `json:"amount"`
+}
+
+// LineTax is the tax service's answer for one SKU.
+type LineTax struct {
+	SKU         string
+	TaxCategory string
+	Tax         int64
+	Components  []TaxComponent
+}
+
+// TaxCalculation is the tax on a set of order lines, keyed by SKU.
+type TaxCalculation struct {
+	RulesVersion     string
+	PricesIncludeTax bool
+	Lines            map[string]*LineTax
+}
+
+// OrderStatusChange is an entry on the order timeline. Entries with a
+// ReturnID record a return transition and hold return statuses.
+type OrderStatusChange struct {
+	ID         int64       `db:"id"`
+	OrderID    string      `db:"order_id"`
+	ReturnID   string      `db:"return_id"`
+	FromStatus OrderStatus `db:"from_status"`
+	ToStatus   OrderStatus `db:"to_status"`
+	ActorID    int64       `db:"actor_id"`
+	ActorRole  ActorRole   `db:"actor_role"`
+	Reason     string      `db:"reason"`
+	// Refunded is the money given back with the change, added to the
+	// order's refunded total.
+	Refunded  int64     `db:"-"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SKUDetails is what the product service says about a SKU at purchase time.
+type SKUDetails struct {
+	SKU         string
+	ProductID   string
+	VariantID   string
+	ProductName string
+	Size        string
+	Color       string
+	Price       int64
+	SellerID    int64
+	StoreID     string
+}
@@ -0,0 +1,59 @@
+package model
+
+import "testing"
+
+var orderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusFulfilled,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+	OrderStatusRefunded,
+}
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	allowed := map[[2]OrderStatus]bool{
+		{OrderStatusPending, OrderStatusPaid}:       true,
+		{OrderStatusPending, OrderStatusCancelled}:  true,
+		{OrderStatusPaid, OrderStatusFulfilled}:     true,
+		{OrderStatusPaid, OrderStatusCancelled}:     true,
+		{OrderStatusPaid, OrderStatusRefunded}:      true,
+		{OrderStatusFulfilled, OrderStatusShipped}:  true,
+		{OrderStatusFulfilled, OrderStatusRefunded}: true,
+		{OrderStatusShipped, OrderStatusDelivered}:  true,
+		{OrderStatusDelivered, OrderStatusRefunded}: true,
+	}
+
+	for _, from := range orderStatuses {
+		for _, to := range orderStatuses {
+			want := allowed[[2]OrderStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestActorRoleCanSet(t *testing.T) {
+	allowed := map[ActorRole][]OrderStatus{
+		ActorCustomer: {OrderStatusCancelled},
+		ActorSeller:   {OrderStatusFulfilled, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled},
+		ActorAdmin:    orderStatuses,
+		ActorSystem:   orderStatuses,
+		"guest":       nil,
+	}
+
+	for role, targets := range allowed {
+		want := map[OrderStatus]bool{}
+		for _, status := range targets {
+			want[status] = true
+		}
+
+		for _, status := range orderStatuses {
+			if got := role.CanSet(status); got != want[status] {
+				t.Errorf("%s.CanSet(%s) = %v, want %v", role, status, got, want[status])
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import "time"
+
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusCancelled ReturnStatus = "cancelled"
+	ReturnStatusReceived  ReturnStatus = "received"
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+)
+
+// returnTransitions is the return lifecycle. Goods are restocked when the
+// seller receives them and money goes back only after that.
+var returnTransitions = map[ReturnStatus][]ReturnStatus{
+	ReturnStatusRequested: {ReturnStatusApproved, ReturnStatusRejected, ReturnStatusCancelled},
+	ReturnStatusApproved:  {ReturnStatusReceived, ReturnStatusCancelled},
+	ReturnStatusReceived:  {ReturnStatusRefunded},
+	ReturnStatusRejected:  {},
+	ReturnStatusCancelled: {},
+	ReturnStatusRefunded:  {},
+}
+
+func (s ReturnStatus) IsValid() bool {
+	_, ok := returnTransitions[s]
+	return ok
+}
+
+func (s ReturnStatus) CanTransitionTo(next ReturnStatus) bool {
+	for _, allowed := range returnTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// returnRoleTargets limits which statuses each role may move a return to.
+// Admins and the system may make any transition the state machine allows.
+var returnRoleTargets = map[ActorRole][]ReturnStatus{
+	ActorCustomer: {ReturnStatusCancelled},
+	ActorSeller:   {ReturnStatusApproved, ReturnStatusRejected, ReturnStatusReceived, ReturnStatusRefunded},
+	ActorAdmin:    nil,
+	ActorSystem:   nil,
+}
+
+func (r ActorRole) CanSetReturn(status ReturnStatus) bool {
+	targets, ok := returnRoleTargets[r]
+	if !ok {
+		return false
+	}
+	if targets == nil {
+		return true
+	}
+	for _, allowed := range targets {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Return is a customer's request to send back some lines of one order.
+type Return struct {
+	ID           string       `db:"id"`
+	RMANumber    string       `db:"rma_number"`
+	OrderID      string       `db:"order_id"`
+	CustomerID   int64        `db:"customer_id"`
+	SellerID     int64        `db:"seller_id"`
+	Status       ReturnStatus `db:"status"`
+	Reason       string       `db:"reason"`
+	Items        []*ReturnItem
+	RefundAmount int64     `db:"refund_amount"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// ItemsTotal is what the returned items were bought for.
+func (r *Return) ItemsTotal() int64 {
+	var total int64
+	for _, item := range r.Items {
+		total += item.UnitPrice * int64(item.Quantity)
+	}
+	return total
+}
+
+type ReturnItem struct {
+	SKU       string `db:"sku"`
+	Quantity  int    `db:"quantity"`
+	UnitPrice int64  `db:"unit_price"`
+}
+
+// ReturnStatusChange is a return transition as recorded on the order
+// timeline.
+type ReturnStatusChange struct {
+	ReturnID   string
+	OrderID    string
+	FromStatus ReturnStatus
+	ToStatus   ReturnStatus
+	ActorID    int64
+	ActorRole  ActorRole
+	Reason     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,55 @@
+package model
+
+import "testing"
+
+func TestReturnStatusCanTransitionTo(t *testing.T) {
+	statuses := []ReturnStatus{
+		ReturnStatusRequested,
+		ReturnStatusApproved,
+		ReturnStatusRejected,
+		ReturnStatusCancelled,
+		ReturnStatusReceived,
+		ReturnStatusRefunded,
+	}
+	allowed := map[[2]ReturnStatus]bool{
+		{ReturnStatusRequested, ReturnStatusApproved}:  true,
+		{ReturnStatusRequested, ReturnStatusRejected}:  true,
+		{ReturnStatusRequested, ReturnStatusCancelled}: true,
+		{ReturnStatusApproved, ReturnStatusReceived}:   true,
+		{ReturnStatusApproved, ReturnStatusCancelled}:  true,
+		{ReturnStatusReceived, ReturnStatusRefunded}:   true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]ReturnStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestActorRoleCanSetReturn(t *testing.T) {
+	tests := []struct {
+		role   ActorRole
+		status ReturnStatus
+		want   bool
+	}{
+		{ActorCustomer, ReturnStatusCancelled, true},
+		{ActorCustomer, ReturnStatusApproved, false},
+		{ActorCustomer, ReturnStatusRefunded, false},
+		{ActorSeller, ReturnStatusReceived, true},
+		{ActorSeller, ReturnStatusRefunded, true},
+		{ActorSeller, ReturnStatusCancelled, false},
+		{ActorAdmin, ReturnStatusCancelled, true},
+		{ActorSystem, ReturnStatusRefunded, true},
+		{"guest", ReturnStatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.CanSetReturn(tt.status); got != tt.want {
+			t.Errorf("%s.CanSetReturn(%s) = %v, want %v", tt.role, tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+)
+
+var (
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrCheckoutExists = errors.New("orders already exist for this checkout")
+	ErrStatusChanged  = errors.New("order status changed concurrently")
+)
+
+// OrderFilter selects orders for listings; zero fields are ignored.
+type OrderFilter struct {
+	CustomerID int64
+	SellerID   int64
+	CheckoutID string
+	Status     model.OrderStatus
+	Limit      int
+	Offset     int
+}
+
+type OrderRepository interface {
+	// CreateMany inserts the orders of one checkout with their items and
+	// first history entry in a single transaction. It fails with
+	// ErrCheckoutExists if the checkout already has orders.
+	CreateMany(ctx context.Context, orders []*model.Order) error
+	GetByID(ctx context.Context, id string) (*model.Order, error)
+	List(ctx context.Context, filter OrderFilter) ([]*model.Order, int, error)
+
+	// UpdateStatus moves the order from one status to another, adds what the
+	// change refunded to the order's refunded total and records the change in
+	// the history table. It fails with ErrStatusChanged if the order is no
+	// longer in the from status.
+	UpdateStatus(ctx context.Context, change *model.OrderStatusChange) error
+	ListStatusHistory(ctx context.Context, orderID string) ([]*model.OrderStatusChange, error)
+}
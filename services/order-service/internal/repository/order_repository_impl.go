@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const orderColumns = `
+	id, order_number, checkout_id, customer_id, seller_id, store_id, COALESCE(payment_id, ''), status,
+	subtotal, shipping_total, tax_total, discount_total, total, refunded_total, shipping_address,
+	tax_rules_version, prices_include_tax, COALESCE(shipping_option, ''),
+	COALESCE(currency, ''), COALESCE(base_currency, ''), exchange_rate_id, exchange_rate, created_at, updated_at
+`
+
+type orderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) OrderRepository {
+	return &orderRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrder(row rowScanner) (*model.Order, error) {
+	var order model.Order
+	var address []byte
+	err := row.Scan(
+		&order.ID,
+		&order.OrderNumber,
+		&order.CheckoutID,
+		&order.CustomerID,
+		&order.SellerID,
+		&order.StoreID,
+		&order.PaymentID,
+		&order.Status,
+		&order.Subtotal,
+		&order.ShippingTotal,
+		&order.TaxTotal,
+		&order.DiscountTotal,
+		&order.Total,
+		&order.RefundedTotal,
+		&address,
+		&order.TaxRulesVersion,
+		&order.PricesIncludeTax,
+		&order.ShippingOption,
+		&order.Currency,
+		&order.BaseCurrency,
+		&order.ExchangeRateID,
+		&order.ExchangeRate,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(address, &order.ShippingAddress); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *orderRepository) CreateMany(ctx context.Context, orders []*model.Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, order := range orders {
+		address, err := json.Marshal(order.ShippingAddress)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO orders (
+				order_number, checkout_id, customer_id, seller_id, store_id, payment_id, status,
+				subtotal, shipping_total, tax_total, discount_total, total, shipping_address,
+				tax_rules_version, prices_include_tax, shipping_option,
+				currency, base_currency, exchange_rate_id, exchange_rate
+			)
+			VALUES (
+				$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''),
+				$17, $18, $19, $20
+			)
+			RETURNING id, created_at, updated_at
+		`
+
+		err = tx.QueryRowContext(ctx, query,
+			order.OrderNumber,
+			order.CheckoutID,
+			order.CustomerID,
+			order.SellerID,
+			order.StoreID,
+			order.PaymentID,
+			order.Status,
+			order.Subtotal,
+			order.ShippingTotal,
+			order.TaxTotal,
+			order.DiscountTotal,
+			order.Total,
+			address,
+			order.TaxRulesVersion,
+			order.PricesIncludeTax,
+			order.ShippingOption,
+			order.Currency,
+			order.BaseCurrency,
+			order.ExchangeRateID,
+			order.ExchangeRate,
+		).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "orders_checkout_id_seller_id_key" {
+				return ErrCheckoutExists
+			}
+			return err
+		}
+
+		for _, item := range order.Items {
+			breakdown, err := json.Marshal(item.TaxBreakdown)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO order_items (
+					order_id, product_id, variant_id, sku, product_name, size, color,
+					unit_price, quantity, line_total, discount, tax, tax_category, tax_breakdown
+				)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			`,
+				order.ID,
+				item.ProductID,
+				item.VariantID,
+				item.SKU,
+				item.ProductName,
+				item.Size,
+				item.Color,
+				item.UnitPrice,
+				item.Quantity,
+				item.LineTotal,
+				item.Discount,
+				item.Tax,
+				item.TaxCategory,
+				breakdown,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_status_history (order_id, to_status, actor_id, actor_role)
+			VALUES ($1, $2, $3, $4)
+		`, order.ID, order.Status, order.CustomerID, model.ActorCustomer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *orderRepository) GetByID(ctx context.Context, id string) (*model.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx,
+		`SELECT `+orderColumns+` FROM orders WHERE id::text = $1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, []*model.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *orderRepository) List(ctx context.Context, filter OrderFilter) ([]*model.Order, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if filter.CustomerID != 0 {
+		addCondition("customer_id", filter.CustomerID)
+	}
+	if filter.SellerID != 0 {
+		addCondition("seller_id", filter.SellerID)
+	}
+	if filter.CheckoutID != "" {
+		addCondition("checkout_id", filter.CheckoutID)
+	}
+	if filter.Status != "" {
+		addCondition("status", filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM orders `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM orders %s ORDER BY created_at DESC, id ASC LIMIT $%d OFFSET $%d`,
+		orderColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []*model.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, change *model.OrderStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE orders SET status = $3, refunded_total = refunded_total + $4, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`, change.OrderID, change.FromStatus, change.ToStatus, change.Refunded)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrStatusChanged
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, actor_id, actor_role, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, change.OrderID, change.FromStatus, change.ToStatus, change.ActorID, change.ActorRole, change.Reason,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *orderRepository) ListStatusHistory(ctx context.Context, orderID string) ([]*model.OrderStatusChange, error) {
+	query := `
+		SELECT id, order_id, COALESCE(return_id::text, ''), COALESCE(from_status, ''), to_status,
+			actor_id, actor_role, COALESCE(reason, ''), created_at
+		FROM order_status_history
+		WHERE order_id::text = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.OrderStatusChange
+	for rows.Next() {
+		var change model.OrderStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.OrderID,
+			&change.ReturnID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.ActorID,
+			&change.ActorRole,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+func (r *orderRepository) loadItems(ctx context.Context, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT order_id, product_id, variant_id, sku, product_name, COALESCE(size, ''), COALESCE(color, ''),
+			unit_price, quantity, line_total, discount, tax, tax_category, tax_breakdown
+		FROM order_items
+		WHERE order_id::text = ANY($1)
+		ORDER BY id ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item model.OrderItem
+		var breakdown []byte
+		err := rows.Scan(
+			&orderID,
+			&item.ProductID,
+			&item.VariantID,
+			&item.SKU,
+			&item.ProductName,
+			&item.Size,
+			&item.Color,
+			&item.UnitPrice,
+			&item.Quantity,
+			&item.LineTotal,
+			&item.Discount,
+			&item.Tax,
+			&item.TaxCategory,
+			&breakdown,
+		)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(breakdown, &item.TaxBreakdown); err != nil {
+			return err
+		}
+		if order := byID[orderID]; order != nil {
+			order.Items = append(order.Items, &item)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+)
+
+var (
+	ErrReturnNotFound         = errors.New("return not found")
+	ErrReturnQuantityExceeded = errors.New("more items returned than were ordered")
+	ErrRefundExceedsTotal     = errors.New("refunds would exceed the order total")
+)
+
+// ReturnFilter selects returns for listings; zero fields are ignored.
+type ReturnFilter struct {
+	OrderID    string
+	CustomerID int64
+	SellerID   int64
+	Status     model.ReturnStatus
+	Limit      int
+	Offset     int
+}
+
+// ReturnRepository stores returns. Every transition is written to the order
+// timeline in the same transaction as the return itself.
+type ReturnRepository interface {
+	// Create inserts the return with its items and first timeline entry. The
+	// order is locked meanwhile, so concurrent requests can never return
+	// more than was ordered; that fails with ErrReturnQuantityExceeded.
+	Create(ctx context.Context, ret *model.Return) error
+	GetByID(ctx context.Context, id string) (*model.Return, error)
+	List(ctx context.Context, filter ReturnFilter) ([]*model.Return, int, error)
+
+	// UpdateStatus applies change to the return. It fails with
+	// ErrStatusChanged if the return is no longer in the from status.
+	UpdateStatus(ctx context.Context, change *model.ReturnStatusChange) error
+
+	// Refund moves the return to refunded with ret.RefundAmount and adds the
+	// amount to the order's refunded total. Once the whole order total is
+	// refunded, a delivered order becomes refunded too. It fails with
+	// ErrRefundExceedsTotal if the order has less left to refund.
+	Refund(ctx context.Context, ret *model.Return, change *model.ReturnStatusChange) error
+}
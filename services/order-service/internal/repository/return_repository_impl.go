@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const returnColumns = `
+	id, rma_number, order_id, customer_id, seller_id, status, reason, refund_amount,
+	created_at, updated_at
+`
+
+type returnRepository struct {
+	db *sql.DB
+}
+
+func NewReturnRepository(db *sql.DB) ReturnRepository {
+	return &returnRepository{db: db}
+}
+
+func scanReturn(row rowScanner) (*model.Return, error) {
+	var ret model.Return
+	err := row.Scan(
+		&ret.ID,
+		&ret.RMANumber,
+		&ret.OrderID,
+		&ret.CustomerID,
+		&ret.SellerID,
+		&ret.Status,
+		&ret.Reason,
+		&ret.RefundAmount,
+		&ret.CreatedAt,
+		&ret.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (r *returnRepository) Create(ctx context.Context, ret *model.Return) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM orders WHERE id = $1 FOR UPDATE`, ret.OrderID); err != nil {
+		return err
+	}
+
+	// Quantities still returnable per SKU: ordered minus what open or
+	// completed returns already cover.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT oi.sku, SUM(oi.quantity) - COALESCE((
+			SELECT SUM(ri.quantity)
+			FROM return_items ri
+			JOIN returns rt ON rt.id = ri.return_id
+			WHERE rt.order_id = oi.order_id AND ri.sku = oi.sku AND rt.status NOT IN ('rejected', 'cancelled')
+		), 0)
+		FROM order_items oi
+		WHERE oi.order_id = $1
+		GROUP BY oi.order_id, oi.sku
+	`, ret.OrderID)
+	if err != nil {
+		return err
+	}
+
+	returnable := map[string]int{}
+	for rows.Next() {
+		var sku string
+		var quantity int
+		if err := rows.Scan(&sku, &quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		returnable[sku] = quantity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, item := range ret.Items {
+		if item.Quantity > returnable[item.SKU] {
+			return fmt.Errorf("%w: %s", ErrReturnQuantityExceeded, item.SKU)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO returns (rma_number, order_id, customer_id, seller_id, status, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`,
+		ret.RMANumber,
+		ret.OrderID,
+		ret.CustomerID,
+		ret.SellerID,
+		ret.Status,
+		ret.Reason,
+	).Scan(&ret.ID, &ret.CreatedAt, &ret.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range ret.Items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO return_items (return_id, sku, quantity, unit_price)
+			VALUES ($1, $2, $3, $4)
+		`, ret.ID, item.SKU, item.Quantity, item.UnitPrice)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertReturnChange(ctx, tx, &model.ReturnStatusChange{
+		ReturnID:  ret.ID,
+		OrderID:   ret.OrderID,
+		ToStatus:  ret.Status,
+		ActorID:   ret.CustomerID,
+		ActorRole: model.ActorCustomer,
+		Reason:    ret.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *returnRepository) GetByID(ctx context.Context, id string) (*model.Return, error) {
+	ret, err := scanReturn(r.db.QueryRowContext(ctx,
+		`SELECT `+returnColumns+` FROM returns WHERE id::text = $1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrReturnNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, []*model.Return{ret}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *returnRepository) List(ctx context.Context, filter ReturnFilter) ([]*model.Return, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if filter.OrderID != "" {
+		addCondition("order_id::text", filter.OrderID)
+	}
+	if filter.CustomerID != 0 {
+		addCondition("customer_id", filter.CustomerID)
+	}
+	if filter.SellerID != 0 {
+		addCondition("seller_id", filter.SellerID)
+	}
+	if filter.Status != "" {
+		addCondition("status", filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM returns `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM returns %s ORDER BY created_at DESC, id ASC LIMIT $%d OFFSET $%d`,
+		returnColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var returns []*model.Return
+	for rows.Next() {
+		ret, err := scanReturn(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadItems(ctx, returns); err != nil {
+		return nil, 0, err
+	}
+
+	return returns, total, nil
+}
+
+func (r *returnRepository) UpdateStatus(ctx context.Context, change *model.ReturnStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setReturnStatus(ctx, tx, change, 0); err != nil {
+		return err
+	}
+
+	if err := insertReturnChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *returnRepository) Refund(ctx context.Context, ret *model.Return, change *model.ReturnStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status model.OrderStatus
+	var total, refunded int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, total, refunded_total FROM orders WHERE id = $1 FOR UPDATE`, ret.OrderID,
+	).Scan(&status, &total, &refunded)
+	if err == sql.ErrNoRows {
+		return ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if refunded+ret.RefundAmount > total {
+		return fmt.Errorf("%w: %d of %d already refunded", ErrRefundExceedsTotal, refunded, total)
+	}
+
+	if err := setReturnStatus(ctx, tx, change, ret.RefundAmount); err != nil {
+		return err
+	}
+	if err := insertReturnChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	refunded += ret.RefundAmount
+	next := status
+	if refunded == total && status.CanTransitionTo(model.OrderStatusRefunded) {
+		next = model.OrderStatusRefunded
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE orders SET refunded_total = $2, status = $3, updated_at = NOW()
+		WHERE id = $1
+	`, ret.OrderID, refunded, next)
+	if err != nil {
+		return err
+	}
+
+	if next != status {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_status_history (order_id, from_status, to_status, actor_id, actor_role, reason)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, ret.OrderID, status, next, change.ActorID, change.ActorRole, "Refunded in full by return "+ret.RMANumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// setReturnStatus moves a return between the statuses of change, setting
+// the refund amount if it is not zero.
+func setReturnStatus(ctx context.Context, tx *sql.Tx, change *model.ReturnStatusChange, refundAmount int64) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE returns
+		SET status = $3,
+			refund_amount = CASE WHEN $4 > 0 THEN $4 ELSE refund_amount END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`, change.ReturnID, change.FromStatus, change.ToStatus, refundAmount)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrStatusChanged
+	}
+
+	return nil
+}
+
+func insertReturnChange(ctx context.Context, tx *sql.Tx, change *model.ReturnStatusChange) error {
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO order_status_history (order_id, return_id, from_status, to_status, actor_id, actor_role, reason)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		RETURNING created_at
+	`,
+		change.OrderID,
+		change.ReturnID,
+		change.FromStatus,
+		change.ToStatus,
+		change.ActorID,
+		change.ActorRole,
+		change.Reason,
+	).Scan(&change.CreatedAt)
+}
+
+func (r *returnRepository) loadItems(ctx context.Context, returns []*model.Return) error {
+	if len(returns) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Return, len(returns))
+	ids := make([]string, 0, len(returns))
+	for _, ret := range returns {
+		byID[ret.ID] = ret
+		ids = append(ids, ret.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT return_id, sku, quantity, unit_price
+		FROM return_items
+		WHERE return_id::text = ANY($1)
+		ORDER BY id ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var returnID string
+		var item model.ReturnItem
+		if err := rows.Scan(&returnID, &item.SKU, &item.Quantity, &item.UnitPrice); err != nil {
+			return err
+		}
+		if ret := byID[returnID]; ret != nil {
+			ret.Items = append(ret.Items, &item)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+)
+
+var (
+	ErrInvalidTransition = errors.New("order status transition not allowed")
+	ErrForbidden         = errors.New("not allowed to set this order status")
+	ErrItemUnavailable   = errors.New("item is not available")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ProductCatalog resolves SKUs against the product service, priced in
+// currency at the rate exchangeRateID, or the current rate when it is zero.
+// SKUs that are unknown or not for sale are left out of the result.
+type ProductCatalog interface {
+	LookupSKUs(ctx context.Context, skus []string, currency string, exchangeRateID int64) (map[string]*model.SKUDetails, *model.ExchangeRate, error)
+}
+
+// Taxes calculates the tax on order items through the tax service. An
+// empty rulesVersion uses the rules in effect now.
+type Taxes interface {
+	Calculate(ctx context.Context, address model.ShippingAddress, rulesVersion string, items []*model.OrderItem) (*model.TaxCalculation, error)
+}
+
+// Ledger books the money of paid orders to their sellers, in the base
+// currency. Captures are idempotent on the order and refunds on reference.
+// RecordRefund expects the order's RefundedTotal from before the refund.
+type Ledger interface {
+	RecordCapture(ctx context.Context, order *model.Order) error
+	RecordRefund(ctx context.Context, order *model.Order, amount int64, reference string) error
+}
+
+// OrderScope restricts which orders a caller can see. A zero scope sees
+// everything and is meant for admins and other services.
+type OrderScope struct {
+	CustomerID int64
+	SellerID   int64
+}
+
+// OrderService places orders and drives them through their lifecycle
+type OrderService interface {
+	CreateOrders(ctx context.Context, req *dto.CreateOrdersRequest) ([]*dto.OrderResponse, error)
+	GetOrder(ctx context.Context, id string, scope OrderScope) (*dto.OrderResponse, error)
+	ListOrders(ctx context.Context, req *dto.ListOrdersRequest) ([]*dto.OrderResponse, int, error)
+
+	// ChangeOrderStatus applies a transition on behalf of an actor. Customers
+	// and sellers can only act on their own orders. Cancelling or refunding
+	// a paid order refunds the payment and restocks goods not yet shipped,
+	// except for the system, whose cancellations undo a failed checkout that
+	// gives the money and stock back itself.
+	ChangeOrderStatus(ctx context.Context, req *dto.ChangeOrderStatusRequest) (*dto.OrderResponse, error)
+	GetOrderHistory(ctx context.Context, id string, scope OrderScope) ([]*dto.OrderStatusChangeResponse, error)
+}
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/repository"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxOrderLines   = 100
+	maxLineQuantity = 99
+	maxReasonLength = 500
+	numberSuffix    = 8
+	numberCharset   = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// maxShippingOptionLength fits a carrier:service pair.
+	maxShippingOptionLength = 61
+)
+
+type orderServiceImpl struct {
+	orderRepo repository.OrderRepository
+	catalog   ProductCatalog
+	taxes     Taxes
+	inventory Inventory
+	payments  Payments
+	ledger    Ledger
+}
+
+func NewOrderService(
+	orderRepo repository.OrderRepository,
+	catalog ProductCatalog,
+	taxes Taxes,
+	inventory Inventory,
+	payments Payments,
+	ledger Ledger,
+) OrderService {
+	return &orderServiceImpl{
+		orderRepo: orderRepo,
+		catalog:   catalog,
+		taxes:     taxes,
+		inventory: inventory,
+		payments:  payments,
+		ledger:    ledger,
+	}
+}
+
+// CreateOrders implements OrderService.
+func (s *orderServiceImpl) CreateOrders(ctx context.Context, req *dto.CreateOrdersRequest) ([]*dto.OrderResponse, error) {
+	checkoutID := strings.TrimSpace(req.CheckoutID)
+	address := normalizeAddress(req.ShippingAddress)
+
+	invalid := map[string]string{}
+	if checkoutID == "" || len(checkoutID) > 100 {
+		invalid["checkout_id"] = "must be between 1 and 100 characters"
+	}
+	if req.CustomerID <= 0 {
+		invalid["customer_id"] = "is required"
+	}
+	if len(req.Lines) == 0 || len(req.Lines) > maxOrderLines {
+		invalid["lines"] = fmt.Sprintf("must contain between 1 and %d lines", maxOrderLines)
+	}
+	validateAddress(invalid, address)
+
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency != "" && !model.IsCurrencyCode(currency) {
+		invalid["currency"] = "must be an ISO 4217 code"
+	}
+	if req.ExchangeRateID < 0 {
+		invalid["exchange_rate_id"] = "must not be negative"
+	}
+
+	// Merge repeated SKUs, keeping the order they were first given in.
+	var skus []string
+	quantities := map[string]int{}
+	discounts := map[string]int64{}
+	for i, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		if sku == "" {
+			invalid[fmt.Sprintf("lines[%d].sku", i)] = "is required"
+			continue
+		}
+		if line.Quantity <= 0 || line.Quantity > maxLineQuantity {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = fmt.Sprintf("must be between 1 and %d", maxLineQuantity)
+			continue
+		}
+		if line.Discount < 0 {
+			invalid[fmt.Sprintf("lines[%d].discount", i)] = "must not be negative"
+			continue
+		}
+		if _, seen := quantities[sku]; !seen {
+			skus = append(skus, sku)
+		}
+		quantities[sku] += line.Quantity
+		discounts[sku] += line.Discount
+	}
+
+	shipping := map[int64]dto.SellerShipping{}
+	for i, charge := range req.Shipping {
+		charge.Option = strings.TrimSpace(charge.Option)
+		if _, seen := shipping[charge.SellerID]; seen || charge.SellerID <= 0 {
+			invalid[fmt.Sprintf("shipping[%d].seller_id", i)] = "must be a seller given only once"
+			continue
+		}
+		if charge.Option == "" || len(charge.Option) > maxShippingOptionLength {
+			invalid[fmt.Sprintf("shipping[%d].option", i)] = fmt.Sprintf("must be between 1 and %d characters", maxShippingOptionLength)
+			continue
+		}
+		if charge.Amount < 0 {
+			invalid[fmt.Sprintf("shipping[%d].amount", i)] = "must not be negative"
+			continue
+		}
+		shipping[charge.SellerID] = charge
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	if existing, err := s.ordersForCheckout(ctx, checkoutID, req.CustomerID); err != nil || existing != nil {
+		return existing, err
+	}
+
+	// Prices are looked up at the rate the checkout was priced with, which
+	// every order keeps.
+	details, rate, err := s.catalog.LookupSKUs(ctx, skus, currency, req.ExchangeRateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One order per seller, in the order sellers first appear in the lines.
+	var orders []*model.Order
+	var items []*model.OrderItem
+	bySeller := map[int64]*model.Order{}
+	for _, sku := range skus {
+		product, ok := details[sku]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrItemUnavailable, sku)
+		}
+
+		order := bySeller[product.SellerID]
+		if order == nil {
+			number, err := newNumber("MC")
+			if err != nil {
+				return nil, err
+			}
+
+			order = &model.Order{
+				OrderNumber:     number,
+				CheckoutID:      checkoutID,
+				CustomerID:      req.CustomerID,
+				SellerID:        product.SellerID,
+				StoreID:         product.StoreID,
+				PaymentID:       strings.TrimSpace(req.PaymentID),
+				Status:          model.OrderStatusPending,
+				ShippingAddress: address,
+				Currency:        rate.Currency,
+				BaseCurrency:    rate.BaseCurrency,
+				ExchangeRateID:  rate.ID,
+				ExchangeRate:    rate.Rate,
+			}
+			bySeller[product.SellerID] = order
+			orders = append(orders, order)
+		}
+
+		quantity := quantities[sku]
+		item := &model.OrderItem{
+			ProductID:   product.ProductID,
+			VariantID:   product.VariantID,
+			SKU:         sku,
+			ProductName: product.ProductName,
+			Size:        product.Size,
+			Color:       product.Color,
+			UnitPrice:   product.Price,
+			Quantity:    quantity,
+			LineTotal:   product.Price * int64(quantity),
+			Discount:    discounts[sku],
+		}
+		if item.Discount > item.LineTotal {
+			return nil, &ValidationError{Fields: map[string]string{
+				"lines.discount": fmt.Sprintf("for %s exceeds the line total of %d", sku, item.LineTotal),
+			}}
+		}
+		order.Items = append(order.Items, item)
+		order.Subtotal += item.LineTotal
+		order.DiscountTotal += item.Discount
+		items = append(items, item)
+	}
+
+	for sellerID, charge := range shipping {
+		order, ok := bySeller[sellerID]
+		if !ok {
+			return nil, &ValidationError{Fields: map[string]string{
+				"shipping.seller_id": fmt.Sprintf("%d has no lines", sellerID),
+			}}
+		}
+		order.ShippingOption = charge.Option
+		order.ShippingTotal = charge.Amount
+	}
+
+	// Tax every order in one calculation with the rules the checkout priced
+	// with, so the orders add up to what the customer was charged.
+	taxes, err := s.taxes.Calculate(ctx, address, strings.TrimSpace(req.TaxRulesVersion), items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.TaxRulesVersion = taxes.RulesVersion
+		order.PricesIncludeTax = taxes.PricesIncludeTax
+
+		for _, item := range order.Items {
+			line, ok := taxes.Lines[item.SKU]
+			if !ok {
+				return nil, fmt.Errorf("tax service returned no tax for %s", item.SKU)
+			}
+			item.Tax = line.Tax
+			item.TaxCategory = line.TaxCategory
+			item.TaxBreakdown = line.Components
+			order.TaxTotal += item.Tax
+		}
+
+		order.Total = order.Subtotal + order.ShippingTotal - order.DiscountTotal
+		if !order.PricesIncludeTax {
+			order.Total += order.TaxTotal
+		}
+	}
+
+	if err := s.orderRepo.CreateMany(ctx, orders); err != nil {
+		if errors.Is(err, repository.ErrCheckoutExists) {
+			// Lost a race with a retry of the same checkout.
+			return s.ordersForCheckout(ctx, checkoutID, req.CustomerID)
+		}
+		return nil, err
+	}
+
+	resp := make([]*dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		resp = append(resp, toOrderResponse(order))
+	}
+
+	return resp, nil
+}
+
+// GetOrder implements OrderService.
+func (s *orderServiceImpl) GetOrder(ctx context.Context, id string, scope OrderScope) (*dto.OrderResponse, error) {
+	order, err := s.getScoped(ctx, id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// ListOrders implements OrderService.
+func (s *orderServiceImpl) ListOrders(ctx context.Context, req *dto.ListOrdersRequest) ([]*dto.OrderResponse, int, error) {
+	status := model.OrderStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return nil, 0, &ValidationError{Fields: map[string]string{"status": "is not a known order status"}}
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	orders, total, err := s.orderRepo.List(ctx, repository.OrderFilter{
+		CustomerID: req.CustomerID,
+		SellerID:   req.SellerID,
+		CheckoutID: strings.TrimSpace(req.CheckoutID),
+		Status:     status,
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		resp = append(resp, toOrderResponse(order))
+	}
+
+	return resp, total, nil
+}
+
+// ChangeOrderStatus implements OrderService.
+func (s *orderServiceImpl) ChangeOrderStatus(ctx context.Context, req *dto.ChangeOrderStatusRequest) (*dto.OrderResponse, error) {
+	next := model.OrderStatus(req.Status)
+	role := model.ActorRole(req.ActorRole)
+	reason := strings.TrimSpace(req.Reason)
+
+	invalid := map[string]string{}
+	if !next.IsValid() {
+		invalid["status"] = "is not a known order status"
+	}
+	if !role.IsValid() {
+		invalid["actor_role"] = "must be customer, seller, admin or system"
+	}
+	if len(reason) > maxReasonLength {
+		invalid["reason"] = fmt.Sprintf("must be at most %d characters", maxReasonLength)
+	}
+	if role != model.ActorSystem && reason == "" &&
+		(next == model.OrderStatusCancelled || next == model.OrderStatusRefunded) {
+		invalid["reason"] = "is required when cancelling or refunding"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	var scope OrderScope
+	switch role {
+	case model.ActorCustomer:
+		scope.CustomerID = req.ActorID
+	case model.ActorSeller:
+		scope.SellerID = req.ActorID
+	}
+
+	order, err := s.getScoped(ctx, req.OrderID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeating the last transition is a no-op so callers can retry safely.
+	if order.Status == next {
+		return toOrderResponse(order), nil
+	}
+
+	if !role.CanSet(next) {
+		return nil, ErrForbidden
+	}
+	if !order.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, next)
+	}
+
+	change := &model.OrderStatusChange{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   next,
+		ActorID:    req.ActorID,
+		ActorRole:  role,
+		Reason:     reason,
+	}
+
+	refunded, err := s.settle(ctx, order, next, role)
+	if err != nil {
+		return nil, err
+	}
+	change.Refunded = refunded
+
+	if err := s.book(ctx, order, next); err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, change); err != nil {
+		return nil, err
+	}
+
+	order.Status = next
+	order.RefundedTotal += refunded
+	order.UpdatedAt = change.CreatedAt
+
+	return toOrderResponse(order), nil
+}
+
+// settle gives the customer back what cancelling or refunding a paid order
+// takes from them: the money not refunded yet and, while the goods are still
+// with the seller, the stock. It returns the amount refunded. Both calls are
+// keyed by the order and its new status, so a retried transition does not
+// pay or restock twice.
+//
+// The system only cancels the orders of a failed checkout, whose saga
+// refunds the capture and returns the stock itself.
+func (s *orderServiceImpl) settle(ctx context.Context, order *model.Order, next model.OrderStatus, role model.ActorRole) (int64, error) {
+	if role == model.ActorSystem || order.Status == model.OrderStatusPending {
+		return 0, nil
+	}
+	if next != model.OrderStatusCancelled && next != model.OrderStatusRefunded {
+		return 0, nil
+	}
+
+	reference := order.ID + ":" + string(next)
+
+	remaining := order.Total - order.RefundedTotal
+	if remaining > 0 {
+		if order.PaymentID == "" {
+			return 0, fmt.Errorf("order %s has no payment to refund", order.ID)
+		}
+		if err := s.payments.Refund(ctx, order.PaymentID, remaining, reference); err != nil {
+			return 0, err
+		}
+	}
+
+	if order.Status == model.OrderStatusPaid || order.Status == model.OrderStatusFulfilled {
+		items := make([]*model.ReturnItem, 0, len(order.Items))
+		for _, item := range order.Items {
+			items = append(items, &model.ReturnItem{
+				SKU:       item.SKU,
+				Quantity:  item.Quantity,
+				UnitPrice: order.PaidUnitPrice(item),
+			})
+		}
+		if err := s.inventory.Restock(ctx, reference, order.CheckoutID, items); err != nil {
+			return 0, err
+		}
+	}
+
+	return max(remaining, 0), nil
+}
+
+// book records what moving order to next means for the seller's money. It
+// runs before the transition is stored, so a retried transition books it
+// again rather than not at all; the ledger ignores the repeat.
+func (s *orderServiceImpl) book(ctx context.Context, order *model.Order, next model.OrderStatus) error {
+	switch {
+	case next == model.OrderStatusPaid:
+		return s.ledger.RecordCapture(ctx, order)
+	case order.Status == model.OrderStatusPending:
+		// Nothing was captured, so there is nothing to give back.
+		return nil
+	case next == model.OrderStatusCancelled, next == model.OrderStatusRefunded:
+		if remaining := order.Total - order.RefundedTotal; remaining > 0 {
+			return s.ledger.RecordRefund(ctx, order, remaining, string(next))
+		}
+	}
+	return nil
+}
+
+// GetOrderHistory implements OrderService.
+func (s *orderServiceImpl) GetOrderHistory(ctx context.Context, id string, scope OrderScope) ([]*dto.OrderStatusChangeResponse, error) {
+	order, err := s.getScoped(ctx, id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.orderRepo.ListStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.OrderStatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, &dto.OrderStatusChangeResponse{
+			ReturnID:   change.ReturnID,
+			FromStatus: string(change.FromStatus),
+			ToStatus:   string(change.ToStatus),
+			ActorID:    change.ActorID,
+			ActorRole:  string(change.ActorRole),
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
+
+// getScoped loads an order and hides it if it lies outside scope, so other
+// customers' and sellers' orders are indistinguishable from missing ones.
+func (s *orderServiceImpl) getScoped(ctx context.Context, id string, scope OrderScope) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.CustomerID != 0 && order.CustomerID != scope.CustomerID {
+		return nil, repository.ErrOrderNotFound
+	}
+	if scope.SellerID != 0 && order.SellerID != scope.SellerID {
+		return nil, repository.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// ordersForCheckout returns the orders already created for checkoutID, or
+// nil if there are none.
+func (s *orderServiceImpl) ordersForCheckout(ctx context.Context, checkoutID string, customerID int64) ([]*dto.OrderResponse, error) {
+	orders, _, err := s.orderRepo.List(ctx, repository.OrderFilter{
+		CheckoutID: checkoutID,
+		Limit:      maxOrderLines,
+	})
+	if err != nil || len(orders) == 0 {
+		return nil, err
+	}
+
+	resp := make([]*dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		if order.CustomerID != customerID {
+			return nil, &ValidationError{Fields: map[string]string{"checkout_id": "is already used"}}
+		}
+		resp = append(resp, toOrderResponse(order))
+	}
+
+	return resp, nil
+}
+
+// newNumber returns a human friendly identifier such as an order or RMA
+// number: the prefix, today's date and a random suffix.
+func newNumber(prefix string) (string, error) {
+	buf := make([]byte, numberSuffix)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, numberSuffix)
+	for i, b := range buf {
+		suffix[i] = numberCharset[int(b)%len(numberCharset)]
+	}
+
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().UTC().Format("060102"), suffix), nil
+}
+
+func normalizeAddress(address dto.ShippingAddress) model.ShippingAddress {
+	return model.ShippingAddress{
+		RecipientName: strings.TrimSpace(address.RecipientName),
+		PhoneNumber:   strings.TrimSpace(address.PhoneNumber),
+		Line1:         strings.TrimSpace(address.Line1),
+		Line2:         strings.TrimSpace(address.Line2),
+		City:          strings.TrimSpace(address.City),
+		Region:        strings.TrimSpace(address.Region),
+		PostalCode:    strings.TrimSpace(address.PostalCode),
+		Country:       strings.ToUpper(strings.TrimSpace(address.Country)),
+	}
+}
+
+func validateAddress(invalid map[string]string, address model.ShippingAddress) {
+	required := map[string]string{
+		"shipping_address.recipient_name": address.RecipientName,
+		"shipping_address.phone_number":   address.PhoneNumber,
+		"shipping_address.line1":          address.Line1,
+		"shipping_address.city":           address.City,
+		"shipping_address.postal_code":    address.PostalCode,
+	}
+	for field, value := range required {
+		if value == "" {
+			invalid[field] = "is required"
+		}
+	}
+
+	if len(address.Country) != 2 {
+		invalid["shipping_address.country"] = "must be an ISO 3166-1 alpha-2 code"
+	}
+}
+
+func toOrderResponse(order *model.Order) *dto.OrderResponse {
+	resp := &dto.OrderResponse{
+		ID:             order.ID,
+		OrderNumber:    order.OrderNumber,
+		CheckoutID:     order.CheckoutID,
+		CustomerID:     order.CustomerID,
+		SellerID:       order.SellerID,
+		StoreID:        order.StoreID,
+		PaymentID:      order.PaymentID,
+		Status:         string(order.Status),
+		Items:          make([]*dto.OrderItemResponse, 0, len(order.Items)),
+		Subtotal:       order.Subtotal,
+		ShippingTotal:  order.ShippingTotal,
+		ShippingOption: order.ShippingOption,
+		TaxTotal:       order.TaxTotal,
+		DiscountTotal:  order.DiscountTotal,
+		Total:          order.Total,
+		RefundedTotal:  order.RefundedTotal,
+		ShippingAddress: dto.ShippingAddress{
+			RecipientName: order.ShippingAddress.RecipientName,
+			PhoneNumber:   order.ShippingAddress.PhoneNumber,
+			Line1:         order.ShippingAddress.Line1,
+			Line2:         order.ShippingAddress.Line2,
+			City:          order.ShippingAddress.City,
+			Region:        order.ShippingAddress.Region,
+			PostalCode:    order.ShippingAddress.PostalCode,
+			Country:       order.ShippingAddress.Country,
+		},
+		TaxRulesVersion:  order.TaxRulesVersion,
+		PricesIncludeTax: order.PricesIncludeTax,
+		Currency:         order.Currency,
+		BaseCurrency:     order.BaseCurrency,
+		ExchangeRate:     order.ExchangeRate,
+		ExchangeRateID:   order.ExchangeRateID,
+		CreatedAt:        order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        order.UpdatedAt.Format(time.RFC3339),
+	}
+
+	for _, item := range order.Items {
+		itemResp := &dto.OrderItemResponse{
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			SKU:          item.SKU,
+			ProductName:  item.ProductName,
+			Size:         item.Size,
+			Color:        item.Color,
+			UnitPrice:    item.UnitPrice,
+			Quantity:     item.Quantity,
+			LineTotal:    item.LineTotal,
+			Discount:     item.Discount,
+			Tax:          item.Tax,
+			TaxCategory:  item.TaxCategory,
+			TaxBreakdown: make([]*dto.TaxComponentResponse, 0, len(item.TaxBreakdown)),
+		}
+		for _, component := range item.TaxBreakdown {
+			itemResp.TaxBreakdown = append(itemResp.TaxBreakdown, &dto.TaxComponentResponse{
+				Jurisdiction: component.Jurisdiction,
+				Name:         component.Name,
+				Type:         component.Type,
+				RateBPS:      component.RateBPS,
+				Amount:       component.Amount,
+			})
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+
+	return resp
+}
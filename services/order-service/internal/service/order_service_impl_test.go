@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/repository"
+)
+
+type fakeOrderRepo struct {
+	orders  map[string]*model.Order
+	changes []*model.OrderStatusChange
+}
+
+func (r *fakeOrderRepo) CreateMany(ctx context.Context, orders []*model.Order) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, id string) (*model.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, repository.ErrOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (r *fakeOrderRepo) List(ctx context.Context, filter repository.OrderFilter) ([]*model.Order, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *fakeOrderRepo) UpdateStatus(ctx context.Context, change *model.OrderStatusChange) error {
+	order := r.orders[change.OrderID]
+	if order.Status != change.FromStatus {
+		return repository.ErrStatusChanged
+	}
+	order.Status = change.ToStatus
+	order.RefundedTotal += change.Refunded
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *fakeOrderRepo) ListStatusHistory(ctx context.Context, orderID string) ([]*model.OrderStatusChange, error) {
+	return r.changes, nil
+}
+
+type refundCall struct {
+	paymentID string
+	amount    int64
+	reference string
+}
+
+type fakePayments struct {
+	refunds []refundCall
+}
+
+func (p *fakePayments) Refund(ctx context.Context, paymentID string, amount int64, reference string) error {
+	p.refunds = append(p.refunds, refundCall{paymentID, amount, reference})
+	return nil
+}
+
+type fakeInventory struct {
+	restocked map[string]int
+}
+
+func (i *fakeInventory) Restock(ctx context.Context, reference, checkoutID string, items []*model.ReturnItem) error {
+	for _, item := range items {
+		i.restocked[item.SKU] += item.Quantity
+	}
+	return nil
+}
+
+type fakeLedger struct {
+	refunded int64
+}
+
+func (l *fakeLedger) RecordCapture(ctx context.Context, order *model.Order) error {
+	return nil
+}
+
+func (l *fakeLedger) RecordRefund(ctx context.Context, order *model.Order, amount int64, reference string) error {
+	l.refunded += amount
+	return nil
+}
+
+func TestChangeOrderStatus(t *testing.T) {
+	const (
+		customerID = 10
+		sellerID   = 20
+	)
+
+	tests := []struct {
+		name         string
+		from         model.OrderStatus
+		refunded     int64
+		req          dto.ChangeOrderStatusRequest
+		wantErr      error
+		wantRefund   int64
+		wantRestock  int
+		wantLedger   int64
+		wantRefunded int64
+	}{
+		{
+			name: "customer cancels pending",
+			from: model.OrderStatusPending,
+			req:  dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: customerID, ActorRole: "customer", Reason: "changed mind"},
+		},
+		{
+			name:         "customer cancels paid",
+			from:         model.OrderStatusPaid,
+			req:          dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: customerID, ActorRole: "customer", Reason: "changed mind"},
+			wantRefund:   1000,
+			wantRestock:  2,
+			wantLedger:   1000,
+			wantRefunded: 1000,
+		},
+		{
+			name:    "seller cancels fulfilled is not allowed by the state machine",
+			from:    model.OrderStatusFulfilled,
+			req:     dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: sellerID, ActorRole: "seller", Reason: "out of stock"},
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:         "seller cancels paid",
+			from:         model.OrderStatusPaid,
+			req:          dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: sellerID, ActorRole: "seller", Reason: "out of stock"},
+			wantRefund:   1000,
+			wantRestock:  2,
+			wantLedger:   1000,
+			wantRefunded: 1000,
+		},
+		{
+			name:         "admin refunds what is left of a delivered order",
+			from:         model.OrderStatusDelivered,
+			refunded:     300,
+			req:          dto.ChangeOrderStatusRequest{Status: "refunded", ActorID: 1, ActorRole: "admin", Reason: "damaged"},
+			wantRefund:   700,
+			wantLedger:   700,
+			wantRefunded: 1000,
+		},
+		{
+			name:       "system cancels paid order of a failed checkout",
+			from:       model.OrderStatusPaid,
+			req:        dto.ChangeOrderStatusRequest{Status: "cancelled", ActorRole: "system", Reason: "checkout failed"},
+			wantLedger: 1000,
+		},
+		{
+			name:    "customer cannot ship",
+			from:    model.OrderStatusFulfilled,
+			req:     dto.ChangeOrderStatusRequest{Status: "shipped", ActorID: customerID, ActorRole: "customer"},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "other customer",
+			from:    model.OrderStatusPaid,
+			req:     dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: customerID + 1, ActorRole: "customer", Reason: "mine"},
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name:    "delivered cannot be cancelled",
+			from:    model.OrderStatusDelivered,
+			req:     dto.ChangeOrderStatusRequest{Status: "cancelled", ActorID: 1, ActorRole: "admin", Reason: "late"},
+			wantErr: ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOrderRepo{orders: map[string]*model.Order{
+				"o1": {
+					ID:            "o1",
+					CheckoutID:    "c1",
+					CustomerID:    customerID,
+					SellerID:      sellerID,
+					PaymentID:     "p1",
+					Status:        tt.from,
+					Total:         1000,
+					RefundedTotal: tt.refunded,
+					Items: []*model.OrderItem{
+						{SKU: "SKU-1", Quantity: 2, UnitPrice: 450, LineTotal: 900},
+					},
+				},
+			}}
+			payments := &fakePayments{}
+			inventory := &fakeInventory{restocked: map[string]int{}}
+			ledger := &fakeLedger{}
+			svc := NewOrderService(repo, nil, nil, inventory, payments, ledger)
+
+			tt.req.OrderID = "o1"
+			_, err := svc.ChangeOrderStatus(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeOrderStatus() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var refunded int64
+			for _, call := range payments.refunds {
+				refunded += call.amount
+				if call.paymentID != "p1" || call.reference == "" {
+					t.Errorf("refund %+v, want payment p1 with a reference", call)
+				}
+			}
+			if refunded != tt.wantRefund {
+				t.Errorf("payment refunds = %d, want %d", refunded, tt.wantRefund)
+			}
+			if got := inventory.restocked["SKU-1"]; got != tt.wantRestock {
+				t.Errorf("restocked = %d, want %d", got, tt.wantRestock)
+			}
+			if ledger.refunded != tt.wantLedger {
+				t.Errorf("ledger refunds = %d, want %d", ledger.refunded, tt.wantLedger)
+			}
+			if got := repo.orders["o1"].RefundedTotal; tt.wantErr == nil && got != tt.wantRefunded {
+				t.Errorf("refunded total = %d, want %d", got, tt.wantRefunded)
+			}
+		})
+	}
+}
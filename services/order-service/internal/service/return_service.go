@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+)
+
+var (
+	ErrNotReturnable      = errors.New("order cannot be returned")
+	ErrReturnWindowClosed = errors.New("return window has closed")
+)
+
+// Inventory puts returned goods, and those of cancelled orders, back on
+// hand. Restock is idempotent on reference; checkoutID names the reservation
+// the goods were sold under.
+type Inventory interface {
+	Restock(ctx context.Context, reference, checkoutID string, items []*model.ReturnItem) error
+}
+
+// Payments refunds money through the payment service. Refund is idempotent
+// on reference.
+type Payments interface {
+	Refund(ctx context.Context, paymentID string, amount int64, reference string) error
+}
+
+// ReturnService handles returns (RMAs) of delivered orders
+type ReturnService interface {
+	// CreateReturn opens a return for some lines of one of the customer's
+	// delivered orders, within the return window.
+	CreateReturn(ctx context.Context, req *dto.CreateReturnRequest) (*dto.ReturnResponse, error)
+	GetReturn(ctx context.Context, id string, scope OrderScope) (*dto.ReturnResponse, error)
+	ListReturns(ctx context.Context, req *dto.ListReturnsRequest) ([]*dto.ReturnResponse, int, error)
+
+	// ChangeReturnStatus applies a transition on behalf of an actor.
+	// Receiving a return restocks its items and refunding it pays the
+	// customer back.
+	ChangeReturnStatus(ctx context.Context, req *dto.ChangeReturnStatusRequest) (*dto.ReturnResponse, error)
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/order-service/internal/repository"
+)
+
+const maxReturnLines = 100
+
+type returnServiceImpl struct {
+	returnRepo   repository.ReturnRepository
+	orderRepo    repository.OrderRepository
+	inventory    Inventory
+	payments     Payments
+	ledger       Ledger
+	returnWindow time.Duration
+}
+
+// NewReturnService creates a return service accepting returns for
+// returnWindow after an order was delivered.
+func NewReturnService(
+	returnRepo repository.ReturnRepository,
+	orderRepo repository.OrderRepository,
+	inventory Inventory,
+	payments Payments,
+	ledger Ledger,
+	returnWindow time.Duration,
+) ReturnService {
+	return &returnServiceImpl{
+		returnRepo:   returnRepo,
+		orderRepo:    orderRepo,
+		inventory:    inventory,
+		payments:     payments,
+		ledger:       ledger,
+		returnWindow: returnWindow,
+	}
+}
+
+// CreateReturn implements ReturnService.
+func (s *returnServiceImpl) CreateReturn(ctx context.Context, req *dto.CreateReturnRequest) (*dto.ReturnResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+
+	invalid := map[string]string{}
+	if req.CustomerID <= 0 {
+		invalid["customer_id"] = "is required"
+	}
+	if reason == "" || len(reason) > maxReasonLength {
+		invalid["reason"] = fmt.Sprintf("must be between 1 and %d characters", maxReasonLength)
+	}
+	if len(req.Lines) == 0 || len(req.Lines) > maxReturnLines {
+		invalid["lines"] = fmt.Sprintf("must contain between 1 and %d lines", maxReturnLines)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID != req.CustomerID {
+		return nil, repository.ErrOrderNotFound
+	}
+
+	if order.Status != model.OrderStatusDelivered {
+		return nil, fmt.Errorf("%w: order is %s", ErrNotReturnable, order.Status)
+	}
+
+	deliveredAt, err := s.deliveredAt(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(deliveredAt) > s.returnWindow {
+		return nil, ErrReturnWindowClosed
+	}
+
+	prices := make(map[string]int64, len(order.Items))
+	for _, item := range order.Items {
+		prices[item.SKU] = order.PaidUnitPrice(item)
+	}
+
+	// Merge repeated SKUs, keeping the order they were first given in.
+	var items []*model.ReturnItem
+	bySKU := map[string]*model.ReturnItem{}
+	for i, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		price, ok := prices[sku]
+		if !ok {
+			invalid[fmt.Sprintf("lines[%d].sku", i)] = "is not part of the order"
+			continue
+		}
+		if line.Quantity <= 0 {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = "must be greater than zero"
+			continue
+		}
+
+		if item := bySKU[sku]; item != nil {
+			item.Quantity += line.Quantity
+			continue
+		}
+		item := &model.ReturnItem{SKU: sku, Quantity: line.Quantity, UnitPrice: price}
+		bySKU[sku] = item
+		items = append(items, item)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	number, err := newNumber("RMA")
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &model.Return{
+		RMANumber:  number,
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		SellerID:   order.SellerID,
+		Status:     model.ReturnStatusRequested,
+		Reason:     reason,
+		Items:      items,
+	}
+
+	if err := s.returnRepo.Create(ctx, ret); err != nil {
+		return nil, err
+	}
+
+	return toReturnResponse(ret), nil
+}
+
+// GetReturn implements ReturnService.
+func (s *returnServiceImpl) GetReturn(ctx context.Context, id string, scope OrderScope) (*dto.ReturnResponse, error) {
+	ret, err := s.getScoped(ctx, id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturnResponse(ret), nil
+}
+
+// ListReturns implements ReturnService.
+func (s *returnServiceImpl) ListReturns(ctx context.Context, req *dto.ListReturnsRequest) ([]*dto.ReturnResponse, int, error) {
+	status := model.ReturnStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return nil, 0, &ValidationError{Fields: map[string]string{"status": "is not a known return status"}}
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	returns, total, err := s.returnRepo.List(ctx, repository.ReturnFilter{
+		OrderID:    strings.TrimSpace(req.OrderID),
+		CustomerID: req.CustomerID,
+		SellerID:   req.SellerID,
+		Status:     status,
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.ReturnResponse, 0, len(returns))
+	for _, ret := range returns {
+		resp = append(resp, toReturnResponse(ret))
+	}
+
+	return resp, total, nil
+}
+
+// ChangeReturnStatus implements ReturnService.
+func (s *returnServiceImpl) ChangeReturnStatus(ctx context.Context, req *dto.ChangeReturnStatusRequest) (*dto.ReturnResponse, error) {
+	next := model.ReturnStatus(req.Status)
+	role := model.ActorRole(req.ActorRole)
+	reason := strings.TrimSpace(req.Reason)
+
+	invalid := map[string]string{}
+	if !next.IsValid() {
+		invalid["status"] = "is not a known return status"
+	}
+	if !role.IsValid() {
+		invalid["actor_role"] = "must be customer, seller, admin or system"
+	}
+	if len(reason) > maxReasonLength {
+		invalid["reason"] = fmt.Sprintf("must be at most %d characters", maxReasonLength)
+	}
+	if role != model.ActorSystem && reason == "" && next == model.ReturnStatusRejected {
+		invalid["reason"] = "is required when rejecting"
+	}
+	if req.RefundAmount < 0 {
+		invalid["refund_amount"] = "must not be negative"
+	}
+	if req.RefundAmount != 0 && next != model.ReturnStatusRefunded {
+		invalid["refund_amount"] = "is only allowed when refunding"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	var scope OrderScope
+	switch role {
+	case model.ActorCustomer:
+		scope.CustomerID = req.ActorID
+	case model.ActorSeller:
+		scope.SellerID = req.ActorID
+	}
+
+	ret, err := s.getScoped(ctx, req.ReturnID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeating the last transition is a no-op so callers can retry safely.
+	if ret.Status == next {
+		return toReturnResponse(ret), nil
+	}
+
+	if !role.CanSetReturn(next) {
+		return nil, ErrForbidden
+	}
+	if !ret.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: return %s to %s", ErrInvalidTransition, ret.Status, next)
+	}
+
+	change := &model.ReturnStatusChange{
+		ReturnID:   ret.ID,
+		OrderID:    ret.OrderID,
+		FromStatus: ret.Status,
+		ToStatus:   next,
+		ActorID:    req.ActorID,
+		ActorRole:  role,
+		Reason:     reason,
+	}
+
+	switch next {
+	case model.ReturnStatusReceived:
+		err = s.receive(ctx, ret, change)
+	case model.ReturnStatusRefunded:
+		err = s.refund(ctx, ret, change, req.RefundAmount)
+	default:
+		err = s.returnRepo.UpdateStatus(ctx, change)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Status = next
+	ret.UpdatedAt = change.CreatedAt
+
+	return toReturnResponse(ret), nil
+}
+
+// receive restocks the returned items before recording the transition. The
+// restock is keyed by the return, so a retry after a failed update does not
+// count the goods twice.
+func (s *returnServiceImpl) receive(ctx context.Context, ret *model.Return, change *model.ReturnStatusChange) error {
+	order, err := s.orderRepo.GetByID(ctx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.inventory.Restock(ctx, ret.ID, order.CheckoutID, ret.Items); err != nil {
+		return err
+	}
+
+	return s.returnRepo.UpdateStatus(ctx, change)
+}
+
+// refund pays amount back, defaulting to what the returned items cost, and
+// records it against the order and in the seller's ledger. The payment and
+// ledger refunds are keyed by the return, so retrying after a failed update
+// does not pay twice.
+func (s *returnServiceImpl) refund(ctx context.Context, ret *model.Return, change *model.ReturnStatusChange, amount int64) error {
+	order, err := s.orderRepo.GetByID(ctx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if amount == 0 {
+		amount = ret.ItemsTotal()
+	}
+	if remaining := order.Total - order.RefundedTotal; amount > remaining {
+		return &ValidationError{Fields: map[string]string{
+			"refund_amount": fmt.Sprintf("must be at most the %d left to refund on the order", remaining),
+		}}
+	}
+	if order.PaymentID == "" {
+		return fmt.Errorf("%w: order has no payment to refund", ErrNotReturnable)
+	}
+
+	if err := s.payments.Refund(ctx, order.PaymentID, amount, ret.ID); err != nil {
+		return err
+	}
+	if err := s.ledger.RecordRefund(ctx, order, amount, ret.ID); err != nil {
+		return err
+	}
+
+	ret.RefundAmount = amount
+	return s.returnRepo.Refund(ctx, ret, change)
+}
+
+// deliveredAt finds when the order was last delivered, which starts the
+// return window.
+func (s *returnServiceImpl) deliveredAt(ctx context.Context, orderID string) (time.Time, error) {
+	changes, err := s.orderRepo.ListStatusHistory(ctx, orderID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.ReturnID == "" && change.ToStatus == model.OrderStatusDelivered {
+			return change.CreatedAt, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: no delivery recorded", ErrNotReturnable)
+}
+
+// getScoped loads a return and hides it if it lies outside scope, like
+// orderServiceImpl.getScoped does for orders.
+func (s *returnServiceImpl) getScoped(ctx context.Context, id string, scope OrderScope) (*model.Return, error) {
+	ret, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.CustomerID != 0 && ret.CustomerID != scope.CustomerID {
+		return nil, repository.ErrReturnNotFound
+	}
+	if scope.SellerID != 0 && ret.SellerID != scope.SellerID {
+		return nil, repository.ErrReturnNotFound
+	}
+
+	return ret, nil
+}
+
+func toReturnResponse(ret *model.Return) *dto.ReturnResponse {
+	resp := &dto.ReturnResponse{
+		ID:           ret.ID,
+		RMANumber:    ret.RMANumber,
+		OrderID:      ret.OrderID,
+		CustomerID:   ret.CustomerID,
+		SellerID:     ret.SellerID,
+		Status:       string(ret.Status),
+		Reason:       ret.Reason,
+		Items:        make([]*dto.ReturnItemResponse, 0, len(ret.Items)),
+		RefundAmount: ret.RefundAmount,
+		CreatedAt:    ret.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    ret.UpdatedAt.Format(time.RFC3339),
+	}
+
+	for _, item := range ret.Items {
+		resp.Items = append(resp.Items, &dto.ReturnItemResponse{
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,54 @@
+-- Each order belongs to a single seller. A checkout spanning several sellers
+-- creates one order per seller, all sharing the same checkout_id.
+CREATE TABLE orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_number VARCHAR(32) UNIQUE NOT NULL,
+    checkout_id VARCHAR(100) NOT NULL,
+    customer_id BIGINT NOT NULL,
+    seller_id BIGINT NOT NULL,
+    store_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'paid', 'fulfilled', 'shipped', 'delivered', 'cancelled', 'refunded'
+    subtotal BIGINT NOT NULL, -- amounts in minor units
+    shipping_total BIGINT NOT NULL DEFAULT 0,
+    tax_total BIGINT NOT NULL DEFAULT 0,
+    discount_total BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL,
+    shipping_address JSONB NOT NULL, -- snapshot of the address at checkout
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (checkout_id, seller_id)
+);
+
+-- Line items copy the product as it was at purchase time so later catalog
+-- edits never change past orders.
+CREATE TABLE order_items (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    variant_id UUID NOT NULL,
+    sku VARCHAR(64) NOT NULL,
+    product_name VARCHAR(200) NOT NULL,
+    size VARCHAR(50),
+    color VARCHAR(50),
+    unit_price BIGINT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    line_total BIGINT NOT NULL
+);
+
+CREATE TABLE order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_id BIGINT NOT NULL, -- 0 for system actions such as payment capture
+    actor_role VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX idx_orders_customer ON orders(customer_id, created_at DESC);
+CREATE INDEX idx_orders_seller ON orders(seller_id, created_at DESC);
+CREATE INDEX idx_orders_checkout ON orders(checkout_id);
+CREATE INDEX idx_order_items_order ON order_items(order_id);
+CREATE INDEX idx_order_status_history_order ON order_status_history(order_id);
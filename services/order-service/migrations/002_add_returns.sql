@@ -0,0 +1,37 @@
+-- Payment taken at checkout. One payment can cover several orders of the
+-- same checkout, so refunds are tracked per order.
+ALTER TABLE orders ADD COLUMN payment_id VARCHAR(100);
+ALTER TABLE orders ADD COLUMN refunded_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD CONSTRAINT orders_refunded_total_check CHECK (refunded_total <= total);
+
+-- A return (RMA) covers some lines of a single delivered order.
+CREATE TABLE returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rma_number VARCHAR(32) UNIQUE NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    customer_id BIGINT NOT NULL,
+    seller_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested', -- 'requested', 'approved', 'rejected', 'cancelled', 'received', 'refunded'
+    reason TEXT NOT NULL,
+    refund_amount BIGINT NOT NULL DEFAULT 0, -- set once refunded, in minor units
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE return_items (
+    id BIGSERIAL PRIMARY KEY,
+    return_id UUID NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price BIGINT NOT NULL -- copied from the order line
+);
+
+-- Return transitions share the order timeline; from_status and to_status
+-- then hold return statuses.
+ALTER TABLE order_status_history ADD COLUMN return_id UUID REFERENCES returns(id) ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX idx_returns_order ON returns(order_id);
+CREATE INDEX idx_returns_customer ON returns(customer_id, created_at DESC);
+CREATE INDEX idx_returns_seller ON returns(seller_id, created_at DESC);
+CREATE INDEX idx_return_items_return ON return_items(return_id);
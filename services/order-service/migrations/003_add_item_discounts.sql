@@ -0,0 +1,4 @@
+-- Promotion discounts granted at checkout, snapshotted per line so refunds
+-- give back what the customer actually paid.
+ALTER TABLE order_items ADD COLUMN discount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD CONSTRAINT order_items_discount_check CHECK (discount >= 0 AND discount <= line_total);
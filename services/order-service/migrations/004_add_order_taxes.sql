@@ -0,0 +1,9 @@
+-- Taxes calculated at checkout, snapshotted with the rules version used so
+-- orders and refunds keep their tax after the rule tables change.
+ALTER TABLE orders ADD COLUMN tax_rules_version VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE order_items ADD COLUMN tax BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN tax_category VARCHAR(30) NOT NULL DEFAULT 'standard';
+ALTER TABLE order_items ADD COLUMN tax_breakdown JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE order_items ADD CONSTRAINT order_items_tax_check CHECK (tax >= 0);
@@ -0,0 +1,3 @@
+-- Carrier service the customer paid shipping_total for, as
+-- "carrier:service"; the seller ships with it unless they pick another.
+ALTER TABLE orders ADD COLUMN shipping_option VARCHAR(61);
@@ -0,0 +1,8 @@
+-- Every amount on an order is in its currency. Prices are set in the base
+-- currency and converted at the rate in effect at checkout, which is kept
+-- so historical totals stay correct when rates change. Orders placed before
+-- this have no currency and are in the base currency.
+ALTER TABLE orders ADD COLUMN currency CHAR(3);
+ALTER TABLE orders ADD COLUMN base_currency CHAR(3);
+ALTER TABLE orders ADD COLUMN exchange_rate_id BIGINT NOT NULL DEFAULT 0; -- product service rate, 0 when currency is base_currency
+ALTER TABLE orders ADD COLUMN exchange_rate VARCHAR(25) NOT NULL DEFAULT '1'; -- units of currency per unit of base_currency
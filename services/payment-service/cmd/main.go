@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/provider"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/payment-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	if cfg.PaymentProvider != provider.FakeProviderName {
+		logger.Fatalf("Unknown payment provider %q", cfg.PaymentProvider)
+	}
+	if cfg.Environment == "production" {
+		logger.Fatal("The fake payment provider must not be used in production")
+	}
+	if cfg.FakeWebhookSecret == "" {
+		logger.Fatal("FAKE_PROVIDER_WEBHOOK_SECRET must be set")
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("payment-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	paymentRepo := repository.NewPaymentRepository(db)
+
+	// Without a URL the fake provider's webhooks skip the gateway but are
+	// still signed and verified like any other.
+	var paymentService service.PaymentService
+	send := provider.WebhookSender(func(payload []byte, headers map[string]string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := paymentService.HandleWebhook(ctx, provider.FakeProviderName, payload, headers); err != nil {
+			logger.Errorf("Failed to handle fake provider webhook: %v", err)
+		}
+	})
+	if cfg.FakeWebhookURL != "" {
+		send = provider.NewHTTPWebhookSender(cfg.FakeWebhookURL)
+	}
+
+	fakeProvider := provider.NewFakeProvider(
+		cfg.FakeWebhookSecret,
+		cfg.FakeChallengeDelay,
+		cfg.FakeSettlementDelay,
+		send,
+	)
+
+	paymentService = service.NewPaymentService(paymentRepo, fakeProvider)
+
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+
+	if err := pb.RegisterPaymentServiceHandler(srv.Server(), paymentHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Provider used for new payments. Only "fake" is built in.
+	PaymentProvider string
+
+	// Settings of the fake provider. Its webhooks are posted to
+	// FakeWebhookURL when set and handed to the service directly otherwise.
+	FakeWebhookSecret   string
+	FakeWebhookURL      string
+	FakeChallengeDelay  time.Duration
+	FakeSettlementDelay time.Duration
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:                getEnv("PORT", "8090"),
+		Environment:         os.Getenv("ENVIRONMENT"),
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+		PaymentProvider:     getEnv("PAYMENT_PROVIDER", "fake"),
+		FakeWebhookSecret:   os.Getenv("FAKE_PROVIDER_WEBHOOK_SECRET"),
+		FakeWebhookURL:      os.Getenv("FAKE_PROVIDER_WEBHOOK_URL"),
+		FakeChallengeDelay:  getDurationEnv("FAKE_PROVIDER_CHALLENGE_DELAY", 2*time.Second),
+		FakeSettlementDelay: getDurationEnv("FAKE_PROVIDER_SETTLEMENT_DELAY", 10*time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,25 @@
+package dto
+
+type AuthorizePaymentRequest struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	CustomerID     int64  `json:"customer_id"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	PaymentMethod  string `json:"payment_method"`
+}
+
+type PaymentResponse struct {
+	ID             string `json:"id"`
+	IdempotencyKey string `json:"idempotency_key"`
+	CustomerID     int64  `json:"customer_id"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	Provider       string `json:"provider"`
+	Status         string `json:"status"`
+	DeclineCode    string `json:"decline_code,omitempty"`
+	NextActionURL  string `json:"next_action_url,omitempty"`
+	CapturedAmount int64  `json:"captured_amount"`
+	RefundedAmount int64  `json:"refunded_amount"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
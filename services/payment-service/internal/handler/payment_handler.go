@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/provider"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/payment-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "payment-service"
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+func (h *PaymentHandler) AuthorizePayment(ctx context.Context, req *pb.AuthorizePaymentRequest, rsp *pb.PaymentResponse) error {
+	payment, err := h.paymentService.AuthorizePayment(ctx, &dto.AuthorizePaymentRequest{
+		IdempotencyKey: req.IdempotencyKey,
+		CustomerID:     req.CustomerId,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		PaymentMethod:  req.PaymentMethod,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payment = toProtoPayment(payment)
+	return nil
+}
+
+func (h *PaymentHandler) GetPayment(ctx context.Context, req *pb.GetPaymentRequest, rsp *pb.PaymentResponse) error {
+	payment, err := h.paymentService.GetPayment(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payment = toProtoPayment(payment)
+	return nil
+}
+
+func (h *PaymentHandler) CapturePayment(ctx context.Context, req *pb.CapturePaymentRequest, rsp *pb.PaymentResponse) error {
+	payment, err := h.paymentService.CapturePayment(ctx, req.Id, req.Amount)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payment = toProtoPayment(payment)
+	return nil
+}
+
+func (h *PaymentHandler) VoidPayment(ctx context.Context, req *pb.VoidPaymentRequest, rsp *pb.PaymentResponse) error {
+	payment, err := h.paymentService.VoidPayment(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payment = toProtoPayment(payment)
+	return nil
+}
+
+func (h *PaymentHandler) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest, rsp *pb.PaymentResponse) error {
+	payment, err := h.paymentService.RefundPayment(ctx, req.Id, req.Amount, req.Reference)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Payment = toProtoPayment(payment)
+	return nil
+}
+
+func (h *PaymentHandler) HandleWebhook(ctx context.Context, req *pb.HandleWebhookRequest, rsp *pb.HandleWebhookResponse) error {
+	applied, err := h.paymentService.HandleWebhook(ctx, req.Provider, req.Payload, req.Headers)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Applied = applied
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, provider.ErrInvalidSignature):
+		return microerrors.Unauthorized(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrPaymentNotFound),
+		errors.Is(err, service.ErrUnknownProvider):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrIdempotencyMismatch),
+		errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, repository.ErrStatusChanged),
+		errors.Is(err, repository.ErrRefundExceedsCapture):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoPayment(payment *dto.PaymentResponse) *pb.Payment {
+	return &pb.Payment{
+		Id:             payment.ID,
+		IdempotencyKey: payment.IdempotencyKey,
+		CustomerId:     payment.CustomerID,
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		Provider:       payment.Provider,
+		Status:         payment.Status,
+		DeclineCode:    payment.DeclineCode,
+		NextActionUrl:  payment.NextActionURL,
+		CapturedAmount: payment.CapturedAmount,
+		RefundedAmount: payment.RefundedAmount,
+		CreatedAt:      payment.CreatedAt,
+		UpdatedAt:      payment.UpdatedAt,
+	}
+}
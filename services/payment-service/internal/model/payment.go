@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	PaymentCreated PaymentStatus = "created"
+	// PaymentRequiresAction means the customer has to pass a 3-D Secure
+	// challenge; the provider reports the outcome by webhook.
+	PaymentRequiresAction PaymentStatus = "requires_action"
+	PaymentAuthorized     PaymentStatus = "authorized"
+	PaymentDeclined       PaymentStatus = "declined"
+	// PaymentCapturePending means the capture was accepted but the funds
+	// settle later; the provider confirms by webhook.
+	PaymentCapturePending PaymentStatus = "capture_pending"
+	PaymentCaptured       PaymentStatus = "captured"
+	PaymentVoided         PaymentStatus = "voided"
+	PaymentRefunded       PaymentStatus = "refunded"
+)
+
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentCreated:        {PaymentRequiresAction, PaymentAuthorized, PaymentDeclined},
+	PaymentRequiresAction: {PaymentAuthorized, PaymentDeclined, PaymentVoided},
+	PaymentAuthorized:     {PaymentCapturePending, PaymentCaptured, PaymentVoided},
+	PaymentCapturePending: {PaymentCaptured},
+	PaymentCaptured:       {PaymentRefunded},
+	PaymentDeclined:       {},
+	PaymentVoided:         {},
+	PaymentRefunded:       {},
+}
+
+func (s PaymentStatus) IsValid() bool {
+	_, ok := paymentTransitions[s]
+	return ok
+}
+
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentRefund is one refund against a captured payment. Reference is the
+// caller's idempotency key.
+type PaymentRefund struct {
+	PaymentID string    `db:"payment_id"`
+	Reference string    `db:"reference"`
+	Amount    int64     `db:"amount"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type PaymentIntent struct {
+	ID                string        `db:"id"`
+	IdempotencyKey    string        `db:"idempotency_key"`
+	CustomerID        int64         `db:"customer_id"`
+	Amount            int64         `db:"amount"`
+	Currency          string        `db:"currency"`
+	PaymentMethod     string        `db:"payment_method"`
+	Provider          string        `db:"provider"`
+	ProviderReference string        `db:"provider_reference"`
+	Status            PaymentStatus `db:"status"`
+	DeclineCode       string        `db:"decline_code"`
+	NextActionURL     string        `db:"next_action_url"`
+	CapturedAmount    int64         `db:"captured_amount"`
+	RefundedAmount    int64         `db:"refunded_amount"`
+	CreatedAt         time.Time     `db:"created_at"`
+	UpdatedAt         time.Time     `db:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "testing"
+
+var paymentStatuses = []PaymentStatus{
+	PaymentCreated,
+	PaymentRequiresAction,
+	PaymentAuthorized,
+	PaymentDeclined,
+	PaymentCapturePending,
+	PaymentCaptured,
+	PaymentVoided,
+	PaymentRefunded,
+}
+
+func TestPaymentStatusCanTransitionTo(t *testing.T) {
+	allowed := map[[2]PaymentStatus]bool{
+		{PaymentCreated, PaymentRequiresAction}:    true,
+		{PaymentCreated, PaymentAuthorized}:        true,
+		{PaymentCreated, PaymentDeclined}:          true,
+		{PaymentRequiresAction, PaymentAuthorized}: true,
+		{PaymentRequiresAction, PaymentDeclined}:   true,
+		{PaymentRequiresAction, PaymentVoided}:     true,
+		{PaymentAuthorized, PaymentCapturePending}: true,
+		{PaymentAuthorized, PaymentCaptured}:       true,
+		{PaymentAuthorized, PaymentVoided}:         true,
+		{PaymentCapturePending, PaymentCaptured}:   true,
+		{PaymentCaptured, PaymentRefunded}:         true,
+	}
+
+	for _, from := range paymentStatuses {
+		for _, to := range paymentStatuses {
+			want := allowed[[2]PaymentStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+)
+
+const (
+	FakeProviderName = "fake"
+
+	// FakeSignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of
+	// "<t>.<payload>">".
+	FakeSignatureHeader = "Fake-Signature"
+
+	fakeSignatureTolerance = 5 * time.Minute
+)
+
+// Payment method tokens understood by the fake provider. Any other token is
+// declined as invalid.
+const (
+	FakeCardSuccess         = "pm_card_success"
+	FakeCardDecline         = "pm_card_decline"
+	FakeCardInsufficient    = "pm_card_insufficient_funds"
+	FakeCard3DS             = "pm_card_3ds"
+	FakeCard3DSFail         = "pm_card_3ds_fail"
+	FakeCardDelayedSettling = "pm_card_delayed"
+)
+
+// WebhookSender delivers a signed webhook request to the payment service.
+type WebhookSender func(payload []byte, headers map[string]string)
+
+type fakePayment struct {
+	method   string
+	amount   int64
+	status   model.PaymentStatus
+	captured int64
+	refunded int64
+	refunds  map[string]int64
+}
+
+type fakeProvider struct {
+	secret          []byte
+	challengeDelay  time.Duration
+	settlementDelay time.Duration
+	send            WebhookSender
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+}
+
+// NewFakeProvider returns an in-memory provider for development and tests.
+// It behaves according to the payment method token: FakeCard3DS asks for a
+// challenge that passes after challengeDelay, FakeCardDelayedSettling
+// settles captures after settlementDelay, and both report the outcome via
+// webhooks signed with secret and handed to send.
+func NewFakeProvider(secret string, challengeDelay, settlementDelay time.Duration, send WebhookSender) PaymentProvider {
+	return &fakeProvider{
+		secret:          []byte(secret),
+		challengeDelay:  challengeDelay,
+		settlementDelay: settlementDelay,
+		send:            send,
+		payments:        map[string]*fakePayment{},
+	}
+}
+
+func (p *fakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *fakeProvider) Authorize(ctx context.Context, req *AuthorizeRequest) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ref := "fake_" + req.Reference
+	if payment, ok := p.payments[ref]; ok {
+		return p.result(ref, payment), nil
+	}
+
+	payment := &fakePayment{method: req.PaymentMethod, amount: req.Amount}
+	p.payments[ref] = payment
+
+	switch req.PaymentMethod {
+	case FakeCardSuccess, FakeCardDelayedSettling:
+		payment.status = model.PaymentAuthorized
+	case FakeCard3DS, FakeCard3DSFail:
+		payment.status = model.PaymentRequiresAction
+		time.AfterFunc(p.challengeDelay, func() { p.completeChallenge(ref) })
+	default:
+		payment.status = model.PaymentDeclined
+	}
+
+	return p.result(ref, payment), nil
+}
+
+func (p *fakeProvider) Capture(ctx context.Context, providerReference string, amount int64) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[providerReference]
+	if !ok {
+		return nil, ErrUnknownPayment
+	}
+	if payment.status != model.PaymentAuthorized {
+		return p.result(providerReference, payment), nil
+	}
+	if amount > payment.amount {
+		return nil, fmt.Errorf("fake provider: cannot capture %d of %d authorized", amount, payment.amount)
+	}
+
+	payment.captured = amount
+	if payment.method == FakeCardDelayedSettling {
+		payment.status = model.PaymentCapturePending
+		time.AfterFunc(p.settlementDelay, func() { p.settle(providerReference) })
+	} else {
+		payment.status = model.PaymentCaptured
+	}
+
+	return p.result(providerReference, payment), nil
+}
+
+func (p *fakeProvider) Void(ctx context.Context, providerReference string) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[providerReference]
+	if !ok {
+		return nil, ErrUnknownPayment
+	}
+	if payment.status == model.PaymentAuthorized || payment.status == model.PaymentRequiresAction {
+		payment.status = model.PaymentVoided
+	}
+
+	return p.result(providerReference, payment), nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, providerReference string, amount int64, idempotencyKey string) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[providerReference]
+	if !ok {
+		return nil, ErrUnknownPayment
+	}
+	if _, done := payment.refunds[idempotencyKey]; done {
+		return p.result(providerReference, payment), nil
+	}
+	if payment.status != model.PaymentCaptured || payment.refunded+amount > payment.captured {
+		return nil, fmt.Errorf("fake provider: cannot refund %d of payment %s", amount, providerReference)
+	}
+
+	if payment.refunds == nil {
+		payment.refunds = map[string]int64{}
+	}
+	payment.refunds[idempotencyKey] = amount
+	payment.refunded += amount
+	if payment.refunded == payment.captured {
+		payment.status = model.PaymentRefunded
+	}
+
+	return p.result(providerReference, payment), nil
+}
+
+type fakeWebhook struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Reference   string `json:"reference"`
+		Status      string `json:"status"`
+		DeclineCode string `json:"decline_code,omitempty"`
+	} `json:"data"`
+}
+
+func (p *fakeProvider) VerifyWebhook(payload []byte, headers map[string]string) (*WebhookEvent, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(headerValue(headers, FakeSignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > fakeSignatureTolerance || age < -fakeSignatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(p.sign(timestamp, payload))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var webhook fakeWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("fake provider: malformed webhook: %w", err)
+	}
+
+	return &WebhookEvent{
+		ID:                webhook.ID,
+		Type:              webhook.Type,
+		ProviderReference: webhook.Data.Reference,
+		Status:            model.PaymentStatus(webhook.Data.Status),
+		DeclineCode:       webhook.Data.DeclineCode,
+	}, nil
+}
+
+// completeChallenge plays the customer answering the 3-D Secure challenge.
+func (p *fakeProvider) completeChallenge(ref string) {
+	p.mu.Lock()
+	payment := p.payments[ref]
+	if payment.status != model.PaymentRequiresAction {
+		p.mu.Unlock()
+		return
+	}
+	if payment.method == FakeCard3DSFail {
+		payment.status = model.PaymentDeclined
+	} else {
+		payment.status = model.PaymentAuthorized
+	}
+	result := p.result(ref, payment)
+	p.mu.Unlock()
+
+	if result.Status == model.PaymentAuthorized {
+		p.notify("payment.authorized", result)
+	} else {
+		p.notify("payment.declined", result)
+	}
+}
+
+func (p *fakeProvider) settle(ref string) {
+	p.mu.Lock()
+	payment := p.payments[ref]
+	if payment.status != model.PaymentCapturePending {
+		p.mu.Unlock()
+		return
+	}
+	payment.status = model.PaymentCaptured
+	result := p.result(ref, payment)
+	p.mu.Unlock()
+
+	p.notify("payment.captured", result)
+}
+
+func (p *fakeProvider) notify(eventType string, result *Result) {
+	var webhook fakeWebhook
+	webhook.ID = "evt_" + randomHex(12)
+	webhook.Type = eventType
+	webhook.Data.Reference = result.ProviderReference
+	webhook.Data.Status = string(result.Status)
+	webhook.Data.DeclineCode = result.DeclineCode
+
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	p.send(payload, map[string]string{
+		"Content-Type":      "application/json",
+		FakeSignatureHeader: "t=" + timestamp + ",v1=" + p.sign(timestamp, payload),
+	})
+}
+
+func (p *fakeProvider) sign(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *fakeProvider) result(ref string, payment *fakePayment) *Result {
+	result := &Result{ProviderReference: ref, Status: payment.status}
+
+	switch {
+	case payment.status == model.PaymentRequiresAction:
+		result.NextActionURL = "https://fake-provider.invalid/3ds/" + ref
+	case payment.status == model.PaymentDeclined && payment.method == FakeCard3DSFail:
+		result.DeclineCode = "authentication_failed"
+	case payment.status == model.PaymentDeclined && payment.method == FakeCardDecline:
+		result.DeclineCode = "card_declined"
+	case payment.status == model.PaymentDeclined && payment.method == FakeCardInsufficient:
+		result.DeclineCode = "insufficient_funds"
+	case payment.status == model.PaymentDeclined:
+		result.DeclineCode = "invalid_payment_method"
+	}
+
+	return result
+}
+
+// headerValue looks a header up ignoring case, as proxies may change it.
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+)
+
+func TestFakeVerifyWebhook(t *testing.T) {
+	p := NewFakeProvider("whsec_test", 0, 0, nil).(*fakeProvider)
+	other := NewFakeProvider("whsec_other", 0, 0, nil).(*fakeProvider)
+
+	payload := []byte(`{"id":"evt_1","type":"payment.captured","data":{"reference":"fake_1","status":"captured"}}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-2*fakeSignatureTolerance).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(2*fakeSignatureTolerance).Unix(), 10)
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		wantErr error
+	}{
+		{name: "valid", header: "t=" + now + ",v1=" + p.sign(now, payload)},
+		{name: "valid with spaces", header: "t=" + now + ", v1=" + p.sign(now, payload)},
+		{name: "missing header", header: "", wantErr: ErrInvalidSignature},
+		{name: "missing signature", header: "t=" + now, wantErr: ErrInvalidSignature},
+		{name: "missing timestamp", header: "v1=" + p.sign(now, payload), wantErr: ErrInvalidSignature},
+		{name: "other secret", header: "t=" + now + ",v1=" + other.sign(now, payload), wantErr: ErrInvalidSignature},
+		{name: "tampered payload", header: "t=" + now + ",v1=" + p.sign(now, payload), payload: []byte(strings.Replace(string(payload), "captured", "refunded", 1)), wantErr: ErrInvalidSignature},
+		{name: "timestamp swapped", header: "t=" + stale + ",v1=" + p.sign(now, payload), wantErr: ErrInvalidSignature},
+		{name: "stale", header: "t=" + stale + ",v1=" + p.sign(stale, payload), wantErr: ErrInvalidSignature},
+		{name: "from the future", header: "t=" + future + ",v1=" + p.sign(future, payload), wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := payload
+			if tt.payload != nil {
+				body = tt.payload
+			}
+
+			event, err := p.VerifyWebhook(body, map[string]string{FakeSignatureHeader: tt.header})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if event.ID != "evt_1" || event.ProviderReference != "fake_1" || event.Status != model.PaymentCaptured {
+				t.Errorf("VerifyWebhook() = %+v", event)
+			}
+		})
+	}
+}
+
+func TestFakeWebhookRoundTrip(t *testing.T) {
+	var sent []byte
+	var headers map[string]string
+	p := NewFakeProvider("whsec_test", 0, 0, func(payload []byte, h map[string]string) {
+		sent, headers = payload, h
+	}).(*fakeProvider)
+
+	p.notify("payment.authorized", &Result{ProviderReference: "fake_1", Status: model.PaymentAuthorized})
+
+	// Proxies may change the case of header names.
+	lower := map[string]string{}
+	for key, value := range headers {
+		lower[strings.ToLower(key)] = value
+	}
+
+	event, err := p.VerifyWebhook(sent, lower)
+	if err != nil {
+		t.Fatalf("VerifyWebhook() error = %v", err)
+	}
+	if event.Type != "payment.authorized" || event.ProviderReference != "fake_1" || event.Status != model.PaymentAuthorized {
+		t.Errorf("VerifyWebhook() = %+v", event)
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrUnknownPayment   = errors.New("provider does not know this payment")
+)
+
+// AuthorizeRequest asks a provider to hold an amount. Reference is unique
+// per payment intent and doubles as the provider-side idempotency key.
+type AuthorizeRequest struct {
+	Reference     string
+	Amount        int64
+	Currency      string
+	PaymentMethod string
+}
+
+// Result is the state of a payment at the provider after an operation.
+type Result struct {
+	ProviderReference string
+	Status            model.PaymentStatus
+	DeclineCode       string
+	NextActionURL     string
+}
+
+// WebhookEvent is a verified notification from a provider about one of its
+// payments.
+type WebhookEvent struct {
+	ID                string
+	Type              string
+	ProviderReference string
+	Status            model.PaymentStatus
+	DeclineCode       string
+}
+
+// PaymentProvider is implemented once per payment vendor. Declines are
+// reported in the result; an error means the operation may not have
+// happened and can be retried with the same reference.
+type PaymentProvider interface {
+	Name() string
+
+	Authorize(ctx context.Context, req *AuthorizeRequest) (*Result, error)
+	Capture(ctx context.Context, providerReference string, amount int64) (*Result, error)
+	Void(ctx context.Context, providerReference string) (*Result, error)
+	// Refund passes idempotencyKey on to the provider, so a retried refund
+	// is not paid out twice.
+	Refund(ctx context.Context, providerReference string, amount int64, idempotencyKey string) (*Result, error)
+
+	// VerifyWebhook checks the signature of a webhook request and decodes
+	// it. It returns ErrInvalidSignature for forged or stale requests.
+	VerifyWebhook(payload []byte, headers map[string]string) (*WebhookEvent, error)
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-micro.dev/v4/logger"
+)
+
+const webhookAttempts = 3
+
+// NewHTTPWebhookSender posts webhooks to url, normally the gateway's public
+// webhook endpoint, so they travel the same path as a real provider's.
+// Delivery is retried a few times and then dropped.
+func NewHTTPWebhookSender(url string) WebhookSender {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	return func(payload []byte, headers map[string]string) {
+		go func() {
+			for attempt := 1; attempt <= webhookAttempts; attempt++ {
+				if err := post(client, url, payload, headers); err == nil {
+					return
+				} else if attempt == webhookAttempts {
+					logger.Errorf("Failed to deliver webhook to %s: %v", url, err)
+				}
+				time.Sleep(time.Duration(attempt) * time.Second)
+			}
+		}()
+	}
+}
+
+func post(client *http.Client, url string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded %s", resp.Status)
+	}
+	return nil
+}
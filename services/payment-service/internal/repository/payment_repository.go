@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+)
+
+var (
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrDuplicateIdempotencyKey = errors.New("payment with this idempotency key already exists")
+	ErrStatusChanged           = errors.New("payment status changed concurrently")
+	ErrRefundNotFound          = errors.New("refund not found")
+	ErrDuplicateRefund         = errors.New("refund reference already used")
+	ErrRefundExceedsCapture    = errors.New("refunds would exceed the captured amount")
+)
+
+// PaymentRepository stores payment intents and the provider webhooks applied
+// to them.
+type PaymentRepository interface {
+	// Create returns ErrDuplicateIdempotencyKey if the key is taken.
+	Create(ctx context.Context, intent *model.PaymentIntent) error
+	GetByID(ctx context.Context, id string) (*model.PaymentIntent, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*model.PaymentIntent, error)
+	GetByProviderReference(ctx context.Context, provider, reference string) (*model.PaymentIntent, error)
+
+	// Update writes the provider state of intent if its status is still
+	// fromStatus, and returns ErrStatusChanged otherwise.
+	Update(ctx context.Context, intent *model.PaymentIntent, fromStatus model.PaymentStatus) error
+
+	GetRefund(ctx context.Context, paymentID, reference string) (*model.PaymentRefund, error)
+	// AddRefund records refund and adds it to the refunded amount of intent,
+	// which becomes refunded once nothing captured is left. It fails with
+	// ErrDuplicateRefund if the reference was used before, ErrStatusChanged
+	// if intent is no longer captured and ErrRefundExceedsCapture if a
+	// concurrent refund got there first.
+	AddRefund(ctx context.Context, intent *model.PaymentIntent, refund *model.PaymentRefund) error
+
+	// RecordWebhookEvent stores a webhook event and reports false if it was
+	// recorded before.
+	RecordWebhookEvent(ctx context.Context, provider, eventID, eventType string, payload []byte) (bool, error)
+}
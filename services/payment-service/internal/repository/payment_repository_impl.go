@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const paymentColumns = `
+	id, idempotency_key, customer_id, amount, currency, payment_method, provider,
+	COALESCE(provider_reference, ''), status, COALESCE(decline_code, ''), COALESCE(next_action_url, ''),
+	captured_amount, refunded_amount, created_at, updated_at
+`
+
+type paymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayment(row rowScanner) (*model.PaymentIntent, error) {
+	var intent model.PaymentIntent
+	err := row.Scan(
+		&intent.ID,
+		&intent.IdempotencyKey,
+		&intent.CustomerID,
+		&intent.Amount,
+		&intent.Currency,
+		&intent.PaymentMethod,
+		&intent.Provider,
+		&intent.ProviderReference,
+		&intent.Status,
+		&intent.DeclineCode,
+		&intent.NextActionURL,
+		&intent.CapturedAmount,
+		&intent.RefundedAmount,
+		&intent.CreatedAt,
+		&intent.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+func (r *paymentRepository) Create(ctx context.Context, intent *model.PaymentIntent) error {
+	query := `
+		INSERT INTO payment_intents (idempotency_key, customer_id, amount, currency, payment_method, provider, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		intent.IdempotencyKey,
+		intent.CustomerID,
+		intent.Amount,
+		intent.Currency,
+		intent.PaymentMethod,
+		intent.Provider,
+		intent.Status,
+	).Scan(&intent.ID, &intent.CreatedAt, &intent.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateIdempotencyKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) GetByID(ctx context.Context, id string) (*model.PaymentIntent, error) {
+	return r.get(ctx, `SELECT `+paymentColumns+` FROM payment_intents WHERE id::text = $1`, id)
+}
+
+func (r *paymentRepository) GetByIdempotencyKey(ctx context.Context, key string) (*model.PaymentIntent, error) {
+	return r.get(ctx, `SELECT `+paymentColumns+` FROM payment_intents WHERE idempotency_key = $1`, key)
+}
+
+func (r *paymentRepository) GetByProviderReference(ctx context.Context, provider, reference string) (*model.PaymentIntent, error) {
+	return r.get(ctx,
+		`SELECT `+paymentColumns+` FROM payment_intents WHERE provider = $1 AND provider_reference = $2`,
+		provider, reference,
+	)
+}
+
+func (r *paymentRepository) get(ctx context.Context, query string, args ...interface{}) (*model.PaymentIntent, error) {
+	intent, err := scanPayment(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+func (r *paymentRepository) Update(ctx context.Context, intent *model.PaymentIntent, fromStatus model.PaymentStatus) error {
+	query := `
+		UPDATE payment_intents
+		SET provider_reference = NULLIF($3, ''),
+			status = $4,
+			decline_code = NULLIF($5, ''),
+			next_action_url = NULLIF($6, ''),
+			captured_amount = $7,
+			refunded_amount = $8,
+			updated_at = NOW()
+		WHERE id = $1 AND status = $2
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		intent.ID,
+		fromStatus,
+		intent.ProviderReference,
+		intent.Status,
+		intent.DeclineCode,
+		intent.NextActionURL,
+		intent.CapturedAmount,
+		intent.RefundedAmount,
+	).Scan(&intent.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrStatusChanged
+	}
+
+	return err
+}
+
+func (r *paymentRepository) GetRefund(ctx context.Context, paymentID, reference string) (*model.PaymentRefund, error) {
+	query := `
+		SELECT payment_id, reference, amount, created_at
+		FROM payment_refunds
+		WHERE payment_id::text = $1 AND reference = $2
+	`
+
+	var refund model.PaymentRefund
+	err := r.db.QueryRowContext(ctx, query, paymentID, reference).
+		Scan(&refund.PaymentID, &refund.Reference, &refund.Amount, &refund.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrRefundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+func (r *paymentRepository) AddRefund(ctx context.Context, intent *model.PaymentIntent, refund *model.PaymentRefund) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO payment_refunds (payment_id, reference, amount)
+		VALUES ($1, $2, $3)
+		RETURNING created_at
+	`, refund.PaymentID, refund.Reference, refund.Amount).Scan(&refund.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateRefund
+		}
+		return err
+	}
+
+	// Amounts are added in SQL so concurrent partial refunds cannot
+	// overwrite each other.
+	err = tx.QueryRowContext(ctx, `
+		UPDATE payment_intents
+		SET refunded_amount = refunded_amount + $2,
+			status = CASE WHEN refunded_amount + $2 = captured_amount THEN 'refunded' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'captured'
+		RETURNING status, refunded_amount, updated_at
+	`, refund.PaymentID, refund.Amount).Scan(&intent.Status, &intent.RefundedAmount, &intent.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrStatusChanged
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "payment_intents_amounts_check" {
+			return ErrRefundExceedsCapture
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *paymentRepository) RecordWebhookEvent(ctx context.Context, provider, eventID, eventType string, payload []byte) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO payment_webhook_events (provider, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, provider, eventID, eventType, payload)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
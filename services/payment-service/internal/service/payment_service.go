@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/dto"
+)
+
+var (
+	ErrIdempotencyMismatch = errors.New("idempotency key was used for a different payment")
+	ErrInvalidTransition   = errors.New("payment is not in a state that allows this")
+	ErrUnknownProvider     = errors.New("unknown payment provider")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// PaymentService moves money through a PaymentProvider and keeps the payment
+// intents in step with it.
+type PaymentService interface {
+	// AuthorizePayment creates an intent and asks the provider to hold the
+	// amount. A decline is not an error; it is reported in the status.
+	AuthorizePayment(ctx context.Context, req *dto.AuthorizePaymentRequest) (*dto.PaymentResponse, error)
+	GetPayment(ctx context.Context, id string) (*dto.PaymentResponse, error)
+	CapturePayment(ctx context.Context, id string, amount int64) (*dto.PaymentResponse, error)
+	VoidPayment(ctx context.Context, id string) (*dto.PaymentResponse, error)
+	// RefundPayment pays back part or all of a captured payment. reference
+	// makes it idempotent; reusing it for a different amount fails with
+	// ErrIdempotencyMismatch.
+	RefundPayment(ctx context.Context, id string, amount int64, reference string) (*dto.PaymentResponse, error)
+
+	// HandleWebhook verifies and applies a provider webhook. It reports
+	// false if the event had been applied before.
+	HandleWebhook(ctx context.Context, provider string, payload []byte, headers map[string]string) (bool, error)
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/provider"
+	"github.com/Dzaakk/micro-commerce/services/payment-service/internal/repository"
+
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	maxIdempotencyKeyLength = 100
+	maxPaymentMethodLength  = 100
+	maxRefundRefLength      = 100
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type paymentServiceImpl struct {
+	paymentRepo repository.PaymentRepository
+	providers   map[string]provider.PaymentProvider
+	active      provider.PaymentProvider
+}
+
+// NewPaymentService creates a payment service that authorizes new payments
+// with active. Payments keep the provider they were created with, so
+// providers that used to be active should still be passed in others.
+func NewPaymentService(
+	paymentRepo repository.PaymentRepository,
+	active provider.PaymentProvider,
+	others ...provider.PaymentProvider,
+) PaymentService {
+	providers := map[string]provider.PaymentProvider{active.Name(): active}
+	for _, p := range others {
+		providers[p.Name()] = p
+	}
+
+	return &paymentServiceImpl{
+		paymentRepo: paymentRepo,
+		providers:   providers,
+		active:      active,
+	}
+}
+
+// AuthorizePayment implements PaymentService.
+func (s *paymentServiceImpl) AuthorizePayment(ctx context.Context, req *dto.AuthorizePaymentRequest) (*dto.PaymentResponse, error) {
+	key := strings.TrimSpace(req.IdempotencyKey)
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	method := strings.TrimSpace(req.PaymentMethod)
+
+	invalid := map[string]string{}
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		invalid["idempotency_key"] = fmt.Sprintf("must be between 1 and %d characters", maxIdempotencyKeyLength)
+	}
+	if req.CustomerID <= 0 {
+		invalid["customer_id"] = "is required"
+	}
+	if req.Amount <= 0 {
+		invalid["amount"] = "must be positive"
+	}
+	if !currencyPattern.MatchString(currency) {
+		invalid["currency"] = "must be an ISO 4217 code"
+	}
+	if method == "" || len(method) > maxPaymentMethodLength {
+		invalid["payment_method"] = fmt.Sprintf("must be between 1 and %d characters", maxPaymentMethodLength)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	intent := &model.PaymentIntent{
+		IdempotencyKey: key,
+		CustomerID:     req.CustomerID,
+		Amount:         req.Amount,
+		Currency:       currency,
+		PaymentMethod:  method,
+		Provider:       s.active.Name(),
+		Status:         model.PaymentCreated,
+	}
+
+	err := s.paymentRepo.Create(ctx, intent)
+	if errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+		existing, err := s.paymentRepo.GetByIdempotencyKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing.CustomerID != intent.CustomerID || existing.Amount != intent.Amount ||
+			existing.Currency != intent.Currency || existing.PaymentMethod != intent.PaymentMethod {
+			return nil, ErrIdempotencyMismatch
+		}
+		// Only an intent whose provider call never completed is retried.
+		if existing.Status != model.PaymentCreated {
+			return toPaymentResponse(existing), nil
+		}
+		intent = existing
+	} else if err != nil {
+		return nil, err
+	}
+
+	p, err := s.provider(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.Authorize(ctx, &provider.AuthorizeRequest{
+		Reference:     intent.ID,
+		Amount:        intent.Amount,
+		Currency:      intent.Currency,
+		PaymentMethod: intent.PaymentMethod,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(ctx, intent, result)
+}
+
+// GetPayment implements PaymentService.
+func (s *paymentServiceImpl) GetPayment(ctx context.Context, id string) (*dto.PaymentResponse, error) {
+	intent, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(intent), nil
+}
+
+// CapturePayment implements PaymentService.
+func (s *paymentServiceImpl) CapturePayment(ctx context.Context, id string, amount int64) (*dto.PaymentResponse, error) {
+	intent, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch intent.Status {
+	case model.PaymentCapturePending, model.PaymentCaptured:
+		return toPaymentResponse(intent), nil
+	case model.PaymentAuthorized:
+	default:
+		return nil, fmt.Errorf("%w: cannot capture a %s payment", ErrInvalidTransition, intent.Status)
+	}
+
+	if amount == 0 {
+		amount = intent.Amount
+	}
+	if amount < 0 || amount > intent.Amount {
+		return nil, &ValidationError{Fields: map[string]string{
+			"amount": fmt.Sprintf("must be between 1 and the authorized %d", intent.Amount),
+		}}
+	}
+
+	p, err := s.provider(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.Capture(ctx, intent.ProviderReference, amount)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status == model.PaymentCaptured || result.Status == model.PaymentCapturePending {
+		intent.CapturedAmount = amount
+	}
+
+	return s.apply(ctx, intent, result)
+}
+
+// VoidPayment implements PaymentService.
+func (s *paymentServiceImpl) VoidPayment(ctx context.Context, id string) (*dto.PaymentResponse, error) {
+	intent, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch intent.Status {
+	case model.PaymentVoided, model.PaymentDeclined:
+		// Nothing is held, so there is nothing to release.
+		return toPaymentResponse(intent), nil
+	case model.PaymentAuthorized, model.PaymentRequiresAction:
+	default:
+		return nil, fmt.Errorf("%w: cannot void a %s payment", ErrInvalidTransition, intent.Status)
+	}
+
+	p, err := s.provider(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.Void(ctx, intent.ProviderReference)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(ctx, intent, result)
+}
+
+// RefundPayment implements PaymentService.
+func (s *paymentServiceImpl) RefundPayment(ctx context.Context, id string, amount int64, reference string) (*dto.PaymentResponse, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || len(reference) > maxRefundRefLength {
+		return nil, &ValidationError{Fields: map[string]string{
+			"reference": fmt.Sprintf("must be between 1 and %d characters", maxRefundRefLength),
+		}}
+	}
+
+	intent, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.paymentRepo.GetRefund(ctx, intent.ID, reference)
+	switch {
+	case err == nil:
+		if amount != 0 && amount != existing.Amount {
+			return nil, ErrIdempotencyMismatch
+		}
+		return toPaymentResponse(intent), nil
+	case !errors.Is(err, repository.ErrRefundNotFound):
+		return nil, err
+	}
+
+	if intent.Status != model.PaymentCaptured {
+		return nil, fmt.Errorf("%w: cannot refund a %s payment", ErrInvalidTransition, intent.Status)
+	}
+
+	remaining := intent.CapturedAmount - intent.RefundedAmount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 0 || amount > remaining {
+		return nil, &ValidationError{Fields: map[string]string{
+			"amount": fmt.Sprintf("must be between 1 and the refundable %d", remaining),
+		}}
+	}
+
+	p, err := s.provider(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.Refund(ctx, intent.ProviderReference, amount, intent.ID+"/"+reference); err != nil {
+		return nil, err
+	}
+
+	err = s.paymentRepo.AddRefund(ctx, intent, &model.PaymentRefund{
+		PaymentID: intent.ID,
+		Reference: reference,
+		Amount:    amount,
+	})
+	if errors.Is(err, repository.ErrDuplicateRefund) {
+		// A retry of the same refund won the race.
+		return s.GetPayment(ctx, intent.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(intent), nil
+}
+
+// HandleWebhook implements PaymentService.
+func (s *paymentServiceImpl) HandleWebhook(ctx context.Context, providerName string, payload []byte, headers map[string]string) (bool, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return false, ErrUnknownProvider
+	}
+
+	event, err := p.VerifyWebhook(payload, headers)
+	if err != nil {
+		return false, err
+	}
+
+	// A webhook can beat the response to our own provider call, leaving the
+	// reference unknown for a moment. The error makes the provider redeliver.
+	intent, err := s.paymentRepo.GetByProviderReference(ctx, providerName, event.ProviderReference)
+	if err != nil {
+		return false, err
+	}
+
+	if event.Status != intent.Status {
+		_, err := s.apply(ctx, intent, &provider.Result{
+			ProviderReference: event.ProviderReference,
+			Status:            event.Status,
+			DeclineCode:       event.DeclineCode,
+		})
+		switch {
+		case errors.Is(err, ErrInvalidTransition):
+			// Stale or out of order; the intent has already moved on.
+			logger.Warnf("Ignoring %s webhook %s for payment %s: %v", providerName, event.ID, intent.ID, err)
+		case err != nil:
+			return false, err
+		}
+	}
+
+	return s.paymentRepo.RecordWebhookEvent(ctx, providerName, event.ID, event.Type, payload)
+}
+
+// apply stores the outcome of a provider call or webhook on intent. If the
+// intent changed underneath, the stored version is returned instead so the
+// caller sees the provider's latest word.
+func (s *paymentServiceImpl) apply(ctx context.Context, intent *model.PaymentIntent, result *provider.Result) (*dto.PaymentResponse, error) {
+	from := intent.Status
+	if result.Status != from && !from.CanTransitionTo(result.Status) {
+		return nil, fmt.Errorf("%w: provider reported %s for a %s payment", ErrInvalidTransition, result.Status, from)
+	}
+
+	intent.ProviderReference = result.ProviderReference
+	intent.Status = result.Status
+	intent.DeclineCode = result.DeclineCode
+	intent.NextActionURL = result.NextActionURL
+	if intent.Status == model.PaymentRefunded {
+		intent.RefundedAmount = intent.CapturedAmount
+	}
+
+	err := s.paymentRepo.Update(ctx, intent, from)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return s.GetPayment(ctx, intent.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(intent), nil
+}
+
+func (s *paymentServiceImpl) provider(intent *model.PaymentIntent) (provider.PaymentProvider, error) {
+	p, ok := s.providers[intent.Provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, intent.Provider)
+	}
+	return p, nil
+}
+
+func toPaymentResponse(intent *model.PaymentIntent) *dto.PaymentResponse {
+	return &dto.PaymentResponse{
+		ID:             intent.ID,
+		IdempotencyKey: intent.IdempotencyKey,
+		CustomerID:     intent.CustomerID,
+		Amount:         intent.Amount,
+		Currency:       intent.Currency,
+		Provider:       intent.Provider,
+		Status:         string(intent.Status),
+		DeclineCode:    intent.DeclineCode,
+		NextActionURL:  intent.NextActionURL,
+		CapturedAmount: intent.CapturedAmount,
+		RefundedAmount: intent.RefundedAmount,
+		CreatedAt:      intent.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      intent.UpdatedAt.Format(time.RFC3339),
+	}
+}
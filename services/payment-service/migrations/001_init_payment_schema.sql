@@ -0,0 +1,40 @@
+-- A payment intent is one attempt to take money for a checkout. Callers pass
+-- an idempotency key so retries never create a second intent.
+CREATE TABLE payment_intents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key VARCHAR(100) NOT NULL UNIQUE,
+    customer_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),  -- in minor units
+    currency CHAR(3) NOT NULL,
+    payment_method VARCHAR(100) NOT NULL,       -- provider token, never card data
+    provider VARCHAR(30) NOT NULL,
+    provider_reference VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'created',
+    decline_code VARCHAR(50),
+    next_action_url TEXT,                       -- where to send the customer for a 3-D Secure challenge
+    captured_amount BIGINT NOT NULL DEFAULT 0,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT payment_intents_status_check CHECK (status IN (
+        'created', 'requires_action', 'authorized', 'declined',
+        'capture_pending', 'captured', 'voided', 'refunded'
+    )),
+    CONSTRAINT payment_intents_amounts_check CHECK (
+        captured_amount <= amount AND refunded_amount <= captured_amount
+    )
+);
+
+-- Webhook events already applied, so a redelivered event is ignored.
+CREATE TABLE payment_webhook_events (
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, event_id)
+);
+
+-- Indexes
+CREATE UNIQUE INDEX idx_payment_intents_provider_reference ON payment_intents(provider, provider_reference);
+CREATE INDEX idx_payment_intents_customer_id ON payment_intents(customer_id);
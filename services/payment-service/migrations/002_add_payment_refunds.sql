@@ -0,0 +1,9 @@
+-- Refunds against captured payments. The caller supplied reference, e.g. a
+-- return id, makes a retried refund a no-op.
+CREATE TABLE payment_refunds (
+    payment_id UUID NOT NULL REFERENCES payment_intents(id),
+    reference VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (payment_id, reference)
+);
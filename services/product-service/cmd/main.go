@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+	if !model.IsCurrencyCode(cfg.BaseCurrency) {
+		logger.Fatal("BASE_CURRENCY must be an ISO 4217 code")
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("product-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	categoryRepo := repository.NewCategoryRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	rateRepo := repository.NewExchangeRateRepository(db)
+	categoryService := service.NewCategoryService(categoryRepo)
+	productService := service.NewProductService(productRepo, categoryRepo, rateRepo, cfg.BaseCurrency)
+	exchangeRateService := service.NewExchangeRateService(rateRepo, cfg.BaseCurrency)
+
+	if cfg.ExchangeRatesFile != "" {
+		req, err := service.ReadExchangeRatesFile(cfg.ExchangeRatesFile)
+		if err != nil {
+			logger.Fatal("Failed to read exchange rates: ", err)
+		}
+		if _, err := exchangeRateService.SetExchangeRates(context.Background(), req); err != nil {
+			logger.Fatal("Failed to load exchange rates: ", err)
+		}
+	}
+
+	productHandler := handler.NewProductHandler(categoryService, productService, exchangeRateService)
+
+	if err := pb.RegisterProductServiceHandler(srv.Server(), productHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
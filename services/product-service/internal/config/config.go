@@ -0,0 +1,40 @@
+// services/product-service/internal/config/config.go
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// BaseCurrency is the ISO 4217 code variant prices are set in. Other
+	// currencies are priced through exchange rates from it.
+	BaseCurrency string
+	// ExchangeRatesFile, when set, is a JSON rate file loaded on start.
+	ExchangeRatesFile string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:        getEnv("PORT", "8082"),
+		Environment: os.Getenv("ENVIRONMENT"),
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+
+		BaseCurrency:      getEnv("BASE_CURRENCY", "IDR"),
+		ExchangeRatesFile: os.Getenv("EXCHANGE_RATES_FILE"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,109 @@
+package dto
+
+type CreateCategoryRequest struct {
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name"`
+	TaxCategory string `json:"tax_category"`
+}
+
+type CategoryResponse struct {
+	ID          string `json:"id"`
+	ParentID    string `json:"parent_id,omitempty"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Path        string `json:"path"`
+	TaxCategory string `json:"tax_category"`
+}
+
+// Money is an amount in minor units of an ISO 4217 currency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type VariantRequest struct {
+	SKU         string  `json:"sku"`
+	Size        string  `json:"size"`
+	Color       string  `json:"color"`
+	Price       int64   `json:"price"`
+	WeightGrams int32   `json:"weight_grams"`
+	Prices      []Money `json:"prices"`
+}
+
+type UpdateVariantRequest struct {
+	Price       *int64 `json:"price"`
+	WeightGrams *int32 `json:"weight_grams"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type CreateProductRequest struct {
+	StoreID     string            `json:"store_id"`
+	CategoryID  string            `json:"category_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Variants    []*VariantRequest `json:"variants"`
+}
+
+type UpdateProductRequest struct {
+	CategoryID  *string `json:"category_id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type ListProductsRequest struct {
+	CategoryID string
+	StoreID    string
+	Query      string
+	// SellerID lists that seller's own products including drafts
+	SellerID int64
+	Page     int
+	PageSize int
+}
+
+type VariantResponse struct {
+	ID          string  `json:"id"`
+	SKU         string  `json:"sku"`
+	Size        string  `json:"size,omitempty"`
+	Color       string  `json:"color,omitempty"`
+	Price       int64   `json:"price"`
+	WeightGrams int32   `json:"weight_grams"`
+	IsActive    bool    `json:"is_active"`
+	Prices      []Money `json:"prices"`
+}
+
+type ProductResponse struct {
+	ID          string             `json:"id"`
+	SellerID    int64              `json:"seller_id"`
+	StoreID     string             `json:"store_id"`
+	CategoryID  string             `json:"category_id,omitempty"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description string             `json:"description,omitempty"`
+	Status      string             `json:"status"`
+	Variants    []*VariantResponse `json:"variants"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+	PublishedAt string             `json:"published_at,omitempty"`
+}
+
+type ExchangeRateInput struct {
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+}
+
+type SetExchangeRatesRequest struct {
+	// BaseCurrency must be the catalog's base currency. It guards against
+	// loading a rate file quoted against another currency.
+	BaseCurrency string              `json:"base_currency"`
+	Source       string              `json:"source"`
+	Rates        []ExchangeRateInput `json:"rates"`
+}
+
+type ExchangeRateResponse struct {
+	ID           int64  `json:"id"`
+	BaseCurrency string `json:"base_currency"`
+	Currency     string `json:"currency"`
+	Rate         string `json:"rate"`
+	Source       string `json:"source"`
+	CreatedAt    string `json:"created_at"`
+}
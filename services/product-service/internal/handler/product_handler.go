@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "product-service"
+
+type ProductHandler struct {
+	categoryService     service.CategoryService
+	productService      service.ProductService
+	exchangeRateService service.ExchangeRateService
+}
+
+func NewProductHandler(
+	categoryService service.CategoryService,
+	productService service.ProductService,
+	exchangeRateService service.ExchangeRateService,
+) *ProductHandler {
+	return &ProductHandler{
+		categoryService:     categoryService,
+		productService:      productService,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+func (h *ProductHandler) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest, rsp *pb.CategoryResponse) error {
+	category, err := h.categoryService.CreateCategory(ctx, &dto.CreateCategoryRequest{
+		ParentID:    req.ParentId,
+		Name:        req.Name,
+		TaxCategory: req.TaxCategory,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Category = toProtoCategory(category)
+	return nil
+}
+
+func (h *ProductHandler) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest, rsp *pb.ListCategoriesResponse) error {
+	categories, err := h.categoryService.ListCategories(ctx)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, category := range categories {
+		rsp.Categories = append(rsp.Categories, toProtoCategory(category))
+	}
+
+	return nil
+}
+
+func (h *ProductHandler) CreateProduct(ctx context.Context, req *pb.CreateProductRequest, rsp *pb.ProductResponse) error {
+	createReq := &dto.CreateProductRequest{
+		StoreID:     req.StoreId,
+		CategoryID:  req.CategoryId,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	for _, variant := range req.Variants {
+		createReq.Variants = append(createReq.Variants, fromProtoVariantInput(variant))
+	}
+
+	product, err := h.productService.CreateProduct(ctx, req.SellerId, createReq)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.UpdateProduct(ctx, req.SellerId, req.Id, &dto.UpdateProductRequest{
+		CategoryID:  req.CategoryId,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) PublishProduct(ctx context.Context, req *pb.ChangeProductStatusRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.PublishProduct(ctx, req.SellerId, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) UnpublishProduct(ctx context.Context, req *pb.ChangeProductStatusRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.UnpublishProduct(ctx, req.SellerId, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest, rsp *pb.DeleteProductResponse) error {
+	if err := h.productService.DeleteProduct(ctx, req.SellerId, req.Id); err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Deleted = true
+	return nil
+}
+
+func (h *ProductHandler) GetProduct(ctx context.Context, req *pb.GetProductRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.GetProduct(ctx, req.Id, req.SellerId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) ListProducts(ctx context.Context, req *pb.ListProductsRequest, rsp *pb.ListProductsResponse) error {
+	products, total, err := h.productService.ListProducts(ctx, &dto.ListProductsRequest{
+		CategoryID: req.CategoryId,
+		StoreID:    req.StoreId,
+		Query:      req.Query,
+		SellerID:   req.SellerId,
+		Page:       int(req.Page),
+		PageSize:   int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, product := range products {
+		rsp.Products = append(rsp.Products, toProtoProduct(product))
+	}
+	rsp.Total = int32(total)
+
+	return nil
+}
+
+func (h *ProductHandler) AddVariant(ctx context.Context, req *pb.AddVariantRequest, rsp *pb.ProductResponse) error {
+	if req.Variant == nil {
+		return microerrors.BadRequest(serviceID, "variant is required")
+	}
+
+	product, err := h.productService.AddVariant(ctx, req.SellerId, req.ProductId, fromProtoVariantInput(req.Variant))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) UpdateVariant(ctx context.Context, req *pb.UpdateVariantRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.UpdateVariant(ctx, req.SellerId, req.ProductId, req.VariantId, &dto.UpdateVariantRequest{
+		Price:       req.Price,
+		WeightGrams: req.WeightGrams,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) DeleteVariant(ctx context.Context, req *pb.DeleteVariantRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.DeleteVariant(ctx, req.SellerId, req.ProductId, req.VariantId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) SetVariantPrices(ctx context.Context, req *pb.SetVariantPricesRequest, rsp *pb.ProductResponse) error {
+	product, err := h.productService.SetVariantPrices(ctx, req.SellerId, req.ProductId, req.VariantId, fromProtoPrices(req.Prices))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Product = toProtoProduct(product)
+	return nil
+}
+
+func (h *ProductHandler) LookupSKUs(ctx context.Context, req *pb.LookupSKUsRequest, rsp *pb.LookupSKUsResponse) error {
+	items, rate, err := h.productService.LookupSKUs(ctx, req.Skus, req.Currency, req.ExchangeRateId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Currency = rate.Currency
+	rsp.ExchangeRate = &pb.ExchangeRate{
+		Id:           rate.ID,
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+		Source:       rate.Source,
+	}
+	if rate.ID != 0 {
+		rsp.ExchangeRate.CreatedAt = rate.CreatedAt.Format(time.RFC3339)
+	}
+
+	for _, item := range items {
+		rsp.Items = append(rsp.Items, &pb.SKUInfo{
+			Sku:         item.SKU,
+			ProductId:   item.ProductID,
+			VariantId:   item.VariantID,
+			ProductName: item.ProductName,
+			Size:        item.Size,
+			Color:       item.Color,
+			Price:       item.Price,
+			WeightGrams: item.WeightGrams,
+			SellerId:    item.SellerID,
+			StoreId:     item.StoreID,
+			CategoryId:  item.CategoryID,
+			TaxCategory: item.TaxCategory,
+		})
+	}
+
+	return nil
+}
+
+func (h *ProductHandler) SetExchangeRates(ctx context.Context, req *pb.SetExchangeRatesRequest, rsp *pb.ExchangeRatesResponse) error {
+	setReq := &dto.SetExchangeRatesRequest{
+		BaseCurrency: req.BaseCurrency,
+		Source:       req.Source,
+	}
+	for _, rate := range req.Rates {
+		setReq.Rates = append(setReq.Rates, dto.ExchangeRateInput{
+			Currency: rate.Currency,
+			Rate:     rate.Rate,
+		})
+	}
+
+	rates, err := h.exchangeRateService.SetExchangeRates(ctx, setReq)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Rates = toProtoExchangeRates(rates)
+	return nil
+}
+
+func (h *ProductHandler) ListExchangeRates(ctx context.Context, req *pb.ListExchangeRatesRequest, rsp *pb.ExchangeRatesResponse) error {
+	rates, err := h.exchangeRateService.ListExchangeRates(ctx)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Rates = toProtoExchangeRates(rates)
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrProductNotFound),
+		errors.Is(err, repository.ErrVariantNotFound),
+		errors.Is(err, repository.ErrCategoryNotFound),
+		errors.Is(err, repository.ErrExchangeRateNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrDuplicateSKU),
+		errors.Is(err, repository.ErrDuplicateSlug),
+		errors.Is(err, repository.ErrCategoryExists),
+		errors.Is(err, service.ErrNotPublishable):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func fromProtoVariantInput(in *pb.VariantInput) *dto.VariantRequest {
+	return &dto.VariantRequest{
+		SKU:         in.Sku,
+		Size:        in.Size,
+		Color:       in.Color,
+		Price:       in.Price,
+		WeightGrams: in.WeightGrams,
+		Prices:      fromProtoPrices(in.Prices),
+	}
+}
+
+func fromProtoPrices(in []*pb.Money) []dto.Money {
+	prices := make([]dto.Money, 0, len(in))
+	for _, price := range in {
+		prices = append(prices, dto.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return prices
+}
+
+func toProtoExchangeRates(rates []*dto.ExchangeRateResponse) []*pb.ExchangeRate {
+	resp := make([]*pb.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		resp = append(resp, &pb.ExchangeRate{
+			Id:           rate.ID,
+			BaseCurrency: rate.BaseCurrency,
+			Currency:     rate.Currency,
+			Rate:         rate.Rate,
+			Source:       rate.Source,
+			CreatedAt:    rate.CreatedAt,
+		})
+	}
+	return resp
+}
+
+func toProtoCategory(category *dto.CategoryResponse) *pb.Category {
+	return &pb.Category{
+		Id:          category.ID,
+		ParentId:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Path:        category.Path,
+		TaxCategory: category.TaxCategory,
+	}
+}
+
+func toProtoProduct(product *dto.ProductResponse) *pb.Product {
+	resp := &pb.Product{
+		Id:          product.ID,
+		SellerId:    product.SellerID,
+		StoreId:     product.StoreID,
+		CategoryId:  product.CategoryID,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description,
+		Status:      product.Status,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+		PublishedAt: product.PublishedAt,
+	}
+
+	for _, variant := range product.Variants {
+		protoVariant := &pb.Variant{
+			Id:          variant.ID,
+			Sku:         variant.SKU,
+			Size:        variant.Size,
+			Color:       variant.Color,
+			Price:       variant.Price,
+			WeightGrams: variant.WeightGrams,
+			IsActive:    variant.IsActive,
+		}
+		for _, price := range variant.Prices {
+			protoVariant.Prices = append(protoVariant.Prices, &pb.Money{
+				Amount:   price.Amount,
+				Currency: price.Currency,
+			})
+		}
+		resp.Variants = append(resp.Variants, protoVariant)
+	}
+
+	return resp
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRate = errors.New("exchange rate must be a positive decimal")
+
+// ratePattern allows as many decimals as the exchange_rates.rate column
+// keeps.
+var ratePattern = regexp.MustCompile(`^[0-9]{1,12}(\.[0-9]{1,12})?$`)
+
+// Money is an amount in the minor units of a currency, e.g. cents for USD
+// or yen for JPY, with the currency's ISO 4217 code.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit. Every other currency has two decimals.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent returns the number of decimals of the currency's minor
+// unit.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// IsCurrencyCode reports whether code has the shape of an ISO 4217 code.
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ExchangeRate is what one unit of BaseCurrency is worth in Currency, e.g.
+// 0.000061 from IDR to USD. Rates are never changed in place: a new rate
+// supersedes the previous one, so orders keep the rate they were priced
+// with.
+type ExchangeRate struct {
+	ID           int64     `db:"id"`
+	BaseCurrency string    `db:"base_currency"`
+	Currency     string    `db:"currency"`
+	Rate         string    `db:"rate"`
+	Source       string    `db:"source"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// IdentityRate prices the base currency in itself. It is not stored and
+// has no id.
+func IdentityRate(currency string) *ExchangeRate {
+	return &ExchangeRate{
+		BaseCurrency: currency,
+		Currency:     currency,
+		Rate:         "1",
+	}
+}
+
+// Convert turns an amount of the base currency into the rate's currency,
+// rounding half away from zero to the nearest minor unit.
+func (r *ExchangeRate) Convert(amount int64) (int64, error) {
+	rate, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || rate.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, r.Rate)
+	}
+
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	value.Mul(value, pow10(CurrencyExponent(r.Currency)-CurrencyExponent(r.BaseCurrency)))
+
+	return roundHalfAway(value)
+}
+
+// NormalizeRate checks a rate given as a decimal string and strips
+// insignificant zeros, so that equal rates compare equal.
+func NormalizeRate(rate string) (string, error) {
+	rate = strings.TrimSpace(rate)
+	if !ratePattern.MatchString(rate) {
+		return "", ErrInvalidRate
+	}
+
+	if strings.Contains(rate, ".") {
+		rate = strings.TrimRight(strings.TrimRight(rate, "0"), ".")
+	}
+	rate = strings.TrimLeft(rate, "0")
+	if rate == "" || strings.HasPrefix(rate, ".") {
+		rate = "0" + rate
+	}
+	if rate == "0" {
+		return "", ErrInvalidRate
+	}
+
+	return rate, nil
+}
+
+func pow10(exponent int) *big.Rat {
+	if exponent < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exponent)), nil))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+}
+
+func roundHalfAway(value *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+
+	if !quotient.IsInt64() {
+		return 0, errors.New("converted amount overflows")
+	}
+	return quotient.Int64(), nil
+}
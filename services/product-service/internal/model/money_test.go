@@ -0,0 +1,94 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    ExchangeRate
+		amount  int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "identity", rate: *IdentityRate("USD"), amount: 1234, want: 1234},
+		{name: "same exponent", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "USD", Rate: "0.000061"}, amount: 100000000, want: 6100},
+		{name: "to a currency without decimals", rate: ExchangeRate{BaseCurrency: "IDR", Currency: "JPY", Rate: "0.0093"}, amount: 100000000, want: 9300},
+		{name: "to a currency with three decimals", rate: ExchangeRate{BaseCurrency: "USD", Currency: "KWD", Rate: "0.307"}, amount: 100, want: 307},
+		{name: "half rounds up", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "1.5"}, amount: 1, want: 2},
+		{name: "negative half rounds away from zero", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "1.5"}, amount: -1, want: -2},
+		{name: "below half rounds down", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "1.25"}, amount: 1, want: 1},
+		{name: "zero rate", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0"}, amount: 100, wantErr: true},
+		{name: "not a number", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "abc"}, amount: 100, wantErr: true},
+		{name: "overflow", rate: ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "2"}, amount: math.MaxInt64, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.Convert(tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert(%d) error = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRate(t *testing.T) {
+	tests := []struct {
+		rate string
+		want string
+	}{
+		{rate: "0.000061", want: "0.000061"},
+		{rate: "00.50", want: "0.5"},
+		{rate: "1.000", want: "1"},
+		{rate: " 2.50 ", want: "2.5"},
+		{rate: "15300", want: "15300"},
+		{rate: "0"},
+		{rate: "0.000"},
+		{rate: "-1"},
+		{rate: "1e-5"},
+		{rate: ".5"},
+		{rate: "0." + strings.Repeat("1", 13)},
+		{rate: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			got, err := NormalizeRate(tt.rate)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidRate) {
+					t.Errorf("NormalizeRate(%q) = %q, %v, want %v", tt.rate, got, err, ErrInvalidRate)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeRate(%q) = %q, %v, want %q", tt.rate, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCurrencyCode(t *testing.T) {
+	tests := map[string]bool{
+		"USD":  true,
+		"JPY":  true,
+		"usd":  false,
+		"US":   false,
+		"USDT": false,
+		"U5D":  false,
+		"":     false,
+	}
+
+	for code, want := range tests {
+		if got := IsCurrencyCode(code); got != want {
+			t.Errorf("IsCurrencyCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
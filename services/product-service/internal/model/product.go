@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+type ProductStatus string
+
+const (
+	ProductStatusDraft     ProductStatus = "draft"
+	ProductStatusPublished ProductStatus = "published"
+)
+
+type Category struct {
+	ID       string `db:"id"`
+	ParentID string `db:"parent_id"`
+	Name     string `db:"name"`
+	Slug     string `db:"slug"`
+	Path     string `db:"path"`
+	// TaxCategory names the rates the tax service applies to products in
+	// the category.
+	TaxCategory string    `db:"tax_category"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// DefaultTaxCategory applies to products without a category and to root
+// categories created without one.
+const DefaultTaxCategory = "standard"
+
+type Product struct {
+	ID          string        `db:"id"`
+	SellerID    int64         `db:"seller_id"`
+	StoreID     string        `db:"store_id"`
+	CategoryID  string        `db:"category_id"`
+	Name        string        `db:"name"`
+	Slug        string        `db:"slug"`
+	Description string        `db:"description"`
+	Status      ProductStatus `db:"status"`
+	PublishedAt *time.Time    `db:"published_at"`
+	DeletedAt   *time.Time    `db:"deleted_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+
+	Variants []*Variant `db:"-"`
+}
+
+// Variant is a purchasable version of a product. Price is in the base
+// currency.
+type Variant struct {
+	ID          string     `db:"id"`
+	ProductID   string     `db:"product_id"`
+	SKU         string     `db:"sku"`
+	Size        string     `db:"size"`
+	Color       string     `db:"color"`
+	Price       int64      `db:"price"`
+	WeightGrams int32      `db:"weight_grams"`
+	IsActive    bool       `db:"is_active"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+
+	// Prices the seller set for other currencies. Currencies without one
+	// are converted from Price at the current exchange rate.
+	Prices []Money `db:"-"`
+}
+
+// SKUInfo is a published, active variant joined with its product.
+type SKUInfo struct {
+	SKU         string
+	ProductID   string
+	VariantID   string
+	ProductName string
+	Size        string
+	Color       string
+	// Price is in the currency the SKUs were looked up in. ListPrice is
+	// the seller's own price for that currency, if they set one.
+	Price       int64
+	ListPrice   *int64
+	WeightGrams int32
+	SellerID    int64
+	StoreID     string
+	CategoryID  string
+	TaxCategory string
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+type categoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *model.Category) error {
+	query := `
+		INSERT INTO categories (parent_id, name, slug, path, tax_category)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Path,
+		category.TaxCategory,
+	).Scan(&category.ID, &category.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrCategoryExists
+	}
+
+	return err
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id string) (*model.Category, error) {
+	query := `
+		SELECT id, COALESCE(parent_id::text, ''), name, slug, path, tax_category, created_at
+		FROM categories
+		WHERE id::text = $1
+	`
+
+	var category model.Category
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&category.ID,
+		&category.ParentID,
+		&category.Name,
+		&category.Slug,
+		&category.Path,
+		&category.TaxCategory,
+		&category.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) List(ctx context.Context) ([]*model.Category, error) {
+	query := `
+		SELECT id, COALESCE(parent_id::text, ''), name, slug, path, tax_category, created_at
+		FROM categories
+		ORDER BY path ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*model.Category
+	for rows.Next() {
+		var category model.Category
+		err := rows.Scan(
+			&category.ID,
+			&category.ParentID,
+			&category.Name,
+			&category.Slug,
+			&category.Path,
+			&category.TaxCategory,
+			&category.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+
+	return categories, rows.Err()
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRateRepository keeps every rate ever set. The latest one per
+// currency is the current rate.
+type ExchangeRateRepository interface {
+	Create(ctx context.Context, rates []*model.ExchangeRate) error
+	GetByID(ctx context.Context, id int64) (*model.ExchangeRate, error)
+	GetCurrent(ctx context.Context, baseCurrency, currency string) (*model.ExchangeRate, error)
+	ListCurrent(ctx context.Context, baseCurrency string) ([]*model.ExchangeRate, error)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+const exchangeRateColumns = `id, base_currency, currency, rate::text, source, created_at`
+
+type exchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func scanExchangeRate(row rowScanner) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	err := row.Scan(
+		&rate.ID,
+		&rate.BaseCurrency,
+		&rate.Currency,
+		&rate.Rate,
+		&rate.Source,
+		&rate.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// NUMERIC comes back padded to its scale, e.g. 0.000061000000.
+	if strings.Contains(rate.Rate, ".") {
+		rate.Rate = strings.TrimRight(strings.TrimRight(rate.Rate, "0"), ".")
+	}
+
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) Create(ctx context.Context, rates []*model.ExchangeRate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO exchange_rates (base_currency, currency, rate, source)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`, rate.BaseCurrency, rate.Currency, rate.Rate, rate.Source).Scan(&rate.ID, &rate.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *exchangeRateRepository) GetByID(ctx context.Context, id int64) (*model.ExchangeRate, error) {
+	rate, err := scanExchangeRate(r.db.QueryRowContext(ctx,
+		`SELECT `+exchangeRateColumns+` FROM exchange_rates WHERE id = $1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrExchangeRateNotFound
+	}
+
+	return rate, err
+}
+
+func (r *exchangeRateRepository) GetCurrent(ctx context.Context, baseCurrency, currency string) (*model.ExchangeRate, error) {
+	rate, err := scanExchangeRate(r.db.QueryRowContext(ctx, `
+		SELECT `+exchangeRateColumns+`
+		FROM exchange_rates
+		WHERE base_currency = $1 AND currency = $2
+		ORDER BY id DESC
+		LIMIT 1
+	`, baseCurrency, currency))
+	if err == sql.ErrNoRows {
+		return nil, ErrExchangeRateNotFound
+	}
+
+	return rate, err
+}
+
+func (r *exchangeRateRepository) ListCurrent(ctx context.Context, baseCurrency string) ([]*model.ExchangeRate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (currency) `+exchangeRateColumns+`
+		FROM exchange_rates
+		WHERE base_currency = $1
+		ORDER BY currency ASC, id DESC
+	`, baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*model.ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrVariantNotFound = errors.New("variant not found")
+	ErrDuplicateSKU    = errors.New("sku already in use")
+	ErrDuplicateSlug   = errors.New("product slug already in use in this store")
+)
+
+type ProductFilter struct {
+	// CategoryPath matches the category and all of its descendants
+	CategoryPath string
+	StoreID      string
+	Query        string
+	SellerID     int64
+	Status       model.ProductStatus
+	Limit        int
+	Offset       int
+}
+
+// ProductRepository never returns soft-deleted products or variants.
+type ProductRepository interface {
+	Create(ctx context.Context, product *model.Product) error
+	Update(ctx context.Context, product *model.Product) error
+	UpdateStatus(ctx context.Context, id string, status model.ProductStatus, publishedAt *time.Time) error
+	SoftDelete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*model.Product, error)
+	List(ctx context.Context, filter ProductFilter) ([]*model.Product, int, error)
+
+	AddVariant(ctx context.Context, variant *model.Variant) error
+	UpdateVariant(ctx context.Context, variant *model.Variant) error
+	SoftDeleteVariant(ctx context.Context, productID, variantID string) error
+	// SetVariantPrices replaces the variant's prices in other currencies.
+	SetVariantPrices(ctx context.Context, variant *model.Variant) error
+
+	// LookupSKUs fills ListPrice with the seller's price for currency and
+	// Price with the base price.
+	LookupSKUs(ctx context.Context, skus []string, currency string) ([]*model.SKUInfo, error)
+}
@@ -0,0 +1,533 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const productColumns = `
+	p.id, p.seller_id, p.store_id, COALESCE(p.category_id::text, ''), p.name, p.slug,
+	COALESCE(p.description, ''), p.status, p.published_at, p.created_at, p.updated_at
+`
+
+const variantColumns = `
+	id, product_id, sku, COALESCE(size, ''), COALESCE(color, ''), price, weight_grams,
+	is_active, created_at, updated_at
+`
+
+type productRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) ProductRepository {
+	return &productRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(row rowScanner) (*model.Product, error) {
+	var (
+		product     model.Product
+		publishedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&product.ID,
+		&product.SellerID,
+		&product.StoreID,
+		&product.CategoryID,
+		&product.Name,
+		&product.Slug,
+		&product.Description,
+		&product.Status,
+		&publishedAt,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if publishedAt.Valid {
+		product.PublishedAt = &publishedAt.Time
+	}
+
+	return &product, nil
+}
+
+func scanVariant(row rowScanner) (*model.Variant, error) {
+	var variant model.Variant
+	err := row.Scan(
+		&variant.ID,
+		&variant.ProductID,
+		&variant.SKU,
+		&variant.Size,
+		&variant.Color,
+		&variant.Price,
+		&variant.WeightGrams,
+		&variant.IsActive,
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *productRepository) Create(ctx context.Context, product *model.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO products (seller_id, store_id, category_id, name, slug, description, status)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		product.SellerID,
+		product.StoreID,
+		product.CategoryID,
+		product.Name,
+		product.Slug,
+		product.Description,
+		product.Status,
+	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		return translateUniqueViolation(err)
+	}
+
+	for _, variant := range product.Variants {
+		variant.ProductID = product.ID
+		if err := insertVariant(ctx, tx, variant); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *productRepository) Update(ctx context.Context, product *model.Product) error {
+	query := `
+		UPDATE products
+		SET category_id = NULLIF($2, '')::uuid, name = $3, slug = $4, description = $5, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		product.ID,
+		product.CategoryID,
+		product.Name,
+		product.Slug,
+		product.Description,
+	).Scan(&product.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrProductNotFound
+	}
+
+	return translateUniqueViolation(err)
+}
+
+func (r *productRepository) UpdateStatus(ctx context.Context, id string, status model.ProductStatus, publishedAt *time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE products SET status = $2, published_at = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, status, publishedAt)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrProductNotFound
+	}
+
+	return nil
+}
+
+func (r *productRepository) SoftDelete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE products SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrProductNotFound
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE product_variants SET deleted_at = NOW() WHERE product_id = $1 AND deleted_at IS NULL`, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *productRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products p WHERE p.id::text = $1 AND p.deleted_at IS NULL`
+
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadVariants(ctx, []*model.Product{product}); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) List(ctx context.Context, filter ProductFilter) ([]*model.Product, int, error) {
+	where := []string{"p.deleted_at IS NULL"}
+	args := []interface{}{}
+
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.CategoryPath != "" {
+		placeholder := addArg(filter.CategoryPath)
+		where = append(where, fmt.Sprintf(
+			"p.category_id IN (SELECT id FROM categories WHERE path = %[1]s OR path LIKE %[1]s || '/%%')", placeholder,
+		))
+	}
+	if filter.StoreID != "" {
+		where = append(where, "p.store_id::text = "+addArg(filter.StoreID))
+	}
+	if filter.SellerID != 0 {
+		where = append(where, "p.seller_id = "+addArg(filter.SellerID))
+	}
+	if filter.Status != "" {
+		where = append(where, "p.status = "+addArg(filter.Status))
+	}
+	if filter.Query != "" {
+		placeholder := addArg("%" + escapeLike(filter.Query) + "%")
+		where = append(where, fmt.Sprintf("(p.name ILIKE %[1]s OR p.description ILIKE %[1]s)", placeholder))
+	}
+
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products p WHERE `+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + productColumns + ` FROM products p WHERE ` + whereClause +
+		fmt.Sprintf(` ORDER BY p.created_at DESC LIMIT %s OFFSET %s`, addArg(filter.Limit), addArg(filter.Offset))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var products []*model.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadVariants(ctx, products); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+func (r *productRepository) AddVariant(ctx context.Context, variant *model.Variant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertVariant(ctx, tx, variant); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *productRepository) UpdateVariant(ctx context.Context, variant *model.Variant) error {
+	query := `
+		UPDATE product_variants
+		SET price = $3, weight_grams = $4, is_active = $5, updated_at = NOW()
+		WHERE id::text = $1 AND product_id::text = $2 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		variant.ID,
+		variant.ProductID,
+		variant.Price,
+		variant.WeightGrams,
+		variant.IsActive,
+	).Scan(&variant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrVariantNotFound
+	}
+
+	return err
+}
+
+func (r *productRepository) SoftDeleteVariant(ctx context.Context, productID, variantID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE product_variants SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id::text = $1 AND product_id::text = $2 AND deleted_at IS NULL
+	`, variantID, productID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrVariantNotFound
+	}
+
+	return nil
+}
+
+func (r *productRepository) SetVariantPrices(ctx context.Context, variant *model.Variant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE product_variants SET updated_at = NOW()
+		WHERE id::text = $1 AND product_id::text = $2 AND deleted_at IS NULL
+		RETURNING updated_at
+	`, variant.ID, variant.ProductID).Scan(&variant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM variant_prices WHERE variant_id::text = $1`, variant.ID); err != nil {
+		return err
+	}
+	if err := insertVariantPrices(ctx, tx, variant); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *productRepository) LookupSKUs(ctx context.Context, skus []string, currency string) ([]*model.SKUInfo, error) {
+	query := `
+		SELECT v.sku, p.id, v.id, p.name, COALESCE(v.size, ''), COALESCE(v.color, ''), v.price, vp.amount,
+			v.weight_grams, p.seller_id, p.store_id, COALESCE(p.category_id::text, ''),
+			COALESCE(c.tax_category, $2)
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		LEFT JOIN variant_prices vp ON vp.variant_id = v.id AND vp.currency = $3
+		WHERE v.sku = ANY($1)
+			AND v.deleted_at IS NULL AND v.is_active
+			AND p.deleted_at IS NULL AND p.status = 'published'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(skus), model.DefaultTaxCategory, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.SKUInfo
+	for rows.Next() {
+		var item model.SKUInfo
+		var listPrice sql.NullInt64
+		err := rows.Scan(
+			&item.SKU,
+			&item.ProductID,
+			&item.VariantID,
+			&item.ProductName,
+			&item.Size,
+			&item.Color,
+			&item.Price,
+			&listPrice,
+			&item.WeightGrams,
+			&item.SellerID,
+			&item.StoreID,
+			&item.CategoryID,
+			&item.TaxCategory,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if listPrice.Valid {
+			item.ListPrice = &listPrice.Int64
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *productRepository) loadVariants(ctx context.Context, products []*model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(products))
+	byID := make(map[string]*model.Product, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+		byID[product.ID] = product
+	}
+
+	query := `SELECT ` + variantColumns + `
+		FROM product_variants
+		WHERE product_id::text = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var variantIDs []string
+	variants := map[string]*model.Variant{}
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return err
+		}
+		if product, ok := byID[variant.ProductID]; ok {
+			product.Variants = append(product.Variants, variant)
+			variants[variant.ID] = variant
+			variantIDs = append(variantIDs, variant.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return r.loadVariantPrices(ctx, variantIDs, variants)
+}
+
+func (r *productRepository) loadVariantPrices(ctx context.Context, ids []string, variants map[string]*model.Variant) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT variant_id, currency, amount
+		FROM variant_prices
+		WHERE variant_id::text = ANY($1)
+		ORDER BY currency ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variantID string
+		var price model.Money
+		if err := rows.Scan(&variantID, &price.Currency, &price.Amount); err != nil {
+			return err
+		}
+		if variant, ok := variants[variantID]; ok {
+			variant.Prices = append(variant.Prices, price)
+		}
+	}
+
+	return rows.Err()
+}
+
+func insertVariant(ctx context.Context, tx *sql.Tx, variant *model.Variant) error {
+	query := `
+		INSERT INTO product_variants (product_id, sku, size, color, price, weight_grams, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		variant.ProductID,
+		variant.SKU,
+		variant.Size,
+		variant.Color,
+		variant.Price,
+		variant.WeightGrams,
+		variant.IsActive,
+	).Scan(&variant.ID, &variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		return translateUniqueViolation(err)
+	}
+
+	return insertVariantPrices(ctx, tx, variant)
+}
+
+func insertVariantPrices(ctx context.Context, tx *sql.Tx, variant *model.Variant) error {
+	for _, price := range variant.Prices {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO variant_prices (variant_id, currency, amount) VALUES ($1, $2, $3)`,
+			variant.ID, price.Currency, price.Amount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+func translateUniqueViolation(err error) error {
+	if !isUniqueViolation(err) {
+		return err
+	}
+
+	switch err.(*pq.Error).Constraint {
+	case "idx_product_variants_sku":
+		return ErrDuplicateSKU
+	case "idx_products_store_slug":
+		return ErrDuplicateSlug
+	default:
+		return err
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+)
+
+const maxCategoryDepth = 5
+
+var taxCategoryPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,29}$`)
+
+type categoryServiceImpl struct {
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryServiceImpl{
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateCategory implements CategoryService.
+func (s *categoryServiceImpl) CreateCategory(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	taxCategory := strings.ToLower(strings.TrimSpace(req.TaxCategory))
+
+	invalid := map[string]string{}
+	if name == "" || len(name) > 100 {
+		invalid["name"] = "must be between 1 and 100 characters"
+	}
+	if taxCategory != "" && !taxCategoryPattern.MatchString(taxCategory) {
+		invalid["tax_category"] = "must be lower-case letters, digits or underscores"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	category := &model.Category{
+		ParentID:    req.ParentID,
+		Name:        name,
+		Slug:        slugify(name, "category"),
+		TaxCategory: taxCategory,
+	}
+	category.Path = category.Slug
+
+	if req.ParentID != "" {
+		parent, err := s.categoryRepo.GetByID(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.Count(parent.Path, "/")+1 >= maxCategoryDepth {
+			return nil, &ValidationError{Fields: map[string]string{"parent_id": "category tree is too deep"}}
+		}
+		category.Path = parent.Path + "/" + category.Slug
+		if category.TaxCategory == "" {
+			category.TaxCategory = parent.TaxCategory
+		}
+	}
+	if category.TaxCategory == "" {
+		category.TaxCategory = model.DefaultTaxCategory
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return toCategoryResponse(category), nil
+}
+
+// ListCategories implements CategoryService.
+func (s *categoryServiceImpl) ListCategories(ctx context.Context) ([]*dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		resp = append(resp, toCategoryResponse(category))
+	}
+
+	return resp, nil
+}
+
+func toCategoryResponse(category *model.Category) *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Path:        category.Path,
+		TaxCategory: category.TaxCategory,
+	}
+}
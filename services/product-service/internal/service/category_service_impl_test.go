@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+)
+
+func TestCreateCategoryTree(t *testing.T) {
+	svc := NewCategoryService(&fakeCategoryRepo{categories: map[string]*model.Category{}})
+	ctx := context.Background()
+
+	parentID := ""
+	var paths []string
+	for _, name := range []string{"Clothing", "Men's Shoes", "Running", "Trail", "Waterproof"} {
+		category, err := svc.CreateCategory(ctx, &dto.CreateCategoryRequest{ParentID: parentID, Name: name})
+		if err != nil {
+			t.Fatalf("CreateCategory(%q) error = %v", name, err)
+		}
+		parentID = category.ID
+		paths = append(paths, category.Path)
+	}
+	if got, want := paths[len(paths)-1], "clothing/men-s-shoes/running/trail/waterproof"; got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+
+	var validationErr *ValidationError
+	_, err := svc.CreateCategory(ctx, &dto.CreateCategoryRequest{ParentID: parentID, Name: "Too Deep"})
+	if !errors.As(err, &validationErr) || validationErr.Fields["parent_id"] == "" {
+		t.Errorf("CreateCategory() past the depth limit error = %v, want a parent_id error", err)
+	}
+
+	if _, err := svc.CreateCategory(ctx, &dto.CreateCategoryRequest{ParentID: "missing", Name: "Orphan"}); !errors.Is(err, repository.ErrCategoryNotFound) {
+		t.Errorf("CreateCategory() under an unknown parent error = %v, want %v", err, repository.ErrCategoryNotFound)
+	}
+	if _, err := svc.CreateCategory(ctx, &dto.CreateCategoryRequest{Name: "Clothing"}); !errors.Is(err, repository.ErrCategoryExists) {
+		t.Errorf("CreateCategory() of an existing path error = %v, want %v", err, repository.ErrCategoryExists)
+	}
+}
+
+func TestCreateCategoryValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       dto.CreateCategoryRequest
+		wantField string
+	}{
+		{name: "name required", req: dto.CreateCategoryRequest{Name: "  "}, wantField: "name"},
+		{name: "name too long", req: dto.CreateCategoryRequest{Name: strings.Repeat("a", 101)}, wantField: "name"},
+		{name: "tax category with spaces", req: dto.CreateCategoryRequest{Name: "Books", TaxCategory: "reduced rate"}, wantField: "tax_category"},
+		{name: "tax category too long", req: dto.CreateCategoryRequest{Name: "Books", TaxCategory: strings.Repeat("a", 31)}, wantField: "tax_category"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewCategoryService(&fakeCategoryRepo{categories: map[string]*model.Category{}})
+
+			var validationErr *ValidationError
+			_, err := svc.CreateCategory(context.Background(), &tt.req)
+			if !errors.As(err, &validationErr) || validationErr.Fields[tt.wantField] == "" {
+				t.Errorf("CreateCategory() error = %v, want a %s error", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCreateCategoryTaxCategory(t *testing.T) {
+	svc := NewCategoryService(&fakeCategoryRepo{categories: map[string]*model.Category{}})
+	ctx := context.Background()
+
+	create := func(parentID, name, taxCategory string) *dto.CategoryResponse {
+		t.Helper()
+		category, err := svc.CreateCategory(ctx, &dto.CreateCategoryRequest{ParentID: parentID, Name: name, TaxCategory: taxCategory})
+		if err != nil {
+			t.Fatalf("CreateCategory(%q) error = %v", name, err)
+		}
+		return category
+	}
+
+	root := create("", "Electronics", "")
+	grocery := create("", "Grocery", " Food ")
+	fruit := create(grocery.ID, "Fruit", "")
+	baby := create(grocery.ID, "Baby Formula", "children_food")
+
+	tests := []struct {
+		category *dto.CategoryResponse
+		want     string
+	}{
+		{category: root, want: model.DefaultTaxCategory},
+		{category: grocery, want: "food"},
+		{category: fruit, want: "food"},
+		{category: baby, want: "children_food"},
+	}
+	for _, tt := range tests {
+		if tt.category.TaxCategory != tt.want {
+			t.Errorf("%s tax category = %q, want %q", tt.category.Name, tt.category.TaxCategory, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+)
+
+const (
+	maxRatesPerRequest  = 200
+	maxRateSourceLength = 50
+
+	// RateSourceFile and RateSourceAdmin tell where a rate came from when
+	// the caller does not say.
+	RateSourceFile  = "file"
+	RateSourceAdmin = "admin"
+)
+
+type exchangeRateServiceImpl struct {
+	rateRepo     repository.ExchangeRateRepository
+	baseCurrency string
+}
+
+func NewExchangeRateService(rateRepo repository.ExchangeRateRepository, baseCurrency string) ExchangeRateService {
+	return &exchangeRateServiceImpl{
+		rateRepo:     rateRepo,
+		baseCurrency: baseCurrency,
+	}
+}
+
+// SetExchangeRates implements ExchangeRateService.
+func (s *exchangeRateServiceImpl) SetExchangeRates(ctx context.Context, req *dto.SetExchangeRatesRequest) ([]*dto.ExchangeRateResponse, error) {
+	base := strings.ToUpper(strings.TrimSpace(req.BaseCurrency))
+	source := strings.TrimSpace(req.Source)
+	if source == "" {
+		source = RateSourceAdmin
+	}
+
+	invalid := map[string]string{}
+	if base != s.baseCurrency {
+		invalid["base_currency"] = fmt.Sprintf("must be %s", s.baseCurrency)
+	}
+	if len(source) > maxRateSourceLength {
+		invalid["source"] = fmt.Sprintf("must be at most %d characters", maxRateSourceLength)
+	}
+	if len(req.Rates) == 0 || len(req.Rates) > maxRatesPerRequest {
+		invalid["rates"] = fmt.Sprintf("must contain between 1 and %d rates", maxRatesPerRequest)
+	}
+
+	var rates []*model.ExchangeRate
+	seen := map[string]bool{}
+	for i, input := range req.Rates {
+		currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+		switch {
+		case !model.IsCurrencyCode(currency):
+			invalid[fmt.Sprintf("rates[%d].currency", i)] = "must be an ISO 4217 code"
+		case currency == s.baseCurrency:
+			invalid[fmt.Sprintf("rates[%d].currency", i)] = "is the base currency"
+		case seen[currency]:
+			invalid[fmt.Sprintf("rates[%d].currency", i)] = "is duplicated"
+		}
+		seen[currency] = true
+
+		rate, err := model.NormalizeRate(input.Rate)
+		if err != nil {
+			invalid[fmt.Sprintf("rates[%d].rate", i)] = "must be a positive decimal with at most 12 decimals"
+		}
+
+		rates = append(rates, &model.ExchangeRate{
+			BaseCurrency: base,
+			Currency:     currency,
+			Rate:         rate,
+			Source:       source,
+		})
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	current, err := s.rateRepo.ListCurrent(ctx, s.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := make(map[string]*model.ExchangeRate, len(current))
+	for _, rate := range current {
+		byCurrency[rate.Currency] = rate
+	}
+
+	var changed []*model.ExchangeRate
+	for i, rate := range rates {
+		if existing, ok := byCurrency[rate.Currency]; ok && existing.Rate == rate.Rate {
+			rates[i] = existing
+			continue
+		}
+		changed = append(changed, rate)
+	}
+
+	if len(changed) > 0 {
+		if err := s.rateRepo.Create(ctx, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := make([]*dto.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		resp = append(resp, toExchangeRateResponse(rate))
+	}
+	return resp, nil
+}
+
+// ListExchangeRates implements ExchangeRateService.
+func (s *exchangeRateServiceImpl) ListExchangeRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error) {
+	rates, err := s.rateRepo.ListCurrent(ctx, s.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		resp = append(resp, toExchangeRateResponse(rate))
+	}
+	return resp, nil
+}
+
+// ReadExchangeRatesFile reads a rate file of the form
+//
+//	{"base_currency": "IDR", "rates": {"USD": "0.000061", "SGD": "0.000082"}}
+//
+// into a request for SetExchangeRates.
+func ReadExchangeRatesFile(path string) (*dto.SetExchangeRatesRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		BaseCurrency string            `json:"base_currency"`
+		Rates        map[string]string `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	req := &dto.SetExchangeRatesRequest{
+		BaseCurrency: file.BaseCurrency,
+		Source:       RateSourceFile,
+	}
+	for currency, rate := range file.Rates {
+		req.Rates = append(req.Rates, dto.ExchangeRateInput{Currency: currency, Rate: rate})
+	}
+	sort.Slice(req.Rates, func(i, j int) bool {
+		return req.Rates[i].Currency < req.Rates[j].Currency
+	})
+
+	return req, nil
+}
+
+func toExchangeRateResponse(rate *model.ExchangeRate) *dto.ExchangeRateResponse {
+	return &dto.ExchangeRateResponse{
+		ID:           rate.ID,
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+		Source:       rate.Source,
+		CreatedAt:    rate.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+func TestSetExchangeRatesValidation(t *testing.T) {
+	tooMany := make([]dto.ExchangeRateInput, maxRatesPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = dto.ExchangeRateInput{Currency: "EUR", Rate: "0.9"}
+	}
+
+	tests := []struct {
+		name      string
+		req       *dto.SetExchangeRatesRequest
+		wantField string
+	}{
+		{name: "valid", req: &dto.SetExchangeRatesRequest{BaseCurrency: " usd ", Rates: []dto.ExchangeRateInput{{Currency: "eur", Rate: "0.9"}}}},
+		{name: "other base currency", req: &dto.SetExchangeRatesRequest{BaseCurrency: "IDR", Rates: []dto.ExchangeRateInput{{Currency: "EUR", Rate: "0.9"}}}, wantField: "base_currency"},
+		{name: "source too long", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Source: strings.Repeat("s", maxRateSourceLength+1), Rates: []dto.ExchangeRateInput{{Currency: "EUR", Rate: "0.9"}}}, wantField: "source"},
+		{name: "no rates", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD"}, wantField: "rates"},
+		{name: "too many rates", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: tooMany}, wantField: "rates"},
+		{name: "not a currency code", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: []dto.ExchangeRateInput{{Currency: "EURO", Rate: "0.9"}}}, wantField: "rates[0].currency"},
+		{name: "rate for the base currency", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: []dto.ExchangeRateInput{{Currency: "USD", Rate: "1"}}}, wantField: "rates[0].currency"},
+		{name: "duplicated currency", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: []dto.ExchangeRateInput{{Currency: "EUR", Rate: "0.9"}, {Currency: "eur", Rate: "0.8"}}}, wantField: "rates[1].currency"},
+		{name: "zero rate", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: []dto.ExchangeRateInput{{Currency: "EUR", Rate: "0"}}}, wantField: "rates[0].rate"},
+		{name: "too many decimals", req: &dto.SetExchangeRatesRequest{BaseCurrency: "USD", Rates: []dto.ExchangeRateInput{{Currency: "EUR", Rate: "0.1234567890123"}}}, wantField: "rates[0].rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := &fakeRateRepo{}
+			svc := NewExchangeRateService(rates, "USD")
+
+			_, err := svc.SetExchangeRates(context.Background(), tt.req)
+
+			var validationErr *ValidationError
+			switch {
+			case tt.wantField == "" && err != nil:
+				t.Fatalf("SetExchangeRates() error = %v, want nil", err)
+			case tt.wantField != "" && !errors.As(err, &validationErr):
+				t.Fatalf("SetExchangeRates() error = %v, want validation error", err)
+			case tt.wantField != "" && validationErr.Fields[tt.wantField] == "":
+				t.Errorf("SetExchangeRates() invalid fields = %v, want %s", validationErr.Fields, tt.wantField)
+			case tt.wantField != "" && len(rates.rates) != 0:
+				t.Errorf("SetExchangeRates() stored %d rates from an invalid request", len(rates.rates))
+			}
+		})
+	}
+}
+
+func TestSetExchangeRatesKeepsUnchangedRates(t *testing.T) {
+	rates := &fakeRateRepo{}
+	svc := NewExchangeRateService(rates, "USD")
+	ctx := context.Background()
+
+	if _, err := svc.SetExchangeRates(ctx, &dto.SetExchangeRatesRequest{
+		BaseCurrency: "USD",
+		Rates: []dto.ExchangeRateInput{
+			{Currency: "EUR", Rate: "0.9"},
+			{Currency: "JPY", Rate: "150"},
+		},
+	}); err != nil {
+		t.Fatalf("SetExchangeRates() error = %v", err)
+	}
+
+	resp, err := svc.SetExchangeRates(ctx, &dto.SetExchangeRatesRequest{
+		BaseCurrency: "USD",
+		Source:       RateSourceFile,
+		Rates: []dto.ExchangeRateInput{
+			{Currency: "EUR", Rate: "0.90"},
+			{Currency: "JPY", Rate: "151.5"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SetExchangeRates() error = %v", err)
+	}
+
+	if len(rates.rates) != 3 {
+		t.Fatalf("stored %d rates, want 3: an unchanged rate must not be stored again", len(rates.rates))
+	}
+	got := fmt.Sprintf("%d %s %s, %d %s %s",
+		resp[0].ID, resp[0].Rate, resp[0].Source, resp[1].ID, resp[1].Rate, resp[1].Source)
+	if want := "1 0.9 admin, 3 151.5 file"; got != want {
+		t.Errorf("SetExchangeRates() = %s, want %s", got, want)
+	}
+
+	current, err := svc.ListExchangeRates(ctx)
+	if err != nil {
+		t.Fatalf("ListExchangeRates() error = %v", err)
+	}
+	byCurrency := map[string]string{}
+	for _, rate := range current {
+		byCurrency[rate.Currency] = rate.Rate
+	}
+	if byCurrency["EUR"] != "0.9" || byCurrency["JPY"] != "151.5" {
+		t.Errorf("ListExchangeRates() = %v, want EUR 0.9 and JPY 151.5", byCurrency)
+	}
+}
+
+func TestReadExchangeRatesFile(t *testing.T) {
+	req, err := ReadExchangeRatesFile("../../data/exchange_rates.json")
+	if err != nil {
+		t.Fatalf("ReadExchangeRatesFile() error = %v", err)
+	}
+
+	if req.BaseCurrency != "IDR" || req.Source != RateSourceFile {
+		t.Errorf("ReadExchangeRatesFile() base and source = %s, %s, want IDR, %s", req.BaseCurrency, req.Source, RateSourceFile)
+	}
+	var currencies []string
+	for _, rate := range req.Rates {
+		currencies = append(currencies, rate.Currency)
+	}
+	if got := strings.Join(currencies, " "); got != "EUR JPY MYR SGD USD" {
+		t.Errorf("ReadExchangeRatesFile() currencies = %s, want them sorted", got)
+	}
+
+	// The shipped file must load into a catalog priced in its base currency.
+	svc := NewExchangeRateService(&fakeRateRepo{}, "IDR")
+	if _, err := svc.SetExchangeRates(context.Background(), req); err != nil {
+		t.Errorf("SetExchangeRates() of the shipped file error = %v", err)
+	}
+
+	if _, err := ReadExchangeRatesFile("../../data/missing.json"); err == nil {
+		t.Error("ReadExchangeRatesFile() of a missing file error = nil")
+	}
+}
+
+func TestLookupSKUsCurrency(t *testing.T) {
+	listPrice := int64(999)
+	products := newFakeProductRepo()
+	products.lookups["TR-42"] = &model.SKUInfo{SKU: "TR-42", Price: 12345}
+	products.lookups["TR-43"] = &model.SKUInfo{SKU: "TR-43", Price: 12345, ListPrice: &listPrice}
+
+	rates := &fakeRateRepo{}
+	_ = rates.Create(context.Background(), []*model.ExchangeRate{
+		{BaseCurrency: "USD", Currency: "EUR", Rate: "0.9"},
+		{BaseCurrency: "USD", Currency: "JPY", Rate: "150"},
+		{BaseCurrency: "USD", Currency: "EUR", Rate: "0.5"},
+	})
+	svc := NewProductService(products, &fakeCategoryRepo{}, rates, "USD")
+
+	tests := []struct {
+		name       string
+		currency   string
+		rateID     int64
+		wantRate   string
+		wantRateID int64
+		wantPrices string
+		wantField  string
+	}{
+		{name: "base currency", currency: "", wantRate: "1", wantPrices: "12345 999"},
+		{name: "base currency by code", currency: "usd", wantRate: "1", wantPrices: "12345 999"},
+		{name: "current rate", currency: "EUR", wantRate: "0.5", wantRateID: 3, wantPrices: "6173 999"},
+		{name: "pinned rate", currency: "EUR", rateID: 1, wantRate: "0.9", wantRateID: 1, wantPrices: "11111 999"},
+		{name: "no decimals", currency: "JPY", wantRate: "150", wantRateID: 2, wantPrices: "18518 999"},
+		{name: "pinned rate of another currency", currency: "JPY", rateID: 1, wantField: "exchange_rate_id"},
+		{name: "unsupported currency", currency: "GBP", wantField: "currency"},
+		{name: "not a currency code", currency: "EURO", wantField: "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, rate, err := svc.LookupSKUs(context.Background(), []string{" tr-42", "TR-43"}, tt.currency, tt.rateID)
+
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Fields[tt.wantField] == "" {
+					t.Errorf("LookupSKUs() error = %v, want validation error on %s", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupSKUs() error = %v", err)
+			}
+
+			if rate.Rate != tt.wantRate || rate.ID != tt.wantRateID {
+				t.Errorf("LookupSKUs() rate = %d/%s, want %d/%s", rate.ID, rate.Rate, tt.wantRateID, tt.wantRate)
+			}
+			var prices []string
+			for _, item := range items {
+				prices = append(prices, fmt.Sprint(item.Price))
+			}
+			if got := strings.Join(prices, " "); got != tt.wantPrices {
+				t.Errorf("LookupSKUs() prices = %s, want %s", got, tt.wantPrices)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+)
+
+var ErrNotPublishable = errors.New("product needs a category and at least one active variant to be published")
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// CategoryService manages the category tree
+type CategoryService interface {
+	CreateCategory(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	ListCategories(ctx context.Context) ([]*dto.CategoryResponse, error)
+}
+
+// ProductService manages products and their variants. Every mutating call
+// takes the seller id and only touches products that seller owns.
+type ProductService interface {
+	CreateProduct(ctx context.Context, sellerID int64, req *dto.CreateProductRequest) (*dto.ProductResponse, error)
+	UpdateProduct(ctx context.Context, sellerID int64, id string, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
+	PublishProduct(ctx context.Context, sellerID int64, id string) (*dto.ProductResponse, error)
+	UnpublishProduct(ctx context.Context, sellerID int64, id string) (*dto.ProductResponse, error)
+	DeleteProduct(ctx context.Context, sellerID int64, id string) error
+
+	// GetProduct returns published products, and drafts only to their owner.
+	GetProduct(ctx context.Context, id string, viewerSellerID int64) (*dto.ProductResponse, error)
+	ListProducts(ctx context.Context, req *dto.ListProductsRequest) ([]*dto.ProductResponse, int, error)
+
+	AddVariant(ctx context.Context, sellerID int64, productID string, req *dto.VariantRequest) (*dto.ProductResponse, error)
+	UpdateVariant(ctx context.Context, sellerID int64, productID, variantID string, req *dto.UpdateVariantRequest) (*dto.ProductResponse, error)
+	DeleteVariant(ctx context.Context, sellerID int64, productID, variantID string) (*dto.ProductResponse, error)
+	// SetVariantPrices replaces the variant's prices in currencies other
+	// than the base currency.
+	SetVariantPrices(ctx context.Context, sellerID int64, productID, variantID string, prices []dto.Money) (*dto.ProductResponse, error)
+
+	// LookupSKUs resolves purchasable SKUs for the cart and order services,
+	// priced in currency; empty means the base currency. Prices the seller
+	// did not set for the currency are converted with the exchange rate
+	// exchangeRateID, or the current rate when it is zero. The rate used is
+	// returned alongside.
+	LookupSKUs(ctx context.Context, skus []string, currency string, exchangeRateID int64) ([]*model.SKUInfo, *model.ExchangeRate, error)
+}
+
+// ExchangeRateService keeps the rates base prices are converted with.
+type ExchangeRateService interface {
+	// SetExchangeRates records new rates. Rates that equal the current one
+	// are skipped, so loading the same file again changes nothing.
+	SetExchangeRates(ctx context.Context, req *dto.SetExchangeRatesRequest) ([]*dto.ExchangeRateResponse, error)
+	ListExchangeRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error)
+}
@@ -0,0 +1,618 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+)
+
+const (
+	defaultPageSize    = 20
+	maxPageSize        = 100
+	maxSlugAttempts    = 10
+	maxVariantsPerItem = 100
+	maxPricesPerItem   = 50
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,63}$`)
+
+type productServiceImpl struct {
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	rateRepo     repository.ExchangeRateRepository
+	baseCurrency string
+}
+
+func NewProductService(
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	rateRepo repository.ExchangeRateRepository,
+	baseCurrency string,
+) ProductService {
+	return &productServiceImpl{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		rateRepo:     rateRepo,
+		baseCurrency: baseCurrency,
+	}
+}
+
+// CreateProduct implements ProductService.
+func (s *productServiceImpl) CreateProduct(ctx context.Context, sellerID int64, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	product := &model.Product{
+		SellerID:    sellerID,
+		StoreID:     req.StoreID,
+		CategoryID:  req.CategoryID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Status:      model.ProductStatusDraft,
+	}
+
+	fields := map[string]string{}
+	validateProductFields(fields, product)
+	if product.StoreID == "" {
+		fields["store_id"] = "is required"
+	}
+	if len(req.Variants) > maxVariantsPerItem {
+		fields["variants"] = fmt.Sprintf("at most %d variants are allowed", maxVariantsPerItem)
+	}
+
+	seen := map[string]bool{}
+	for i, variantReq := range req.Variants {
+		variant := s.buildVariant(fields, fmt.Sprintf("variants[%d].", i), variantReq)
+		if seen[variant.SKU] {
+			fields[fmt.Sprintf("variants[%d].sku", i)] = "is duplicated"
+		}
+		seen[variant.SKU] = true
+		product.Variants = append(product.Variants, variant)
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+
+	base := slugify(product.Name, "product")
+	for attempt := 1; ; attempt++ {
+		product.Slug = slugCandidate(base, attempt)
+
+		err := s.productRepo.Create(ctx, product)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repository.ErrDuplicateSlug) || attempt == maxSlugAttempts {
+			return nil, err
+		}
+	}
+
+	return toProductResponse(product), nil
+}
+
+// UpdateProduct implements ProductService.
+func (s *productServiceImpl) UpdateProduct(ctx context.Context, sellerID int64, id string, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		renamed = name != product.Name
+		product.Name = name
+	}
+	if req.Description != nil {
+		product.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.CategoryID != nil {
+		product.CategoryID = *req.CategoryID
+	}
+
+	fields := map[string]string{}
+	validateProductFields(fields, product)
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	if product.Status == model.ProductStatusPublished && product.CategoryID == "" {
+		return nil, ErrNotPublishable
+	}
+
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+
+	// Published products keep their slug so that shared links keep working.
+	base := product.Slug
+	if renamed && product.Status == model.ProductStatusDraft {
+		base = slugify(product.Name, "product")
+	}
+
+	for attempt := 1; ; attempt++ {
+		product.Slug = slugCandidate(base, attempt)
+
+		err := s.productRepo.Update(ctx, product)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repository.ErrDuplicateSlug) || attempt == maxSlugAttempts {
+			return nil, err
+		}
+	}
+
+	return toProductResponse(product), nil
+}
+
+// PublishProduct implements ProductService.
+func (s *productServiceImpl) PublishProduct(ctx context.Context, sellerID int64, id string) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Status == model.ProductStatusPublished {
+		return toProductResponse(product), nil
+	}
+
+	if product.CategoryID == "" || !hasActiveVariant(product) {
+		return nil, ErrNotPublishable
+	}
+
+	now := time.Now().UTC()
+	if err := s.productRepo.UpdateStatus(ctx, product.ID, model.ProductStatusPublished, &now); err != nil {
+		return nil, err
+	}
+
+	product.Status = model.ProductStatusPublished
+	product.PublishedAt = &now
+	product.UpdatedAt = now
+
+	return toProductResponse(product), nil
+}
+
+// UnpublishProduct implements ProductService.
+func (s *productServiceImpl) UnpublishProduct(ctx context.Context, sellerID int64, id string) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Status == model.ProductStatusDraft {
+		return toProductResponse(product), nil
+	}
+
+	if err := s.productRepo.UpdateStatus(ctx, product.ID, model.ProductStatusDraft, nil); err != nil {
+		return nil, err
+	}
+
+	product.Status = model.ProductStatusDraft
+	product.PublishedAt = nil
+	product.UpdatedAt = time.Now().UTC()
+
+	return toProductResponse(product), nil
+}
+
+// DeleteProduct implements ProductService.
+func (s *productServiceImpl) DeleteProduct(ctx context.Context, sellerID int64, id string) error {
+	if _, err := s.getOwnedProduct(ctx, sellerID, id); err != nil {
+		return err
+	}
+
+	return s.productRepo.SoftDelete(ctx, id)
+}
+
+// GetProduct implements ProductService.
+func (s *productServiceImpl) GetProduct(ctx context.Context, id string, viewerSellerID int64) (*dto.ProductResponse, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Status != model.ProductStatusPublished && product.SellerID != viewerSellerID {
+		return nil, repository.ErrProductNotFound
+	}
+
+	return toProductResponse(product), nil
+}
+
+// ListProducts implements ProductService.
+func (s *productServiceImpl) ListProducts(ctx context.Context, req *dto.ListProductsRequest) ([]*dto.ProductResponse, int, error) {
+	filter := repository.ProductFilter{
+		StoreID:  req.StoreID,
+		Query:    strings.TrimSpace(req.Query),
+		SellerID: req.SellerID,
+	}
+
+	// Buyers only ever see published products, sellers browsing their own
+	// catalog see drafts too.
+	if req.SellerID == 0 {
+		filter.Status = model.ProductStatusPublished
+	}
+
+	if req.CategoryID != "" {
+		category, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.CategoryPath = category.Path
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		pageSize = defaultPageSize
+	}
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	products, total, err := s.productRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		resp = append(resp, toProductResponse(product))
+	}
+
+	return resp, total, nil
+}
+
+// AddVariant implements ProductService.
+func (s *productServiceImpl) AddVariant(ctx context.Context, sellerID int64, productID string, req *dto.VariantRequest) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(product.Variants) >= maxVariantsPerItem {
+		return nil, &ValidationError{Fields: map[string]string{"variants": fmt.Sprintf("at most %d variants are allowed", maxVariantsPerItem)}}
+	}
+
+	fields := map[string]string{}
+	variant := s.buildVariant(fields, "", req)
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+	variant.ProductID = product.ID
+
+	if err := s.productRepo.AddVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	product.Variants = append(product.Variants, variant)
+	return toProductResponse(product), nil
+}
+
+// UpdateVariant implements ProductService.
+func (s *productServiceImpl) UpdateVariant(ctx context.Context, sellerID int64, productID, variantID string, req *dto.UpdateVariantRequest) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := findVariant(product, variantID)
+	if variant == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	fields := map[string]string{}
+	if req.Price != nil {
+		if *req.Price < 0 {
+			fields["price"] = "must not be negative"
+		}
+		variant.Price = *req.Price
+	}
+	if req.WeightGrams != nil {
+		if *req.WeightGrams < 0 {
+			fields["weight_grams"] = "must not be negative"
+		}
+		variant.WeightGrams = *req.WeightGrams
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	if product.Status == model.ProductStatusPublished && !hasActiveVariant(product) {
+		return nil, ErrNotPublishable
+	}
+
+	if err := s.productRepo.UpdateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	return toProductResponse(product), nil
+}
+
+// DeleteVariant implements ProductService.
+func (s *productServiceImpl) DeleteVariant(ctx context.Context, sellerID int64, productID, variantID string) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make([]*model.Variant, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		if variant.ID != variantID {
+			remaining = append(remaining, variant)
+		}
+	}
+	if len(remaining) == len(product.Variants) {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	product.Variants = remaining
+	if product.Status == model.ProductStatusPublished && !hasActiveVariant(product) {
+		return nil, ErrNotPublishable
+	}
+
+	if err := s.productRepo.SoftDeleteVariant(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	return toProductResponse(product), nil
+}
+
+// SetVariantPrices implements ProductService.
+func (s *productServiceImpl) SetVariantPrices(ctx context.Context, sellerID int64, productID, variantID string, prices []dto.Money) (*dto.ProductResponse, error) {
+	product, err := s.getOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := findVariant(product, variantID)
+	if variant == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	fields := map[string]string{}
+	variant.Prices = s.buildPrices(fields, "", prices)
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	if err := s.productRepo.SetVariantPrices(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	return toProductResponse(product), nil
+}
+
+// LookupSKUs implements ProductService.
+func (s *productServiceImpl) LookupSKUs(ctx context.Context, skus []string, currency string, exchangeRateID int64) ([]*model.SKUInfo, *model.ExchangeRate, error) {
+	rate, err := s.exchangeRate(ctx, strings.ToUpper(strings.TrimSpace(currency)), exchangeRateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(skus) == 0 {
+		return nil, rate, nil
+	}
+
+	normalized := make([]string, 0, len(skus))
+	for _, sku := range skus {
+		normalized = append(normalized, strings.ToUpper(strings.TrimSpace(sku)))
+	}
+
+	items, err := s.productRepo.LookupSKUs(ctx, normalized, rate.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, item := range items {
+		if item.ListPrice != nil {
+			item.Price = *item.ListPrice
+			continue
+		}
+		if item.Price, err = rate.Convert(item.Price); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return items, rate, nil
+}
+
+// exchangeRate finds the rate from the base currency to currency: the one
+// with id when given, otherwise the current one.
+func (s *productServiceImpl) exchangeRate(ctx context.Context, currency string, id int64) (*model.ExchangeRate, error) {
+	if currency == "" {
+		currency = s.baseCurrency
+	}
+	if !model.IsCurrencyCode(currency) {
+		return nil, &ValidationError{Fields: map[string]string{"currency": "must be an ISO 4217 code"}}
+	}
+
+	if id != 0 {
+		rate, err := s.rateRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if rate.BaseCurrency != s.baseCurrency || rate.Currency != currency {
+			return nil, &ValidationError{Fields: map[string]string{
+				"exchange_rate_id": fmt.Sprintf("is not a rate from %s to %s", s.baseCurrency, currency),
+			}}
+		}
+		return rate, nil
+	}
+
+	if currency == s.baseCurrency {
+		return model.IdentityRate(currency), nil
+	}
+
+	rate, err := s.rateRepo.GetCurrent(ctx, s.baseCurrency, currency)
+	if errors.Is(err, repository.ErrExchangeRateNotFound) {
+		return nil, &ValidationError{Fields: map[string]string{"currency": "is not supported"}}
+	}
+
+	return rate, err
+}
+
+// getOwnedProduct hides products of other sellers behind ErrProductNotFound
+// so that their ids cannot be probed.
+func (s *productServiceImpl) getOwnedProduct(ctx context.Context, sellerID int64, id string) (*model.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, repository.ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+func (s *productServiceImpl) checkCategory(ctx context.Context, categoryID string) error {
+	if categoryID == "" {
+		return nil
+	}
+
+	_, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return &ValidationError{Fields: map[string]string{"category_id": "unknown category"}}
+	}
+
+	return err
+}
+
+func validateProductFields(fields map[string]string, product *model.Product) {
+	if product.Name == "" || len(product.Name) > 200 {
+		fields["name"] = "must be between 1 and 200 characters"
+	}
+	if len(product.Description) > 10000 {
+		fields["description"] = "must be at most 10000 characters"
+	}
+}
+
+func (s *productServiceImpl) buildVariant(fields map[string]string, prefix string, req *dto.VariantRequest) *model.Variant {
+	variant := &model.Variant{
+		SKU:         strings.ToUpper(strings.TrimSpace(req.SKU)),
+		Size:        strings.TrimSpace(req.Size),
+		Color:       strings.TrimSpace(req.Color),
+		Price:       req.Price,
+		WeightGrams: req.WeightGrams,
+		IsActive:    true,
+	}
+
+	if !skuPattern.MatchString(variant.SKU) {
+		fields[prefix+"sku"] = "must be 1-64 letters, digits, '.', '_' or '-'"
+	}
+	if len(variant.Size) > 20 {
+		fields[prefix+"size"] = "must be at most 20 characters"
+	}
+	if len(variant.Color) > 30 {
+		fields[prefix+"color"] = "must be at most 30 characters"
+	}
+	if variant.Price < 0 {
+		fields[prefix+"price"] = "must not be negative"
+	}
+	if variant.WeightGrams < 0 {
+		fields[prefix+"weight_grams"] = "must not be negative"
+	}
+	variant.Prices = s.buildPrices(fields, prefix, req.Prices)
+
+	return variant
+}
+
+func (s *productServiceImpl) buildPrices(fields map[string]string, prefix string, req []dto.Money) []model.Money {
+	if len(req) > maxPricesPerItem {
+		fields[prefix+"prices"] = fmt.Sprintf("at most %d prices are allowed", maxPricesPerItem)
+		return nil
+	}
+
+	prices := make([]model.Money, 0, len(req))
+	seen := map[string]bool{}
+	for i, price := range req {
+		currency := strings.ToUpper(strings.TrimSpace(price.Currency))
+		switch {
+		case !model.IsCurrencyCode(currency):
+			fields[fmt.Sprintf("%sprices[%d].currency", prefix, i)] = "must be an ISO 4217 code"
+		case currency == s.baseCurrency:
+			fields[fmt.Sprintf("%sprices[%d].currency", prefix, i)] = "is the base currency, set price instead"
+		case seen[currency]:
+			fields[fmt.Sprintf("%sprices[%d].currency", prefix, i)] = "is duplicated"
+		}
+		if price.Amount < 0 {
+			fields[fmt.Sprintf("%sprices[%d].amount", prefix, i)] = "must not be negative"
+		}
+		seen[currency] = true
+		prices = append(prices, model.Money{Amount: price.Amount, Currency: currency})
+	}
+
+	return prices
+}
+
+func findVariant(product *model.Product, variantID string) *model.Variant {
+	for _, variant := range product.Variants {
+		if variant.ID == variantID {
+			return variant
+		}
+	}
+	return nil
+}
+
+func hasActiveVariant(product *model.Product) bool {
+	for _, variant := range product.Variants {
+		if variant.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+func toProductResponse(product *model.Product) *dto.ProductResponse {
+	resp := &dto.ProductResponse{
+		ID:          product.ID,
+		SellerID:    product.SellerID,
+		StoreID:     product.StoreID,
+		CategoryID:  product.CategoryID,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description,
+		Status:      string(product.Status),
+		Variants:    make([]*dto.VariantResponse, 0, len(product.Variants)),
+		CreatedAt:   product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   product.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if product.PublishedAt != nil {
+		resp.PublishedAt = product.PublishedAt.Format(time.RFC3339)
+	}
+
+	for _, variant := range product.Variants {
+		variantResp := &dto.VariantResponse{
+			ID:          variant.ID,
+			SKU:         variant.SKU,
+			Size:        variant.Size,
+			Color:       variant.Color,
+			Price:       variant.Price,
+			WeightGrams: variant.WeightGrams,
+			IsActive:    variant.IsActive,
+			Prices:      make([]dto.Money, 0, len(variant.Prices)),
+		}
+		for _, price := range variant.Prices {
+			variantResp.Prices = append(variantResp.Prices, dto.Money{
+				Amount:   price.Amount,
+				Currency: price.Currency,
+			})
+		}
+		resp.Variants = append(resp.Variants, variantResp)
+	}
+
+	return resp
+}
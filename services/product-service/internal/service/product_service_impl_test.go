@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/product-service/internal/repository"
+)
+
+// fakeProductRepo keeps products in memory and enforces unique slugs per
+// store, as the database does.
+type fakeProductRepo struct {
+	products map[string]*model.Product
+	// lookups are the SKUs LookupSKUs returns, by SKU.
+	lookups map[string]*model.SKUInfo
+}
+
+func newFakeProductRepo() *fakeProductRepo {
+	return &fakeProductRepo{products: map[string]*model.Product{}, lookups: map[string]*model.SKUInfo{}}
+}
+
+func (r *fakeProductRepo) slugTaken(product *model.Product) bool {
+	for _, other := range r.products {
+		if other.ID != product.ID && other.StoreID == product.StoreID && other.Slug == product.Slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeProductRepo) Create(ctx context.Context, product *model.Product) error {
+	if r.slugTaken(product) {
+		return repository.ErrDuplicateSlug
+	}
+	product.ID = fmt.Sprintf("p%d", len(r.products)+1)
+	for i, variant := range product.Variants {
+		variant.ID = fmt.Sprintf("%s-v%d", product.ID, i+1)
+		variant.ProductID = product.ID
+	}
+	r.products[product.ID] = product
+	return nil
+}
+
+func (r *fakeProductRepo) Update(ctx context.Context, product *model.Product) error {
+	if r.slugTaken(product) {
+		return repository.ErrDuplicateSlug
+	}
+	r.products[product.ID] = product
+	return nil
+}
+
+func (r *fakeProductRepo) UpdateStatus(ctx context.Context, id string, status model.ProductStatus, publishedAt *time.Time) error {
+	r.products[id].Status = status
+	r.products[id].PublishedAt = publishedAt
+	return nil
+}
+
+func (r *fakeProductRepo) SoftDelete(ctx context.Context, id string) error {
+	delete(r.products, id)
+	return nil
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrProductNotFound
+	}
+	copied := *product
+	copied.Variants = nil
+	for _, variant := range product.Variants {
+		v := *variant
+		copied.Variants = append(copied.Variants, &v)
+	}
+	return &copied, nil
+}
+
+func (r *fakeProductRepo) List(ctx context.Context, filter repository.ProductFilter) ([]*model.Product, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *fakeProductRepo) AddVariant(ctx context.Context, variant *model.Variant) error {
+	product := r.products[variant.ProductID]
+	variant.ID = fmt.Sprintf("%s-v%d", product.ID, len(product.Variants)+1)
+	product.Variants = append(product.Variants, variant)
+	return nil
+}
+
+func (r *fakeProductRepo) UpdateVariant(ctx context.Context, variant *model.Variant) error {
+	product := r.products[variant.ProductID]
+	for i, existing := range product.Variants {
+		if existing.ID == variant.ID {
+			product.Variants[i] = variant
+		}
+	}
+	return nil
+}
+
+func (r *fakeProductRepo) SoftDeleteVariant(ctx context.Context, productID, variantID string) error {
+	product := r.products[productID]
+	for i, variant := range product.Variants {
+		if variant.ID == variantID {
+			product.Variants = append(product.Variants[:i], product.Variants[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrVariantNotFound
+}
+
+func (r *fakeProductRepo) SetVariantPrices(ctx context.Context, variant *model.Variant) error {
+	return r.UpdateVariant(ctx, variant)
+}
+
+func (r *fakeProductRepo) LookupSKUs(ctx context.Context, skus []string, currency string) ([]*model.SKUInfo, error) {
+	var items []*model.SKUInfo
+	for _, sku := range skus {
+		if item, ok := r.lookups[sku]; ok {
+			copied := *item
+			items = append(items, &copied)
+		}
+	}
+	return items, nil
+}
+
+type fakeCategoryRepo struct {
+	categories map[string]*model.Category
+}
+
+func (r *fakeCategoryRepo) Create(ctx context.Context, category *model.Category) error {
+	for _, other := range r.categories {
+		if other.Path == category.Path {
+			return repository.ErrCategoryExists
+		}
+	}
+	category.ID = fmt.Sprintf("c%d", len(r.categories)+1)
+	r.categories[category.ID] = category
+	return nil
+}
+
+func (r *fakeCategoryRepo) GetByID(ctx context.Context, id string) (*model.Category, error) {
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, repository.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (r *fakeCategoryRepo) List(ctx context.Context) ([]*model.Category, error) {
+	return nil, errors.New("not implemented")
+}
+
+// fakeRateRepo keeps every rate set, the latest per currency being the
+// current one.
+type fakeRateRepo struct {
+	rates []*model.ExchangeRate
+}
+
+func (r *fakeRateRepo) Create(ctx context.Context, rates []*model.ExchangeRate) error {
+	for _, rate := range rates {
+		rate.ID = int64(len(r.rates) + 1)
+		r.rates = append(r.rates, rate)
+	}
+	return nil
+}
+
+func (r *fakeRateRepo) GetByID(ctx context.Context, id int64) (*model.ExchangeRate, error) {
+	for _, rate := range r.rates {
+		if rate.ID == id {
+			return rate, nil
+		}
+	}
+	return nil, repository.ErrExchangeRateNotFound
+}
+
+func (r *fakeRateRepo) GetCurrent(ctx context.Context, baseCurrency, currency string) (*model.ExchangeRate, error) {
+	var current *model.ExchangeRate
+	for _, rate := range r.rates {
+		if rate.BaseCurrency == baseCurrency && rate.Currency == currency {
+			current = rate
+		}
+	}
+	if current == nil {
+		return nil, repository.ErrExchangeRateNotFound
+	}
+	return current, nil
+}
+
+func (r *fakeRateRepo) ListCurrent(ctx context.Context, baseCurrency string) ([]*model.ExchangeRate, error) {
+	var current []*model.ExchangeRate
+	seen := map[string]bool{}
+	for i := len(r.rates) - 1; i >= 0; i-- {
+		rate := r.rates[i]
+		if rate.BaseCurrency == baseCurrency && !seen[rate.Currency] {
+			seen[rate.Currency] = true
+			current = append(current, rate)
+		}
+	}
+	return current, nil
+}
+
+const (
+	sellerID      = 7
+	otherSellerID = 8
+)
+
+func newTestProductService() (ProductService, *fakeProductRepo) {
+	products := newFakeProductRepo()
+	categories := &fakeCategoryRepo{categories: map[string]*model.Category{
+		"shoes": {ID: "shoes", Name: "Shoes", Slug: "shoes", Path: "shoes", TaxCategory: "standard"},
+	}}
+	return NewProductService(products, categories, &fakeRateRepo{}, "USD"), products
+}
+
+func validProductRequest() *dto.CreateProductRequest {
+	return &dto.CreateProductRequest{
+		StoreID:    "s1",
+		CategoryID: "shoes",
+		Name:       "Trail Runner",
+		Variants: []*dto.VariantRequest{
+			{SKU: "tr-42", Size: "42", Price: 12000, WeightGrams: 700},
+		},
+	}
+}
+
+func TestCreateProductValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		edit       func(req *dto.CreateProductRequest)
+		wantFields []string
+	}{
+		{name: "valid", edit: func(req *dto.CreateProductRequest) {}},
+		{name: "name required", edit: func(req *dto.CreateProductRequest) {
+			req.Name = "   "
+		}, wantFields: []string{"name"}},
+		{name: "name too long", edit: func(req *dto.CreateProductRequest) {
+			req.Name = strings.Repeat("a", 201)
+		}, wantFields: []string{"name"}},
+		{name: "store required", edit: func(req *dto.CreateProductRequest) {
+			req.StoreID = ""
+		}, wantFields: []string{"store_id"}},
+		{name: "variant fields", edit: func(req *dto.CreateProductRequest) {
+			req.Variants = []*dto.VariantRequest{{
+				SKU:         "bad sku",
+				Size:        strings.Repeat("x", 21),
+				Color:       strings.Repeat("x", 31),
+				Price:       -1,
+				WeightGrams: -1,
+			}}
+		}, wantFields: []string{"variants[0].sku", "variants[0].size", "variants[0].color", "variants[0].price", "variants[0].weight_grams"}},
+		{name: "duplicate sku differing in case", edit: func(req *dto.CreateProductRequest) {
+			req.Variants = append(req.Variants, &dto.VariantRequest{SKU: " TR-42 ", Price: 1})
+		}, wantFields: []string{"variants[1].sku"}},
+		{name: "price in the base currency", edit: func(req *dto.CreateProductRequest) {
+			req.Variants[0].Prices = []dto.Money{{Amount: 100, Currency: "usd"}}
+		}, wantFields: []string{"variants[0].prices[0].currency"}},
+		{name: "unknown category", edit: func(req *dto.CreateProductRequest) {
+			req.CategoryID = "hats"
+		}, wantFields: []string{"category_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestProductService()
+			req := validProductRequest()
+			tt.edit(req)
+
+			product, err := svc.CreateProduct(context.Background(), sellerID, req)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("CreateProduct() error = %v", err)
+				}
+				if product.Status != string(model.ProductStatusDraft) || product.Variants[0].SKU != "TR-42" {
+					t.Errorf("CreateProduct() = status %s sku %s, want a draft with SKU TR-42", product.Status, product.Variants[0].SKU)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("CreateProduct() error = %v, want validation error", err)
+			}
+			for _, field := range tt.wantFields {
+				if validationErr.Fields[field] == "" {
+					t.Errorf("CreateProduct() invalid fields = %v, want %s", validationErr.Fields, field)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateProductSlugs(t *testing.T) {
+	svc, _ := newTestProductService()
+	ctx := context.Background()
+
+	var slugs []string
+	for i := 0; i < 3; i++ {
+		product, err := svc.CreateProduct(ctx, sellerID, validProductRequest())
+		if err != nil {
+			t.Fatalf("CreateProduct() error = %v", err)
+		}
+		slugs = append(slugs, product.Slug)
+	}
+	if want := []string{"trail-runner", "trail-runner-2", "trail-runner-3"}; strings.Join(slugs, " ") != strings.Join(want, " ") {
+		t.Errorf("slugs = %v, want %v", slugs, want)
+	}
+
+	req := validProductRequest()
+	req.StoreID = "s2"
+	product, err := svc.CreateProduct(ctx, sellerID, req)
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if product.Slug != "trail-runner" {
+		t.Errorf("slug in another store = %s, want trail-runner", product.Slug)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Trail Runner", want: "trail-runner"},
+		{name: "  Café & Co. -- 2024!  ", want: "caf-co-2024"},
+		{name: "!!!", want: "fallback"},
+		{name: strings.Repeat("ab-", 50), want: strings.TrimRight(strings.Repeat("ab-", 34)[:100], "-")},
+	}
+
+	for _, tt := range tests {
+		if got := slugify(tt.name, "fallback"); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPublishProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		edit       func(req *dto.CreateProductRequest)
+		deactivate bool
+		wantErr    error
+	}{
+		{name: "publishable", edit: func(req *dto.CreateProductRequest) {}},
+		{name: "without a category", edit: func(req *dto.CreateProductRequest) {
+			req.CategoryID = ""
+		}, wantErr: ErrNotPublishable},
+		{name: "without variants", edit: func(req *dto.CreateProductRequest) {
+			req.Variants = nil
+		}, wantErr: ErrNotPublishable},
+		{name: "without an active variant", edit: func(req *dto.CreateProductRequest) {}, deactivate: true, wantErr: ErrNotPublishable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestProductService()
+			ctx := context.Background()
+			req := validProductRequest()
+			tt.edit(req)
+
+			product, err := svc.CreateProduct(ctx, sellerID, req)
+			if err != nil {
+				t.Fatalf("CreateProduct() error = %v", err)
+			}
+			if tt.deactivate {
+				repo.products[product.ID].Variants[0].IsActive = false
+			}
+
+			if _, err := svc.PublishProduct(ctx, otherSellerID, product.ID); !errors.Is(err, repository.ErrProductNotFound) {
+				t.Errorf("PublishProduct() by another seller error = %v, want %v", err, repository.ErrProductNotFound)
+			}
+
+			published, err := svc.PublishProduct(ctx, sellerID, product.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PublishProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (published.Status != string(model.ProductStatusPublished) || published.PublishedAt == "") {
+				t.Errorf("PublishProduct() = status %s published at %q", published.Status, published.PublishedAt)
+			}
+		})
+	}
+}
+
+func TestPublishedProductStaysPublishable(t *testing.T) {
+	svc, _ := newTestProductService()
+	ctx := context.Background()
+
+	product, err := svc.CreateProduct(ctx, sellerID, validProductRequest())
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if _, err := svc.PublishProduct(ctx, sellerID, product.ID); err != nil {
+		t.Fatalf("PublishProduct() error = %v", err)
+	}
+	variantID := product.Variants[0].ID
+
+	inactive := false
+	if _, err := svc.UpdateVariant(ctx, sellerID, product.ID, variantID, &dto.UpdateVariantRequest{IsActive: &inactive}); !errors.Is(err, ErrNotPublishable) {
+		t.Errorf("UpdateVariant() deactivating the last variant error = %v, want %v", err, ErrNotPublishable)
+	}
+	if _, err := svc.DeleteVariant(ctx, sellerID, product.ID, variantID); !errors.Is(err, ErrNotPublishable) {
+		t.Errorf("DeleteVariant() of the last variant error = %v, want %v", err, ErrNotPublishable)
+	}
+	noCategory := ""
+	if _, err := svc.UpdateProduct(ctx, sellerID, product.ID, &dto.UpdateProductRequest{CategoryID: &noCategory}); !errors.Is(err, ErrNotPublishable) {
+		t.Errorf("UpdateProduct() removing the category error = %v, want %v", err, ErrNotPublishable)
+	}
+
+	// Renaming a published product keeps its slug so links keep working.
+	name := "Trail Runner II"
+	renamed, err := svc.UpdateProduct(ctx, sellerID, product.ID, &dto.UpdateProductRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if renamed.Slug != product.Slug {
+		t.Errorf("slug after renaming a published product = %s, want %s", renamed.Slug, product.Slug)
+	}
+}
+
+func TestGetProductVisibility(t *testing.T) {
+	svc, _ := newTestProductService()
+	ctx := context.Background()
+
+	product, err := svc.CreateProduct(ctx, sellerID, validProductRequest())
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+
+	if _, err := svc.GetProduct(ctx, product.ID, sellerID); err != nil {
+		t.Errorf("GetProduct() of a draft by its owner error = %v", err)
+	}
+	for _, viewer := range []int64{0, otherSellerID} {
+		if _, err := svc.GetProduct(ctx, product.ID, viewer); !errors.Is(err, repository.ErrProductNotFound) {
+			t.Errorf("GetProduct() of a draft by viewer %d error = %v, want %v", viewer, err, repository.ErrProductNotFound)
+		}
+	}
+
+	if _, err := svc.PublishProduct(ctx, sellerID, product.ID); err != nil {
+		t.Fatalf("PublishProduct() error = %v", err)
+	}
+	if _, err := svc.GetProduct(ctx, product.ID, 0); err != nil {
+		t.Errorf("GetProduct() of a published product error = %v", err)
+	}
+}
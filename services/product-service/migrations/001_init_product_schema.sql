@@ -0,0 +1,48 @@
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES categories(id),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(120) NOT NULL,
+    path TEXT UNIQUE NOT NULL, -- 'fashion/men/shirts'
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE products (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seller_id BIGINT NOT NULL,
+    store_id UUID NOT NULL,
+    category_id UUID REFERENCES categories(id),
+    name VARCHAR(200) NOT NULL,
+    slug VARCHAR(220) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- 'draft', 'published'
+    published_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID REFERENCES products(id),
+    sku VARCHAR(64) NOT NULL,
+    size VARCHAR(20),
+    color VARCHAR(30),
+    price BIGINT NOT NULL CHECK (price >= 0), -- minor units
+    weight_grams INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    deleted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+CREATE INDEX idx_products_seller ON products(seller_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_category ON products(category_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_status ON products(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_product_variants_product ON product_variants(product_id);
+
+-- SKUs stay unique among live variants, soft-deleted ones free their SKU
+CREATE UNIQUE INDEX idx_product_variants_sku ON product_variants(sku) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_products_store_slug ON products(store_id, slug) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Products are taxed by the tax category of their category. Categories
+-- without a specific treatment use the standard rate.
+ALTER TABLE categories ADD COLUMN tax_category VARCHAR(30) NOT NULL DEFAULT 'standard';
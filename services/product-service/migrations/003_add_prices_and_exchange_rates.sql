@@ -0,0 +1,21 @@
+-- Prices a seller sets for currencies other than the base currency. A
+-- variant without one for a currency is converted from its base price.
+CREATE TABLE variant_prices (
+    variant_id UUID NOT NULL REFERENCES product_variants(id),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0), -- minor units of currency
+    PRIMARY KEY (variant_id, currency)
+);
+
+-- Exchange rates are append-only: the latest row per currency is the one in
+-- effect, older rows stay for orders that were priced with them.
+CREATE TABLE exchange_rates (
+    id BIGSERIAL PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0), -- units of currency per unit of base_currency
+    source VARCHAR(50) NOT NULL, -- 'file' or 'admin'
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_exchange_rates_current ON exchange_rates(base_currency, currency, id DESC);
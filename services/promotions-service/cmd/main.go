@@ -0,0 +1,56 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/promotions-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("promotions-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	promotionRepo := repository.NewPromotionRepository(db)
+	redemptionRepo := repository.NewRedemptionRepository(db)
+
+	promotionService := service.NewPromotionService(promotionRepo)
+	pricingService := service.NewPricingService(
+		promotionRepo,
+		redemptionRepo,
+		client.NewProductCatalog(cfg.ProductServiceName, srv.Client()),
+	)
+
+	promotionsHandler := handler.NewPromotionsHandler(promotionService, pricingService)
+
+	if err := pb.RegisterPromotionsServiceHandler(srv.Server(), promotionsHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
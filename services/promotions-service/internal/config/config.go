@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Registry name of the product service, used to find the product,
+	// category and seller of the SKUs being priced.
+	ProductServiceName string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:               getEnv("PORT", "8092"),
+		Environment:        os.Getenv("ENVIRONMENT"),
+		DatabaseURL:        os.Getenv("DATABASE_URL"),
+		ProductServiceName: getEnv("PRODUCT_SERVICE_NAME", "product-service"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,138 @@
+package dto
+
+type PromotionTerms struct {
+	Name             string   `json:"name"`
+	Value            int64    `json:"value"`
+	MaxDiscount      int64    `json:"max_discount"`
+	MinSubtotal      int64    `json:"min_subtotal"`
+	StartsAt         string   `json:"starts_at"`
+	EndsAt           string   `json:"ends_at"`
+	UsageLimit       int      `json:"usage_limit"`
+	PerCustomerLimit int      `json:"per_customer_limit"`
+	ProductIDs       []string `json:"product_ids"`
+	CategoryIDs      []string `json:"category_ids"`
+	SellerIDs        []int64  `json:"seller_ids"`
+	Stackable        bool     `json:"stackable"`
+	Priority         int      `json:"priority"`
+}
+
+type CreatePromotionRequest struct {
+	Code   string         `json:"code"`
+	Type   string         `json:"type"`
+	Terms  PromotionTerms `json:"terms"`
+	Active bool           `json:"active"`
+}
+
+type UpdatePromotionRequest struct {
+	ID    string         `json:"id"`
+	Terms PromotionTerms `json:"terms"`
+}
+
+type ListPromotionsRequest struct {
+	Code     string `json:"code"`
+	State    string `json:"state"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type PromotionResponse struct {
+	ID               string   `json:"id"`
+	Code             string   `json:"code,omitempty"`
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	Value            int64    `json:"value"`
+	MaxDiscount      int64    `json:"max_discount"`
+	MinSubtotal      int64    `json:"min_subtotal"`
+	StartsAt         string   `json:"starts_at"`
+	EndsAt           string   `json:"ends_at,omitempty"`
+	UsageLimit       int      `json:"usage_limit"`
+	PerCustomerLimit int      `json:"per_customer_limit"`
+	UsageCount       int      `json:"usage_count"`
+	ProductIDs       []string `json:"product_ids"`
+	CategoryIDs      []string `json:"category_ids"`
+	SellerIDs        []int64  `json:"seller_ids"`
+	Stackable        bool     `json:"stackable"`
+	Priority         int      `json:"priority"`
+	Active           bool     `json:"active"`
+	CreatedAt        string   `json:"created_at"`
+	UpdatedAt        string   `json:"updated_at"`
+}
+
+type PricingLine struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+}
+
+type ExchangeRate struct {
+	BaseCurrency string `json:"base_currency"`
+	Currency     string `json:"currency"`
+	Rate         string `json:"rate"`
+}
+
+type EvaluatePricingRequest struct {
+	CustomerID    int64         `json:"customer_id"`
+	Codes         []string      `json:"codes"`
+	Lines         []PricingLine `json:"lines"`
+	ShippingTotal int64         `json:"shipping_total"`
+	ExchangeRate  *ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type RedeemPromotionsRequest struct {
+	Reference     string        `json:"reference"`
+	CustomerID    int64         `json:"customer_id"`
+	PromotionIDs  []string      `json:"promotion_ids"`
+	Lines         []PricingLine `json:"lines"`
+	ShippingTotal int64         `json:"shipping_total"`
+	DiscountTotal int64         `json:"discount_total"`
+	ExchangeRate  *ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type DiscountAllocationResponse struct {
+	PromotionID string `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	Amount      int64  `json:"amount"`
+}
+
+type LineDiscountResponse struct {
+	SKU         string                        `json:"sku"`
+	LineTotal   int64                         `json:"line_total"`
+	Discount    int64                         `json:"discount"`
+	Allocations []*DiscountAllocationResponse `json:"allocations"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID      string `json:"promotion_id"`
+	Code             string `json:"code,omitempty"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	Discount         int64  `json:"discount"`
+	ShippingDiscount int64  `json:"shipping_discount"`
+}
+
+type RejectedCodeResponse struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+type PricingResponse struct {
+	Subtotal         int64                       `json:"subtotal"`
+	DiscountTotal    int64                       `json:"discount_total"`
+	ShippingTotal    int64                       `json:"shipping_total"`
+	ShippingDiscount int64                       `json:"shipping_discount"`
+	Total            int64                       `json:"total"`
+	Lines            []*LineDiscountResponse     `json:"lines"`
+	Applied          []*AppliedPromotionResponse `json:"applied"`
+	Rejected         []*RejectedCodeResponse     `json:"rejected"`
+}
+
+type RedemptionResponse struct {
+	ID          string `json:"id"`
+	Reference   string `json:"reference"`
+	PromotionID string `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	CustomerID  int64  `json:"customer_id"`
+	Amount      int64  `json:"amount"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/promotions-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "promotions-service"
+
+type PromotionsHandler struct {
+	promotionService service.PromotionService
+	pricingService   service.PricingService
+}
+
+func NewPromotionsHandler(promotionService service.PromotionService, pricingService service.PricingService) *PromotionsHandler {
+	return &PromotionsHandler{
+		promotionService: promotionService,
+		pricingService:   pricingService,
+	}
+}
+
+func (h *PromotionsHandler) CreatePromotion(ctx context.Context, req *pb.CreatePromotionRequest, rsp *pb.PromotionResponse) error {
+	promotion, err := h.promotionService.CreatePromotion(ctx, &dto.CreatePromotionRequest{
+		Code:   req.Code,
+		Type:   req.Type,
+		Terms:  fromProtoTerms(req.Terms),
+		Active: req.Active,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Promotion = toProtoPromotion(promotion)
+	return nil
+}
+
+func (h *PromotionsHandler) UpdatePromotion(ctx context.Context, req *pb.UpdatePromotionRequest, rsp *pb.PromotionResponse) error {
+	promotion, err := h.promotionService.UpdatePromotion(ctx, &dto.UpdatePromotionRequest{
+		ID:    req.Id,
+		Terms: fromProtoTerms(req.Terms),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Promotion = toProtoPromotion(promotion)
+	return nil
+}
+
+func (h *PromotionsHandler) SetPromotionActive(ctx context.Context, req *pb.SetPromotionActiveRequest, rsp *pb.PromotionResponse) error {
+	promotion, err := h.promotionService.SetPromotionActive(ctx, req.Id, req.Active)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Promotion = toProtoPromotion(promotion)
+	return nil
+}
+
+func (h *PromotionsHandler) GetPromotion(ctx context.Context, req *pb.GetPromotionRequest, rsp *pb.PromotionResponse) error {
+	promotion, err := h.promotionService.GetPromotion(ctx, req.Id)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Promotion = toProtoPromotion(promotion)
+	return nil
+}
+
+func (h *PromotionsHandler) ListPromotions(ctx context.Context, req *pb.ListPromotionsRequest, rsp *pb.ListPromotionsResponse) error {
+	promotions, total, err := h.promotionService.ListPromotions(ctx, &dto.ListPromotionsRequest{
+		Code:     req.Code,
+		State:    req.State,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, promotion := range promotions {
+		rsp.Promotions = append(rsp.Promotions, toProtoPromotion(promotion))
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *PromotionsHandler) EvaluatePricing(ctx context.Context, req *pb.EvaluatePricingRequest, rsp *pb.PricingResponse) error {
+	pricing, err := h.pricingService.EvaluatePricing(ctx, &dto.EvaluatePricingRequest{
+		CustomerID:    req.CustomerId,
+		Codes:         req.Codes,
+		Lines:         fromProtoLines(req.Lines),
+		ShippingTotal: req.ShippingTotal,
+		ExchangeRate:  fromProtoExchangeRate(req.ExchangeRate),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Pricing = toProtoPricing(pricing)
+	return nil
+}
+
+func (h *PromotionsHandler) RedeemPromotions(ctx context.Context, req *pb.RedeemPromotionsRequest, rsp *pb.RedemptionResponse) error {
+	redemptions, err := h.pricingService.RedeemPromotions(ctx, &dto.RedeemPromotionsRequest{
+		Reference:     req.Reference,
+		CustomerID:    req.CustomerId,
+		PromotionIDs:  req.PromotionIds,
+		Lines:         fromProtoLines(req.Lines),
+		ShippingTotal: req.ShippingTotal,
+		DiscountTotal: req.DiscountTotal,
+		ExchangeRate:  fromProtoExchangeRate(req.ExchangeRate),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reference = req.Reference
+	rsp.Redemptions = toProtoRedemptions(redemptions)
+	return nil
+}
+
+func (h *PromotionsHandler) ReleasePromotions(ctx context.Context, req *pb.ReleasePromotionsRequest, rsp *pb.RedemptionResponse) error {
+	redemptions, err := h.pricingService.ReleasePromotions(ctx, req.Reference)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Reference = req.Reference
+	rsp.Redemptions = toProtoRedemptions(redemptions)
+	return nil
+}
+
+func fromProtoExchangeRate(rate *pb.ExchangeRate) *dto.ExchangeRate {
+	if rate == nil {
+		return nil
+	}
+	return &dto.ExchangeRate{
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+	}
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrPromotionNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrDuplicateCode),
+		errors.Is(err, repository.ErrUsageLimitReached),
+		errors.Is(err, repository.ErrCustomerLimitReached),
+		errors.Is(err, service.ErrPricingChanged):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func fromProtoTerms(terms *pb.PromotionInput) dto.PromotionTerms {
+	if terms == nil {
+		return dto.PromotionTerms{}
+	}
+
+	return dto.PromotionTerms{
+		Name:             terms.Name,
+		Value:            terms.Value,
+		MaxDiscount:      terms.MaxDiscount,
+		MinSubtotal:      terms.MinSubtotal,
+		StartsAt:         terms.StartsAt,
+		EndsAt:           terms.EndsAt,
+		UsageLimit:       int(terms.UsageLimit),
+		PerCustomerLimit: int(terms.PerCustomerLimit),
+		ProductIDs:       terms.ProductIds,
+		CategoryIDs:      terms.CategoryIds,
+		SellerIDs:        terms.SellerIds,
+		Stackable:        terms.Stackable,
+		Priority:         int(terms.Priority),
+	}
+}
+
+func fromProtoLines(lines []*pb.PricingLine) []dto.PricingLine {
+	result := make([]dto.PricingLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, dto.PricingLine{
+			SKU:       line.Sku,
+			Quantity:  int(line.Quantity),
+			UnitPrice: line.UnitPrice,
+		})
+	}
+	return result
+}
+
+func toProtoPromotion(promotion *dto.PromotionResponse) *pb.Promotion {
+	return &pb.Promotion{
+		Id:               promotion.ID,
+		Code:             promotion.Code,
+		Name:             promotion.Name,
+		Type:             promotion.Type,
+		Value:            promotion.Value,
+		MaxDiscount:      promotion.MaxDiscount,
+		MinSubtotal:      promotion.MinSubtotal,
+		StartsAt:         promotion.StartsAt,
+		EndsAt:           promotion.EndsAt,
+		UsageLimit:       int32(promotion.UsageLimit),
+		PerCustomerLimit: int32(promotion.PerCustomerLimit),
+		UsageCount:       int32(promotion.UsageCount),
+		ProductIds:       promotion.ProductIDs,
+		CategoryIds:      promotion.CategoryIDs,
+		SellerIds:        promotion.SellerIDs,
+		Stackable:        promotion.Stackable,
+		Priority:         int32(promotion.Priority),
+		Active:           promotion.Active,
+		CreatedAt:        promotion.CreatedAt,
+		UpdatedAt:        promotion.UpdatedAt,
+	}
+}
+
+func toProtoPricing(pricing *dto.PricingResponse) *pb.Pricing {
+	resp := &pb.Pricing{
+		Subtotal:         pricing.Subtotal,
+		DiscountTotal:    pricing.DiscountTotal,
+		ShippingTotal:    pricing.ShippingTotal,
+		ShippingDiscount: pricing.ShippingDiscount,
+		Total:            pricing.Total,
+	}
+
+	for _, line := range pricing.Lines {
+		protoLine := &pb.LineDiscount{
+			Sku:       line.SKU,
+			LineTotal: line.LineTotal,
+			Discount:  line.Discount,
+		}
+		for _, allocation := range line.Allocations {
+			protoLine.Allocations = append(protoLine.Allocations, &pb.DiscountAllocation{
+				PromotionId: allocation.PromotionID,
+				Code:        allocation.Code,
+				Amount:      allocation.Amount,
+			})
+		}
+		resp.Lines = append(resp.Lines, protoLine)
+	}
+
+	for _, applied := range pricing.Applied {
+		resp.Applied = append(resp.Applied, &pb.AppliedPromotion{
+			PromotionId:      applied.PromotionID,
+			Code:             applied.Code,
+			Name:             applied.Name,
+			Type:             applied.Type,
+			Discount:         applied.Discount,
+			ShippingDiscount: applied.ShippingDiscount,
+		})
+	}
+
+	for _, rejected := range pricing.Rejected {
+		resp.Rejected = append(resp.Rejected, &pb.RejectedCode{
+			Code:   rejected.Code,
+			Reason: rejected.Reason,
+		})
+	}
+
+	return resp
+}
+
+func toProtoRedemptions(redemptions []*dto.RedemptionResponse) []*pb.Redemption {
+	resp := make([]*pb.Redemption, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		resp = append(resp, &pb.Redemption{
+			Id:          redemption.ID,
+			Reference:   redemption.Reference,
+			PromotionId: redemption.PromotionID,
+			Code:        redemption.Code,
+			CustomerId:  redemption.CustomerID,
+			Amount:      redemption.Amount,
+			Status:      redemption.Status,
+			CreatedAt:   redemption.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidRate = errors.New("exchange rate must be a positive decimal")
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit. Every other currency has two decimals.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+func currencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// ExchangeRate is what one unit of BaseCurrency is worth in Currency, as
+// the product service priced the lines with. Promotion amounts are kept in
+// the base currency.
+type ExchangeRate struct {
+	BaseCurrency string
+	Currency     string
+	Rate         string
+}
+
+// IsIdentity reports whether no conversion is needed.
+func (r *ExchangeRate) IsIdentity() bool {
+	return r == nil || r.Currency == "" || r.Currency == r.BaseCurrency
+}
+
+// Convert turns an amount of the base currency into the rate's currency,
+// rounding half away from zero to the nearest minor unit.
+func (r *ExchangeRate) Convert(amount int64) (int64, error) {
+	if r.IsIdentity() {
+		return amount, nil
+	}
+
+	rate, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || rate.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, r.Rate)
+	}
+
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	exponent := currencyExponent(r.Currency) - currencyExponent(r.BaseCurrency)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil)
+	if exponent < 0 {
+		value.Quo(value, new(big.Rat).SetInt(scale))
+	} else {
+		value.Mul(value, new(big.Rat).SetInt(scale))
+	}
+
+	num := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	if !quotient.IsInt64() {
+		return 0, errors.New("converted amount overflows")
+	}
+	return quotient.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    *ExchangeRate
+		amount  int64
+		want    int64
+		wantErr error
+	}{
+		{name: "no rate", rate: nil, amount: 1234, want: 1234},
+		{name: "no currency", rate: &ExchangeRate{BaseCurrency: "USD"}, amount: 1234, want: 1234},
+		{name: "same currency", rate: &ExchangeRate{BaseCurrency: "USD", Currency: "USD", Rate: "bogus"}, amount: 1234, want: 1234},
+		{name: "same exponent", rate: &ExchangeRate{BaseCurrency: "IDR", Currency: "USD", Rate: "0.000061"}, amount: 100000000, want: 6100},
+		{name: "to a currency without decimals", rate: &ExchangeRate{BaseCurrency: "IDR", Currency: "JPY", Rate: "0.0093"}, amount: 100000000, want: 9300},
+		{name: "to a currency with three decimals", rate: &ExchangeRate{BaseCurrency: "USD", Currency: "KWD", Rate: "0.307"}, amount: 100, want: 307},
+		{name: "half rounds away from zero", rate: &ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "1.5"}, amount: -1, want: -2},
+		{name: "zero rate", rate: &ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0"}, amount: 100, wantErr: ErrInvalidRate},
+		{name: "overflow", rate: &ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "2"}, amount: math.MaxInt64, wantErr: errors.New("overflow")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.Convert(tt.amount)
+			if (err != nil) != (tt.wantErr != nil) || (errors.Is(tt.wantErr, ErrInvalidRate) && !errors.Is(err, ErrInvalidRate)) {
+				t.Fatalf("Convert(%d) error = %v, want %v", tt.amount, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionInCurrency(t *testing.T) {
+	rate := &ExchangeRate{BaseCurrency: "USD", Currency: "JPY", Rate: "150"}
+
+	fixed := &Promotion{Type: PromotionFixed, Value: 500, MaxDiscount: 0, MinSubtotal: 2000}
+	got, err := fixed.InCurrency(rate)
+	if err != nil {
+		t.Fatalf("InCurrency() error = %v", err)
+	}
+	if got.Value != 750 || got.MaxDiscount != 0 || got.MinSubtotal != 3000 {
+		t.Errorf("InCurrency() = value %d, max %d, min %d, want 750, 0, 3000", got.Value, got.MaxDiscount, got.MinSubtotal)
+	}
+	if fixed.Value != 500 {
+		t.Errorf("InCurrency() changed the promotion it was given")
+	}
+
+	percentage := &Promotion{Type: PromotionPercentage, Value: 10, MaxDiscount: 1000}
+	got, err = percentage.InCurrency(rate)
+	if err != nil {
+		t.Fatalf("InCurrency() error = %v", err)
+	}
+	if got.Value != 10 || got.MaxDiscount != 1500 {
+		t.Errorf("InCurrency() = value %d, max %d, want 10, 1500", got.Value, got.MaxDiscount)
+	}
+
+	if got, _ := percentage.InCurrency(&ExchangeRate{BaseCurrency: "USD", Currency: "USD"}); got != percentage {
+		t.Errorf("InCurrency() with the base currency copied the promotion")
+	}
+
+	if _, err := fixed.InCurrency(&ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "-1"}); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("InCurrency() error = %v, want %v", err, ErrInvalidRate)
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+// Reasons a coupon code was not applied.
+const (
+	RejectUnknownCode       = "unknown_code"
+	RejectInactive          = "inactive"
+	RejectNotStarted        = "not_started"
+	RejectExpired           = "expired"
+	RejectUsageLimit        = "usage_limit_reached"
+	RejectCustomerLimit     = "customer_limit_reached"
+	RejectSignInRequired    = "sign_in_required"
+	RejectMinimumNotMet     = "minimum_not_met"
+	RejectNoQualifyingItems = "no_qualifying_items"
+	RejectNotStackable      = "not_stackable"
+)
+
+// PricingLine is a cart line with what the product service says about its
+// SKU. SKUs the catalog does not know keep empty targeting fields and only
+// qualify for untargeted promotions.
+type PricingLine struct {
+	SKU        string
+	ProductID  string
+	CategoryID string
+	SellerID   int64
+	Quantity   int
+	UnitPrice  int64
+}
+
+func (l *PricingLine) Total() int64 {
+	return l.UnitPrice * int64(l.Quantity)
+}
+
+// Allocation is the part of a promotion's discount taken off one line.
+type Allocation struct {
+	PromotionID string
+	Code        string
+	Amount      int64
+}
+
+type LineDiscount struct {
+	SKU         string
+	LineTotal   int64
+	Discount    int64
+	Allocations []Allocation
+}
+
+type AppliedPromotion struct {
+	PromotionID      string
+	Code             string
+	Name             string
+	Type             PromotionType
+	Discount         int64
+	ShippingDiscount int64
+}
+
+type RejectedCode struct {
+	Code   string
+	Reason string
+}
+
+// Pricing is the result of applying promotions to a set of lines.
+type Pricing struct {
+	Subtotal         int64
+	DiscountTotal    int64
+	ShippingTotal    int64
+	ShippingDiscount int64
+	Lines            []*LineDiscount
+	Applied          []*AppliedPromotion
+	Rejected         []RejectedCode
+}
+
+func (p *Pricing) Total() int64 {
+	return p.Subtotal - p.DiscountTotal + p.ShippingTotal - p.ShippingDiscount
+}
+
+// Granted is everything the promotions took off, lines and shipping.
+func (p *Pricing) Granted() int64 {
+	return p.DiscountTotal + p.ShippingDiscount
+}
+
+// SKUDetails is what the product service says about a SKU that matters for
+// targeting.
+type SKUDetails struct {
+	SKU        string
+	ProductID  string
+	CategoryID string
+	SellerID   int64
+}
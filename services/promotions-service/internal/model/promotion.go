@@ -0,0 +1,135 @@
+package model
+
+import "time"
+
+type PromotionType string
+
+const (
+	// PromotionPercentage takes Value percent off the qualifying lines.
+	PromotionPercentage PromotionType = "percentage"
+	// PromotionFixed takes Value minor units off the qualifying lines.
+	PromotionFixed PromotionType = "fixed"
+	// PromotionFreeShipping waives the shipping charge.
+	PromotionFreeShipping PromotionType = "free_shipping"
+)
+
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case PromotionPercentage, PromotionFixed, PromotionFreeShipping:
+		return true
+	}
+	return false
+}
+
+type Promotion struct {
+	ID               string        `db:"id"`
+	Code             string        `db:"code"`
+	Name             string        `db:"name"`
+	Type             PromotionType `db:"type"`
+	Value            int64         `db:"value"`
+	MaxDiscount      int64         `db:"max_discount"`
+	MinSubtotal      int64         `db:"min_subtotal"`
+	StartsAt         time.Time     `db:"starts_at"`
+	EndsAt           *time.Time    `db:"ends_at"`
+	UsageLimit       int           `db:"usage_limit"`
+	PerCustomerLimit int           `db:"per_customer_limit"`
+	UsageCount       int           `db:"usage_count"`
+	ProductIDs       []string      `db:"product_ids"`
+	CategoryIDs      []string      `db:"category_ids"`
+	SellerIDs        []int64       `db:"seller_ids"`
+	Stackable        bool          `db:"stackable"`
+	Priority         int           `db:"priority"`
+	Active           bool          `db:"active"`
+	CreatedAt        time.Time     `db:"created_at"`
+	UpdatedAt        time.Time     `db:"updated_at"`
+}
+
+// InCurrency returns a copy of the promotion with its amounts, which are
+// kept in the base currency, converted with rate. Percentages stay as
+// they are.
+func (p *Promotion) InCurrency(rate *ExchangeRate) (*Promotion, error) {
+	if rate.IsIdentity() {
+		return p, nil
+	}
+
+	converted := *p
+	var err error
+	if p.Type == PromotionFixed {
+		if converted.Value, err = rate.Convert(p.Value); err != nil {
+			return nil, err
+		}
+	}
+	if converted.MaxDiscount, err = rate.Convert(p.MaxDiscount); err != nil {
+		return nil, err
+	}
+	if converted.MinSubtotal, err = rate.Convert(p.MinSubtotal); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// IsAutomatic reports whether the promotion applies without a code.
+func (p *Promotion) IsAutomatic() bool {
+	return p.Code == ""
+}
+
+// IsExhausted reports whether every use the promotion allows is taken.
+func (p *Promotion) IsExhausted() bool {
+	return p.UsageLimit > 0 && p.UsageCount >= p.UsageLimit
+}
+
+// Targets reports whether a line qualifies for the promotion. A line must
+// match every target list that is not empty.
+func (p *Promotion) Targets(line *PricingLine) bool {
+	if len(p.ProductIDs) > 0 && !containsString(p.ProductIDs, line.ProductID) {
+		return false
+	}
+	if len(p.CategoryIDs) > 0 && !containsString(p.CategoryIDs, line.CategoryID) {
+		return false
+	}
+	if len(p.SellerIDs) > 0 {
+		found := false
+		for _, id := range p.SellerIDs {
+			if id == line.SellerID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type RedemptionStatus string
+
+const (
+	RedemptionRedeemed RedemptionStatus = "redeemed"
+	RedemptionReleased RedemptionStatus = "released"
+)
+
+// Redemption is one use of a promotion by a checkout.
+type Redemption struct {
+	ID          string           `db:"id"`
+	Reference   string           `db:"reference"`
+	PromotionID string           `db:"promotion_id"`
+	Code        string           `db:"code"`
+	CustomerID  int64            `db:"customer_id"`
+	Amount      int64            `db:"amount"`
+	Status      RedemptionStatus `db:"status"`
+	CreatedAt   time.Time        `db:"created_at"`
+}
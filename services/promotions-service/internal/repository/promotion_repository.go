@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+)
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrDuplicateCode     = errors.New("a promotion with this code already exists")
+)
+
+type PromotionFilter struct {
+	Code   string
+	Active *bool
+	Limit  int
+	Offset int
+}
+
+// PromotionRepository stores promotions and answers the lookups pricing
+// needs.
+type PromotionRepository interface {
+	// Create fails with ErrDuplicateCode if the code is taken.
+	Create(ctx context.Context, promotion *model.Promotion) error
+	// UpdateTerms saves everything but the code, type, active flag and
+	// usage count.
+	UpdateTerms(ctx context.Context, promotion *model.Promotion) error
+	SetActive(ctx context.Context, id string, active bool) (*model.Promotion, error)
+	GetByID(ctx context.Context, id string) (*model.Promotion, error)
+	List(ctx context.Context, filter PromotionFilter) ([]*model.Promotion, int, error)
+
+	// ListByCodes and ListByIDs return the promotions found, in no
+	// particular order, whatever their state.
+	ListByCodes(ctx context.Context, codes []string) ([]*model.Promotion, error)
+	ListByIDs(ctx context.Context, ids []string) ([]*model.Promotion, error)
+	// ListAutomatic returns the active promotions without a code that are
+	// running at now.
+	ListAutomatic(ctx context.Context, now time.Time) ([]*model.Promotion, error)
+
+	// CustomerUsage counts the redemptions the customer holds of each of
+	// the promotions.
+	CustomerUsage(ctx context.Context, customerID int64, promotionIDs []string) (map[string]int, error)
+}
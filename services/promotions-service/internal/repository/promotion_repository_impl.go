@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const promotionColumns = `
+	id, COALESCE(code, ''), name, type, value, max_discount, min_subtotal, starts_at, ends_at,
+	usage_limit, per_customer_limit, usage_count, product_ids, category_ids, seller_ids,
+	stackable, priority, active, created_at, updated_at
+`
+
+type promotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(row rowScanner) (*model.Promotion, error) {
+	var promotion model.Promotion
+	var endsAt sql.NullTime
+	err := row.Scan(
+		&promotion.ID,
+		&promotion.Code,
+		&promotion.Name,
+		&promotion.Type,
+		&promotion.Value,
+		&promotion.MaxDiscount,
+		&promotion.MinSubtotal,
+		&promotion.StartsAt,
+		&endsAt,
+		&promotion.UsageLimit,
+		&promotion.PerCustomerLimit,
+		&promotion.UsageCount,
+		pq.Array(&promotion.ProductIDs),
+		pq.Array(&promotion.CategoryIDs),
+		pq.Array(&promotion.SellerIDs),
+		&promotion.Stackable,
+		&promotion.Priority,
+		&promotion.Active,
+		&promotion.CreatedAt,
+		&promotion.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+
+	return &promotion, nil
+}
+
+func (r *promotionRepository) Create(ctx context.Context, promotion *model.Promotion) error {
+	query := `
+		INSERT INTO promotions (
+			code, name, type, value, max_discount, min_subtotal, starts_at, ends_at,
+			usage_limit, per_customer_limit, product_ids, category_ids, seller_ids,
+			stackable, priority, active
+		)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		promotion.Code,
+		promotion.Name,
+		promotion.Type,
+		promotion.Value,
+		promotion.MaxDiscount,
+		promotion.MinSubtotal,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.UsageLimit,
+		promotion.PerCustomerLimit,
+		pq.Array(promotion.ProductIDs),
+		pq.Array(promotion.CategoryIDs),
+		pq.Array(promotion.SellerIDs),
+		promotion.Stackable,
+		promotion.Priority,
+		promotion.Active,
+	).Scan(&promotion.ID, &promotion.CreatedAt, &promotion.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *promotionRepository) UpdateTerms(ctx context.Context, promotion *model.Promotion) error {
+	query := `
+		UPDATE promotions SET
+			name = $2, value = $3, max_discount = $4, min_subtotal = $5, starts_at = $6, ends_at = $7,
+			usage_limit = $8, per_customer_limit = $9, product_ids = $10, category_ids = $11,
+			seller_ids = $12, stackable = $13, priority = $14, updated_at = NOW()
+		WHERE id::text = $1
+		RETURNING ` + promotionColumns
+
+	updated, err := scanPromotion(r.db.QueryRowContext(ctx, query,
+		promotion.ID,
+		promotion.Name,
+		promotion.Value,
+		promotion.MaxDiscount,
+		promotion.MinSubtotal,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.UsageLimit,
+		promotion.PerCustomerLimit,
+		pq.Array(promotion.ProductIDs),
+		pq.Array(promotion.CategoryIDs),
+		pq.Array(promotion.SellerIDs),
+		promotion.Stackable,
+		promotion.Priority,
+	))
+	if err == sql.ErrNoRows {
+		return ErrPromotionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	*promotion = *updated
+	return nil
+}
+
+func (r *promotionRepository) SetActive(ctx context.Context, id string, active bool) (*model.Promotion, error) {
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, `
+		UPDATE promotions SET active = $2, updated_at = NOW()
+		WHERE id::text = $1
+		RETURNING `+promotionColumns,
+		id, active,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrPromotionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *promotionRepository) GetByID(ctx context.Context, id string) (*model.Promotion, error) {
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx,
+		`SELECT `+promotionColumns+` FROM promotions WHERE id::text = $1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrPromotionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *promotionRepository) List(ctx context.Context, filter PromotionFilter) ([]*model.Promotion, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if filter.Code != "" {
+		addCondition("code", filter.Code)
+	}
+	if filter.Active != nil {
+		addCondition("active", *filter.Active)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM promotions `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM promotions %s ORDER BY created_at DESC, id ASC LIMIT $%d OFFSET $%d`,
+		promotionColumns, where, len(args)-1, len(args))
+
+	promotions, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return promotions, total, nil
+}
+
+func (r *promotionRepository) ListByCodes(ctx context.Context, codes []string) ([]*model.Promotion, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	return r.query(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE code = ANY($1)`, pq.Array(codes))
+}
+
+func (r *promotionRepository) ListByIDs(ctx context.Context, ids []string) ([]*model.Promotion, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.query(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id::text = ANY($1)`, pq.Array(ids))
+}
+
+func (r *promotionRepository) ListAutomatic(ctx context.Context, now time.Time) ([]*model.Promotion, error) {
+	return r.query(ctx, `
+		SELECT `+promotionColumns+` FROM promotions
+		WHERE code IS NULL AND active AND starts_at <= $1 AND (ends_at IS NULL OR ends_at > $1)
+	`, now)
+}
+
+func (r *promotionRepository) CustomerUsage(ctx context.Context, customerID int64, promotionIDs []string) (map[string]int, error) {
+	usage := make(map[string]int, len(promotionIDs))
+	if customerID == 0 || len(promotionIDs) == 0 {
+		return usage, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT promotion_id, COUNT(*)
+		FROM redemptions
+		WHERE customer_id = $1 AND promotion_id::text = ANY($2) AND status = $3
+		GROUP BY promotion_id
+	`, customerID, pq.Array(promotionIDs), model.RedemptionRedeemed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		usage[id] = count
+	}
+
+	return usage, rows.Err()
+}
+
+func (r *promotionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*model.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []*model.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+)
+
+var (
+	ErrUsageLimitReached    = errors.New("promotion has no uses left")
+	ErrCustomerLimitReached = errors.New("customer has used this promotion as often as allowed")
+	ErrDuplicateRedemption  = errors.New("reference was already redeemed")
+)
+
+// RedemptionRepository records promotion uses and keeps the usage count of
+// each promotion in step with them.
+type RedemptionRepository interface {
+	// Redeem stores the redemptions and counts them against their
+	// promotions in one transaction. The promotions are locked while their
+	// limits are checked again, so concurrent checkouts cannot take more
+	// uses than allowed. It fails with ErrUsageLimitReached or
+	// ErrCustomerLimitReached without storing anything, and with
+	// ErrDuplicateRedemption if the reference was redeemed before.
+	Redeem(ctx context.Context, redemptions []*model.Redemption) error
+	ListByReference(ctx context.Context, reference string) ([]*model.Redemption, error)
+	// Release marks the held redemptions of reference released and gives
+	// their uses back.
+	Release(ctx context.Context, reference string) error
+}
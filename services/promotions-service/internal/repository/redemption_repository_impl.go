@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const redemptionColumns = `
+	id, reference, promotion_id, COALESCE(code, ''), customer_id, amount, status, created_at
+`
+
+type redemptionRepository struct {
+	db *sql.DB
+}
+
+func NewRedemptionRepository(db *sql.DB) RedemptionRepository {
+	return &redemptionRepository{db: db}
+}
+
+func scanRedemption(row rowScanner) (*model.Redemption, error) {
+	var redemption model.Redemption
+	err := row.Scan(
+		&redemption.ID,
+		&redemption.Reference,
+		&redemption.PromotionID,
+		&redemption.Code,
+		&redemption.CustomerID,
+		&redemption.Amount,
+		&redemption.Status,
+		&redemption.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redemption, nil
+}
+
+func (r *redemptionRepository) Redeem(ctx context.Context, redemptions []*model.Redemption) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock promotions in a fixed order so two checkouts using the same
+	// promotions cannot deadlock.
+	ordered := make([]*model.Redemption, len(redemptions))
+	copy(ordered, redemptions)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].PromotionID < ordered[j].PromotionID
+	})
+
+	for _, redemption := range ordered {
+		var usageLimit, usageCount, perCustomerLimit int
+		err := tx.QueryRowContext(ctx, `
+			SELECT usage_limit, usage_count, per_customer_limit
+			FROM promotions
+			WHERE id::text = $1
+			FOR UPDATE
+		`, redemption.PromotionID).Scan(&usageLimit, &usageCount, &perCustomerLimit)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrPromotionNotFound, redemption.PromotionID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if usageLimit > 0 && usageCount >= usageLimit {
+			return fmt.Errorf("%w: %s", ErrUsageLimitReached, redemption.PromotionID)
+		}
+
+		if perCustomerLimit > 0 {
+			var used int
+			err := tx.QueryRowContext(ctx, `
+				SELECT COUNT(*) FROM redemptions
+				WHERE promotion_id::text = $1 AND customer_id = $2 AND status = $3
+			`, redemption.PromotionID, redemption.CustomerID, model.RedemptionRedeemed).Scan(&used)
+			if err != nil {
+				return err
+			}
+			if used >= perCustomerLimit {
+				return fmt.Errorf("%w: %s", ErrCustomerLimitReached, redemption.PromotionID)
+			}
+		}
+
+		redemption.Status = model.RedemptionRedeemed
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO redemptions (reference, promotion_id, code, customer_id, amount, status)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)
+			RETURNING id, created_at
+		`,
+			redemption.Reference,
+			redemption.PromotionID,
+			redemption.Code,
+			redemption.CustomerID,
+			redemption.Amount,
+			redemption.Status,
+		).Scan(&redemption.ID, &redemption.CreatedAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return ErrDuplicateRedemption
+			}
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE promotions SET usage_count = usage_count + 1 WHERE id::text = $1
+		`, redemption.PromotionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *redemptionRepository) ListByReference(ctx context.Context, reference string) ([]*model.Redemption, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+redemptionColumns+` FROM redemptions WHERE reference = $1 ORDER BY created_at ASC, id ASC`,
+		reference,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redemptions []*model.Redemption
+	for rows.Next() {
+		redemption, err := scanRedemption(rows)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, redemption)
+	}
+
+	return redemptions, rows.Err()
+}
+
+func (r *redemptionRepository) Release(ctx context.Context, reference string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE redemptions SET status = $2, released_at = NOW()
+		WHERE reference = $1 AND status = $3
+		RETURNING promotion_id
+	`, reference, model.RedemptionReleased, model.RedemptionRedeemed)
+	if err != nil {
+		return err
+	}
+
+	var promotionIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		promotionIDs = append(promotionIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sort.Strings(promotionIDs)
+	for _, id := range promotionIDs {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE promotions SET usage_count = usage_count - 1 WHERE id::text = $1
+		`, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
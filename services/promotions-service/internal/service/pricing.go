@@ -0,0 +1,215 @@
+package service
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+)
+
+// candidate is a promotion being considered for a pricing.
+type candidate struct {
+	promotion *model.Promotion
+	// requested is set for promotions the caller asked for by code or id;
+	// only those are reported when they do not apply.
+	requested bool
+	// used is how often the customer already redeemed the promotion.
+	used int
+}
+
+// price applies the candidates to the lines. It only depends on its
+// arguments: candidates are tried by descending priority, then by code and
+// id, and each one discounts what earlier ones left of the lines.
+//
+// Stacking: the first promotion always applies. A promotion that is not
+// stackable never joins one that already applied, and once one applies
+// nothing else does.
+func price(lines []*model.PricingLine, shippingTotal int64, candidates []candidate, customerID int64, now time.Time) *model.Pricing {
+	pricing := &model.Pricing{
+		ShippingTotal: shippingTotal,
+		Lines:         make([]*model.LineDiscount, 0, len(lines)),
+	}
+
+	remaining := make([]int64, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Total()
+		pricing.Subtotal += remaining[i]
+		pricing.Lines = append(pricing.Lines, &model.LineDiscount{
+			SKU:       line.SKU,
+			LineTotal: remaining[i],
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].promotion, candidates[j].promotion
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.ID < b.ID
+	})
+
+	shippingLeft := shippingTotal
+	exclusive := false
+
+	for _, c := range candidates {
+		promotion := c.promotion
+
+		var qualifying []int
+		for i, line := range lines {
+			if promotion.Targets(line) {
+				qualifying = append(qualifying, i)
+			}
+		}
+
+		reason := rejection(c, customerID, pricing.Subtotal, len(qualifying) > 0, now)
+		if reason == "" && (exclusive || (len(pricing.Applied) > 0 && !promotion.Stackable)) {
+			reason = model.RejectNotStackable
+		}
+
+		var base int64
+		for _, i := range qualifying {
+			base += remaining[i]
+		}
+		if reason == "" && promotion.Type != model.PromotionFreeShipping && base == 0 {
+			// Earlier promotions already took everything off these lines.
+			reason = model.RejectNoQualifyingItems
+		}
+
+		if reason != "" {
+			if c.requested {
+				pricing.Rejected = append(pricing.Rejected, model.RejectedCode{Code: promotion.Code, Reason: reason})
+			}
+			continue
+		}
+
+		applied := &model.AppliedPromotion{
+			PromotionID: promotion.ID,
+			Code:        promotion.Code,
+			Name:        promotion.Name,
+			Type:        promotion.Type,
+		}
+
+		switch promotion.Type {
+		case model.PromotionFreeShipping:
+			amount := capDiscount(shippingLeft, promotion.MaxDiscount)
+			shippingLeft -= amount
+			applied.ShippingDiscount = amount
+
+		default:
+			var amount int64
+			if promotion.Type == model.PromotionPercentage {
+				amount = base * promotion.Value / 100
+			} else {
+				amount = promotion.Value
+			}
+			if amount > base {
+				amount = base
+			}
+			amount = capDiscount(amount, promotion.MaxDiscount)
+
+			shares := allocate(amount, qualifying, remaining)
+			for k, i := range qualifying {
+				if shares[k] == 0 {
+					continue
+				}
+				remaining[i] -= shares[k]
+				line := pricing.Lines[i]
+				line.Discount += shares[k]
+				line.Allocations = append(line.Allocations, model.Allocation{
+					PromotionID: promotion.ID,
+					Code:        promotion.Code,
+					Amount:      shares[k],
+				})
+			}
+			applied.Discount = amount
+		}
+
+		pricing.DiscountTotal += applied.Discount
+		pricing.ShippingDiscount += applied.ShippingDiscount
+		pricing.Applied = append(pricing.Applied, applied)
+
+		if !promotion.Stackable {
+			exclusive = true
+		}
+	}
+
+	return pricing
+}
+
+// rejection returns why the promotion cannot apply, or an empty string if
+// it can.
+func rejection(c candidate, customerID, subtotal int64, qualifies bool, now time.Time) string {
+	promotion := c.promotion
+
+	switch {
+	case !promotion.Active:
+		return model.RejectInactive
+	case now.Before(promotion.StartsAt):
+		return model.RejectNotStarted
+	case promotion.EndsAt != nil && !now.Before(*promotion.EndsAt):
+		return model.RejectExpired
+	case promotion.IsExhausted():
+		return model.RejectUsageLimit
+	case promotion.PerCustomerLimit > 0 && customerID == 0:
+		return model.RejectSignInRequired
+	case promotion.PerCustomerLimit > 0 && c.used >= promotion.PerCustomerLimit:
+		return model.RejectCustomerLimit
+	case subtotal < promotion.MinSubtotal:
+		return model.RejectMinimumNotMet
+	case !qualifies:
+		return model.RejectNoQualifyingItems
+	}
+	return ""
+}
+
+func capDiscount(amount, maxDiscount int64) int64 {
+	if maxDiscount > 0 && amount > maxDiscount {
+		return maxDiscount
+	}
+	return amount
+}
+
+// allocate splits amount over the lines in proportion to what is left of
+// them. Units lost to rounding go to the lines with the largest remainders,
+// earlier lines first on ties. amount must not exceed what is left.
+func allocate(amount int64, lines []int, remaining []int64) []int64 {
+	shares := make([]int64, len(lines))
+	remainders := make([]uint64, len(lines))
+
+	var base int64
+	for _, i := range lines {
+		base += remaining[i]
+	}
+	if base == 0 || amount == 0 {
+		return shares
+	}
+
+	var given int64
+	for k, i := range lines {
+		// amount * remaining[i] can overflow int64 on large carts.
+		hi, lo := bits.Mul64(uint64(amount), uint64(remaining[i]))
+		quotient, remainder := bits.Div64(hi, lo, uint64(base))
+		shares[k] = int64(quotient)
+		remainders[k] = remainder
+		given += shares[k]
+	}
+
+	order := make([]int, len(lines))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+
+	for k := 0; given < amount; k++ {
+		shares[order[k]]++
+		given++
+	}
+
+	return shares
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/repository"
+)
+
+const (
+	maxPricingLines            = 100
+	maxCodesPerPricing         = 5
+	maxPromotionsPerRedemption = 20
+	maxReferenceLength         = 100
+)
+
+type pricingServiceImpl struct {
+	promotionRepo  repository.PromotionRepository
+	redemptionRepo repository.RedemptionRepository
+	catalog        ProductCatalog
+}
+
+func NewPricingService(
+	promotionRepo repository.PromotionRepository,
+	redemptionRepo repository.RedemptionRepository,
+	catalog ProductCatalog,
+) PricingService {
+	return &pricingServiceImpl{
+		promotionRepo:  promotionRepo,
+		redemptionRepo: redemptionRepo,
+		catalog:        catalog,
+	}
+}
+
+// EvaluatePricing implements PricingService.
+func (s *pricingServiceImpl) EvaluatePricing(ctx context.Context, req *dto.EvaluatePricingRequest) (*dto.PricingResponse, error) {
+	invalid := map[string]string{}
+	validatePricingInput(invalid, req.CustomerID, req.Lines, req.ShippingTotal)
+
+	codes := normalizeCodes(req.Codes)
+	if len(codes) > maxCodesPerPricing {
+		invalid["codes"] = fmt.Sprintf("must have at most %d codes", maxCodesPerPricing)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	now := time.Now().UTC()
+
+	lines, err := s.resolveLines(ctx, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	coded, err := s.promotionRepo.ListByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	automatic, err := s.promotionRepo.ListAutomatic(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(coded))
+	promotions := make([]*model.Promotion, 0, len(coded)+len(automatic))
+	requested := make(map[string]bool, len(coded))
+	for _, promotion := range coded {
+		found[promotion.Code] = true
+		requested[promotion.ID] = true
+		promotions = append(promotions, promotion)
+	}
+	promotions = append(promotions, automatic...)
+
+	promotions, err = inCurrency(promotions, req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidates(ctx, req.CustomerID, promotions, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := price(lines, req.ShippingTotal, candidates, req.CustomerID, now)
+	for _, code := range codes {
+		if !found[code] {
+			pricing.Rejected = append(pricing.Rejected, model.RejectedCode{Code: code, Reason: model.RejectUnknownCode})
+		}
+	}
+	sort.SliceStable(pricing.Rejected, func(i, j int) bool {
+		return pricing.Rejected[i].Code < pricing.Rejected[j].Code
+	})
+
+	return toPricingResponse(pricing), nil
+}
+
+// RedeemPromotions implements PricingService.
+func (s *pricingServiceImpl) RedeemPromotions(ctx context.Context, req *dto.RedeemPromotionsRequest) ([]*dto.RedemptionResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+
+	invalid := map[string]string{}
+	if reference == "" || len(reference) > maxReferenceLength {
+		invalid["reference"] = fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength)
+	}
+	validatePricingInput(invalid, req.CustomerID, req.Lines, req.ShippingTotal)
+
+	ids := make([]string, 0, len(req.PromotionIDs))
+	seen := map[string]bool{}
+	for _, id := range req.PromotionIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxPromotionsPerRedemption {
+		invalid["promotion_ids"] = fmt.Sprintf("must have at most %d promotions", maxPromotionsPerRedemption)
+	}
+	if req.DiscountTotal < 0 {
+		invalid["discount_total"] = "must not be negative"
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	existing, err := s.redemptionRepo.ListByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return toRedemptionResponses(existing), nil
+	}
+
+	lines, err := s.resolveLines(ctx, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.promotionRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(promotions) != len(ids) {
+		return nil, fmt.Errorf("%w: a promotion no longer exists", ErrPricingChanged)
+	}
+
+	requested := make(map[string]bool, len(promotions))
+	for _, promotion := range promotions {
+		requested[promotion.ID] = true
+	}
+
+	promotions, err = inCurrency(promotions, req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidates(ctx, req.CustomerID, promotions, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := price(lines, req.ShippingTotal, candidates, req.CustomerID, time.Now().UTC())
+	if len(pricing.Rejected) > 0 {
+		rejected := pricing.Rejected[0]
+		return nil, fmt.Errorf("%w: promotion %s is %s", ErrPricingChanged, displayCode(rejected.Code), rejected.Reason)
+	}
+	if pricing.Granted() != req.DiscountTotal {
+		return nil, fmt.Errorf("%w: discount is now %d", ErrPricingChanged, pricing.Granted())
+	}
+
+	redemptions := make([]*model.Redemption, 0, len(pricing.Applied))
+	for _, applied := range pricing.Applied {
+		redemptions = append(redemptions, &model.Redemption{
+			Reference:   reference,
+			PromotionID: applied.PromotionID,
+			Code:        applied.Code,
+			CustomerID:  req.CustomerID,
+			Amount:      applied.Discount + applied.ShippingDiscount,
+		})
+	}
+	if len(redemptions) == 0 {
+		return []*dto.RedemptionResponse{}, nil
+	}
+
+	err = s.redemptionRepo.Redeem(ctx, redemptions)
+	if errors.Is(err, repository.ErrDuplicateRedemption) {
+		// A concurrent request with the same reference won the race.
+		existing, err := s.redemptionRepo.ListByReference(ctx, reference)
+		if err != nil {
+			return nil, err
+		}
+		return toRedemptionResponses(existing), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toRedemptionResponses(redemptions), nil
+}
+
+// ReleasePromotions implements PricingService.
+func (s *pricingServiceImpl) ReleasePromotions(ctx context.Context, reference string) ([]*dto.RedemptionResponse, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || len(reference) > maxReferenceLength {
+		return nil, &ValidationError{Fields: map[string]string{
+			"reference": fmt.Sprintf("must be between 1 and %d characters", maxReferenceLength),
+		}}
+	}
+
+	if err := s.redemptionRepo.Release(ctx, reference); err != nil {
+		return nil, err
+	}
+
+	redemptions, err := s.redemptionRepo.ListByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRedemptionResponses(redemptions), nil
+}
+
+// resolveLines looks up the targeting details of every line's SKU.
+func (s *pricingServiceImpl) resolveLines(ctx context.Context, reqLines []dto.PricingLine) ([]*model.PricingLine, error) {
+	lines := make([]*model.PricingLine, 0, len(reqLines))
+	if len(reqLines) == 0 {
+		return lines, nil
+	}
+
+	skus := make([]string, 0, len(reqLines))
+	for _, line := range reqLines {
+		skus = append(skus, strings.TrimSpace(line.SKU))
+	}
+
+	details, err := s.catalog.LookupSKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range reqLines {
+		priced := &model.PricingLine{
+			SKU:       skus[i],
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+		}
+		if product, ok := details[skus[i]]; ok {
+			priced.ProductID = product.ProductID
+			priced.CategoryID = product.CategoryID
+			priced.SellerID = product.SellerID
+		}
+		lines = append(lines, priced)
+	}
+
+	return lines, nil
+}
+
+// candidates pairs the promotions with the customer's use of them.
+func (s *pricingServiceImpl) candidates(ctx context.Context, customerID int64, promotions []*model.Promotion, requested map[string]bool) ([]candidate, error) {
+	var limited []string
+	for _, promotion := range promotions {
+		if promotion.PerCustomerLimit > 0 {
+			limited = append(limited, promotion.ID)
+		}
+	}
+
+	usage, err := s.promotionRepo.CustomerUsage(ctx, customerID, limited)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]candidate, 0, len(promotions))
+	for _, promotion := range promotions {
+		candidates = append(candidates, candidate{
+			promotion: promotion,
+			requested: requested[promotion.ID],
+			used:      usage[promotion.ID],
+		})
+	}
+
+	return candidates, nil
+}
+
+// inCurrency converts the promotions' fixed amounts into the currency the
+// lines were priced in.
+func inCurrency(promotions []*model.Promotion, reqRate *dto.ExchangeRate) ([]*model.Promotion, error) {
+	if reqRate == nil {
+		return promotions, nil
+	}
+
+	rate := &model.ExchangeRate{
+		BaseCurrency: strings.ToUpper(strings.TrimSpace(reqRate.BaseCurrency)),
+		Currency:     strings.ToUpper(strings.TrimSpace(reqRate.Currency)),
+		Rate:         strings.TrimSpace(reqRate.Rate),
+	}
+
+	converted := make([]*model.Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		promotion, err := promotion.InCurrency(rate)
+		if errors.Is(err, model.ErrInvalidRate) {
+			return nil, &ValidationError{Fields: map[string]string{
+				"exchange_rate.rate": "must be a positive decimal",
+			}}
+		}
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, promotion)
+	}
+
+	return converted, nil
+}
+
+func validatePricingInput(invalid map[string]string, customerID int64, lines []dto.PricingLine, shippingTotal int64) {
+	if customerID < 0 {
+		invalid["customer_id"] = "must not be negative"
+	}
+	if len(lines) > maxPricingLines {
+		invalid["lines"] = fmt.Sprintf("must have at most %d lines", maxPricingLines)
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line.SKU) == "" {
+			invalid[fmt.Sprintf("lines[%d].sku", i)] = "is required"
+		}
+		if line.Quantity <= 0 {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = "must be greater than zero"
+		}
+		if line.UnitPrice < 0 {
+			invalid[fmt.Sprintf("lines[%d].unit_price", i)] = "must not be negative"
+		}
+	}
+	if shippingTotal < 0 {
+		invalid["shipping_total"] = "must not be negative"
+	}
+}
+
+// normalizeCodes upper-cases and trims the codes and drops blanks and
+// repeats, keeping the order they were given in.
+func normalizeCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code = normalizeCode(code)
+		if code != "" && !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+	return normalized
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func displayCode(code string) string {
+	if code == "" {
+		return "(automatic)"
+	}
+	return code
+}
+
+func toPricingResponse(pricing *model.Pricing) *dto.PricingResponse {
+	resp := &dto.PricingResponse{
+		Subtotal:         pricing.Subtotal,
+		DiscountTotal:    pricing.DiscountTotal,
+		ShippingTotal:    pricing.ShippingTotal,
+		ShippingDiscount: pricing.ShippingDiscount,
+		Total:            pricing.Total(),
+		Lines:            make([]*dto.LineDiscountResponse, 0, len(pricing.Lines)),
+		Applied:          make([]*dto.AppliedPromotionResponse, 0, len(pricing.Applied)),
+		Rejected:         make([]*dto.RejectedCodeResponse, 0, len(pricing.Rejected)),
+	}
+
+	for _, line := range pricing.Lines {
+		lineResp := &dto.LineDiscountResponse{
+			SKU:         line.SKU,
+			LineTotal:   line.LineTotal,
+			Discount:    line.Discount,
+			Allocations: make([]*dto.DiscountAllocationResponse, 0, len(line.Allocations)),
+		}
+		for _, allocation := range line.Allocations {
+			lineResp.Allocations = append(lineResp.Allocations, &dto.DiscountAllocationResponse{
+				PromotionID: allocation.PromotionID,
+				Code:        allocation.Code,
+				Amount:      allocation.Amount,
+			})
+		}
+		resp.Lines = append(resp.Lines, lineResp)
+	}
+
+	for _, applied := range pricing.Applied {
+		resp.Applied = append(resp.Applied, &dto.AppliedPromotionResponse{
+			PromotionID:      applied.PromotionID,
+			Code:             applied.Code,
+			Name:             applied.Name,
+			Type:             string(applied.Type),
+			Discount:         applied.Discount,
+			ShippingDiscount: applied.ShippingDiscount,
+		})
+	}
+
+	for _, rejected := range pricing.Rejected {
+		resp.Rejected = append(resp.Rejected, &dto.RejectedCodeResponse{
+			Code:   rejected.Code,
+			Reason: rejected.Reason,
+		})
+	}
+
+	return resp
+}
+
+func toRedemptionResponses(redemptions []*model.Redemption) []*dto.RedemptionResponse {
+	resp := make([]*dto.RedemptionResponse, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		resp = append(resp, &dto.RedemptionResponse{
+			ID:          redemption.ID,
+			Reference:   redemption.Reference,
+			PromotionID: redemption.PromotionID,
+			Code:        redemption.Code,
+			CustomerID:  redemption.CustomerID,
+			Amount:      redemption.Amount,
+			Status:      string(redemption.Status),
+			CreatedAt:   redemption.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/repository"
+)
+
+// fakePromotionRepo answers the pricing lookups from a fixed list.
+type fakePromotionRepo struct {
+	promotions []*model.Promotion
+	usage      map[string]int
+}
+
+func (r *fakePromotionRepo) Create(ctx context.Context, promotion *model.Promotion) error {
+	r.promotions = append(r.promotions, promotion)
+	return nil
+}
+
+func (r *fakePromotionRepo) UpdateTerms(ctx context.Context, promotion *model.Promotion) error {
+	return nil
+}
+
+func (r *fakePromotionRepo) SetActive(ctx context.Context, id string, active bool) (*model.Promotion, error) {
+	return nil, repository.ErrPromotionNotFound
+}
+
+func (r *fakePromotionRepo) GetByID(ctx context.Context, id string) (*model.Promotion, error) {
+	return nil, repository.ErrPromotionNotFound
+}
+
+func (r *fakePromotionRepo) List(ctx context.Context, filter repository.PromotionFilter) ([]*model.Promotion, int, error) {
+	return r.promotions, len(r.promotions), nil
+}
+
+func (r *fakePromotionRepo) ListByCodes(ctx context.Context, codes []string) ([]*model.Promotion, error) {
+	var found []*model.Promotion
+	for _, promotion := range r.promotions {
+		for _, code := range codes {
+			if promotion.Code != "" && promotion.Code == code {
+				found = append(found, promotion)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePromotionRepo) ListByIDs(ctx context.Context, ids []string) ([]*model.Promotion, error) {
+	var found []*model.Promotion
+	for _, promotion := range r.promotions {
+		for _, id := range ids {
+			if promotion.ID == id {
+				found = append(found, promotion)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePromotionRepo) ListAutomatic(ctx context.Context, now time.Time) ([]*model.Promotion, error) {
+	var found []*model.Promotion
+	for _, promotion := range r.promotions {
+		if promotion.IsAutomatic() && promotion.Active {
+			found = append(found, promotion)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePromotionRepo) CustomerUsage(ctx context.Context, customerID int64, promotionIDs []string) (map[string]int, error) {
+	return r.usage, nil
+}
+
+// fakeRedemptionRepo keeps redemptions by reference. With raceWinner set,
+// Redeem behaves as if a concurrent request stored those first.
+type fakeRedemptionRepo struct {
+	byReference map[string][]*model.Redemption
+	raceWinner  []*model.Redemption
+	redeemErr   error
+	redeemCalls int
+}
+
+func (r *fakeRedemptionRepo) Redeem(ctx context.Context, redemptions []*model.Redemption) error {
+	r.redeemCalls++
+	reference := redemptions[0].Reference
+	if r.raceWinner != nil {
+		r.byReference[reference] = r.raceWinner
+		return repository.ErrDuplicateRedemption
+	}
+	if r.redeemErr != nil {
+		return r.redeemErr
+	}
+	for i, redemption := range redemptions {
+		redemption.ID = fmt.Sprintf("redemption-%d", i+1)
+		redemption.Status = model.RedemptionRedeemed
+	}
+	r.byReference[reference] = redemptions
+	return nil
+}
+
+func (r *fakeRedemptionRepo) ListByReference(ctx context.Context, reference string) ([]*model.Redemption, error) {
+	return r.byReference[reference], nil
+}
+
+func (r *fakeRedemptionRepo) Release(ctx context.Context, reference string) error {
+	for _, redemption := range r.byReference[reference] {
+		redemption.Status = model.RedemptionReleased
+	}
+	return nil
+}
+
+type fakeCatalog struct {
+	details map[string]*model.SKUDetails
+}
+
+func (c *fakeCatalog) LookupSKUs(ctx context.Context, skus []string) (map[string]*model.SKUDetails, error) {
+	return c.details, nil
+}
+
+func newTestPricingService(promotions ...*model.Promotion) (PricingService, *fakePromotionRepo, *fakeRedemptionRepo) {
+	promotionRepo := &fakePromotionRepo{promotions: promotions}
+	redemptionRepo := &fakeRedemptionRepo{byReference: map[string][]*model.Redemption{}}
+	catalog := &fakeCatalog{details: map[string]*model.SKUDetails{
+		"SKU-A": {SKU: "SKU-A", ProductID: "p1", CategoryID: "c1", SellerID: 1},
+		"SKU-B": {SKU: "SKU-B", ProductID: "p2", CategoryID: "c2", SellerID: 2},
+	}}
+	return NewPricingService(promotionRepo, redemptionRepo, catalog), promotionRepo, redemptionRepo
+}
+
+// testPricingLines match testLines; the catalog supplies their targeting.
+func testPricingLines() []dto.PricingLine {
+	return []dto.PricingLine{
+		{SKU: "SKU-A", Quantity: 2, UnitPrice: 1000},
+		{SKU: "SKU-B", Quantity: 1, UnitPrice: 1000},
+	}
+}
+
+// runningPromotion is testPromotion started an hour before the real clock,
+// for code paths that price at time.Now.
+func runningPromotion(code string, promotionType model.PromotionType, value int64) *model.Promotion {
+	promotion := testPromotion(code, promotionType, value)
+	promotion.StartsAt = time.Now().Add(-time.Hour)
+	return promotion
+}
+
+func TestEvaluatePricingValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(req *dto.EvaluatePricingRequest)
+		wantField string
+	}{
+		{name: "negative customer", edit: func(req *dto.EvaluatePricingRequest) { req.CustomerID = -1 }, wantField: "customer_id"},
+		{name: "too many codes", edit: func(req *dto.EvaluatePricingRequest) {
+			req.Codes = []string{"A", "B", "C", "D", "E", "F"}
+		}, wantField: "codes"},
+		{name: "repeated codes count once", edit: func(req *dto.EvaluatePricingRequest) {
+			req.Codes = []string{"a", "A ", " a", "B", "C", "D", "E"}
+		}},
+		{name: "blank sku", edit: func(req *dto.EvaluatePricingRequest) { req.Lines[0].SKU = " " }, wantField: "lines[0].sku"},
+		{name: "zero quantity", edit: func(req *dto.EvaluatePricingRequest) { req.Lines[1].Quantity = 0 }, wantField: "lines[1].quantity"},
+		{name: "negative price", edit: func(req *dto.EvaluatePricingRequest) { req.Lines[1].UnitPrice = -1 }, wantField: "lines[1].unit_price"},
+		{name: "negative shipping", edit: func(req *dto.EvaluatePricingRequest) { req.ShippingTotal = -1 }, wantField: "shipping_total"},
+		{name: "invalid exchange rate", edit: func(req *dto.EvaluatePricingRequest) {
+			req.Codes = []string{"TEN"}
+			req.ExchangeRate = &dto.ExchangeRate{BaseCurrency: "USD", Currency: "EUR", Rate: "0"}
+		}, wantField: "exchange_rate.rate"},
+		{name: "identity exchange rate is not converted", edit: func(req *dto.EvaluatePricingRequest) {
+			req.Codes = []string{"TEN"}
+			req.ExchangeRate = &dto.ExchangeRate{BaseCurrency: "USD", Currency: "usd"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestPricingService(runningPromotion("TEN", model.PromotionFixed, 100))
+			req := &dto.EvaluatePricingRequest{CustomerID: 7, Lines: testPricingLines()}
+			tt.edit(req)
+
+			_, err := svc.EvaluatePricing(context.Background(), req)
+
+			var validationErr *ValidationError
+			switch {
+			case tt.wantField == "" && err != nil:
+				t.Fatalf("EvaluatePricing() error = %v, want nil", err)
+			case tt.wantField != "" && !errors.As(err, &validationErr):
+				t.Fatalf("EvaluatePricing() error = %v, want validation error", err)
+			case tt.wantField != "" && validationErr.Fields[tt.wantField] == "":
+				t.Errorf("EvaluatePricing() invalid fields = %v, want %s", validationErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestEvaluatePricing(t *testing.T) {
+	category := runningPromotion("CAT", model.PromotionPercentage, 10)
+	category.CategoryIDs = []string{"c1"}
+	automatic := runningPromotion("", model.PromotionFreeShipping, 0)
+	limited := runningPromotion("ONCE", model.PromotionFixed, 100)
+	limited.PerCustomerLimit = 1
+
+	svc, promotionRepo, _ := newTestPricingService(category, automatic, limited)
+	promotionRepo.usage = map[string]int{limited.ID: 1}
+
+	resp, err := svc.EvaluatePricing(context.Background(), &dto.EvaluatePricingRequest{
+		CustomerID:    7,
+		Codes:         []string{" cat ", "nope", "once", "CAT"},
+		Lines:         testPricingLines(),
+		ShippingTotal: 500,
+	})
+	if err != nil {
+		t.Fatalf("EvaluatePricing() error = %v", err)
+	}
+
+	if resp.Subtotal != 3000 || resp.DiscountTotal != 200 || resp.ShippingDiscount != 500 || resp.Total != 2800 {
+		t.Errorf("EvaluatePricing() totals = %d - %d + 500 - %d = %d, want 3000 - 200 + 500 - 500 = 2800",
+			resp.Subtotal, resp.DiscountTotal, resp.ShippingDiscount, resp.Total)
+	}
+	if len(resp.Lines) != 2 || resp.Lines[0].Discount != 200 || resp.Lines[1].Discount != 0 {
+		t.Errorf("line discounts = %+v, want 200 off SKU-A only", resp.Lines)
+	}
+
+	var applied []string
+	for _, promotion := range resp.Applied {
+		applied = append(applied, displayCode(promotion.Code))
+	}
+	if want := "[(automatic) CAT]"; fmt.Sprint(applied) != want {
+		t.Errorf("applied = %v, want %s", applied, want)
+	}
+
+	var rejected []string
+	for _, code := range resp.Rejected {
+		rejected = append(rejected, code.Code+":"+code.Reason)
+	}
+	if want := "[NOPE:unknown_code ONCE:customer_limit_reached]"; fmt.Sprint(rejected) != want {
+		t.Errorf("rejected = %v, want %s", rejected, want)
+	}
+}
+
+func TestRedeemPromotions(t *testing.T) {
+	ten := runningPromotion("TEN", model.PromotionPercentage, 10)
+	ship := runningPromotion("SHIP", model.PromotionFreeShipping, 0)
+	ctx := context.Background()
+
+	request := func(discountTotal int64, ids ...string) *dto.RedeemPromotionsRequest {
+		return &dto.RedeemPromotionsRequest{
+			Reference:     "checkout-1",
+			CustomerID:    7,
+			PromotionIDs:  ids,
+			Lines:         testPricingLines(),
+			ShippingTotal: 500,
+			DiscountTotal: discountTotal,
+		}
+	}
+
+	t.Run("redeems what the customer was shown", func(t *testing.T) {
+		svc, _, redemptionRepo := newTestPricingService(ten, ship)
+
+		resp, err := svc.RedeemPromotions(ctx, request(800, ten.ID, ship.ID, ten.ID))
+		if err != nil {
+			t.Fatalf("RedeemPromotions() error = %v", err)
+		}
+		if len(resp) != 2 || resp[0].Code != "SHIP" || resp[0].Amount != 500 || resp[1].Code != "TEN" || resp[1].Amount != 300 {
+			t.Errorf("RedeemPromotions() = %+v, want SHIP for 500 and TEN for 300", resp)
+		}
+
+		again, err := svc.RedeemPromotions(ctx, request(800, ten.ID, ship.ID))
+		if err != nil {
+			t.Fatalf("second RedeemPromotions() error = %v", err)
+		}
+		if len(again) != 2 || again[0].ID != resp[0].ID || redemptionRepo.redeemCalls != 1 {
+			t.Errorf("second RedeemPromotions() = %+v after %d redeems, want the first redemptions back", again, redemptionRepo.redeemCalls)
+		}
+	})
+
+	t.Run("discount changed", func(t *testing.T) {
+		svc, _, redemptionRepo := newTestPricingService(ten)
+
+		if _, err := svc.RedeemPromotions(ctx, request(250, ten.ID)); !errors.Is(err, ErrPricingChanged) {
+			t.Errorf("RedeemPromotions() error = %v, want %v", err, ErrPricingChanged)
+		}
+		if redemptionRepo.redeemCalls != 0 {
+			t.Errorf("redeemed %d times, want none", redemptionRepo.redeemCalls)
+		}
+	})
+
+	t.Run("promotion no longer applies", func(t *testing.T) {
+		ended := runningPromotion("OLD", model.PromotionFixed, 100)
+		ended.Active = false
+		svc, _, _ := newTestPricingService(ended)
+
+		if _, err := svc.RedeemPromotions(ctx, request(100, ended.ID)); !errors.Is(err, ErrPricingChanged) {
+			t.Errorf("RedeemPromotions() error = %v, want %v", err, ErrPricingChanged)
+		}
+	})
+
+	t.Run("promotion deleted", func(t *testing.T) {
+		svc, _, _ := newTestPricingService(ten)
+
+		if _, err := svc.RedeemPromotions(ctx, request(300, ten.ID, "promo-GONE")); !errors.Is(err, ErrPricingChanged) {
+			t.Errorf("RedeemPromotions() error = %v, want %v", err, ErrPricingChanged)
+		}
+	})
+
+	t.Run("limit taken by a concurrent checkout", func(t *testing.T) {
+		svc, _, redemptionRepo := newTestPricingService(ten)
+		redemptionRepo.redeemErr = repository.ErrUsageLimitReached
+
+		if _, err := svc.RedeemPromotions(ctx, request(300, ten.ID)); !errors.Is(err, repository.ErrUsageLimitReached) {
+			t.Errorf("RedeemPromotions() error = %v, want %v", err, repository.ErrUsageLimitReached)
+		}
+	})
+
+	t.Run("same reference redeemed concurrently", func(t *testing.T) {
+		svc, _, redemptionRepo := newTestPricingService(ten)
+		redemptionRepo.raceWinner = []*model.Redemption{{ID: "winner", Reference: "checkout-1", PromotionID: ten.ID, Amount: 300}}
+
+		resp, err := svc.RedeemPromotions(ctx, request(300, ten.ID))
+		if err != nil {
+			t.Fatalf("RedeemPromotions() error = %v", err)
+		}
+		if len(resp) != 1 || resp[0].ID != "winner" {
+			t.Errorf("RedeemPromotions() = %+v, want the winner's redemptions", resp)
+		}
+	})
+
+	t.Run("nothing to redeem", func(t *testing.T) {
+		svc, _, redemptionRepo := newTestPricingService()
+
+		resp, err := svc.RedeemPromotions(ctx, request(0))
+		if err != nil || len(resp) != 0 || redemptionRepo.redeemCalls != 0 {
+			t.Errorf("RedeemPromotions() = %v, %v after %d redeems, want nothing redeemed", resp, err, redemptionRepo.redeemCalls)
+		}
+	})
+}
+
+func TestReleasePromotions(t *testing.T) {
+	ten := runningPromotion("TEN", model.PromotionPercentage, 10)
+	svc, _, _ := newTestPricingService(ten)
+	ctx := context.Background()
+
+	_, err := svc.RedeemPromotions(ctx, &dto.RedeemPromotionsRequest{
+		Reference:     "checkout-1",
+		CustomerID:    7,
+		PromotionIDs:  []string{ten.ID},
+		Lines:         testPricingLines(),
+		DiscountTotal: 300,
+	})
+	if err != nil {
+		t.Fatalf("RedeemPromotions() error = %v", err)
+	}
+
+	resp, err := svc.ReleasePromotions(ctx, " checkout-1 ")
+	if err != nil {
+		t.Fatalf("ReleasePromotions() error = %v", err)
+	}
+	if len(resp) != 1 || resp[0].Status != string(model.RedemptionReleased) {
+		t.Errorf("ReleasePromotions() = %+v, want one released redemption", resp)
+	}
+
+	var validationErr *ValidationError
+	if _, err := svc.ReleasePromotions(ctx, " "); !errors.As(err, &validationErr) {
+		t.Errorf("ReleasePromotions() without a reference error = %v, want validation error", err)
+	}
+}
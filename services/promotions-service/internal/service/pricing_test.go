@@ -0,0 +1,295 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testPromotion returns an active, stackable promotion that started an hour
+// before testNow and has no limits or targets.
+func testPromotion(code string, promotionType model.PromotionType, value int64) *model.Promotion {
+	return &model.Promotion{
+		ID:        "promo-" + code,
+		Code:      code,
+		Name:      code,
+		Type:      promotionType,
+		Value:     value,
+		StartsAt:  testNow.Add(-time.Hour),
+		Stackable: true,
+		Active:    true,
+	}
+}
+
+// testLines are 2 x 1000 of a c1 product from seller 1 and 1 x 1000 of a c2
+// product from seller 2.
+func testLines() []*model.PricingLine {
+	return []*model.PricingLine{
+		{SKU: "SKU-A", ProductID: "p1", CategoryID: "c1", SellerID: 1, Quantity: 2, UnitPrice: 1000},
+		{SKU: "SKU-B", ProductID: "p2", CategoryID: "c2", SellerID: 2, Quantity: 1, UnitPrice: 1000},
+	}
+}
+
+func requested(promotions ...*model.Promotion) []candidate {
+	candidates := make([]candidate, 0, len(promotions))
+	for _, promotion := range promotions {
+		candidates = append(candidates, candidate{promotion: promotion, requested: true})
+	}
+	return candidates
+}
+
+func TestPrice(t *testing.T) {
+	with := func(promotion *model.Promotion, edit func(p *model.Promotion)) *model.Promotion {
+		edit(promotion)
+		return promotion
+	}
+
+	tests := []struct {
+		name             string
+		candidates       []candidate
+		wantDiscount     int64
+		wantShipping     int64
+		wantLineDiscount []int64
+		wantApplied      []string
+		wantRejected     []string
+	}{
+		{
+			name:             "percentage off everything",
+			candidates:       requested(testPromotion("TEN", model.PromotionPercentage, 10)),
+			wantDiscount:     300,
+			wantLineDiscount: []int64{200, 100},
+			wantApplied:      []string{"TEN"},
+		},
+		{
+			name: "percentage capped by max discount",
+			candidates: requested(with(testPromotion("TEN", model.PromotionPercentage, 10), func(p *model.Promotion) {
+				p.MaxDiscount = 250
+			})),
+			wantDiscount:     250,
+			wantLineDiscount: []int64{167, 83},
+			wantApplied:      []string{"TEN"},
+		},
+		{
+			name: "fixed amount never exceeds the qualifying lines",
+			candidates: requested(with(testPromotion("BIG", model.PromotionFixed, 5000), func(p *model.Promotion) {
+				p.CategoryIDs = []string{"c2"}
+			})),
+			wantDiscount:     1000,
+			wantLineDiscount: []int64{0, 1000},
+			wantApplied:      []string{"BIG"},
+		},
+		{
+			name: "targeted to a seller",
+			candidates: requested(with(testPromotion("TEN", model.PromotionPercentage, 10), func(p *model.Promotion) {
+				p.SellerIDs = []int64{1}
+			})),
+			wantDiscount:     200,
+			wantLineDiscount: []int64{200, 0},
+			wantApplied:      []string{"TEN"},
+		},
+		{
+			name:             "free shipping",
+			candidates:       requested(testPromotion("SHIP", model.PromotionFreeShipping, 0)),
+			wantShipping:     500,
+			wantLineDiscount: []int64{0, 0},
+			wantApplied:      []string{"SHIP"},
+		},
+		{
+			name: "free shipping capped by max discount",
+			candidates: requested(with(testPromotion("SHIP", model.PromotionFreeShipping, 0), func(p *model.Promotion) {
+				p.MaxDiscount = 200
+			})),
+			wantShipping:     200,
+			wantLineDiscount: []int64{0, 0},
+			wantApplied:      []string{"SHIP"},
+		},
+		{
+			name: "stackable promotions discount what is left",
+			candidates: requested(
+				testPromotion("TEN", model.PromotionPercentage, 10),
+				with(testPromotion("FIVE", model.PromotionFixed, 500), func(p *model.Promotion) {
+					p.CategoryIDs = []string{"c1"}
+				}),
+			),
+			// FIVE sorts first on code and leaves 1500 of line A for TEN.
+			wantDiscount:     750,
+			wantLineDiscount: []int64{650, 100},
+			wantApplied:      []string{"FIVE", "TEN"},
+		},
+		{
+			name: "higher priority goes first",
+			candidates: requested(
+				with(testPromotion("TEN", model.PromotionPercentage, 10), func(p *model.Promotion) {
+					p.Priority = 1
+				}),
+				with(testPromotion("FIVE", model.PromotionFixed, 500), func(p *model.Promotion) {
+					p.CategoryIDs = []string{"c1"}
+				}),
+			),
+			wantDiscount:     800,
+			wantLineDiscount: []int64{700, 100},
+			wantApplied:      []string{"TEN", "FIVE"},
+		},
+		{
+			name: "non-stackable promotion does not join an applied one",
+			candidates: requested(
+				testPromotion("AAA", model.PromotionFixed, 100),
+				with(testPromotion("BBB", model.PromotionPercentage, 10), func(p *model.Promotion) {
+					p.Stackable = false
+				}),
+			),
+			wantDiscount:     100,
+			wantLineDiscount: []int64{67, 33},
+			wantApplied:      []string{"AAA"},
+			wantRejected:     []string{"BBB:not_stackable"},
+		},
+		{
+			name: "applied non-stackable promotion blocks the rest",
+			candidates: requested(
+				with(testPromotion("AAA", model.PromotionPercentage, 10), func(p *model.Promotion) {
+					p.Stackable = false
+				}),
+				testPromotion("BBB", model.PromotionFixed, 100),
+			),
+			wantDiscount:     300,
+			wantLineDiscount: []int64{200, 100},
+			wantApplied:      []string{"AAA"},
+			wantRejected:     []string{"BBB:not_stackable"},
+		},
+		{
+			name: "nothing left to discount",
+			candidates: requested(
+				testPromotion("AAA", model.PromotionPercentage, 100),
+				testPromotion("BBB", model.PromotionFixed, 100),
+			),
+			wantDiscount:     3000,
+			wantLineDiscount: []int64{2000, 1000},
+			wantApplied:      []string{"AAA"},
+			wantRejected:     []string{"BBB:no_qualifying_items"},
+		},
+		{
+			name: "automatic promotions that do not apply are not reported",
+			candidates: []candidate{{promotion: with(testPromotion("", model.PromotionPercentage, 10), func(p *model.Promotion) {
+				p.MinSubtotal = 5000
+			})}},
+			wantLineDiscount: []int64{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing := price(testLines(), 500, tt.candidates, 7, testNow)
+
+			if pricing.Subtotal != 3000 || pricing.ShippingTotal != 500 {
+				t.Errorf("subtotal and shipping = %d, %d, want 3000, 500", pricing.Subtotal, pricing.ShippingTotal)
+			}
+			if pricing.DiscountTotal != tt.wantDiscount || pricing.ShippingDiscount != tt.wantShipping {
+				t.Errorf("discount = %d, shipping discount = %d, want %d, %d",
+					pricing.DiscountTotal, pricing.ShippingDiscount, tt.wantDiscount, tt.wantShipping)
+			}
+
+			var lineTotal int64
+			for i, line := range pricing.Lines {
+				lineTotal += line.Discount
+				if line.Discount != tt.wantLineDiscount[i] {
+					t.Errorf("line %s discount = %d, want %d", line.SKU, line.Discount, tt.wantLineDiscount[i])
+				}
+			}
+			if lineTotal != pricing.DiscountTotal {
+				t.Errorf("line discounts add up to %d, want the discount total %d", lineTotal, pricing.DiscountTotal)
+			}
+
+			var applied []string
+			for _, promotion := range pricing.Applied {
+				applied = append(applied, promotion.Code)
+			}
+			if fmt.Sprint(applied) != fmt.Sprint(tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", applied, tt.wantApplied)
+			}
+
+			var rejected []string
+			for _, code := range pricing.Rejected {
+				rejected = append(rejected, code.Code+":"+code.Reason)
+			}
+			if fmt.Sprint(rejected) != fmt.Sprint(tt.wantRejected) {
+				t.Errorf("rejected = %v, want %v", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestPriceRejections(t *testing.T) {
+	endsAt := testNow
+
+	tests := []struct {
+		name       string
+		edit       func(p *model.Promotion)
+		used       int
+		customerID int64
+		want       string
+	}{
+		{name: "inactive", edit: func(p *model.Promotion) { p.Active = false }, want: model.RejectInactive},
+		{name: "not started", edit: func(p *model.Promotion) { p.StartsAt = testNow.Add(time.Minute) }, want: model.RejectNotStarted},
+		{name: "ended", edit: func(p *model.Promotion) { p.EndsAt = &endsAt }, want: model.RejectExpired},
+		{name: "used up", edit: func(p *model.Promotion) { p.UsageLimit, p.UsageCount = 5, 5 }, want: model.RejectUsageLimit},
+		{name: "guest with a per customer limit", edit: func(p *model.Promotion) { p.PerCustomerLimit = 1 }, want: model.RejectSignInRequired},
+		{name: "customer used it up", edit: func(p *model.Promotion) { p.PerCustomerLimit = 2 }, used: 2, customerID: 7, want: model.RejectCustomerLimit},
+		{name: "customer has uses left", edit: func(p *model.Promotion) { p.PerCustomerLimit = 2 }, used: 1, customerID: 7},
+		{name: "minimum not met", edit: func(p *model.Promotion) { p.MinSubtotal = 3001 }, customerID: 7, want: model.RejectMinimumNotMet},
+		{name: "minimum met exactly", edit: func(p *model.Promotion) { p.MinSubtotal = 3000 }, customerID: 7},
+		{name: "no qualifying items", edit: func(p *model.Promotion) { p.ProductIDs = []string{"p9"} }, want: model.RejectNoQualifyingItems},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := testPromotion("CODE", model.PromotionPercentage, 10)
+			tt.edit(promotion)
+
+			pricing := price(testLines(), 0, []candidate{{promotion: promotion, requested: true, used: tt.used}}, tt.customerID, testNow)
+
+			got := ""
+			if len(pricing.Rejected) > 0 {
+				got = pricing.Rejected[0].Reason
+			}
+			if got != tt.want {
+				t.Errorf("rejection = %q, want %q", got, tt.want)
+			}
+			if applied := len(pricing.Applied) == 1; applied != (tt.want == "") {
+				t.Errorf("applied = %v, want %v", applied, tt.want == "")
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int64
+		remaining []int64
+		want      []int64
+	}{
+		{name: "even split", amount: 300, remaining: []int64{2000, 1000}, want: []int64{200, 100}},
+		{name: "rounding goes to earlier lines on ties", amount: 100, remaining: []int64{1, 1, 1}, want: []int64{34, 33, 33}},
+		{name: "rounding goes to the largest remainder", amount: 10, remaining: []int64{100, 200, 400}, want: []int64{1, 3, 6}},
+		{name: "nothing left", amount: 0, remaining: []int64{0, 0}, want: []int64{0, 0}},
+		{name: "large amounts do not overflow", amount: 1 << 62, remaining: []int64{1 << 61, 1 << 61}, want: []int64{1 << 61, 1 << 61}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make([]int, len(tt.remaining))
+			for i := range lines {
+				lines[i] = i
+			}
+
+			got := allocate(tt.amount, lines, tt.remaining)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("allocate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
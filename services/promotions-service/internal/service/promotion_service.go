@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+)
+
+var ErrPricingChanged = errors.New("discount no longer matches what the customer was shown")
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ProductCatalog resolves SKUs against the product service. SKUs that are
+// unknown or not for sale are left out of the result.
+type ProductCatalog interface {
+	LookupSKUs(ctx context.Context, skus []string) (map[string]*model.SKUDetails, error)
+}
+
+// PromotionService manages promotions and coupon codes.
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *dto.CreatePromotionRequest) (*dto.PromotionResponse, error)
+	UpdatePromotion(ctx context.Context, req *dto.UpdatePromotionRequest) (*dto.PromotionResponse, error)
+	SetPromotionActive(ctx context.Context, id string, active bool) (*dto.PromotionResponse, error)
+	GetPromotion(ctx context.Context, id string) (*dto.PromotionResponse, error)
+	ListPromotions(ctx context.Context, req *dto.ListPromotionsRequest) ([]*dto.PromotionResponse, int, error)
+}
+
+// PricingService applies promotions to carts and checkouts.
+type PricingService interface {
+	// EvaluatePricing applies the coupon codes and every automatic promotion
+	// that qualifies. Codes that do not apply are reported with a reason
+	// rather than failing the call.
+	EvaluatePricing(ctx context.Context, req *dto.EvaluatePricingRequest) (*dto.PricingResponse, error)
+	// RedeemPromotions prices the lines again with exactly the given
+	// promotions and counts them against their limits. It fails with
+	// ErrPricingChanged if the discount differs from req.DiscountTotal.
+	// Redeeming a reference again returns the first redemptions.
+	RedeemPromotions(ctx context.Context, req *dto.RedeemPromotionsRequest) ([]*dto.RedemptionResponse, error)
+	ReleasePromotions(ctx context.Context, reference string) ([]*dto.RedemptionResponse, error)
+}
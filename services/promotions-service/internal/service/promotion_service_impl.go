@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/promotions-service/internal/repository"
+)
+
+const (
+	defaultPageSize  = 20
+	maxPageSize      = 100
+	maxNameLength    = 200
+	maxTargets       = 100
+	maxPercentageOff = 100
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type promotionServiceImpl struct {
+	promotionRepo repository.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository) PromotionService {
+	return &promotionServiceImpl{
+		promotionRepo: promotionRepo,
+	}
+}
+
+// CreatePromotion implements PromotionService.
+func (s *promotionServiceImpl) CreatePromotion(ctx context.Context, req *dto.CreatePromotionRequest) (*dto.PromotionResponse, error) {
+	code := normalizeCode(req.Code)
+	promotionType := model.PromotionType(strings.TrimSpace(req.Type))
+
+	invalid := map[string]string{}
+	if code != "" && !codePattern.MatchString(code) {
+		invalid["code"] = "must be 3 to 32 letters, digits, dashes or underscores"
+	}
+	if !promotionType.IsValid() {
+		invalid["type"] = "must be percentage, fixed or free_shipping"
+	}
+
+	promotion := &model.Promotion{
+		Code:   code,
+		Type:   promotionType,
+		Active: req.Active,
+	}
+	applyTerms(invalid, promotion, &req.Terms)
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	if err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	return toPromotionResponse(promotion), nil
+}
+
+// UpdatePromotion implements PromotionService.
+func (s *promotionServiceImpl) UpdatePromotion(ctx context.Context, req *dto.UpdatePromotionRequest) (*dto.PromotionResponse, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invalid := map[string]string{}
+	applyTerms(invalid, promotion, &req.Terms)
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+	if promotion.UsageLimit > 0 && promotion.UsageLimit < promotion.UsageCount {
+		return nil, &ValidationError{Fields: map[string]string{
+			"usage_limit": fmt.Sprintf("cannot be below the %d uses already taken", promotion.UsageCount),
+		}}
+	}
+
+	if err := s.promotionRepo.UpdateTerms(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	return toPromotionResponse(promotion), nil
+}
+
+// SetPromotionActive implements PromotionService.
+func (s *promotionServiceImpl) SetPromotionActive(ctx context.Context, id string, active bool) (*dto.PromotionResponse, error) {
+	promotion, err := s.promotionRepo.SetActive(ctx, id, active)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPromotionResponse(promotion), nil
+}
+
+// GetPromotion implements PromotionService.
+func (s *promotionServiceImpl) GetPromotion(ctx context.Context, id string) (*dto.PromotionResponse, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPromotionResponse(promotion), nil
+}
+
+// ListPromotions implements PromotionService.
+func (s *promotionServiceImpl) ListPromotions(ctx context.Context, req *dto.ListPromotionsRequest) ([]*dto.PromotionResponse, int, error) {
+	filter := repository.PromotionFilter{Code: normalizeCode(req.Code)}
+
+	switch strings.TrimSpace(req.State) {
+	case "":
+	case "active":
+		active := true
+		filter.Active = &active
+	case "inactive":
+		active := false
+		filter.Active = &active
+	default:
+		return nil, 0, &ValidationError{Fields: map[string]string{"state": "must be active or inactive"}}
+	}
+
+	page, pageSize := pagination(req.Page, req.PageSize)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	promotions, total, err := s.promotionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		resp = append(resp, toPromotionResponse(promotion))
+	}
+
+	return resp, total, nil
+}
+
+// applyTerms validates terms against the promotion's type and copies them
+// onto it, recording problems in invalid.
+func applyTerms(invalid map[string]string, promotion *model.Promotion, terms *dto.PromotionTerms) {
+	name := strings.TrimSpace(terms.Name)
+	if name == "" || len(name) > maxNameLength {
+		invalid["terms.name"] = fmt.Sprintf("must be between 1 and %d characters", maxNameLength)
+	}
+
+	switch promotion.Type {
+	case model.PromotionPercentage:
+		if terms.Value < 1 || terms.Value > maxPercentageOff {
+			invalid["terms.value"] = fmt.Sprintf("must be a percentage between 1 and %d", maxPercentageOff)
+		}
+	case model.PromotionFixed:
+		if terms.Value <= 0 {
+			invalid["terms.value"] = "must be greater than zero"
+		}
+	case model.PromotionFreeShipping:
+		if terms.Value != 0 {
+			invalid["terms.value"] = "must be empty for free shipping"
+		}
+	}
+
+	if terms.MaxDiscount < 0 {
+		invalid["terms.max_discount"] = "must not be negative"
+	}
+	if terms.MinSubtotal < 0 {
+		invalid["terms.min_subtotal"] = "must not be negative"
+	}
+	if terms.UsageLimit < 0 {
+		invalid["terms.usage_limit"] = "must not be negative"
+	}
+	if terms.PerCustomerLimit < 0 {
+		invalid["terms.per_customer_limit"] = "must not be negative"
+	}
+
+	startsAt := time.Now().UTC()
+	if value := strings.TrimSpace(terms.StartsAt); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalid["terms.starts_at"] = "must be an RFC 3339 time"
+		}
+		startsAt = parsed.UTC()
+	}
+
+	var endsAt *time.Time
+	if value := strings.TrimSpace(terms.EndsAt); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		switch {
+		case err != nil:
+			invalid["terms.ends_at"] = "must be an RFC 3339 time"
+		case !parsed.After(startsAt):
+			invalid["terms.ends_at"] = "must be after starts_at"
+		default:
+			parsed = parsed.UTC()
+			endsAt = &parsed
+		}
+	}
+
+	productIDs := cleanTargets(invalid, "terms.product_ids", terms.ProductIDs)
+	categoryIDs := cleanTargets(invalid, "terms.category_ids", terms.CategoryIDs)
+
+	sellerIDs := make([]int64, 0, len(terms.SellerIDs))
+	seen := map[int64]bool{}
+	for _, id := range terms.SellerIDs {
+		if id <= 0 {
+			invalid["terms.seller_ids"] = "must be positive"
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			sellerIDs = append(sellerIDs, id)
+		}
+	}
+	if len(sellerIDs) > maxTargets {
+		invalid["terms.seller_ids"] = fmt.Sprintf("must have at most %d entries", maxTargets)
+	}
+
+	promotion.Name = name
+	promotion.Value = terms.Value
+	promotion.MaxDiscount = terms.MaxDiscount
+	promotion.MinSubtotal = terms.MinSubtotal
+	promotion.StartsAt = startsAt
+	promotion.EndsAt = endsAt
+	promotion.UsageLimit = terms.UsageLimit
+	promotion.PerCustomerLimit = terms.PerCustomerLimit
+	promotion.ProductIDs = productIDs
+	promotion.CategoryIDs = categoryIDs
+	promotion.SellerIDs = sellerIDs
+	promotion.Stackable = terms.Stackable
+	promotion.Priority = terms.Priority
+}
+
+// cleanTargets trims the ids and drops blanks and repeats.
+func cleanTargets(invalid map[string]string, field string, ids []string) []string {
+	cleaned := make([]string, 0, len(ids))
+	seen := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			cleaned = append(cleaned, id)
+		}
+	}
+	if len(cleaned) > maxTargets {
+		invalid[field] = fmt.Sprintf("must have at most %d entries", maxTargets)
+	}
+	return cleaned
+}
+
+func pagination(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+func toPromotionResponse(promotion *model.Promotion) *dto.PromotionResponse {
+	resp := &dto.PromotionResponse{
+		ID:               promotion.ID,
+		Code:             promotion.Code,
+		Name:             promotion.Name,
+		Type:             string(promotion.Type),
+		Value:            promotion.Value,
+		MaxDiscount:      promotion.MaxDiscount,
+		MinSubtotal:      promotion.MinSubtotal,
+		StartsAt:         promotion.StartsAt.Format(time.RFC3339),
+		UsageLimit:       promotion.UsageLimit,
+		PerCustomerLimit: promotion.PerCustomerLimit,
+		UsageCount:       promotion.UsageCount,
+		ProductIDs:       promotion.ProductIDs,
+		CategoryIDs:      promotion.CategoryIDs,
+		SellerIDs:        promotion.SellerIDs,
+		Stackable:        promotion.Stackable,
+		Priority:         promotion.Priority,
+		Active:           promotion.Active,
+		CreatedAt:        promotion.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        promotion.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if promotion.EndsAt != nil {
+		resp.EndsAt = promotion.EndsAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
@@ -0,0 +1,48 @@
+-- A promotion with a code is a coupon the customer enters; one without a
+-- code applies automatically to every cart it qualifies for.
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) UNIQUE,                  -- stored upper case, NULL for automatic promotions
+    name VARCHAR(200) NOT NULL,
+    type VARCHAR(20) NOT NULL,                -- percentage, fixed, free_shipping
+    value BIGINT NOT NULL DEFAULT 0,          -- percent off, or amount off in minor units
+    max_discount BIGINT NOT NULL DEFAULT 0,   -- 0 means no cap
+    min_subtotal BIGINT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    usage_limit INTEGER NOT NULL DEFAULT 0,   -- 0 means unlimited
+    per_customer_limit INTEGER NOT NULL DEFAULT 0,
+    usage_count INTEGER NOT NULL DEFAULT 0,   -- redemptions currently held
+    product_ids TEXT[] NOT NULL DEFAULT '{}',
+    category_ids TEXT[] NOT NULL DEFAULT '{}',
+    seller_ids BIGINT[] NOT NULL DEFAULT '{}',
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT promotions_type_check CHECK (type IN ('percentage', 'fixed', 'free_shipping')),
+    CONSTRAINT promotions_window_check CHECK (ends_at IS NULL OR ends_at > starts_at),
+    CONSTRAINT promotions_usage_check CHECK (usage_count >= 0 AND (usage_limit = 0 OR usage_count <= usage_limit))
+);
+
+-- One row per promotion a checkout used. Released rows stay for the record
+-- but no longer count against the limits.
+CREATE TABLE redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference VARCHAR(100) NOT NULL,          -- idempotency key, usually the checkout id
+    promotion_id UUID NOT NULL REFERENCES promotions(id),
+    code VARCHAR(32),
+    customer_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL,                   -- discount granted, in minor units
+    status VARCHAR(20) NOT NULL DEFAULT 'redeemed', -- redeemed, released
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP,
+    CONSTRAINT redemptions_reference_promotion UNIQUE (reference, promotion_id),
+    CONSTRAINT redemptions_status_check CHECK (status IN ('redeemed', 'released'))
+);
+
+-- Indexes
+CREATE INDEX idx_promotions_automatic ON promotions(priority) WHERE code IS NULL AND active;
+CREATE INDEX idx_redemptions_reference ON redemptions(reference);
+CREATE INDEX idx_redemptions_customer ON redemptions(promotion_id, customer_id) WHERE status = 'redeemed';
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/seller-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("seller-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	storeRepo := repository.NewStoreRepository(db)
+	storeService := service.NewStoreService(storeRepo)
+
+	storeHandler := handler.NewStoreHandler(storeService)
+
+	if err := pb.RegisterSellerServiceHandler(srv.Server(), storeHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
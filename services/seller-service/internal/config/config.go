@@ -0,0 +1,31 @@
+// services/seller-service/internal/config/config.go
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:        getEnv("PORT", "8086"),
+		Environment: os.Getenv("ENVIRONMENT"),
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
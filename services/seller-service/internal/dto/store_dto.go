@@ -0,0 +1,44 @@
+package dto
+
+type ApplyStoreRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Address     string `json:"address"`
+	Description string `json:"description"`
+}
+
+type UpdateStoreRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	Address     *string `json:"address"`
+	Description *string `json:"description"`
+}
+
+type ChangeStoreStatusRequest struct {
+	StoreID string `json:"store_id"`
+	Status  string `json:"status"`
+	ActorID int64  `json:"actor_id"`
+	Reason  string `json:"reason"`
+}
+
+type StoreResponse struct {
+	ID           string `json:"id"`
+	OwnerID      int64  `json:"owner_id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	PhoneNumber  string `json:"phone_number"`
+	Address      string `json:"address"`
+	Description  string `json:"description,omitempty"`
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type StoreStatusChangeResponse struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ActorID    int64  `json:"actor_id"`
+	Reason     string `json:"reason,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/seller-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "seller-service"
+
+type StoreHandler struct {
+	storeService service.StoreService
+}
+
+func NewStoreHandler(storeService service.StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+
+func (h *StoreHandler) ApplyStore(ctx context.Context, req *pb.ApplyStoreRequest, rsp *pb.StoreResponse) error {
+	store, err := h.storeService.ApplyStore(ctx, req.OwnerId, &dto.ApplyStoreRequest{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Address:     req.Address,
+		Description: req.Description,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Store = toProtoStore(store)
+	return nil
+}
+
+func (h *StoreHandler) GetMyStore(ctx context.Context, req *pb.GetMyStoreRequest, rsp *pb.StoreResponse) error {
+	store, err := h.storeService.GetMyStore(ctx, req.OwnerId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Store = toProtoStore(store)
+	return nil
+}
+
+func (h *StoreHandler) UpdateStore(ctx context.Context, req *pb.UpdateStoreRequest, rsp *pb.StoreResponse) error {
+	store, err := h.storeService.UpdateStore(ctx, req.OwnerId, &dto.UpdateStoreRequest{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Address:     req.Address,
+		Description: req.Description,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Store = toProtoStore(store)
+	return nil
+}
+
+func (h *StoreHandler) GetStore(ctx context.Context, req *pb.GetStoreRequest, rsp *pb.StoreResponse) error {
+	var (
+		store *dto.StoreResponse
+		err   error
+	)
+
+	if req.Slug != "" {
+		store, err = h.storeService.GetStoreBySlug(ctx, req.Slug)
+	} else {
+		store, err = h.storeService.GetStore(ctx, req.Id)
+	}
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Store = toProtoStore(store)
+	return nil
+}
+
+func (h *StoreHandler) ListStores(ctx context.Context, req *pb.ListStoresRequest, rsp *pb.ListStoresResponse) error {
+	stores, total, err := h.storeService.ListStores(ctx, req.Status, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, store := range stores {
+		rsp.Stores = append(rsp.Stores, toProtoStore(store))
+	}
+	rsp.Total = int32(total)
+
+	return nil
+}
+
+func (h *StoreHandler) ChangeStoreStatus(ctx context.Context, req *pb.ChangeStoreStatusRequest, rsp *pb.StoreResponse) error {
+	store, err := h.storeService.ChangeStoreStatus(ctx, &dto.ChangeStoreStatusRequest{
+		StoreID: req.StoreId,
+		Status:  req.Status,
+		ActorID: req.ActorId,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Store = toProtoStore(store)
+	return nil
+}
+
+func (h *StoreHandler) GetStoreHistory(ctx context.Context, req *pb.GetStoreHistoryRequest, rsp *pb.GetStoreHistoryResponse) error {
+	changes, err := h.storeService.GetStoreHistory(ctx, req.StoreId)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, change := range changes {
+		rsp.Changes = append(rsp.Changes, &pb.StoreStatusChange{
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			ActorId:    change.ActorID,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt,
+		})
+	}
+
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, repository.ErrStoreNotFound):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrStoreAlreadyExists),
+		errors.Is(err, repository.ErrSlugTaken),
+		errors.Is(err, repository.ErrStatusChanged),
+		errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, service.ErrStoreNotEditable):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoStore(store *dto.StoreResponse) *pb.Store {
+	return &pb.Store{
+		Id:           store.ID,
+		OwnerId:      store.OwnerID,
+		Name:         store.Name,
+		Slug:         store.Slug,
+		PhoneNumber:  store.PhoneNumber,
+		Address:      store.Address,
+		Description:  store.Description,
+		Status:       store.Status,
+		StatusReason: store.StatusReason,
+		CreatedAt:    store.CreatedAt,
+		UpdatedAt:    store.UpdatedAt,
+	}
+}
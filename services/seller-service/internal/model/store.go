@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+type StoreStatus string
+
+const (
+	StoreStatusApplied     StoreStatus = "applied"
+	StoreStatusUnderReview StoreStatus = "under_review"
+	StoreStatusApproved    StoreStatus = "approved"
+	StoreStatusRejected    StoreStatus = "rejected"
+	StoreStatusSuspended   StoreStatus = "suspended"
+)
+
+// storeTransitions is the onboarding state machine. A rejected store goes
+// back to applied when the seller edits and resubmits it.
+var storeTransitions = map[StoreStatus][]StoreStatus{
+	StoreStatusApplied:     {StoreStatusUnderReview},
+	StoreStatusUnderReview: {StoreStatusApproved, StoreStatusRejected},
+	StoreStatusRejected:    {StoreStatusApplied},
+	StoreStatusApproved:    {StoreStatusSuspended},
+	StoreStatusSuspended:   {StoreStatusApproved},
+}
+
+func (s StoreStatus) IsValid() bool {
+	_, ok := storeTransitions[s]
+	return ok
+}
+
+func (s StoreStatus) CanTransitionTo(next StoreStatus) bool {
+	for _, allowed := range storeTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Store struct {
+	ID           string      `db:"id"`
+	OwnerID      int64       `db:"owner_id"`
+	Name         string      `db:"name"`
+	Slug         string      `db:"slug"`
+	PhoneNumber  string      `db:"phone_number"`
+	Address      string      `db:"address"`
+	Description  string      `db:"description"`
+	Status       StoreStatus `db:"status"`
+	StatusReason string      `db:"status_reason"`
+	CreatedAt    time.Time   `db:"created_at"`
+	UpdatedAt    time.Time   `db:"updated_at"`
+}
+
+type StoreStatusChange struct {
+	ID         int64       `db:"id"`
+	StoreID    string      `db:"store_id"`
+	FromStatus StoreStatus `db:"from_status"`
+	ToStatus   StoreStatus `db:"to_status"`
+	ActorID    int64       `db:"actor_id"`
+	Reason     string      `db:"reason"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+func TestStoreStatusCanTransitionTo(t *testing.T) {
+	statuses := []StoreStatus{
+		StoreStatusApplied,
+		StoreStatusUnderReview,
+		StoreStatusApproved,
+		StoreStatusRejected,
+		StoreStatusSuspended,
+	}
+
+	allowed := map[[2]StoreStatus]bool{
+		{StoreStatusApplied, StoreStatusUnderReview}:  true,
+		{StoreStatusUnderReview, StoreStatusApproved}: true,
+		{StoreStatusUnderReview, StoreStatusRejected}: true,
+		{StoreStatusRejected, StoreStatusApplied}:     true,
+		{StoreStatusApproved, StoreStatusSuspended}:   true,
+		{StoreStatusSuspended, StoreStatusApproved}:   true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]StoreStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestStoreStatusIsValid(t *testing.T) {
+	tests := []struct {
+		status StoreStatus
+		want   bool
+	}{
+		{StoreStatusApplied, true},
+		{StoreStatusSuspended, true},
+		{"pending", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsValid(); got != tt.want {
+			t.Errorf("StoreStatus(%q).IsValid() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
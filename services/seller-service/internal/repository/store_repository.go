@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/model"
+)
+
+var (
+	ErrStoreNotFound      = errors.New("store not found")
+	ErrStoreAlreadyExists = errors.New("seller already has a store")
+	ErrSlugTaken          = errors.New("store slug already taken")
+	ErrStatusChanged      = errors.New("store status changed concurrently")
+)
+
+type StoreRepository interface {
+	// Create inserts the store and records its initial status in the history
+	// table in the same transaction, so the audit trail starts at the
+	// application.
+	Create(ctx context.Context, store *model.Store) error
+	Update(ctx context.Context, store *model.Store) error
+	GetByID(ctx context.Context, id string) (*model.Store, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Store, error)
+	GetByOwner(ctx context.Context, ownerID int64) (*model.Store, error)
+	List(ctx context.Context, status model.StoreStatus, limit, offset int) ([]*model.Store, int, error)
+
+	// UpdateStatus moves the store from one status to another and records the
+	// change in the history table. It fails with ErrStatusChanged if the store
+	// is no longer in the from status.
+	UpdateStatus(ctx context.Context, change *model.StoreStatusChange) error
+	ListStatusHistory(ctx context.Context, storeID string) ([]*model.StoreStatusChange, error)
+}
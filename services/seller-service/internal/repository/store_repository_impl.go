@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const storeColumns = `
+	id, owner_id, name, slug, phone_number, address, COALESCE(description, ''),
+	status, COALESCE(status_reason, ''), created_at, updated_at
+`
+
+type storeRepository struct {
+	db *sql.DB
+}
+
+func NewStoreRepository(db *sql.DB) StoreRepository {
+	return &storeRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStore(row rowScanner) (*model.Store, error) {
+	var store model.Store
+	err := row.Scan(
+		&store.ID,
+		&store.OwnerID,
+		&store.Name,
+		&store.Slug,
+		&store.PhoneNumber,
+		&store.Address,
+		&store.Description,
+		&store.Status,
+		&store.StatusReason,
+		&store.CreatedAt,
+		&store.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &store, nil
+}
+
+func (r *storeRepository) Create(ctx context.Context, store *model.Store) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO stores (owner_id, name, slug, phone_number, address, description, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		store.OwnerID,
+		store.Name,
+		store.Slug,
+		store.PhoneNumber,
+		store.Address,
+		store.Description,
+		store.Status,
+	).Scan(&store.ID, &store.CreatedAt, &store.UpdatedAt)
+	if err != nil {
+		return translateUniqueViolation(err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO store_status_history (store_id, to_status, actor_id, reason) VALUES ($1, $2, $3, $4)`,
+		store.ID, store.Status, store.OwnerID, "applied by seller",
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *storeRepository) Update(ctx context.Context, store *model.Store) error {
+	query := `
+		UPDATE stores
+		SET name = $2, slug = $3, phone_number = $4, address = $5, description = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		store.ID,
+		store.Name,
+		store.Slug,
+		store.PhoneNumber,
+		store.Address,
+		store.Description,
+	).Scan(&store.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrStoreNotFound
+	}
+	if err != nil {
+		return translateUniqueViolation(err)
+	}
+
+	return nil
+}
+
+func (r *storeRepository) GetByID(ctx context.Context, id string) (*model.Store, error) {
+	return r.getOne(ctx, `SELECT `+storeColumns+` FROM stores WHERE id::text = $1`, id)
+}
+
+func (r *storeRepository) GetBySlug(ctx context.Context, slug string) (*model.Store, error) {
+	return r.getOne(ctx, `SELECT `+storeColumns+` FROM stores WHERE slug = $1`, slug)
+}
+
+func (r *storeRepository) GetByOwner(ctx context.Context, ownerID int64) (*model.Store, error) {
+	return r.getOne(ctx, `SELECT `+storeColumns+` FROM stores WHERE owner_id = $1`, ownerID)
+}
+
+func (r *storeRepository) List(ctx context.Context, status model.StoreStatus, limit, offset int) ([]*model.Store, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM stores WHERE $1 = '' OR status = $1`, status,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + storeColumns + `
+		FROM stores
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var stores []*model.Store
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		stores = append(stores, store)
+	}
+
+	return stores, total, rows.Err()
+}
+
+func (r *storeRepository) UpdateStatus(ctx context.Context, change *model.StoreStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE stores SET status = $3, status_reason = $4, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`, change.StoreID, change.FromStatus, change.ToStatus, change.Reason)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrStatusChanged
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO store_status_history (store_id, from_status, to_status, actor_id, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, change.StoreID, change.FromStatus, change.ToStatus, change.ActorID, change.Reason,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *storeRepository) ListStatusHistory(ctx context.Context, storeID string) ([]*model.StoreStatusChange, error) {
+	query := `
+		SELECT id, store_id, COALESCE(from_status, ''), to_status, actor_id, COALESCE(reason, ''), created_at
+		FROM store_status_history
+		WHERE store_id::text = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.StoreStatusChange
+	for rows.Next() {
+		var change model.StoreStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.StoreID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.ActorID,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+func (r *storeRepository) getOne(ctx context.Context, query string, args ...interface{}) (*model.Store, error) {
+	store, err := scanStore(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrStoreNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func translateUniqueViolation(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23505" {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "stores_owner_id_key":
+		return ErrStoreAlreadyExists
+	case "stores_slug_key":
+		return ErrSlugTaken
+	default:
+		return err
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/dto"
+)
+
+var (
+	ErrInvalidTransition = errors.New("store status transition not allowed")
+	ErrStoreNotEditable  = errors.New("store cannot be edited in its current status")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// StoreService handles seller onboarding and store management
+type StoreService interface {
+	ApplyStore(ctx context.Context, ownerID int64, req *dto.ApplyStoreRequest) (*dto.StoreResponse, error)
+	GetMyStore(ctx context.Context, ownerID int64) (*dto.StoreResponse, error)
+	UpdateStore(ctx context.Context, ownerID int64, req *dto.UpdateStoreRequest) (*dto.StoreResponse, error)
+	GetStore(ctx context.Context, id string) (*dto.StoreResponse, error)
+	GetStoreBySlug(ctx context.Context, slug string) (*dto.StoreResponse, error)
+	ListStores(ctx context.Context, status string, page, pageSize int) ([]*dto.StoreResponse, int, error)
+
+	// ChangeStoreStatus is used by admins to drive the onboarding state machine.
+	ChangeStoreStatus(ctx context.Context, req *dto.ChangeStoreStatusRequest) (*dto.StoreResponse, error)
+	GetStoreHistory(ctx context.Context, storeID string) ([]*dto.StoreStatusChangeResponse, error)
+}
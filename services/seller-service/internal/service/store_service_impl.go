@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/seller-service/internal/repository"
+)
+
+const (
+	defaultPageSize  = 20
+	maxPageSize      = 100
+	maxSlugAttempts  = 10
+	maxDescriptionSz = 2000
+)
+
+var (
+	phoneNumberPattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	nonSlugChars       = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type storeServiceImpl struct {
+	storeRepo repository.StoreRepository
+}
+
+func NewStoreService(storeRepo repository.StoreRepository) StoreService {
+	return &storeServiceImpl{
+		storeRepo: storeRepo,
+	}
+}
+
+// ApplyStore implements StoreService.
+func (s *storeServiceImpl) ApplyStore(ctx context.Context, ownerID int64, req *dto.ApplyStoreRequest) (*dto.StoreResponse, error) {
+	store := &model.Store{
+		OwnerID:     ownerID,
+		Name:        strings.TrimSpace(req.Name),
+		PhoneNumber: strings.TrimSpace(req.PhoneNumber),
+		Address:     strings.TrimSpace(req.Address),
+		Description: strings.TrimSpace(req.Description),
+		Status:      model.StoreStatusApplied,
+	}
+
+	if err := validateStore(store); err != nil {
+		return nil, err
+	}
+
+	if err := s.createWithUniqueSlug(ctx, store); err != nil {
+		return nil, err
+	}
+
+	return toStoreResponse(store), nil
+}
+
+// GetMyStore implements StoreService.
+func (s *storeServiceImpl) GetMyStore(ctx context.Context, ownerID int64) (*dto.StoreResponse, error) {
+	store, err := s.storeRepo.GetByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStoreResponse(store), nil
+}
+
+// UpdateStore implements StoreService.
+func (s *storeServiceImpl) UpdateStore(ctx context.Context, ownerID int64, req *dto.UpdateStoreRequest) (*dto.StoreResponse, error) {
+	store, err := s.storeRepo.GetByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if store.Status == model.StoreStatusSuspended || store.Status == model.StoreStatusUnderReview {
+		return nil, ErrStoreNotEditable
+	}
+
+	renamed := false
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		renamed = name != store.Name
+		store.Name = name
+	}
+	if req.PhoneNumber != nil {
+		store.PhoneNumber = strings.TrimSpace(*req.PhoneNumber)
+	}
+	if req.Address != nil {
+		store.Address = strings.TrimSpace(*req.Address)
+	}
+	if req.Description != nil {
+		store.Description = strings.TrimSpace(*req.Description)
+	}
+
+	if err := validateStore(store); err != nil {
+		return nil, err
+	}
+
+	// Approved stores keep their slug so that links to them stay valid.
+	if renamed && store.Status != model.StoreStatusApproved {
+		store.Slug, err = s.uniqueSlug(ctx, store.Name, store.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.storeRepo.Update(ctx, store); err != nil {
+		return nil, err
+	}
+
+	// Editing a rejected application resubmits it.
+	if store.Status == model.StoreStatusRejected {
+		return s.transition(ctx, store, model.StoreStatusApplied, ownerID, "resubmitted by seller")
+	}
+
+	return toStoreResponse(store), nil
+}
+
+// GetStore implements StoreService.
+func (s *storeServiceImpl) GetStore(ctx context.Context, id string) (*dto.StoreResponse, error) {
+	store, err := s.storeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStoreResponse(store), nil
+}
+
+// GetStoreBySlug implements StoreService.
+func (s *storeServiceImpl) GetStoreBySlug(ctx context.Context, slug string) (*dto.StoreResponse, error) {
+	store, err := s.storeRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStoreResponse(store), nil
+}
+
+// ListStores implements StoreService.
+func (s *storeServiceImpl) ListStores(ctx context.Context, status string, page, pageSize int) ([]*dto.StoreResponse, int, error) {
+	storeStatus := model.StoreStatus(status)
+	if status != "" && !storeStatus.IsValid() {
+		return nil, 0, &ValidationError{Fields: map[string]string{"status": "unknown store status"}}
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		pageSize = defaultPageSize
+	}
+
+	stores, total, err := s.storeRepo.List(ctx, storeStatus, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.StoreResponse, 0, len(stores))
+	for _, store := range stores {
+		resp = append(resp, toStoreResponse(store))
+	}
+
+	return resp, total, nil
+}
+
+// ChangeStoreStatus implements StoreService.
+func (s *storeServiceImpl) ChangeStoreStatus(ctx context.Context, req *dto.ChangeStoreStatusRequest) (*dto.StoreResponse, error) {
+	next := model.StoreStatus(req.Status)
+	if !next.IsValid() {
+		return nil, &ValidationError{Fields: map[string]string{"status": "unknown store status"}}
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if (next == model.StoreStatusRejected || next == model.StoreStatusSuspended) && reason == "" {
+		return nil, &ValidationError{Fields: map[string]string{"reason": "is required when rejecting or suspending a store"}}
+	}
+
+	store, err := s.storeRepo.GetByID(ctx, req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transition(ctx, store, next, req.ActorID, reason)
+}
+
+// GetStoreHistory implements StoreService.
+func (s *storeServiceImpl) GetStoreHistory(ctx context.Context, storeID string) ([]*dto.StoreStatusChangeResponse, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.storeRepo.ListStatusHistory(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.StoreStatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, &dto.StoreStatusChangeResponse{
+			FromStatus: string(change.FromStatus),
+			ToStatus:   string(change.ToStatus),
+			ActorID:    change.ActorID,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *storeServiceImpl) transition(ctx context.Context, store *model.Store, next model.StoreStatus, actorID int64, reason string) (*dto.StoreResponse, error) {
+	if !store.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, store.Status, next)
+	}
+
+	change := &model.StoreStatusChange{
+		StoreID:    store.ID,
+		FromStatus: store.Status,
+		ToStatus:   next,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+
+	if err := s.storeRepo.UpdateStatus(ctx, change); err != nil {
+		return nil, err
+	}
+
+	store.Status = next
+	store.StatusReason = reason
+	store.UpdatedAt = change.CreatedAt
+
+	return toStoreResponse(store), nil
+}
+
+func (s *storeServiceImpl) createWithUniqueSlug(ctx context.Context, store *model.Store) error {
+	base := slugify(store.Name)
+
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		store.Slug = slugCandidate(base, attempt)
+
+		err := s.storeRepo.Create(ctx, store)
+		if !errors.Is(err, repository.ErrSlugTaken) {
+			return err
+		}
+	}
+
+	return repository.ErrSlugTaken
+}
+
+func (s *storeServiceImpl) uniqueSlug(ctx context.Context, name, storeID string) (string, error) {
+	base := slugify(name)
+
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		candidate := slugCandidate(base, attempt)
+
+		existing, err := s.storeRepo.GetBySlug(ctx, candidate)
+		if errors.Is(err, repository.ErrStoreNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if existing.ID == storeID {
+			return candidate, nil
+		}
+	}
+
+	return "", repository.ErrSlugTaken
+}
+
+func slugify(name string) string {
+	slug := nonSlugChars.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		slug = "store"
+	}
+	if len(slug) > 100 {
+		slug = strings.TrimRight(slug[:100], "-")
+	}
+	return slug
+}
+
+func slugCandidate(base string, attempt int) string {
+	if attempt == 1 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, attempt)
+}
+
+func validateStore(store *model.Store) error {
+	fields := map[string]string{}
+
+	if len(store.Name) < 3 || len(store.Name) > 100 {
+		fields["name"] = "must be between 3 and 100 characters"
+	}
+	if !phoneNumberPattern.MatchString(store.PhoneNumber) {
+		fields["phone_number"] = "must be in E.164 format, e.g. +6281234567890"
+	}
+	if store.Address == "" {
+		fields["address"] = "is required"
+	}
+	if len(store.Description) > maxDescriptionSz {
+		fields["description"] = fmt.Sprintf("must be at most %d characters", maxDescriptionSz)
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func toStoreResponse(store *model.Store) *dto.StoreResponse {
+	return &dto.StoreResponse{
+		ID:           store.ID,
+		OwnerID:      store.OwnerID,
+		Name:         store.Name,
+		Slug:         store.Slug,
+		PhoneNumber:  store.PhoneNumber,
+		Address:      store.Address,
+		Description:  store.Description,
+		Status:       string(store.Status),
+		StatusReason: store.StatusReason,
+		CreatedAt:    store.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    store.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/carrier"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/shipping-service/proto"
+
+	_ "github.com/lib/pq"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	if cfg.WebhookSecret == "" {
+		logger.Fatal("SHIPPING_WEBHOOK_SECRET must be set")
+	}
+
+	tables, err := carrier.LoadTables(cfg.CarriersDir)
+	if err != nil {
+		logger.Fatal("Failed to load carrier rate tables: ", err)
+	}
+
+	var carriers []carrier.Carrier
+	for _, table := range tables {
+		if table.Currency != cfg.Currency {
+			logger.Fatalf("Carrier %s quotes in %s, not %s", table.Carrier, table.Currency, cfg.Currency)
+		}
+		carriers = append(carriers, carrier.NewTableRateCarrier(table, cfg.WebhookSecret))
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	srv := micro.NewService(
+		micro.Name("shipping-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	shippingService := service.NewShippingService(
+		repository.NewShipmentRepository(db),
+		carriers,
+		client.NewProductCatalog(cfg.ProductServiceName, srv.Client()),
+		client.NewOrders(cfg.OrderServiceName, srv.Client()),
+	)
+
+	shippingHandler := handler.NewShippingHandler(shippingService)
+
+	if err := pb.RegisterShippingServiceHandler(srv.Server(), shippingHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,89 @@
+package carrier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrUnknownService   = errors.New("carrier does not offer this service")
+	ErrNotServed        = errors.New("carrier does not deliver this parcel to this address")
+)
+
+// RateRequest asks what it costs to send a parcel to an address.
+type RateRequest struct {
+	Country     string
+	Region      string
+	WeightGrams int
+}
+
+// Rate is the price of one carrier service for a parcel.
+type Rate struct {
+	Service  string
+	Name     string
+	Amount   int64
+	Currency string
+	MinDays  int
+	MaxDays  int
+}
+
+// LabelRequest books a parcel with the carrier. Reference is unique per
+// shipment and doubles as the carrier-side idempotency key.
+type LabelRequest struct {
+	Reference   string
+	Service     string
+	WeightGrams int
+	Address     model.Address
+}
+
+type Label struct {
+	TrackingNumber string
+	TrackingURL    string
+}
+
+// TrackingEvent is a verified notification from a carrier about one of its
+// parcels.
+type TrackingEvent struct {
+	ID             string
+	TrackingNumber string
+	Status         model.ShipmentStatus
+	Description    string
+	Location       string
+	OccurredAt     time.Time
+}
+
+// Carrier is implemented once per delivery company. An error from
+// CreateLabel means the booking may not have happened and can be retried
+// with the same reference.
+type Carrier interface {
+	Name() string
+
+	// Rates lists the services that take the parcel to the address. An
+	// address or weight the carrier does not serve gives no rates.
+	Rates(ctx context.Context, req *RateRequest) ([]Rate, error)
+	CreateLabel(ctx context.Context, req *LabelRequest) (*Label, error)
+
+	// VerifyWebhook checks the signature of a tracking webhook and decodes
+	// it. It returns ErrInvalidSignature for forged or stale requests.
+	VerifyWebhook(payload []byte, headers map[string]string) (*TrackingEvent, error)
+}
+
+// Option names a carrier service as customers pick it, e.g.
+// "micro-post:express".
+func Option(carrier, service string) string {
+	return carrier + ":" + service
+}
+
+// ParseOption splits an option into its carrier and service.
+func ParseOption(option string) (string, string, bool) {
+	carrier, service, ok := strings.Cut(option, ":")
+	if !ok || carrier == "" || service == "" {
+		return "", "", false
+	}
+	return carrier, service, true
+}
@@ -0,0 +1,374 @@
+package carrier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of
+	// "<t>.<payload>">" on tracking webhooks of table-rate carriers.
+	SignatureHeader = "Carrier-Signature"
+
+	signatureTolerance = 5 * time.Minute
+	gramsPerKilogram   = 1000
+)
+
+var codePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,29}$`)
+
+// Bracket prices parcels up to MaxGrams.
+type Bracket struct {
+	MaxGrams int   `json:"max_grams"`
+	Amount   int64 `json:"amount"`
+}
+
+// ZoneRate is what a service costs in one zone. Parcels heavier than the
+// last bracket pay ExtraPerKg for every started kilogram over it, or are
+// refused when ExtraPerKg is zero.
+type ZoneRate struct {
+	MinDays    int       `json:"min_days"`
+	MaxDays    int       `json:"max_days"`
+	Brackets   []Bracket `json:"brackets"`
+	ExtraPerKg int64     `json:"extra_per_kg"`
+}
+
+// Service is a delivery speed such as standard or express, with its rate
+// in every zone it serves. MaxGrams caps the parcel weight; zero means no
+// cap.
+type Service struct {
+	Code     string               `json:"code"`
+	Name     string               `json:"name"`
+	MaxGrams int                  `json:"max_grams"`
+	Rates    map[string]*ZoneRate `json:"rates"`
+}
+
+// Zone groups destinations priced alike. A destination is a country code
+// ("ID"), a country and region ("ID/Papua") or "*" for anywhere else; the
+// most specific match wins.
+type Zone struct {
+	Code         string   `json:"code"`
+	Destinations []string `json:"destinations"`
+}
+
+// Table is the rate card of a carrier that prices by weight and zone.
+// TrackingURL may hold a %s for the tracking number.
+type Table struct {
+	Carrier        string     `json:"carrier"`
+	Name           string     `json:"name"`
+	Currency       string     `json:"currency"`
+	TrackingPrefix string     `json:"tracking_prefix"`
+	TrackingURL    string     `json:"tracking_url"`
+	Zones          []*Zone    `json:"zones"`
+	Services       []*Service `json:"services"`
+}
+
+// LoadTables reads every .json file in dir as a carrier rate table.
+func LoadTables(dir string) ([]*Table, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list rate tables: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no rate tables found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	var tables []*Table
+	seen := map[string]bool{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read rate table: %w", err)
+		}
+
+		table, err := ParseTable(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if seen[table.Carrier] {
+			return nil, fmt.Errorf("carrier %s is defined twice", table.Carrier)
+		}
+		seen[table.Carrier] = true
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func ParseTable(data []byte) (*Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("parse rate table: %w", err)
+	}
+
+	if !codePattern.MatchString(table.Carrier) {
+		return nil, fmt.Errorf("rate table: invalid carrier code %q", table.Carrier)
+	}
+	if len(table.Currency) != 3 {
+		return nil, fmt.Errorf("rate table %s: currency must be an ISO 4217 code", table.Carrier)
+	}
+	if table.TrackingPrefix == "" {
+		return nil, fmt.Errorf("rate table %s: tracking_prefix is required", table.Carrier)
+	}
+
+	zones := map[string]bool{}
+	for _, zone := range table.Zones {
+		if zone.Code == "" || zones[zone.Code] {
+			return nil, fmt.Errorf("rate table %s: zone codes must be set and unique", table.Carrier)
+		}
+		zones[zone.Code] = true
+	}
+
+	services := map[string]bool{}
+	for _, service := range table.Services {
+		if !codePattern.MatchString(service.Code) || services[service.Code] {
+			return nil, fmt.Errorf("rate table %s: service codes must be valid and unique", table.Carrier)
+		}
+		services[service.Code] = true
+
+		for zone, rate := range service.Rates {
+			if !zones[zone] {
+				return nil, fmt.Errorf("rate table %s: %s: unknown zone %q", table.Carrier, service.Code, zone)
+			}
+			if err := validateZoneRate(rate); err != nil {
+				return nil, fmt.Errorf("rate table %s: %s: %s: %w", table.Carrier, service.Code, zone, err)
+			}
+		}
+	}
+
+	return &table, nil
+}
+
+func validateZoneRate(rate *ZoneRate) error {
+	if len(rate.Brackets) == 0 {
+		return fmt.Errorf("at least one bracket is required")
+	}
+	for i, bracket := range rate.Brackets {
+		if bracket.MaxGrams <= 0 || bracket.Amount < 0 {
+			return fmt.Errorf("brackets need a positive max_grams and an amount of at least zero")
+		}
+		if i > 0 && bracket.MaxGrams <= rate.Brackets[i-1].MaxGrams {
+			return fmt.Errorf("brackets must be ordered by max_grams")
+		}
+	}
+	if rate.ExtraPerKg < 0 {
+		return fmt.Errorf("extra_per_kg must not be negative")
+	}
+	if rate.MinDays < 0 || rate.MaxDays < rate.MinDays {
+		return fmt.Errorf("max_days must be at least min_days")
+	}
+	return nil
+}
+
+type tableRateCarrier struct {
+	table  *Table
+	secret []byte
+}
+
+// NewTableRateCarrier prices parcels from table. Labels are numbered by the
+// carrier's prefix and a hash of the reference, so booking the same
+// shipment twice gives the same tracking number. Tracking webhooks must be
+// signed with secret.
+func NewTableRateCarrier(table *Table, secret string) Carrier {
+	return &tableRateCarrier{
+		table:  table,
+		secret: []byte(secret),
+	}
+}
+
+func (c *tableRateCarrier) Name() string {
+	return c.table.Carrier
+}
+
+func (c *tableRateCarrier) Rates(ctx context.Context, req *RateRequest) ([]Rate, error) {
+	zone := c.zone(req.Country, req.Region)
+	if zone == "" {
+		return nil, nil
+	}
+
+	var rates []Rate
+	for _, service := range c.table.Services {
+		rate, ok := service.Rates[zone]
+		if !ok || (service.MaxGrams > 0 && req.WeightGrams > service.MaxGrams) {
+			continue
+		}
+
+		amount, ok := rate.price(req.WeightGrams)
+		if !ok {
+			continue
+		}
+
+		rates = append(rates, Rate{
+			Service:  service.Code,
+			Name:     c.table.Name + " " + service.Name,
+			Amount:   amount,
+			Currency: c.table.Currency,
+			MinDays:  rate.MinDays,
+			MaxDays:  rate.MaxDays,
+		})
+	}
+
+	return rates, nil
+}
+
+func (c *tableRateCarrier) CreateLabel(ctx context.Context, req *LabelRequest) (*Label, error) {
+	known := false
+	for _, service := range c.table.Services {
+		known = known || service.Code == req.Service
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownService, Option(c.table.Carrier, req.Service))
+	}
+
+	rates, err := c.Rates(ctx, &RateRequest{
+		Country:     req.Address.Country,
+		Region:      req.Address.Region,
+		WeightGrams: req.WeightGrams,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	offered := false
+	for _, rate := range rates {
+		offered = offered || rate.Service == req.Service
+	}
+	if !offered {
+		return nil, fmt.Errorf("%w: %s", ErrNotServed, Option(c.table.Carrier, req.Service))
+	}
+
+	sum := sha256.Sum256([]byte(c.table.Carrier + ":" + req.Reference))
+	number := c.table.TrackingPrefix + strings.ToUpper(hex.EncodeToString(sum[:6]))
+
+	label := &Label{TrackingNumber: number}
+	if c.table.TrackingURL != "" {
+		label.TrackingURL = strings.ReplaceAll(c.table.TrackingURL, "%s", number)
+	}
+
+	return label, nil
+}
+
+type trackingWebhook struct {
+	ID             string    `json:"id"`
+	TrackingNumber string    `json:"tracking_number"`
+	Status         string    `json:"status"`
+	Description    string    `json:"description"`
+	Location       string    `json:"location"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+func (c *tableRateCarrier) VerifyWebhook(payload []byte, headers map[string]string) (*TrackingEvent, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(headerValue(headers, SignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(c.sign(timestamp, payload))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var webhook trackingWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("%s: malformed webhook: %w", c.table.Carrier, err)
+	}
+
+	status := model.ShipmentStatus(webhook.Status)
+	if webhook.ID == "" || webhook.TrackingNumber == "" || !status.IsValid() {
+		return nil, fmt.Errorf("%s: webhook needs an id, a tracking number and a known status", c.table.Carrier)
+	}
+	if webhook.OccurredAt.IsZero() {
+		webhook.OccurredAt = time.Now()
+	}
+
+	return &TrackingEvent{
+		ID:             webhook.ID,
+		TrackingNumber: webhook.TrackingNumber,
+		Status:         status,
+		Description:    webhook.Description,
+		Location:       webhook.Location,
+		OccurredAt:     webhook.OccurredAt.UTC(),
+	}, nil
+}
+
+// zone returns the code of the zone the address falls in, or "" if the
+// carrier does not deliver there.
+func (c *tableRateCarrier) zone(country, region string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.TrimSpace(region)
+
+	candidates := []string{country, "*"}
+	if region != "" {
+		candidates = append([]string{country + "/" + region}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		for _, zone := range c.table.Zones {
+			for _, destination := range zone.Destinations {
+				if strings.EqualFold(destination, candidate) {
+					return zone.Code
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (c *tableRateCarrier) sign(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// price returns the rate for a parcel of weight grams, or false if it is
+// too heavy.
+func (r *ZoneRate) price(weight int) (int64, bool) {
+	for _, bracket := range r.Brackets {
+		if weight <= bracket.MaxGrams {
+			return bracket.Amount, true
+		}
+	}
+	if r.ExtraPerKg == 0 {
+		return 0, false
+	}
+
+	last := r.Brackets[len(r.Brackets)-1]
+	extraKg := (weight - last.MaxGrams + gramsPerKilogram - 1) / gramsPerKilogram
+	return last.Amount + int64(extraKg)*r.ExtraPerKg, true
+}
+
+// headerValue looks a header up ignoring case, as proxies may change it.
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package carrier
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+)
+
+func TestTableRateVerifyWebhook(t *testing.T) {
+	table := &Table{Carrier: "acme"}
+	c := NewTableRateCarrier(table, "whsec_test").(*tableRateCarrier)
+	other := NewTableRateCarrier(table, "whsec_other").(*tableRateCarrier)
+
+	payload := []byte(`{"id":"evt_1","tracking_number":"ACME123","status":"in_transit","occurred_at":"2026-01-02T03:04:05Z"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-2*signatureTolerance).Unix(), 10)
+
+	sign := func(c *tableRateCarrier, timestamp string, payload []byte) string {
+		return "t=" + timestamp + ",v1=" + c.sign(timestamp, payload)
+	}
+	unknownStatus := []byte(strings.Replace(string(payload), "in_transit", "teleported", 1))
+
+	tests := []struct {
+		name          string
+		header        string
+		payload       []byte
+		wantValid     bool
+		wantMalformed bool
+	}{
+		{name: "valid", header: sign(c, now, payload), wantValid: true},
+		{name: "missing header", header: ""},
+		{name: "missing signature", header: "t=" + now},
+		{name: "other secret", header: sign(other, now, payload)},
+		{name: "tampered payload", header: sign(c, now, payload), payload: []byte(strings.Replace(string(payload), "in_transit", "delivered", 1))},
+		{name: "stale", header: sign(c, stale, payload)},
+		{name: "signed with unknown status", header: sign(c, now, unknownStatus), payload: unknownStatus, wantMalformed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := payload
+			if tt.payload != nil {
+				body = tt.payload
+			}
+
+			event, err := c.VerifyWebhook(body, map[string]string{SignatureHeader: tt.header})
+			switch {
+			case tt.wantValid:
+				if err != nil {
+					t.Fatalf("VerifyWebhook() error = %v", err)
+				}
+				if event.ID != "evt_1" || event.TrackingNumber != "ACME123" || event.Status != model.ShipmentInTransit {
+					t.Errorf("VerifyWebhook() = %+v", event)
+				}
+			case tt.wantMalformed:
+				// Signed but unusable: rejected, though not as forged.
+				if err == nil || errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("VerifyWebhook() error = %v, want a malformed webhook error", err)
+				}
+			default:
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("VerifyWebhook() error = %v, want %v", err, ErrInvalidSignature)
+				}
+			}
+		})
+	}
+}
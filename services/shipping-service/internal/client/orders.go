@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+
+	orderpb "github.com/Dzaakk/micro-commerce/services/order-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+// systemActor is how the shipping service identifies itself in order
+// history.
+const systemActor = "system"
+
+type orders struct {
+	orders orderpb.OrderService
+}
+
+// NewOrders reads and updates orders through the order service registered
+// under name.
+func NewOrders(name string, c client.Client) service.Orders {
+	return &orders{
+		orders: orderpb.NewOrderService(name, c),
+	}
+}
+
+func (o *orders) GetOrder(ctx context.Context, id string, sellerID int64) (*model.OrderDetails, error) {
+	resp, err := o.orders.GetOrder(ctx, &orderpb.GetOrderRequest{Id: id, SellerId: sellerID})
+	if err != nil {
+		return nil, err
+	}
+
+	order := resp.Order
+	details := &model.OrderDetails{
+		ID:             order.Id,
+		CustomerID:     order.CustomerId,
+		SellerID:       order.SellerId,
+		Status:         order.Status,
+		ShippingOption: order.ShippingOption,
+	}
+	if address := order.ShippingAddress; address != nil {
+		details.ShippingAddress = model.Address{
+			RecipientName: address.RecipientName,
+			PhoneNumber:   address.PhoneNumber,
+			Line1:         address.Line1,
+			Line2:         address.Line2,
+			City:          address.City,
+			Region:        address.Region,
+			PostalCode:    address.PostalCode,
+			Country:       address.Country,
+		}
+	}
+	for _, item := range order.Items {
+		details.Items = append(details.Items, model.OrderItem{
+			SKU:      item.Sku,
+			Quantity: int(item.Quantity),
+		})
+	}
+
+	return details, nil
+}
+
+func (o *orders) ChangeStatus(ctx context.Context, id, status, reason string) error {
+	_, err := o.orders.ChangeOrderStatus(ctx, &orderpb.ChangeOrderStatusRequest{
+		Id:        id,
+		Status:    status,
+		ActorRole: systemActor,
+		Reason:    reason,
+	})
+	return err
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	productpb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+type productCatalog struct {
+	products productpb.ProductService
+}
+
+// NewProductCatalog looks SKUs up through the product service registered
+// under name.
+func NewProductCatalog(name string, c client.Client) service.ProductCatalog {
+	return &productCatalog{
+		products: productpb.NewProductService(name, c),
+	}
+}
+
+func (p *productCatalog) LookupSKUs(ctx context.Context, skus []string) (map[string]*model.SKUDetails, error) {
+	resp, err := p.products.LookupSKUs(ctx, &productpb.LookupSKUsRequest{Skus: skus})
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]*model.SKUDetails, len(resp.Items))
+	for _, item := range resp.Items {
+		details[item.Sku] = &model.SKUDetails{
+			SKU:         item.Sku,
+			SellerID:    item.SellerId,
+			WeightGrams: int(item.WeightGrams),
+		}
+	}
+
+	return details, nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+	DatabaseURL string
+
+	// Directory holding one JSON rate table per table-rate carrier.
+	CarriersDir string
+	// Secret carriers sign their tracking webhooks with.
+	WebhookSecret string
+	// Currency rates are quoted in; it must match the checkout's.
+	Currency string
+
+	// Registry names of the services the shipping service calls.
+	ProductServiceName string
+	OrderServiceName   string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:               getEnv("PORT", "8094"),
+		Environment:        os.Getenv("ENVIRONMENT"),
+		DatabaseURL:        os.Getenv("DATABASE_URL"),
+		CarriersDir:        getEnv("CARRIERS_DIR", "data/carriers"),
+		WebhookSecret:      os.Getenv("SHIPPING_WEBHOOK_SECRET"),
+		Currency:           getEnv("CURRENCY", "IDR"),
+		ProductServiceName: getEnv("PRODUCT_SERVICE_NAME", "product-service"),
+		OrderServiceName:   getEnv("ORDER_SERVICE_NAME", "order-service"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,87 @@
+package dto
+
+type QuoteLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type QuoteRatesRequest struct {
+	Country string      `json:"country"`
+	Region  string      `json:"region"`
+	Lines   []QuoteLine `json:"lines"`
+}
+
+type RateResponse struct {
+	Option   string `json:"option"`
+	Carrier  string `json:"carrier"`
+	Service  string `json:"service"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	MinDays  int    `json:"min_days"`
+	MaxDays  int    `json:"max_days"`
+}
+
+type ParcelResponse struct {
+	SellerID    int64           `json:"seller_id"`
+	SKUs        []string        `json:"skus"`
+	WeightGrams int             `json:"weight_grams"`
+	Rates       []*RateResponse `json:"rates"`
+}
+
+type QuoteRatesResponse struct {
+	Parcels []*ParcelResponse `json:"parcels"`
+	Options []*RateResponse   `json:"options"`
+}
+
+type CreateShipmentRequest struct {
+	OrderID     string `json:"order_id"`
+	SellerID    int64  `json:"seller_id"`
+	Option      string `json:"option"`
+	WeightGrams int    `json:"weight_grams"`
+}
+
+type ListShipmentsRequest struct {
+	OrderID    string `json:"order_id"`
+	CustomerID int64  `json:"customer_id"`
+	SellerID   int64  `json:"seller_id"`
+	Status     string `json:"status"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+}
+
+type Address struct {
+	RecipientName string `json:"recipient_name"`
+	PhoneNumber   string `json:"phone_number"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+type ShipmentEventResponse struct {
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+	OccurredAt  string `json:"occurred_at"`
+}
+
+type ShipmentResponse struct {
+	ID              string                   `json:"id"`
+	OrderID         string                   `json:"order_id"`
+	CustomerID      int64                    `json:"customer_id"`
+	SellerID        int64                    `json:"seller_id"`
+	Carrier         string                   `json:"carrier"`
+	Service         string                   `json:"service"`
+	TrackingNumber  string                   `json:"tracking_number"`
+	TrackingURL     string                   `json:"tracking_url,omitempty"`
+	Status          string                   `json:"status"`
+	WeightGrams     int                      `json:"weight_grams"`
+	ShippingAddress Address                  `json:"shipping_address"`
+	DeliveredAt     string                   `json:"delivered_at,omitempty"`
+	Events          []*ShipmentEventResponse `json:"events"`
+	CreatedAt       string                   `json:"created_at"`
+	UpdatedAt       string                   `json:"updated_at"`
+}
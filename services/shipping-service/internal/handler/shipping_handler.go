@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/carrier"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/repository"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/service"
+	pb "github.com/Dzaakk/micro-commerce/services/shipping-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "shipping-service"
+
+type ShippingHandler struct {
+	shippingService service.ShippingService
+}
+
+func NewShippingHandler(shippingService service.ShippingService) *ShippingHandler {
+	return &ShippingHandler{
+		shippingService: shippingService,
+	}
+}
+
+func (h *ShippingHandler) QuoteRates(ctx context.Context, req *pb.QuoteRatesRequest, rsp *pb.QuoteRatesResponse) error {
+	lines := make([]dto.QuoteLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, dto.QuoteLine{
+			SKU:      line.Sku,
+			Quantity: int(line.Quantity),
+		})
+	}
+
+	quote, err := h.shippingService.QuoteRates(ctx, &dto.QuoteRatesRequest{
+		Country: req.Country,
+		Region:  req.Region,
+		Lines:   lines,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, parcel := range quote.Parcels {
+		protoParcel := &pb.Parcel{
+			SellerId:    parcel.SellerID,
+			Skus:        parcel.SKUs,
+			WeightGrams: int32(parcel.WeightGrams),
+		}
+		for _, rate := range parcel.Rates {
+			protoParcel.Rates = append(protoParcel.Rates, toProtoRate(rate))
+		}
+		rsp.Parcels = append(rsp.Parcels, protoParcel)
+	}
+	for _, option := range quote.Options {
+		rsp.Options = append(rsp.Options, toProtoRate(option))
+	}
+	return nil
+}
+
+func (h *ShippingHandler) CreateShipment(ctx context.Context, req *pb.CreateShipmentRequest, rsp *pb.ShipmentResponse) error {
+	shipment, err := h.shippingService.CreateShipment(ctx, &dto.CreateShipmentRequest{
+		OrderID:     req.OrderId,
+		SellerID:    req.SellerId,
+		Option:      req.Option,
+		WeightGrams: int(req.WeightGrams),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Shipment = toProtoShipment(shipment)
+	return nil
+}
+
+func (h *ShippingHandler) GetShipment(ctx context.Context, req *pb.GetShipmentRequest, rsp *pb.ShipmentResponse) error {
+	shipment, err := h.shippingService.GetShipment(ctx, req.Id, service.ShipmentScope{
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Shipment = toProtoShipment(shipment)
+	return nil
+}
+
+func (h *ShippingHandler) ListShipments(ctx context.Context, req *pb.ListShipmentsRequest, rsp *pb.ListShipmentsResponse) error {
+	shipments, total, err := h.shippingService.ListShipments(ctx, &dto.ListShipmentsRequest{
+		OrderID:    req.OrderId,
+		CustomerID: req.CustomerId,
+		SellerID:   req.SellerId,
+		Status:     req.Status,
+		Page:       int(req.Page),
+		PageSize:   int(req.PageSize),
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, shipment := range shipments {
+		rsp.Shipments = append(rsp.Shipments, toProtoShipment(shipment))
+	}
+	rsp.Total = int32(total)
+	return nil
+}
+
+func (h *ShippingHandler) HandleWebhook(ctx context.Context, req *pb.HandleWebhookRequest, rsp *pb.HandleWebhookResponse) error {
+	applied, err := h.shippingService.HandleWebhook(ctx, req.Carrier, req.Payload, req.Headers)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Applied = applied
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+	var upstreamErr *microerrors.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, carrier.ErrInvalidSignature):
+		return microerrors.Unauthorized(serviceID, "%s", err.Error())
+	case errors.Is(err, repository.ErrShipmentNotFound),
+		errors.Is(err, service.ErrUnknownCarrier):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	case errors.Is(err, service.ErrNotShippable):
+		return microerrors.Conflict(serviceID, "%s", err.Error())
+	case errors.As(err, &upstreamErr) && upstreamErr.Code == http.StatusNotFound:
+		// e.g. an order of another seller
+		return microerrors.NotFound(serviceID, "%s", upstreamErr.Detail)
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoRate(rate *dto.RateResponse) *pb.Rate {
+	return &pb.Rate{
+		Option:   rate.Option,
+		Carrier:  rate.Carrier,
+		Service:  rate.Service,
+		Name:     rate.Name,
+		Amount:   rate.Amount,
+		Currency: rate.Currency,
+		MinDays:  int32(rate.MinDays),
+		MaxDays:  int32(rate.MaxDays),
+	}
+}
+
+func toProtoShipment(shipment *dto.ShipmentResponse) *pb.Shipment {
+	resp := &pb.Shipment{
+		Id:             shipment.ID,
+		OrderId:        shipment.OrderID,
+		CustomerId:     shipment.CustomerID,
+		SellerId:       shipment.SellerID,
+		Carrier:        shipment.Carrier,
+		Service:        shipment.Service,
+		TrackingNumber: shipment.TrackingNumber,
+		TrackingUrl:    shipment.TrackingURL,
+		Status:         shipment.Status,
+		WeightGrams:    int32(shipment.WeightGrams),
+		ShippingAddress: &pb.Address{
+			RecipientName: shipment.ShippingAddress.RecipientName,
+			PhoneNumber:   shipment.ShippingAddress.PhoneNumber,
+			Line1:         shipment.ShippingAddress.Line1,
+			Line2:         shipment.ShippingAddress.Line2,
+			City:          shipment.ShippingAddress.City,
+			Region:        shipment.ShippingAddress.Region,
+			PostalCode:    shipment.ShippingAddress.PostalCode,
+			Country:       shipment.ShippingAddress.Country,
+		},
+		DeliveredAt: shipment.DeliveredAt,
+		CreatedAt:   shipment.CreatedAt,
+		UpdatedAt:   shipment.UpdatedAt,
+	}
+
+	for _, event := range shipment.Events {
+		resp.Events = append(resp.Events, &pb.ShipmentEvent{
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    event.Location,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+
+	return resp
+}
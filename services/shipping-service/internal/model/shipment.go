@@ -0,0 +1,139 @@
+package model
+
+import "time"
+
+type ShipmentStatus string
+
+const (
+	ShipmentLabelCreated   ShipmentStatus = "label_created"
+	ShipmentInTransit      ShipmentStatus = "in_transit"
+	ShipmentOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentDelivered      ShipmentStatus = "delivered"
+	// ShipmentException is a delay or failed delivery attempt the carrier
+	// expects to recover from.
+	ShipmentException ShipmentStatus = "exception"
+	// ShipmentReturned means the parcel is going back to the seller.
+	ShipmentReturned ShipmentStatus = "returned"
+)
+
+// shipmentTransitions is the tracking lifecycle. Carriers report events out
+// of order now and then; anything not listed here is recorded on the
+// timeline without changing the status.
+var shipmentTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentLabelCreated:   {ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered, ShipmentException, ShipmentReturned},
+	ShipmentInTransit:      {ShipmentOutForDelivery, ShipmentDelivered, ShipmentException, ShipmentReturned},
+	ShipmentOutForDelivery: {ShipmentInTransit, ShipmentDelivered, ShipmentException, ShipmentReturned},
+	ShipmentException:      {ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered, ShipmentReturned},
+	ShipmentDelivered:      {},
+	ShipmentReturned:       {},
+}
+
+func (s ShipmentStatus) IsValid() bool {
+	_, ok := shipmentTransitions[s]
+	return ok
+}
+
+func (s ShipmentStatus) CanTransitionTo(next ShipmentStatus) bool {
+	for _, allowed := range shipmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatus is the order status a shipment in this status implies, or ""
+// if it says nothing new about the order.
+func (s ShipmentStatus) OrderStatus() string {
+	switch s {
+	case ShipmentLabelCreated:
+		return OrderFulfilled
+	case ShipmentInTransit, ShipmentOutForDelivery:
+		return OrderShipped
+	case ShipmentDelivered:
+		return OrderDelivered
+	default:
+		return ""
+	}
+}
+
+// Order statuses the shipping service drives, in lifecycle order.
+const (
+	OrderPaid      = "paid"
+	OrderFulfilled = "fulfilled"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+)
+
+var OrderProgress = []string{OrderPaid, OrderFulfilled, OrderShipped, OrderDelivered}
+
+type Address struct {
+	RecipientName string `json:"recipient_name"`
+	PhoneNumber   string `json:"phone_number"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+type Shipment struct {
+	ID              string         `db:"id"`
+	OrderID         string         `db:"order_id"`
+	CustomerID      int64          `db:"customer_id"`
+	SellerID        int64          `db:"seller_id"`
+	Carrier         string         `db:"carrier"`
+	Service         string         `db:"service"`
+	TrackingNumber  string         `db:"tracking_number"`
+	TrackingURL     string         `db:"tracking_url"`
+	Status          ShipmentStatus `db:"status"`
+	WeightGrams     int            `db:"weight_grams"`
+	ShippingAddress Address        `db:"shipping_address"`
+	DeliveredAt     *time.Time     `db:"delivered_at"`
+	Events          []*ShipmentEvent
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// ShipmentEvent is an entry on the tracking timeline as the carrier
+// reported it.
+type ShipmentEvent struct {
+	ID          int64          `db:"id"`
+	ShipmentID  string         `db:"shipment_id"`
+	Status      ShipmentStatus `db:"status"`
+	Description string         `db:"description"`
+	Location    string         `db:"location"`
+	OccurredAt  time.Time      `db:"occurred_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// Parcel is what goes out in one box: the lines of one seller.
+type Parcel struct {
+	SellerID    int64
+	SKUs        []string
+	WeightGrams int
+}
+
+// SKUDetails is what the product service says about a SKU.
+type SKUDetails struct {
+	SKU         string
+	SellerID    int64
+	WeightGrams int
+}
+
+// OrderDetails is the part of an order a shipment is made from.
+type OrderDetails struct {
+	ID              string
+	CustomerID      int64
+	SellerID        int64
+	Status          string
+	ShippingOption  string
+	ShippingAddress Address
+	Items           []OrderItem
+}
+
+type OrderItem struct {
+	SKU      string
+	Quantity int
+}
@@ -0,0 +1,31 @@
+package model
+
+import "testing"
+
+func TestShipmentStatusCanTransitionTo(t *testing.T) {
+	statuses := []ShipmentStatus{
+		ShipmentLabelCreated,
+		ShipmentInTransit,
+		ShipmentOutForDelivery,
+		ShipmentDelivered,
+		ShipmentException,
+		ShipmentReturned,
+	}
+	moving := []ShipmentStatus{ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered, ShipmentException, ShipmentReturned}
+
+	allowed := map[[2]ShipmentStatus]bool{}
+	for _, from := range []ShipmentStatus{ShipmentLabelCreated, ShipmentInTransit, ShipmentOutForDelivery, ShipmentException} {
+		for _, to := range moving {
+			allowed[[2]ShipmentStatus{from, to}] = from != to
+		}
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]ShipmentStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+)
+
+var (
+	ErrShipmentNotFound = errors.New("shipment not found")
+	ErrShipmentExists   = errors.New("order already has a shipment")
+)
+
+// ShipmentFilter selects shipments for listings; zero fields are ignored.
+type ShipmentFilter struct {
+	OrderID    string
+	CustomerID int64
+	SellerID   int64
+	Status     model.ShipmentStatus
+	Limit      int
+	Offset     int
+}
+
+// ShipmentRepository stores shipments, their tracking timeline and the
+// carrier webhooks applied to them.
+type ShipmentRepository interface {
+	// Create inserts the shipment with a label_created event. It fails with
+	// ErrShipmentExists if the order already has a shipment.
+	Create(ctx context.Context, shipment *model.Shipment) error
+	GetByID(ctx context.Context, id string) (*model.Shipment, error)
+	GetByOrderID(ctx context.Context, orderID string) (*model.Shipment, error)
+	GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*model.Shipment, error)
+	List(ctx context.Context, filter ShipmentFilter) ([]*model.Shipment, int, error)
+
+	// RecordTrackingEvent stores a carrier webhook and adds its event to the
+	// timeline of shipment, moving the shipment to the event status when
+	// the lifecycle allows it. It reports false, and changes nothing, if
+	// the webhook was recorded before. shipment is updated in place.
+	RecordTrackingEvent(ctx context.Context, eventID string, payload []byte, shipment *model.Shipment, event *model.ShipmentEvent) (bool, error)
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const shipmentColumns = `
+	id, order_id, customer_id, seller_id, carrier, service, tracking_number, COALESCE(tracking_url, ''),
+	status, weight_grams, shipping_address, delivered_at, created_at, updated_at
+`
+
+type shipmentRepository struct {
+	db *sql.DB
+}
+
+func NewShipmentRepository(db *sql.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShipment(row rowScanner) (*model.Shipment, error) {
+	var shipment model.Shipment
+	var address []byte
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&shipment.ID,
+		&shipment.OrderID,
+		&shipment.CustomerID,
+		&shipment.SellerID,
+		&shipment.Carrier,
+		&shipment.Service,
+		&shipment.TrackingNumber,
+		&shipment.TrackingURL,
+		&shipment.Status,
+		&shipment.WeightGrams,
+		&address,
+		&deliveredAt,
+		&shipment.CreatedAt,
+		&shipment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveredAt.Valid {
+		shipment.DeliveredAt = &deliveredAt.Time
+	}
+	if err := json.Unmarshal(address, &shipment.ShippingAddress); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+func (r *shipmentRepository) Create(ctx context.Context, shipment *model.Shipment) error {
+	address, err := json.Marshal(shipment.ShippingAddress)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO shipments (
+			order_id, customer_id, seller_id, carrier, service, tracking_number, tracking_url,
+			status, weight_grams, shipping_address
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		shipment.OrderID,
+		shipment.CustomerID,
+		shipment.SellerID,
+		shipment.Carrier,
+		shipment.Service,
+		shipment.TrackingNumber,
+		shipment.TrackingURL,
+		shipment.Status,
+		shipment.WeightGrams,
+		address,
+	).Scan(&shipment.ID, &shipment.CreatedAt, &shipment.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "shipments_order_id_key" {
+			return ErrShipmentExists
+		}
+		return err
+	}
+
+	event := &model.ShipmentEvent{
+		ShipmentID:  shipment.ID,
+		Status:      shipment.Status,
+		Description: "Shipping label created",
+		OccurredAt:  shipment.CreatedAt,
+	}
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	shipment.Events = []*model.ShipmentEvent{event}
+
+	return tx.Commit()
+}
+
+func (r *shipmentRepository) GetByID(ctx context.Context, id string) (*model.Shipment, error) {
+	return r.get(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE id::text = $1`, id)
+}
+
+func (r *shipmentRepository) GetByOrderID(ctx context.Context, orderID string) (*model.Shipment, error) {
+	return r.get(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id::text = $1`, orderID)
+}
+
+func (r *shipmentRepository) GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*model.Shipment, error) {
+	return r.get(ctx,
+		`SELECT `+shipmentColumns+` FROM shipments WHERE carrier = $1 AND tracking_number = $2`,
+		carrier, trackingNumber,
+	)
+}
+
+func (r *shipmentRepository) get(ctx context.Context, query string, args ...interface{}) (*model.Shipment, error) {
+	shipment, err := scanShipment(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrShipmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadEvents(ctx, []*model.Shipment{shipment}); err != nil {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+func (r *shipmentRepository) List(ctx context.Context, filter ShipmentFilter) ([]*model.Shipment, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if filter.OrderID != "" {
+		addCondition("order_id::text", filter.OrderID)
+	}
+	if filter.CustomerID != 0 {
+		addCondition("customer_id", filter.CustomerID)
+	}
+	if filter.SellerID != 0 {
+		addCondition("seller_id", filter.SellerID)
+	}
+	if filter.Status != "" {
+		addCondition("status", filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM shipments `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM shipments %s ORDER BY created_at DESC, id ASC LIMIT $%d OFFSET $%d`,
+		shipmentColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var shipments []*model.Shipment
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadEvents(ctx, shipments); err != nil {
+		return nil, 0, err
+	}
+
+	return shipments, total, nil
+}
+
+func (r *shipmentRepository) RecordTrackingEvent(ctx context.Context, eventID string, payload []byte, shipment *model.Shipment, event *model.ShipmentEvent) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO shipping_webhook_events (carrier, event_id, payload)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (carrier, event_id) DO NOTHING
+	`, shipment.Carrier, eventID, payload)
+	if err != nil {
+		return false, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return false, err
+	}
+
+	event.ShipmentID = shipment.ID
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return false, err
+	}
+
+	// The row lock orders concurrent webhooks for the same parcel, so the
+	// transition is checked against the status they leave behind.
+	var current model.ShipmentStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM shipments WHERE id = $1 FOR UPDATE`, shipment.ID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return false, ErrShipmentNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	shipment.Status = current
+
+	if current.CanTransitionTo(event.Status) {
+		var deliveredAt sql.NullTime
+		err = tx.QueryRowContext(ctx, `
+			UPDATE shipments
+			SET status = $2,
+				delivered_at = CASE WHEN $4 THEN $3 ELSE delivered_at END,
+				updated_at = NOW()
+			WHERE id = $1
+			RETURNING delivered_at, updated_at
+		`, shipment.ID, event.Status, event.OccurredAt, event.Status == model.ShipmentDelivered).Scan(&deliveredAt, &shipment.UpdatedAt)
+		if err != nil {
+			return false, err
+		}
+
+		shipment.Status = event.Status
+		if deliveredAt.Valid {
+			shipment.DeliveredAt = &deliveredAt.Time
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	shipment.Events = append(shipment.Events, event)
+	return true, nil
+}
+
+func insertEvent(ctx context.Context, tx *sql.Tx, event *model.ShipmentEvent) error {
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO shipment_events (shipment_id, status, description, location, occurred_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+		RETURNING id, created_at
+	`,
+		event.ShipmentID,
+		event.Status,
+		event.Description,
+		event.Location,
+		event.OccurredAt,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+func (r *shipmentRepository) loadEvents(ctx context.Context, shipments []*model.Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Shipment, len(shipments))
+	ids := make([]string, 0, len(shipments))
+	for _, shipment := range shipments {
+		byID[shipment.ID] = shipment
+		ids = append(ids, shipment.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, shipment_id, status, COALESCE(description, ''), COALESCE(location, ''), occurred_at, created_at
+		FROM shipment_events
+		WHERE shipment_id::text = ANY($1)
+		ORDER BY occurred_at ASC, id ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.ShipmentEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.ShipmentID,
+			&event.Status,
+			&event.Description,
+			&event.Location,
+			&event.OccurredAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if shipment, ok := byID[event.ShipmentID]; ok {
+			shipment.Events = append(shipment.Events, &event)
+		}
+	}
+
+	return rows.Err()
+}
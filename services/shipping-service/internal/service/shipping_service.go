@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+)
+
+var (
+	ErrUnknownCarrier = errors.New("unknown carrier")
+	ErrNotShippable   = errors.New("order is not ready to ship")
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ProductCatalog resolves SKUs against the product service. SKUs that are
+// unknown or not for sale are left out of the result.
+type ProductCatalog interface {
+	LookupSKUs(ctx context.Context, skus []string) (map[string]*model.SKUDetails, error)
+}
+
+// Orders reads orders and moves them along as their parcels travel.
+type Orders interface {
+	// GetOrder returns the order if it belongs to sellerID; zero skips the
+	// check.
+	GetOrder(ctx context.Context, id string, sellerID int64) (*model.OrderDetails, error)
+	ChangeStatus(ctx context.Context, id, status, reason string) error
+}
+
+// ShipmentScope restricts which shipments a caller can see. A zero scope
+// sees everything and is meant for admins and other services.
+type ShipmentScope struct {
+	CustomerID int64
+	SellerID   int64
+}
+
+// ShippingService prices delivery, books parcels with carriers and follows
+// them to the door, keeping the order status in step.
+type ShippingService interface {
+	QuoteRates(ctx context.Context, req *dto.QuoteRatesRequest) (*dto.QuoteRatesResponse, error)
+
+	// CreateShipment books the parcel of a paid order and marks the order
+	// fulfilled. It returns the existing shipment if the order has one.
+	CreateShipment(ctx context.Context, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error)
+	GetShipment(ctx context.Context, id string, scope ShipmentScope) (*dto.ShipmentResponse, error)
+	ListShipments(ctx context.Context, req *dto.ListShipmentsRequest) ([]*dto.ShipmentResponse, int, error)
+
+	// HandleWebhook verifies and applies a carrier tracking webhook. It
+	// reports false if the event had been applied before.
+	HandleWebhook(ctx context.Context, carrier string, payload []byte, headers map[string]string) (bool, error)
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/carrier"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/shipping-service/internal/repository"
+
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxQuoteLines   = 100
+	maxLineQuantity = 100
+	// maxParcelGrams bounds weights given when booking by hand.
+	maxParcelGrams = 1000000
+	// minParcelGrams is charged for packaging, and for items the catalog
+	// has no weight for.
+	minParcelGrams = 100
+)
+
+type shippingServiceImpl struct {
+	shipmentRepo repository.ShipmentRepository
+	carriers     map[string]carrier.Carrier
+	carrierNames []string
+	catalog      ProductCatalog
+	orders       Orders
+}
+
+func NewShippingService(
+	shipmentRepo repository.ShipmentRepository,
+	carriers []carrier.Carrier,
+	catalog ProductCatalog,
+	orders Orders,
+) ShippingService {
+	s := &shippingServiceImpl{
+		shipmentRepo: shipmentRepo,
+		carriers:     make(map[string]carrier.Carrier, len(carriers)),
+		catalog:      catalog,
+		orders:       orders,
+	}
+	for _, c := range carriers {
+		s.carriers[c.Name()] = c
+		s.carrierNames = append(s.carrierNames, c.Name())
+	}
+	sort.Strings(s.carrierNames)
+
+	return s
+}
+
+// QuoteRates implements ShippingService.
+func (s *shippingServiceImpl) QuoteRates(ctx context.Context, req *dto.QuoteRatesRequest) (*dto.QuoteRatesResponse, error) {
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+	region := strings.TrimSpace(req.Region)
+
+	invalid := map[string]string{}
+	if len(country) != 2 {
+		invalid["country"] = "must be an ISO 3166-1 alpha-2 code"
+	}
+	if len(req.Lines) == 0 || len(req.Lines) > maxQuoteLines {
+		invalid["lines"] = fmt.Sprintf("must have between 1 and %d lines", maxQuoteLines)
+	}
+
+	var skus []string
+	quantities := map[string]int{}
+	for i, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		if sku == "" {
+			invalid[fmt.Sprintf("lines[%d].sku", i)] = "is required"
+			continue
+		}
+		if line.Quantity <= 0 || line.Quantity > maxLineQuantity {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = fmt.Sprintf("must be between 1 and %d", maxLineQuantity)
+			continue
+		}
+		if _, seen := quantities[sku]; !seen {
+			skus = append(skus, sku)
+		}
+		quantities[sku] += line.Quantity
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	details, err := s.catalog.LookupSKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	// One parcel per seller, in the order sellers first appear in the lines,
+	// as orders are split at checkout.
+	var parcels []*model.Parcel
+	bySeller := map[int64]*model.Parcel{}
+	for _, sku := range skus {
+		product, ok := details[sku]
+		if !ok {
+			invalid["lines.sku"] = fmt.Sprintf("%s is not for sale", sku)
+			continue
+		}
+
+		parcel := bySeller[product.SellerID]
+		if parcel == nil {
+			parcel = &model.Parcel{SellerID: product.SellerID}
+			bySeller[product.SellerID] = parcel
+			parcels = append(parcels, parcel)
+		}
+		parcel.SKUs = append(parcel.SKUs, sku)
+		parcel.WeightGrams += product.WeightGrams * quantities[sku]
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	resp := &dto.QuoteRatesResponse{
+		Parcels: make([]*dto.ParcelResponse, 0, len(parcels)),
+		Options: []*dto.RateResponse{},
+	}
+
+	// An option is offered when it can carry every parcel; its price is
+	// what all the parcels cost together and its days those of the slowest.
+	options := map[string]*dto.RateResponse{}
+	counts := map[string]int{}
+	for _, parcel := range parcels {
+		parcel.WeightGrams = max(parcel.WeightGrams, minParcelGrams)
+
+		rates, err := s.rates(ctx, country, region, parcel.WeightGrams)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rate := range rates {
+			counts[rate.Option]++
+			option, ok := options[rate.Option]
+			if !ok {
+				copied := *rate
+				options[rate.Option] = &copied
+				continue
+			}
+			option.Amount += rate.Amount
+			option.MinDays = max(option.MinDays, rate.MinDays)
+			option.MaxDays = max(option.MaxDays, rate.MaxDays)
+		}
+
+		resp.Parcels = append(resp.Parcels, &dto.ParcelResponse{
+			SellerID:    parcel.SellerID,
+			SKUs:        parcel.SKUs,
+			WeightGrams: parcel.WeightGrams,
+			Rates:       rates,
+		})
+	}
+
+	for code, option := range options {
+		if counts[code] == len(parcels) {
+			resp.Options = append(resp.Options, option)
+		}
+	}
+	sortRates(resp.Options)
+
+	return resp, nil
+}
+
+// rates asks every carrier what a parcel of weight grams costs to the
+// address, cheapest first.
+func (s *shippingServiceImpl) rates(ctx context.Context, country, region string, weight int) ([]*dto.RateResponse, error) {
+	rates := []*dto.RateResponse{}
+	for _, name := range s.carrierNames {
+		quoted, err := s.carriers[name].Rates(ctx, &carrier.RateRequest{
+			Country:     country,
+			Region:      region,
+			WeightGrams: weight,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, rate := range quoted {
+			rates = append(rates, &dto.RateResponse{
+				Option:   carrier.Option(name, rate.Service),
+				Carrier:  name,
+				Service:  rate.Service,
+				Name:     rate.Name,
+				Amount:   rate.Amount,
+				Currency: rate.Currency,
+				MinDays:  rate.MinDays,
+				MaxDays:  rate.MaxDays,
+			})
+		}
+	}
+
+	sortRates(rates)
+	return rates, nil
+}
+
+func sortRates(rates []*dto.RateResponse) {
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Amount != rates[j].Amount {
+			return rates[i].Amount < rates[j].Amount
+		}
+		return rates[i].Option < rates[j].Option
+	})
+}
+
+// CreateShipment implements ShippingService.
+func (s *shippingServiceImpl) CreateShipment(ctx context.Context, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error) {
+	orderID := strings.TrimSpace(req.OrderID)
+	option := strings.TrimSpace(req.Option)
+
+	invalid := map[string]string{}
+	if orderID == "" {
+		invalid["order_id"] = "is required"
+	}
+	if req.WeightGrams < 0 || req.WeightGrams > maxParcelGrams {
+		invalid["weight_grams"] = fmt.Sprintf("must be between 0 and %d", maxParcelGrams)
+	}
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	order, err := s.orders.GetOrder(ctx, orderID, req.SellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		// A retry may come after the order update failed the first time.
+		if err := s.syncOrder(ctx, order, existing.Status); err != nil {
+			return nil, err
+		}
+		return toShipmentResponse(existing), nil
+	}
+	if !errors.Is(err, repository.ErrShipmentNotFound) {
+		return nil, err
+	}
+
+	if order.Status != model.OrderPaid && order.Status != model.OrderFulfilled {
+		return nil, fmt.Errorf("%w: order is %s", ErrNotShippable, order.Status)
+	}
+
+	if option == "" {
+		option = order.ShippingOption
+	}
+	carrierName, serviceCode, ok := carrier.ParseOption(option)
+	if !ok {
+		return nil, &ValidationError{Fields: map[string]string{"option": "must be given as carrier:service"}}
+	}
+	c, ok := s.carriers[carrierName]
+	if !ok {
+		return nil, &ValidationError{Fields: map[string]string{"option": fmt.Sprintf("%s is not a known carrier", carrierName)}}
+	}
+
+	weight := req.WeightGrams
+	if weight == 0 {
+		if weight, err = s.orderWeight(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	label, err := c.CreateLabel(ctx, &carrier.LabelRequest{
+		Reference:   order.ID,
+		Service:     serviceCode,
+		WeightGrams: weight,
+		Address:     order.ShippingAddress,
+	})
+	if errors.Is(err, carrier.ErrUnknownService) || errors.Is(err, carrier.ErrNotServed) {
+		return nil, &ValidationError{Fields: map[string]string{"option": err.Error()}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &model.Shipment{
+		OrderID:         order.ID,
+		CustomerID:      order.CustomerID,
+		SellerID:        order.SellerID,
+		Carrier:         carrierName,
+		Service:         serviceCode,
+		TrackingNumber:  label.TrackingNumber,
+		TrackingURL:     label.TrackingURL,
+		Status:          model.ShipmentLabelCreated,
+		WeightGrams:     weight,
+		ShippingAddress: order.ShippingAddress,
+	}
+
+	err = s.shipmentRepo.Create(ctx, shipment)
+	if errors.Is(err, repository.ErrShipmentExists) {
+		// Lost a race with a retry for the same order.
+		if shipment, err = s.shipmentRepo.GetByOrderID(ctx, order.ID); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.syncOrder(ctx, order, shipment.Status); err != nil {
+		return nil, err
+	}
+
+	return toShipmentResponse(shipment), nil
+}
+
+// orderWeight is the catalog weight of the order items.
+func (s *shippingServiceImpl) orderWeight(ctx context.Context, order *model.OrderDetails) (int, error) {
+	skus := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		skus = append(skus, item.SKU)
+	}
+
+	details, err := s.catalog.LookupSKUs(ctx, skus)
+	if err != nil {
+		return 0, err
+	}
+
+	weight := 0
+	for _, item := range order.Items {
+		if product, ok := details[item.SKU]; ok {
+			weight += product.WeightGrams * item.Quantity
+		}
+	}
+
+	return max(weight, minParcelGrams), nil
+}
+
+// GetShipment implements ShippingService.
+func (s *shippingServiceImpl) GetShipment(ctx context.Context, id string, scope ShipmentScope) (*dto.ShipmentResponse, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if (scope.CustomerID != 0 && shipment.CustomerID != scope.CustomerID) ||
+		(scope.SellerID != 0 && shipment.SellerID != scope.SellerID) {
+		return nil, repository.ErrShipmentNotFound
+	}
+
+	return toShipmentResponse(shipment), nil
+}
+
+// ListShipments implements ShippingService.
+func (s *shippingServiceImpl) ListShipments(ctx context.Context, req *dto.ListShipmentsRequest) ([]*dto.ShipmentResponse, int, error) {
+	status := model.ShipmentStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return nil, 0, &ValidationError{Fields: map[string]string{"status": "is not a known shipment status"}}
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	shipments, total, err := s.shipmentRepo.List(ctx, repository.ShipmentFilter{
+		OrderID:    strings.TrimSpace(req.OrderID),
+		CustomerID: req.CustomerID,
+		SellerID:   req.SellerID,
+		Status:     status,
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*dto.ShipmentResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		resp = append(resp, toShipmentResponse(shipment))
+	}
+
+	return resp, total, nil
+}
+
+// HandleWebhook implements ShippingService.
+func (s *shippingServiceImpl) HandleWebhook(ctx context.Context, carrierName string, payload []byte, headers map[string]string) (bool, error) {
+	c, ok := s.carriers[carrierName]
+	if !ok {
+		return false, ErrUnknownCarrier
+	}
+
+	event, err := c.VerifyWebhook(payload, headers)
+	if err != nil {
+		return false, err
+	}
+
+	// A webhook can beat the response to our own label request, leaving the
+	// tracking number unknown for a moment. The error makes the carrier
+	// redeliver.
+	shipment, err := s.shipmentRepo.GetByTrackingNumber(ctx, carrierName, event.TrackingNumber)
+	if err != nil {
+		return false, err
+	}
+
+	applied, err := s.shipmentRepo.RecordTrackingEvent(ctx, event.ID, payload, shipment, &model.ShipmentEvent{
+		Status:      event.Status,
+		Description: event.Description,
+		Location:    event.Location,
+		OccurredAt:  event.OccurredAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	if applied && shipment.Status != event.Status {
+		// Stale or out of order; the timeline has it but the status stays.
+		logger.Warnf("Shipment %s is %s, not moving it to %s from %s webhook %s",
+			shipment.ID, shipment.Status, event.Status, carrierName, event.ID)
+	}
+
+	// The order is brought in step on redeliveries too, in case updating it
+	// failed the first time.
+	order, err := s.orders.GetOrder(ctx, shipment.OrderID, 0)
+	if err != nil {
+		return false, err
+	}
+	if err := s.syncOrder(ctx, order, shipment.Status); err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// syncOrder moves the order forward to the status the shipment implies,
+// one step at a time so every transition is one the order service allows.
+// Orders already past it are left alone, as are cancelled and refunded
+// ones.
+func (s *shippingServiceImpl) syncOrder(ctx context.Context, order *model.OrderDetails, status model.ShipmentStatus) error {
+	target := status.OrderStatus()
+	if target == "" {
+		return nil
+	}
+
+	from, to := -1, -1
+	for i, step := range model.OrderProgress {
+		if step == order.Status {
+			from = i
+		}
+		if step == target {
+			to = i
+		}
+	}
+	if from < 0 {
+		logger.Warnf("Order %s is %s, not marking it %s", order.ID, order.Status, target)
+		return nil
+	}
+
+	for _, next := range model.OrderProgress[from+1 : max(from+1, to+1)] {
+		reason := fmt.Sprintf("shipment %s", strings.ReplaceAll(string(status), "_", " "))
+		if err := s.orders.ChangeStatus(ctx, order.ID, next, reason); err != nil {
+			return fmt.Errorf("mark order %s %s: %w", order.ID, next, err)
+		}
+		order.Status = next
+	}
+
+	return nil
+}
+
+func toShipmentResponse(shipment *model.Shipment) *dto.ShipmentResponse {
+	resp := &dto.ShipmentResponse{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		CustomerID:     shipment.CustomerID,
+		SellerID:       shipment.SellerID,
+		Carrier:        shipment.Carrier,
+		Service:        shipment.Service,
+		TrackingNumber: shipment.TrackingNumber,
+		TrackingURL:    shipment.TrackingURL,
+		Status:         string(shipment.Status),
+		WeightGrams:    shipment.WeightGrams,
+		ShippingAddress: dto.Address{
+			RecipientName: shipment.ShippingAddress.RecipientName,
+			PhoneNumber:   shipment.ShippingAddress.PhoneNumber,
+			Line1:         shipment.ShippingAddress.Line1,
+			Line2:         shipment.ShippingAddress.Line2,
+			City:          shipment.ShippingAddress.City,
+			Region:        shipment.ShippingAddress.Region,
+			PostalCode:    shipment.ShippingAddress.PostalCode,
+			Country:       shipment.ShippingAddress.Country,
+		},
+		Events:    make([]*dto.ShipmentEventResponse, 0, len(shipment.Events)),
+		CreatedAt: shipment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: shipment.UpdatedAt.Format(time.RFC3339),
+	}
+	if shipment.DeliveredAt != nil {
+		resp.DeliveredAt = shipment.DeliveredAt.Format(time.RFC3339)
+	}
+
+	for _, event := range shipment.Events {
+		resp.Events = append(resp.Events, &dto.ShipmentEventResponse{
+			Status:      string(event.Status),
+			Description: event.Description,
+			Location:    event.Location,
+			OccurredAt:  event.OccurredAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,48 @@
+-- A shipment is the parcel of one order booked with a carrier. Orders are
+-- split per seller at checkout, so each ships as a single parcel.
+CREATE TABLE shipments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE,
+    customer_id BIGINT NOT NULL,
+    seller_id BIGINT NOT NULL,
+    carrier VARCHAR(30) NOT NULL,
+    service VARCHAR(30) NOT NULL,
+    tracking_number VARCHAR(50) NOT NULL,
+    tracking_url TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'label_created',
+    weight_grams INT NOT NULL CHECK (weight_grams > 0),
+    shipping_address JSONB NOT NULL, -- snapshot of the order address
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT shipments_status_check CHECK (status IN (
+        'label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception', 'returned'
+    ))
+);
+
+-- Tracking timeline as the carrier reported it, including events that
+-- arrived out of order and did not change the status.
+CREATE TABLE shipment_events (
+    id BIGSERIAL PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    description TEXT,
+    location VARCHAR(255),
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhook events already applied, so a redelivered event is ignored.
+CREATE TABLE shipping_webhook_events (
+    carrier VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (carrier, event_id)
+);
+
+-- Indexes
+CREATE UNIQUE INDEX idx_shipments_tracking_number ON shipments(carrier, tracking_number);
+CREATE INDEX idx_shipments_customer_id ON shipments(customer_id);
+CREATE INDEX idx_shipments_seller_id ON shipments(seller_id);
+CREATE INDEX idx_shipment_events_shipment_id ON shipment_events(shipment_id);
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/client"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/config"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/handler"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/service"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+	pb "github.com/Dzaakk/micro-commerce/services/tax-service/proto"
+
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/logger"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	rules, err := taxrules.LoadRules(cfg.TaxRulesDir)
+	if err != nil {
+		logger.Fatal("Failed to load tax rules: ", err)
+	}
+
+	srv := micro.NewService(
+		micro.Name("tax-service"),
+		micro.Version("latest"),
+		micro.Address(":"+cfg.Port),
+	)
+
+	srv.Init()
+
+	taxService := service.NewTaxService(
+		rules,
+		client.NewProductCatalog(cfg.ProductServiceName, srv.Client()),
+	)
+
+	taxHandler := handler.NewTaxHandler(taxService)
+
+	if err := pb.RegisterTaxServiceHandler(srv.Server(), taxHandler); err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.Infof("Starting %s on port %s", srv.Name(), cfg.Port)
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+
+	productpb "github.com/Dzaakk/micro-commerce/services/product-service/proto"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/service"
+
+	"go-micro.dev/v4/client"
+)
+
+type productCatalog struct {
+	products productpb.ProductService
+}
+
+// NewProductCatalog looks SKUs up through the product service registered
+// under name.
+func NewProductCatalog(name string, c client.Client) service.ProductCatalog {
+	return &productCatalog{
+		products: productpb.NewProductService(name, c),
+	}
+}
+
+func (p *productCatalog) TaxCategories(ctx context.Context, skus []string) (map[string]string, error) {
+	resp, err := p.products.LookupSKUs(ctx, &productpb.LookupSKUsRequest{Skus: skus})
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]string, len(resp.Items))
+	for _, item := range resp.Items {
+		categories[item.Sku] = item.TaxCategory
+	}
+
+	return categories, nil
+}
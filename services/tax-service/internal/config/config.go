@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port        string
+	Environment string
+
+	// Directory holding one JSON file per version of the tax rules.
+	TaxRulesDir string
+
+	// Registry name of the product service, used to find the tax category
+	// of the SKUs being taxed.
+	ProductServiceName string
+}
+
+func Load() *Config {
+	godotenv.Load()
+
+	return &Config{
+		Port:               getEnv("PORT", "8093"),
+		Environment:        os.Getenv("ENVIRONMENT"),
+		TaxRulesDir:        getEnv("TAX_RULES_DIR", "data/tax_rules"),
+		ProductServiceName: getEnv("PRODUCT_SERVICE_NAME", "product-service"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,48 @@
+package dto
+
+type TaxLine struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+	Discount  int64  `json:"discount"`
+}
+
+type CalculateTaxRequest struct {
+	Country      string    `json:"country"`
+	Region       string    `json:"region"`
+	RulesVersion string    `json:"rules_version"`
+	Lines        []TaxLine `json:"lines"`
+}
+
+type TaxComponentResponse struct {
+	Jurisdiction string `json:"jurisdiction"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	RateBPS      int    `json:"rate_bps"`
+	Amount       int64  `json:"amount"`
+}
+
+type LineTaxResponse struct {
+	SKU           string                  `json:"sku"`
+	TaxCategory   string                  `json:"tax_category"`
+	Amount        int64                   `json:"amount"`
+	TaxableAmount int64                   `json:"taxable_amount"`
+	Tax           int64                   `json:"tax"`
+	Components    []*TaxComponentResponse `json:"components"`
+}
+
+type TaxResponse struct {
+	RulesVersion     string             `json:"rules_version"`
+	Country          string             `json:"country"`
+	Region           string             `json:"region,omitempty"`
+	PricesIncludeTax bool               `json:"prices_include_tax"`
+	Lines            []*LineTaxResponse `json:"lines"`
+	TaxTotal         int64              `json:"tax_total"`
+}
+
+type RuleSetResponse struct {
+	Version       string   `json:"version"`
+	EffectiveFrom string   `json:"effective_from"`
+	Countries     []string `json:"countries"`
+	InEffect      bool     `json:"in_effect"`
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/service"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+	pb "github.com/Dzaakk/micro-commerce/services/tax-service/proto"
+
+	microerrors "go-micro.dev/v4/errors"
+)
+
+const serviceID = "tax-service"
+
+type TaxHandler struct {
+	taxService service.TaxService
+}
+
+func NewTaxHandler(taxService service.TaxService) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+	}
+}
+
+func (h *TaxHandler) CalculateTax(ctx context.Context, req *pb.CalculateTaxRequest, rsp *pb.TaxResponse) error {
+	lines := make([]dto.TaxLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, dto.TaxLine{
+			SKU:       line.Sku,
+			Quantity:  int(line.Quantity),
+			UnitPrice: line.UnitPrice,
+			Discount:  line.Discount,
+		})
+	}
+
+	calculation, err := h.taxService.CalculateTax(ctx, &dto.CalculateTaxRequest{
+		Country:      req.Country,
+		Region:       req.Region,
+		RulesVersion: req.RulesVersion,
+		Lines:        lines,
+	})
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	rsp.Calculation = toProtoCalculation(calculation)
+	return nil
+}
+
+func (h *TaxHandler) ListRuleSets(ctx context.Context, req *pb.ListRuleSetsRequest, rsp *pb.ListRuleSetsResponse) error {
+	ruleSets, err := h.taxService.ListRuleSets(ctx)
+	if err != nil {
+		return toMicroError(err)
+	}
+
+	for _, ruleSet := range ruleSets {
+		rsp.RuleSets = append(rsp.RuleSets, &pb.RuleSet{
+			Version:       ruleSet.Version,
+			EffectiveFrom: ruleSet.EffectiveFrom,
+			Countries:     ruleSet.Countries,
+			InEffect:      ruleSet.InEffect,
+		})
+	}
+	return nil
+}
+
+func toMicroError(err error) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return microerrors.BadRequest(serviceID, "%s", validationErr.Error())
+	case errors.Is(err, taxrules.ErrUnsupportedCountry):
+		return microerrors.BadRequest(serviceID, "%s", err.Error())
+	case errors.Is(err, taxrules.ErrUnknownVersion):
+		return microerrors.NotFound(serviceID, "%s", err.Error())
+	default:
+		return microerrors.InternalServerError(serviceID, "%s", err.Error())
+	}
+}
+
+func toProtoCalculation(calculation *dto.TaxResponse) *pb.TaxCalculation {
+	resp := &pb.TaxCalculation{
+		RulesVersion:     calculation.RulesVersion,
+		Country:          calculation.Country,
+		Region:           calculation.Region,
+		PricesIncludeTax: calculation.PricesIncludeTax,
+		TaxTotal:         calculation.TaxTotal,
+	}
+
+	for _, line := range calculation.Lines {
+		protoLine := &pb.LineTax{
+			Sku:           line.SKU,
+			TaxCategory:   line.TaxCategory,
+			Amount:        line.Amount,
+			TaxableAmount: line.TaxableAmount,
+			Tax:           line.Tax,
+		}
+		for _, component := range line.Components {
+			protoLine.Components = append(protoLine.Components, &pb.TaxComponent{
+				Jurisdiction: component.Jurisdiction,
+				Name:         component.Name,
+				Type:         component.Type,
+				RateBps:      int32(component.RateBPS),
+				Amount:       component.Amount,
+			})
+		}
+		resp.Lines = append(resp.Lines, protoLine)
+	}
+
+	return resp
+}
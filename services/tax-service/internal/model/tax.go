@@ -0,0 +1,41 @@
+package model
+
+// TaxLine is a line to tax. Its amount is the line total less discount.
+type TaxLine struct {
+	SKU         string
+	Quantity    int
+	UnitPrice   int64
+	Discount    int64
+	TaxCategory string
+}
+
+func (l *TaxLine) Amount() int64 {
+	return l.UnitPrice*int64(l.Quantity) - l.Discount
+}
+
+// TaxComponent is what one tax contributes to a line.
+type TaxComponent struct {
+	Jurisdiction string
+	Name         string
+	Type         string
+	RateBPS      int
+	Amount       int64
+}
+
+type LineTax struct {
+	SKU           string
+	TaxCategory   string
+	Amount        int64
+	TaxableAmount int64
+	Tax           int64
+	Components    []TaxComponent
+}
+
+type TaxCalculation struct {
+	RulesVersion     string
+	Country          string
+	Region           string
+	PricesIncludeTax bool
+	Lines            []*LineTax
+	TaxTotal         int64
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+)
+
+const basisPoints = 10000
+
+// calculate applies the levies to each line. Every component is rounded
+// half up on its own, per line, so the tax of a line never depends on the
+// other lines and an order split by seller adds up to the whole.
+//
+// When prices include tax the tax is extracted from the line amount:
+// amount * rate / (10000 + total rate). Otherwise it is added on top:
+// amount * rate / 10000.
+func calculate(levies []taxrules.Levy, pricesIncludeTax bool, lines []*model.TaxLine) ([]*model.LineTax, int64) {
+	result := make([]*model.LineTax, 0, len(lines))
+	var total int64
+
+	for _, line := range lines {
+		amount := line.Amount()
+		taxed := &model.LineTax{
+			SKU:         line.SKU,
+			TaxCategory: line.TaxCategory,
+			Amount:      amount,
+			Components:  make([]model.TaxComponent, 0, len(levies)),
+		}
+
+		divisor := int64(basisPoints)
+		if pricesIncludeTax {
+			for _, levy := range levies {
+				divisor += int64(levy.Tax.Rate(line.TaxCategory))
+			}
+		}
+
+		for _, levy := range levies {
+			rate := levy.Tax.Rate(line.TaxCategory)
+			component := model.TaxComponent{
+				Jurisdiction: levy.Jurisdiction,
+				Name:         levy.Tax.Name,
+				Type:         levy.Tax.Type,
+				RateBPS:      rate,
+				Amount:       divideRounded(amount*int64(rate), divisor),
+			}
+			taxed.Tax += component.Amount
+			taxed.Components = append(taxed.Components, component)
+		}
+
+		taxed.TaxableAmount = amount
+		if pricesIncludeTax {
+			taxed.TaxableAmount -= taxed.Tax
+		}
+
+		total += taxed.Tax
+		result = append(result, taxed)
+	}
+
+	return result, total
+}
+
+// divideRounded divides non-negative a by b, rounding half up.
+func divideRounded(a, b int64) int64 {
+	return (a + b/2) / b
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+)
+
+func TestCalculate(t *testing.T) {
+	vat := taxrules.Levy{Jurisdiction: "DE", Tax: &taxrules.Tax{Name: "VAT", Type: "vat", RatesBPS: map[string]int{"standard": 1900, "food": 700}}}
+	federal := taxrules.Levy{Jurisdiction: "XX", Tax: &taxrules.Tax{Name: "Federal", Type: "gst", RatesBPS: map[string]int{"standard": 1000}}}
+	state := taxrules.Levy{Jurisdiction: "XX-YY", Tax: &taxrules.Tax{Name: "State", Type: "sales_tax", RatesBPS: map[string]int{"standard": 725, "food": 0}}}
+
+	tests := []struct {
+		name             string
+		levies           []taxrules.Levy
+		pricesIncludeTax bool
+		line             model.TaxLine
+		wantComponents   []int64
+		wantTaxable      int64
+	}{
+		{
+			name:           "added on top, rounded half up",
+			levies:         []taxrules.Levy{state},
+			line:           model.TaxLine{Quantity: 1, UnitPrice: 1000, TaxCategory: "standard"},
+			wantComponents: []int64{73},
+			wantTaxable:    1000,
+		},
+		{
+			name:           "discount lowers the taxable amount",
+			levies:         []taxrules.Levy{state},
+			line:           model.TaxLine{Quantity: 2, UnitPrice: 1000, Discount: 500, TaxCategory: "standard"},
+			wantComponents: []int64{109},
+			wantTaxable:    1500,
+		},
+		{
+			name:             "extracted from an inclusive price",
+			levies:           []taxrules.Levy{vat},
+			pricesIncludeTax: true,
+			line:             model.TaxLine{Quantity: 1, UnitPrice: 1190, TaxCategory: "standard"},
+			wantComponents:   []int64{190},
+			wantTaxable:      1000,
+		},
+		{
+			name:             "reduced rate category",
+			levies:           []taxrules.Levy{vat},
+			pricesIncludeTax: true,
+			line:             model.TaxLine{Quantity: 1, UnitPrice: 1070, TaxCategory: "food"},
+			wantComponents:   []int64{70},
+			wantTaxable:      1000,
+		},
+		{
+			name:             "unknown category falls back to the standard rate",
+			levies:           []taxrules.Levy{vat},
+			pricesIncludeTax: true,
+			line:             model.TaxLine{Quantity: 1, UnitPrice: 1190, TaxCategory: "luxury"},
+			wantComponents:   []int64{190},
+			wantTaxable:      1000,
+		},
+		{
+			name:           "every levy is a component of its own",
+			levies:         []taxrules.Levy{federal, state},
+			line:           model.TaxLine{Quantity: 1, UnitPrice: 1000, TaxCategory: "standard"},
+			wantComponents: []int64{100, 73},
+			wantTaxable:    1000,
+		},
+		{
+			name:             "inclusive levies share one divisor",
+			levies:           []taxrules.Levy{federal, federal},
+			pricesIncludeTax: true,
+			line:             model.TaxLine{Quantity: 1, UnitPrice: 1200, TaxCategory: "standard"},
+			wantComponents:   []int64{100, 100},
+			wantTaxable:      1000,
+		},
+		{
+			name:           "no levies",
+			line:           model.TaxLine{Quantity: 1, UnitPrice: 1000, TaxCategory: "standard"},
+			wantComponents: []int64{},
+			wantTaxable:    1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.line
+			taxed, total := calculate(tt.levies, tt.pricesIncludeTax, []*model.TaxLine{&line})
+
+			var wantTax int64
+			for _, amount := range tt.wantComponents {
+				wantTax += amount
+			}
+			if total != wantTax || taxed[0].Tax != wantTax {
+				t.Errorf("tax = %d, total = %d, want %d", taxed[0].Tax, total, wantTax)
+			}
+			if taxed[0].TaxableAmount != tt.wantTaxable {
+				t.Errorf("taxable amount = %d, want %d", taxed[0].TaxableAmount, tt.wantTaxable)
+			}
+			if len(taxed[0].Components) != len(tt.wantComponents) {
+				t.Fatalf("components = %+v, want %d", taxed[0].Components, len(tt.wantComponents))
+			}
+			for i, component := range taxed[0].Components {
+				if component.Amount != tt.wantComponents[i] || component.Jurisdiction != tt.levies[i].Jurisdiction {
+					t.Errorf("component %d = %s %d, want %s %d", i, component.Jurisdiction, component.Amount, tt.levies[i].Jurisdiction, tt.wantComponents[i])
+				}
+			}
+		})
+	}
+}
+
+// An order split into several calculations must be taxed the same as the
+// whole, so each line is rounded on its own.
+func TestCalculateLinesIndependent(t *testing.T) {
+	levies := []taxrules.Levy{{Jurisdiction: "XX", Tax: &taxrules.Tax{Name: "Tax", RatesBPS: map[string]int{"standard": 725}}}}
+	lines := []*model.TaxLine{
+		{SKU: "A", Quantity: 1, UnitPrice: 1000, TaxCategory: "standard"},
+		{SKU: "B", Quantity: 3, UnitPrice: 333, TaxCategory: "standard"},
+	}
+
+	_, whole := calculate(levies, false, lines)
+
+	var split int64
+	for _, line := range lines {
+		_, total := calculate(levies, false, []*model.TaxLine{line})
+		split += total
+	}
+	if whole != split {
+		t.Errorf("whole order tax = %d, split tax = %d, want them equal", whole, split)
+	}
+}
+
+func TestDivideRounded(t *testing.T) {
+	tests := []struct{ a, b, want int64 }{
+		{a: 0, b: 10000, want: 0},
+		{a: 4999, b: 10000, want: 0},
+		{a: 5000, b: 10000, want: 1},
+		{a: 725000, b: 10000, want: 73},
+		{a: 2261000, b: 11900, want: 190},
+	}
+	for _, tt := range tests {
+		if got := divideRounded(tt.a, tt.b); got != tt.want {
+			t.Errorf("divideRounded(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/dto"
+)
+
+// ValidationError lists the invalid fields of a request.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ProductCatalog resolves the tax category of SKUs through the product
+// service. SKUs that are unknown or not for sale are left out of the result.
+type ProductCatalog interface {
+	TaxCategories(ctx context.Context, skus []string) (map[string]string, error)
+}
+
+// TaxService calculates taxes from the loaded rule tables.
+type TaxService interface {
+	// CalculateTax taxes each line with the rules of req.RulesVersion, or
+	// the rules in effect now if it is empty. The same request with the same
+	// version always gives the same result.
+	CalculateTax(ctx context.Context, req *dto.CalculateTaxRequest) (*dto.TaxResponse, error)
+	ListRuleSets(ctx context.Context) ([]*dto.RuleSetResponse, error)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/model"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+)
+
+const (
+	maxTaxLines     = 100
+	maxRegionLength = 100
+	maxSKULength    = 64
+
+	// maxLineAmount keeps amount * rate well inside int64.
+	maxLineAmount = int64(1e14)
+)
+
+type taxServiceImpl struct {
+	rules   taxrules.Rules
+	catalog ProductCatalog
+}
+
+func NewTaxService(rules taxrules.Rules, catalog ProductCatalog) TaxService {
+	return &taxServiceImpl{
+		rules:   rules,
+		catalog: catalog,
+	}
+}
+
+// CalculateTax implements TaxService.
+func (s *taxServiceImpl) CalculateTax(ctx context.Context, req *dto.CalculateTaxRequest) (*dto.TaxResponse, error) {
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+	region := strings.TrimSpace(req.Region)
+
+	invalid := map[string]string{}
+	if len(country) != 2 {
+		invalid["country"] = "must be a two-letter country code"
+	}
+	if len(region) > maxRegionLength {
+		invalid["region"] = fmt.Sprintf("must be at most %d characters", maxRegionLength)
+	}
+	validateTaxLines(invalid, req.Lines)
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	ruleSet, err := s.ruleSet(strings.TrimSpace(req.RulesVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	levies, pricesIncludeTax, err := ruleSet.Levies(country, region)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.resolveLines(ctx, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	taxed, total := calculate(levies, pricesIncludeTax, lines)
+
+	return toTaxResponse(&model.TaxCalculation{
+		RulesVersion:     ruleSet.Version,
+		Country:          country,
+		Region:           region,
+		PricesIncludeTax: pricesIncludeTax,
+		Lines:            taxed,
+		TaxTotal:         total,
+	}), nil
+}
+
+// ListRuleSets implements TaxService.
+func (s *taxServiceImpl) ListRuleSets(ctx context.Context) ([]*dto.RuleSetResponse, error) {
+	current, _ := s.rules.At(time.Now().UTC())
+
+	resp := make([]*dto.RuleSetResponse, 0, len(s.rules))
+	for _, ruleSet := range s.rules {
+		countries := make([]string, 0, len(ruleSet.Countries))
+		for code := range ruleSet.Countries {
+			countries = append(countries, code)
+		}
+		sort.Strings(countries)
+
+		resp = append(resp, &dto.RuleSetResponse{
+			Version:       ruleSet.Version,
+			EffectiveFrom: ruleSet.EffectiveFrom.Format(time.RFC3339),
+			Countries:     countries,
+			InEffect:      ruleSet == current,
+		})
+	}
+
+	return resp, nil
+}
+
+// ruleSet returns the rules of version, or the rules in effect now when no
+// version is given.
+func (s *taxServiceImpl) ruleSet(version string) (*taxrules.RuleSet, error) {
+	if version == "" {
+		return s.rules.At(time.Now().UTC())
+	}
+	return s.rules.Version(version)
+}
+
+// resolveLines looks up the tax category of each SKU. SKUs the catalog does
+// not know are taxed at the standard rate rather than rejected, so a product
+// taken off sale after checkout started can still be ordered.
+func (s *taxServiceImpl) resolveLines(ctx context.Context, reqLines []dto.TaxLine) ([]*model.TaxLine, error) {
+	skus := make([]string, 0, len(reqLines))
+	for _, line := range reqLines {
+		skus = append(skus, strings.TrimSpace(line.SKU))
+	}
+
+	categories, err := s.catalog.TaxCategories(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*model.TaxLine, 0, len(reqLines))
+	for i, line := range reqLines {
+		category, ok := categories[skus[i]]
+		if !ok || category == "" {
+			category = taxrules.DefaultCategory
+		}
+
+		lines = append(lines, &model.TaxLine{
+			SKU:         skus[i],
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Discount:    line.Discount,
+			TaxCategory: category,
+		})
+	}
+
+	return lines, nil
+}
+
+func validateTaxLines(invalid map[string]string, lines []dto.TaxLine) {
+	if len(lines) == 0 {
+		invalid["lines"] = "must have at least one line"
+	}
+	if len(lines) > maxTaxLines {
+		invalid["lines"] = fmt.Sprintf("must have at most %d lines", maxTaxLines)
+	}
+	for i, line := range lines {
+		if sku := strings.TrimSpace(line.SKU); sku == "" || len(sku) > maxSKULength {
+			invalid[fmt.Sprintf("lines[%d].sku", i)] = fmt.Sprintf("must be between 1 and %d characters", maxSKULength)
+		}
+		if line.Quantity <= 0 {
+			invalid[fmt.Sprintf("lines[%d].quantity", i)] = "must be greater than zero"
+			continue
+		}
+		if line.UnitPrice < 0 || line.UnitPrice > maxLineAmount/int64(line.Quantity) {
+			invalid[fmt.Sprintf("lines[%d].unit_price", i)] = fmt.Sprintf("must be between 0 and %d for the whole line", maxLineAmount)
+			continue
+		}
+		if line.Discount < 0 || line.Discount > line.UnitPrice*int64(line.Quantity) {
+			invalid[fmt.Sprintf("lines[%d].discount", i)] = "must be between 0 and the line total"
+		}
+	}
+}
+
+func toTaxResponse(calculation *model.TaxCalculation) *dto.TaxResponse {
+	resp := &dto.TaxResponse{
+		RulesVersion:     calculation.RulesVersion,
+		Country:          calculation.Country,
+		Region:           calculation.Region,
+		PricesIncludeTax: calculation.PricesIncludeTax,
+		Lines:            make([]*dto.LineTaxResponse, 0, len(calculation.Lines)),
+		TaxTotal:         calculation.TaxTotal,
+	}
+
+	for _, line := range calculation.Lines {
+		lineResp := &dto.LineTaxResponse{
+			SKU:           line.SKU,
+			TaxCategory:   line.TaxCategory,
+			Amount:        line.Amount,
+			TaxableAmount: line.TaxableAmount,
+			Tax:           line.Tax,
+			Components:    make([]*dto.TaxComponentResponse, 0, len(line.Components)),
+		}
+		for _, component := range line.Components {
+			lineResp.Components = append(lineResp.Components, &dto.TaxComponentResponse{
+				Jurisdiction: component.Jurisdiction,
+				Name:         component.Name,
+				Type:         component.Type,
+				RateBPS:      component.RateBPS,
+				Amount:       component.Amount,
+			})
+		}
+		resp.Lines = append(resp.Lines, lineResp)
+	}
+
+	return resp
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/dto"
+	"github.com/Dzaakk/micro-commerce/services/tax-service/internal/taxrules"
+)
+
+type fakeCatalog struct {
+	categories map[string]string
+}
+
+func (c *fakeCatalog) TaxCategories(ctx context.Context, skus []string) (map[string]string, error) {
+	return c.categories, nil
+}
+
+func newTestTaxService(t *testing.T) TaxService {
+	t.Helper()
+
+	rules, err := taxrules.LoadRules("../../data/tax_rules")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	return NewTaxService(rules, &fakeCatalog{categories: map[string]string{
+		"BOOK-1":  "books",
+		"APPLE-1": "food",
+	}})
+}
+
+func TestCalculateTaxValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(req *dto.CalculateTaxRequest)
+		wantField string
+	}{
+		{name: "country is not alpha-2", edit: func(req *dto.CalculateTaxRequest) { req.Country = "USA" }, wantField: "country"},
+		{name: "region too long", edit: func(req *dto.CalculateTaxRequest) { req.Region = strings.Repeat("r", maxRegionLength+1) }, wantField: "region"},
+		{name: "no lines", edit: func(req *dto.CalculateTaxRequest) { req.Lines = nil }, wantField: "lines"},
+		{name: "blank sku", edit: func(req *dto.CalculateTaxRequest) { req.Lines[0].SKU = " " }, wantField: "lines[0].sku"},
+		{name: "sku too long", edit: func(req *dto.CalculateTaxRequest) { req.Lines[0].SKU = strings.Repeat("s", maxSKULength+1) }, wantField: "lines[0].sku"},
+		{name: "zero quantity", edit: func(req *dto.CalculateTaxRequest) { req.Lines[0].Quantity = 0 }, wantField: "lines[0].quantity"},
+		{name: "negative price", edit: func(req *dto.CalculateTaxRequest) { req.Lines[0].UnitPrice = -1 }, wantField: "lines[0].unit_price"},
+		{name: "line total overflows", edit: func(req *dto.CalculateTaxRequest) {
+			req.Lines[0].Quantity, req.Lines[0].UnitPrice = 2, maxLineAmount
+		}, wantField: "lines[0].unit_price"},
+		{name: "discount above the line total", edit: func(req *dto.CalculateTaxRequest) { req.Lines[0].Discount = 1001 }, wantField: "lines[0].discount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &dto.CalculateTaxRequest{
+				Country: "US",
+				Region:  "CA",
+				Lines:   []dto.TaxLine{{SKU: "SKU-1", Quantity: 1, UnitPrice: 1000}},
+			}
+			tt.edit(req)
+
+			_, err := newTestTaxService(t).CalculateTax(context.Background(), req)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Fields[tt.wantField] == "" {
+				t.Errorf("CalculateTax() error = %v, want a %s error", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCalculateTax(t *testing.T) {
+	tests := []struct {
+		name              string
+		country           string
+		region            string
+		sku               string
+		unitPrice         int64
+		wantTax           int64
+		wantCategory      string
+		wantInclusive     bool
+		wantJurisdictions []string
+	}{
+		{name: "US state sales tax", country: "us", region: "CA", sku: "SKU-1", unitPrice: 1000, wantTax: 73, wantCategory: "standard", wantJurisdictions: []string{"US-CA"}},
+		{name: "US state without its own rules", country: "US", region: "OR", sku: "SKU-1", unitPrice: 1000, wantTax: 0, wantCategory: "standard", wantJurisdictions: []string{}},
+		{name: "food exempt in the state", country: "US", region: "California", sku: "APPLE-1", unitPrice: 1000, wantTax: 0, wantCategory: "food", wantJurisdictions: []string{"US-CA"}},
+		{name: "national and provincial tax", country: "CA", region: "QC", sku: "SKU-1", unitPrice: 1000, wantTax: 150, wantCategory: "standard", wantJurisdictions: []string{"CA", "CA-QC"}},
+		{name: "harmonized tax replaces the national one", country: "CA", region: "on", sku: "SKU-1", unitPrice: 1000, wantTax: 130, wantCategory: "standard", wantJurisdictions: []string{"CA-ON"}},
+		{name: "VAT included in the price", country: "DE", sku: "SKU-1", unitPrice: 1190, wantTax: 190, wantCategory: "standard", wantInclusive: true, wantJurisdictions: []string{"DE"}},
+		{name: "reduced VAT for books", country: "DE", sku: "BOOK-1", unitPrice: 1070, wantTax: 70, wantCategory: "books", wantInclusive: true, wantJurisdictions: []string{"DE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newTestTaxService(t).CalculateTax(context.Background(), &dto.CalculateTaxRequest{
+				Country: tt.country,
+				Region:  tt.region,
+				Lines:   []dto.TaxLine{{SKU: tt.sku, Quantity: 1, UnitPrice: tt.unitPrice}},
+			})
+			if err != nil {
+				t.Fatalf("CalculateTax() error = %v", err)
+			}
+
+			if resp.TaxTotal != tt.wantTax || resp.PricesIncludeTax != tt.wantInclusive {
+				t.Errorf("CalculateTax() = %d, inclusive %v, want %d, inclusive %v", resp.TaxTotal, resp.PricesIncludeTax, tt.wantTax, tt.wantInclusive)
+			}
+			if resp.RulesVersion != "2026-01" {
+				t.Errorf("rules version = %s, want 2026-01", resp.RulesVersion)
+			}
+
+			line := resp.Lines[0]
+			if line.TaxCategory != tt.wantCategory {
+				t.Errorf("tax category = %s, want %s", line.TaxCategory, tt.wantCategory)
+			}
+			jurisdictions := make([]string, 0, len(line.Components))
+			for _, component := range line.Components {
+				jurisdictions = append(jurisdictions, component.Jurisdiction)
+			}
+			if strings.Join(jurisdictions, ",") != strings.Join(tt.wantJurisdictions, ",") {
+				t.Errorf("jurisdictions = %v, want %v", jurisdictions, tt.wantJurisdictions)
+			}
+		})
+	}
+}
+
+func TestCalculateTaxRules(t *testing.T) {
+	svc := newTestTaxService(t)
+	ctx := context.Background()
+	lines := []dto.TaxLine{{SKU: "SKU-1", Quantity: 1, UnitPrice: 1000}}
+
+	pinned, err := svc.CalculateTax(ctx, &dto.CalculateTaxRequest{Country: "DE", RulesVersion: "2026-01", Lines: lines})
+	if err != nil || pinned.RulesVersion != "2026-01" {
+		t.Errorf("CalculateTax() with a version = %+v, %v, want version 2026-01", pinned, err)
+	}
+
+	if _, err := svc.CalculateTax(ctx, &dto.CalculateTaxRequest{Country: "DE", RulesVersion: "1999-01", Lines: lines}); !errors.Is(err, taxrules.ErrUnknownVersion) {
+		t.Errorf("CalculateTax() with an unknown version error = %v, want %v", err, taxrules.ErrUnknownVersion)
+	}
+	if _, err := svc.CalculateTax(ctx, &dto.CalculateTaxRequest{Country: "ZZ", Lines: lines}); !errors.Is(err, taxrules.ErrUnsupportedCountry) {
+		t.Errorf("CalculateTax() in an unsupported country error = %v, want %v", err, taxrules.ErrUnsupportedCountry)
+	}
+
+	ruleSets, err := svc.ListRuleSets(ctx)
+	if err != nil {
+		t.Fatalf("ListRuleSets() error = %v", err)
+	}
+	if len(ruleSets) != 1 || !ruleSets[0].InEffect || len(ruleSets[0].Countries) == 0 {
+		t.Errorf("ListRuleSets() = %+v, want the one rule set, in effect", ruleSets)
+	}
+}
@@ -0,0 +1,245 @@
+package taxrules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCategory is the rate used for tax categories a tax has no
+// specific rate for.
+const DefaultCategory = "standard"
+
+// maxRateBPS caps a single rate at 100%.
+const maxRateBPS = 10000
+
+var (
+	ErrUnsupportedCountry = errors.New("no tax rules for country")
+	ErrUnknownVersion     = errors.New("unknown tax rules version")
+	ErrNoRulesInEffect    = errors.New("no tax rules in effect")
+)
+
+var categoryPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,29}$`)
+
+// Tax is a single levy such as a VAT, GST or state sales tax. Rates are in
+// basis points of the taxable amount, keyed by tax category.
+type Tax struct {
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	RatesBPS map[string]int `json:"rates_bps"`
+}
+
+// Rate returns the rate for category, falling back to the standard rate.
+func (t *Tax) Rate(category string) int {
+	if rate, ok := t.RatesBPS[category]; ok {
+		return rate
+	}
+	return t.RatesBPS[DefaultCategory]
+}
+
+// Region holds the taxes a state or province levies on top of the national
+// ones, or instead of them when ReplacesNational is set (e.g. a harmonized
+// sales tax).
+type Region struct {
+	Name             string `json:"name"`
+	ReplacesNational bool   `json:"replaces_national"`
+	Taxes            []*Tax `json:"taxes"`
+}
+
+// Country holds the national taxes and the regions with taxes of their own.
+// PricesIncludeTax says whether shelf prices already contain the tax, as is
+// usual for VAT and GST, or whether it is added on top, as for US sales tax.
+type Country struct {
+	Name             string             `json:"name"`
+	PricesIncludeTax bool               `json:"prices_include_tax"`
+	Taxes            []*Tax             `json:"taxes"`
+	Regions          map[string]*Region `json:"regions"`
+}
+
+// RuleSet is one version of the rule table, in effect from EffectiveFrom
+// until the next version takes over. Countries are keyed by ISO 3166-1
+// alpha-2 code and regions by their subdivision code, e.g. "CA" or "ON".
+type RuleSet struct {
+	Version       string              `json:"version"`
+	EffectiveFrom time.Time           `json:"effective_from"`
+	Countries     map[string]*Country `json:"countries"`
+}
+
+// Levy is a tax that applies to an address, labelled with the jurisdiction
+// levying it: the country code, or country and region as in "US-CA".
+type Levy struct {
+	Jurisdiction string
+	Tax          *Tax
+}
+
+// Levies returns the taxes that apply to an address in country and region,
+// and whether prices there include tax. region may be given by code or by
+// name, as addresses hold whatever the customer typed. A region without
+// rules of its own only pays the national taxes.
+func (rs *RuleSet) Levies(country, region string) ([]Levy, bool, error) {
+	rules, ok := rs.Countries[country]
+	if !ok {
+		return nil, false, fmt.Errorf("%w %q", ErrUnsupportedCountry, country)
+	}
+
+	var levies []Levy
+	code, regional := rules.region(region)
+
+	if regional == nil || !regional.ReplacesNational {
+		for _, tax := range rules.Taxes {
+			levies = append(levies, Levy{Jurisdiction: country, Tax: tax})
+		}
+	}
+	if regional != nil {
+		for _, tax := range regional.Taxes {
+			levies = append(levies, Levy{Jurisdiction: country + "-" + code, Tax: tax})
+		}
+	}
+
+	return levies, rules.PricesIncludeTax, nil
+}
+
+func (c *Country) region(region string) (string, *Region) {
+	region = strings.TrimSpace(region)
+	if region == "" {
+		return "", nil
+	}
+
+	code := strings.ToUpper(region)
+	if rules, ok := c.Regions[code]; ok {
+		return code, rules
+	}
+	for code, rules := range c.Regions {
+		if strings.EqualFold(rules.Name, region) {
+			return code, rules
+		}
+	}
+	return "", nil
+}
+
+// Rules holds every loaded rule set, oldest first.
+type Rules []*RuleSet
+
+// LoadRules reads every .json file in dir as a rule set.
+func LoadRules(dir string) (Rules, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list tax rules: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no tax rules found in %s", dir)
+	}
+
+	var rules Rules
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read tax rules: %w", err)
+		}
+
+		ruleSet, err := ParseRuleSet(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		rules = append(rules, ruleSet)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].EffectiveFrom.Before(rules[j].EffectiveFrom)
+	})
+
+	versions := map[string]bool{}
+	for i, ruleSet := range rules {
+		if versions[ruleSet.Version] {
+			return nil, fmt.Errorf("tax rules version %s is defined twice", ruleSet.Version)
+		}
+		versions[ruleSet.Version] = true
+
+		if i > 0 && ruleSet.EffectiveFrom.Equal(rules[i-1].EffectiveFrom) {
+			return nil, fmt.Errorf("tax rules %s and %s take effect at the same time", rules[i-1].Version, ruleSet.Version)
+		}
+	}
+
+	return rules, nil
+}
+
+func ParseRuleSet(data []byte) (*RuleSet, error) {
+	var ruleSet RuleSet
+	if err := json.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("parse tax rules: %w", err)
+	}
+
+	if strings.TrimSpace(ruleSet.Version) == "" {
+		return nil, fmt.Errorf("tax rules: version is required")
+	}
+	if ruleSet.EffectiveFrom.IsZero() {
+		return nil, fmt.Errorf("tax rules %s: effective_from is required", ruleSet.Version)
+	}
+
+	for code, country := range ruleSet.Countries {
+		if len(code) != 2 || strings.ToUpper(code) != code {
+			return nil, fmt.Errorf("tax rules %s: invalid country code %q", ruleSet.Version, code)
+		}
+		if err := validateTaxes(country.Taxes); err != nil {
+			return nil, fmt.Errorf("tax rules %s: %s: %w", ruleSet.Version, code, err)
+		}
+
+		for regionCode, region := range country.Regions {
+			if regionCode == "" || strings.ToUpper(regionCode) != regionCode {
+				return nil, fmt.Errorf("tax rules %s: %s: invalid region code %q", ruleSet.Version, code, regionCode)
+			}
+			if err := validateTaxes(region.Taxes); err != nil {
+				return nil, fmt.Errorf("tax rules %s: %s-%s: %w", ruleSet.Version, code, regionCode, err)
+			}
+		}
+	}
+
+	return &ruleSet, nil
+}
+
+func validateTaxes(taxes []*Tax) error {
+	for _, tax := range taxes {
+		if tax.Name == "" {
+			return fmt.Errorf("tax name is required")
+		}
+		if _, ok := tax.RatesBPS[DefaultCategory]; !ok {
+			return fmt.Errorf("%s has no %s rate", tax.Name, DefaultCategory)
+		}
+		for category, rate := range tax.RatesBPS {
+			if !categoryPattern.MatchString(category) {
+				return fmt.Errorf("%s: invalid tax category %q", tax.Name, category)
+			}
+			if rate < 0 || rate > maxRateBPS {
+				return fmt.Errorf("%s: rate for %s must be between 0 and %d basis points", tax.Name, category, maxRateBPS)
+			}
+		}
+	}
+	return nil
+}
+
+// At returns the rule set in effect at t.
+func (r Rules) At(t time.Time) (*RuleSet, error) {
+	for i := len(r) - 1; i >= 0; i-- {
+		if !t.Before(r[i].EffectiveFrom) {
+			return r[i], nil
+		}
+	}
+	return nil, ErrNoRulesInEffect
+}
+
+// Version returns the rule set with the given version, so a calculation can
+// be repeated with the rules it was first made with.
+func (r Rules) Version(version string) (*RuleSet, error) {
+	for _, ruleSet := range r {
+		if ruleSet.Version == version {
+			return ruleSet, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownVersion, version)
+}
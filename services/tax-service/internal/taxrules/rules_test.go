@@ -0,0 +1,147 @@
+package taxrules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadRulesDataFiles(t *testing.T) {
+	rules, err := LoadRules("../../data/tax_rules")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if len(rules) == 0 {
+		t.Fatal("LoadRules() loaded no rule sets")
+	}
+}
+
+func TestLevies(t *testing.T) {
+	rules, err := LoadRules("../../data/tax_rules")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	ruleSet := rules[len(rules)-1]
+
+	tests := []struct {
+		country       string
+		region        string
+		wantLevies    []string
+		wantInclusive bool
+		wantErr       error
+	}{
+		{country: "DE", wantLevies: []string{"DE:USt"}, wantInclusive: true},
+		{country: "CA", wantLevies: []string{"CA:GST"}},
+		{country: "CA", region: "BC", wantLevies: []string{"CA:GST", "CA-BC:PST"}},
+		{country: "CA", region: " british columbia ", wantLevies: []string{"CA:GST", "CA-BC:PST"}},
+		{country: "CA", region: "ON", wantLevies: []string{"CA-ON:HST"}},
+		{country: "CA", region: "Yukon", wantLevies: []string{"CA:GST"}},
+		{country: "US", region: "NY", wantLevies: []string{"US-NY:Sales tax"}},
+		{country: "US", wantLevies: []string{}},
+		{country: "ZZ", wantErr: ErrUnsupportedCountry},
+		{country: "de", wantErr: ErrUnsupportedCountry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+"/"+tt.region, func(t *testing.T) {
+			levies, inclusive, err := ruleSet.Levies(tt.country, tt.region)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Levies() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := make([]string, 0, len(levies))
+			for _, levy := range levies {
+				got = append(got, levy.Jurisdiction+":"+levy.Tax.Name)
+			}
+			if len(got) != len(tt.wantLevies) {
+				t.Fatalf("Levies() = %v, want %v", got, tt.wantLevies)
+			}
+			for i := range got {
+				if got[i] != tt.wantLevies[i] {
+					t.Errorf("Levies() = %v, want %v", got, tt.wantLevies)
+				}
+			}
+			if inclusive != tt.wantInclusive {
+				t.Errorf("prices include tax = %v, want %v", inclusive, tt.wantInclusive)
+			}
+		})
+	}
+}
+
+func TestParseRuleSetRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "malformed json", data: `{"version": `},
+		{name: "no version", data: `{"effective_from": "2026-01-01T00:00:00Z"}`},
+		{name: "no effective date", data: `{"version": "v1"}`},
+		{name: "lower-case country", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"de": {}}}`},
+		{name: "lower-case region", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"US": {"regions": {"ca": {}}}}}`},
+		{name: "no standard rate", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"DE": {"taxes": [{"name": "VAT", "rates_bps": {"food": 700}}]}}}`},
+		{name: "rate above 100%", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"DE": {"taxes": [{"name": "VAT", "rates_bps": {"standard": 10001}}]}}}`},
+		{name: "negative rate", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"DE": {"taxes": [{"name": "VAT", "rates_bps": {"standard": -1}}]}}}`},
+		{name: "invalid category", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"DE": {"taxes": [{"name": "VAT", "rates_bps": {"standard": 1900, "Reduced Rate": 700}}]}}}`},
+		{name: "unnamed tax", data: `{"version": "v1", "effective_from": "2026-01-01T00:00:00Z", "countries": {"DE": {"taxes": [{"rates_bps": {"standard": 1900}}]}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRuleSet([]byte(tt.data)); err == nil {
+				t.Error("ParseRuleSet() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestRuleSetVersions(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"b.json": `{"version": "2026-07", "effective_from": "2026-07-01T00:00:00Z", "countries": {}}`,
+		"a.json": `{"version": "2026-01", "effective_from": "2026-01-01T00:00:00Z", "countries": {}}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules, err := LoadRules(dir)
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	tests := []struct {
+		at      time.Time
+		want    string
+		wantErr error
+	}{
+		{at: time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), wantErr: ErrNoRulesInEffect},
+		{at: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), want: "2026-01"},
+		{at: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), want: "2026-01"},
+		{at: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), want: "2026-07"},
+	}
+	for _, tt := range tests {
+		ruleSet, err := rules.At(tt.at)
+		if !errors.Is(err, tt.wantErr) || (err == nil && ruleSet.Version != tt.want) {
+			t.Errorf("At(%s) = %v, %v, want %s, %v", tt.at, ruleSet, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if ruleSet, err := rules.Version("2026-01"); err != nil || ruleSet.Version != "2026-01" {
+		t.Errorf("Version(2026-01) = %v, %v", ruleSet, err)
+	}
+	if _, err := rules.Version("2027-01"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Version(2027-01) error = %v, want %v", err, ErrUnknownVersion)
+	}
+
+	duplicate := filepath.Join(dir, "c.json")
+	os.WriteFile(duplicate, []byte(`{"version": "2026-01", "effective_from": "2027-01-01T00:00:00Z", "countries": {}}`), 0o644)
+	if _, err := LoadRules(dir); err == nil {
+		t.Error("LoadRules() with a version defined twice error = nil, want an error")
+	}
+}